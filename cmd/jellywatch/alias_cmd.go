@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/spf13/cobra"
+)
+
+func newAliasCmd() *cobra.Command {
+	return newAliasCmdWithDeps(database.Open, os.Stdout)
+}
+
+func newAliasCmdWithDeps(openDB func() (*database.MediaDB, error), stdout io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "alias",
+		Aliases: []string{"aliases"},
+		Short:   "Manage manual title overrides",
+		Long: `Aliases map a release title, a regular expression over the path or a
+folder name to the canonical title, year and provider IDs. The daemon, the
+library scanner, library selection and housekeeping apply them in place of
+what the filename parser finds.`,
+	}
+
+	var (
+		kind, mediaType, title, imdb string
+		year, tmdb, tvdb             int
+	)
+	add := &cobra.Command{
+		Use:   "add <pattern>",
+		Short: "Add or replace an alias",
+		Example: `  jellywatch alias add "The Office" --type series --title "The Office (US)" --year 2005 --tvdb 73244
+  jellywatch alias add "(?i)office\.uk\." --kind regex --type series --title "The Office" --year 2001
+  jellywatch alias add "Kids Stuff" --kind folder --type movie --title "Frozen" --year 2013`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openDB()
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			defer db.Close()
+
+			alias := &database.Alias{
+				Kind:      kind,
+				Pattern:   args[0],
+				MediaType: mediaType,
+				Title:     title,
+			}
+			if year > 0 {
+				alias.Year = &year
+			}
+			if tmdb > 0 {
+				alias.TmdbID = &tmdb
+			}
+			if tvdb > 0 {
+				alias.TvdbID = &tvdb
+			}
+			if imdb = strings.TrimSpace(imdb); imdb != "" {
+				alias.ImdbID = &imdb
+			}
+			if err := db.UpsertAlias(alias); err != nil {
+				return err
+			}
+			printAlias(stdout, *alias)
+			return nil
+		},
+	}
+	add.Flags().StringVar(&kind, "kind", database.AliasKindTitle, "what the pattern matches: title, regex or folder")
+	add.Flags().StringVar(&mediaType, "type", "", "media type: movie or series")
+	add.Flags().StringVar(&title, "title", "", "canonical title")
+	add.Flags().IntVar(&year, "year", 0, "canonical year")
+	add.Flags().IntVar(&tmdb, "tmdb", 0, "TMDB ID")
+	add.Flags().IntVar(&tvdb, "tvdb", 0, "TVDB ID")
+	add.Flags().StringVar(&imdb, "imdb", "", "IMDb ID (tt...)")
+	_ = add.MarkFlagRequired("type")
+	_ = add.MarkFlagRequired("title")
+
+	var jsonOutput bool
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "Show every alias",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openDB()
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			defer db.Close()
+
+			aliases, err := db.ListAliases()
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				enc := json.NewEncoder(stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(aliases)
+			}
+			if len(aliases) == 0 {
+				fmt.Fprintln(stdout, "no aliases")
+				return nil
+			}
+			for _, a := range aliases {
+				printAlias(stdout, a)
+			}
+			return nil
+		},
+	}
+	list.Flags().BoolVar(&jsonOutput, "json", false, "print JSON")
+
+	remove := &cobra.Command{
+		Use:   "remove <id>",
+		Short: "Delete an alias",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid alias id %q", args[0])
+			}
+			db, err := openDB()
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			defer db.Close()
+
+			ok, err := db.DeleteAlias(id)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("no alias %d", id)
+			}
+			fmt.Fprintf(stdout, "removed alias %d\n", id)
+			return nil
+		},
+	}
+
+	cmd.AddCommand(add, list, remove)
+	return cmd
+}
+
+func printAlias(w io.Writer, a database.Alias) {
+	target := a.Title
+	if a.Year != nil {
+		target = fmt.Sprintf("%s (%d)", a.Title, *a.Year)
+	}
+	var ids []string
+	if a.TmdbID != nil {
+		ids = append(ids, fmt.Sprintf("tmdb=%d", *a.TmdbID))
+	}
+	if a.TvdbID != nil {
+		ids = append(ids, fmt.Sprintf("tvdb=%d", *a.TvdbID))
+	}
+	if a.ImdbID != nil {
+		ids = append(ids, "imdb="+*a.ImdbID)
+	}
+	line := fmt.Sprintf("%-4d %-6s %-6s %q -> %s", a.ID, a.MediaType, a.Kind, a.Pattern, target)
+	if len(ids) > 0 {
+		line += " [" + strings.Join(ids, " ") + "]"
+	}
+	fmt.Fprintln(w, line)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Nomadcxx/jellywatch/internal/database"
+)
+
+func TestAliasCmdAddListRemove(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	openDB := func() (*database.MediaDB, error) { return database.OpenPath(dbPath) }
+
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		cmd := newAliasCmdWithDeps(openDB, &out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("add", "The Office", "--type", "series", "--title", "The Office (US)", "--year", "2005", "--tvdb", "73244")
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if !strings.Contains(out, `"The Office" -> The Office (US) (2005) [tvdb=73244]`) {
+		t.Fatalf("add output = %q", out)
+	}
+	if _, err := run("add", "(", "--kind", "regex", "--type", "series", "--title", "X"); err == nil {
+		t.Fatal("add accepted a bad regex")
+	}
+
+	out, err = run("list")
+	if err != nil || strings.Count(out, "\n") != 1 {
+		t.Fatalf("list = %q, %v", out, err)
+	}
+
+	if _, err := run("remove", "1"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if _, err := run("remove", "1"); err == nil {
+		t.Fatal("removing a missing alias should fail")
+	}
+	out, _ = run("list")
+	if !strings.Contains(out, "no aliases") {
+		t.Fatalf("list after remove = %q", out)
+	}
+}
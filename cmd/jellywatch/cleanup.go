@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/Nomadcxx/jellywatch/internal/trash"
 	"github.com/Nomadcxx/jellywatch/internal/video"
 )
 
@@ -39,7 +40,7 @@ func isInsideLibrary(path string, libraryRoots []string) bool {
 // cleanupSourceDir removes cruft files and empty directories after a move.
 // It only deletes cruft if NO video files remain in the directory.
 // It walks up to directory tree, deleting empty dirs until hitting a library root.
-func cleanupSourceDir(dir string, libraryRoots []string) error {
+func cleanupSourceDir(dir string, libraryRoots []string, bin *trash.Bin) error {
 	currentDir := filepath.Clean(dir)
 
 	for {
@@ -78,10 +79,10 @@ func cleanupSourceDir(dir string, libraryRoots []string) error {
 			entryPath := filepath.Join(currentDir, entry.Name())
 			if entry.IsDir() {
 				// Recursively clean subdirectories first
-				cleanupSourceDir(entryPath, libraryRoots)
+				cleanupSourceDir(entryPath, libraryRoots, bin)
 			}
 			// Remove the entry (file or now-empty dir)
-			os.RemoveAll(entryPath)
+			bin.RemoveAll(entryPath, "cruft left after consolidation")
 		}
 
 		// Now directory should be empty, remove it
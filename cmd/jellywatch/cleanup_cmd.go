@@ -9,7 +9,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/Nomadcxx/jellywatch/internal/config"
+	"github.com/Nomadcxx/jellywatch/internal/database"
 	"github.com/Nomadcxx/jellywatch/internal/privilege"
+	"github.com/Nomadcxx/jellywatch/internal/trash"
 )
 
 func newCleanupCmd() *cobra.Command {
@@ -86,6 +88,16 @@ func runCleanupCruft(dryRun bool) error {
 		return fmt.Errorf("no libraries configured")
 	}
 
+	var bin *trash.Bin
+	if !dryRun && cfg.Trash.Enabled {
+		db, err := database.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open database: %w", err)
+		}
+		defer db.Close()
+		bin = trash.New(db, cfg.Trash)
+	}
+
 	if dryRun {
 		fmt.Println("Scanning for cruft files (dry run)...")
 	} else {
@@ -97,7 +109,13 @@ func runCleanupCruft(dryRun bool) error {
 
 	for _, root := range allRoots {
 		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-			if err != nil || info.IsDir() {
+			if err != nil {
+				return nil
+			}
+			if info.IsDir() {
+				if trash.IsTrashDir(path) {
+					return filepath.SkipDir
+				}
 				return nil
 			}
 
@@ -105,7 +123,7 @@ func runCleanupCruft(dryRun bool) error {
 				if dryRun {
 					fmt.Printf("  [DELETE] %s (%s)\n", path, formatBytes(info.Size()))
 				} else {
-					if err := os.Remove(path); err != nil {
+					if _, err := bin.Remove(path, "cruft"); err != nil {
 						fmt.Printf("  Failed: %s: %v\n", path, err)
 						return nil
 					}
@@ -3,10 +3,12 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/Nomadcxx/jellywatch/internal/config"
+	"github.com/Nomadcxx/jellywatch/internal/naming"
 	"github.com/Nomadcxx/jellywatch/internal/radarr"
 	"github.com/Nomadcxx/jellywatch/internal/sonarr"
 	"github.com/spf13/cobra"
@@ -24,12 +26,14 @@ Examples:
   jellywatch config init              # Create default config file
   jellywatch config show              # Display current configuration
   jellywatch config test              # Test all connections
+  jellywatch config test-template     # Preview naming templates
   jellywatch config path              # Show config file path`,
 	}
 
 	cmd.AddCommand(newConfigInitCmd())
 	cmd.AddCommand(newConfigShowCmd())
 	cmd.AddCommand(newConfigTestCmd())
+	cmd.AddCommand(newConfigTestTemplateCmd())
 	cmd.AddCommand(newConfigPathCmd())
 
 	return cmd
@@ -118,6 +122,7 @@ func newConfigShowCmd() *cobra.Command {
 			fmt.Printf("Dry Run:         %v\n", cfg.Options.DryRun)
 			fmt.Printf("Delete Source:   %v\n", cfg.Options.DeleteSource)
 			fmt.Printf("Verify Checksum: %v\n", cfg.Options.VerifyChecksums)
+			fmt.Printf("Extras:          %s\n", cfg.Options.Extras)
 
 			return nil
 		},
@@ -269,6 +274,164 @@ Tests:
 	}
 }
 
+func newConfigTestTemplateCmd() *cobra.Command {
+	var (
+		library      string
+		overrides    naming.Templates
+		values       naming.TemplateValues
+		showMovie    bool
+		showEpisode  bool
+		episodeTitle string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "test-template <release-name>",
+		Short: "Preview where a release lands under the naming templates",
+		Long: `Parse a release name and print the library path the naming templates
+produce for it, without touching any files.
+
+Templates come from the [[libraries.settings]] entry of --library; the
+template flags override them, so new templates can be tried before they
+are saved. Provider IDs and episode titles normally come from the database
+and Sonarr; pass them as flags to see them rendered.
+
+Tokens: {Title} {Year} {Season} {Episode} {EpisodeTitle} {ImdbID} {TmdbID}
+{TvdbID} {Resolution} {Source} {Edition} {ReleaseGroup}
+
+Examples:
+  jellywatch config test-template "The.Matrix.1999.1080p.BluRay.x264-SPARKS.mkv" \
+    --movie-folder "{Title} ({Year}) [imdbid-{ImdbID}]" --imdb tt0133093
+  jellywatch config test-template "Severance.S01E01.1080p.WEB-DL.mkv" --library "/mnt/TV"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			release := args[0]
+
+			var tmpl naming.Templates
+			if library != "" {
+				if !config.ConfigExists() {
+					return fmt.Errorf("no config file found (run 'jellywatch config init' first)")
+				}
+				cfg, err := config.Load()
+				if err != nil {
+					return fmt.Errorf("failed to load config: %w", err)
+				}
+				tmpl = cfg.Libraries.TemplatesFor(library)
+			}
+			tmpl = mergeTemplates(tmpl, overrides)
+			if err := tmpl.Validate(); err != nil {
+				return err
+			}
+
+			values.EpisodeTitle = episodeTitle
+			values.FillRelease(release)
+			ext := filepath.Ext(release)
+			if ext == "" {
+				ext = ".mkv"
+			}
+
+			isEpisode := naming.IsTVEpisodeFilename(release)
+			if showMovie {
+				isEpisode = false
+			} else if showEpisode {
+				isEpisode = true
+			}
+
+			var target string
+			if isEpisode {
+				tv, err := naming.ParseTVShowName(release)
+				if err != nil {
+					return fmt.Errorf("cannot parse %q as an episode: %w", release, err)
+				}
+				target = filepath.Join(
+					tmpl.SeriesFolderName(tv, values),
+					tmpl.SeasonFolderName(tv.Season),
+					tmpl.EpisodeFileName(tv, values, ext))
+				fmt.Printf("Type:        episode\n")
+				fmt.Printf("Parsed:      %s %s\n", naming.NormalizeMediaName(tv.Title, tv.Year), naming.FormatEpisodeTag(tv.Season, tv.Episode, tv.EpisodeEnd))
+			} else {
+				movie, err := naming.ParseMovieName(release)
+				if err != nil {
+					return fmt.Errorf("cannot parse %q as a movie: %w", release, err)
+				}
+				target = filepath.Join(
+					tmpl.MovieFolderName(movie, values),
+					tmpl.MovieFileName(movie, values, ext))
+				fmt.Printf("Type:        movie\n")
+				fmt.Printf("Parsed:      %s", naming.NormalizeMediaName(movie.Title, movie.Year))
+				if movie.Edition != "" {
+					fmt.Printf(" - %s", movie.Edition)
+				}
+				fmt.Println()
+			}
+			fmt.Printf("Release:     %s %s %s\n", valueOrDash(values.Resolution), valueOrDash(values.Source), valueOrDash(values.ReleaseGroup))
+			if library != "" {
+				target = filepath.Join(library, target)
+			}
+			fmt.Printf("Target:      %s\n", target)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&library, "library", "", "use the templates configured for this library")
+	cmd.Flags().StringVar(&overrides.MovieFolder, "movie-folder", "", "movie folder template")
+	cmd.Flags().StringVar(&overrides.MovieFile, "movie-file", "", "movie file template")
+	cmd.Flags().StringVar(&overrides.SeriesFolder, "series-folder", "", "series folder template")
+	cmd.Flags().StringVar(&overrides.SeasonFolder, "season-folder", "", "season folder template")
+	cmd.Flags().StringVar(&overrides.EpisodeFile, "episode-file", "", "episode file template")
+	cmd.Flags().StringVar(&values.ImdbID, "imdb", "", "IMDb ID for {ImdbID}")
+	cmd.Flags().StringVar(&values.TmdbID, "tmdb", "", "TMDB ID for {TmdbID}")
+	cmd.Flags().StringVar(&values.TvdbID, "tvdb", "", "TVDB ID for {TvdbID}")
+	cmd.Flags().StringVar(&episodeTitle, "episode-title", "", "episode title for {EpisodeTitle}")
+	cmd.Flags().BoolVar(&showMovie, "movie", false, "treat the release as a movie")
+	cmd.Flags().BoolVar(&showEpisode, "episode", false, "treat the release as a TV episode")
+
+	return cmd
+}
+
+// mergeTemplates returns base with the non-empty templates of override.
+func mergeTemplates(base, override naming.Templates) naming.Templates {
+	if override.MovieFolder != "" {
+		base.MovieFolder = override.MovieFolder
+	}
+	if override.MovieFile != "" {
+		base.MovieFile = override.MovieFile
+	}
+	if override.SeriesFolder != "" {
+		base.SeriesFolder = override.SeriesFolder
+	}
+	if override.SeasonFolder != "" {
+		base.SeasonFolder = override.SeasonFolder
+	}
+	if override.EpisodeFile != "" {
+		base.EpisodeFile = override.EpisodeFile
+	}
+	return base
+}
+
+// configuredTemplates returns the naming templates of the configured
+// library holding path, or the zero Templates when there is no config.
+func configuredTemplates(path string) naming.Templates {
+	if !config.ConfigExists() {
+		return naming.Templates{}
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return naming.Templates{}
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	return cfg.Libraries.TemplatesForPath(abs)
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 func newConfigPathCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "path",
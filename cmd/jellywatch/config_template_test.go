@@ -0,0 +1,24 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigTestTemplateRejectsUnknownToken(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+
+	cmd := newRootCmd()
+	cmd.SetArgs([]string{"config", "test-template", "The.Matrix.1999.1080p.mkv", "--movie-file", "{Title} {Quality}"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "{Quality}") {
+		t.Fatalf("expected unknown token error, got %v", err)
+	}
+
+	cmd = newRootCmd()
+	cmd.SetArgs([]string{"config", "test-template", "The.Matrix.1999.1080p.mkv", "--movie-folder", "{Title} ({Year}) [imdbid-{ImdbID}]"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("valid template preview failed: %v", err)
+	}
+}
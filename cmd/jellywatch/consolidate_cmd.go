@@ -12,6 +12,7 @@ import (
 	"github.com/Nomadcxx/jellywatch/internal/plans"
 	"github.com/Nomadcxx/jellywatch/internal/privilege"
 	"github.com/Nomadcxx/jellywatch/internal/transfer"
+	"github.com/Nomadcxx/jellywatch/internal/trash"
 )
 
 func runConsolidateExecute(db *database.MediaDB, cfg *config.Config) error {
@@ -56,6 +57,7 @@ func runConsolidateExecute(db *database.MediaDB, cfg *config.Config) error {
 	fmt.Println("\n📦 Executing consolidation plans...")
 
 	allLibraryRoots := append(cfg.Libraries.TV, cfg.Libraries.Movies...)
+	bin := trash.New(db, cfg.Trash)
 
 	transferer, err := transfer.New(transfer.BackendAuto)
 	if err != nil {
@@ -111,7 +113,7 @@ func runConsolidateExecute(db *database.MediaDB, cfg *config.Config) error {
 
 			// Cleanup source directory (delete cruft and empty dirs)
 			sourceDir := filepath.Dir(op.SourcePath)
-			if err := cleanupSourceDir(sourceDir, allLibraryRoots); err != nil {
+			if err := cleanupSourceDir(sourceDir, allLibraryRoots, bin); err != nil {
 				fmt.Printf("  ⚠️  Cleanup warning: %v\n", err)
 			}
 
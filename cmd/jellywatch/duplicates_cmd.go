@@ -11,9 +11,10 @@ import (
 	"github.com/Nomadcxx/jellywatch/internal/permissions"
 	"github.com/Nomadcxx/jellywatch/internal/plans"
 	"github.com/Nomadcxx/jellywatch/internal/privilege"
+	"github.com/Nomadcxx/jellywatch/internal/trash"
 )
 
-func deleteDuplicateFile(db *database.MediaDB, filePath string, uid, gid int) error {
+func deleteDuplicateFile(db *database.MediaDB, bin *trash.Bin, filePath string, uid, gid int) error {
 	canDelete, err := permissions.CanDelete(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -25,7 +26,7 @@ func deleteDuplicateFile(db *database.MediaDB, filePath string, uid, gid int) er
 
 	if !canDelete {
 		if err := permissions.FixPermissions(filePath, uid, gid); err != nil {
-			if removeErr := os.Remove(filePath); removeErr != nil {
+			if _, removeErr := bin.Remove(filePath, "duplicate"); removeErr != nil {
 				_ = db.DeleteMediaFile(filePath)
 				if os.IsNotExist(removeErr) {
 					return nil
@@ -35,7 +36,7 @@ func deleteDuplicateFile(db *database.MediaDB, filePath string, uid, gid int) er
 		}
 	}
 
-	if err := os.Remove(filePath); err != nil {
+	if _, err := bin.Remove(filePath, "duplicate"); err != nil {
 		_ = db.DeleteMediaFile(filePath)
 
 		if os.IsNotExist(err) {
@@ -68,9 +69,19 @@ func runDuplicatesExecute(db *database.MediaDB, cfg *config.Config) error {
 		return nil
 	}
 
-	fmt.Printf("⚠️  WARNING: This will permanently DELETE %d files.\n", plan.Summary.FilesToDelete)
-	fmt.Printf("Space to reclaim: %s\n", formatBytes(plan.Summary.SpaceReclaimable))
-	fmt.Println("This action CANNOT be undone!")
+	var bin *trash.Bin
+	if cfg != nil {
+		bin = trash.New(db, cfg.Trash)
+	}
+	if bin.Enabled() {
+		fmt.Printf("This will move %d files to the trash.\n", plan.Summary.FilesToDelete)
+		fmt.Printf("Space to reclaim once the trash is purged: %s\n", formatBytes(plan.Summary.SpaceReclaimable))
+		fmt.Println("Restore them with 'jellywatch trash restore'.")
+	} else {
+		fmt.Printf("⚠️  WARNING: This will permanently DELETE %d files.\n", plan.Summary.FilesToDelete)
+		fmt.Printf("Space to reclaim: %s\n", formatBytes(plan.Summary.SpaceReclaimable))
+		fmt.Println("This action CANNOT be undone!")
+	}
 	fmt.Print("\nContinue? [y/N]: ")
 
 	reader := bufio.NewReader(os.Stdin)
@@ -113,7 +124,7 @@ func runDuplicatesExecute(db *database.MediaDB, cfg *config.Config) error {
 			gid, _ = cfg.Permissions.ResolveGID()
 		}
 
-		if err := deleteDuplicateFile(db, filePath, uid, gid); err != nil {
+		if err := deleteDuplicateFile(db, bin, filePath, uid, gid); err != nil {
 			fmt.Printf("  ❌ Failed to delete %s: %v\n", filePath, err)
 			failedCount++
 			continue
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/Nomadcxx/jellywatch/internal/config"
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+	"github.com/Nomadcxx/jellywatch/internal/quality"
+	"github.com/spf13/cobra"
+)
+
+func newDuplicatesExplainCmd() *cobra.Command {
+	var profileName string
+
+	cmd := &cobra.Command{
+		Use:   "explain <file> <file>",
+		Short: "Explain why one copy outscores another",
+		Long: `Score two copies with a quality profile and show what each factor
+contributed, so you can see why duplicate resolution keeps one over the other.
+
+The profile defaults to the one assigned to the files' library; files in
+libraries with different profiles are compared with the default profile.
+Stream data measured by ffprobe during scans is used when available.`,
+		Example: `  jellywatch duplicates explain "/movies/Heat (1995)/Heat.1995.2160p.WEB-DL.mkv" "/movies/Heat (1995) [Remux]/Heat.1995.1080p.BluRay.REMUX.mkv"
+  jellywatch duplicates explain a.mkv b.mkv --profile remux-first`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDuplicatesExplain(cmd.OutOrStdout(), args[0], args[1], profileName)
+		},
+	}
+
+	cmd.Flags().StringVar(&profileName, "profile", "", "Quality profile to score with (default: the library's profile)")
+
+	return cmd
+}
+
+func runDuplicatesExplain(w io.Writer, pathA, pathB, profileName string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	paths := []string{pathA, pathB}
+	for i, p := range paths {
+		if abs, err := filepath.Abs(p); err == nil {
+			paths[i] = abs
+		}
+	}
+
+	var profile *quality.Profile
+	if profileName != "" {
+		if profile, err = cfg.Quality.Lookup(profileName); err != nil {
+			return err
+		}
+	} else {
+		profiles, err := cfg.QualityProfiles()
+		if err != nil {
+			return err
+		}
+		profile = profiles.ForFiles(paths)
+	}
+
+	// Stored probes are a bonus; explain works without a database.
+	db, err := database.Open()
+	if err == nil {
+		defer db.Close()
+	}
+
+	breakdowns := make([]quality.Breakdown, len(paths))
+	for i, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("cannot read %s: %w", path, err)
+		}
+		var probed *quality.Probed
+		if db != nil {
+			if result, err := db.GetMediaFileProbe(path); err == nil {
+				probed = result.Quality()
+			}
+		}
+		breakdowns[i] = profile.ExplainFile(path, info.Size(), isEpisodePath(cfg, path), probed)
+	}
+
+	writeScoreComparison(w, paths, breakdowns)
+	return nil
+}
+
+// isEpisodePath reports whether path is a TV episode, by library when it
+// lives in one and by filename otherwise. Episodes have a lower size cap.
+func isEpisodePath(cfg *config.Config, path string) bool {
+	for _, root := range cfg.Libraries.TV {
+		if pathIsUnderRoot(path, root) {
+			return true
+		}
+	}
+	for _, root := range cfg.Libraries.Movies {
+		if pathIsUnderRoot(path, root) {
+			return false
+		}
+	}
+	return naming.IsTVEpisodeFilename(filepath.Base(path))
+}
+
+// writeScoreComparison prints two breakdowns factor by factor and names
+// the copy duplicate resolution would keep.
+func writeScoreComparison(w io.Writer, paths []string, b []quality.Breakdown) {
+	fmt.Fprintf(w, "Quality profile: %s\n\n", b[0].Profile)
+	fmt.Fprintf(w, "  A: %s\n  B: %s\n\n", paths[0], paths[1])
+
+	fmt.Fprintf(w, "  %-12s %-23s   %s\n", "FACTOR", "A", "B")
+	points := make(map[string]quality.ScoreItem, len(b[1].Items))
+	for _, item := range b[1].Items {
+		points[item.Factor] = item
+	}
+	for _, a := range b[0].Items {
+		other, ok := points[a.Factor]
+		if !ok {
+			other = quality.ScoreItem{Value: "-"}
+		}
+		marker := ""
+		switch {
+		case a.Points > other.Points:
+			marker = "  <- A"
+		case a.Points < other.Points:
+			marker = "  <- B"
+		}
+		fmt.Fprintf(w, "  %-12s %-16s %+6d   %-16s %+6d%s\n", a.Factor, a.Value, a.Points, other.Value, other.Points, marker)
+	}
+	fmt.Fprintf(w, "  %-12s %-16s %6d   %-16s %6d\n\n", "TOTAL", "", b[0].Total, "", b[1].Total)
+
+	switch diff := b[0].Total - b[1].Total; {
+	case diff > 0:
+		fmt.Fprintf(w, "A wins by %d points and would be kept.\n", diff)
+	case diff < 0:
+		fmt.Fprintf(w, "B wins by %d points and would be kept.\n", -diff)
+	default:
+		fmt.Fprintln(w, "Tie: the larger file would be kept.")
+	}
+}
@@ -15,6 +15,11 @@ func runDuplicatesGenerate() error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	profiles, err := cfg.QualityProfiles()
+	if err != nil {
+		return err
+	}
+
 	if err := checkDatabasePopulated(); err != nil {
 		return err
 	}
@@ -28,6 +33,7 @@ func runDuplicatesGenerate() error {
 	fmt.Println("🔍 Analyzing library for duplicates...")
 
 	svc := service.NewCleanupService(db)
+	svc.SetQualityProfiles(profiles)
 	analysis, err := svc.AnalyzeDuplicates()
 	if err != nil {
 		return fmt.Errorf("failed to analyze duplicates: %w", err)
@@ -81,6 +87,7 @@ func runDuplicatesGenerate() error {
 			Year:      group.Year,
 			Season:    group.Season,
 			Episode:   group.Episode,
+			Profile:   group.QualityProfile,
 			Keep: plans.FileInfo{
 				ID:           keepFile.ID,
 				Path:         keepFile.Path,
@@ -38,7 +38,7 @@ func TestDeleteDuplicateWithPermissions(t *testing.T) {
 		t.Fatalf("Failed to insert: %v", err)
 	}
 
-	err = deleteDuplicateFile(db, testFile, -1, -1)
+	err = deleteDuplicateFile(db, nil, testFile, -1, -1)
 
 	if err != nil {
 		t.Errorf("Delete failed even after permission fix: %v", err)
@@ -74,7 +74,7 @@ func TestDeleteDuplicateDatabaseCleanupOnFailure(t *testing.T) {
 		t.Fatalf("Failed to insert: %v", err)
 	}
 
-	err = deleteDuplicateFile(db, testFile, -1, -1)
+	err = deleteDuplicateFile(db, nil, testFile, -1, -1)
 
 	if err != nil {
 		t.Logf("Expected error for non-existent file: %v", err)
@@ -295,3 +295,37 @@ func TestRunDuplicatesExecuteArchivesPlanOnPartialFailure(t *testing.T) {
 		t.Fatalf("archived plan should exist after partial failure: %v", err)
 	}
 }
+
+func TestDuplicatesExplainNamesWinner(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+
+	dir := t.TempDir()
+	remux := filepath.Join(dir, "Movie.2020.1080p.BluRay.REMUX.TrueHD.Atmos.mkv")
+	web := filepath.Join(dir, "Movie.2020.2160p.WEB-DL.x265.mkv")
+	for _, p := range []string{remux, web} {
+		if err := os.WriteFile(p, []byte("video"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	cmd := newRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"duplicates", "explain", remux, web})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("explain failed: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{"Quality profile: default", "resolution", "1080p", "2160p", "B wins by"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+
+	cmd = newRootCmd()
+	cmd.SetArgs([]string{"duplicates", "explain", remux, web, "--profile", "nope"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Fatalf("expected unknown profile error, got %v", err)
+	}
+}
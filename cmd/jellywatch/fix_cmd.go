@@ -3,7 +3,9 @@ package main
 import (
 	"fmt"
 
+	"github.com/Nomadcxx/jellywatch/internal/config"
 	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/trash"
 	"github.com/Nomadcxx/jellywatch/internal/wizard"
 	"github.com/spf13/cobra"
 )
@@ -46,6 +48,15 @@ Examples:
 }
 
 func runFix(dryRun, autoYes, duplicatesOnly, consolidateOnly bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	profiles, err := cfg.QualityProfiles()
+	if err != nil {
+		return err
+	}
+
 	db, err := database.Open()
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
@@ -57,6 +68,8 @@ func runFix(dryRun, autoYes, duplicatesOnly, consolidateOnly bool) error {
 		AutoYes:         autoYes,
 		DuplicatesOnly:  duplicatesOnly,
 		ConsolidateOnly: consolidateOnly,
+		QualityProfiles: profiles,
+		Trash:           trash.New(db, cfg.Trash),
 	}
 
 	w := wizard.New(db, opts)
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/spf13/cobra"
+)
+
+func newHistoryCmd() *cobra.Command {
+	return newHistoryCmdWithDeps(database.Open, os.Stdout)
+}
+
+func newHistoryCmdWithDeps(openDB func() (*database.MediaDB, error), stdout io.Writer) *cobra.Command {
+	var (
+		limit      int
+		source     string
+		batch      string
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show the journal of files jellywatch moved, renamed or deleted",
+		Long: `Lists filesystem changes newest first: organizes, housekeeping merges and
+parser-drift renames, consolidation and duplicate deletions. Each entry has
+an operation ID and a batch ID, either of which "jellywatch undo" accepts.`,
+		Example: `  jellywatch history
+  jellywatch history --source organize --limit 20
+  jellywatch history --batch merge_move-20260102T030405-9f3a1c`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openDB()
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			defer db.Close()
+
+			var ops []database.JournalOp
+			if batch != "" {
+				ops, err = db.ListOperationBatch(batch)
+			} else {
+				ops, err = db.ListOperations(source, limit)
+			}
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				enc := json.NewEncoder(stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(ops)
+			}
+			if len(ops) == 0 {
+				fmt.Fprintln(stdout, "no operations recorded")
+				return nil
+			}
+			for _, op := range ops {
+				printJournalOp(stdout, op)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 50, "maximum operations to show")
+	cmd.Flags().StringVar(&source, "source", "", "only show one workflow (organize, merge_move, parser_drift, consolidate, duplicate_delete, undo)")
+	cmd.Flags().StringVar(&batch, "batch", "", "show every operation in a batch, oldest first")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+	cmd.MarkFlagsMutuallyExclusive("batch", "source")
+
+	return cmd
+}
+
+func printJournalOp(w io.Writer, op database.JournalOp) {
+	status := ""
+	switch {
+	case op.UndoneAt != nil:
+		status = " [undone]"
+	case !op.Reversible:
+		status = " [irreversible]"
+	}
+	fmt.Fprintf(w, "#%-6d %s  %-16s %-6s %s%s\n",
+		op.ID, op.CreatedAt.Local().Format("2006-01-02 15:04:05"), op.Source, op.Kind, op.BatchID, status)
+	if op.BeforePath != "" {
+		fmt.Fprintf(w, "        from: %s\n", op.BeforePath)
+	}
+	if op.AfterPath != "" {
+		fmt.Fprintf(w, "        to:   %s\n", op.AfterPath)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Nomadcxx/jellywatch/internal/database"
+)
+
+func TestHistoryAndUndoCmd(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "test.db")
+	openDB := func() (*database.MediaDB, error) { return database.OpenPath(dbPath) }
+
+	src := filepath.Join(dir, "downloads", "Heat.1995.mkv")
+	dst := filepath.Join(dir, "movies", "Heat (1995)", "Heat (1995).mkv")
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dst, []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := openDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := db.RecordOperation(database.JournalOp{
+		BatchID: "organize-test", Source: database.OpSourceOrganize, Kind: database.OpMove,
+		BeforePath: src, AfterPath: dst, Reversible: true,
+	})
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	history := newHistoryCmdWithDeps(openDB, &out)
+	history.SetArgs([]string{"--source", "organize"})
+	if err := history.Execute(); err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if !strings.Contains(out.String(), "organize-test") || !strings.Contains(out.String(), dst) {
+		t.Fatalf("history output = %q", out.String())
+	}
+
+	out.Reset()
+	undo := newUndoCmdWithDeps(openDB, nil, &out)
+	undo.SetArgs([]string{strconv.FormatInt(id, 10)})
+	if err := undo.Execute(); err != nil {
+		t.Fatalf("undo: %v\n%s", err, out.String())
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Fatalf("file not restored to %s: %v", src, err)
+	}
+
+	out.Reset()
+	history = newHistoryCmdWithDeps(openDB, &out)
+	history.SetArgs([]string{"--batch", "organize-test"})
+	if err := history.Execute(); err != nil {
+		t.Fatalf("history --batch: %v", err)
+	}
+	if !strings.Contains(out.String(), "[undone]") {
+		t.Fatalf("history should mark the operation undone: %q", out.String())
+	}
+}
@@ -99,13 +99,20 @@ The primary CLI workflows are:
 	rootCmd.AddCommand(newDaemonCmd())
 	rootCmd.AddCommand(newRepairCmd())
 	rootCmd.AddCommand(newPostmortemCmd())
+	rootCmd.AddCommand(newHistoryCmd())
+	rootCmd.AddCommand(newUndoCmd())
+	rootCmd.AddCommand(newTrashCmd())
+	rootCmd.AddCommand(newRulesCmd())
+	rootCmd.AddCommand(newAliasCmd())
 	hideRootCommands(rootCmd,
+		"alias",
 		"audit",
 		"cleanup",
 		"daemon",
 		"database",
 		"fix",
 		"health",
+		"history",
 		"migrate",
 		"monitor",
 		"organize",
@@ -116,8 +123,11 @@ The primary CLI workflows are:
 		"radarr",
 		"repair",
 		"review",
+		"rules",
 		"serve",
 		"sonarr",
+		"trash",
+		"undo",
 		"validate",
 		"watch",
 	)
@@ -160,7 +170,7 @@ Examples:
 	cmd.Flags().BoolVarP(&recursive, "recursive", "r", true, "process subdirectories")
 	cmd.Flags().DurationVarP(&timeout, "timeout", "t", 5*time.Minute, "transfer timeout")
 	cmd.Flags().BoolVar(&verifyChecksum, "checksum", false, "verify checksum after transfer")
-	cmd.Flags().StringVarP(&backendName, "backend", "b", "auto", "transfer backend: auto, pv, rsync, native")
+	cmd.Flags().StringVarP(&backendName, "backend", "b", "auto", "transfer backend: auto, pv, rsync, native, hardlink, reflink")
 
 	return cmd
 }
@@ -202,6 +212,7 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 		organizer.WithTimeout(timeout),
 		organizer.WithChecksumVerify(verifyChecksum),
 		organizer.WithBackend(transfer.ParseBackend(backendName)),
+		organizer.WithLibraryTemplates(target, configuredTemplates(target)),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create organizer: %w", err)
@@ -278,6 +289,7 @@ func organizeDirectory(org *organizer.Organizer, source, target string) error {
 
 func newOrganizeFolderCmd() *cobra.Command {
 	var keepExtras bool
+	var extrasLayout string
 
 	cmd := &cobra.Command{
 		Use:   "organize-folder <folder> [library]",
@@ -290,10 +302,12 @@ This command analyzes the folder to:
   - Copy subtitles alongside the media
   - Remove samples and junk files after successful transfer
   - Detect incomplete archives (RAR files without extracted media)
+  - With --keep-extras, place trailers and featurettes beside the media
 
 Examples:
   jellywatch organize-folder /downloads/Movie.2024.1080p/ /media/Movies
   jellywatch organize-folder /downloads/Show.S01E05/ /media/TV --keep-extras
+  jellywatch organize-folder /downloads/Movie.2024.1080p/ --keep-extras --extras-layout suffix
   jellywatch organize-folder /downloads/folder/ --dry-run`,
 		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -315,6 +329,11 @@ Examples:
 				target = cfg.Libraries.Movies[0]
 			}
 
+			layout, err := naming.ParseExtrasLayout(extrasLayout)
+			if err != nil {
+				return err
+			}
+
 			// Escalate to root if needed for file operations
 			if privilege.NeedsRoot() {
 				return privilege.Escalate("move files and set ownership")
@@ -328,6 +347,8 @@ Examples:
 				organizer.WithTimeout(timeout),
 				organizer.WithChecksumVerify(verifyChecksum),
 				organizer.WithBackend(transfer.ParseBackend(backendName)),
+				organizer.WithLibraryTemplates(target, configuredTemplates(target)),
+				organizer.WithExtrasLayout(layout),
 			)
 			if err != nil {
 				return fmt.Errorf("failed to create organizer: %w", err)
@@ -353,8 +374,9 @@ Examples:
 	cmd.Flags().BoolVarP(&forceOverwrite, "force", "f", false, "overwrite existing files regardless of quality")
 	cmd.Flags().DurationVarP(&timeout, "timeout", "t", 5*time.Minute, "transfer timeout")
 	cmd.Flags().BoolVar(&verifyChecksum, "checksum", false, "verify checksum after transfer")
-	cmd.Flags().StringVarP(&backendName, "backend", "b", "auto", "transfer backend: auto, pv, rsync, native")
-	cmd.Flags().BoolVar(&keepExtras, "keep-extras", false, "preserve extra files (trailers, featurettes)")
+	cmd.Flags().StringVarP(&backendName, "backend", "b", "auto", "transfer backend: auto, pv, rsync, native, hardlink, reflink")
+	cmd.Flags().BoolVar(&keepExtras, "keep-extras", false, "place extras (trailers, featurettes) beside the media in the library")
+	cmd.Flags().StringVar(&extrasLayout, "extras-layout", "folders", "how movie extras are placed: folders, suffix or skip")
 
 	return cmd
 }
@@ -378,7 +400,7 @@ func printFolderResult(result *organizer.FolderOrganizationResult, keepExtras bo
 	}
 
 	if len(result.SubtitlesCopied) > 0 {
-		fmt.Printf("📝 Subtitles copied: %d\n", len(result.SubtitlesCopied))
+		fmt.Printf("📝 Subtitles placed: %d\n", len(result.SubtitlesCopied))
 		if verbose {
 			for _, s := range result.SubtitlesCopied {
 				fmt.Printf("   - %s\n", s)
@@ -399,6 +421,15 @@ func printFolderResult(result *organizer.FolderOrganizationResult, keepExtras bo
 		fmt.Printf("🗑  Samples removed: %d\n", len(result.SamplesRemoved))
 	}
 
+	if len(result.ExtrasPlaced) > 0 {
+		fmt.Printf("🎞  Extras placed: %d\n", len(result.ExtrasPlaced))
+		if verbose {
+			for _, e := range result.ExtrasPlaced {
+				fmt.Printf("   - %s\n", e)
+			}
+		}
+	}
+
 	if len(result.ExtrasSkipped) > 0 && !keepExtras {
 		fmt.Printf("⏭  Extras skipped: %d\n", len(result.ExtrasSkipped))
 		if verbose {
@@ -423,12 +454,15 @@ func printResult(result *organizer.OrganizationResult) {
 	}
 
 	if result.Success {
-		action := "moved"
+		verb, action := "move", "moved"
 		if keepSource {
-			action = "copied"
+			verb, action = "copy", "copied"
+		}
+		if result.LinkType != "" {
+			verb, action = result.LinkType, result.LinkType+"ed"
 		}
 		if dryRun {
-			action = "would " + action[:len(action)-1]
+			action = "would " + verb
 		}
 		fmt.Printf("✓ %s %s\n", action, filepath.Base(result.SourcePath))
 		if verbose {
@@ -471,7 +505,7 @@ Examples:
 func runValidate(cmd *cobra.Command, args []string) error {
 	path := args[0]
 
-	v := validator.NewValidator()
+	v := validator.NewValidator(validator.WithTemplates(configuredTemplates(path)))
 
 	info, err := os.Stat(path)
 	if err != nil {
@@ -961,6 +995,8 @@ func newWatchCmd() *cobra.Command {
 	var tvLibrary string
 	var movieLibrary string
 	var debounce time.Duration
+	var watchBackend string
+	var pollInterval time.Duration
 
 	cmd := &cobra.Command{
 		Use:   "watch <directory>",
@@ -973,7 +1009,8 @@ Jellyfin naming conventions and moved to the appropriate library.
 Examples:
   jellywatch watch /downloads/tv --tv-library /media/TV
   jellywatch watch /downloads --tv-library /media/TV --movie-library /media/Movies
-  jellywatch watch /downloads -n  # dry-run mode`,
+  jellywatch watch /downloads -n  # dry-run mode
+  jellywatch watch /mnt/nas/downloads --watch-backend poll  # NFS/SMB/rclone`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg, err := config.Load()
@@ -1006,20 +1043,49 @@ Examples:
 				return fmt.Errorf("no libraries configured (use --tv-library or --movie-library, or set in config)")
 			}
 
+			filters, err := cfg.Watch.IngestFilters()
+			if err != nil {
+				return fmt.Errorf("invalid watch filters: %w", err)
+			}
+			extractor, err := cfg.Extract.Extractor()
+			if err != nil {
+				return fmt.Errorf("invalid extract settings: %w", err)
+			}
+			extrasLayout, err := cfg.Options.ExtrasLayout()
+			if err != nil {
+				return fmt.Errorf("invalid extras setting: %w", err)
+			}
+
 			handler, err := daemon.NewMediaHandler(daemon.MediaHandlerConfig{
-				TVLibraries:  tvLibs,
-				MovieLibs:    movieLibs,
-				DebounceTime: debounce,
-				DryRun:       dryRun,
-				Timeout:      timeout,
-				Backend:      transfer.ParseBackend(backendName),
+				TVLibraries:        tvLibs,
+				MovieLibs:          movieLibs,
+				DebounceTime:       debounce,
+				DryRun:             dryRun,
+				Timeout:            timeout,
+				Backend:            transfer.ParseBackend(backendName),
+				Extractor:          extractor,
+				KeepArchives:       cfg.Extract.KeepSource,
+				Filters:            filters,
+				ExtrasLayout:       extrasLayout,
+				ResolutionVersions: cfg.Options.ResolutionVersions,
 			})
 			if err != nil {
 				return fmt.Errorf("failed to create media handler: %w", err)
 			}
 			defer handler.Shutdown()
 
-			w, err := watcher.NewWatcher(handler, dryRun)
+			if watchBackend == "" {
+				watchBackend = cfg.Watch.Backend
+			}
+			if pollInterval == 0 {
+				pollInterval = cfg.Watch.PollInterval()
+			}
+			w, err := watcher.NewWatcher(handler, dryRun,
+				watcher.WithSettleWindow(cfg.Watch.SettleWindow()),
+				watcher.WithBackend(watchBackend, cfg.Watch.PathBackends()),
+				watcher.WithPollInterval(pollInterval),
+				watcher.WithFilters(filters),
+				watcher.WithArchives(extractor != nil))
 			if err != nil {
 				return fmt.Errorf("creating watcher: %w", err)
 			}
@@ -1029,7 +1095,7 @@ Examples:
 				return fmt.Errorf("setting up watch: %w", err)
 			}
 
-			fmt.Printf("Watching: %s\n", watchDir)
+			fmt.Printf("Watching: %s (%s)\n", watchDir, w.Backends()[filepath.Clean(watchDir)])
 			if len(tvLibs) > 0 {
 				fmt.Printf("TV Library: %s\n", tvLibs[0])
 			}
@@ -1049,7 +1115,9 @@ Examples:
 	cmd.Flags().StringVar(&movieLibrary, "movie-library", "", "target movie library path")
 	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Minute, "transfer timeout")
 	cmd.Flags().DurationVar(&debounce, "debounce", 10*time.Second, "debounce time before processing")
-	cmd.Flags().StringVarP(&backendName, "backend", "b", "auto", "transfer backend: auto, pv, rsync, native")
+	cmd.Flags().StringVarP(&backendName, "backend", "b", "auto", "transfer backend: auto, pv, rsync, native, hardlink, reflink")
+	cmd.Flags().StringVar(&watchBackend, "watch-backend", "", "watch backend: auto, fsnotify, poll (default from config)")
+	cmd.Flags().DurationVar(&pollInterval, "poll-interval", 0, "how often the poll backend re-reads the folder (default from config)")
 
 	return cmd
 }
@@ -1161,5 +1229,7 @@ can help you clean up wasted space.`,
 		},
 	})
 
+	cmd.AddCommand(newDuplicatesExplainCmd())
+
 	return cmd
 }
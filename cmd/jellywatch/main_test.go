@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Nomadcxx/jellywatch/internal/organizer"
+)
+
+func TestPrintResultVerbs(t *testing.T) {
+	oldDryRun, oldKeepSource := dryRun, keepSource
+	t.Cleanup(func() { dryRun, keepSource = oldDryRun, oldKeepSource })
+
+	tests := []struct {
+		dryRun, keepSource bool
+		linkType           string
+		want               string
+	}{
+		{false, false, "", "✓ moved "},
+		{true, false, "", "✓ would move "},
+		{false, true, "", "✓ copied "},
+		{true, true, "", "✓ would copy "},
+		{false, false, "hardlink", "✓ hardlinked "},
+		{true, false, "hardlink", "✓ would hardlink "},
+		{false, false, "reflink", "✓ reflinked "},
+	}
+	for _, tt := range tests {
+		dryRun, keepSource = tt.dryRun, tt.keepSource
+		out := capturePrintResult(t, &organizer.OrganizationResult{
+			Success:    true,
+			SourcePath: "/downloads/Movie.2001.mkv",
+			LinkType:   tt.linkType,
+		})
+		if !strings.HasPrefix(out, tt.want) {
+			t.Errorf("dryRun=%v keepSource=%v link=%q: got %q, want prefix %q", tt.dryRun, tt.keepSource, tt.linkType, out, tt.want)
+		}
+	}
+}
+
+func capturePrintResult(t *testing.T, result *organizer.OrganizationResult) string {
+	t.Helper()
+	oldStdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("stdout pipe: %v", err)
+	}
+	os.Stdout = w
+	printResult(result)
+	os.Stdout = oldStdout
+	_ = w.Close()
+	var out bytes.Buffer
+	_, _ = io.Copy(&out, r)
+	return out.String()
+}
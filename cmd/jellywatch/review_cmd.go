@@ -146,7 +146,8 @@ func applyApproved(cfg *config.Config, item daemon.EnhanceLogEntry) error {
 		if targetLib == "" {
 			targetLib = cfg.Libraries.TV[0]
 		}
-		org, err := organizer.NewOrganizer(cfg.Libraries.TV)
+		org, err := organizer.NewOrganizer(cfg.Libraries.TV,
+			organizer.WithLibraryTemplates(targetLib, cfg.Libraries.TemplatesFor(targetLib)))
 		if err != nil {
 			return fmt.Errorf("build organizer: %w", err)
 		}
@@ -177,11 +178,12 @@ func applyApproved(cfg *config.Config, item daemon.EnhanceLogEntry) error {
 		if targetLib == "" {
 			targetLib = cfg.Libraries.Movies[0]
 		}
-		org, err := organizer.NewOrganizer(cfg.Libraries.Movies)
+		org, err := organizer.NewOrganizer(cfg.Libraries.Movies,
+			organizer.WithLibraryTemplates(targetLib, cfg.Libraries.TemplatesFor(targetLib)))
 		if err != nil {
 			return fmt.Errorf("build organizer: %w", err)
 		}
-		mv := naming.MovieInfo{Title: item.AITitle, Year: yearStr}
+		mv := naming.MovieInfo{Title: item.AITitle, Year: yearStr, Part: naming.ParseStackPart(item.SourcePath)}
 		result, err := org.OrganizeMovieWithParsed(item.SourcePath, targetLib, mv)
 		if err != nil {
 			return err
@@ -51,12 +51,14 @@ func TestNewRootCmdKeepsAdvancedCommandsCallableButHidden(t *testing.T) {
 	hidden := hiddenSubcommandMap(cmd)
 
 	for _, required := range []string{
+		"alias",
 		"audit",
 		"cleanup",
 		"daemon",
 		"database",
 		"fix",
 		"health",
+		"history",
 		"migrate",
 		"monitor",
 		"orphans",
@@ -65,10 +67,13 @@ func TestNewRootCmdKeepsAdvancedCommandsCallableButHidden(t *testing.T) {
 		"radarr",
 		"repair",
 		"review",
+		"rules",
 		"serve",
 		"sonarr",
 		"organize",
 		"organize-folder",
+		"trash",
+		"undo",
 		"validate",
 		"watch",
 	} {
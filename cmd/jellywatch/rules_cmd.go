@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/Nomadcxx/jellywatch/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newRulesCmd() *cobra.Command {
+	return newRulesCmdWithDeps(config.Load, os.Stdout)
+}
+
+func newRulesCmdWithDeps(loadConfig func() (*config.Config, error), stdout io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rules",
+		Short: "Inspect the [[rules]] that route, ignore or re-title downloads",
+		Long: `Rules in the config are checked in order against every download before
+it is parsed; the first one that matches decides its library, media type,
+title, whether it is ignored, whether the download is kept and how it is
+transferred.`,
+	}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "Show the configured rules in evaluation order",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			engine, err := cfg.RuleEngine()
+			if err != nil {
+				return err
+			}
+			if len(engine.Rules()) == 0 {
+				fmt.Fprintln(stdout, "no rules configured")
+				return nil
+			}
+			for i, r := range engine.Rules() {
+				fmt.Fprintf(stdout, "%d. %s: %s\n", i+1, r.Name, r.Actions())
+			}
+			return nil
+		},
+	}
+
+	var sizeMB int64
+	var category string
+	test := &cobra.Command{
+		Use:   "test <path>",
+		Short: "Explain which rule a download would match",
+		Long: `Checks path against every rule and says why each did or didn't match.
+The path doesn't have to exist; pass --size-mb to test size bounds for a
+file that isn't there, and --category to test category matchers with the
+download client category the file would come from.`,
+		Example: `  jellywatch rules test "/downloads/movies/Kids/Frozen.2013.1080p.BluRay.x264-SPARKS.mkv"
+  jellywatch rules test "/downloads/tv/[SubsPlease] Frieren - 01 (1080p).mkv" --size-mb 1400
+  jellywatch rules test "/downloads/complete/Dune.2021.2160p.UHD.BluRay.x265-GROUP.mkv" --category radarr-4k`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			engine, err := cfg.RuleEngine()
+			if err != nil {
+				return err
+			}
+
+			path := args[0]
+			if abs, err := filepath.Abs(path); err == nil {
+				path = abs
+			}
+			size := int64(-1)
+			if cmd.Flags().Changed("size-mb") {
+				size = sizeMB << 20
+			} else if info, err := os.Stat(path); err == nil {
+				size = info.Size()
+			}
+
+			verdicts := engine.Explain(path, size, category)
+			if len(verdicts) == 0 {
+				fmt.Fprintln(stdout, "no rules configured")
+				return nil
+			}
+			matched := false
+			for i, v := range verdicts {
+				switch {
+				case v.Matched && !matched:
+					matched = true
+					fmt.Fprintf(stdout, "%d. %s: MATCH -> %s\n", i+1, v.Rule.Name, v.Rule.Actions())
+				case v.Matched:
+					fmt.Fprintf(stdout, "%d. %s: would match, but an earlier rule wins\n", i+1, v.Rule.Name)
+				default:
+					fmt.Fprintf(stdout, "%d. %s: no match (%s)\n", i+1, v.Rule.Name, v.Reason)
+				}
+			}
+			if !matched {
+				fmt.Fprintln(stdout, "No rule matched; the file is organized by its watch folder and name.")
+			}
+			return nil
+		},
+	}
+	test.Flags().Int64Var(&sizeMB, "size-mb", 0, "file size to test size bounds with")
+	test.Flags().StringVar(&category, "category", "", "download client category to test category matchers with")
+
+	cmd.AddCommand(list, test)
+	return cmd
+}
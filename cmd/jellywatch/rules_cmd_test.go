@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Nomadcxx/jellywatch/internal/config"
+)
+
+func TestRulesTestExplainsMatch(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Libraries.Movies = []string{"/media/Movies", "/media/Kids Movies"}
+	cfg.Rules = []config.RuleConfig{
+		{Name: "samples", Path: "*.sample.*", Ignore: true},
+		{Name: "kids", Path: "*/Kids/*", Library: "/media/Kids Movies"},
+		{Name: "big", MinSizeMB: 1, Title: "Frozen"},
+	}
+	loadConfig := func() (*config.Config, error) { return cfg, nil }
+
+	var out bytes.Buffer
+	cmd := newRulesCmdWithDeps(loadConfig, &out)
+	cmd.SetArgs([]string{"test", "/downloads/movies/Kids/Frozen.2013.1080p.BluRay.x264-SPARKS.mkv", "--size-mb", "4000"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		`1. samples: no match (path does not match "*.sample.*")`,
+		`2. kids: MATCH -> library="/media/Kids Movies" media_type="movie"`,
+		`3. big: would match, but an earlier rule wins`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
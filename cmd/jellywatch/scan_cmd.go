@@ -120,6 +120,14 @@ func runScan(syncSonarr, syncRadarr, syncFilesystem, showStats bool, scanPath st
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	profiles, err := cfg.QualityProfiles()
+	if err != nil {
+		return err
+	}
+	filters, err := cfg.Watch.IngestFilters()
+	if err != nil {
+		return err
+	}
 
 	// Open database
 	dbPath := config.GetDatabasePath()
@@ -175,13 +183,17 @@ func runScan(syncSonarr, syncRadarr, syncFilesystem, showStats bool, scanPath st
 	}
 
 	syncService := sync.NewSyncService(sync.SyncConfig{
-		DB:             db,
-		Sonarr:         sonarrClient,
-		Radarr:         radarrClient,
-		AIHelper:       aiHelper,
-		TVLibraries:    cfg.Libraries.TV,
-		MovieLibraries: cfg.Libraries.Movies,
-		Logger:         logger,
+		DB:               db,
+		Sonarr:           sonarrClient,
+		Radarr:           radarrClient,
+		AIHelper:         aiHelper,
+		TVLibraries:      cfg.Libraries.TV,
+		MovieLibraries:   cfg.Libraries.Movies,
+		LibraryTemplates: cfg.Libraries.TemplatesByLibrary(),
+		Prober:           cfg.Probe.Prober(),
+		QualityProfiles:  profiles,
+		Filters:          filters,
+		Logger:           logger,
 	})
 
 	ctx := context.Background()
@@ -328,6 +340,7 @@ func runScan(syncSonarr, syncRadarr, syncFilesystem, showStats bool, scanPath st
 
 		if shouldRunPostScanAnalysis(showStats, analyze) {
 			svc := service.NewCleanupService(db)
+			svc.SetQualityProfiles(profiles)
 
 			dupAnalysis, err := svc.AnalyzeDuplicates()
 			hasDuplicates := err == nil && dupAnalysis.TotalGroups > 0
@@ -422,11 +435,23 @@ func runTargetedScan(ctx context.Context, db *database.MediaDB, cfg *config.Conf
 	if err != nil {
 		return err
 	}
+	profiles, err := cfg.QualityProfiles()
+	if err != nil {
+		return err
+	}
 
 	fileScanner := scanner.NewFileScanner(db)
 	if aiHelper != nil {
 		fileScanner = scanner.NewFileScannerWithAI(db, aiHelper)
 	}
+	fileScanner.SetLibraryTemplates(cfg.Libraries.TemplatesByLibrary())
+	fileScanner.SetProber(cfg.Probe.Prober())
+	fileScanner.SetQualityProfiles(profiles)
+	filters, err := cfg.Watch.IngestFilters()
+	if err != nil {
+		return err
+	}
+	fileScanner.SetFilters(filters)
 
 	if !jsonOutput {
 		fmt.Printf("Scanning %s path:\n  %s\nLibrary root:\n  %s\n\n", mediaType, path, libraryRoot)
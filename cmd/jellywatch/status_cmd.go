@@ -97,7 +97,11 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	printHousekeepingSummary(os.Stdout, db)
 	printDeploymentDrift(os.Stdout)
 
-	duplicateAnalysis, err := service.NewCleanupService(db).AnalyzeDuplicates()
+	dupService := service.NewCleanupService(db)
+	if profiles, err := cfg.QualityProfiles(); err == nil {
+		dupService.SetQualityProfiles(profiles)
+	}
+	duplicateAnalysis, err := dupService.AnalyzeDuplicates()
 	if err != nil {
 		return fmt.Errorf("failed to analyze duplicates: %w", err)
 	}
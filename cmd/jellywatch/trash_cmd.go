@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Nomadcxx/jellywatch/internal/config"
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/trash"
+	"github.com/spf13/cobra"
+)
+
+func newTrashCmd() *cobra.Command {
+	return newTrashCmdWithDeps(database.Open, config.Load, newUndoSyncer, os.Stdout)
+}
+
+func newTrashCmdWithDeps(openDB func() (*database.MediaDB, error), loadConfig func() (*config.Config, error), newSyncer func(*database.MediaDB) undoSyncer, stdout io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trash",
+		Short: "List, restore or empty the recycle bin",
+		Long: `With [trash] enabled, files jellywatch deletes are moved into a trash
+directory on their own volume instead. They stay restorable until the
+daemon purges them by age or size quota, or the trash is emptied.`,
+	}
+
+	// openBin opens the database and a bin configured like the daemon's.
+	// Listing, restoring and emptying work even with the bin disabled, so
+	// items kept before it was turned off are still reachable.
+	openBin := func() (*database.MediaDB, *trash.Bin, error) {
+		db, err := openDB()
+		if err != nil {
+			return nil, nil, fmt.Errorf("open database: %w", err)
+		}
+		var cfg config.TrashConfig
+		if loadConfig != nil {
+			if c, err := loadConfig(); err == nil {
+				cfg = c.Trash
+			}
+		}
+		return db, trash.New(db, cfg), nil
+	}
+
+	var jsonOutput bool
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "Show what the trash holds, oldest first",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, bin, err := openBin()
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			items, err := bin.List()
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				enc := json.NewEncoder(stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(items)
+			}
+			if !bin.Enabled() {
+				fmt.Fprintln(stdout, "The recycle bin is disabled; deletes are permanent. Enable it under [trash] in the config.")
+			}
+			if len(items) == 0 {
+				fmt.Fprintln(stdout, "trash is empty")
+				return nil
+			}
+			var total int64
+			for _, item := range items {
+				printTrashItem(stdout, item)
+				total += item.Size
+			}
+			fmt.Fprintf(stdout, "%d items, %s\n", len(items), formatBytes(total))
+			return nil
+		},
+	}
+	list.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+
+	restore := &cobra.Command{
+		Use:   "restore <id>",
+		Short: "Move an item back to where it was deleted from",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid id %q", args[0])
+			}
+			db, bin, err := openBin()
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			var syncer undoSyncer
+			if newSyncer != nil {
+				syncer = newSyncer(db)
+			}
+			if syncer != nil {
+				bin.SetSyncer(syncer)
+			}
+			item, err := bin.Restore(id)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(stdout, "Restored #%d: %s\n", item.ID, item.OriginalPath)
+			if syncer != nil {
+				ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+				defer cancel()
+				if serr := syncer.SyncDirty(ctx); serr != nil {
+					fmt.Fprintf(stdout, "Warning: Sonarr/Radarr path sync failed, the daemon will retry: %v\n", serr)
+				}
+			}
+			return nil
+		},
+	}
+
+	var yes bool
+	empty := &cobra.Command{
+		Use:   "empty",
+		Short: "Delete everything in the trash for good",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !yes {
+				return fmt.Errorf("emptying the trash cannot be undone; pass --yes to confirm")
+			}
+			db, bin, err := openBin()
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			res, err := bin.Empty()
+			fmt.Fprintf(stdout, "Purged %d items, %s\n", res.Items, formatBytes(res.Bytes))
+			return err
+		},
+	}
+	empty.Flags().BoolVar(&yes, "yes", false, "confirm permanent deletion")
+
+	cmd.AddCommand(list, restore, empty)
+	return cmd
+}
+
+func printTrashItem(w io.Writer, item database.TrashItem) {
+	fmt.Fprintf(w, "#%-6d %s  %9s  %s\n",
+		item.ID, item.DeletedAt.Local().Format("2006-01-02 15:04:05"), formatBytes(item.Size), item.OriginalPath)
+	if item.Reason != "" {
+		fmt.Fprintf(w, "        why:  %s\n", item.Reason)
+	}
+	fmt.Fprintf(w, "        kept: %s\n", item.TrashPath)
+}
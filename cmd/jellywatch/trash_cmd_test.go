@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Nomadcxx/jellywatch/internal/config"
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/trash"
+)
+
+func TestTrashCmdListRestoreEmpty(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "test.db")
+	openDB := func() (*database.MediaDB, error) { return database.OpenPath(dbPath) }
+	trashCfg := config.TrashConfig{Enabled: true, Dirs: []string{filepath.Join(dir, "trash")}}
+	loadConfig := func() (*config.Config, error) { return &config.Config{Trash: trashCfg}, nil }
+
+	kept := filepath.Join(dir, "movies", "Heat (1995)", "Heat (1995).mkv")
+	gone := filepath.Join(dir, "movies", "Heat (1995)", "Heat (1995) - 720p.mkv")
+	db, err := openDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin := trash.New(db, trashCfg)
+	for _, p := range []string{kept, gone} {
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("video"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := bin.Remove(p, "duplicate"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	items, err := bin.List()
+	db.Close()
+	if err != nil || len(items) != 2 {
+		t.Fatalf("items = %v, err = %v", items, err)
+	}
+
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		cmd := newTrashCmdWithDeps(openDB, loadConfig, nil, &out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("list")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if !strings.Contains(out, kept) || !strings.Contains(out, "2 items") {
+		t.Fatalf("list output = %q", out)
+	}
+
+	if _, err := run("restore", "1"); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if _, err := os.Stat(items[0].OriginalPath); err != nil {
+		t.Fatalf("not restored: %v", err)
+	}
+
+	if _, err := run("empty"); err == nil {
+		t.Fatal("empty without --yes should refuse")
+	}
+	out, err = run("empty", "--yes")
+	if err != nil {
+		t.Fatalf("empty: %v", err)
+	}
+	if !strings.Contains(out, "Purged 1 items") {
+		t.Fatalf("empty output = %q", out)
+	}
+	if _, err := os.Stat(items[1].TrashPath); !os.IsNotExist(err) {
+		t.Fatalf("trash file still present: %v", err)
+	}
+}
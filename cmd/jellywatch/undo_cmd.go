@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Nomadcxx/jellywatch/internal/config"
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/jellyfin"
+	"github.com/Nomadcxx/jellywatch/internal/journal"
+	"github.com/Nomadcxx/jellywatch/internal/radarr"
+	"github.com/Nomadcxx/jellywatch/internal/sonarr"
+	"github.com/Nomadcxx/jellywatch/internal/sync"
+	"github.com/spf13/cobra"
+)
+
+func newUndoCmd() *cobra.Command {
+	return newUndoCmdWithDeps(database.Open, newUndoSyncer, os.Stdout)
+}
+
+// undoSyncer is what undo needs from the sync service: path updates
+// queued while undoing, then pushed before the command exits.
+type undoSyncer interface {
+	journal.Syncer
+	SyncDirty(ctx context.Context) error
+}
+
+func newUndoCmdWithDeps(openDB func() (*database.MediaDB, error), newSyncer func(*database.MediaDB) undoSyncer, stdout io.Writer) *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "undo <operation-id|batch-id>",
+		Short: "Reverse a journaled move, rename or batch",
+		Long: `Moves files back to where an operation found them, restores the database
+rows it changed and queues the old paths for Sonarr, Radarr and Jellyfin.
+A numeric argument undoes one operation; anything else undoes a whole
+batch, newest operation first. Deletions kept by the recycle bin come
+back out of the trash; other deletions are skipped: the file is gone.
+Find IDs with "jellywatch history".`,
+		Example: `  jellywatch undo 42
+  jellywatch undo organize-20260102T030405-9f3a1c --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openDB()
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			defer db.Close()
+
+			var syncer undoSyncer
+			if !dryRun && newSyncer != nil {
+				syncer = newSyncer(db)
+			}
+			undoer := journal.NewUndoer(db, syncer, dryRun)
+
+			var res *journal.Result
+			if id, perr := strconv.ParseInt(args[0], 10, 64); perr == nil {
+				res, err = undoer.UndoOperation(id)
+			} else {
+				res, err = undoer.UndoBatch(args[0])
+			}
+			if res != nil {
+				printUndoResult(stdout, res, dryRun)
+			}
+			if syncer != nil && res != nil && len(res.Undone) > 0 {
+				ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+				defer cancel()
+				if serr := syncer.SyncDirty(ctx); serr != nil {
+					fmt.Fprintf(stdout, "Warning: Sonarr/Radarr path sync failed, the daemon will retry: %v\n", serr)
+				}
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "check what would be undone without changing anything")
+
+	return cmd
+}
+
+func printUndoResult(w io.Writer, res *journal.Result, dryRun bool) {
+	verb := "Undone"
+	if dryRun {
+		verb = "Would undo"
+	}
+	for _, op := range res.Undone {
+		fmt.Fprintf(w, "%s #%d %s: %s -> %s\n", verb, op.ID, op.Kind, op.AfterPath, op.BeforePath)
+	}
+	for _, s := range res.Skipped {
+		fmt.Fprintf(w, "Skipped #%d %s %s: %s\n", s.Op.ID, s.Op.Kind, s.Op.BeforePath, s.Reason)
+	}
+	if !dryRun && len(res.Undone) > 0 {
+		fmt.Fprintf(w, "Undo batch: %s\n", res.Batch)
+	}
+}
+
+// newUndoSyncer builds a sync service from the config so undo can push
+// restored paths to Sonarr, Radarr and Jellyfin. Without a readable config
+// the dirty flags still carry the paths to the daemon's next sync.
+func newUndoSyncer(db *database.MediaDB) undoSyncer {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil
+	}
+	var sonarrClient *sonarr.Client
+	if cfg.Sonarr.Enabled {
+		sonarrClient = sonarr.NewClient(sonarr.Config{URL: cfg.Sonarr.URL, APIKey: cfg.Sonarr.APIKey})
+	}
+	var radarrClient *radarr.Client
+	if cfg.Radarr.Enabled {
+		radarrClient = radarr.NewClient(radarr.Config{URL: cfg.Radarr.URL, APIKey: cfg.Radarr.APIKey})
+	}
+	var jellyfinClient *jellyfin.Client
+	if cfg.Jellyfin.Enabled && cfg.Jellyfin.APIKey != "" && cfg.Jellyfin.URL != "" {
+		jellyfinClient = jellyfin.NewClient(jellyfin.Config{URL: cfg.Jellyfin.URL, APIKey: cfg.Jellyfin.APIKey, Timeout: 30 * time.Second})
+	}
+	mappings := make([]jellyfin.PathMapping, 0, len(cfg.Jellyfin.PathMappings))
+	for _, m := range cfg.Jellyfin.PathMappings {
+		mappings = append(mappings, jellyfin.PathMapping{Jellyfin: m.Jellyfin, Daemon: m.Daemon})
+	}
+	return sync.NewSyncService(sync.SyncConfig{
+		DB:             db,
+		Sonarr:         sonarrClient,
+		Radarr:         radarrClient,
+		Jellyfin:       jellyfinClient,
+		JellyfinPaths:  jellyfin.NewPathTranslator(mappings),
+		TVLibraries:    cfg.Libraries.TV,
+		MovieLibraries: cfg.Libraries.Movies,
+	})
+}
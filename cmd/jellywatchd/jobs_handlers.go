@@ -7,6 +7,7 @@ import (
 	"github.com/Nomadcxx/jellywatch/internal/daemon/ipc"
 	"github.com/Nomadcxx/jellywatch/internal/database"
 	"github.com/Nomadcxx/jellywatch/internal/housekeeping"
+	"github.com/Nomadcxx/jellywatch/internal/quality"
 	"github.com/Nomadcxx/jellywatch/internal/scheduler"
 	"github.com/Nomadcxx/jellywatch/internal/service"
 )
@@ -339,7 +340,7 @@ func taskVerifyHandler(eng *housekeeping.Engine) ipc.Handler {
 // taskGroupHandler returns the duplicate group attached to a flagged
 // task — used by the scheduler UI to render the per-file inspector
 // (path/size/resolution/quality_score) before approval.
-func taskGroupHandler(db *database.MediaDB) ipc.Handler {
+func taskGroupHandler(db *database.MediaDB, profiles *quality.ProfileSet) ipc.Handler {
 	return func(ctx context.Context, req ipc.Request, w ipc.FrameWriter) {
 		var args taskIDArgs
 		if err := json.Unmarshal(req.Args, &args); err != nil || args.ID == 0 {
@@ -361,8 +362,10 @@ func taskGroupHandler(db *database.MediaDB) ipc.Handler {
 			w.Error(req.ID, ipc.ErrBadRequest, "task has no duplicate group payload")
 			return
 		}
+		edition, _ := t.Payload["edition"].(string)
 		cs := service.NewCleanupService(db)
-		group, err := cs.FindDuplicateGroup(mediaType, title,
+		cs.SetQualityProfiles(profiles)
+		group, err := cs.FindDuplicateGroup(mediaType, title, edition,
 			payloadIntPtrLocal(t.Payload, "year"),
 			payloadIntPtrLocal(t.Payload, "season"),
 			payloadIntPtrLocal(t.Payload, "episode"))
@@ -392,6 +395,8 @@ func taskGroupHandler(db *database.MediaDB) ipc.Handler {
 			"group_id":          group.ID,
 			"media_type":        group.MediaType,
 			"title":             group.Title,
+			"edition":           group.Edition,
+			"quality_profile":   group.QualityProfile,
 			"year":              group.Year,
 			"season":            group.Season,
 			"episode":           group.Episode,
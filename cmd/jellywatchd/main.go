@@ -21,11 +21,13 @@ import (
 	daemonreload "github.com/Nomadcxx/jellywatch/internal/daemon/reload"
 	"github.com/Nomadcxx/jellywatch/internal/database"
 	"github.com/Nomadcxx/jellywatch/internal/housekeeping"
+	"github.com/Nomadcxx/jellywatch/internal/ingest"
 	"github.com/Nomadcxx/jellywatch/internal/jellyfin"
 	"github.com/Nomadcxx/jellywatch/internal/labeling"
 	"github.com/Nomadcxx/jellywatch/internal/logging"
 	"github.com/Nomadcxx/jellywatch/internal/notify"
 	"github.com/Nomadcxx/jellywatch/internal/paths"
+	"github.com/Nomadcxx/jellywatch/internal/probe"
 	"github.com/Nomadcxx/jellywatch/internal/radarr"
 	"github.com/Nomadcxx/jellywatch/internal/scanner"
 	"github.com/Nomadcxx/jellywatch/internal/scheduler"
@@ -33,6 +35,7 @@ import (
 	"github.com/Nomadcxx/jellywatch/internal/sonarr"
 	"github.com/Nomadcxx/jellywatch/internal/tmdb"
 	"github.com/Nomadcxx/jellywatch/internal/transfer"
+	"github.com/Nomadcxx/jellywatch/internal/trash"
 	"github.com/Nomadcxx/jellywatch/internal/video"
 	"github.com/Nomadcxx/jellywatch/internal/watcher"
 	"github.com/spf13/cobra"
@@ -55,7 +58,7 @@ It automatically organizes them according to Jellyfin naming conventions.`,
 	}
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file path")
-	rootCmd.PersistentFlags().StringVar(&backendName, "backend", "auto", "transfer backend: auto, pv, rsync, native")
+	rootCmd.PersistentFlags().StringVar(&backendName, "backend", "auto", "transfer backend: auto, pv, rsync, native, hardlink, reflink")
 	rootCmd.PersistentFlags().StringVar(&healthAddr, "health-addr", ":8686", "health check server address")
 
 	rootCmd.AddCommand(newInstallCmd())
@@ -219,6 +222,15 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	alerter, err := cfg.Alerter()
+	if err != nil {
+		return fmt.Errorf("invalid notifications: %w", err)
+	}
+	if alerter != nil {
+		notifyMgr.SetAlerter(alerter)
+		logger.Info("daemon", "Notification targets enabled", logging.F("targets", alerter.RouteCount()))
+	}
+
 	var playbackLocks *jellyfin.PlaybackLockManager
 	var deferredQueue *jellyfin.DeferredQueue
 	if cfg.Jellyfin.PlaybackSafety {
@@ -283,6 +295,56 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 			logging.F("count", len(pathMappings)))
 	}
 
+	libBackends, err := libraryBackends(cfg)
+	if err != nil {
+		return fmt.Errorf("invalid library settings: %w", err)
+	}
+
+	prober := cfg.Probe.Prober()
+	if prober != nil && !prober.Available() {
+		logger.Warn("daemon", "ffprobe not found, media inspection disabled",
+			logging.F("ffprobe_path", cfg.Probe.FFprobePath))
+		prober = nil
+	}
+	var integrityProber *probe.Prober
+	if cfg.Probe.RejectCorrupt {
+		integrityProber = prober
+	}
+
+	qualityProfiles, err := cfg.QualityProfiles()
+	if err != nil {
+		return fmt.Errorf("invalid quality profiles: %w", err)
+	}
+
+	ruleEngine, err := cfg.RuleEngine()
+	if err != nil {
+		return fmt.Errorf("invalid rules: %w", err)
+	}
+
+	// Deletes go to the recycle bin when [trash] is enabled; a disabled bin
+	// deletes outright, so it is passed everywhere unconditionally.
+	bin := trash.New(db, cfg.Trash)
+
+	// The watcher, periodic scanner and library scans share one view of
+	// what counts as media
+	ingestFilters, err := cfg.Watch.IngestFilters()
+	if err != nil {
+		return fmt.Errorf("invalid watch filters: %w", err)
+	}
+
+	extractor, err := cfg.Extract.Extractor()
+	if err != nil {
+		return fmt.Errorf("invalid extract settings: %w", err)
+	}
+	extrasLayout, err := cfg.Options.ExtrasLayout()
+	if err != nil {
+		return fmt.Errorf("invalid extras setting: %w", err)
+	}
+	downloadTracker, err := cfg.DownloadTracker()
+	if err != nil {
+		return fmt.Errorf("invalid download client settings: %w", err)
+	}
+
 	handler, err := daemon.NewMediaHandler(daemon.MediaHandlerConfig{
 		TVLibraries:                  cfg.Libraries.TV,
 		MovieLibs:                    cfg.Libraries.Movies,
@@ -292,6 +354,8 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 		DryRun:                       false, // Daemon always processes files automatically
 		Timeout:                      5 * time.Minute,
 		Backend:                      transfer.ParseBackend(backendName),
+		LibraryBackends:              libBackends,
+		AnimeLibraries:               animeLibraries(cfg),
 		NotifyManager:                notifyMgr,
 		Logger:                       logger,
 		TargetUID:                    targetUID,
@@ -302,6 +366,8 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 		JellyfinClient:               jellyfinClient,
 		PlaybackSafety:               cfg.Jellyfin.PlaybackSafety,
 		Database:                     db,
+		Trash:                        bin,
+		Rules:                        ruleEngine,
 		ConfigDir:                    configDir,
 		PlaybackLocks:                playbackLocks,
 		DeferredQueue:                deferredQueue,
@@ -310,6 +376,14 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 		AIMatcher:                    aiMatcher,
 		AIConfig:                     cfg.AI,
 		TransferConcurrencyPerVolume: cfg.Options.TransferConcurrencyPerVolume,
+		LibraryTemplates:             cfg.Libraries.TemplatesByLibrary(),
+		Prober:                       integrityProber,
+		Extractor:                    extractor,
+		KeepArchives:                 cfg.Extract.KeepSource,
+		Filters:                      ingestFilters,
+		ExtrasLayout:                 extrasLayout,
+		ResolutionVersions:           cfg.Options.ResolutionVersions,
+		Downloads:                    downloadTracker,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create media handler: %w", err)
@@ -337,11 +411,18 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 		Logger:      logger,
 		ActivityDir: filepath.Join(configDir, "activity"),
 		OrphanCheck: jellyfinClient,
+		Filters:     ingestFilters,
 	})
 
 	healthServer := daemon.NewServer(handler, periodicScanner, healthAddr, logger, cfg.Jellyfin.WebhookSecret)
-
-	w, err := watcher.NewWatcher(handler, false) // Daemon always processes files automatically
+	healthServer.RegisterMetrics(daemon.NewDatabaseCollector(db))
+
+	w, err := watcher.NewWatcher(handler, false, // Daemon always processes files automatically
+		watcher.WithSettleWindow(cfg.Watch.SettleWindow()),
+		watcher.WithBackend(cfg.Watch.Backend, cfg.Watch.PathBackends()),
+		watcher.WithPollInterval(cfg.Watch.PollInterval()),
+		watcher.WithFilters(ingestFilters),
+		watcher.WithArchives(extractor != nil))
 	if err != nil {
 		return fmt.Errorf("unable to create watcher: %w", err)
 	}
@@ -353,7 +434,7 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 
 	// Perform initial scan of existing files
 	logger.Info("daemon", "Performing initial scan of existing files")
-	if err := performInitialScan(handler, watchPaths, logger); err != nil {
+	if err := performInitialScan(handler, watchPaths, ingestFilters, logger); err != nil {
 		logger.Warn("daemon", "Initial scan completed with errors", logging.F("error", err.Error()))
 	} else {
 		logger.Info("daemon", "Initial scan completed successfully")
@@ -393,7 +474,7 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 
 	reloadSupervisor := daemonreload.NewSupervisor()
 	reloadSupervisor.Register(daemonreload.NewLoggingReloadable(logger))
-	reloadSupervisor.Register(daemonreload.NewScannerReloadable(w))
+	reloadSupervisor.Register(daemonreload.NewScannerReloadable(w, ingestFilters))
 	if aiMatcher != nil {
 		reloadSupervisor.Register(daemonreload.NewAIReloadable(aiMatcher))
 	}
@@ -402,6 +483,7 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 	if err := configureControlSocketAccess(controlServer); err != nil {
 		return fmt.Errorf("configure control socket access: %w", err)
 	}
+	handler.SetOpRegistry(controlServer.Registry())
 
 	// Op-registry + op-log are required by streaming commands. The server
 	// already allocates an OpRegistry; we open the on-disk op log and read
@@ -437,6 +519,10 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 	}))
 
 	fileScanner := scanner.NewFileScanner(db)
+	fileScanner.SetLibraryTemplates(cfg.Libraries.TemplatesByLibrary())
+	fileScanner.SetProber(prober)
+	fileScanner.SetQualityProfiles(qualityProfiles)
+	fileScanner.SetFilters(ingestFilters)
 	rescanDefaults := func() []string {
 		paths := append([]string{}, cfg.Libraries.TV...)
 		paths = append(paths, cfg.Libraries.Movies...)
@@ -458,8 +544,11 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 
 	controlServer.RegisterStreaming(daemonipc.CmdRescan, guardMutator(getPending, rescanHandler(fileScanner, rescanDefaults, opLog)))
 	controlServer.RegisterStreaming(daemonipc.CmdResetDB, guardMutator(getPending, resetDBHandler(db.SQL(), opLog)))
-	controlServer.RegisterStreaming(daemonipc.CmdConsolidate, guardMutator(getPending, consolidateHandler(db, opLog)))
-	controlServer.RegisterStreaming(daemonipc.CmdDupScan, dupScanHandler(service.NewCleanupService(db), opLog))
+	controlServer.RegisterStreaming(daemonipc.CmdConsolidate, guardMutator(getPending, consolidateHandler(db, bin, opLog)))
+	dupCleanup := service.NewCleanupService(db)
+	dupCleanup.SetQualityProfiles(qualityProfiles)
+	dupCleanup.SetTrash(bin)
+	controlServer.RegisterStreaming(daemonipc.CmdDupScan, dupScanHandler(dupCleanup, opLog))
 	controlServer.RegisterStreaming(daemonipc.CmdAIBatch, guardMutator(getPending, aiBatchHandler(handler, aiMatcher, opLog)))
 	controlServer.RegisterStreaming(daemonipc.CmdMetadataRefresh, guardMutator(getPending, metadataRefreshHandler(jellyfinClient, opLog)))
 	controlServer.RegisterStreaming(daemonipc.CmdMetadataReconcile, guardMutator(getPending, metadataReconcileHandler(metadataReconciler, opLog)))
@@ -626,8 +715,11 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 		hkCfg.TVLibraries = cfg.Libraries.TV
 		hkCfg.MovieLibraries = cfg.Libraries.Movies
 		hkCfg.WatchDirs = watchPaths
+		hkCfg.QualityProfiles = qualityProfiles
 		hkEngine := housekeeping.NewEngine(hkCfg, db, logger)
 		hkEngine.SetOpRegistry(controlServer.Registry())
+		hkEngine.SetAlerter(alerter)
+		hkEngine.SetTrash(bin)
 
 		// Wire optional verifier (Jellyfin RemoteSearch + TMDB direct).
 		// Either tier may be unavailable; the verifier degrades gracefully.
@@ -643,8 +735,8 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 				if err != nil {
 					return "", err
 				}
-				return fmt.Sprintf("enqueued=%d auto_dup=%d cross_volume=%d folder_rename=%d parser_drift=%d no_year=%d year_mismatch=%d verified_distinct=%d polluted=%d orphan=%d stuck_sync=%d",
-					res.Enqueued, res.AutoDupes, res.CrossVolumeDupes, res.FolderRenames, res.ParserDriftRenames, res.NoYearMerges, res.YearMismatches, res.VerifiedDistinct, res.PollutedNames, res.OrphanSources, res.StuckSyncs), nil
+				return fmt.Sprintf("enqueued=%d auto_dup=%d cross_volume=%d folder_rename=%d parser_drift=%d no_year=%d year_mismatch=%d verified_distinct=%d polluted=%d orphan=%d orphan_sidecar=%d stuck_sync=%d",
+					res.Enqueued, res.AutoDupes, res.CrossVolumeDupes, res.FolderRenames, res.ParserDriftRenames, res.NoYearMerges, res.YearMismatches, res.VerifiedDistinct, res.PollutedNames, res.OrphanSources, res.OrphanSidecars, res.StuckSyncs), nil
 			},
 		}); err != nil {
 			logger.Warn("daemon", "register housekeeping.detect failed", logging.F("error", err.Error()))
@@ -661,6 +753,21 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 		}); err != nil {
 			logger.Warn("daemon", "register housekeeping.drain failed", logging.F("error", err.Error()))
 		}
+		if bin.Enabled() {
+			if err := sched.Register(scheduler.Job{
+				Name:     "trash.purge",
+				Schedule: "@hourly",
+				Run: func(ctx context.Context) (string, error) {
+					res, err := bin.Purge()
+					if err != nil {
+						return "", err
+					}
+					return fmt.Sprintf("purged=%d bytes=%d", res.Items, res.Bytes), nil
+				},
+			}); err != nil {
+				logger.Warn("daemon", "register trash.purge failed", logging.F("error", err.Error()))
+			}
+		}
 		// Recovery: prior daemon may have died with rows still in 'running'
 		// state (in-memory flag, not persisted). Clear them so the queue
 		// can drain and the scheduler can re-fire continuous jobs.
@@ -691,7 +798,7 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 		controlServer.Register(daemonipc.CmdTasksBulk, tasksBulkHandler(db))
 		controlServer.Register(daemonipc.CmdTasksPurge, tasksPurgeHandler(db))
 		controlServer.Register(daemonipc.CmdTaskVerify, taskVerifyHandler(hkEngine))
-		controlServer.Register(daemonipc.CmdTaskGroup, taskGroupHandler(db))
+		controlServer.Register(daemonipc.CmdTaskGroup, taskGroupHandler(db, qualityProfiles))
 		controlServer.Register(daemonipc.CmdTaskApprove, taskApproveHandler(db))
 		logger.Info("daemon", "Scheduler + housekeeping engine started")
 	}
@@ -817,12 +924,44 @@ func runMetadataReconcileOnce(ctx context.Context, logger *logging.Logger, recon
 	}
 }
 
+// libraryBackends maps each configured library with a transfer_backend
+// override to its backend. Overrides are resolved through SettingsFor so a
+// trailing slash on either side still matches; an unknown backend name is an
+// error rather than a silent fallback to a moving backend.
+func libraryBackends(cfg *config.Config) (map[string]transfer.Backend, error) {
+	out := make(map[string]transfer.Backend)
+	libs := append(append([]string{}, cfg.Libraries.TV...), cfg.Libraries.Movies...)
+	for _, lib := range libs {
+		s, ok := cfg.Libraries.SettingsFor(lib)
+		if !ok || strings.TrimSpace(s.TransferBackend) == "" {
+			continue
+		}
+		backend, err := transfer.LookupBackend(strings.TrimSpace(s.TransferBackend))
+		if err != nil {
+			return nil, fmt.Errorf("library %s: %w", lib, err)
+		}
+		out[lib] = backend
+	}
+	return out, nil
+}
+
+// animeLibraries returns the TV libraries whose settings set anime = true.
+func animeLibraries(cfg *config.Config) []string {
+	var out []string
+	for _, lib := range cfg.Libraries.TV {
+		if s, ok := cfg.Libraries.SettingsFor(lib); ok && s.Anime {
+			out = append(out, lib)
+		}
+	}
+	return out
+}
+
 func isMediaFile(path string) bool {
 	return video.IsVideo(path)
 }
 
 // performInitialScan walks through all watch directories and processes any existing media files
-func performInitialScan(handler *daemon.MediaHandler, watchPaths []string, logger *logging.Logger) error {
+func performInitialScan(handler *daemon.MediaHandler, watchPaths []string, filters *ingest.Filters, logger *logging.Logger) error {
 	totalProcessed := 0
 	totalErrors := 0
 
@@ -838,7 +977,17 @@ func performInitialScan(handler *daemon.MediaHandler, watchPaths []string, logge
 				return nil // Continue scanning other directories
 			}
 
-			if !info.IsDir() && handler.IsMediaFile(path) {
+			if info.IsDir() {
+				if path != watchPath && filters.SkipDir(path) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if ok, _ := filters.Accept(path, info.Size(), ""); !ok {
+				return nil
+			}
+
+			if handler.IsMediaFile(path) {
 				logger.Info("daemon", "Processing existing file", logging.F("file", filepath.Base(path)))
 
 				// Create a file event for the existing file
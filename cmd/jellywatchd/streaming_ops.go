@@ -13,6 +13,7 @@ import (
 	"github.com/Nomadcxx/jellywatch/internal/database"
 	"github.com/Nomadcxx/jellywatch/internal/jellyfin"
 	"github.com/Nomadcxx/jellywatch/internal/service"
+	"github.com/Nomadcxx/jellywatch/internal/trash"
 )
 
 // streamRunner wires a phased internal operation to the IPC progress channel.
@@ -52,7 +53,7 @@ type consolidateArgs struct {
 	DryRun bool `json:"dry_run"`
 }
 
-func consolidateHandler(db *database.MediaDB, log *ipc.OpLog) ipc.StreamingHandler {
+func consolidateHandler(db *database.MediaDB, bin *trash.Bin, log *ipc.OpLog) ipc.StreamingHandler {
 	return func(ctx context.Context, raw json.RawMessage, w ipc.FrameWriter, op *ipc.Op) {
 		var args consolidateArgs
 		if len(raw) > 0 {
@@ -63,6 +64,7 @@ func consolidateHandler(db *database.MediaDB, log *ipc.OpLog) ipc.StreamingHandl
 			func(progress chan<- database.ProgressEvent) error {
 				progress <- database.ProgressEvent{Phase: "planning", Msg: "fetching pending plans"}
 				exec := consolidate.NewExecutor(db, args.DryRun, nil)
+				exec.SetTrash(bin)
 				planner := consolidate.NewPlanner(db)
 
 				plans, err := planner.GetPendingPlans()
@@ -18,6 +18,10 @@ const (
 	MethodAI         ParseMethod = "ai"
 	MethodCache      ParseMethod = "cache"
 	MethodSeasonPack ParseMethod = "season_pack"
+	MethodRule       ParseMethod = "rule"
+	MethodAlias      ParseMethod = "alias"
+	MethodSonarr     ParseMethod = "sonarr"
+	MethodRadarr     ParseMethod = "radarr"
 )
 
 type Entry struct {
@@ -9,12 +9,10 @@
 package ai
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -22,6 +20,7 @@ import (
 	"time"
 
 	"github.com/Nomadcxx/jellywatch/internal/config"
+	"github.com/Nomadcxx/jellywatch/internal/llm"
 )
 
 // Result represents the AI's parsed output
@@ -36,11 +35,30 @@ type Result struct {
 	Confidence      float64      `json:"confidence"`
 }
 
-// Matcher handles AI-based title matching
+// resultSchema constrains provider output to Result. Every property is
+// required and nullable so OpenAI's strict mode accepts it.
+var resultSchema = json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "title": {"type": "string"},
+    "year": {"type": ["integer", "null"]},
+    "type": {"type": "string", "enum": ["movie", "tv"]},
+    "season": {"type": ["integer", "null"]},
+    "episodes": {"type": ["array", "null"], "items": {"type": "integer"}},
+    "absolute_episode": {"type": ["integer", "null"]},
+    "air_date": {"type": ["string", "null"]},
+    "confidence": {"type": "number"}
+  },
+  "required": ["title", "year", "type", "season", "episodes", "absolute_episode", "air_date", "confidence"],
+  "additionalProperties": false
+}`)
+
+// Matcher handles AI-based title matching. Requests go through the
+// configured provider and its fallback chain.
 type Matcher struct {
 	mu           sync.RWMutex
 	config       config.AIConfig
-	client       *http.Client
+	providers    *llm.ProviderRegistry
 	systemPrompt string
 }
 
@@ -49,22 +67,40 @@ func NewMatcher(cfg config.AIConfig) (*Matcher, error) {
 	if err := validateConfig(cfg); err != nil {
 		return nil, err
 	}
+	providers, err := buildProviders(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	return &Matcher{
-		config: cfg,
-		client: &http.Client{
-			Timeout: time.Duration(cfg.TimeoutSeconds) * time.Second,
-		},
+		config:       cfg,
+		providers:    providers,
 		systemPrompt: getSystemPrompt(),
 	}, nil
 }
 
-func validateConfig(cfg config.AIConfig) error {
-	if cfg.Enabled && cfg.Model == "" {
-		return fmt.Errorf("AI enabled but no model specified")
+// buildProviders builds the provider registry. A disabled matcher gets an
+// empty registry rather than an error so it can still be constructed with
+// incomplete settings.
+func buildProviders(cfg config.AIConfig) (*llm.ProviderRegistry, error) {
+	providers, err := cfg.ProviderRegistry()
+	if err != nil {
+		if cfg.Enabled {
+			return nil, err
+		}
+		return llm.NewProviderRegistry(), nil
 	}
-	if cfg.Enabled && cfg.OllamaEndpoint == "" {
-		return fmt.Errorf("AI enabled but no Ollama endpoint specified")
+	return providers, nil
+}
+
+func validateConfig(cfg config.AIConfig) error {
+	if cfg.Enabled && cfg.PrimaryProvider() == config.OllamaProviderID {
+		if cfg.Model == "" {
+			return fmt.Errorf("AI enabled but no model specified")
+		}
+		if cfg.OllamaEndpoint == "" {
+			return fmt.Errorf("AI enabled but no Ollama endpoint specified")
+		}
 	}
 	if cfg.ConfidenceThreshold < 0 || cfg.ConfidenceThreshold > 1 {
 		return fmt.Errorf("confidence threshold must be between 0 and 1")
@@ -79,22 +115,23 @@ func (m *Matcher) Reconfigure(cfg config.AIConfig) error {
 	if err := validateConfig(cfg); err != nil {
 		return err
 	}
+	providers, err := buildProviders(cfg)
+	if err != nil {
+		return err
+	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.config = cfg
-	m.client = &http.Client{
-		Timeout: time.Duration(cfg.TimeoutSeconds) * time.Second,
-	}
+	m.providers = providers
 	return nil
 }
 
-// Parse sends a filename to Ollama and returns parsed metadata
+// Parse sends a filename to the configured provider and returns parsed metadata
 func (m *Matcher) Parse(ctx context.Context, filename string) (*Result, error) {
-	cfg := m.GetConfig()
-	return m.parseWithModel(ctx, filename, cfg.Model)
+	return m.parse(ctx, filename, m.Providers())
 }
 
-// ParseWithContext sends a filename with additional library context to Ollama
+// ParseWithContext sends a filename with additional library context to the configured provider
 func (m *Matcher) ParseWithContext(ctx context.Context, filename string, libraryType string, folderPath string, currentTitle string, currentConfidence float64) (*Result, error) {
 	m.mu.RLock()
 	contextPrompt := m.systemPrompt
@@ -120,15 +157,14 @@ func (m *Matcher) ParseWithContext(ctx context.Context, filename string, library
 		contextPrompt += fmt.Sprintf("- Current title: %s (confidence: %.2f)\n", currentTitle, currentConfidence)
 	}
 
-	cfg := m.GetConfig()
-	return m.parseWithModel(ctx, contextPrompt+"\n\nNow parse this filename: "+filename, cfg.Model)
+	return m.parse(ctx, contextPrompt+"\n\nNow parse this filename: "+filename, m.Providers())
 }
 
-// ParseWithRetry sends a filename to Ollama with one retry attempt on malformed JSON responses.
+// ParseWithRetry sends a filename to the configured provider with one retry attempt on malformed JSON responses.
 // Uses nudge prompt to guide the AI to correct JSON formatting on retry.
 func (m *Matcher) ParseWithRetry(ctx context.Context, filename string) (*Result, error) {
-	cfg := m.GetConfig()
-	result, err := m.parseWithModel(ctx, filename, cfg.Model)
+	providers := m.Providers()
+	result, err := m.parse(ctx, filename, providers)
 	if err == nil {
 		return result, nil
 	}
@@ -146,7 +182,7 @@ func (m *Matcher) ParseWithRetry(ctx context.Context, filename string) (*Result,
 	}
 
 	nudgePrompt := GetNudgePrompt()
-	retryResult, retryErr := m.parseWithModel(ctx, filename+" "+nudgePrompt, cfg.Model)
+	retryResult, retryErr := m.parse(ctx, filename+" "+nudgePrompt, providers)
 	if retryErr == nil {
 		if os.Getenv("DEBUG_AI") == "1" {
 			fmt.Printf("[AI] Retry with nudge prompt succeeded\n")
@@ -185,64 +221,38 @@ func (m *Matcher) ParseWithCloud(ctx context.Context, filename string) (*Result,
 	if cfg.CloudModel == "" {
 		return nil, fmt.Errorf("no cloud model configured")
 	}
-	return m.parseWithModel(ctx, filename, cfg.CloudModel)
+	cloud := llm.NewProviderRegistry()
+	cloud.Register(llm.NewOllamaAdapter("ollama-cloud", "Ollama (cloud model)", cfg.OllamaEndpoint, cfg.CloudModel))
+	return m.parse(ctx, filename, cloud)
 }
 
-// parseWithModel sends a filename to a specific model
-func (m *Matcher) parseWithModel(ctx context.Context, filename, model string) (*Result, error) {
+// parse sends a filename through the providers' chain and decodes the
+// structured result.
+func (m *Matcher) parse(ctx context.Context, filename string, providers *llm.ProviderRegistry) (*Result, error) {
 	m.mu.RLock()
 	cfg := m.config
-	client := m.client
 	systemPrompt := m.systemPrompt
 	m.mu.RUnlock()
 
-	// Construct full prompt
-	fullPrompt := systemPrompt + "\n" + filename
-
-	reqBody := GenerateRequest{
-		Model:  model,
-		Prompt: fullPrompt,
-		Stream: false,
-	}
-
-	reqJSON, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	// Create HTTP request with context
-	reqCtx, cancel := context.WithTimeout(ctx, time.Duration(cfg.TimeoutSeconds)*time.Second)
-	defer cancel()
-
-	req, err := http.NewRequestWithContext(reqCtx, "POST", cfg.OllamaEndpoint+"/api/generate", bytes.NewReader(reqJSON))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	// Send request
 	startTime := time.Now()
-	resp, err := client.Do(req)
+	completion, provider, err := providers.Complete(ctx, filename, llm.CompletionOptions{
+		System:     systemPrompt,
+		Schema:     resultSchema,
+		SchemaName: "media_filename",
+		Timeout:    time.Duration(cfg.TimeoutSeconds) * time.Second,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request to Ollama: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, &HTTPError{StatusCode: resp.StatusCode, Body: string(body)}
-	}
-
-	// Parse response
-	var genResp GenerateResponse
-	if err := json.NewDecoder(resp.Body).Decode(&genResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		var httpErr *llm.HTTPError
+		if errors.As(llm.LastError(err), &httpErr) {
+			return nil, &HTTPError{Provider: httpErr.Provider, StatusCode: httpErr.StatusCode, Body: httpErr.Body}
+		}
+		return nil, fmt.Errorf("failed to send request to AI provider: %w", err)
 	}
 
 	latency := time.Since(startTime)
 
 	// Strip markdown code blocks if present
-	responseText := strings.TrimSpace(genResp.Response)
+	responseText := strings.TrimSpace(completion.Text)
 	if strings.HasPrefix(responseText, "```json") {
 		responseText = strings.TrimPrefix(responseText, "```json")
 	} else if strings.HasPrefix(responseText, "```") {
@@ -256,7 +266,7 @@ func (m *Matcher) parseWithModel(ctx context.Context, filename, model string) (*
 	// Parse AI's JSON response
 	var result Result
 	if err := json.Unmarshal([]byte(responseText), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse AI response: %w (response: %s)", err, genResp.Response)
+		return nil, fmt.Errorf("failed to parse AI response: %w (response: %s)", err, completion.Text)
 	}
 
 	// Validate confidence bounds
@@ -280,34 +290,30 @@ func (m *Matcher) parseWithModel(ctx context.Context, filename, model string) (*
 
 	// Log latency for debugging
 	if os.Getenv("DEBUG_AI") == "1" {
-		fmt.Printf("[AI] Parsed '%s' in %v with %.2f confidence\n", filename, latency, result.Confidence)
+		fmt.Printf("[AI] %s parsed '%s' in %v with %.2f confidence\n", provider.ID(), filename, latency, result.Confidence)
 	}
 
 	return &result, nil
 }
 
-// IsAvailable checks if Ollama is running
+// IsAvailable checks if the primary provider is reachable
 func (m *Matcher) IsAvailable(ctx context.Context) bool {
-	m.mu.RLock()
-	endpoint := m.config.OllamaEndpoint
-	client := m.client
-	m.mu.RUnlock()
+	provider, ok := m.Providers().Default()
+	if !ok {
+		return false
+	}
 
 	reqCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(reqCtx, "GET", endpoint+"/api/tags", nil)
-	if err != nil {
-		return false
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return false
-	}
-	defer resp.Body.Close()
+	return provider.Ping(reqCtx) == nil
+}
 
-	return resp.StatusCode == http.StatusOK
+// Providers returns the registry requests are sent through
+func (m *Matcher) Providers() *llm.ProviderRegistry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.providers
 }
 
 // GetConfig returns the matcher's configuration
@@ -3,6 +3,7 @@ package ai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -751,3 +752,75 @@ func TestShouldSkipParentFolder_RealNames(t *testing.T) {
 		}
 	}
 }
+
+func TestMatcher_ParseThroughConfiguredProviderChain(t *testing.T) {
+	// Primary OpenAI-compatible server rejects the key; the Anthropic
+	// fallback answers through its forced schema tool.
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"invalid api key"}`, http.StatusUnauthorized)
+	}))
+	defer primary.Close()
+
+	var sawSchema bool
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Tools []struct {
+				InputSchema json.RawMessage `json:"input_schema"`
+			} `json:"tools"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		sawSchema = len(req.Tools) == 1 && len(req.Tools[0].InputSchema) > 0
+		w.Write([]byte(`{"content":[{"type":"tool_use","name":"media_filename","input":{"title":"Heat","year":1995,"type":"movie","season":null,"episodes":null,"absolute_episode":null,"air_date":null,"confidence":0.97}}]}`))
+	}))
+	defer fallback.Close()
+
+	cfg := config.AIConfig{
+		Enabled:             true,
+		ConfidenceThreshold: 0.8,
+		TimeoutSeconds:      30,
+		Provider:            "router",
+		Fallback:            []string{"claude"},
+		Providers: []config.AIProviderConfig{
+			{ID: "router", Type: "openai", Endpoint: primary.URL, APIKey: "bad", Model: "qwen"},
+			{ID: "claude", Type: "anthropic", Endpoint: fallback.URL, APIKey: "sk-ant", Model: "claude"},
+		},
+	}
+	matcher, err := NewMatcher(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create matcher: %v", err)
+	}
+
+	result, err := matcher.Parse(context.Background(), "Heat.1995.1080p.BluRay.mkv")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if result.Title != "Heat" || result.Year.Int() == nil || *result.Year.Int() != 1995 || !sawSchema {
+		t.Fatalf("result = %+v sawSchema = %v", result, sawSchema)
+	}
+
+	// Without the fallback the primary's 401 surfaces as a permanent error.
+	cfg.Fallback = nil
+	if err := matcher.Reconfigure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	_, err = matcher.Parse(context.Background(), "Heat.1995.1080p.BluRay.mkv")
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || !httpErr.IsPermanent() || httpErr.Provider != "router" {
+		t.Fatalf("err = %v, want permanent HTTPError from router", err)
+	}
+	// With a fallback that is down, the chain ends on the fallback's 503,
+	// which is retryable even though the primary's 401 is not.
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	cfg.Fallback = []string{"claude"}
+	cfg.Providers[1].Endpoint = down.URL
+	if err := matcher.Reconfigure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	_, err = matcher.Parse(context.Background(), "Heat.1995.1080p.BluRay.mkv")
+	if !errors.As(err, &httpErr) || httpErr.IsPermanent() || httpErr.Provider != "claude" {
+		t.Fatalf("err = %v, want transient HTTPError from claude", err)
+	}
+}
@@ -7,16 +7,21 @@ import (
 	"strings"
 )
 
-// HTTPError represents a non-2xx response from the Ollama API.
+// HTTPError represents a non-2xx response from an LLM provider's API.
 // Permanent (4xx auth/subscription) errors won't recover on retry; the caller
 // should blacklist immediately instead of burning retry attempts.
 type HTTPError struct {
+	Provider   string // provider ID; empty means ollama
 	StatusCode int
 	Body       string
 }
 
 func (e *HTTPError) Error() string {
-	return fmt.Sprintf("ollama returned %d: %s", e.StatusCode, e.Body)
+	provider := e.Provider
+	if provider == "" {
+		provider = "ollama"
+	}
+	return fmt.Sprintf("%s returned %d: %s", provider, e.StatusCode, e.Body)
 }
 
 // IsPermanent reports whether the error is unlikely to recover on retry.
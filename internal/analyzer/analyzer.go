@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/Nomadcxx/jellywatch/internal/naming"
@@ -30,6 +31,9 @@ type FolderAnalysis struct {
 	TotalSize     int64
 	MainMediaFile *FileInfo
 	MediaFiles    []FileInfo
+	// StackedFiles holds the parts of a multi-part movie (cd1/cd2,
+	// part1/part2) in part order; MainMediaFile is the first part.
+	StackedFiles  []FileInfo
 	RARFiles      []FileInfo
 	SampleFiles   []FileInfo
 	ExtraFiles    []FileInfo
@@ -119,7 +123,7 @@ func (a *FolderAnalysis) classifyFile(file FileInfo) {
 	case isVideoFile(name):
 		if isSampleFile(file) {
 			a.SampleFiles = append(a.SampleFiles, file)
-		} else if isExtraFile(name) {
+		} else if isExtraFile(file.Path) {
 			a.ExtraFiles = append(a.ExtraFiles, file)
 		} else {
 			a.MediaFiles = append(a.MediaFiles, file)
@@ -145,6 +149,40 @@ func (a *FolderAnalysis) findMainMediaFile() {
 		}
 	}
 	a.MainMediaFile = largest
+
+	if parts := a.findStack(largest); len(parts) > 1 {
+		a.StackedFiles = parts
+		a.MainMediaFile = &a.StackedFiles[0]
+	}
+}
+
+// findStack returns the parts of the stacked movie main belongs to, in
+// part order, or nil when main is not a stack part. Parts share their
+// name once the part marker is removed.
+func (a *FolderAnalysis) findStack(main *FileInfo) []FileInfo {
+	if naming.IsTVEpisodeFilename(main.Name) || naming.ParseStackPart(main.Name) == 0 {
+		return nil
+	}
+	base := naming.StripStackPart(main.Name)
+	byPart := make(map[int]FileInfo)
+	for _, f := range a.MediaFiles {
+		part := naming.ParseStackPart(f.Name)
+		if part == 0 || !strings.EqualFold(naming.StripStackPart(f.Name), base) {
+			continue
+		}
+		if prev, ok := byPart[part]; !ok || f.Size > prev.Size {
+			byPart[part] = f
+		}
+	}
+
+	parts := make([]FileInfo, 0, len(byPart))
+	for _, f := range byPart {
+		parts = append(parts, f)
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		return naming.ParseStackPart(parts[i].Name) < naming.ParseStackPart(parts[j].Name)
+	})
+	return parts
 }
 
 func (a *FolderAnalysis) detectMediaType() {
@@ -235,6 +273,9 @@ func (a *FolderAnalysis) String() string {
 		sb.WriteString(fmt.Sprintf("  Main File: %s\n", a.MainMediaFile.Name))
 	}
 	sb.WriteString(fmt.Sprintf("  Media Files: %d\n", len(a.MediaFiles)))
+	if len(a.StackedFiles) > 0 {
+		sb.WriteString(fmt.Sprintf("  Stacked Parts: %d\n", len(a.StackedFiles)))
+	}
 	sb.WriteString(fmt.Sprintf("  Sample Files: %d\n", len(a.SampleFiles)))
 	sb.WriteString(fmt.Sprintf("  RAR Files: %d\n", len(a.RARFiles)))
 	sb.WriteString(fmt.Sprintf("  Junk Files: %d\n", len(a.JunkFiles)))
@@ -322,8 +363,8 @@ func isSampleFile(file FileInfo) bool {
 	return samplePatterns.MatchString(file.Name)
 }
 
-var extraPatterns = regexp.MustCompile(`(?i)(^|[.\-_])(trailer|teaser|featurette|behind.?the.?scenes|deleted.?scene|extra|bonus|interview|making.?of)([.\-_]|$)`)
-
-func isExtraFile(name string) bool {
-	return extraPatterns.MatchString(name)
+// isExtraFile reports whether a video is a trailer, featurette or other
+// bonus feature rather than the release itself.
+func isExtraFile(path string) bool {
+	return naming.IsExtra(path)
 }
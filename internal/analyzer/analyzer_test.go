@@ -440,6 +440,68 @@ func TestAnalyzeSingleFile(t *testing.T) {
 	}
 }
 
+func TestAnalyzeFolderStackedMovie(t *testing.T) {
+	movieDir := filepath.Join(t.TempDir(), "Heat.1995.DVDRip.x264")
+	if err := os.MkdirAll(movieDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Part 2 is the larger file, but part 1 leads the stack.
+	for name, size := range map[string]int64{
+		"Heat.1995.DVDRip.x264.cd1.avi":    700 * 1024 * 1024,
+		"Heat.1995.DVDRip.x264.cd2.avi":    710 * 1024 * 1024,
+		"Heat.1995.DVDRip.x264-sample.avi": 20 * 1024 * 1024,
+	} {
+		if err := createFileWithSize(filepath.Join(movieDir, name), size); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysis, err := AnalyzeFolder(movieDir)
+	if err != nil {
+		t.Fatalf("AnalyzeFolder() error = %v", err)
+	}
+
+	if analysis.MediaType != MediaTypeMovie {
+		t.Errorf("MediaType = %v, want Movie", analysis.MediaType)
+	}
+	if len(analysis.StackedFiles) != 2 {
+		t.Fatalf("StackedFiles count = %d, want 2", len(analysis.StackedFiles))
+	}
+	if analysis.StackedFiles[0].Name != "Heat.1995.DVDRip.x264.cd1.avi" || analysis.StackedFiles[1].Name != "Heat.1995.DVDRip.x264.cd2.avi" {
+		t.Errorf("StackedFiles = %v, want cd1 then cd2", analysis.StackedFiles)
+	}
+	if analysis.MainMediaFile == nil || analysis.MainMediaFile.Name != "Heat.1995.DVDRip.x264.cd1.avi" {
+		t.Errorf("MainMediaFile = %v, want the first part", analysis.MainMediaFile)
+	}
+	for _, path := range analysis.GetCleanupFiles() {
+		for _, part := range analysis.StackedFiles {
+			if path == part.Path {
+				t.Errorf("GetCleanupFiles() includes stacked part %s", part.Name)
+			}
+		}
+	}
+}
+
+func TestAnalyzeFolderSingleMovieNotStacked(t *testing.T) {
+	movieDir := filepath.Join(t.TempDir(), "Harry.Potter.and.the.Deathly.Hallows.Part.1.2010.1080p")
+	if err := os.MkdirAll(movieDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	movieFile := filepath.Join(movieDir, "Harry.Potter.and.the.Deathly.Hallows.Part.1.2010.1080p.mkv")
+	if err := createFileWithSize(movieFile, 800*1024*1024); err != nil {
+		t.Fatal(err)
+	}
+
+	analysis, err := AnalyzeFolder(movieDir)
+	if err != nil {
+		t.Fatalf("AnalyzeFolder() error = %v", err)
+	}
+	if len(analysis.StackedFiles) != 0 {
+		t.Errorf("StackedFiles count = %d, want 0", len(analysis.StackedFiles))
+	}
+}
+
 // Helper function to create a file with specific size (sparse file)
 func createFileWithSize(path string, size int64) error {
 	f, err := os.Create(path)
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/go-chi/chi/v5"
+)
+
+// AliasHandlers manages the manual title overrides from the web UI.
+type AliasHandlers struct {
+	DB *database.MediaDB
+}
+
+// List returns every alias in the order they were added.
+func (h *AliasHandlers) List(w http.ResponseWriter, r *http.Request) {
+	aliases, err := h.DB.ListAliases()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "alias_list_failed", err.Error())
+		return
+	}
+	if aliases == nil {
+		aliases = []database.Alias{}
+	}
+	writeJSON(w, http.StatusOK, aliases)
+}
+
+// Upsert adds an alias, or replaces the one with the same kind, pattern
+// and media type.
+func (h *AliasHandlers) Upsert(w http.ResponseWriter, r *http.Request) {
+	var alias database.Alias
+	if err := json.NewDecoder(r.Body).Decode(&alias); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body", err.Error())
+		return
+	}
+	alias.ID = 0
+	if alias.Kind == "" {
+		alias.Kind = database.AliasKindTitle
+	}
+	if err := h.DB.UpsertAlias(&alias); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_alias", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, alias)
+}
+
+// Delete removes one alias.
+func (h *AliasHandlers) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid_id", "id must be a positive integer")
+		return
+	}
+	ok, err := h.DB.DeleteAlias(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "alias_delete_failed", err.Error())
+		return
+	}
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "no such alias")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/go-chi/chi/v5"
+)
+
+func TestAliasUpsertListDelete(t *testing.T) {
+	db, err := database.OpenPath(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	h := &AliasHandlers{DB: db}
+
+	w := httptest.NewRecorder()
+	h.Upsert(w, httptest.NewRequest("POST", "/aliases", strings.NewReader(
+		`{"pattern": "The Office", "media_type": "series", "title": "The Office (US)", "year": 2005}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("upsert status %d: %s", w.Code, w.Body.String())
+	}
+	var created database.Alias
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.ID == 0 || created.Kind != database.AliasKindTitle {
+		t.Fatalf("created = %+v", created)
+	}
+
+	w = httptest.NewRecorder()
+	h.Upsert(w, httptest.NewRequest("POST", "/aliases", strings.NewReader(
+		`{"pattern": "x", "media_type": "music", "title": "X"}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("bad media type: status %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	h.List(w, httptest.NewRequest("GET", "/aliases", nil))
+	var list []database.Alias
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list) != 1 {
+		t.Fatalf("list = %s, %v", w.Body.String(), err)
+	}
+
+	del := func(id string) int {
+		req := httptest.NewRequest("DELETE", "/aliases/"+id, nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", id)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		h.Delete(w, req)
+		return w.Code
+	}
+	id := strconv.FormatInt(created.ID, 10)
+	if code := del(id); code != http.StatusNoContent {
+		t.Errorf("delete status %d", code)
+	}
+	if code := del(id); code != http.StatusNotFound {
+		t.Errorf("second delete status %d", code)
+	}
+}
@@ -0,0 +1,193 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Nomadcxx/jellywatch/internal/activity"
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/radarr"
+	"github.com/Nomadcxx/jellywatch/internal/sonarr"
+)
+
+// HandleSonarrWebhook records the series and episodes Sonarr grabbed or
+// imported a download as, so the daemon organizes its files under that
+// identity instead of parsing their names.
+func (s *Server) HandleSonarrWebhook(w http.ResponseWriter, r *http.Request) {
+	if !s.validateArrWebhookSecret(r, database.ArrSourceSonarr) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+
+	var event sonarr.WebhookEvent
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	switch event.EventType {
+	case sonarr.EventGrab, sonarr.EventDownload:
+		s.recordArrGrab(sonarrGrab(event))
+	default:
+		// Test and other events are accepted so Sonarr does not retry.
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// HandleRadarrWebhook is HandleSonarrWebhook for Radarr's movies.
+func (s *Server) HandleRadarrWebhook(w http.ResponseWriter, r *http.Request) {
+	if !s.validateArrWebhookSecret(r, database.ArrSourceRadarr) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+
+	var event radarr.WebhookEvent
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	switch event.EventType {
+	case radarr.EventGrab, radarr.EventDownload:
+		s.recordArrGrab(radarrGrab(event))
+	default:
+		// Test and other events are accepted so Radarr does not retry.
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// validateArrWebhookSecret checks the secret configured for source
+// against the X-Jellywatch-Webhook-Secret header or, since the *arr
+// webhook connection only offers a username and password, the basic auth
+// password. Without a secret the endpoint is closed.
+func (s *Server) validateArrWebhookSecret(r *http.Request, source string) bool {
+	if s == nil || s.cfg == nil {
+		return false
+	}
+	expected := s.cfg.Radarr.WebhookSecret
+	if source == database.ArrSourceSonarr {
+		expected = s.cfg.Sonarr.WebhookSecret
+	}
+	expected = strings.TrimSpace(expected)
+	if expected == "" {
+		return false
+	}
+
+	provided := strings.TrimSpace(r.Header.Get("X-Jellywatch-Webhook-Secret"))
+	if provided == "" {
+		_, provided, _ = r.BasicAuth()
+	}
+	if provided == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(expected)) == 1
+}
+
+// sonarrGrab is the download identity a Sonarr event carries. An import
+// names the file it took and its episodes; a grab names the release. An
+// import that does not say which file it took covers the whole download,
+// so it records the series but not its episodes.
+func sonarrGrab(event sonarr.WebhookEvent) *database.ArrGrab {
+	g := &database.ArrGrab{
+		DownloadID:   event.DownloadID,
+		Source:       database.ArrSourceSonarr,
+		EventType:    event.EventType,
+		ReleaseTitle: event.Release.ReleaseTitle,
+		ArrID:        event.Series.ID,
+		Title:        event.Series.Title,
+		Year:         positiveInt(event.Series.Year),
+		TvdbID:       positiveInt(event.Series.TvdbID),
+		TmdbID:       positiveInt(event.Series.TmdbID),
+		ImdbID:       event.Series.ImdbID,
+	}
+	if f := event.EpisodeFile; f != nil {
+		g.SourcePath = f.SourcePath
+		if g.ReleaseTitle == "" {
+			g.ReleaseTitle = f.SceneName
+		}
+	}
+	if event.EventType == sonarr.EventDownload && g.SourcePath == "" {
+		return g
+	}
+	for i, ep := range event.Episodes {
+		if i > 0 && ep.SeasonNumber != *g.Season {
+			// Episodes from several seasons: leave the numbers to the
+			// file names.
+			g.Season, g.Episodes = nil, nil
+			break
+		}
+		season := ep.SeasonNumber
+		g.Season = &season
+		g.Episodes = append(g.Episodes, ep.EpisodeNumber)
+	}
+	return g
+}
+
+// radarrGrab is the download identity a Radarr event carries.
+func radarrGrab(event radarr.WebhookEvent) *database.ArrGrab {
+	g := &database.ArrGrab{
+		DownloadID:   event.DownloadID,
+		Source:       database.ArrSourceRadarr,
+		EventType:    event.EventType,
+		ReleaseTitle: event.Release.ReleaseTitle,
+		ArrID:        event.Movie.ID,
+		Title:        event.Movie.Title,
+		Year:         positiveInt(event.Movie.Year),
+		TmdbID:       positiveInt(event.Movie.TmdbID),
+		ImdbID:       event.Movie.ImdbID,
+	}
+	if f := event.MovieFile; f != nil {
+		g.SourcePath = f.SourcePath
+		if g.ReleaseTitle == "" {
+			g.ReleaseTitle = f.SceneName
+		}
+	}
+	return g
+}
+
+// recordArrGrab stores g. Events without a download ID, such as manual
+// imports, have nothing to match files against and are only logged.
+func (s *Server) recordArrGrab(g *database.ArrGrab) {
+	action := g.Source + "_" + strings.ToLower(g.EventType)
+	if s.db == nil {
+		return
+	}
+	if err := s.db.UpsertArrGrab(g); err != nil {
+		s.logArrActivity(action, g, false, err.Error())
+		return
+	}
+	s.logArrActivity(action, g, true, "")
+}
+
+func (s *Server) logArrActivity(action string, g *database.ArrGrab, success bool, errMsg string) {
+	if s.activityLogger == nil {
+		return
+	}
+	source := g.ReleaseTitle
+	if g.SourcePath != "" {
+		source = g.SourcePath
+	}
+	_ = s.activityLogger.Log(activity.Entry{
+		Action:      action,
+		Source:      source,
+		Target:      g.DownloadID,
+		MediaType:   g.Source,
+		ParsedTitle: g.Title,
+		ParsedYear:  g.Year,
+		Success:     success,
+		Error:       errMsg,
+	})
+}
+
+func positiveInt(n int) *int {
+	if n <= 0 {
+		return nil
+	}
+	return &n
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/Nomadcxx/jellywatch/internal/config"
+	"github.com/Nomadcxx/jellywatch/internal/database"
+)
+
+func newArrWebhookServer(t *testing.T) *Server {
+	t.Helper()
+	db, err := database.OpenPath(filepath.Join(t.TempDir(), "media.db"))
+	if err != nil {
+		t.Fatalf("OpenPath() failed: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return &Server{
+		cfg: &config.Config{
+			Sonarr: config.SonarrConfig{WebhookSecret: "sonarr-secret"},
+			Radarr: config.RadarrConfig{WebhookSecret: "radarr-secret"},
+		},
+		db: db,
+	}
+}
+
+func TestSonarrWebhookRecordsGrab(t *testing.T) {
+	s := newArrWebhookServer(t)
+	payload := []byte(`{
+		"eventType": "Grab",
+		"series": {"id": 7, "title": "Shōgun", "year": 2024, "tvdbId": 392573, "imdbId": "tt2788316"},
+		"episodes": [{"id": 70, "seasonNumber": 1, "episodeNumber": 3, "title": "Tomorrow Is Tomorrow"}],
+		"release": {"releaseTitle": "Shogun.2024.S01E03.1080p.WEB.h264-GRP", "indexer": "x"},
+		"downloadClient": "qBittorrent",
+		"downloadId": "0A1B2C3D"
+	}`)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/sonarr", bytes.NewReader(payload))
+	req.SetBasicAuth("sonarr", "sonarr-secret")
+	w := httptest.NewRecorder()
+	s.HandleSonarrWebhook(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	g, err := s.db.MatchArrGrab("/downloads/Shogun.2024.S01E03.1080p.WEB.h264-GRP/shogun.103.mkv")
+	if err != nil || g == nil {
+		t.Fatalf("MatchArrGrab() = %v, %v; want the grab", g, err)
+	}
+	if g.DownloadID != "0a1b2c3d" || g.Title != "Shōgun" || g.TvdbID == nil || *g.TvdbID != 392573 {
+		t.Errorf("grab = %+v", g)
+	}
+	if !g.HasEpisode() || *g.Season != 1 || g.Episodes[0] != 3 {
+		t.Errorf("grab episodes = %v %v, want S01E03", g.Season, g.Episodes)
+	}
+}
+
+func TestRadarrWebhookRecordsImportedFile(t *testing.T) {
+	s := newArrWebhookServer(t)
+	payload := []byte(`{
+		"eventType": "Download",
+		"movie": {"id": 3, "title": "Heat", "year": 1995, "tmdbId": 949, "imdbId": "tt0113277"},
+		"movieFile": {"relativePath": "Heat (1995).mkv", "sceneName": "Heat.1995.1080p.BluRay-GRP", "sourcePath": "/downloads/Heat.1995.1080p.BluRay-GRP/heat.mkv"},
+		"downloadId": "SABnzbd_nzo_abc"
+	}`)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/radarr", bytes.NewReader(payload))
+	req.Header.Set("X-Jellywatch-Webhook-Secret", "radarr-secret")
+	w := httptest.NewRecorder()
+	s.HandleRadarrWebhook(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	g, err := s.db.MatchArrGrab("/downloads/Heat.1995.1080p.BluRay-GRP/heat.mkv")
+	if err != nil || g == nil {
+		t.Fatalf("MatchArrGrab() = %v, %v; want the import", g, err)
+	}
+	if g.Source != database.ArrSourceRadarr || g.Title != "Heat" || g.TmdbID == nil || *g.TmdbID != 949 {
+		t.Errorf("import = %+v", g)
+	}
+}
+
+func TestArrWebhooksRejectWrongSecret(t *testing.T) {
+	s := newArrWebhookServer(t)
+	payload := []byte(`{"eventType":"Test"}`)
+
+	for _, tt := range []struct {
+		name    string
+		handler http.HandlerFunc
+		secret  string
+	}{
+		{"sonarr with radarr's secret", s.HandleSonarrWebhook, "radarr-secret"},
+		{"radarr without a secret", s.HandleRadarrWebhook, ""},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/arr", bytes.NewReader(payload))
+		if tt.secret != "" {
+			req.SetBasicAuth("arr", tt.secret)
+		}
+		w := httptest.NewRecorder()
+		tt.handler(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401, got %d", tt.name, w.Code)
+		}
+	}
+
+	// No secret configured closes the endpoint.
+	s.cfg.Sonarr.WebhookSecret = ""
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/sonarr", bytes.NewReader(payload))
+	w := httptest.NewRecorder()
+	s.HandleSonarrWebhook(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("unconfigured: expected 401, got %d", w.Code)
+	}
+}
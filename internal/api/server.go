@@ -16,6 +16,7 @@ import (
 	"github.com/Nomadcxx/jellywatch/internal/jellyweb/daemonctl"
 	"github.com/Nomadcxx/jellywatch/internal/paths"
 	"github.com/Nomadcxx/jellywatch/internal/service"
+	"github.com/Nomadcxx/jellywatch/internal/trash"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
@@ -36,6 +37,7 @@ type Server struct {
 	pathTranslator   *jellyfin.PathTranslator
 	ipc              IPCCaller
 	launcher         *daemonctl.Launcher
+	trash            *trash.Bin
 }
 
 // NewServer creates a new API server
@@ -63,11 +65,16 @@ func NewServer(db *database.MediaDB, cfg *config.Config) *Server {
 		deferredQueue:  jellyfin.NewDeferredQueue(),
 	}
 	if cfg != nil {
+		if profiles, err := cfg.QualityProfiles(); err == nil {
+			s.service.SetQualityProfiles(profiles)
+		}
 		mappings := make([]jellyfin.PathMapping, 0, len(cfg.Jellyfin.PathMappings))
 		for _, m := range cfg.Jellyfin.PathMappings {
 			mappings = append(mappings, jellyfin.PathMapping{Jellyfin: m.Jellyfin, Daemon: m.Daemon})
 		}
 		s.pathTranslator = jellyfin.NewPathTranslator(mappings)
+		s.trash = trash.New(db, cfg.Trash)
+		s.service.SetTrash(s.trash)
 	}
 	if configDir, err := paths.JellyWatchDir(); err == nil {
 		s.ipc = ipc.NewClient(filepath.Join(configDir, "control.sock"))
@@ -145,6 +152,8 @@ func (s *Server) apiRouter() *chi.Mux {
 
 	// Webhooks are intentionally mounted outside generated OpenAPI handlers.
 	r.Post("/webhooks/jellyfin", s.HandleJellyfinWebhook)
+	r.Post("/webhooks/sonarr", s.HandleSonarrWebhook)
+	r.Post("/webhooks/radarr", s.HandleRadarrWebhook)
 	r.Post("/paths/preflight", PreflightHandler{}.ServeHTTP)
 	testH := &TestHandlers{Cfg: s.cfg}
 	r.Post("/settings/sonarr/test", testH.Sonarr)
@@ -155,6 +164,20 @@ func (s *Server) apiRouter() *chi.Mux {
 	r.Get("/ai/models", s.ListAIModels)
 	r.Put("/ai/settings", s.UpdateAISettings)
 
+	trashH := &TrashHandlers{Bin: s.trash}
+	r.Route("/trash", func(r chi.Router) {
+		r.Get("/", trashH.List)
+		r.Delete("/", trashH.Empty)
+		r.Post("/{id}/restore", trashH.Restore)
+	})
+
+	aliasH := &AliasHandlers{DB: s.db}
+	r.Route("/aliases", func(r chi.Router) {
+		r.Get("/", aliasH.List)
+		r.Post("/", aliasH.Upsert)
+		r.Delete("/{id}", aliasH.Delete)
+	})
+
 	if s.ipc != nil {
 		daemonH := &DaemonHandlers{IPC: s.ipc, Launcher: s.launcher}
 		r.Route("/daemon", func(r chi.Router) {
@@ -282,6 +305,8 @@ func (s *Server) authMiddleware(next http.Handler) http.Handler {
 			"/auth/status",
 			"/health",
 			"/webhooks/jellyfin",
+			"/webhooks/sonarr",
+			"/webhooks/radarr",
 		}
 
 		path := r.URL.Path
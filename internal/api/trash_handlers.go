@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/trash"
+	"github.com/go-chi/chi/v5"
+)
+
+// TrashHandlers exposes the recycle bin to the web UI.
+type TrashHandlers struct {
+	Bin *trash.Bin
+}
+
+// TrashListing is the JSON shape returned by GET /trash.
+type TrashListing struct {
+	Enabled bool                 `json:"enabled"`
+	Items   []database.TrashItem `json:"items"`
+	Bytes   int64                `json:"bytes"`
+}
+
+// List returns what the trash holds, oldest first.
+func (h *TrashHandlers) List(w http.ResponseWriter, r *http.Request) {
+	items, err := h.Bin.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "trash_list_failed", err.Error())
+		return
+	}
+	resp := TrashListing{Enabled: h.Bin.Enabled(), Items: items}
+	if resp.Items == nil {
+		resp.Items = []database.TrashItem{}
+	}
+	for _, item := range items {
+		resp.Bytes += item.Size
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// Restore moves one item back to where it was deleted from.
+func (h *TrashHandlers) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid_id", "id must be a positive integer")
+		return
+	}
+	item, err := h.Bin.Restore(id)
+	if err != nil {
+		writeError(w, http.StatusConflict, "trash_restore_failed", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, item)
+}
+
+// Empty deletes everything in the trash for good.
+func (h *TrashHandlers) Empty(w http.ResponseWriter, r *http.Request) {
+	res, err := h.Bin.Empty()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "trash_empty_failed", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int64{"items": int64(res.Items), "bytes": res.Bytes})
+}
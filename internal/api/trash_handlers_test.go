@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/Nomadcxx/jellywatch/internal/config"
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/trash"
+	"github.com/go-chi/chi/v5"
+)
+
+func TestTrashListAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	db, err := database.OpenPath(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	bin := trash.New(db, config.TrashConfig{Enabled: true, Dirs: []string{filepath.Join(dir, "trash")}})
+	file := filepath.Join(dir, "lib", "Heat (1995).mkv")
+	os.MkdirAll(filepath.Dir(file), 0755)
+	os.WriteFile(file, []byte("x"), 0644)
+	if _, err := bin.Remove(file, "duplicate"); err != nil {
+		t.Fatal(err)
+	}
+
+	h := &TrashHandlers{Bin: bin}
+	w := httptest.NewRecorder()
+	h.List(w, httptest.NewRequest("GET", "/trash", nil))
+	var listing TrashListing
+	if err := json.Unmarshal(w.Body.Bytes(), &listing); err != nil {
+		t.Fatal(err)
+	}
+	if !listing.Enabled || len(listing.Items) != 1 || listing.Items[0].OriginalPath != file {
+		t.Fatalf("unexpected listing %+v", listing)
+	}
+
+	req := httptest.NewRequest("POST", "/trash/1/restore", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", strconv.FormatInt(listing.Items[0].ID, 10))
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w = httptest.NewRecorder()
+	h.Restore(w, req)
+	if w.Code != 200 {
+		t.Fatalf("status %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.Errorf("file not restored: %v", err)
+	}
+}
+
+func TestTrashRestoreRejectsBadID(t *testing.T) {
+	h := &TrashHandlers{}
+	req := httptest.NewRequest("POST", "/trash/x/restore", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "x")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	h.Restore(w, req)
+	if w.Code != 400 {
+		t.Errorf("want 400, got %d", w.Code)
+	}
+}
@@ -29,13 +29,30 @@ const (
 // Checker validates media files against Jellyfin naming conventions
 type Checker struct {
 	libraryRoot string
+	templates   *naming.Templates
 }
 
 // NewChecker creates a new compliance checker
-func NewChecker(libraryRoot string) *Checker {
-	return &Checker{
+func NewChecker(libraryRoot string, options ...func(*Checker)) *Checker {
+	c := &Checker{
 		libraryRoot: libraryRoot,
 	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	return c
+}
+
+// WithTemplates checks names against the library's naming templates
+// instead of the built-in layout. A zero Templates keeps the built-in rules.
+func WithTemplates(t naming.Templates) func(*Checker) {
+	return func(c *Checker) {
+		if !t.IsZero() {
+			c.templates = &t
+		}
+	}
 }
 
 // CheckMovie validates a movie file and its path structure
@@ -67,6 +84,10 @@ func (c *Checker) CheckMovie(fullPath string) ComplianceResult {
 		return result
 	}
 
+	if c.templates != nil {
+		return c.checkTemplatedMovie(fullPath, movie)
+	}
+
 	// Check year format (must be in parentheses)
 	if movie.Year == "" {
 		result.Issues = append(result.Issues, fmt.Sprintf("%s: missing year", IssueMissingYear))
@@ -74,8 +95,17 @@ func (c *Checker) CheckMovie(fullPath string) ComplianceResult {
 		result.Issues = append(result.Issues, fmt.Sprintf("%s: year must be in format (YYYY)", IssueInvalidYearFormat))
 	}
 
+	// Jellyfin multi-version files may carry a resolution label
+	// ("Movie (2001) - 2160p.mkv"); that is not a leftover release marker.
+	version := movie.Edition
+	label := naming.MovieVersionLabel(filename, movie.Title, movie.Year)
+	resolutionVersion := naming.IsResolutionVersionLabel(label)
+	if resolutionVersion {
+		version = label
+	}
+
 	// Check for release markers
-	if hasReleaseMarkers(filename) {
+	if !resolutionVersion && hasReleaseMarkers(filename) {
 		result.Issues = append(result.Issues, fmt.Sprintf("%s: contains quality/codec markers", IssueReleaseMarkers))
 	}
 
@@ -84,8 +114,9 @@ func (c *Checker) CheckMovie(fullPath string) ComplianceResult {
 		result.Issues = append(result.Issues, fmt.Sprintf("%s: contains invalid characters: %s", IssueSpecialCharacters, strings.Join(invalidChars, ", ")))
 	}
 
-	// Validate expected filename
-	expectedFilename := naming.FormatMovieFilename(movie.Title, movie.Year, ext[1:])
+	// Validate expected filename, including any edition/version suffix
+	// and the stacking label of a multi-part movie
+	expectedFilename := naming.FormatMovieVersionFilename(movie.Title, movie.Year, naming.StackedVersion(version, movie.Part), ext[1:])
 	if filename != expectedFilename {
 		result.Issues = append(result.Issues, fmt.Sprintf("%s: expected '%s'", IssueInvalidFilename, expectedFilename))
 	}
@@ -136,9 +167,14 @@ func (c *Checker) CheckEpisode(fullPath string) ComplianceResult {
 		return result
 	}
 
-	// Check season folder format
+	if c.templates != nil {
+		return c.checkTemplatedEpisode(fullPath, tv)
+	}
+
+	// Check season folder format. Specials may live in "Season 00" or
+	// "Specials"; both are Jellyfin conventions.
 	expectedSeasonFolder := naming.FormatSeasonFolder(tv.Season)
-	if !strings.EqualFold(seasonFolder, expectedSeasonFolder) {
+	if !naming.SeasonFolderMatches(seasonFolder, tv.Season) {
 		result.Issues = append(result.Issues, fmt.Sprintf("%s: expected '%s', found '%s'", IssueWrongSeasonFolder, expectedSeasonFolder, seasonFolder))
 	}
 
@@ -166,7 +202,10 @@ func (c *Checker) CheckEpisode(fullPath string) ComplianceResult {
 	}
 
 	// Validate expected filename
-	expectedFilename := naming.FormatTVEpisodeFilename(tv.Title, tv.Year, tv.Season, tv.Episode, ext[1:])
+	expectedFilename := naming.FormatTVEpisodeRangeFilename(tv.Title, tv.Year, tv.Season, tv.Episode, tv.EpisodeEnd, ext[1:])
+	if tv.IsSpecial() && tv.Episode <= 0 {
+		expectedFilename = naming.FormatTVEpisodeFilenameFromInfo(tv, ext[1:])
+	}
 	if filename != expectedFilename {
 		result.Issues = append(result.Issues, fmt.Sprintf("%s: expected '%s'", IssueInvalidFilename, expectedFilename))
 	}
@@ -186,6 +225,69 @@ func (c *Checker) CheckEpisode(fullPath string) ComplianceResult {
 	return result
 }
 
+// checkTemplatedMovie validates a movie against the configured templates.
+// Release details and provider IDs are read back from the path itself, so
+// the check confirms the layout rather than the values.
+func (c *Checker) checkTemplatedMovie(fullPath string, movie *naming.MovieInfo) ComplianceResult {
+	result := ComplianceResult{Issues: []string{}}
+	tmpl := c.templates.WithDefaults()
+	filename := filepath.Base(fullPath)
+	parentDir := filepath.Base(filepath.Dir(fullPath))
+	extra := naming.TemplateValuesFromPath(fullPath)
+
+	if movie.Year == "" && naming.TemplateUses(tmpl.MovieFile, "Year") {
+		result.Issues = append(result.Issues, fmt.Sprintf("%s: missing year", IssueMissingYear))
+	}
+	if !naming.TemplateUses(tmpl.MovieFile, "Resolution", "Source", "ReleaseGroup") && hasReleaseMarkers(filename) {
+		result.Issues = append(result.Issues, fmt.Sprintf("%s: contains quality/codec markers", IssueReleaseMarkers))
+	}
+	if invalidChars := findInvalidCharacters(filename); len(invalidChars) > 0 {
+		result.Issues = append(result.Issues, fmt.Sprintf("%s: contains invalid characters: %s", IssueSpecialCharacters, strings.Join(invalidChars, ", ")))
+	}
+	if expected := tmpl.MovieFileName(movie, extra, filepath.Ext(filename)); filename != expected {
+		result.Issues = append(result.Issues, fmt.Sprintf("%s: expected '%s'", IssueInvalidFilename, expected))
+	}
+	if expected := tmpl.MovieFolderName(movie, extra); parentDir != expected {
+		result.Issues = append(result.Issues, fmt.Sprintf("%s: folder should be '%s'", IssueInvalidFolderStructure, expected))
+	}
+
+	result.IsCompliant = len(result.Issues) == 0
+	return result
+}
+
+// checkTemplatedEpisode validates an episode against the configured
+// templates. Any episode title is accepted where the template has one.
+func (c *Checker) checkTemplatedEpisode(fullPath string, tv *naming.TVShowInfo) ComplianceResult {
+	result := ComplianceResult{Issues: []string{}}
+	filename := filepath.Base(fullPath)
+	seasonFolder := filepath.Base(filepath.Dir(fullPath))
+	showFolder := filepath.Base(filepath.Dir(filepath.Dir(fullPath)))
+	extra := naming.TemplateValuesFromPath(fullPath)
+
+	// Without a season folder template both specials conventions stay valid.
+	expectedSeasonFolder := c.templates.SeasonFolderName(tv.Season)
+	if seasonFolder != expectedSeasonFolder &&
+		!(c.templates.SeasonFolder == "" && naming.SeasonFolderMatches(seasonFolder, tv.Season)) {
+		result.Issues = append(result.Issues, fmt.Sprintf("%s: expected '%s', found '%s'", IssueWrongSeasonFolder, expectedSeasonFolder, seasonFolder))
+	}
+	if !naming.TemplateUses(c.templates.WithDefaults().EpisodeFile, "Resolution", "Source", "ReleaseGroup") && hasReleaseMarkers(filename) {
+		result.Issues = append(result.Issues, fmt.Sprintf("%s: contains quality/codec markers", IssueReleaseMarkers))
+	}
+	if invalidChars := findInvalidCharacters(filename); len(invalidChars) > 0 {
+		result.Issues = append(result.Issues, fmt.Sprintf("%s: contains invalid characters: %s", IssueSpecialCharacters, strings.Join(invalidChars, ", ")))
+	}
+	if !c.templates.MatchesEpisodeFileName(filename, tv, extra) {
+		expected := c.templates.EpisodeFileName(tv, extra, filepath.Ext(filename))
+		result.Issues = append(result.Issues, fmt.Sprintf("%s: expected '%s'", IssueInvalidFilename, expected))
+	}
+	if expected := c.templates.SeriesFolderName(tv, extra); showFolder != expected {
+		result.Issues = append(result.Issues, fmt.Sprintf("%s: show folder should be '%s'", IssueInvalidFolderStructure, expected))
+	}
+
+	result.IsCompliant = len(result.Issues) == 0
+	return result
+}
+
 // CheckFile determines media type and runs appropriate validation
 func (c *Checker) CheckFile(fullPath string) ComplianceResult {
 	filename := filepath.Base(fullPath)
@@ -247,9 +349,13 @@ func findInvalidCharacters(filename string) []string {
 
 // isValidSeasonFolder checks if season folder uses proper zero-padding
 func isValidSeasonFolder(folder string) bool {
-	// Valid formats: "Season 01", "Season 02", ..., "Season 99"
+	// Valid formats: "Season 00", "Season 01", ..., "Season 99", "Specials"
 	// Invalid: "Season 1", "season 01", "S01"
 
+	if folder == naming.SpecialsFolder {
+		return true
+	}
+
 	if !strings.HasPrefix(folder, "Season ") {
 		return false
 	}
@@ -2,6 +2,8 @@ package compliance
 
 import (
 	"testing"
+
+	"github.com/Nomadcxx/jellywatch/internal/naming"
 )
 
 func TestCheckMovie_CompliantFile(t *testing.T) {
@@ -23,6 +25,18 @@ func TestCheckMovie_CompliantFile(t *testing.T) {
 			name: "Movie with comma",
 			path: "/media/Movies/Me, Myself & Irene (2000)/Me, Myself & Irene (2000).mp4",
 		},
+		{
+			name: "Edition version",
+			path: "/media/Movies/Blade Runner (1982)/Blade Runner (1982) - Director's Cut.mkv",
+		},
+		{
+			name: "Resolution version",
+			path: "/media/Movies/Interstellar (2014)/Interstellar (2014) - 2160p.mkv",
+		},
+		{
+			name: "Stacked part",
+			path: "/media/Movies/Heat (1995)/Heat (1995) - part2.avi",
+		},
 	}
 
 	for _, tt := range tests {
@@ -135,6 +149,18 @@ func TestCheckEpisode_CompliantFile(t *testing.T) {
 			name: "Show with apostrophe",
 			path: "/media/TV/The Handmaid's Tale (2017)/Season 01/The Handmaid's Tale (2017) S01E03.mkv",
 		},
+		{
+			name: "Special in Season 00",
+			path: "/media/TV/Doctor Who (2005)/Season 00/Doctor Who (2005) S00E05.mkv",
+		},
+		{
+			name: "Special in Specials folder",
+			path: "/media/TV/Doctor Who (2005)/Specials/Doctor Who (2005) S00E05.mkv",
+		},
+		{
+			name: "Unnumbered special",
+			path: "/media/TV/Doctor Who (2005)/Specials/Doctor Who (2005) - Christmas Special 2023.mkv",
+		},
 	}
 
 	for _, tt := range tests {
@@ -331,6 +357,8 @@ func TestIsValidSeasonFolder(t *testing.T) {
 		{"Season 02", true},
 		{"Season 10", true},
 		{"Season 99", true},
+		{"Season 00", true},
+		{"Specials", true},
 		{"Season 1", false},   // Not padded
 		{"Season 001", false}, // Too many digits
 		{"season 01", false},  // Wrong case
@@ -352,3 +380,35 @@ func TestIsValidSeasonFolder(t *testing.T) {
 func containsIssueType(issue, issueType string) bool {
 	return len(issue) >= len(issueType) && issue[:len(issueType)] == issueType
 }
+
+func TestCheckFile_Templates(t *testing.T) {
+	checker := NewChecker("/media", WithTemplates(naming.Templates{
+		MovieFolder:  "{Title} ({Year}) [imdbid-{ImdbID}]",
+		SeriesFolder: "{Title} ({Year}) [tvdbid-{TvdbID}]",
+		EpisodeFile:  "{Title} ({Year}) S{Season}E{Episode} - {EpisodeTitle} [{Resolution} {Source}]",
+	}))
+
+	tests := []struct {
+		name      string
+		path      string
+		compliant bool
+	}{
+		{"tagged movie folder", "/media/Movies/The Matrix (1999) [imdbid-tt0133093]/The Matrix (1999).mkv", true},
+		{"untagged movie folder", "/media/Movies/The Matrix (1999)/The Matrix (1999).mkv", true},
+		{"movie markers not in template", "/media/Movies/The Matrix (1999)/The Matrix (1999) 1080p.mkv", false},
+		{"episode with title", "/media/TV/Severance (2022) [tvdbid-371980]/Season 01/Severance (2022) S01E01 - Good News About Hell [1080p WEB-DL].mkv", true},
+		{"episode without title", "/media/TV/Severance (2022) [tvdbid-371980]/Season 01/Severance (2022) S01E01 [1080p WEB-DL].mkv", true},
+		{"episode missing quality suffix", "/media/TV/Severance (2022)/Season 01/Severance (2022) S01E01.mkv", true},
+		{"built-in episode name is wrong", "/media/TV/Severance (2022)/Season 01/Severance (2022) S01E01 - Pilot.1080p.mkv", false},
+		{"specials folder still accepted", "/media/TV/Severance (2022)/Specials/Severance (2022) S00E01.mkv", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checker.CheckFile(tt.path)
+			if result.IsCompliant != tt.compliant {
+				t.Errorf("IsCompliant = %v, want %v (issues: %v)", result.IsCompliant, tt.compliant, result.Issues)
+			}
+		})
+	}
+}
@@ -3,8 +3,13 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+	"github.com/Nomadcxx/jellywatch/internal/quality"
 )
 
 func TestPermissionsResolveNumeric(t *testing.T) {
@@ -188,3 +193,520 @@ func TestConfigToTOMLIncludesJellyfinSection(t *testing.T) {
 		t.Fatal("expected webhook_secret key in TOML output")
 	}
 }
+
+func TestProbeSettingsRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+
+	cfg := DefaultConfig()
+	cfg.Probe.Enabled = true
+	cfg.Probe.FFprobePath = "/usr/local/bin/ffprobe"
+	cfg.Probe.TimeoutSeconds = 10
+	cfg.Probe.RejectCorrupt = false
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Probe != cfg.Probe {
+		t.Fatalf("Probe = %+v, want %+v", loaded.Probe, cfg.Probe)
+	}
+}
+
+func TestTrashSettingsRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+
+	cfg := DefaultConfig()
+	cfg.Trash.Enabled = true
+	cfg.Trash.Dirs = []string{"/mnt/STORAGE1/.trash", "/mnt/STORAGE2/.trash"}
+	cfg.Trash.MaxAgeDays = 14
+	cfg.Trash.MaxSizeGB = 250.5
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Trash, cfg.Trash) {
+		t.Fatalf("Trash = %+v, want %+v", loaded.Trash, cfg.Trash)
+	}
+}
+
+func TestWatchBackendSettingsRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+
+	cfg := DefaultConfig()
+	cfg.Watch.TV = []string{"/downloads/tv", "/mnt/nas/tv"}
+	cfg.Watch.Backend = "fsnotify"
+	cfg.Watch.PollSeconds = 45
+	cfg.Watch.Settings = []WatchSettings{{Path: "/mnt/nas", Backend: "poll"}}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Watch, cfg.Watch) {
+		t.Fatalf("Watch = %+v, want %+v", loaded.Watch, cfg.Watch)
+	}
+	if got := loaded.Watch.PollInterval(); got != 45*time.Second {
+		t.Fatalf("PollInterval = %s, want 45s", got)
+	}
+
+	cfg.Watch.Settings[0].Backend = "kqueue"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err == nil {
+		t.Fatal("Load accepted an unknown watch backend")
+	}
+}
+
+func TestWatchFiltersRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+
+	cfg := DefaultConfig()
+	cfg.Watch.TV = []string{"/downloads/tv"}
+	cfg.Watch.Movies = []string{"/downloads/movies"}
+	cfg.Watch.Filters.Exclude = []string{"*sample*"}
+	cfg.Watch.Filters.MinMovieMB = 300
+	cfg.Watch.Filters.MinEpisodeMB = 40
+	cfg.Watch.Settings = []WatchSettings{{
+		Path: "/downloads/tv/anime",
+		FilterConfig: FilterConfig{
+			Include:      []string{"*.mkv"},
+			MinEpisodeMB: 5,
+			HiddenFiles:  "include",
+		},
+	}}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Watch, cfg.Watch) {
+		t.Fatalf("Watch = %+v, want %+v", loaded.Watch, cfg.Watch)
+	}
+
+	filters, err := loaded.Watch.IngestFilters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]bool{
+		"/downloads/movies/Film.2020.mkv":            false, // 100 MB < 300 MB
+		"/downloads/tv/Show.S01E01.mkv":              true,
+		"/downloads/tv/_UNPACK_Show/Show.S01E01.mkv": false,
+		"/downloads/tv/anime/Show - 01.mkv":          true,
+		"/downloads/tv/anime/Show - 01.mp4":          false,
+	} {
+		if got, reason := filters.Accept(path, 100<<20, ""); got != want {
+			t.Errorf("Accept(%q) = %v (%s), want %v", path, got, reason, want)
+		}
+	}
+
+	cfg.Watch.Filters.HiddenFiles = "show"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err == nil {
+		t.Fatal("Load accepted an unknown hidden_files policy")
+	}
+}
+
+func TestExtractRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+
+	cfg := DefaultConfig()
+	cfg.Extract = ExtractConfig{Enabled: true, Backend: "7z", KeepSource: false}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Extract != cfg.Extract {
+		t.Fatalf("Extract = %+v, want %+v", loaded.Extract, cfg.Extract)
+	}
+	if b, err := loaded.Extract.Extractor(); err != nil || b.Name() != "7z" {
+		t.Fatalf("Extractor() = %v, %v, want 7z", b, err)
+	}
+
+	cfg.Extract.Backend = "winrar"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err == nil {
+		t.Fatal("Load accepted an unknown extract backend")
+	}
+}
+
+func TestExtrasRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+
+	cfg := DefaultConfig()
+	cfg.Options.Extras = "suffix"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if layout, err := loaded.Options.ExtrasLayout(); err != nil || layout != naming.ExtrasSuffix {
+		t.Fatalf("ExtrasLayout() = %q, %v, want suffix", layout, err)
+	}
+
+	cfg.Options.Extras = "nested"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err == nil {
+		t.Fatal("Load accepted an unknown extras layout")
+	}
+}
+
+func TestRulesRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+
+	keep := true
+	cfg := DefaultConfig()
+	cfg.Libraries.Movies = []string{"/mnt/Movies", "/mnt/Kids Movies"}
+	cfg.Libraries.TV = []string{"/mnt/TV", "/mnt/Anime"}
+	cfg.Rules = []RuleConfig{
+		{Name: "kids", Path: "*/Kids/*", Library: "/mnt/Kids Movies", KeepSource: &keep, TransferBackend: "hardlink"},
+		{Name: "samples", Path: "*.sample.*", Ignore: true},
+		{ReleaseGroup: []string{"SubsPlease", "Erai-raws"}, Library: "/mnt/Anime"},
+		{PathRegex: `Heat\.1995`, Resolution: []string{"2160p"}, MinSizeMB: 100, Title: "Heat", Year: 1995},
+		{Name: "radarr-4k", Category: []string{"radarr-4k"}, Library: "/mnt/Movies"},
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Rules, cfg.Rules) {
+		t.Fatalf("Rules = %+v, want %+v", loaded.Rules, cfg.Rules)
+	}
+
+	engine, err := loaded.RuleEngine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := engine.Match("/downloads/Anime/[SubsPlease] Frieren - 01 (1080p).mkv", 1<<30, ""); r == nil || r.MediaType != "tv" {
+		t.Errorf("anime rule = %+v, want media type tv from its library", r)
+	}
+	if r := engine.Match("/downloads/complete/Dune.2021.2160p.mkv", 1<<30, "radarr-4k"); r == nil || r.Name != "radarr-4k" {
+		t.Errorf("category rule = %+v, want radarr-4k", r)
+	}
+}
+
+func TestRuleEngineRejectsBadRules(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Libraries.Movies = []string{"/mnt/Movies"}
+	for name, rc := range map[string]RuleConfig{
+		"unknown library": {Path: "*", Library: "/mnt/Elsewhere"},
+		"wrong kind":      {Path: "*", Library: "/mnt/Movies", MediaType: "tv"},
+		"bad backend":     {Path: "*", TransferBackend: "hardlnk"},
+		"no matcher":      {Ignore: true},
+		"no action":       {Path: "*"},
+		"bad regex":       {PathRegex: "(", Ignore: true},
+	} {
+		cfg.Rules = []RuleConfig{rc}
+		if _, err := cfg.RuleEngine(); err == nil {
+			t.Errorf("%s: RuleEngine accepted %+v", name, rc)
+		}
+	}
+}
+
+func TestDownloadClientsRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+
+	cfg := DefaultConfig()
+	cfg.QBittorrent = QBittorrentConfig{
+		Enabled:           true,
+		URL:               "http://localhost:8080",
+		Username:          "admin",
+		Password:          "secret",
+		RespectSeedLimits: true,
+		AfterOrganize:     "pause",
+		PathMappings:      []DownloadClientPathMapping{{Client: "/data/torrents", Daemon: "/mnt/torrents"}},
+	}
+	cfg.SABnzbd = SABnzbdConfig{
+		Enabled:       true,
+		URL:           "http://localhost:8081",
+		APIKey:        "key",
+		AfterOrganize: "remove",
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.QBittorrent, cfg.QBittorrent) {
+		t.Errorf("QBittorrent = %+v, want %+v", loaded.QBittorrent, cfg.QBittorrent)
+	}
+	if !reflect.DeepEqual(loaded.SABnzbd, cfg.SABnzbd) {
+		t.Errorf("SABnzbd = %+v, want %+v", loaded.SABnzbd, cfg.SABnzbd)
+	}
+	if tracker, err := loaded.DownloadTracker(); err != nil || tracker == nil {
+		t.Fatalf("DownloadTracker = %v, %v", tracker, err)
+	}
+}
+
+func TestDownloadTrackerRejectsBadConfig(t *testing.T) {
+	for name, cfg := range map[string]*Config{
+		"no url":        {QBittorrent: QBittorrentConfig{Enabled: true}},
+		"unknown after": {QBittorrent: QBittorrentConfig{Enabled: true, URL: "http://qbt", AfterOrganize: "delete"}},
+		"sab pause":     {SABnzbd: SABnzbdConfig{Enabled: true, URL: "http://sab", AfterOrganize: "pause"}},
+	} {
+		if _, err := cfg.DownloadTracker(); err == nil {
+			t.Errorf("%s: DownloadTracker accepted %+v", name, cfg)
+		}
+	}
+	if tracker, err := DefaultConfig().DownloadTracker(); err != nil || tracker != nil {
+		t.Errorf("default DownloadTracker = %v, %v; want nil", tracker, err)
+	}
+}
+
+func TestQualityProfilesRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+
+	zero := 0
+	cfg := DefaultConfig()
+	cfg.Libraries.Movies = []string{"/mnt/Movies", "/mnt/Movies4K"}
+	cfg.Quality.Profiles = []QualityProfileConfig{{
+		Name:       "remux-first",
+		Resolution: map[string]int{"2160p": 300},
+		Source:     map[string]int{"REMUX": 200},
+		Audio:      map[string]int{"Atmos": 80, "DTS-HD MA": 60},
+		Codec:      map[string]int{"x265": 15},
+		Proper:     5,
+		SizePerGB:  &zero,
+	}}
+	cfg.Libraries.Settings = []LibrarySettings{{Path: "/mnt/Movies", QualityProfile: "remux-first"}}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, err := loaded.QualityProfiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := set.For("/mnt/Movies/Heat (1995)/Heat (1995).mkv")
+	if p.Name != "remux-first" || p.Source[quality.SourceREMUX] != 200 || p.Audio[quality.AudioDTSHDMA] != 60 {
+		t.Fatalf("library profile = %+v, want remux-first weights", p)
+	}
+	if p.Proper != 5 || p.SizePerGB != 0 || p.MaxSizeGB != quality.MaxSizeBonusGB {
+		t.Fatalf("size/proper settings = %d/%d/%d, want 5/0/%d", p.Proper, p.SizePerGB, p.MaxSizeGB, quality.MaxSizeBonusGB)
+	}
+	if got := set.For("/mnt/Movies4K/Heat (1995)/Heat (1995).mkv"); got.Name != quality.DefaultProfileName {
+		t.Fatalf("unassigned library profile = %s, want default", got.Name)
+	}
+}
+
+func TestQualityProfilesRejectsBadConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Libraries.Settings = []LibrarySettings{{Path: "/mnt/Movies", QualityProfile: "missing"}}
+	if _, err := cfg.QualityProfiles(); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Fatalf("expected unknown profile error, got %v", err)
+	}
+
+	cfg = DefaultConfig()
+	cfg.Quality.Profiles = []QualityProfileConfig{{Name: "typo", Source: map[string]int{"BlueRay": 10}}}
+	if _, err := cfg.QualityProfiles(); err == nil || !strings.Contains(err.Error(), "BlueRay") {
+		t.Fatalf("expected unknown source error, got %v", err)
+	}
+}
+
+func TestNotificationsRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+
+	cfg := DefaultConfig()
+	cfg.Notifications = []NotificationConfig{
+		{URL: "discord://123/abc", Events: []string{"failed", "needs_review"}},
+		{URL: "https://example.com/hook", DigestSeconds: 600, MaxPerHour: 6, Template: `{"text": {{json .Message}}}`},
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Notifications, cfg.Notifications) {
+		t.Fatalf("Notifications = %+v, want %+v", loaded.Notifications, cfg.Notifications)
+	}
+	alerter, err := loaded.Alerter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alerter.RouteCount() != 2 {
+		t.Fatalf("RouteCount() = %d, want 2", alerter.RouteCount())
+	}
+
+	loaded.Notifications[0].Events = []string{"organised"}
+	if _, err := loaded.Alerter(); err == nil || !strings.Contains(err.Error(), "organised") {
+		t.Fatalf("expected unknown event error, got %v", err)
+	}
+}
+
+func TestAIProvidersRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+
+	cfg := DefaultConfig()
+	cfg.AI.FallbackModel = "llama3.2:3b"
+	cfg.AI.Provider = "claude"
+	cfg.AI.Fallback = []string{"local"}
+	cfg.AI.Providers = []AIProviderConfig{
+		{ID: "claude", Type: "anthropic", APIKey: "sk-ant-test", Model: "claude-haiku"},
+		{ID: "local", Type: "lmstudio", Endpoint: "http://127.0.0.1:1234/v1", Model: "qwen2.5-7b-instruct"},
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.AI.Provider != "claude" || !reflect.DeepEqual(loaded.AI.Fallback, cfg.AI.Fallback) {
+		t.Fatalf("provider = %q fallback = %v", loaded.AI.Provider, loaded.AI.Fallback)
+	}
+	if !reflect.DeepEqual(loaded.AI.Providers, cfg.AI.Providers) {
+		t.Fatalf("Providers = %+v, want %+v", loaded.AI.Providers, cfg.AI.Providers)
+	}
+
+	reg, err := loaded.AI.ProviderRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chain []string
+	for _, p := range reg.Chain() {
+		chain = append(chain, p.ID())
+	}
+	if want := []string{"claude", "local", OllamaFallbackProviderID}; !reflect.DeepEqual(chain, want) {
+		t.Fatalf("Chain() = %v, want %v", chain, want)
+	}
+
+	loaded.AI.Fallback = []string{"missing"}
+	if _, err := loaded.AI.ProviderRegistry(); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Fatalf("expected unknown fallback error, got %v", err)
+	}
+	loaded.AI.Fallback = nil
+	loaded.AI.Providers[0].Type = "gpt"
+	if _, err := loaded.AI.ProviderRegistry(); err == nil || !strings.Contains(err.Error(), "unknown type") {
+		t.Fatalf("expected unknown type error, got %v", err)
+	}
+}
+
+func TestLibrarySettingsRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+
+	cfg := DefaultConfig()
+	cfg.Libraries.TV = []string{"/mnt/STORAGE1/TV"}
+	cfg.Libraries.Settings = []LibrarySettings{
+		{
+			Path: "/mnt/STORAGE1/TV", TransferBackend: "hardlink", Anime: true,
+			SeriesFolderTemplate: "{Title} ({Year}) [tvdbid-{TvdbID}]",
+		},
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := loaded.Libraries.SettingsFor("/mnt/STORAGE1/TV/")
+	if !ok {
+		t.Fatalf("expected settings for library, got %+v", loaded.Libraries.Settings)
+	}
+	if got.TransferBackend != "hardlink" {
+		t.Fatalf("TransferBackend = %q, want hardlink", got.TransferBackend)
+	}
+	if !got.Anime {
+		t.Fatal("expected anime flag to round-trip")
+	}
+	if tmpl := loaded.Libraries.TemplatesFor("/mnt/STORAGE1/TV"); tmpl.SeriesFolder != "{Title} ({Year}) [tvdbid-{TvdbID}]" {
+		t.Fatalf("SeriesFolder template = %q", tmpl.SeriesFolder)
+	}
+	if tmpl := loaded.Libraries.TemplatesForPath("/mnt/STORAGE1/TV/Show/Season 01/ep.mkv"); tmpl.IsZero() {
+		t.Fatal("expected templates for a file inside the library")
+	}
+	if tmpl := loaded.Libraries.TemplatesForPath("/mnt/STORAGE1/TV2/Show"); !tmpl.IsZero() {
+		t.Fatal("a sibling folder sharing the prefix is not inside the library")
+	}
+	if _, ok := loaded.Libraries.SettingsFor("/mnt/STORAGE2/TV"); ok {
+		t.Fatal("unexpected settings for unconfigured library")
+	}
+}
+
+func TestLibrarySettingsRejectsUnknownBackend(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+
+	cfg := DefaultConfig()
+	cfg.Libraries.TV = []string{"/mnt/STORAGE1/TV"}
+	cfg.Libraries.Settings = []LibrarySettings{
+		{Path: "/mnt/STORAGE1/TV", TransferBackend: "hardlnk"},
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "hardlnk") {
+		t.Fatalf("expected Load to reject unknown transfer_backend, got %v", err)
+	}
+}
+
+func TestLibrarySettingsRejectsInvalidTemplate(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+
+	cfg := DefaultConfig()
+	cfg.Libraries.Movies = []string{"/mnt/STORAGE1/Movies"}
+	cfg.Libraries.Settings = []LibrarySettings{
+		{Path: "/mnt/STORAGE1/Movies", MovieFileTemplate: "{Title} {Quality}"},
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "movie_file_template") {
+		t.Fatalf("expected Load to reject unknown template token, got %v", err)
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"time"
 
 	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/sidecars"
 	"github.com/Nomadcxx/jellywatch/internal/transfer"
+	"github.com/Nomadcxx/jellywatch/internal/trash"
 )
 
 // Executor executes consolidation plans
@@ -19,6 +21,10 @@ type Executor struct {
 	transferer transfer.Transferer
 	dryRun     bool
 	writer     io.Writer
+	// batch groups the journal entries of one ExecutePlans or
+	// ExecutePlan call.
+	batch string
+	trash *trash.Bin
 }
 
 // ExecutionResult contains statistics from plan execution
@@ -49,6 +55,12 @@ func NewExecutor(db *database.MediaDB, dryRun bool, writer io.Writer) *Executor
 	}
 }
 
+// SetTrash sends deleted duplicates to the recycle bin instead of
+// unlinking them.
+func (e *Executor) SetTrash(bin *trash.Bin) {
+	e.trash = bin
+}
+
 // Printf writes formatted output to the configured writer
 func (e *Executor) Printf(format string, a ...interface{}) {
 	fmt.Fprintf(e.writer, format, a...)
@@ -58,6 +70,7 @@ func (e *Executor) Printf(format string, a ...interface{}) {
 func (e *Executor) ExecutePlans(ctx context.Context) (*ExecutionResult, error) {
 	startTime := time.Now()
 	result := &ExecutionResult{}
+	e.batch = database.NewJournalBatch(database.OpSourceConsolidate)
 
 	// Get all pending plans
 	plans, err := e.planner.GetPendingPlans()
@@ -141,6 +154,7 @@ func (e *Executor) ExecutePlan(ctx context.Context, planID int64) error {
 	if plan.Status != "pending" {
 		return fmt.Errorf("plan is not pending (status: %s)", plan.Status)
 	}
+	e.batch = database.NewJournalBatch(database.OpSourceConsolidate)
 	if reason := DBPlanSafetyIssue(plan); reason != "" {
 		err := fmt.Errorf("unsafe consolidation plan: %s", reason)
 		e.markPlanFailed(plan.ID, err.Error())
@@ -181,9 +195,11 @@ func (e *Executor) executeDelete(ctx context.Context, plan *ConsolidationPlan) e
 	}
 
 	// Delete the file
-	if err := os.Remove(plan.SourcePath); err != nil {
+	keptAt, err := e.trash.Remove(plan.SourcePath, "consolidation: "+plan.Reason)
+	if err != nil {
 		return fmt.Errorf("failed to delete file: %w", err)
 	}
+	e.journal(database.OpDelete, plan.SourcePath, keptAt)
 
 	// Remove from database
 	if err := e.db.DeleteMediaFile(plan.SourcePath); err != nil {
@@ -204,20 +220,12 @@ func (e *Executor) executeMove(ctx context.Context, plan *ConsolidationPlan) err
 		return fmt.Errorf("source file does not exist")
 	}
 
-	// Use transfer package for reliable file move (handles failing disks)
-	result, err := e.transferer.Move(plan.SourcePath, plan.TargetPath, transfer.TransferOptions{
-		Timeout:   5 * time.Minute,
-		TargetUID: -1, // Preserve ownership
-		TargetGID: -1,
-	})
-
-	if err != nil {
+	// Use transfer package for reliable file move (handles failing disks).
+	// NFOs, artwork and trickplay folders follow the video.
+	if _, err := sidecars.MoveWith(plan.SourcePath, plan.TargetPath, e.moveFile); err != nil {
 		return fmt.Errorf("transfer failed: %w", err)
 	}
-
-	if result.Error != nil {
-		return fmt.Errorf("transfer failed: %w", result.Error)
-	}
+	e.journal(database.OpMove, plan.SourcePath, plan.TargetPath)
 
 	// Update database - remove old path, add new path
 	file, err := e.db.GetMediaFile(plan.SourcePath)
@@ -271,19 +279,10 @@ func (e *Executor) executeRename(ctx context.Context, plan *ConsolidationPlan) e
 		return fmt.Errorf("source file does not exist")
 	}
 
-	result, err := e.transferer.Move(plan.SourcePath, plan.TargetPath, transfer.TransferOptions{
-		Timeout:   5 * time.Minute,
-		TargetUID: -1,
-		TargetGID: -1,
-	})
-
-	if err != nil {
+	if _, err := sidecars.MoveWith(plan.SourcePath, plan.TargetPath, e.moveFile); err != nil {
 		return fmt.Errorf("failed to move file: %w", err)
 	}
-
-	if result.Error != nil {
-		return fmt.Errorf("file transfer failed: %w", result.Error)
-	}
+	e.journal(database.OpMove, plan.SourcePath, plan.TargetPath)
 
 	// Update database path
 	file, err := e.db.GetMediaFile(plan.SourcePath)
@@ -310,6 +309,34 @@ func (e *Executor) executeRename(ctx context.Context, plan *ConsolidationPlan) e
 	return nil
 }
 
+// moveFile moves one file with the executor's transferer, preserving
+// ownership.
+func (e *Executor) moveFile(src, dst string) error {
+	result, err := e.transferer.Move(src, dst, transfer.TransferOptions{
+		Timeout:   5 * time.Minute,
+		TargetUID: -1,
+		TargetGID: -1,
+	})
+	if err != nil {
+		return err
+	}
+	return result.Error
+}
+
+// journal records a change a plan made, before the database follows it.
+func (e *Executor) journal(kind, before, after string) {
+	if _, err := e.db.RecordOperation(database.JournalOp{
+		BatchID:    e.batch,
+		Source:     database.OpSourceConsolidate,
+		Kind:       kind,
+		BeforePath: before,
+		AfterPath:  after,
+		Reversible: kind != database.OpDelete || after != "",
+	}); err != nil {
+		e.Printf("Warning: failed to journal %s of %s: %v\n", kind, before, err)
+	}
+}
+
 // markPlanCompleted marks a plan as successfully completed
 func (e *Executor) markPlanCompleted(planID int64) error {
 	query := `
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Nomadcxx/jellywatch/internal/sidecars"
 	"github.com/Nomadcxx/jellywatch/internal/transfer"
 )
 
@@ -189,16 +190,22 @@ func (c *Consolidator) executeOperation(op *Operation, dryRun bool) error {
 	opts := transfer.OptionsFromConfig(c.cfg)
 	opts.Checksum = c.cfg.Options.VerifyChecksums
 
-	// Move file (rsync backend handles failing disks)
-	result, err := transf.Move(op.SourcePath, op.DestinationPath, opts)
+	// Move file (rsync backend handles failing disks), bringing its NFO,
+	// artwork and trickplay along.
+	_, err = sidecars.MoveWith(op.SourcePath, op.DestinationPath, func(src, dst string) error {
+		result, err := transf.Move(src, dst, opts)
+		if err != nil {
+			return err
+		}
+		if !result.Success {
+			return fmt.Errorf("%v", result.Error)
+		}
+		return nil
+	})
 	if err != nil {
 		return fmt.Errorf("transfer failed: %w", err)
 	}
 
-	if !result.Success {
-		return fmt.Errorf("transfer failed: %v", result.Error)
-	}
-
 	return nil
 }
 
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/Nomadcxx/jellywatch/internal/naming"
 )
 
 // SafetyIssues returns reasons a consolidation plan must not be executed.
@@ -85,6 +87,9 @@ func hasSeasonComponent(path string) bool {
 }
 
 func isExtraContentPath(path string) bool {
+	if naming.IsExtra(path) {
+		return true
+	}
 	components := strings.FieldsFunc(strings.ToLower(filepath.Clean(path)), func(r rune) bool {
 		return r == filepath.Separator
 	})
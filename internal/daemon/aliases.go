@@ -0,0 +1,22 @@
+package daemon
+
+import (
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/logging"
+)
+
+// aliasFor returns the manual override for a file at path parsed as
+// title, or nil.
+func (h *MediaHandler) aliasFor(mediaType, path, title string) *database.Alias {
+	if h.db == nil {
+		return nil
+	}
+	alias, err := h.db.MatchAlias(mediaType, path, title)
+	if err != nil {
+		h.logger.Warn("handler", "alias lookup failed",
+			logging.F("path", path),
+			logging.F("error", err.Error()))
+		return nil
+	}
+	return alias
+}
@@ -0,0 +1,49 @@
+package daemon
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Aliases apply on their own, without any config rule matching the file.
+func TestProcessFile_AppliesAliasesWithoutRules(t *testing.T) {
+	h, db, root := newArrHandler(t)
+	year := 2005
+	require.NoError(t, db.UpsertAlias(&database.Alias{
+		Kind:      database.AliasKindTitle,
+		Pattern:   "The Office US",
+		MediaType: database.AliasMediaSeries,
+		Title:     "The Office (US)",
+		Year:      &year,
+	}))
+	movieYear := 1995
+	require.NoError(t, db.UpsertAlias(&database.Alias{
+		Kind:      database.AliasKindFolder,
+		Pattern:   "Hitz.1995.1080p.BluRay-GRP",
+		MediaType: database.AliasMediaMovie,
+		Title:     "Heat",
+		Year:      &movieYear,
+	}))
+
+	episode := filepath.Join(root, "tv-downloads", "The.Office.US.S02E01.1080p.WEB-GRP.mkv")
+	movie := filepath.Join(root, "movie-downloads", "Hitz.1995.1080p.BluRay-GRP", "Hitz.1995.1080p.BluRay-GRP.mkv")
+	writeVideo(t, episode)
+	writeVideo(t, movie)
+
+	h.processFile(episode)
+	h.processFile(movie)
+
+	assert.FileExists(t, filepath.Join(root, "TV", "The Office (US) (2005)", "Season 02", "The Office (US) (2005) S02E01.mkv"))
+	assert.FileExists(t, filepath.Join(root, "Movies", "Heat (1995)", "Heat (1995).mkv"))
+
+	rows, err := db.QueryDecisions(database.QueryFilter{})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	for _, row := range rows {
+		assert.Equal(t, "alias", row.ParseMethod, row.SourcePath)
+	}
+}
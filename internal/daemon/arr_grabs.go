@@ -0,0 +1,32 @@
+package daemon
+
+import (
+	"github.com/Nomadcxx/jellywatch/internal/activity"
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/logging"
+)
+
+// arrGrabFor returns what Sonarr or Radarr reported the download holding
+// path to be, or nil. Their identity is authoritative: a file it covers is
+// organized under it without regex or AI parsing.
+func (h *MediaHandler) arrGrabFor(path string) *database.ArrGrab {
+	if h.db == nil {
+		return nil
+	}
+	grab, err := h.db.MatchArrGrab(path)
+	if err != nil {
+		h.logger.Warn("handler", "download identity lookup failed",
+			logging.F("path", path),
+			logging.F("error", err.Error()))
+		return nil
+	}
+	return grab
+}
+
+// arrParseMethod is the parse method recorded for a file named from grab.
+func arrParseMethod(grab *database.ArrGrab) activity.ParseMethod {
+	if grab.IsTV() {
+		return activity.MethodSonarr
+	}
+	return activity.MethodRadarr
+}
@@ -0,0 +1,72 @@
+package daemon
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newArrHandler(t *testing.T) (h *MediaHandler, db *database.MediaDB, root string) {
+	t.Helper()
+	root = t.TempDir()
+	db, err := database.OpenPath(filepath.Join(root, "media.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	h = newTestHandler(t, root, func(cfg *MediaHandlerConfig) { cfg.Database = db })
+	return h, db, root
+}
+
+func TestProcessFile_UsesRadarrIdentity(t *testing.T) {
+	h, db, root := newArrHandler(t)
+	year := 1995
+	require.NoError(t, db.UpsertArrGrab(&database.ArrGrab{
+		DownloadID:   "ABC123",
+		Source:       database.ArrSourceRadarr,
+		EventType:    "Grab",
+		ReleaseTitle: "Heat.1995.1080p.BluRay.x264-GRP",
+		Title:        "Heat",
+		Year:         &year,
+	}))
+
+	src := filepath.Join(root, "movie-downloads", "Heat.1995.1080p.BluRay.x264-GRP", "grp-ht-1080p.mkv")
+	writeVideo(t, src)
+	h.processFile(src)
+
+	assert.FileExists(t, filepath.Join(root, "Movies", "Heat (1995)", "Heat (1995).mkv"))
+	rows, err := db.QueryDecisions(database.QueryFilter{})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "radarr", rows[0].ParseMethod)
+	assert.Equal(t, "abc123", rows[0].ArrDownloadID)
+	assert.Equal(t, "Heat", rows[0].ParsedTitle)
+}
+
+func TestProcessFile_UsesSonarrEpisodeIdentity(t *testing.T) {
+	h, db, root := newArrHandler(t)
+	year, season := 2024, 1
+	require.NoError(t, db.UpsertArrGrab(&database.ArrGrab{
+		DownloadID:   "def456",
+		Source:       database.ArrSourceSonarr,
+		EventType:    "Grab",
+		ReleaseTitle: "Shogun.2024.S01E03.1080p.WEB.h264-GRP",
+		Title:        "Shogun",
+		Year:         &year,
+		Season:       &season,
+		Episodes:     []int{3},
+	}))
+
+	// Nothing in the name says which episode this is; Sonarr does.
+	src := filepath.Join(root, "tv-downloads", "Shogun.2024.S01E03.1080p.WEB.h264-GRP", "grp-sg103.mkv")
+	writeVideo(t, src)
+	h.processFile(src)
+
+	assert.FileExists(t, filepath.Join(root, "TV", "Shogun (2024)", "Season 01", "Shogun (2024) S01E03.mkv"))
+	rows, err := db.QueryDecisions(database.QueryFilter{})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "sonarr", rows[0].ParseMethod)
+	assert.Equal(t, "def456", rows[0].ArrDownloadID)
+}
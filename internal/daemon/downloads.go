@@ -0,0 +1,150 @@
+package daemon
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Nomadcxx/jellywatch/internal/downloads"
+	"github.com/Nomadcxx/jellywatch/internal/extract"
+	"github.com/Nomadcxx/jellywatch/internal/logging"
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+	"github.com/Nomadcxx/jellywatch/internal/rules"
+)
+
+// downloadJobFor returns the download client job holding path, or nil
+// when no client is configured or none knows the file. An unreachable
+// client is logged once per error and treated as not knowing it, so the
+// file is organized the way it would be without clients.
+func (h *MediaHandler) downloadJobFor(path string) *downloads.Job {
+	if h.downloads == nil {
+		return nil
+	}
+	job, err := h.downloads.Find(path)
+	if err != nil && h.shouldLogError("download clients", err.Error()) {
+		h.logger.Warn("handler", "Download client lookup failed",
+			logging.F("error", err.Error()))
+	}
+	return job
+}
+
+// downloadHold says why a file from job has to wait, or "" when it can be
+// organized now. Waiting files are picked up again by the periodic scan.
+// Seeding only holds files the organize would take from the client.
+func (h *MediaHandler) downloadHold(job *downloads.Job, path string, rule *rules.Rule) string {
+	switch {
+	case job == nil:
+		return ""
+	case !job.Complete:
+		return "download not finished"
+	case job.Seeding && h.takesSource(path, rule):
+		return "torrent has not reached its seeding limit"
+	}
+	return ""
+}
+
+// takesSource reports whether organizing path, under rule, removes it from
+// the download folder: the backend moves it, or it is an archive volume
+// deleted after extraction. Videos unpacked from an archive are the
+// daemon's own copies.
+func (h *MediaHandler) takesSource(path string, rule *rules.Rule) bool {
+	if isExtractStagingPath(path) {
+		return false
+	}
+	if h.extractor != nil && extract.IsArchive(path) {
+		return !h.keepArchives
+	}
+	lib := ""
+	if rule != nil {
+		lib = rule.Library
+	}
+	orgs := h.organizersFor(rule)
+	return !orgs.tv.LeavesSource(lib) || !orgs.movie.LeavesSource(lib)
+}
+
+// jobNamePath is path renamed after the job holding it, for parsing a file
+// whose own name is obfuscated. It returns path when that name is fine or
+// the job has no name.
+func jobNamePath(path string, job *downloads.Job) string {
+	if job == nil || job.Name == "" {
+		return path
+	}
+	if !naming.IsObfuscatedFilename(filepath.Base(path)) && !IsObfuscatedSABFilename(path) {
+		return path
+	}
+	ext := filepath.Ext(path)
+	name := job.Name
+	if strings.EqualFold(filepath.Ext(name), ext) {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return filepath.Join(filepath.Dir(path), name+ext)
+}
+
+// downloadOrganized applies the client's after-organize action to job once
+// path, just organized, was its last video waiting to be.
+func (h *MediaHandler) downloadOrganized(job *downloads.Job, path string) {
+	// A torrent organized while seeding was linked or copied; pausing or
+	// removing it now would cut its seeding short, so the client's own
+	// share limit action takes over.
+	if job == nil || job.Seeding || !h.jobFinished(job, path) {
+		return
+	}
+	if err := h.downloads.Organized(job); err != nil {
+		h.logger.Warn("handler", "Download client after-organize action failed",
+			logging.F("client", job.Client),
+			logging.F("job", job.Name),
+			logging.F("error", err.Error()))
+	}
+}
+
+// jobFinished reports whether no video in job's folder other than path is
+// still waiting to be organized. Files the watch filters skip don't count;
+// files left in place by a link backend or keep_source count as done once
+// their organize succeeded, and so do archive volumes already unpacked.
+func (h *MediaHandler) jobFinished(job *downloads.Job, path string) bool {
+	info, err := os.Stat(job.Path)
+	if err != nil || !info.IsDir() {
+		return true
+	}
+	finished := true
+	_ = filepath.WalkDir(job.Path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || p == path || !h.IsMediaFile(p) {
+			return nil
+		}
+		if _, extra := naming.ParseExtra(p); extra {
+			return nil
+		}
+		// Volumes kept after extraction stay for the client to seed;
+		// the videos unpacked from them are what has to be organized.
+		if extract.IsArchive(p) && isExtractedVolume(p) {
+			return nil
+		}
+		size := int64(-1)
+		if fi, err := d.Info(); err == nil {
+			size = fi.Size()
+		}
+		if ok, _ := h.filters.Accept(p, size, ""); !ok {
+			return nil
+		}
+		if h.organizedBefore(p) {
+			return nil
+		}
+		finished = false
+		return fs.SkipAll
+	})
+	return finished
+}
+
+// organizedBefore reports whether the file at p, still in the download
+// folder, was already organized successfully.
+func (h *MediaHandler) organizedBefore(p string) bool {
+	if h.isLinkedSource(p) {
+		return true
+	}
+	if h.db == nil {
+		return false
+	}
+	d, err := h.db.GetMostRecentDecisionBySourcePath(p)
+	return err == nil && d != nil && d.OrganizeOutcome == "success"
+}
@@ -0,0 +1,151 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Nomadcxx/jellywatch/internal/downloads"
+	"github.com/Nomadcxx/jellywatch/internal/qbittorrent"
+	"github.com/Nomadcxx/jellywatch/internal/rules"
+	"github.com/Nomadcxx/jellywatch/internal/sabnzbd"
+	"github.com/Nomadcxx/jellywatch/internal/transfer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeQBittorrent serves one torrent and records the torrents paused.
+type fakeQBittorrent struct {
+	torrent qbittorrent.Torrent
+	paused  []string
+}
+
+func (f *fakeQBittorrent) tracker(t *testing.T) *downloads.Tracker {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/auth/login":
+			w.Write([]byte("Ok."))
+		case "/api/v2/torrents/info":
+			json.NewEncoder(w).Encode([]qbittorrent.Torrent{f.torrent})
+		case "/api/v2/app/preferences":
+			json.NewEncoder(w).Encode(qbittorrent.Preferences{MaxRatioEnabled: true, MaxRatio: 1})
+		case "/api/v2/torrents/stop":
+			require.NoError(t, r.ParseForm())
+			f.paused = append(f.paused, r.PostForm.Get("hashes"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	// A new tracker per call, so the test sees each torrent state without
+	// waiting out the tracker's cache.
+	return downloads.NewTracker(downloads.NewQBittorrent(
+		qbittorrent.NewClient(qbittorrent.Config{URL: server.URL}),
+		downloads.Options{AfterOrganize: downloads.AfterPause, RespectSeedLimits: true},
+	))
+}
+
+func TestProcessFile_WaitsForTorrentAndRoutesByCategory(t *testing.T) {
+	root := t.TempDir()
+	watch, movies, uhd := filepath.Join(root, "movie-downloads"), filepath.Join(root, "Movies"), filepath.Join(root, "Movies 4K")
+	require.NoError(t, os.MkdirAll(uhd, 0755))
+	engine, err := rules.New([]rules.Rule{{Categories: []string{"radarr-4k"}, Library: uhd, MediaType: rules.MediaTypeMovie}})
+	require.NoError(t, err)
+	h := newTestHandler(t, root, func(cfg *MediaHandlerConfig) {
+		cfg.MovieLibs = append(cfg.MovieLibs, uhd)
+		cfg.Rules = engine
+	})
+
+	release := filepath.Join(watch, "Dune.2021.2160p.UHD.BluRay.x265-GRP")
+	src := filepath.Join(release, "Dune.2021.2160p.UHD.BluRay.x265-GRP.mkv")
+	writeVideo(t, src)
+	fake := &fakeQBittorrent{torrent: qbittorrent.Torrent{
+		Hash: "dune", Name: filepath.Base(release), Category: "radarr-4k", ContentPath: release,
+		Progress: 0.6, State: "downloading", RatioLimit: -2, SeedingTimeLimit: -2,
+	}}
+
+	h.downloads = fake.tracker(t)
+	h.processFile(src)
+	assert.FileExists(t, src, "an unfinished torrent must not be organized")
+
+	fake.torrent.Progress, fake.torrent.State, fake.torrent.Ratio = 1, "uploading", 0.4
+	h.downloads = fake.tracker(t)
+	h.processFile(src)
+	assert.FileExists(t, src, "a torrent below its ratio must not be moved")
+	assert.Empty(t, fake.paused)
+
+	fake.torrent.Ratio = 1.2
+	h.downloads = fake.tracker(t)
+	h.processFile(src)
+	assert.FileExists(t, filepath.Join(uhd, "Dune (2021)", "Dune (2021).mkv"))
+	assert.NoDirExists(t, filepath.Join(movies, "Dune (2021)"))
+	assert.Equal(t, []string{"dune"}, fake.paused)
+}
+
+func TestProcessFile_ParsesObfuscatedFileByJobName(t *testing.T) {
+	h, watch, lib := newExtrasHandler(t, "")
+	release := filepath.Join(watch, "a3f9c2e17b")
+	src := filepath.Join(release, "SXvWQZqPGRTeZvy6oGudBsA2FUBH1HUd.mkv")
+	writeVideo(t, src)
+	require.True(t, IsObfuscatedSABFilename(src))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("mode") {
+		case "queue":
+			w.Write([]byte(`{"queue": {"slots": []}}`))
+		case "history":
+			json.NewEncoder(w).Encode(map[string]any{"history": map[string]any{"slots": []sabnzbd.HistorySlot{{
+				NzoID: "SABnzbd_nzo_1", Name: "Heat.1995.1080p.BluRay.x264-GRP", Category: "movies",
+				Status: sabnzbd.StatusCompleted, Storage: release,
+			}}}})
+		}
+	}))
+	defer server.Close()
+	h.downloads = downloads.NewTracker(downloads.NewSABnzbd(
+		sabnzbd.NewClient(sabnzbd.Config{URL: server.URL}), downloads.Options{}))
+
+	h.processFile(src)
+	assert.FileExists(t, filepath.Join(lib, "Heat (1995)", "Heat (1995).mkv"))
+}
+
+func TestProcessFile_PausesArchivedTorrentOnceExtracted(t *testing.T) {
+	h, fake, watch, lib := newExtractHandler(t, true)
+	release := filepath.Join(watch, "Show.S01E01.1080p.WEB-DL")
+	writeVolumes(t, release, "Show.S01E01.1080p.WEB-DL.rar", "Show.S01E01.1080p.WEB-DL.r00")
+	qbt := &fakeQBittorrent{torrent: qbittorrent.Torrent{
+		Hash: "show", Name: filepath.Base(release), ContentPath: release,
+		Progress: 1, State: "uploading", Ratio: 1.2, RatioLimit: -2, SeedingTimeLimit: -2,
+	}}
+	h.downloads = qbt.tracker(t)
+
+	h.processFile(filepath.Join(release, "Show.S01E01.1080p.WEB-DL.rar"))
+
+	require.Equal(t, 1, fake.calls)
+	assert.FileExists(t, filepath.Join(lib, "Show", "Season 01", "Show S01E01.mkv"))
+	assert.FileExists(t, filepath.Join(release, "Show.S01E01.1080p.WEB-DL.r00"), "kept volumes stay for seeding")
+	assert.Equal(t, []string{"show"}, qbt.paused, "kept volumes must not hold up the after-organize action")
+}
+
+func TestProcessFile_LinksSeedingTorrentWithoutWaiting(t *testing.T) {
+	root := t.TempDir()
+	watch, movies := filepath.Join(root, "movie-downloads"), filepath.Join(root, "Movies")
+	h := newTestHandler(t, root, func(cfg *MediaHandlerConfig) { cfg.Backend = transfer.BackendHardlink })
+
+	release := filepath.Join(watch, "Heat.1995.1080p.BluRay.x264-GRP")
+	src := filepath.Join(release, "Heat.1995.1080p.BluRay.x264-GRP.mkv")
+	writeVideo(t, src)
+	fake := &fakeQBittorrent{torrent: qbittorrent.Torrent{
+		Hash: "heat", Name: filepath.Base(release), ContentPath: release,
+		Progress: 1, State: "uploading", Ratio: 0.2, RatioLimit: -2, SeedingTimeLimit: -2,
+	}}
+	h.downloads = fake.tracker(t)
+
+	h.processFile(src)
+
+	assert.FileExists(t, filepath.Join(movies, "Heat (1995)", "Heat (1995).mkv"))
+	assert.FileExists(t, src, "a hardlinked source keeps seeding")
+	assert.Empty(t, fake.paused, "a torrent below its ratio is left seeding")
+}
@@ -0,0 +1,263 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/Nomadcxx/jellywatch/internal/daemon/ipc"
+	"github.com/Nomadcxx/jellywatch/internal/extract"
+	"github.com/Nomadcxx/jellywatch/internal/logging"
+	"github.com/Nomadcxx/jellywatch/internal/video"
+)
+
+// extractStageDir is created next to an archive set to hold what it
+// unpacks, so extraction stays on the download's volume and the organizer
+// can rename rather than copy. The leading dot keeps watch-folder scans
+// out of it; the handler feeds staged videos itself. A marker file beside
+// each set's folder records a finished extraction so kept archives aren't
+// unpacked again on every scan.
+const (
+	extractStageDir     = ".jellywatch-extract"
+	extractMarkerSuffix = ".extracted"
+)
+
+const extractOpCmd ipc.Command = "EXTRACT"
+
+var extractOpSeq atomic.Int64
+
+// SetOpRegistry reports archive extraction progress as ops in r, so the
+// WebUI can follow them at /api/v1/events/op/extract-<n>.
+func (h *MediaHandler) SetOpRegistry(r *ipc.OpRegistry) {
+	h.mu.Lock()
+	h.registry = r
+	h.mu.Unlock()
+}
+
+// isExtractStagingPath reports whether path is inside an extraction
+// staging folder.
+func isExtractStagingPath(path string) bool {
+	for _, part := range strings.Split(filepath.Clean(path), string(filepath.Separator)) {
+		if part == extractStageDir {
+			return true
+		}
+	}
+	return false
+}
+
+// extractArchive is the pipeline stage for archive volumes: once every
+// volume of path's set is on disk it unpacks the set and feeds each video
+// inside through processFile. Staged videos that don't get organized stay
+// put and are fed again the next time the set is seen. Archives are
+// removed afterwards unless keepArchives is set or a video is left over.
+func (h *MediaHandler) extractArchive(path string) {
+	set, err := extract.Detect(path)
+	if err != nil {
+		h.logger.Warn("handler", "Archive detection failed",
+			logging.F("path", path), logging.F("error", err.Error()))
+		return
+	}
+	if !set.Complete() {
+		h.logger.Debug("handler", "Waiting for archive volumes",
+			logging.F("archive", set.Name),
+			logging.F("missing", strings.Join(set.Missing, ", ")))
+		return
+	}
+	// Every volume of a set triggers this; the first one does the work.
+	if filepath.Clean(path) != set.First {
+		return
+	}
+
+	h.mu.Lock()
+	if _, busy := h.extracting[set.First]; busy {
+		h.mu.Unlock()
+		return
+	}
+	h.extracting[set.First] = struct{}{}
+	h.mu.Unlock()
+	defer func() {
+		h.mu.Lock()
+		delete(h.extracting, set.First)
+		h.mu.Unlock()
+	}()
+
+	root := filepath.Join(set.Dir, extractStageDir)
+	stage := filepath.Join(root, set.Name)
+	marker := stage + extractMarkerSuffix
+
+	if _, err := os.Stat(marker); err != nil {
+		if h.dryRun {
+			h.logger.Info("handler", "Dry run - would extract",
+				logging.F("archive", filepath.Base(set.First)),
+				logging.F("volumes", len(set.Volumes)))
+			return
+		}
+		if err := h.unpack(set, stage); err != nil {
+			_ = os.RemoveAll(stage)
+			_ = os.Remove(root)
+			if h.shouldLogError(set.First, err.Error()) {
+				h.logger.Error("handler", "Archive extraction failed", err,
+					logging.F("archive", filepath.Base(set.First)))
+			}
+			h.stats.RecordError()
+			return
+		}
+		if err := os.WriteFile(marker, nil, 0o644); err != nil {
+			h.logger.Warn("handler", "Failed to write extraction marker",
+				logging.F("path", marker), logging.F("error", err.Error()))
+		}
+	}
+
+	left := 0
+	for _, staged := range stagedVideos(stage) {
+		if h.feedStaged(set, stage, staged) {
+			left++
+		}
+	}
+	if left > 0 {
+		h.logger.Info("handler", "Extracted videos left in staging",
+			logging.F("archive", set.Name), logging.F("count", left))
+		return
+	}
+	_ = os.RemoveAll(stage)
+
+	if h.keepArchives {
+		return
+	}
+	for _, vol := range set.Volumes {
+		if _, err := h.trash.Remove(vol, "extracted archive"); err != nil && !os.IsNotExist(err) {
+			h.logger.Warn("handler", "Failed to remove archive volume",
+				logging.F("path", vol), logging.F("error", err.Error()))
+			return
+		}
+	}
+	_ = os.Remove(marker)
+	_ = os.Remove(root)
+	h.logger.Info("handler", "Removed extracted archive",
+		logging.F("archive", set.Name), logging.F("volumes", len(set.Volumes)))
+
+	// The release folder goes too once nothing is left in it.
+	for _, watchRoot := range h.allWatchPaths() {
+		if filepath.Clean(watchRoot) == set.Dir {
+			return
+		}
+	}
+	if err := os.Remove(set.Dir); err == nil {
+		h.logger.Info("handler", "Cleaned up source directory", logging.F("dir", set.Dir))
+	}
+}
+
+// isExtractedVolume reports whether path is a volume of an archive set
+// whose extraction has finished.
+func isExtractedVolume(path string) bool {
+	set, err := extract.Detect(path)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(set.Dir, extractStageDir, set.Name) + extractMarkerSuffix)
+	return err == nil
+}
+
+// unpack extracts set into a fresh stage folder, reporting progress as an
+// op when a registry is wired.
+func (h *MediaHandler) unpack(set *extract.Set, stage string) error {
+	if err := os.RemoveAll(stage); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(stage, 0o755); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	reg := h.registry
+	h.mu.Unlock()
+	id := fmt.Sprintf("extract-%d", extractOpSeq.Add(1))
+	var op *ipc.Op
+	if reg != nil {
+		op, _ = reg.Start(id, extractOpCmd, func() {})
+	}
+	frame := func(phase string, pct int) {
+		if op == nil {
+			return
+		}
+		op.Frames.Append(ipc.Frame{
+			ID:      id,
+			Type:    ipc.FrameProgress,
+			Phase:   phase,
+			Msg:     filepath.Base(set.First),
+			Current: pct,
+			Total:   100,
+		})
+	}
+
+	h.logger.Info("handler", "Extracting archive",
+		logging.F("archive", filepath.Base(set.First)),
+		logging.F("volumes", len(set.Volumes)),
+		logging.F("backend", h.extractor.Name()))
+	frame("extracting", 0)
+	err := h.extractor.Extract(h.ctx, set, stage, func(pct int) { frame("extracting", pct) })
+
+	if op != nil {
+		if err != nil {
+			op.Frames.Append(ipc.Frame{ID: id, Type: ipc.FrameError, Msg: err.Error()})
+			reg.Finish(id, "error", err)
+		} else {
+			data, _ := json.Marshal(map[string]any{
+				"archive": set.First,
+				"volumes": len(set.Volumes),
+				"stage":   stage,
+			})
+			op.Frames.Append(ipc.Frame{ID: id, Type: ipc.FrameDone, Data: data})
+			reg.Finish(id, "done", nil)
+		}
+	}
+	return err
+}
+
+// feedStaged runs one staged video through the normal pipeline and
+// reports whether it is still waiting in the stage afterwards. The watch
+// filters judge it as if it sat in the release folder; a rejected video
+// is dropped rather than left behind.
+func (h *MediaHandler) feedStaged(set *extract.Set, stage, staged string) bool {
+	rel, _ := filepath.Rel(stage, staged)
+	info, err := os.Stat(staged)
+	if err != nil {
+		return false
+	}
+	if ok, reason := h.filters.Accept(filepath.Join(set.Dir, rel), info.Size(), ""); !ok {
+		h.logger.Info("handler", "Skipping extracted file",
+			logging.F("path", rel), logging.F("reason", reason))
+		_ = os.Remove(staged)
+		return false
+	}
+
+	if !h.isLinkedSource(staged) {
+		h.processFile(staged)
+	}
+	if _, err := os.Stat(staged); err != nil {
+		return false
+	}
+	// A link backend leaves the staged copy behind; it's ours, not the
+	// download client's, so it can go once it is in the library.
+	if h.isLinkedSource(staged) {
+		_ = os.Remove(staged)
+		return false
+	}
+	return true
+}
+
+// stagedVideos lists the videos under stage.
+func stagedVideos(stage string) []string {
+	var out []string
+	_ = filepath.WalkDir(stage, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && video.IsVideo(path) {
+			out = append(out, path)
+		}
+		return nil
+	})
+	return out
+}
@@ -0,0 +1,123 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellywatch/internal/daemon/ipc"
+	"github.com/Nomadcxx/jellywatch/internal/extract"
+	"github.com/Nomadcxx/jellywatch/internal/watcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeExtractor "unpacks" a set into an episode named after it.
+type fakeExtractor struct {
+	calls int
+}
+
+func (f *fakeExtractor) Name() string { return "fake" }
+
+func (f *fakeExtractor) Extract(_ context.Context, set *extract.Set, dest string, progress func(int)) error {
+	f.calls++
+	progress(50)
+	if err := os.WriteFile(filepath.Join(dest, set.Name+".mkv"), []byte("episode"), 0644); err != nil {
+		return err
+	}
+	progress(100)
+	return os.WriteFile(filepath.Join(dest, set.Name+".nfo"), []byte("info"), 0644)
+}
+
+func newExtractHandler(t *testing.T, keep bool) (h *MediaHandler, fake *fakeExtractor, watch, lib string) {
+	t.Helper()
+	root := t.TempDir()
+	fake = &fakeExtractor{}
+	h = newTestHandler(t, root, func(cfg *MediaHandlerConfig) {
+		cfg.Extractor = fake
+		cfg.KeepArchives = keep
+	})
+	return h, fake, filepath.Join(root, "tv-downloads"), filepath.Join(root, "TV")
+}
+
+func writeVolumes(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	for _, name := range names {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("volume"), 0644))
+	}
+}
+
+func TestProcessFile_ExtractsArchiveAndRemovesVolumes(t *testing.T) {
+	h, fake, watch, lib := newExtractHandler(t, false)
+	reg := ipc.NewOpRegistry()
+	h.SetOpRegistry(reg)
+
+	release := filepath.Join(watch, "Show.S01E01.1080p.WEB-DL")
+	writeVolumes(t, release, "Show.S01E01.1080p.WEB-DL.rar", "Show.S01E01.1080p.WEB-DL.r00")
+
+	// Later volumes wait for the first.
+	h.processFile(filepath.Join(release, "Show.S01E01.1080p.WEB-DL.r00"))
+	assert.Zero(t, fake.calls)
+
+	h.processFile(filepath.Join(release, "Show.S01E01.1080p.WEB-DL.rar"))
+	require.Equal(t, 1, fake.calls)
+
+	var organized []string
+	_ = filepath.Walk(lib, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			organized = append(organized, path)
+		}
+		return nil
+	})
+	require.Len(t, organized, 1, "extracted episode should be organized")
+	assert.Equal(t, ".mkv", filepath.Ext(organized[0]))
+	assert.NoDirExists(t, release, "volumes, staging and release folder should be gone")
+	assert.DirExists(t, watch)
+
+	ops := reg.List()
+	require.Len(t, ops, 1)
+	assert.Equal(t, string(extractOpCmd), ops[0].Cmd)
+	assert.Equal(t, "done", ops[0].State)
+	assert.Equal(t, 100, ops[0].Current)
+}
+
+func TestProcessFile_ExtractWaitsForMissingVolumesAndKeepsSource(t *testing.T) {
+	h, fake, watch, lib := newExtractHandler(t, true)
+
+	release := filepath.Join(watch, "Show.S01E02.1080p.WEB-DL")
+	writeVolumes(t, release, "Show.S01E02.1080p.WEB-DL.part1.rar", "Show.S01E02.1080p.WEB-DL.part3.rar")
+	first := filepath.Join(release, "Show.S01E02.1080p.WEB-DL.part1.rar")
+
+	h.processFile(first)
+	assert.Zero(t, fake.calls, "part2 is missing")
+
+	writeVolumes(t, release, "Show.S01E02.1080p.WEB-DL.part2.rar")
+	h.processFile(first)
+	require.Equal(t, 1, fake.calls)
+	assert.FileExists(t, first, "keep_source leaves the archives")
+	assert.FileExists(t, filepath.Join(release, extractStageDir, "Show.S01E02.1080p.WEB-DL"+extractMarkerSuffix))
+	assert.NoDirExists(t, filepath.Join(release, extractStageDir, "Show.S01E02.1080p.WEB-DL"))
+
+	entries, err := os.ReadDir(lib)
+	require.NoError(t, err)
+	assert.NotEmpty(t, entries, "extracted episode should be organized")
+
+	// A later scan of the kept archive doesn't unpack it again.
+	h.processFile(first)
+	assert.Equal(t, 1, fake.calls)
+}
+
+func TestHandleFileEvent_IgnoresExtractStaging(t *testing.T) {
+	h, _, watch, _ := newExtractHandler(t, true)
+	h.debounceTime = time.Hour
+
+	staged := filepath.Join(watch, "Show", extractStageDir, "Show", "Show.S01E01.mkv")
+	require.NoError(t, h.HandleFileEvent(watcher.FileEvent{Type: watcher.EventCreate, Path: staged}))
+	assert.Empty(t, h.pending)
+
+	require.NoError(t, h.HandleFileEvent(watcher.FileEvent{Type: watcher.EventCreate, Path: filepath.Join(watch, "Show", "show.r01")}))
+	assert.Len(t, h.pending, 1, "archive volumes are media when extraction is on")
+}
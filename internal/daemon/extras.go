@@ -0,0 +1,147 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/logging"
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+	"github.com/Nomadcxx/jellywatch/internal/organizer"
+	"github.com/Nomadcxx/jellywatch/internal/rules"
+)
+
+// decisionMediaExtra marks the parse decision of a bonus video. Parser
+// drift repair only revisits "movie" and "tv" rows, so extras, which keep
+// their own names, are never renamed after the fact.
+const decisionMediaExtra = "extra"
+
+// processExtra is the pipeline stage for trailers, featurettes and other
+// bonus videos: it places path beside the movie or show it belongs to
+// instead of organizing it as one. Extras sitting loose in a watch root
+// have nothing to belong to and are left alone, as are all extras when
+// the layout is naming.ExtrasSkip.
+func (h *MediaHandler) processExtra(path string, extra naming.ExtraInfo, rule *rules.Rule) {
+	filename := filepath.Base(path)
+	if h.extrasLayout == naming.ExtrasSkip {
+		h.logger.Info("handler", "Leaving extra in place",
+			logging.F("filename", filename),
+			logging.F("type", string(extra.Type)))
+		return
+	}
+	owner := strings.TrimSuffix(extra.MainPath, filepath.Ext(extra.MainPath))
+	for _, root := range h.allWatchPaths() {
+		if filepath.Clean(root) == owner {
+			h.logger.Info("handler", "Leaving extra outside a release folder",
+				logging.F("filename", filename),
+				logging.F("path", path))
+			return
+		}
+	}
+
+	// An extra with a season marker is TV unless it came from a movie
+	// watch folder.
+	hint := h.getSourceHint(path)
+	isTV := naming.IsTVEpisodeFromPath(extra.MainPath, hint) ||
+		(hint != naming.SourceMovie && extra.Season >= 0)
+	if rule != nil && rule.MediaType != "" {
+		isTV = rule.MediaType == rules.MediaTypeTV
+	}
+	orgs := h.organizersFor(rule)
+
+	var decisionID int64
+	if h.db != nil {
+		h.parseDecisionMu.Lock()
+		var insertErr error
+		decisionID, insertErr = h.db.InsertDecision(database.ParseDecision{
+			SourcePath:       path,
+			SourceFilename:   filename,
+			EventAt:          time.Now().UTC(),
+			MediaTypeGuessed: decisionMediaExtra,
+		})
+		h.parseDecisionMu.Unlock()
+		if insertErr != nil {
+			h.logger.Warn("handler", "failed to insert parse decision",
+				logging.F("filename", filename),
+				logging.F("error", insertErr.Error()))
+		}
+	}
+
+	var result *organizer.OrganizationResult
+	var err error
+	if isTV {
+		result, err = h.organizeTVExtra(orgs, path, extra, rule)
+	} else {
+		result, err = h.organizeMovieExtra(orgs, path, extra, rule)
+	}
+	h.updateDecisionOrganize(decisionID, result, err)
+
+	if err == nil && result != nil && result.Error != nil {
+		err = result.Error
+	}
+	switch {
+	case err != nil:
+		if h.shouldLogError(path, err.Error()) {
+			h.logger.Error("handler", "Extra not placed", err, logging.F("filename", filename))
+		}
+		h.stats.RecordError()
+	case result.Skipped:
+		h.logger.Info("handler", "Skipped extra",
+			logging.F("filename", filename),
+			logging.F("reason", result.SkipReason))
+	case result.Success:
+		h.logger.Info("handler", "Placed extra",
+			logging.F("source", filename),
+			logging.F("target", result.TargetPath),
+			logging.F("type", string(extra.Type)))
+		h.rememberLinkedSource(path, result)
+		h.cleanupSourceDir(path, result.SourcePreserved)
+	}
+}
+
+// organizeTVExtra places a TV extra in its show's or season's folders.
+func (h *MediaHandler) organizeTVExtra(orgs ruleOrganizers, path string, extra naming.ExtraInfo, rule *rules.Rule) (*organizer.OrganizationResult, error) {
+	if len(h.tvLibraries) == 0 {
+		return nil, fmt.Errorf("no TV libraries configured")
+	}
+	show, err := extra.Show()
+	if err != nil {
+		return nil, err
+	}
+	h.aliasFor(database.AliasMediaSeries, extra.MainPath, show.Title).ApplyTV(show)
+	retitleTV(rule, show)
+	if rule != nil && rule.Library != "" {
+		return orgs.tv.OrganizeTVExtra(path, rule.Library, *show, extra)
+	}
+	return orgs.tv.OrganizeTVExtraAuto(path, *show, extra, func(p string) (int64, error) {
+		info, err := os.Stat(p)
+		if err != nil {
+			return 0, err
+		}
+		return info.Size(), nil
+	})
+}
+
+// organizeMovieExtra places a movie extra in its movie's folder.
+func (h *MediaHandler) organizeMovieExtra(orgs ruleOrganizers, path string, extra naming.ExtraInfo, rule *rules.Rule) (*organizer.OrganizationResult, error) {
+	if len(h.movieLibs) == 0 {
+		return nil, fmt.Errorf("no movie libraries configured")
+	}
+	targetLib := h.movieLibs[0]
+	if rule != nil && rule.Library != "" {
+		targetLib = rule.Library
+	}
+	movie, err := naming.ParseMovieFromPath(extra.MainPath)
+	if err != nil {
+		return nil, err
+	}
+	h.aliasFor(database.AliasMediaMovie, extra.MainPath, movie.Title).ApplyMovie(movie)
+	retitleMovie(rule, movie)
+	if !h.checkTargetHealth(targetLib) {
+		return nil, fmt.Errorf("target library unhealthy: %s", targetLib)
+	}
+	return orgs.movie.OrganizeMovieExtra(path, targetLib, *movie, extra)
+}
@@ -0,0 +1,63 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newExtrasHandler(t *testing.T, layout naming.ExtrasLayout) (h *MediaHandler, watch, lib string) {
+	t.Helper()
+	root := t.TempDir()
+	h = newTestHandler(t, root, func(cfg *MediaHandlerConfig) { cfg.ExtrasLayout = layout })
+	return h, filepath.Join(root, "movie-downloads"), filepath.Join(root, "Movies")
+}
+
+func TestProcessFile_PlacesExtrasBesideMovie(t *testing.T) {
+	h, watch, lib := newExtrasHandler(t, "")
+
+	release := filepath.Join(watch, "Heat.1995.1080p.BluRay-GRP")
+	main := filepath.Join(release, "Heat.1995.1080p.BluRay-GRP.mkv")
+	featurette := filepath.Join(release, "Featurettes", "Making Of.mkv")
+	trailer := filepath.Join(release, "Heat.1995.Trailer.1080p.BluRay-GRP.mkv")
+	writeVideo(t, main)
+	writeVideo(t, featurette)
+	writeVideo(t, trailer)
+
+	h.processFile(main)
+	h.processFile(featurette)
+	h.processFile(trailer)
+
+	movieDir := filepath.Join(lib, "Heat (1995)")
+	assert.FileExists(t, filepath.Join(movieDir, "Heat (1995).mkv"))
+	assert.FileExists(t, filepath.Join(movieDir, "featurettes", "Making Of.mkv"))
+	assert.FileExists(t, filepath.Join(movieDir, "trailers", "Trailer.mkv"))
+	assert.NoDirExists(t, release, "release folder should be cleaned up once its extras are placed")
+}
+
+func TestProcessFile_LeavesExtras(t *testing.T) {
+	h, watch, lib := newExtrasHandler(t, naming.ExtrasSkip)
+
+	// The skip layout leaves extras where they are, rather than
+	// organizing them as movies of their own.
+	featurette := filepath.Join(watch, "Heat.1995.1080p.BluRay-GRP", "Featurettes", "Making Of.mkv")
+	writeVideo(t, featurette)
+	h.processFile(featurette)
+	assert.FileExists(t, featurette)
+	assert.NoDirExists(t, filepath.Join(lib, "Making Of"))
+
+	// A loose extra in the watch root belongs to nothing.
+	h, watch, lib = newExtrasHandler(t, naming.ExtrasFolders)
+	loose := filepath.Join(watch, "trailer.mp4")
+	writeVideo(t, loose)
+	h.processFile(loose)
+	assert.FileExists(t, loose)
+
+	entries, err := os.ReadDir(lib)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -15,14 +15,21 @@ import (
 	"github.com/Nomadcxx/jellywatch/internal/activity"
 	"github.com/Nomadcxx/jellywatch/internal/ai"
 	"github.com/Nomadcxx/jellywatch/internal/config"
+	"github.com/Nomadcxx/jellywatch/internal/daemon/ipc"
 	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/downloads"
+	"github.com/Nomadcxx/jellywatch/internal/extract"
+	"github.com/Nomadcxx/jellywatch/internal/ingest"
 	"github.com/Nomadcxx/jellywatch/internal/jellyfin"
 	"github.com/Nomadcxx/jellywatch/internal/logging"
 	"github.com/Nomadcxx/jellywatch/internal/naming"
 	"github.com/Nomadcxx/jellywatch/internal/notify"
 	"github.com/Nomadcxx/jellywatch/internal/organizer"
+	"github.com/Nomadcxx/jellywatch/internal/probe"
+	"github.com/Nomadcxx/jellywatch/internal/rules"
 	"github.com/Nomadcxx/jellywatch/internal/sonarr"
 	"github.com/Nomadcxx/jellywatch/internal/transfer"
+	"github.com/Nomadcxx/jellywatch/internal/trash"
 	"github.com/Nomadcxx/jellywatch/internal/watcher"
 )
 
@@ -61,6 +68,7 @@ type MediaHandler struct {
 	aiCache          *ai.Cache
 	aiConfig         config.AIConfig
 	aiRateLimiter    *AIRateLimiter
+	volumeLimiter    *transfer.VolumeLimiter
 	enhanceLogger    *EnhanceLogger
 	aiEnabled        bool
 	// loggedErrors dedupes repeat ERROR emissions for the same (path, error)
@@ -82,6 +90,33 @@ type MediaHandler struct {
 	// deterministic, non-recoverable parse/organize errors so the periodic
 	// scanner doesn't burn cycles re-attempting them every 5 minutes.
 	unparseableCache *NegativeCache
+	// linked tracks seeding sources already hardlinked/reflinked into a
+	// library so periodic scans don't reprocess them.
+	linked *linkedSources
+	// prober inspects downloads with ffprobe before they are organized;
+	// nil disables the integrity gate.
+	prober *probe.Prober
+	// trash keeps what cleanup removes from download folders; nil deletes.
+	trash *trash.Bin
+	// rules route, ignore or re-title downloads before they are parsed.
+	// ruleOrganizers holds organizers for rules that change keep_source
+	// or the transfer backend.
+	rules          *rules.Engine
+	ruleOrganizers map[*rules.Rule]ruleOrganizers
+	// extractor unpacks archive releases before they are organized; nil
+	// leaves archives alone. extracting holds the first volume of each set
+	// being unpacked, and registry, when set, receives progress ops.
+	extractor    extract.Backend
+	keepArchives bool
+	filters      *ingest.Filters
+	extracting   map[string]struct{}
+	registry     *ipc.OpRegistry
+	// extrasLayout is how trailers, featurettes and other bonus videos are
+	// placed; naming.ExtrasSkip leaves them in the download folder.
+	extrasLayout naming.ExtrasLayout
+	// downloads asks qBittorrent and SABnzbd about the job a file came
+	// from; nil leaves completion to file events and path heuristics.
+	downloads *downloads.Tracker
 }
 
 type PendingItem struct {
@@ -193,6 +228,10 @@ type MediaHandlerConfig struct {
 	DryRun          bool
 	Timeout         time.Duration
 	Backend         transfer.Backend
+	// LibraryBackends overrides Backend for individual library roots
+	// (e.g. hardlink for a library on the same disk as the torrent client).
+	LibraryBackends map[string]transfer.Backend
+	AnimeLibraries  []string // TV library roots flagged as anime
 	NotifyManager   *notify.Manager
 	Logger          *logging.Logger
 	TargetUID       int
@@ -216,6 +255,33 @@ type MediaHandlerConfig struct {
 	// failures and long no-progress timeouts. <=0 disables the cap.
 	// Default (when zero) is 2.
 	TransferConcurrencyPerVolume int
+	// LibraryTemplates maps library roots to naming templates that replace
+	// the built-in Jellyfin layout there.
+	LibraryTemplates map[string]naming.Templates
+	// Prober, when set, refuses downloads whose duration or stream layout
+	// shows they are truncated or corrupt.
+	Prober *probe.Prober
+	// Trash, when enabled, keeps replaced library files and download junk
+	// in a recycle bin instead of deleting them.
+	Trash *trash.Bin
+	// Rules are checked against every download before it is parsed.
+	Rules *rules.Engine
+	// Extractor, when set, unpacks complete archive sets into a staging
+	// folder and organizes the videos inside. KeepArchives leaves the
+	// volumes in place afterwards. Filters are the watch-folder filters,
+	// applied to extracted videos as if they sat beside their archive.
+	Extractor    extract.Backend
+	KeepArchives bool
+	Filters      *ingest.Filters
+	// ExtrasLayout places bonus videos beside the movie or show they
+	// belong to. The zero value uses Jellyfin's extras folders.
+	ExtrasLayout naming.ExtrasLayout
+	// ResolutionVersions keeps movies at each resolution side by side.
+	ResolutionVersions bool
+	// Downloads, when set, holds files until their torrent or Usenet job
+	// is complete (and seeded, if configured), matches rules against the
+	// job's category and parses obfuscated files by the job's name.
+	Downloads *downloads.Tracker
 }
 
 func NewMediaHandler(cfg MediaHandlerConfig) (*MediaHandler, error) {
@@ -264,19 +330,62 @@ func NewMediaHandler(cfg MediaHandlerConfig) (*MediaHandler, error) {
 	}
 	volumeLimiter := transfer.NewVolumeLimiter(volumeCap)
 
-	wrapTransferer := func() (transfer.Transferer, error) {
-		base, err := transfer.New(cfg.Backend)
+	wrapTransferer := func(backend transfer.Backend) (transfer.Transferer, error) {
+		base, err := transfer.New(backend)
 		if err != nil {
 			return nil, err
 		}
 		return transfer.NewVolumeLimitedTransferer(base, volumeLimiter), nil
 	}
 
-	tvTransferer, err := wrapTransferer()
+	// LibraryBackends keys and library roots are matched after
+	// filepath.Clean so "/mnt/TV/" in config still applies to "/mnt/TV".
+	libraryBackends := make(map[string]transfer.Backend, len(cfg.LibraryBackends))
+	for lib, backend := range cfg.LibraryBackends {
+		libraryBackends[filepath.Clean(lib)] = backend
+	}
+
+	// libraryTransferers builds one wrapped transferer per library that
+	// overrides the default backend.
+	libraryTransferers := func(libs []string) ([]func(*organizer.Organizer), error) {
+		var opts []func(*organizer.Organizer)
+		for _, lib := range libs {
+			backend, ok := libraryBackends[filepath.Clean(lib)]
+			if !ok || backend == cfg.Backend {
+				continue
+			}
+			t, err := wrapTransferer(backend)
+			if err != nil {
+				return nil, fmt.Errorf("library %s: %w", lib, err)
+			}
+			cfg.Logger.Info("handler", "Library transfer backend override",
+				logging.F("library", lib),
+				logging.F("backend", backend.String()))
+			opts = append(opts, organizer.WithLibraryTransferer(lib, t))
+		}
+		return opts, nil
+	}
+
+	// libraryTemplates names files in the given libraries after their
+	// configured templates, reading provider IDs from the database.
+	libraryTemplates := func(libs []string) []func(*organizer.Organizer) {
+		var opts []func(*organizer.Organizer)
+		for _, lib := range libs {
+			if tmpl, ok := cfg.LibraryTemplates[lib]; ok {
+				opts = append(opts, organizer.WithLibraryTemplates(lib, tmpl))
+			}
+		}
+		if len(opts) > 0 && cfg.Database != nil {
+			opts = append(opts, organizer.WithProviderIDDatabase(cfg.Database))
+		}
+		return opts
+	}
+
+	tvTransferer, err := wrapTransferer(cfg.Backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build TV transferer: %w", err)
 	}
-	movieTransferer, err := wrapTransferer()
+	movieTransferer, err := wrapTransferer(cfg.Backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build Movie transferer: %w", err)
 	}
@@ -290,7 +399,14 @@ func NewMediaHandler(cfg MediaHandlerConfig) (*MediaHandler, error) {
 		organizer.WithDeferredQueue(cfg.DeferredQueue),
 	}
 	if cfg.SonarrClient != nil {
-		tvOrgOpts = append(tvOrgOpts, organizer.WithSonarrClient(cfg.SonarrClient))
+		resolver := sonarr.NewAbsoluteResolver(cfg.SonarrClient)
+		tvOrgOpts = append(tvOrgOpts,
+			organizer.WithSonarrClient(cfg.SonarrClient),
+			organizer.WithAbsoluteResolver(resolver),
+			organizer.WithEpisodeTitleResolver(resolver))
+	}
+	for _, lib := range cfg.AnimeLibraries {
+		tvOrgOpts = append(tvOrgOpts, organizer.WithAnimeLibrary(lib))
 	}
 	if cfg.JellyfinClient != nil {
 		tvOrgOpts = append(tvOrgOpts, organizer.WithJellyfinClient(cfg.JellyfinClient, cfg.PlaybackSafety))
@@ -298,6 +414,16 @@ func NewMediaHandler(cfg MediaHandlerConfig) (*MediaHandler, error) {
 	if cfg.TargetUID >= 0 || cfg.TargetGID >= 0 || cfg.FileMode != 0 || cfg.DirMode != 0 {
 		tvOrgOpts = append(tvOrgOpts, organizer.WithPermissions(cfg.TargetUID, cfg.TargetGID, cfg.FileMode, cfg.DirMode))
 	}
+	tvLibOpts, err := libraryTransferers(cfg.TVLibraries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build TV library transferers: %w", err)
+	}
+	tvOrgOpts = append(tvOrgOpts, tvLibOpts...)
+	tvOrgOpts = append(tvOrgOpts, libraryTemplates(cfg.TVLibraries)...)
+	if cfg.Database != nil {
+		tvOrgOpts = append(tvOrgOpts, organizer.WithJournal(cfg.Database))
+	}
+	tvOrgOpts = append(tvOrgOpts, organizer.WithTrash(cfg.Trash))
 	tvOrganizer, err := organizer.NewOrganizer(cfg.TVLibraries, tvOrgOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create TV organizer: %w", err)
@@ -317,11 +443,65 @@ func NewMediaHandler(cfg MediaHandlerConfig) (*MediaHandler, error) {
 	if cfg.TargetUID >= 0 || cfg.TargetGID >= 0 || cfg.FileMode != 0 || cfg.DirMode != 0 {
 		movieOrgOpts = append(movieOrgOpts, organizer.WithPermissions(cfg.TargetUID, cfg.TargetGID, cfg.FileMode, cfg.DirMode))
 	}
+	movieLibOpts, err := libraryTransferers(cfg.MovieLibs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Movie library transferers: %w", err)
+	}
+	movieOrgOpts = append(movieOrgOpts, movieLibOpts...)
+	movieOrgOpts = append(movieOrgOpts, libraryTemplates(cfg.MovieLibs)...)
+	if cfg.Database != nil {
+		movieOrgOpts = append(movieOrgOpts, organizer.WithJournal(cfg.Database))
+	}
+	movieOrgOpts = append(movieOrgOpts, organizer.WithTrash(cfg.Trash))
+	movieOrgOpts = append(movieOrgOpts, organizer.WithExtrasLayout(cfg.ExtrasLayout))
+	movieOrgOpts = append(movieOrgOpts, organizer.WithResolutionVersions(cfg.ResolutionVersions))
 	movieOrganizer, err := organizer.NewOrganizer(cfg.MovieLibs, movieOrgOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Movie organizer: %w", err)
 	}
 
+	// ruleOrganizer layers a rule's keep_source and transfer backend over
+	// the base options. Its backend replaces the per-library ones too.
+	ruleOrganizer := func(libs []string, base []func(*organizer.Organizer), r *rules.Rule) (*organizer.Organizer, error) {
+		opts := append([]func(*organizer.Organizer){}, base...)
+		if r.KeepSource != nil {
+			opts = append(opts, organizer.WithKeepSource(*r.KeepSource))
+		}
+		if r.TransferBackend != "" {
+			backend, err := transfer.LookupBackend(r.TransferBackend)
+			if err != nil {
+				return nil, err
+			}
+			t, err := wrapTransferer(backend)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, organizer.WithTransferer(t))
+			for _, lib := range libs {
+				opts = append(opts, organizer.WithLibraryTransferer(lib, t))
+			}
+		}
+		return organizer.NewOrganizer(libs, opts...)
+	}
+	ruleOrgs := make(map[*rules.Rule]ruleOrganizers)
+	for _, r := range cfg.Rules.Rules() {
+		if r.KeepSource == nil && r.TransferBackend == "" {
+			continue
+		}
+		orgs := ruleOrganizers{tv: tvOrganizer, movie: movieOrganizer}
+		if r.MediaType != rules.MediaTypeMovie {
+			if orgs.tv, err = ruleOrganizer(cfg.TVLibraries, tvOrgOpts, r); err != nil {
+				return nil, fmt.Errorf("rule %s: %w", r.Name, err)
+			}
+		}
+		if r.MediaType != rules.MediaTypeTV {
+			if orgs.movie, err = ruleOrganizer(cfg.MovieLibs, movieOrgOpts, r); err != nil {
+				return nil, fmt.Errorf("rule %s: %w", r.Name, err)
+			}
+		}
+		ruleOrgs[r] = orgs
+	}
+
 	handler := &MediaHandler{
 		tvOrganizer:       tvOrganizer,
 		movieOrganizer:    movieOrganizer,
@@ -352,11 +532,23 @@ func NewMediaHandler(cfg MediaHandlerConfig) (*MediaHandler, error) {
 		aiCache:           aiCache,
 		aiConfig:          cfg.AIConfig,
 		aiRateLimiter:     rateLimiter,
+		volumeLimiter:     volumeLimiter,
 		enhanceLogger:     enhanceLog,
 		aiEnabled:         cfg.AIEnabled,
 		loggedErrors:      make(map[string]struct{}),
 		targetHealthState: make(map[string]bool),
 		unparseableCache:  NewNegativeCache(),
+		linked:            newLinkedSources(),
+		prober:            cfg.Prober,
+		trash:             cfg.Trash,
+		rules:             cfg.Rules,
+		ruleOrganizers:    ruleOrgs,
+		extractor:         cfg.Extractor,
+		keepArchives:      cfg.KeepArchives,
+		filters:           cfg.Filters,
+		extracting:        make(map[string]struct{}),
+		extrasLayout:      cfg.ExtrasLayout,
+		downloads:         cfg.Downloads,
 	}
 	handler.ctx, handler.cancel = context.WithCancel(context.Background())
 	hydrateNegativeCacheFromDB(handler.unparseableCache, cfg.Database, cfg.Logger)
@@ -419,6 +611,10 @@ func (h *MediaHandler) HandleFileEvent(event watcher.FileEvent) error {
 	if !h.IsMediaFile(normalizedPath) {
 		return nil
 	}
+	// Extracted files are fed from their archive, not from events.
+	if isExtractStagingPath(normalizedPath) {
+		return nil
+	}
 
 	// Skip files that are already inside a library directory. This guards
 	// against misconfigured watch paths that overlap with library paths,
@@ -427,6 +623,14 @@ func (h *MediaHandler) HandleFileEvent(event watcher.FileEvent) error {
 		return nil
 	}
 
+	// Link backends leave the organized source in the watch folder for
+	// seeding; don't re-feed it to the parser on every scan.
+	if h.isLinkedSource(normalizedPath) {
+		h.logger.Debug("handler", "Skipping source already linked into library",
+			logging.F("path", normalizedPath))
+		return nil
+	}
+
 	if action == ingestDefer {
 		h.mu.Lock()
 		defer h.mu.Unlock()
@@ -501,6 +705,7 @@ func (h *MediaHandler) logEntry(
 	sonarrNotified bool,
 	radarrNotified bool,
 ) {
+	recordOrganizeOutcome(mediaType, result)
 	if h.activityLogger == nil {
 		return
 	}
@@ -700,11 +905,41 @@ func (h *MediaHandler) processFile(path string) {
 		return
 	}
 
+	// A download client that knows the file says whether it is done, and
+	// for torrents whether it has seeded enough to be moved. Its category
+	// is one of the things rules match on.
+	job := h.downloadJobFor(path)
+	category := ""
+	if job != nil {
+		category = job.Category
+	}
+	rule := h.matchRule(path, category)
+	if hold := h.downloadHold(job, path, rule); hold != "" {
+		fields := []logging.Field{
+			logging.F("path", path),
+			logging.F("client", job.Client),
+			logging.F("job", job.Name),
+			logging.F("reason", hold),
+		}
+		if h.shouldLogError(path, hold) {
+			h.logger.Info("handler", "Waiting on download client", fields...)
+		} else {
+			h.logger.Debug("handler", "Waiting on download client", fields...)
+		}
+		h.mu.Lock()
+		delete(h.pending, path)
+		delete(h.transientRetries, path)
+		h.mu.Unlock()
+		return
+	}
+
 	// Skip SAB temp-hash filenames (e.g. SXvWQZqPGRTe…BsA2FUBH1HUd.mkv).
 	// SAB renames these to the proper release name when post-processing
 	// finishes; the watcher/scanner will pick up the renamed file. Record in
-	// the negative cache so repeated periodic scans become a no-op.
-	if IsObfuscatedSABFilename(path) {
+	// the negative cache so repeated periodic scans become a no-op. A
+	// finished job's name stands in for the file's when SAB is configured.
+	parsePath := jobNamePath(path, job)
+	if IsObfuscatedSABFilename(path) && parsePath == path {
 		h.logger.Debug("handler", "Skipping obfuscated SAB temp-hash filename",
 			logging.F("path", path))
 		h.unparseableCache.Record(path, "obfuscated SAB temp-hash filename; waiting for SAB rename")
@@ -739,14 +974,37 @@ func (h *MediaHandler) processFile(path string) {
 	delete(h.transientRetries, path)
 	h.mu.Unlock()
 
+	if rule != nil && rule.Ignore {
+		h.logger.Debug("handler", "Ignoring file by rule",
+			logging.F("path", path),
+			logging.F("rule", rule.Name))
+		return
+	}
+
+	if h.extractor != nil && extract.IsArchive(path) {
+		h.extractArchive(path)
+		return
+	}
+
 	filename := filepath.Base(path)
 	h.logger.Info("handler", "Processing file", logging.F("filename", filename), logging.F("path", path))
+	if rule != nil {
+		h.logger.Info("handler", "Rule matched",
+			logging.F("filename", filename),
+			logging.F("rule", rule.Name),
+			logging.F("actions", rule.Actions()))
+	}
 
 	if h.dryRun {
 		h.logger.Info("handler", "Dry run - would process", logging.F("filename", filename))
 		return
 	}
 
+	if extra, ok := naming.ParseExtra(path); ok {
+		h.processExtra(path, extra, rule)
+		return
+	}
+
 	var result *organizer.OrganizationResult
 	var err error
 	var targetLib string
@@ -754,21 +1012,50 @@ func (h *MediaHandler) processFile(path string) {
 
 	var parsedTitle string
 	var parsedYear *int
-	var parsedSeason, parsedEpisode int
+	var parsedSeason, parsedEpisode, parsedEpisodeEnd int
 	parseMethod := activity.MethodRegex
 	aiConfidence := 0.0
 
 	isObfuscated := naming.IsObfuscatedFilename(filename)
-	if isObfuscated {
+	switch {
+	case parsePath != path:
+		h.logger.Info("handler", "Detected obfuscated filename, using download client job name",
+			logging.F("filename", filename),
+			logging.F("job", job.Name))
+	case isObfuscated:
 		h.logger.Info("handler", "Detected obfuscated filename, using folder name", logging.F("filename", filename))
 	}
 
+	// Sonarr or Radarr may already have said what this download is. A
+	// rule's media type still wins over theirs.
+	grab := h.arrGrabFor(path)
 	sourceHint := h.getSourceHint(path)
-	isTVEpisode := naming.IsTVEpisodeFromPath(path, sourceHint)
+	isTVEpisode := naming.IsTVEpisodeFromPath(parsePath, sourceHint)
+	if grab != nil {
+		isTVEpisode = grab.IsTV()
+	}
+	if rule != nil && rule.MediaType != "" {
+		isTVEpisode = rule.MediaType == rules.MediaTypeTV
+		if grab.IsTV() != isTVEpisode {
+			grab = nil
+		}
+	}
+	arrDownloadID := ""
+	if grab != nil {
+		arrDownloadID = grab.DownloadID
+		h.logger.Info("handler", "Using download identity",
+			logging.F("filename", filename),
+			logging.F("source", grab.Source),
+			logging.F("title", grab.Title),
+			logging.F("download_id", grab.DownloadID))
+	}
+	orgs := h.organizersFor(rule)
 
+	// A rule or download identity decides per file, so its files skip
+	// season-pack batching.
 	seasonPackDir := ""
 	seasonPackCompleted := false
-	if isTVEpisode {
+	if isTVEpisode && rule == nil && grab == nil {
 		if releaseDir, ok := seasonPackReleaseDir(path); ok {
 			started, reason := h.beginSeasonPack(releaseDir, startTime)
 			if !started {
@@ -819,11 +1106,24 @@ func (h *MediaHandler) processFile(path string) {
 		}
 	}
 
+	probed, corruptErr := h.inspectDownload(path)
+	if corruptErr != nil {
+		h.logger.Warn("handler", "Refusing to organize corrupt or truncated file",
+			logging.F("filename", filename),
+			logging.F("error", corruptErr.Error()))
+		h.updateDecisionOrganize(decisionID, nil, corruptErr)
+		h.unparseableCache.Record(path, corruptErr.Error())
+		h.stats.RecordError()
+		return
+	}
+
 	if isTVEpisode {
-		handled, completed := h.processTVSeasonPackIfApplicable(path, decisionID, startTime)
-		if handled {
-			seasonPackCompleted = completed
-			return
+		if rule == nil && grab == nil {
+			handled, completed := h.processTVSeasonPackIfApplicable(path, decisionID, startTime)
+			if handled {
+				seasonPackCompleted = completed
+				return
+			}
 		}
 
 		if len(h.tvLibraries) == 0 {
@@ -833,11 +1133,34 @@ func (h *MediaHandler) processFile(path string) {
 		}
 		mediaType = notify.MediaTypeTVEpisode
 
-		tvInfo, strippedTokens, parseErr := naming.ParseTVShowFromPathVerbose(path)
+		tvInfo, strippedTokens, parseErr := naming.ParseTVShowFromPathVerbose(parsePath)
+		if parseErr != nil && grab.HasEpisode() {
+			// The download identity names the episode, so an
+			// unparseable name is fine.
+			tvInfo, strippedTokens, parseErr = &naming.TVShowInfo{}, nil, nil
+		}
+		// A parse of the job name has to be handed to the organizer,
+		// which would otherwise parse the obfuscated file name again.
+		retitled := parseErr == nil && parsePath != path
+		switch {
+		case parseErr != nil:
+		case grab != nil:
+			grab.ApplyTV(tvInfo)
+			retitled = true
+			parseMethod = arrParseMethod(grab)
+		case h.aliasFor(database.AliasMediaSeries, path, tvInfo.Title).ApplyTV(tvInfo):
+			retitled = true
+			parseMethod = activity.MethodAlias
+		}
+		if parseErr == nil && retitleTV(rule, tvInfo) {
+			retitled = true
+			parseMethod = activity.MethodRule
+		}
 		if parseErr == nil {
 			parsedTitle = tvInfo.Title
 			parsedSeason = tvInfo.Season
 			parsedEpisode = tvInfo.Episode
+			parsedEpisodeEnd = tvInfo.EpisodeEnd
 			if tvInfo.Year != "" {
 				year := 0
 				if _, err := fmt.Sscanf(tvInfo.Year, "%d", &year); err == nil {
@@ -847,10 +1170,11 @@ func (h *MediaHandler) processFile(path string) {
 
 			if h.db != nil && decisionID != 0 {
 				u := database.ParseUpdate{
-					ParseMethod:      string(activity.MethodRegex),
+					ParseMethod:      string(parseMethod),
 					ParsedTitle:      tvInfo.Title,
 					ParsedYear:       parsedYear,
 					MediaTypeGuessed: "tv",
+					ArrDownloadID:    arrDownloadID,
 				}
 				if tvInfo.Season != 0 {
 					s := tvInfo.Season
@@ -870,8 +1194,10 @@ func (h *MediaHandler) processFile(path string) {
 				}
 			}
 
+			// Files a rule, alias or download identity decided are
+			// organized as they say, without waiting on AI.
 			confidence := naming.CalculateTitleConfidence(tvInfo.Title, filename)
-			if h.shouldQueueForAI(path, filename, tvInfo, nil, confidence) {
+			if rule == nil && !retitled && h.shouldQueueForAI(path, filename, tvInfo, nil, confidence) {
 				h.markDecisionQueued(decisionID)
 				h.queueForAI(path, filename, tvInfo, nil, "tv", confidence, "", decisionID)
 				return
@@ -883,14 +1209,27 @@ func (h *MediaHandler) processFile(path string) {
 			}
 		}
 
-		// Use auto-selection (queries Sonarr + filesystem)
-		result, err = h.tvOrganizer.OrganizeTVEpisodeAuto(path, func(p string) (int64, error) {
+		fileSize := func(p string) (int64, error) {
 			info, err := os.Stat(p)
 			if err != nil {
 				return 0, err
 			}
 			return info.Size(), nil
-		})
+		}
+		switch {
+		case retitled && rule != nil && rule.Library != "" && grab.HasEpisode():
+			// Sonarr's season and episode are final.
+			result, err = orgs.tv.OrganizeTVWithParsed(path, rule.Library, *tvInfo)
+		case retitled && rule != nil && rule.Library != "":
+			result, err = orgs.tv.OrganizeTVWithParsedIn(path, rule.Library, *tvInfo)
+		case retitled:
+			result, err = orgs.tv.OrganizeTVWithParsedAuto(path, *tvInfo, fileSize)
+		case rule != nil && rule.Library != "":
+			result, err = orgs.tv.OrganizeTVEpisode(path, rule.Library)
+		default:
+			// Use auto-selection (queries Sonarr + filesystem)
+			result, err = orgs.tv.OrganizeTVEpisodeAuto(path, fileSize)
+		}
 
 		// Extract target library from result for health check logging
 		if result != nil && result.TargetPath != "" {
@@ -903,9 +1242,39 @@ func (h *MediaHandler) processFile(path string) {
 			return
 		}
 		targetLib = h.movieLibs[0]
+		if rule != nil && rule.Library != "" {
+			targetLib = rule.Library
+		}
 		mediaType = notify.MediaTypeMovie
 
-		movieInfo, strippedTokens, parseErr := naming.ParseMovieFromPathVerbose(path)
+		movieInfo, strippedTokens, parseErr := naming.ParseMovieFromPathVerbose(parsePath)
+		var alias *database.Alias
+		switch {
+		case grab != nil:
+		case parseErr == nil:
+			alias = h.aliasFor(database.AliasMediaMovie, path, movieInfo.Title)
+		default:
+			alias = h.aliasFor(database.AliasMediaMovie, path, "")
+		}
+		if parseErr != nil && (grab != nil || alias != nil || (rule != nil && rule.Title != "")) {
+			// The download identity, alias or rule names the movie, so
+			// an unparseable name is fine.
+			movieInfo = &naming.MovieInfo{Part: naming.ParseStackPart(path)}
+			parseErr = nil
+		}
+		retitled := parseErr == nil && parsePath != path
+		if parseErr == nil && grab != nil {
+			grab.ApplyMovie(movieInfo)
+			retitled = true
+			parseMethod = arrParseMethod(grab)
+		} else if parseErr == nil && alias.ApplyMovie(movieInfo) {
+			retitled = true
+			parseMethod = activity.MethodAlias
+		}
+		if parseErr == nil && retitleMovie(rule, movieInfo) {
+			retitled = true
+			parseMethod = activity.MethodRule
+		}
 		if parseErr == nil {
 			parsedTitle = movieInfo.Title
 			if movieInfo.Year != "" {
@@ -917,10 +1286,11 @@ func (h *MediaHandler) processFile(path string) {
 
 			if h.db != nil && decisionID != 0 {
 				u := database.ParseUpdate{
-					ParseMethod:      string(activity.MethodRegex),
+					ParseMethod:      string(parseMethod),
 					ParsedTitle:      movieInfo.Title,
 					ParsedYear:       parsedYear,
 					MediaTypeGuessed: "movie",
+					ArrDownloadID:    arrDownloadID,
 				}
 				if b, jerr := json.Marshal(strippedTokens); jerr == nil {
 					u.ParserStrippedTokens = string(b)
@@ -933,7 +1303,7 @@ func (h *MediaHandler) processFile(path string) {
 			}
 
 			confidence := naming.CalculateTitleConfidence(movieInfo.Title, filename)
-			if h.shouldQueueForAI(path, filename, nil, movieInfo, confidence) {
+			if rule == nil && !retitled && h.shouldQueueForAI(path, filename, nil, movieInfo, confidence) {
 				h.markDecisionQueued(decisionID)
 				h.queueForAI(path, filename, nil, movieInfo, "movie", confidence, targetLib, decisionID)
 				return
@@ -951,7 +1321,11 @@ func (h *MediaHandler) processFile(path string) {
 			return
 		}
 
-		result, err = h.movieOrganizer.OrganizeMovie(path, targetLib)
+		if retitled {
+			result, err = orgs.movie.OrganizeMovieWithParsed(path, targetLib, *movieInfo)
+		} else {
+			result, err = orgs.movie.OrganizeMovie(path, targetLib)
+		}
 	}
 
 	duration := time.Since(startTime)
@@ -968,6 +1342,7 @@ func (h *MediaHandler) processFile(path string) {
 		}
 		if h.shouldLogError(path, err.Error()) {
 			h.logger.Error("handler", "Organization failed", err, logging.F("filename", filename))
+			h.alert(notify.AlertFailed, "Organization failed", filename+": "+err.Error(), path)
 		}
 		h.stats.RecordError()
 		h.logEntry(result, mediaType, parsedTitle, parsedYear, parseMethod, aiConfidence, duration, sonarrNotified, radarrNotified)
@@ -992,9 +1367,12 @@ func (h *MediaHandler) processFile(path string) {
 		if parsedYear != nil {
 			yearStr = fmt.Sprintf("%d", *parsedYear)
 		}
-		sonarrNotified, radarrNotified = h.sendNotificationsWithTracking(result, mediaType, parsedTitle, yearStr, parsedSeason, parsedEpisode)
-		h.cleanupSourceDir(path)
+		sonarrNotified, radarrNotified = h.sendNotificationsWithTracking(result, mediaType, parsedTitle, yearStr, parsedSeason, parsedEpisode, parsedEpisodeEnd)
+		h.rememberLinkedSource(path, result)
+		h.recordProbe(result, probed)
+		h.cleanupSourceDir(path, result.SourcePreserved)
 		h.unparseableCache.Forget(path)
+		h.downloadOrganized(job, path)
 	} else if result.Skipped {
 		h.logger.Info("handler", "Skipped", logging.F("filename", filename), logging.F("reason", result.SkipReason))
 	} else {
@@ -1007,6 +1385,7 @@ func (h *MediaHandler) processFile(path string) {
 		}
 		if h.shouldLogError(path, errMsg) {
 			h.logger.Error("handler", "Organization failed", result.Error, logging.F("filename", filename))
+			h.alert(notify.AlertFailed, "Organization failed", filename+": "+errMsg, path)
 		}
 		h.stats.RecordError()
 	}
@@ -1081,12 +1460,13 @@ func (h *MediaHandler) processTVSeasonPackIfApplicable(path string, decisionID i
 			}
 			h.stats.RecordTV(imported.BytesCopied)
 			tv, parseErr := naming.ParseTVShowFromPath(imported.TargetPath)
-			season, episode := packInfo.Season, 0
+			season, episode, episodeEnd := packInfo.Season, 0, 0
 			if parseErr == nil {
 				season = tv.Season
 				episode = tv.Episode
+				episodeEnd = tv.EpisodeEnd
 			}
-			notified, _ := h.sendNotificationsWithTracking(imported, notify.MediaTypeTVEpisode, packInfo.Title, packInfo.Year, season, episode)
+			notified, _ := h.sendNotificationsWithTracking(imported, notify.MediaTypeTVEpisode, packInfo.Title, packInfo.Year, season, episode, episodeEnd)
 			sonarrNotified = sonarrNotified || notified
 		}
 	}
@@ -1105,10 +1485,12 @@ func (h *MediaHandler) processTVSeasonPackIfApplicable(path string, decisionID i
 		h.logger.Info("handler", "Season pack requires review",
 			logging.F("dir", releaseDir),
 			logging.F("reason", result.SkipReason))
+		h.alert(notify.AlertNeedsReview, "Season pack requires review", filepath.Base(releaseDir)+": "+result.SkipReason, releaseDir)
 	} else {
 		h.stats.RecordError()
 		if result.Error != nil && h.shouldLogError(path, result.Error.Error()) {
 			h.logger.Error("handler", "Season pack organization failed", result.Error, logging.F("dir", releaseDir))
+			h.alert(notify.AlertFailed, "Season pack organization failed", filepath.Base(releaseDir)+": "+result.Error.Error(), releaseDir)
 		}
 	}
 
@@ -1157,7 +1539,12 @@ func (h *MediaHandler) seasonPackOrganizationResult(path, releaseDir string, pac
 }
 
 func hasDeterministicTVEpisodeIdentity(path string, tvInfo *naming.TVShowInfo) bool {
-	if tvInfo == nil || strings.TrimSpace(tvInfo.Title) == "" || tvInfo.Season <= 0 || tvInfo.Episode <= 0 {
+	if tvInfo == nil || strings.TrimSpace(tvInfo.Title) == "" || tvInfo.Season < 0 {
+		return false
+	}
+	// Season 0 is valid for specials; unnumbered ones are identified by
+	// their special marker instead of an episode number.
+	if tvInfo.Episode <= 0 && tvInfo.SpecialTitle == "" {
 		return false
 	}
 	return naming.IsTVEpisodeFromPath(path, naming.SourceUnknown)
@@ -1231,7 +1618,9 @@ func (h *MediaHandler) getParsedTitle(tvInfo *naming.TVShowInfo, movieInfo *nami
 	return ""
 }
 
-func (h *MediaHandler) sendNotificationsWithTracking(result *organizer.OrganizationResult, mediaType notify.MediaType, title, year string, season, episode int) (sonarrNotified, radarrNotified bool) {
+// episodeEnd is the last episode of a multi-episode file (0 otherwise) so
+// notifiers see the whole S01E01-E02 span rather than just its first episode.
+func (h *MediaHandler) sendNotificationsWithTracking(result *organizer.OrganizationResult, mediaType notify.MediaType, title, year string, season, episode, episodeEnd int) (sonarrNotified, radarrNotified bool) {
 	if h.notifyManager == nil {
 		return false, false
 	}
@@ -1245,6 +1634,7 @@ func (h *MediaHandler) sendNotificationsWithTracking(result *organizer.Organizat
 		Year:        year,
 		Season:      season,
 		Episode:     episode,
+		EpisodeEnd:  episodeEnd,
 		BytesCopied: result.BytesCopied,
 		Duration:    result.Duration,
 	}
@@ -1294,13 +1684,24 @@ func (h *MediaHandler) recordTargetHealth(target string, healthy bool, err error
 		h.logger.Warn("handler", "Target library became unhealthy",
 			logging.F("target", target),
 			logging.F("reason", reason))
+		h.alert(notify.AlertDiskUnhealthy, "Target library unhealthy", target+": "+reason, target)
 	} else if seen {
 		h.logger.Info("handler", "Target library recovered",
 			logging.F("target", target))
 	}
 }
 
+// alert passes a user-facing event to the notification targets, if any.
+func (h *MediaHandler) alert(kind notify.AlertKind, title, message, path string) {
+	h.notifyManager.Alert(notify.Alert{Kind: kind, Title: title, Message: message, Path: path})
+}
+
+// IsMediaFile reports whether path is a video, or an archive volume when
+// extraction is enabled.
 func (h *MediaHandler) IsMediaFile(path string) bool {
+	if h.extractor != nil && extract.IsArchive(path) {
+		return true
+	}
 	ext := strings.ToLower(filepath.Ext(path))
 	mediaExts := map[string]bool{
 		".mkv": true, ".mp4": true, ".avi": true, ".mov": true,
@@ -1363,7 +1764,7 @@ func (h *MediaHandler) removeNonVideoContents(dir string) (removed, kept int) {
 			kept++
 			continue
 		}
-		if err := os.Remove(e.path); err == nil {
+		if _, err := h.trash.Remove(e.path, "left in download folder"); err == nil {
 			removed++
 		}
 	}
@@ -1373,14 +1774,20 @@ func (h *MediaHandler) removeNonVideoContents(dir string) (removed, kept int) {
 // cleanupSourceDir removes the source download directory after a successful move,
 // walking up parent directories until it reaches a watch root.
 //
-// Gate: if sourcePath still exists the source was preserved (keepSource, dry-run),
-// so cleanup is skipped entirely.
+// Gate: if sourcePreserved is set (the transfer hardlinked/reflinked the target
+// so the download client can keep seeding) or sourcePath still exists (keepSource,
+// dry-run), cleanup is skipped entirely.
 //
 // For the starting release directory only, organizer.PurgeNonAllowed is called to
 // strip junk files while preserving allowed video and subtitle extensions. Parent
 // directories are only removed if they are already empty; junk in parents is never
 // touched to avoid interfering with other concurrent downloads.
-func (h *MediaHandler) cleanupSourceDir(sourcePath string) {
+func (h *MediaHandler) cleanupSourceDir(sourcePath string, sourcePreserved bool) {
+	if sourcePreserved {
+		h.logger.Debug("handler", "Skipping cleanup: source preserved by transfer backend",
+			logging.F("path", sourcePath))
+		return
+	}
 	// Gate: source still present means keepSource or dry-run — do nothing.
 	if _, err := os.Stat(sourcePath); err == nil {
 		return
@@ -1426,7 +1833,7 @@ func (h *MediaHandler) cleanupSourceDir(sourcePath string) {
 		if firstDir {
 			// Purge only disallowed files from the starting release directory.
 			// Subtitle and video files are preserved by the allowlist.
-			if err := organizer.PurgeNonAllowed(dir); err != nil {
+			if err := organizer.PurgeNonAllowed(dir, h.trash); err != nil {
 				h.logger.Warn("handler", "Allowlist purge failed",
 					logging.F("dir", dir), logging.F("error", err.Error()))
 			}
@@ -2033,6 +2440,7 @@ func (h *MediaHandler) processPendingAI(ctx context.Context, progress chan<- dat
 				logging.F("filename", item.Filename),
 				logging.F("category", string(classification.Category)),
 				logging.F("reason", reason))
+			h.alert(notify.AlertNeedsReview, "AI suggestion needs review", item.Filename+": "+reason, item.Path)
 		}
 
 		h.mu.Lock()
@@ -2125,9 +2533,10 @@ func (h *MediaHandler) applyAIResult(item *PendingItem, aiResult *ai.Result) {
 			tvInfo.Season = item.TVInfo.Season
 		}
 		if len(aiResult.Episodes) > 0 {
-			tvInfo.Episode = aiResult.Episodes[0]
+			tvInfo.SetEpisodes(aiResult.Episodes)
 		} else if item.TVInfo != nil {
 			tvInfo.Episode = item.TVInfo.Episode
+			tvInfo.EpisodeEnd = item.TVInfo.EpisodeEnd
 		}
 
 		// Use smart selector instead of hard-coding tvLibraries[0]; this
@@ -2143,6 +2552,7 @@ func (h *MediaHandler) applyAIResult(item *PendingItem, aiResult *ai.Result) {
 	} else {
 		movieInfo := naming.MovieInfo{
 			Title: aiResult.Title,
+			Part:  naming.ParseStackPart(item.Path),
 		}
 		if aiResult.Year != nil && aiResult.Year.Int() != nil {
 			movieInfo.Year = fmt.Sprintf("%d", *aiResult.Year.Int())
@@ -2181,6 +2591,7 @@ func (h *MediaHandler) applyAIResult(item *PendingItem, aiResult *ai.Result) {
 	if err != nil {
 		h.logger.Error("handler", "AI-enhanced organization failed", err,
 			logging.F("filename", item.Filename))
+		h.alert(notify.AlertFailed, "Organization failed", item.Filename+": "+err.Error(), item.Path)
 		h.stats.RecordError()
 		return
 	}
@@ -2197,7 +2608,7 @@ func (h *MediaHandler) applyAIResult(item *PendingItem, aiResult *ai.Result) {
 
 		// Send notifications for AI-enhanced moves (was previously missing)
 		var mediaType notify.MediaType
-		var season, episode int
+		var season, episode, episodeEnd int
 		yearStr := ""
 		if item.MediaType == "tv" {
 			mediaType = notify.MediaTypeTVEpisode
@@ -2205,7 +2616,9 @@ func (h *MediaHandler) applyAIResult(item *PendingItem, aiResult *ai.Result) {
 				season = *aiResult.Season.Int()
 			}
 			if len(aiResult.Episodes) > 0 {
-				episode = aiResult.Episodes[0]
+				var span naming.TVShowInfo
+				span.SetEpisodes(aiResult.Episodes)
+				episode, episodeEnd = span.Episode, span.EpisodeEnd
 			}
 		} else {
 			mediaType = notify.MediaTypeMovie
@@ -2213,7 +2626,7 @@ func (h *MediaHandler) applyAIResult(item *PendingItem, aiResult *ai.Result) {
 		if aiResult.Year != nil && aiResult.Year.Int() != nil {
 			yearStr = fmt.Sprintf("%d", *aiResult.Year.Int())
 		}
-		sonarrNotified, radarrNotified := h.sendNotificationsWithTracking(result, mediaType, aiResult.Title, yearStr, season, episode)
+		sonarrNotified, radarrNotified := h.sendNotificationsWithTracking(result, mediaType, aiResult.Title, yearStr, season, episode, episodeEnd)
 
 		// Activity-log the AI-enhanced move so operators can see what actually
 		// ran via the AI path (previously only the regex path wrote entries).
@@ -2246,7 +2659,8 @@ func (h *MediaHandler) applyAIResult(item *PendingItem, aiResult *ai.Result) {
 			}
 		}
 
-		h.cleanupSourceDir(item.Path)
+		h.rememberLinkedSource(item.Path, result)
+		h.cleanupSourceDir(item.Path, result.SourcePreserved)
 	}
 }
 
@@ -2270,7 +2684,7 @@ func (h *MediaHandler) organizeWithRegexFallback(item *PendingItem) {
 	var mediaType notify.MediaType
 	var parsedTitle string
 	var parsedYear *int
-	var parsedSeason, parsedEpisode int
+	var parsedSeason, parsedEpisode, parsedEpisodeEnd int
 
 	if item.MediaType == "tv" {
 		mediaType = notify.MediaTypeTVEpisode
@@ -2278,6 +2692,7 @@ func (h *MediaHandler) organizeWithRegexFallback(item *PendingItem) {
 			parsedTitle = item.TVInfo.Title
 			parsedSeason = item.TVInfo.Season
 			parsedEpisode = item.TVInfo.Episode
+			parsedEpisodeEnd = item.TVInfo.EpisodeEnd
 			if item.TVInfo.Year != "" {
 				year := 0
 				if _, e := fmt.Sscanf(item.TVInfo.Year, "%d", &year); e == nil {
@@ -2341,6 +2756,7 @@ func (h *MediaHandler) organizeWithRegexFallback(item *PendingItem) {
 
 	if err != nil {
 		h.logger.Error("handler", "Regex-fallback organization failed", err, logging.F("filename", filename))
+		h.alert(notify.AlertFailed, "Organization failed", filename+": "+err.Error(), item.Path)
 		h.stats.RecordError()
 		h.logEntry(result, mediaType, parsedTitle, parsedYear, activity.MethodRegex, 0.0, duration, sonarrNotified, radarrNotified)
 		return
@@ -2361,12 +2777,14 @@ func (h *MediaHandler) organizeWithRegexFallback(item *PendingItem) {
 		if parsedYear != nil {
 			yearStr = fmt.Sprintf("%d", *parsedYear)
 		}
-		sonarrNotified, radarrNotified = h.sendNotificationsWithTracking(result, mediaType, parsedTitle, yearStr, parsedSeason, parsedEpisode)
-		h.cleanupSourceDir(item.Path)
+		sonarrNotified, radarrNotified = h.sendNotificationsWithTracking(result, mediaType, parsedTitle, yearStr, parsedSeason, parsedEpisode, parsedEpisodeEnd)
+		h.rememberLinkedSource(item.Path, result)
+		h.cleanupSourceDir(item.Path, result.SourcePreserved)
 	} else if result != nil && result.Skipped {
 		h.logger.Info("handler", "Regex-fallback skipped", logging.F("filename", filename), logging.F("reason", result.SkipReason))
 	} else if result != nil && result.Error != nil {
 		h.logger.Error("handler", "Regex-fallback organization failed", result.Error, logging.F("filename", filename))
+		h.alert(notify.AlertFailed, "Organization failed", filename+": "+result.Error.Error(), item.Path)
 		h.stats.RecordError()
 	}
 
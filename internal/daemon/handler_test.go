@@ -2,6 +2,9 @@ package daemon
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"sync"
@@ -14,11 +17,48 @@ import (
 	"github.com/Nomadcxx/jellywatch/internal/library"
 	"github.com/Nomadcxx/jellywatch/internal/logging"
 	"github.com/Nomadcxx/jellywatch/internal/naming"
+	"github.com/Nomadcxx/jellywatch/internal/probe"
+	"github.com/Nomadcxx/jellywatch/internal/rules"
+	"github.com/Nomadcxx/jellywatch/internal/sonarr"
+	"github.com/Nomadcxx/jellywatch/internal/transfer"
 	"github.com/Nomadcxx/jellywatch/internal/watcher"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// newTestHandler builds a handler over root, which gets tv-downloads and
+// movie-downloads watch folders and TV and Movies libraries. configure, when
+// set, adjusts the config before the handler is built.
+func newTestHandler(t *testing.T, root string, configure func(cfg *MediaHandlerConfig)) *MediaHandler {
+	t.Helper()
+	for _, dir := range []string{"tv-downloads", "movie-downloads", "TV", "Movies"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755))
+	}
+	cfg := MediaHandlerConfig{
+		TVLibraries:     []string{filepath.Join(root, "TV")},
+		MovieLibs:       []string{filepath.Join(root, "Movies")},
+		TVWatchPaths:    []string{filepath.Join(root, "tv-downloads")},
+		MovieWatchPaths: []string{filepath.Join(root, "movie-downloads")},
+		Backend:         transfer.BackendNative,
+		Logger:          logging.Nop(),
+		TargetUID:       -1,
+		TargetGID:       -1,
+	}
+	if configure != nil {
+		configure(&cfg)
+	}
+	h, err := NewMediaHandler(cfg)
+	require.NoError(t, err)
+	t.Cleanup(h.Shutdown)
+	return h
+}
+
+func writeVideo(t *testing.T, path string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(filepath.Base(path)), 0644))
+}
+
 func TestMediaHandler_SeparateLibraries(t *testing.T) {
 	cfg := MediaHandlerConfig{
 		TVLibraries:     []string{"/tv/lib1"},
@@ -318,6 +358,87 @@ func TestProcessFile_FastLaneHighConfidence(t *testing.T) {
 	}
 }
 
+func TestProcessFile_RefusesTruncatedDownload(t *testing.T) {
+	tmpLib := t.TempDir()
+	watchDir := t.TempDir()
+
+	srcFile := filepath.Join(watchDir, "Heat.1995.1080p.BluRay.x264.mkv")
+	os.WriteFile(srcFile, []byte("test"), 0644)
+
+	// Video and audio stop a third of the way into the container duration.
+	bin, err := probe.WriteFakeBinary(t.TempDir(), `{
+		"streams": [
+			{"codec_type": "video", "codec_name": "h264", "width": 1920, "height": 800, "duration": "3400.0"},
+			{"codec_type": "audio", "codec_name": "dts", "channels": 6, "duration": "3400.0"}
+		],
+		"format": {"duration": "10200.0"}
+	}`, 0)
+	require.NoError(t, err)
+
+	handler, err := NewMediaHandler(MediaHandlerConfig{
+		TVLibraries:     []string{},
+		MovieLibs:       []string{tmpLib},
+		MovieWatchPaths: []string{watchDir},
+		Logger:          logging.Nop(),
+		Prober:          probe.New(bin, time.Second),
+	})
+	require.NoError(t, err)
+
+	handler.processFile(srcFile)
+
+	assert.FileExists(t, srcFile, "truncated download must stay in the watch folder")
+	entries, err := os.ReadDir(tmpLib)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "nothing should be organized into the library")
+	deferred, _, lastErr := handler.UnparseableCache().IsDeferred(srcFile)
+	assert.True(t, deferred, "truncated download should be deferred")
+	assert.Contains(t, lastErr, "truncated")
+}
+
+func TestProcessFile_StoresProbeOfOrganizedFile(t *testing.T) {
+	root := t.TempDir()
+	watchDir, lib := filepath.Join(root, "downloads"), filepath.Join(root, "Movies")
+	require.NoError(t, os.MkdirAll(lib, 0755))
+	srcFile := filepath.Join(watchDir, "Heat.1995.1080p.BluRay.x264.mkv")
+	writeVideo(t, srcFile)
+
+	bin, err := probe.WriteFakeBinary(t.TempDir(), `{
+		"streams": [
+			{"codec_type": "video", "codec_name": "h264", "width": 1920, "height": 800, "duration": "10200.0"},
+			{"codec_type": "audio", "codec_name": "dts", "channels": 6, "duration": "10200.0"}
+		],
+		"format": {"duration": "10200.0"}
+	}`, 0)
+	require.NoError(t, err)
+	db, err := database.OpenPath(filepath.Join(root, "media.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	handler, err := NewMediaHandler(MediaHandlerConfig{
+		MovieLibs:       []string{lib},
+		MovieWatchPaths: []string{watchDir},
+		Backend:         transfer.BackendNative,
+		Logger:          logging.Nop(),
+		TargetUID:       -1,
+		TargetGID:       -1,
+		Database:        db,
+		Prober:          probe.New(bin, time.Second),
+	})
+	require.NoError(t, err)
+	t.Cleanup(handler.Shutdown)
+
+	handler.processFile(srcFile)
+
+	// No scan has indexed the library file yet; the probe is kept for it.
+	target := filepath.Join(lib, "Heat (1995)", "Heat (1995).mkv")
+	require.FileExists(t, target)
+	got, err := db.GetMediaFileProbe(target)
+	require.NoError(t, err)
+	require.NotNil(t, got, "the download's probe must not be lost")
+	assert.Equal(t, 1920, got.Width)
+	assert.Equal(t, "h264", got.VideoCodec)
+}
+
 func TestProcessFile_SlowLaneLowConfidence(t *testing.T) {
 	tmpLib := t.TempDir()
 	watchDir := t.TempDir()
@@ -516,6 +637,75 @@ func TestProcessPendingAI_SkipsMissingFiles(t *testing.T) {
 	}
 }
 
+func TestMediaHandler_LibraryBackendOverrideIgnoresTrailingSlash(t *testing.T) {
+	root := t.TempDir()
+	downloads := filepath.Join(root, "downloads")
+	lib := filepath.Join(root, "TV")
+	require.NoError(t, os.MkdirAll(downloads, 0755))
+	require.NoError(t, os.MkdirAll(lib, 0755))
+
+	handler, err := NewMediaHandler(MediaHandlerConfig{
+		TVLibraries: []string{lib},
+		MovieLibs:   []string{filepath.Join(root, "Movies")},
+		Backend:     transfer.BackendNative,
+		// Configured with a trailing slash, as users commonly write it.
+		LibraryBackends: map[string]transfer.Backend{lib + "/": transfer.BackendHardlink},
+		TargetUID:       -1,
+		TargetGID:       -1,
+	})
+	require.NoError(t, err)
+	defer handler.Shutdown()
+
+	src := filepath.Join(downloads, "Show.S01E01.1080p.WEB-DL.mkv")
+	require.NoError(t, os.WriteFile(src, []byte("seeding"), 0644))
+
+	result, err := handler.tvOrganizer.OrganizeTVEpisode(src, lib)
+	require.NoError(t, err)
+	require.True(t, result.Success, "organize failed: %v (%s)", result.Error, result.SkipReason)
+	assert.Equal(t, "hardlink", result.LinkType)
+	assert.True(t, result.SourcePreserved)
+	_, err = os.Stat(src)
+	assert.NoError(t, err, "hardlinked source must stay in place for seeding")
+}
+
+func TestHandleFileEvent_SkipsLinkedSeedingSource(t *testing.T) {
+	root := t.TempDir()
+	watch := filepath.Join(root, "downloads")
+	lib := filepath.Join(root, "TV")
+	require.NoError(t, os.MkdirAll(watch, 0755))
+	require.NoError(t, os.MkdirAll(lib, 0755))
+
+	handler, err := NewMediaHandler(MediaHandlerConfig{
+		TVLibraries:     []string{lib},
+		MovieLibs:       []string{filepath.Join(root, "Movies")},
+		TVWatchPaths:    []string{watch},
+		MovieWatchPaths: []string{filepath.Join(root, "movie-downloads")},
+		DebounceTime:    time.Hour,
+		Backend:         transfer.BackendHardlink,
+		Logger:          logging.Nop(),
+		TargetUID:       -1,
+		TargetGID:       -1,
+	})
+	require.NoError(t, err)
+	defer handler.Shutdown()
+
+	src := filepath.Join(watch, "Show.S01E01.1080p.WEB-DL.mkv")
+	require.NoError(t, os.WriteFile(src, []byte("seeding"), 0644))
+
+	result, err := handler.tvOrganizer.OrganizeTVEpisode(src, lib)
+	require.NoError(t, err)
+	require.True(t, result.SourcePreserved)
+	handler.rememberLinkedSource(src, result)
+
+	require.NoError(t, handler.HandleFileEvent(watcher.FileEvent{Type: watcher.EventCreate, Path: src}))
+	assert.Empty(t, handler.pending, "linked source must not be re-queued")
+
+	// Once the library copy is gone the source is fair game again.
+	require.NoError(t, os.Remove(result.TargetPath))
+	require.NoError(t, handler.HandleFileEvent(watcher.FileEvent{Type: watcher.EventCreate, Path: src}))
+	assert.Len(t, handler.pending, 1)
+}
+
 func makeHandler(t *testing.T, watchRoots []string) *MediaHandler {
 	t.Helper()
 	return &MediaHandler{
@@ -536,7 +726,7 @@ func TestCleanupSourceDir_SimpleMovie(t *testing.T) {
 	movedFile := filepath.Join(dlDir, "movie.mkv")
 
 	h := makeHandler(t, []string{root})
-	h.cleanupSourceDir(movedFile)
+	h.cleanupSourceDir(movedFile, false)
 
 	_, err := os.Stat(dlDir)
 	assert.True(t, os.IsNotExist(err), "download dir should be removed")
@@ -545,6 +735,25 @@ func TestCleanupSourceDir_SimpleMovie(t *testing.T) {
 	assert.NoError(t, err, "watch root must not be removed")
 }
 
+func TestCleanupSourceDir_SourcePreserved_NoCleanup(t *testing.T) {
+	root := t.TempDir()
+	dlDir := filepath.Join(root, "Movie.Name.2025.1080p-GROUP")
+	require.NoError(t, os.MkdirAll(dlDir, 0755))
+
+	junk := filepath.Join(dlDir, "movie.nfo")
+	require.NoError(t, os.WriteFile(junk, []byte("nfo"), 0644))
+
+	// The torrent client may already have removed the payload; a linked
+	// organize still must not touch the release directory.
+	movedFile := filepath.Join(dlDir, "movie.mkv")
+
+	h := makeHandler(t, []string{root})
+	h.cleanupSourceDir(movedFile, true)
+
+	_, err := os.Stat(junk)
+	assert.NoError(t, err, "release dir must remain when the source was preserved by a link backend")
+}
+
 func TestCleanupSourceDir_SourceStillExists_NoCleanup(t *testing.T) {
 	root := t.TempDir()
 	dlDir := filepath.Join(root, "Movie.Name.2025.1080p-GROUP")
@@ -554,7 +763,7 @@ func TestCleanupSourceDir_SourceStillExists_NoCleanup(t *testing.T) {
 	require.NoError(t, os.WriteFile(videoFile, []byte("data"), 0644))
 
 	h := makeHandler(t, []string{root})
-	h.cleanupSourceDir(videoFile)
+	h.cleanupSourceDir(videoFile, false)
 
 	_, err := os.Stat(dlDir)
 	assert.NoError(t, err, "download dir must remain when source still exists")
@@ -565,7 +774,7 @@ func TestCleanupSourceDir_FileDirectlyInWatchRoot_NoCleanup(t *testing.T) {
 	movedFile := filepath.Join(root, "movie.mkv")
 
 	h := makeHandler(t, []string{root})
-	h.cleanupSourceDir(movedFile)
+	h.cleanupSourceDir(movedFile, false)
 
 	_, err := os.Stat(root)
 	assert.NoError(t, err, "watch root must not be removed")
@@ -585,7 +794,7 @@ func TestCleanupSourceDir_SeasonPackRemainingEpisodes(t *testing.T) {
 	movedFile := filepath.Join(s1, "Show.S01E02.mkv")
 
 	h := makeHandler(t, []string{root})
-	h.cleanupSourceDir(movedFile)
+	h.cleanupSourceDir(movedFile, false)
 
 	_, err := os.Stat(s1)
 	assert.True(t, os.IsNotExist(err), "empty Season 1 should be removed")
@@ -606,7 +815,7 @@ func TestCleanupSourceDir_LastEpisodeSeasonPack_FullCleanup(t *testing.T) {
 	movedFile := filepath.Join(s1, "Show.S01E01.mkv")
 
 	h := makeHandler(t, []string{root})
-	h.cleanupSourceDir(movedFile)
+	h.cleanupSourceDir(movedFile, false)
 
 	// Starting release dir (s1) is purged and removed.
 	_, err := os.Stat(s1)
@@ -650,7 +859,7 @@ func TestCleanupSourceDir_SubtitlesTreatedAsJunk(t *testing.T) {
 	movedFile := filepath.Join(dlDir, "movie.mkv")
 
 	h := makeHandler(t, []string{root})
-	h.cleanupSourceDir(movedFile)
+	h.cleanupSourceDir(movedFile, false)
 
 	_, err := os.Stat(dlDir)
 	assert.NoError(t, err, "dir with subtitle leftovers should be preserved by allowlist")
@@ -670,7 +879,7 @@ func TestCleanupSourceDir_RemoveAllError_ContinuesUpward(t *testing.T) {
 	movedFile := filepath.Join(childDir, "video.mkv")
 
 	h := makeHandler(t, []string{root})
-	h.cleanupSourceDir(movedFile)
+	h.cleanupSourceDir(movedFile, false)
 
 	_, err := os.Stat(root)
 	assert.NoError(t, err, "root must still exist")
@@ -687,7 +896,7 @@ func TestCleanupSourceDir_WatchRootWithTrailingSlash(t *testing.T) {
 	movedFile := filepath.Join(dlDir, "movie.mkv")
 
 	h := makeHandler(t, []string{root + string(os.PathSeparator)})
-	h.cleanupSourceDir(movedFile)
+	h.cleanupSourceDir(movedFile, false)
 
 	_, err := os.Stat(dlDir)
 	assert.True(t, os.IsNotExist(err), "download dir should be removed even with trailing slash in watch root")
@@ -710,7 +919,7 @@ func TestCleanupAllowlist_JunkRemoved(t *testing.T) {
 	movedFile := filepath.Join(dlDir, "movie.mkv") // source already moved away
 
 	h := makeHandler(t, []string{root})
-	h.cleanupSourceDir(movedFile)
+	h.cleanupSourceDir(movedFile, false)
 
 	_, err := os.Stat(dlDir)
 	assert.True(t, os.IsNotExist(err), "dir should be removed once junk is purged and it is empty")
@@ -730,7 +939,7 @@ func TestCleanupAllowlist_SourceStillExists_NoCleanup(t *testing.T) {
 	require.NoError(t, os.WriteFile(junk, []byte("x"), 0644))
 
 	h := makeHandler(t, []string{root})
-	h.cleanupSourceDir(videoFile)
+	h.cleanupSourceDir(videoFile, false)
 
 	_, err := os.Stat(dlDir)
 	assert.NoError(t, err, "dir must remain when source file still exists")
@@ -752,7 +961,7 @@ func TestCleanupAllowlist_VideoRemains(t *testing.T) {
 	movedFile := filepath.Join(dlDir, "Show.S01E01.mkv")
 
 	h := makeHandler(t, []string{root})
-	h.cleanupSourceDir(movedFile)
+	h.cleanupSourceDir(movedFile, false)
 
 	_, err := os.Stat(dlDir)
 	assert.NoError(t, err, "release dir must remain while sibling video is present")
@@ -775,7 +984,7 @@ func TestCleanupAllowlist_ParentNotPurged(t *testing.T) {
 	movedFile := filepath.Join(startingDir, "Show.S01E01.mkv")
 
 	h := makeHandler(t, []string{root})
-	h.cleanupSourceDir(movedFile)
+	h.cleanupSourceDir(movedFile, false)
 
 	// Starting dir is empty → removed.
 	_, err := os.Stat(startingDir)
@@ -1422,3 +1631,94 @@ func TestReplayDeferredOperation_WaitsForShutdown(t *testing.T) {
 		t.Fatal("replayDeferredOperation goroutine should complete after cancel")
 	}
 }
+
+func TestProcessFile_RulesIgnoreAndRoute(t *testing.T) {
+	root := t.TempDir()
+	watch := filepath.Join(root, "downloads")
+	movies := filepath.Join(root, "Movies")
+	kids := filepath.Join(root, "Kids Movies")
+	for _, dir := range []string{filepath.Join(watch, "Kids"), movies, kids} {
+		require.NoError(t, os.MkdirAll(dir, 0755))
+	}
+
+	engine, err := rules.New([]rules.Rule{
+		{Name: "samples", Path: "*.sample.*", Ignore: true},
+		{Name: "kids", Path: "*/Kids/*", Library: kids, MediaType: rules.MediaTypeMovie, Title: "Frozen", Year: "2013"},
+	})
+	require.NoError(t, err)
+
+	handler, err := NewMediaHandler(MediaHandlerConfig{
+		MovieLibs:       []string{movies, kids},
+		MovieWatchPaths: []string{watch},
+		Backend:         transfer.BackendNative,
+		Logger:          logging.Nop(),
+		Rules:           engine,
+		TargetUID:       -1,
+		TargetGID:       -1,
+	})
+	require.NoError(t, err)
+	defer handler.Shutdown()
+
+	sample := filepath.Join(watch, "Heat.1995.1080p.BluRay.x264.sample.mkv")
+	require.NoError(t, os.WriteFile(sample, []byte("sample"), 0644))
+	handler.processFile(sample)
+	assert.FileExists(t, sample, "ignored file must stay put")
+
+	src := filepath.Join(watch, "Kids", "frozen.ws.1080p.bluray.mkv")
+	require.NoError(t, os.WriteFile(src, []byte("movie"), 0644))
+	handler.processFile(src)
+	assert.FileExists(t, filepath.Join(kids, "Frozen (2013)", "Frozen (2013).mkv"))
+	entries, err := os.ReadDir(movies)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "rule library must win over the selector")
+}
+
+// An anime rule that also fixes the title still maps absolute numbering,
+// under the rule's title.
+func TestProcessFile_AnimeRuleWithTitleResolvesAbsolute(t *testing.T) {
+	root := t.TempDir()
+	watch := filepath.Join(root, "downloads")
+	tv, anime := filepath.Join(root, "TV"), filepath.Join(root, "Anime")
+	for _, dir := range []string{watch, tv, anime} {
+		require.NoError(t, os.MkdirAll(dir, 0755))
+	}
+
+	absolute := 29
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/series":
+			json.NewEncoder(w).Encode([]sonarr.Series{{ID: 1, Title: "Frieren", SeriesType: "anime"}})
+		case "/api/v3/episode":
+			json.NewEncoder(w).Encode([]sonarr.Episode{{SeasonNumber: 2, EpisodeNumber: 1, AbsoluteEpisodeNumber: &absolute}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	engine, err := rules.New([]rules.Rule{{
+		Name: "anime", ReleaseGroups: []string{"SubsPlease"}, MediaType: rules.MediaTypeTV,
+		Library: anime, Title: "Frieren",
+	}})
+	require.NoError(t, err)
+	h, err := NewMediaHandler(MediaHandlerConfig{
+		TVLibraries:    []string{tv, anime},
+		AnimeLibraries: []string{anime},
+		TVWatchPaths:   []string{watch},
+		Backend:        transfer.BackendNative,
+		Logger:         logging.Nop(),
+		TargetUID:      -1,
+		TargetGID:      -1,
+		Rules:          engine,
+		SonarrClient:   sonarr.NewClient(sonarr.Config{URL: server.URL, APIKey: "test"}),
+	})
+	require.NoError(t, err)
+	t.Cleanup(h.Shutdown)
+
+	src := filepath.Join(watch, "[SubsPlease] Sousou no Frieren - 29 (1080p) [ABCD1234].mkv")
+	writeVideo(t, src)
+	h.processFile(src)
+
+	assert.FileExists(t, filepath.Join(anime, "Frieren", "Season 02", "Frieren S02E01.mkv"))
+}
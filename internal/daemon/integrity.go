@@ -0,0 +1,51 @@
+package daemon
+
+import (
+	"errors"
+	"os"
+
+	"github.com/Nomadcxx/jellywatch/internal/logging"
+	"github.com/Nomadcxx/jellywatch/internal/organizer"
+	"github.com/Nomadcxx/jellywatch/internal/probe"
+)
+
+// inspectDownload probes path with ffprobe before it is organized. It
+// returns an error wrapping probe.ErrCorrupt when the streams show a
+// truncated or damaged download; the measured result is returned for
+// storing once the file lands in a library. Without a prober, or when
+// ffprobe can't run, inspection is skipped and the file is organized as
+// before.
+func (h *MediaHandler) inspectDownload(path string) (*probe.Result, error) {
+	if h.prober == nil {
+		return nil, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil
+	}
+	result, err := h.prober.Probe(h.ctx, path)
+	if err != nil {
+		if errors.Is(err, probe.ErrCorrupt) {
+			return nil, err
+		}
+		h.logger.Debug("handler", "Media inspection unavailable",
+			logging.F("path", path), logging.F("error", err.Error()))
+		return nil, nil
+	}
+	if err := result.CheckIntegrity(info.Size()); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// recordProbe stores the download's measurements on its organized library
+// file so later scans and duplicate scoring use them without re-probing.
+func (h *MediaHandler) recordProbe(result *organizer.OrganizationResult, probed *probe.Result) {
+	if h.db == nil || probed == nil || result == nil || !result.Success {
+		return
+	}
+	if err := h.db.UpdateMediaFileProbe(result.TargetPath, probed); err != nil {
+		h.logger.Warn("handler", "failed to store media inspection",
+			logging.F("path", result.TargetPath), logging.F("error", err.Error()))
+	}
+}
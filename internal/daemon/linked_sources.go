@@ -0,0 +1,94 @@
+package daemon
+
+import (
+	"os"
+	"sync"
+
+	"github.com/Nomadcxx/jellywatch/internal/logging"
+	"github.com/Nomadcxx/jellywatch/internal/organizer"
+)
+
+// linkedSources remembers download files that a hardlink/reflink backend
+// organized and deliberately left in place for seeding. Those files stay in
+// the watch folder forever, so without this guard every periodic scan would
+// re-parse them, possibly queue them for AI, and skip them again.
+type linkedSources struct {
+	mu      sync.Mutex
+	targets map[string]string // source path -> library target path
+}
+
+func newLinkedSources() *linkedSources {
+	return &linkedSources{targets: make(map[string]string)}
+}
+
+// rememberLinkedSource records path as organized when the transfer backend
+// preserved it. Moved or copied sources are not recorded.
+func (h *MediaHandler) rememberLinkedSource(path string, result *organizer.OrganizationResult) {
+	if h.linked == nil || result == nil || !result.SourcePreserved || result.TargetPath == "" {
+		return
+	}
+	h.linked.mu.Lock()
+	h.linked.targets[path] = result.TargetPath
+	h.linked.mu.Unlock()
+}
+
+// isLinkedSource reports whether path is a seeding source that was already
+// linked into a library and has not changed since. Entries learned in this
+// process are checked first; after a restart the most recent successful
+// parse decision for the path is consulted instead.
+func (h *MediaHandler) isLinkedSource(path string) bool {
+	if h.linked == nil {
+		return false
+	}
+
+	h.linked.mu.Lock()
+	target, ok := h.linked.targets[path]
+	h.linked.mu.Unlock()
+
+	if !ok && h.db != nil {
+		d, err := h.db.GetMostRecentDecisionBySourcePath(path)
+		if err != nil {
+			h.logger.Debug("handler", "Linked-source lookup failed",
+				logging.F("path", path), logging.F("error", err.Error()))
+			return false
+		}
+		if d == nil || d.OrganizeOutcome != "success" || d.TargetPath == "" {
+			return false
+		}
+		target = d.TargetPath
+	}
+	if target == "" {
+		return false
+	}
+
+	if !sharesData(path, target) {
+		// Source replaced or target gone: forget it and let it be processed.
+		h.linked.mu.Lock()
+		delete(h.linked.targets, path)
+		h.linked.mu.Unlock()
+		return false
+	}
+
+	h.linked.mu.Lock()
+	h.linked.targets[path] = target
+	h.linked.mu.Unlock()
+	return true
+}
+
+// sharesData reports whether target still holds the data of source: the
+// same inode for a hardlink, or the same size and a target written no
+// earlier than the source for a reflink.
+func sharesData(source, target string) bool {
+	srcInfo, err := os.Stat(source)
+	if err != nil {
+		return false
+	}
+	dstInfo, err := os.Stat(target)
+	if err != nil {
+		return false
+	}
+	if os.SameFile(srcInfo, dstInfo) {
+		return true
+	}
+	return srcInfo.Size() == dstInfo.Size() && !dstInfo.ModTime().Before(srcInfo.ModTime())
+}
@@ -0,0 +1,105 @@
+package daemon
+
+import (
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/metrics"
+	"github.com/Nomadcxx/jellywatch/internal/notify"
+	"github.com/Nomadcxx/jellywatch/internal/organizer"
+)
+
+var organizeTotal = metrics.Default.NewCounterVec("jellywatch_organize_total",
+	"Files handled by the organizer, by media type and outcome (success, skipped, failed).",
+	"media_type", "outcome")
+
+func recordOrganizeOutcome(mediaType notify.MediaType, result *organizer.OrganizationResult) {
+	outcome := "failed"
+	switch {
+	case result == nil:
+	case result.Success:
+		outcome = "success"
+	case result.Skipped:
+		outcome = "skipped"
+	}
+	organizeTotal.Inc(mediaType.String(), outcome)
+}
+
+// Collect writes the handler's point-in-time state: uptime, transfer
+// slots per volume, AI budget, the negative cache and Jellyfin
+// playback locks.
+func (h *MediaHandler) Collect(e *metrics.Encoder) {
+	e.Gauge("jellywatch_uptime_seconds", "Seconds since the daemon started.", h.stats.Snapshot().Uptime.Seconds())
+
+	usage := h.volumeLimiter.Usage()
+	e.Family("jellywatch_volume_transfers_active", "Transfers holding a slot on each destination volume.", "gauge")
+	for _, u := range usage {
+		e.Sample("jellywatch_volume_transfers_active", float64(u.Active), "volume", u.Root)
+	}
+	e.Family("jellywatch_volume_transfers_waiting", "Transfers queued for a slot on each destination volume.", "gauge")
+	for _, u := range usage {
+		e.Sample("jellywatch_volume_transfers_waiting", float64(u.Waiting), "volume", u.Root)
+	}
+
+	if h.aiRateLimiter != nil {
+		hourlyUsed, dailyUsed := h.aiRateLimiter.Stats()
+		hourlyCap, dailyCap := h.aiRateLimiter.Caps()
+		e.Family("jellywatch_ai_requests_used", "AI requests counted against the rate limit in the current window.", "gauge")
+		e.Sample("jellywatch_ai_requests_used", float64(hourlyUsed), "window", "hourly")
+		e.Sample("jellywatch_ai_requests_used", float64(dailyUsed), "window", "daily")
+		e.Family("jellywatch_ai_requests_limit", "AI requests allowed per window.", "gauge")
+		e.Sample("jellywatch_ai_requests_limit", float64(hourlyCap), "window", "hourly")
+		e.Sample("jellywatch_ai_requests_limit", float64(dailyCap), "window", "daily")
+	}
+
+	e.Gauge("jellywatch_negative_cache_entries", "Unparseable files deferred from re-processing.", float64(h.unparseableCache.Len()))
+
+	if h.playbackLocks != nil {
+		e.Gauge("jellywatch_playback_locks", "Files Jellyfin is streaming, which transfers won't touch.", float64(h.playbackLocks.Count()))
+	}
+}
+
+// NewDatabaseCollector reports housekeeping task counts and scheduled job
+// results from the database at scrape time.
+func NewDatabaseCollector(db *database.MediaDB) metrics.Collector {
+	return metrics.CollectorFunc(func(e *metrics.Encoder) {
+		if counts, err := db.CountHousekeepingTasksByKind(); err == nil {
+			e.Family("jellywatch_housekeeping_tasks", "Housekeeping tasks by kind and status.", "gauge")
+			for _, c := range counts {
+				e.Sample("jellywatch_housekeeping_tasks", float64(c.Count), "kind", c.Kind, "status", c.Status)
+			}
+		}
+
+		jobs, err := db.ListScheduledJobs()
+		if err != nil {
+			return
+		}
+		e.Family("jellywatch_scheduler_job_last_run_timestamp_seconds", "Unix time a scheduled job last finished.", "gauge")
+		for _, j := range jobs {
+			if j.LastRunAt.Valid {
+				e.Sample("jellywatch_scheduler_job_last_run_timestamp_seconds", float64(j.LastRunAt.Time.Unix()), "job", j.Name)
+			}
+		}
+		e.Family("jellywatch_scheduler_job_last_duration_seconds", "How long a scheduled job's last run took.", "gauge")
+		for _, j := range jobs {
+			if j.LastDurationMS.Valid {
+				e.Sample("jellywatch_scheduler_job_last_duration_seconds", float64(j.LastDurationMS.Int64)/1000, "job", j.Name)
+			}
+		}
+		e.Family("jellywatch_scheduler_job_last_success", "1 if a scheduled job's last run succeeded, 0 if it failed.", "gauge")
+		for _, j := range jobs {
+			if j.LastRunAt.Valid {
+				e.Sample("jellywatch_scheduler_job_last_success", boolValue(!j.LastError.Valid || j.LastError.String == ""), "job", j.Name)
+			}
+		}
+		e.Family("jellywatch_scheduler_job_running", "1 while a scheduled job is running.", "gauge")
+		for _, j := range jobs {
+			e.Sample("jellywatch_scheduler_job_running", boolValue(j.Running), "job", j.Name)
+		}
+	})
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -182,6 +182,16 @@ func (n *NegativeCache) Snapshot() []NegativeCacheEntry {
 	return out
 }
 
+// Len returns the number of paths in the cache.
+func (n *NegativeCache) Len() int {
+	if n == nil {
+		return 0
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.entries)
+}
+
 // NegativeCacheEntry is a snapshot of a single cache row.
 type NegativeCacheEntry struct {
 	Path           string        `json:"path"`
@@ -2,8 +2,10 @@ package reload
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/Nomadcxx/jellywatch/internal/config"
+	"github.com/Nomadcxx/jellywatch/internal/ingest"
 )
 
 type WatchPathReplacer interface {
@@ -12,10 +14,13 @@ type WatchPathReplacer interface {
 
 type scannerReloadable struct {
 	watcher WatchPathReplacer
+	filters *ingest.Filters
 }
 
-func NewScannerReloadable(watcher WatchPathReplacer) Reloadable {
-	return &scannerReloadable{watcher: watcher}
+// NewScannerReloadable reloads the watch folders and, when filters is
+// set, the watch-folder filters the watcher, handler and scanners share.
+func NewScannerReloadable(watcher WatchPathReplacer, filters *ingest.Filters) Reloadable {
+	return &scannerReloadable{watcher: watcher, filters: filters}
 }
 
 func (r *scannerReloadable) Name() string {
@@ -28,11 +33,31 @@ func (r *scannerReloadable) Prepare(ctx context.Context, oldCfg, newCfg *config.
 	oldPaths := append([]string{}, oldCfg.Watch.Movies...)
 	oldPaths = append(oldPaths, oldCfg.Watch.TV...)
 
+	var newFilters, oldFilters *ingest.Filters
+	if r.filters != nil {
+		built, err := newCfg.Watch.IngestFilters()
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid watch filters: %w", err)
+		}
+		newFilters = built
+		oldFilters = &ingest.Filters{}
+		oldFilters.Replace(r.filters)
+	}
+
 	commit := func() error {
-		return r.watcher.ReplaceWatchPaths(newPaths)
+		if err := r.watcher.ReplaceWatchPaths(newPaths); err != nil {
+			return err
+		}
+		if newFilters != nil {
+			r.filters.Replace(newFilters)
+		}
+		return nil
 	}
 	rollback := func() {
 		_ = r.watcher.ReplaceWatchPaths(oldPaths)
+		if oldFilters != nil {
+			r.filters.Replace(oldFilters)
+		}
 	}
 	return commit, rollback, nil
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/Nomadcxx/jellywatch/internal/config"
+	"github.com/Nomadcxx/jellywatch/internal/ingest"
 )
 
 type fakeWatchPathReplacer struct {
@@ -19,7 +20,7 @@ func (f *fakeWatchPathReplacer) ReplaceWatchPaths(paths []string) error {
 
 func TestScannerReloadableReplacesWatchPathsOnCommit(t *testing.T) {
 	replacer := &fakeWatchPathReplacer{}
-	r := NewScannerReloadable(replacer)
+	r := NewScannerReloadable(replacer, nil)
 
 	oldCfg := &config.Config{
 		Watch: config.WatchConfig{
@@ -50,3 +51,38 @@ func TestScannerReloadableReplacesWatchPathsOnCommit(t *testing.T) {
 		t.Fatalf("paths = %#v, want %#v", replacer.paths, want)
 	}
 }
+
+func TestScannerReloadableRebuildsFilters(t *testing.T) {
+	oldCfg := &config.Config{Watch: config.WatchConfig{Movies: []string{"/downloads"}}}
+	newCfg := &config.Config{Watch: config.WatchConfig{
+		Movies:  []string{"/downloads"},
+		Filters: config.FilterConfig{Exclude: []string{"*.sample.mkv"}},
+	}}
+	filters, err := oldCfg.Watch.IngestFilters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewScannerReloadable(&fakeWatchPathReplacer{}, filters)
+	const path = "/downloads/Movie.2001.sample.mkv"
+
+	commit, rollback, err := r.Prepare(context.Background(), oldCfg, newCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := commit(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := filters.Accept(path, -1, ingest.MediaTypeMovie); ok {
+		t.Fatal("reloaded exclude pattern not applied")
+	}
+	rollback()
+	if ok, _ := filters.Accept(path, -1, ingest.MediaTypeMovie); !ok {
+		t.Fatal("rollback did not restore the old filters")
+	}
+
+	// A bad pattern fails the reload before anything changes.
+	newCfg.Watch.Filters.Exclude = []string{"[bad"}
+	if _, _, err := r.Prepare(context.Background(), oldCfg, newCfg); err == nil {
+		t.Fatal("Prepare accepted an invalid pattern")
+	}
+}
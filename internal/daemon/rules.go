@@ -0,0 +1,66 @@
+package daemon
+
+import (
+	"os"
+
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+	"github.com/Nomadcxx/jellywatch/internal/organizer"
+	"github.com/Nomadcxx/jellywatch/internal/rules"
+)
+
+// ruleOrganizers are the organizers a rule's files go through.
+type ruleOrganizers struct {
+	tv    *organizer.Organizer
+	movie *organizer.Organizer
+}
+
+// matchRule returns the first rule matching path, downloaded under the
+// download client category, or nil.
+func (h *MediaHandler) matchRule(path, category string) *rules.Rule {
+	if h.rules == nil {
+		return nil
+	}
+	size := int64(-1)
+	if info, err := os.Stat(path); err == nil {
+		size = info.Size()
+	}
+	return h.rules.Match(path, size, category)
+}
+
+// organizersFor returns the organizers for files rule matched; the
+// handler's own when the rule doesn't change how files land.
+func (h *MediaHandler) organizersFor(rule *rules.Rule) ruleOrganizers {
+	if orgs, ok := h.ruleOrganizers[rule]; ok {
+		return orgs
+	}
+	return ruleOrganizers{tv: h.tvOrganizer, movie: h.movieOrganizer}
+}
+
+// retitleTV applies a rule's title and year to a TV parse. It reports
+// whether the rule set either.
+func retitleTV(rule *rules.Rule, tv *naming.TVShowInfo) bool {
+	if rule == nil || tv == nil || (rule.Title == "" && rule.Year == "") {
+		return false
+	}
+	if rule.Title != "" {
+		tv.Title = rule.Title
+	}
+	if rule.Year != "" {
+		tv.Year = rule.Year
+	}
+	return true
+}
+
+// retitleMovie is retitleTV for movies.
+func retitleMovie(rule *rules.Rule, movie *naming.MovieInfo) bool {
+	if rule == nil || movie == nil || (rule.Title == "" && rule.Year == "") {
+		return false
+	}
+	if rule.Title != "" {
+		movie.Title = rule.Title
+	}
+	if rule.Year != "" {
+		movie.Year = rule.Year
+	}
+	return true
+}
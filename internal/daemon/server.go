@@ -13,6 +13,7 @@ import (
 
 	"github.com/Nomadcxx/jellywatch/internal/jellyfin"
 	"github.com/Nomadcxx/jellywatch/internal/logging"
+	"github.com/Nomadcxx/jellywatch/internal/metrics"
 	"github.com/Nomadcxx/jellywatch/internal/scanner"
 )
 
@@ -25,6 +26,7 @@ type Server struct {
 	healthy       bool
 	logger        *logging.Logger
 	webhookSecret string
+	metrics       *metrics.Registry
 }
 
 type HealthResponse struct {
@@ -56,13 +58,18 @@ func NewServer(handler *MediaHandler, periodicScanner *scanner.PeriodicScanner,
 		healthy:       true,
 		logger:        logger,
 		webhookSecret: strings.TrimSpace(webhookSecret),
+		metrics:       metrics.NewRegistry(),
+	}
+	s.metrics.MustRegister(metrics.Default)
+	if handler != nil {
+		s.metrics.MustRegister(handler)
 	}
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/health", s.handleHealth)
 	mux.HandleFunc("/healthz", s.handleHealth)
 	mux.HandleFunc("/ready", s.handleReady)
-	mux.HandleFunc("/metrics", s.handleMetrics)
+	mux.HandleFunc("/metrics", s.handlePrometheus)
 	mux.HandleFunc("/stats", s.handleMetrics)
 	mux.HandleFunc("/api/v1/webhooks/jellyfin", s.handleJellyfinWebhook)
 
@@ -169,6 +176,18 @@ func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// RegisterMetrics adds collectors to /metrics, after the process-wide
+// counters and the handler's gauges.
+func (s *Server) RegisterMetrics(cs ...metrics.Collector) {
+	s.metrics.MustRegister(cs...)
+}
+
+// handlePrometheus serves the metrics registry in Prometheus text format.
+// /stats keeps the JSON counters for existing consumers.
+func (s *Server) handlePrometheus(w http.ResponseWriter, r *http.Request) {
+	s.metrics.Handler().ServeHTTP(w, r)
+}
+
 func (s *Server) handleJellyfinWebhook(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		w.WriteHeader(http.StatusMethodNotAllowed)
@@ -5,9 +5,16 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Nomadcxx/jellywatch/internal/activity"
+	"github.com/Nomadcxx/jellywatch/internal/config"
 	"github.com/Nomadcxx/jellywatch/internal/jellyfin"
+	"github.com/Nomadcxx/jellywatch/internal/metrics"
+	"github.com/Nomadcxx/jellywatch/internal/notify"
+	"github.com/Nomadcxx/jellywatch/internal/organizer"
 	"github.com/Nomadcxx/jellywatch/internal/transfer"
 )
 
@@ -151,6 +158,55 @@ func TestServerMetrics(t *testing.T) {
 	}
 }
 
+func TestServerPrometheusMetrics(t *testing.T) {
+	handler, err := NewMediaHandler(MediaHandlerConfig{
+		TVLibraries: []string{"/tv"},
+		MovieLibs:   []string{"/movies"},
+		Backend:     transfer.BackendNative,
+		ConfigDir:   t.TempDir(),
+		AIEnabled:   true,
+		AIConfig:    config.DefaultAIConfig(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+	defer handler.Shutdown()
+
+	handler.logEntry(&organizer.OrganizationResult{Success: true}, notify.MediaTypeTVEpisode, "Show", nil, activity.MethodRegex, 0, time.Second, false, false)
+	handler.unparseableCache.Record("/downloads/unknown.mkv", "unparseable")
+
+	server := NewServer(handler, nil, ":0", nil, "")
+	server.RegisterMetrics(metrics.CollectorFunc(func(e *metrics.Encoder) {
+		e.Gauge("jellywatch_test_extra", "Registered by the test.", 7)
+	}))
+
+	w := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("Content-Type = %q, want Prometheus text format", ct)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		"# TYPE jellywatch_organize_total counter",
+		`jellywatch_organize_total{media_type="tv",outcome="success"}`,
+		"# TYPE jellywatch_transfer_duration_seconds histogram",
+		`jellywatch_ai_requests_limit{window="hourly"}`,
+		"jellywatch_negative_cache_entries 1",
+		"jellywatch_test_extra 7",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("/metrics missing %q", want)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats", nil))
+	var resp MetricsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("/stats is no longer JSON: %v", err)
+	}
+}
+
 func TestStats(t *testing.T) {
 	stats := NewStats()
 
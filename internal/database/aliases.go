@@ -0,0 +1,332 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+)
+
+// Alias kinds: what an alias's pattern is compared with.
+const (
+	// AliasKindTitle matches the parsed title, normalized like every other
+	// title lookup.
+	AliasKindTitle = "title"
+	// AliasKindRegex is a regular expression searched for in the file path.
+	AliasKindRegex = "regex"
+	// AliasKindFolder matches the name of any folder the file sits in,
+	// case-insensitively.
+	AliasKindFolder = "folder"
+)
+
+// Alias media types, as in conflicts.
+const (
+	AliasMediaMovie  = "movie"
+	AliasMediaSeries = "series"
+)
+
+// Alias is a manual override mapping a release title, path pattern or
+// folder name to the canonical title, year and provider IDs.
+type Alias struct {
+	ID        int64     `json:"id"`
+	Kind      string    `json:"kind"`
+	Pattern   string    `json:"pattern"`
+	MediaType string    `json:"media_type"`
+	Title     string    `json:"title"`
+	Year      *int      `json:"year,omitempty"`
+	TmdbID    *int      `json:"tmdb_id,omitempty"`
+	TvdbID    *int      `json:"tvdb_id,omitempty"`
+	ImdbID    *string   `json:"imdb_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ApplyTV replaces the parsed show's title and year. It reports whether
+// anything changed.
+func (a *Alias) ApplyTV(tv *naming.TVShowInfo) bool {
+	if a == nil || tv == nil {
+		return false
+	}
+	year := a.yearString(tv.Year)
+	changed := tv.Title != a.Title || tv.Year != year
+	tv.Title, tv.Year = a.Title, year
+	return changed
+}
+
+// ApplyMovie is ApplyTV for movies.
+func (a *Alias) ApplyMovie(movie *naming.MovieInfo) bool {
+	if a == nil || movie == nil {
+		return false
+	}
+	year := a.yearString(movie.Year)
+	changed := movie.Title != a.Title || movie.Year != year
+	movie.Title, movie.Year = a.Title, year
+	return changed
+}
+
+// yearString is the alias year, or parsed when the alias leaves it open.
+func (a *Alias) yearString(parsed string) string {
+	if a.Year == nil {
+		return parsed
+	}
+	return strconv.Itoa(*a.Year)
+}
+
+// aliasKey is the alias_normalized value pattern is stored and looked up
+// under.
+func aliasKey(kind, pattern string) (string, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return "", fmt.Errorf("alias pattern is empty")
+	}
+	switch kind {
+	case AliasKindTitle:
+		key := NormalizeTitle(pattern)
+		if key == "" {
+			return "", fmt.Errorf("alias title %q normalizes to nothing", pattern)
+		}
+		return key, nil
+	case AliasKindRegex:
+		if _, err := aliasRegexp(pattern); err != nil {
+			return "", fmt.Errorf("alias regex: %w", err)
+		}
+		return pattern, nil
+	case AliasKindFolder:
+		return strings.ToLower(pattern), nil
+	default:
+		return "", fmt.Errorf("unknown alias kind %q (want title, regex or folder)", kind)
+	}
+}
+
+var aliasRegexps sync.Map // pattern -> *regexp.Regexp
+
+func aliasRegexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := aliasRegexps.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	aliasRegexps.Store(pattern, re)
+	return re, nil
+}
+
+const aliasColumns = `id, kind, pattern, media_type, title, year, tmdb_id, tvdb_id, imdb_id, created_at`
+
+func scanAlias(row interface{ Scan(...any) error }) (*Alias, error) {
+	var a Alias
+	var year, tmdbID, tvdbID sql.NullInt64
+	var imdbID sql.NullString
+	var createdAt sql.NullTime
+	if err := row.Scan(&a.ID, &a.Kind, &a.Pattern, &a.MediaType, &a.Title,
+		&year, &tmdbID, &tvdbID, &imdbID, &createdAt); err != nil {
+		return nil, err
+	}
+	a.Year = intPtrFromNull(year)
+	a.TmdbID = intPtrFromNull(tmdbID)
+	a.TvdbID = intPtrFromNull(tvdbID)
+	if imdbID.Valid {
+		a.ImdbID = &imdbID.String
+	}
+	a.CreatedAt = createdAt.Time
+	return &a, nil
+}
+
+func intPtrFromNull(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	v := int(n.Int64)
+	return &v
+}
+
+// UpsertAlias validates a and stores it, replacing the alias with the same
+// kind, pattern and media type. It sets a.ID.
+func (m *MediaDB) UpsertAlias(a *Alias) error {
+	if a.MediaType != AliasMediaMovie && a.MediaType != AliasMediaSeries {
+		return fmt.Errorf("unknown alias media type %q (want movie or series)", a.MediaType)
+	}
+	a.Title = strings.TrimSpace(a.Title)
+	if a.Title == "" {
+		return fmt.Errorf("alias needs a canonical title")
+	}
+	key, err := aliasKey(a.Kind, a.Pattern)
+	if err != nil {
+		return err
+	}
+	a.Pattern = strings.TrimSpace(a.Pattern)
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now().UTC()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	err = m.db.QueryRow(`
+		INSERT INTO aliases (kind, pattern, alias_normalized, media_type, title, year, tmdb_id, tvdb_id, imdb_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(kind, alias_normalized, media_type) DO UPDATE SET
+			pattern = excluded.pattern,
+			title = excluded.title,
+			year = excluded.year,
+			tmdb_id = excluded.tmdb_id,
+			tvdb_id = excluded.tvdb_id,
+			imdb_id = excluded.imdb_id
+		RETURNING id`,
+		a.Kind, a.Pattern, key, a.MediaType, a.Title, a.Year, a.TmdbID, a.TvdbID, a.ImdbID, a.CreatedAt,
+	).Scan(&a.ID)
+	if err != nil {
+		return fmt.Errorf("UpsertAlias: %w", err)
+	}
+	return nil
+}
+
+// GetAlias returns one alias, or nil if there is none.
+func (m *MediaDB) GetAlias(id int64) (*Alias, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	a, err := scanAlias(m.db.QueryRow(`SELECT `+aliasColumns+` FROM aliases WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetAlias: %w", err)
+	}
+	return a, nil
+}
+
+// ListAliases returns every alias that maps to a title, in the order they
+// were added.
+func (m *MediaDB) ListAliases() ([]Alias, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.listAliasesLocked(``)
+}
+
+func (m *MediaDB) listAliasesLocked(mediaType string) ([]Alias, error) {
+	return m.queryAliasesLocked(mediaType, ``)
+}
+
+// queryAliasesLocked returns the aliases of mediaType, or of every type if
+// it is empty, that also satisfy the SQL condition cond, in the order they
+// were added.
+func (m *MediaDB) queryAliasesLocked(mediaType, cond string, args ...any) ([]Alias, error) {
+	query := `SELECT ` + aliasColumns + ` FROM aliases WHERE title != ''`
+	if cond != "" {
+		query += ` AND ` + cond
+	}
+	if mediaType != "" {
+		query += ` AND media_type = ?`
+		args = append(args, mediaType)
+	}
+	rows, err := m.db.Query(query+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ListAliases: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Alias
+	for rows.Next() {
+		a, err := scanAlias(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ListAliases: %w", err)
+		}
+		out = append(out, *a)
+	}
+	return out, rows.Err()
+}
+
+// DeleteAlias removes an alias. It reports whether there was one.
+func (m *MediaDB) DeleteAlias(id int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	res, err := m.db.Exec(`DELETE FROM aliases WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("DeleteAlias: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// MatchAlias returns the alias overriding a file at path that parsed as
+// title, or nil. Either may be empty. Regex aliases are tried first, then
+// folder aliases, then title aliases, so the most specific one wins.
+// Folder and title aliases are looked up by their stored key; only regex
+// aliases have to be read and tried one by one.
+func (m *MediaDB) MatchAlias(mediaType, path, title string) (*Alias, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if path != "" {
+		regexes, err := m.queryAliasesLocked(mediaType, `kind = ?`, AliasKindRegex)
+		if err != nil {
+			return nil, err
+		}
+		slashed := filepath.ToSlash(path)
+		for i := range regexes {
+			re, err := aliasRegexp(regexes[i].Pattern)
+			if err == nil && re.MatchString(slashed) {
+				return &regexes[i], nil
+			}
+		}
+
+		var folders []any
+		for _, part := range strings.Split(filepath.ToSlash(filepath.Dir(path)), "/") {
+			if part != "" && part != "." {
+				folders = append(folders, strings.ToLower(part))
+			}
+		}
+		if len(folders) > 0 {
+			cond := `kind = ? AND alias_normalized IN (?` + strings.Repeat(`, ?`, len(folders)-1) + `)`
+			matches, err := m.queryAliasesLocked(mediaType, cond, append([]any{AliasKindFolder}, folders...)...)
+			if err != nil || len(matches) > 0 {
+				return firstAlias(matches), err
+			}
+		}
+	}
+
+	if titleKey := NormalizeTitle(title); titleKey != "" {
+		matches, err := m.queryAliasesLocked(mediaType, `kind = ? AND alias_normalized = ?`, AliasKindTitle, titleKey)
+		return firstAlias(matches), err
+	}
+	return nil, nil
+}
+
+func firstAlias(aliases []Alias) *Alias {
+	if len(aliases) == 0 {
+		return nil
+	}
+	return &aliases[0]
+}
+
+// AliasIDs returns the alias whose canonical title and year are title and
+// year and that carries provider IDs, or nil. Naming templates use it for
+// items the database has no IDs for yet.
+func (m *MediaDB) AliasIDs(mediaType, title string, year int) (*Alias, error) {
+	m.mu.RLock()
+	aliases, err := m.listAliasesLocked(mediaType)
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	key := NormalizeTitle(title)
+	for i := range aliases {
+		a := &aliases[i]
+		if a.TmdbID == nil && a.TvdbID == nil && a.ImdbID == nil {
+			continue
+		}
+		if NormalizeTitle(a.Title) != key {
+			continue
+		}
+		if a.Year != nil && year != 0 && *a.Year != year {
+			continue
+		}
+		return a, nil
+	}
+	return nil, nil
+}
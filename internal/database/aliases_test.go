@@ -0,0 +1,97 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+)
+
+func TestAliases_MatchMostSpecificFirst(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	year := 2005
+	tvdb := 73244
+	for _, a := range []*Alias{
+		{Kind: AliasKindTitle, Pattern: "The Office", MediaType: AliasMediaSeries, Title: "The Office (US)", Year: &year, TvdbID: &tvdb},
+		{Kind: AliasKindFolder, Pattern: "The Office UK", MediaType: AliasMediaSeries, Title: "The Office", Year: intPtr(2001)},
+		{Kind: AliasKindRegex, Pattern: `(?i)office\.uk\.`, MediaType: AliasMediaSeries, Title: "The Office", Year: intPtr(2001)},
+	} {
+		if err := db.UpsertAlias(a); err != nil {
+			t.Fatalf("UpsertAlias(%s): %v", a.Pattern, err)
+		}
+	}
+
+	tests := []struct {
+		path, title string
+		wantYear    int
+	}{
+		{"/downloads/The.Office.S02E01.720p.mkv", "The Office", 2005},
+		{"/downloads/the office uk/The.Office.S02E01.720p.mkv", "The Office", 2001},
+		{"/downloads/The.Office.UK.S02E01.720p.mkv", "The Office UK", 2001},
+	}
+	for _, tt := range tests {
+		a, err := db.MatchAlias(AliasMediaSeries, tt.path, tt.title)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a == nil || a.Year == nil || *a.Year != tt.wantYear {
+			t.Errorf("MatchAlias(%s, %s) = %+v, want year %d", tt.path, tt.title, a, tt.wantYear)
+		}
+	}
+
+	if a, _ := db.MatchAlias(AliasMediaMovie, "/downloads/The.Office.S02E01.mkv", "The Office"); a != nil {
+		t.Errorf("series alias matched a movie: %+v", a)
+	}
+
+	tv := &naming.TVShowInfo{Title: "The Office", Season: 2, Episode: 1}
+	a, _ := db.MatchAlias(AliasMediaSeries, "", tv.Title)
+	if !a.ApplyTV(tv) || tv.Title != "The Office (US)" || tv.Year != "2005" {
+		t.Errorf("ApplyTV = %+v", tv)
+	}
+
+	ids, err := db.AliasIDs(AliasMediaSeries, "The Office (US)", 2005)
+	if err != nil || ids == nil || *ids.TvdbID != tvdb {
+		t.Errorf("AliasIDs = %+v, %v", ids, err)
+	}
+}
+
+func TestAliases_UpsertReplacesAndValidates(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	a := &Alias{Kind: AliasKindTitle, Pattern: "Shogun", MediaType: AliasMediaSeries, Title: "Shogun", Year: intPtr(1980)}
+	if err := db.UpsertAlias(a); err != nil {
+		t.Fatal(err)
+	}
+	b := &Alias{Kind: AliasKindTitle, Pattern: "shogun", MediaType: AliasMediaSeries, Title: "Shōgun", Year: intPtr(2024)}
+	if err := db.UpsertAlias(b); err != nil {
+		t.Fatal(err)
+	}
+	if b.ID != a.ID {
+		t.Errorf("same pattern should replace the alias, got ids %d and %d", a.ID, b.ID)
+	}
+	list, err := db.ListAliases()
+	if err != nil || len(list) != 1 || *list[0].Year != 2024 {
+		t.Fatalf("ListAliases = %+v, %v", list, err)
+	}
+
+	for _, bad := range []*Alias{
+		{Kind: AliasKindTitle, Pattern: "x", MediaType: "music", Title: "X"},
+		{Kind: AliasKindTitle, Pattern: "x", MediaType: AliasMediaMovie},
+		{Kind: AliasKindRegex, Pattern: "(", MediaType: AliasMediaMovie, Title: "X"},
+		{Kind: "glob", Pattern: "x", MediaType: AliasMediaMovie, Title: "X"},
+	} {
+		if err := db.UpsertAlias(bad); err == nil {
+			t.Errorf("UpsertAlias accepted %+v", bad)
+		}
+	}
+
+	ok, err := db.DeleteAlias(a.ID)
+	if err != nil || !ok {
+		t.Fatalf("DeleteAlias = %v, %v", ok, err)
+	}
+	if got, _ := db.GetAlias(a.ID); got != nil {
+		t.Errorf("alias still there: %+v", got)
+	}
+}
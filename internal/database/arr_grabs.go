@@ -0,0 +1,255 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+)
+
+// Sources of a download identity.
+const (
+	ArrSourceSonarr = "sonarr"
+	ArrSourceRadarr = "radarr"
+)
+
+// arrGrabRetention is how long a download identity is kept. Downloads
+// that have not finished by then are unlikely to.
+const arrGrabRetention = 30 * 24 * time.Hour
+
+// ArrGrab is what Sonarr or Radarr said a download is, from a Grab or
+// Download webhook. A grab covers the whole release; an import names the
+// one file it took, by its path in the download folder (SourcePath).
+type ArrGrab struct {
+	DownloadID   string
+	Source       string
+	EventType    string
+	ReleaseTitle string
+	SourcePath   string
+	// ArrID is the series or movie ID in Sonarr or Radarr.
+	ArrID  int
+	Title  string
+	Year   *int
+	TvdbID *int
+	TmdbID *int
+	ImdbID string
+	// Season and Episodes are the episodes a Sonarr download covers.
+	Season    *int
+	Episodes  []int
+	UpdatedAt time.Time
+}
+
+// IsTV reports whether the download is a Sonarr series download.
+func (g *ArrGrab) IsTV() bool {
+	return g != nil && g.Source == ArrSourceSonarr
+}
+
+// HasEpisode reports whether the download names exactly which episodes a
+// file holds: one episode, or one contiguous run from a single imported
+// file.
+func (g *ArrGrab) HasEpisode() bool {
+	if g == nil || g.Season == nil || len(g.Episodes) == 0 {
+		return false
+	}
+	if len(g.Episodes) == 1 {
+		return true
+	}
+	if g.SourcePath == "" {
+		return false
+	}
+	for i := 1; i < len(g.Episodes); i++ {
+		if g.Episodes[i] != g.Episodes[i-1]+1 {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyTV replaces the parsed show's title and year with the series', and
+// its episode numbers when HasEpisode. It reports whether anything changed.
+func (g *ArrGrab) ApplyTV(tv *naming.TVShowInfo) bool {
+	if g == nil || tv == nil {
+		return false
+	}
+	before := *tv
+	tv.Title, tv.Year = g.Title, g.yearString(tv.Year)
+	if g.HasEpisode() {
+		tv.Season = *g.Season
+		tv.Episode = g.Episodes[0]
+		tv.EpisodeEnd = 0
+		if n := len(g.Episodes); n > 1 {
+			tv.EpisodeEnd = g.Episodes[n-1]
+		}
+		tv.AbsoluteEpisode = 0
+		tv.EpisodeDate = ""
+	}
+	return *tv != before
+}
+
+// ApplyMovie replaces the parsed movie's title and year with the movie's.
+// It reports whether anything changed.
+func (g *ArrGrab) ApplyMovie(movie *naming.MovieInfo) bool {
+	if g == nil || movie == nil {
+		return false
+	}
+	year := g.yearString(movie.Year)
+	changed := movie.Title != g.Title || movie.Year != year
+	movie.Title, movie.Year = g.Title, year
+	return changed
+}
+
+func (g *ArrGrab) yearString(parsed string) string {
+	if g.Year == nil {
+		return parsed
+	}
+	return strconv.Itoa(*g.Year)
+}
+
+// releaseKey is the form release titles and download folder or file names
+// are compared in: lowercase letters and digits only, so "Show.S01.1080p"
+// and "Show S01 1080p" match.
+func releaseKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// UpsertArrGrab stores what Sonarr or Radarr said a download is, replacing
+// the identity recorded for the same download and file. Identities older
+// than a month are dropped on the way.
+func (m *MediaDB) UpsertArrGrab(g *ArrGrab) error {
+	g.DownloadID = strings.ToLower(strings.TrimSpace(g.DownloadID))
+	if g.DownloadID == "" {
+		return fmt.Errorf("download identity needs a download ID")
+	}
+	if g.Source != ArrSourceSonarr && g.Source != ArrSourceRadarr {
+		return fmt.Errorf("unknown download identity source %q (want sonarr or radarr)", g.Source)
+	}
+	g.Title = strings.TrimSpace(g.Title)
+	if g.Title == "" {
+		return fmt.Errorf("download identity needs a title")
+	}
+	if g.UpdatedAt.IsZero() {
+		g.UpdatedAt = time.Now().UTC()
+	}
+	sort.Ints(g.Episodes)
+	episodes := make([]string, len(g.Episodes))
+	for i, e := range g.Episodes {
+		episodes[i] = strconv.Itoa(e)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, err := m.db.Exec(`DELETE FROM arr_grabs WHERE updated_at < ?`, g.UpdatedAt.Add(-arrGrabRetention)); err != nil {
+		return fmt.Errorf("UpsertArrGrab: %w", err)
+	}
+	_, err := m.db.Exec(`
+		INSERT INTO arr_grabs (download_id, source, event_type, release_title, release_key, source_path,
+			arr_id, title, year, tvdb_id, tmdb_id, imdb_id, season, episodes, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(download_id, source_path) DO UPDATE SET
+			source = excluded.source,
+			event_type = excluded.event_type,
+			release_title = COALESCE(NULLIF(excluded.release_title, ''), release_title),
+			release_key = COALESCE(NULLIF(excluded.release_key, ''), release_key),
+			arr_id = excluded.arr_id,
+			title = excluded.title,
+			year = excluded.year,
+			tvdb_id = excluded.tvdb_id,
+			tmdb_id = excluded.tmdb_id,
+			imdb_id = excluded.imdb_id,
+			season = excluded.season,
+			episodes = excluded.episodes,
+			updated_at = excluded.updated_at`,
+		g.DownloadID, g.Source, g.EventType, g.ReleaseTitle, releaseKey(g.ReleaseTitle), g.SourcePath,
+		g.ArrID, g.Title, g.Year, g.TvdbID, g.TmdbID, g.ImdbID, g.Season, strings.Join(episodes, ","), g.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("UpsertArrGrab: %w", err)
+	}
+	return nil
+}
+
+const arrGrabColumns = `download_id, source, event_type, release_title, source_path,
+	arr_id, title, year, tvdb_id, tmdb_id, imdb_id, season, episodes, updated_at`
+
+func scanArrGrab(row interface{ Scan(...any) error }) (*ArrGrab, error) {
+	var g ArrGrab
+	var year, tvdbID, tmdbID, season sql.NullInt64
+	var episodes string
+	if err := row.Scan(&g.DownloadID, &g.Source, &g.EventType, &g.ReleaseTitle, &g.SourcePath,
+		&g.ArrID, &g.Title, &year, &tvdbID, &tmdbID, &g.ImdbID, &season, &episodes, &g.UpdatedAt); err != nil {
+		return nil, err
+	}
+	g.Year = intPtrFromNull(year)
+	g.TvdbID = intPtrFromNull(tvdbID)
+	g.TmdbID = intPtrFromNull(tmdbID)
+	g.Season = intPtrFromNull(season)
+	for _, e := range strings.Split(episodes, ",") {
+		if n, err := strconv.Atoi(e); err == nil {
+			g.Episodes = append(g.Episodes, n)
+		}
+	}
+	return &g, nil
+}
+
+// MatchArrGrab returns the download identity for a file at path, or nil.
+// An import that names the file wins; otherwise the newest identity whose
+// release title matches the file's name, or else one of the two folders
+// above it, the way download clients name what they fetch.
+func (m *MediaDB) MatchArrGrab(path string) (*ArrGrab, error) {
+	if path == "" {
+		return nil, nil
+	}
+	name := filepath.Base(path)
+	names := []string{strings.TrimSuffix(name, filepath.Ext(name))}
+	for dir := filepath.Dir(path); len(names) < 3; dir = filepath.Dir(dir) {
+		base := filepath.Base(dir)
+		if base == dir || base == "." || base == string(filepath.Separator) {
+			break
+		}
+		names = append(names, base)
+	}
+
+	// Rank candidates: the file itself, then its name, then each folder.
+	where := `source_path = ?`
+	rank := `CASE WHEN source_path = ? THEN 0`
+	whereArgs := []any{path}
+	rankArgs := []any{path}
+	for i, n := range names {
+		key := releaseKey(n)
+		if key == "" {
+			continue
+		}
+		where += ` OR release_key = ?`
+		whereArgs = append(whereArgs, key)
+		rank += ` WHEN release_key = ? THEN ` + strconv.Itoa(i+1)
+		rankArgs = append(rankArgs, key)
+	}
+	rank += ` END`
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	g, err := scanArrGrab(m.db.QueryRow(`
+		SELECT `+arrGrabColumns+` FROM arr_grabs
+		 WHERE `+where+`
+		 ORDER BY `+rank+`, updated_at DESC
+		 LIMIT 1`, append(whereArgs, rankArgs...)...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("MatchArrGrab: %w", err)
+	}
+	return g, nil
+}
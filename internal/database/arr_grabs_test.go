@@ -0,0 +1,95 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+)
+
+func TestArrGrabs_MatchMostSpecificFirst(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	season := 1
+	for _, g := range []*ArrGrab{
+		{DownloadID: "PACK", Source: ArrSourceSonarr, EventType: "Grab", ReleaseTitle: "Show.S01.1080p.WEB-GRP", Title: "Show", Season: &season, Episodes: []int{1, 2, 3}},
+		{DownloadID: "pack", Source: ArrSourceSonarr, EventType: "Download", ReleaseTitle: "Show.S01E02.1080p.WEB-GRP",
+			SourcePath: "/downloads/Show.S01.1080p.WEB-GRP/Show.S01E02.1080p.WEB-GRP.mkv", Title: "Show", Season: &season, Episodes: []int{2}},
+		{DownloadID: "other", Source: ArrSourceRadarr, EventType: "Grab", ReleaseTitle: "Film 2020 1080p", Title: "Film"},
+	} {
+		if err := db.UpsertArrGrab(g); err != nil {
+			t.Fatalf("UpsertArrGrab(%s): %v", g.ReleaseTitle, err)
+		}
+	}
+
+	tests := []struct {
+		path         string
+		wantEvent    string
+		wantEpisodes int
+	}{
+		{"/downloads/Show.S01.1080p.WEB-GRP/Show.S01E02.1080p.WEB-GRP.mkv", "Download", 1},
+		{"/downloads/Show.S01.1080p.WEB-GRP/Show.S01E03.1080p.WEB-GRP.mkv", "Grab", 3},
+		{"/downloads/Film.2020.1080p/film.mkv", "Grab", 0},
+	}
+	for _, tt := range tests {
+		g, err := db.MatchArrGrab(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g == nil || g.EventType != tt.wantEvent || len(g.Episodes) != tt.wantEpisodes {
+			t.Errorf("MatchArrGrab(%s) = %+v, want %s with %d episodes", tt.path, g, tt.wantEvent, tt.wantEpisodes)
+		}
+	}
+
+	if g, err := db.MatchArrGrab("/downloads/Unrelated.2021.mkv"); err != nil || g != nil {
+		t.Errorf("MatchArrGrab(unrelated) = %+v, %v; want nil", g, err)
+	}
+}
+
+func TestArrGrabs_PrunesOldIdentities(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	old := time.Now().UTC().Add(-2 * arrGrabRetention)
+	if err := db.UpsertArrGrab(&ArrGrab{DownloadID: "old", Source: ArrSourceRadarr, EventType: "Grab", ReleaseTitle: "Old.Film.2001", Title: "Old Film", UpdatedAt: old}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.UpsertArrGrab(&ArrGrab{DownloadID: "new", Source: ArrSourceRadarr, EventType: "Grab", ReleaseTitle: "New.Film.2024", Title: "New Film"}); err != nil {
+		t.Fatal(err)
+	}
+	if g, _ := db.MatchArrGrab("/downloads/Old.Film.2001.mkv"); g != nil {
+		t.Errorf("identity older than the retention still matched: %+v", g)
+	}
+	if err := db.UpsertArrGrab(&ArrGrab{Source: ArrSourceRadarr, Title: "No ID"}); err == nil {
+		t.Error("UpsertArrGrab accepted an identity without a download ID")
+	}
+}
+
+func TestArrGrab_ApplyTV(t *testing.T) {
+	season := 2
+	single := &ArrGrab{Source: ArrSourceSonarr, Title: "Show", Year: intPtr(2019), Season: &season, Episodes: []int{5}}
+	tv := &naming.TVShowInfo{Title: "Shw", Season: 1, Episode: 25, AbsoluteEpisode: 25}
+	if !single.ApplyTV(tv) {
+		t.Fatal("ApplyTV reported no change")
+	}
+	if tv.Title != "Show" || tv.Year != "2019" || tv.Season != 2 || tv.Episode != 5 || tv.AbsoluteEpisode != 0 {
+		t.Errorf("ApplyTV = %+v", tv)
+	}
+
+	// A pack grab names the show, not which episode a file is.
+	pack := &ArrGrab{Source: ArrSourceSonarr, Title: "Show", Season: &season, Episodes: []int{1, 2, 3}}
+	tv = &naming.TVShowInfo{Title: "Shw", Season: 2, Episode: 3}
+	pack.ApplyTV(tv)
+	if tv.Title != "Show" || tv.Episode != 3 || tv.EpisodeEnd != 0 {
+		t.Errorf("ApplyTV(pack) = %+v", tv)
+	}
+
+	// An import of one multi-episode file names its span.
+	file := &ArrGrab{Source: ArrSourceSonarr, Title: "Show", SourcePath: "/dl/Show.S02E01E02.mkv", Season: &season, Episodes: []int{1, 2}}
+	tv = &naming.TVShowInfo{}
+	file.ApplyTV(tv)
+	if tv.Episode != 1 || tv.EpisodeEnd != 2 {
+		t.Errorf("ApplyTV(multi-episode import) = %+v", tv)
+	}
+}
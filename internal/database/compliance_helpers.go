@@ -19,7 +19,13 @@ import (
 //   - isCompliant: true if file follows all Jellyfin naming conventions
 //   - issues: List of specific compliance violations (empty if compliant)
 func CheckCompliance(fullPath string, libraryRoot string) (isCompliant bool, issues []string) {
-	checker := compliance.NewChecker(libraryRoot)
+	return CheckComplianceWithTemplates(fullPath, libraryRoot, naming.Templates{})
+}
+
+// CheckComplianceWithTemplates is CheckCompliance for a library with naming
+// templates; names are checked against tmpl instead of the built-in layout.
+func CheckComplianceWithTemplates(fullPath string, libraryRoot string, tmpl naming.Templates) (isCompliant bool, issues []string) {
+	checker := compliance.NewChecker(libraryRoot, compliance.WithTemplates(tmpl))
 	result := checker.CheckFile(fullPath)
 	return result.IsCompliant, result.Issues
 }
@@ -30,6 +30,7 @@ const (
 	TaskKindOrphanSource   = "orphan_source"   // remove empty watch-dir source dir
 	TaskKindStuckSync      = "stuck_sync"      // mark stuck sync_log row as error
 	TaskKindSubdirMismatch = "subdir_mismatch" // flag-only, naming workflow
+	TaskKindOrphanSidecar  = "orphan_sidecar"  // rename metadata left behind by a past move after the only video beside it
 
 	// Convergence (housekeeper ↔ consolidator/cleanup): three workflows.
 	// Duplicate-removal workflow (movies + TV):
@@ -381,6 +382,36 @@ func (m *MediaDB) CountHousekeepingTasks() (map[string]int, error) {
 	return out, rows.Err()
 }
 
+// HousekeepingTaskCount is the number of tasks of one kind in one status.
+type HousekeepingTaskCount struct {
+	Kind   string
+	Status string
+	Count  int
+}
+
+// CountHousekeepingTasksByKind returns task counts grouped by kind and
+// status, ordered by both.
+func (m *MediaDB) CountHousekeepingTasksByKind() ([]HousekeepingTaskCount, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	rows, err := m.db.Query(`SELECT kind, status, COUNT(*) FROM housekeeping_tasks GROUP BY kind, status ORDER BY kind, status`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []HousekeepingTaskCount
+	for rows.Next() {
+		var c HousekeepingTaskCount
+		if err := rows.Scan(&c.Kind, &c.Status, &c.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
 // RetryHousekeepingTask resets a failed/canceled/flagged task to pending.
 func (m *MediaDB) RetryHousekeepingTask(id int64) error {
 	m.mu.Lock()
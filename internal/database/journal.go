@@ -0,0 +1,269 @@
+package database
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Journal operation kinds.
+const (
+	// OpMove means BeforePath now lives at AfterPath.
+	OpMove = "move"
+	// OpCopy means AfterPath was created from BeforePath, which stayed in
+	// place (a copy, hardlink or reflink).
+	OpCopy = "copy"
+	// OpDelete means BeforePath was removed. AfterPath, when set, is where
+	// the file was kept instead (e.g. a recycle bin).
+	OpDelete = "delete"
+)
+
+// Journal operation sources: the workflow that mutated the library.
+const (
+	OpSourceOrganize    = "organize"
+	OpSourceMergeMove   = "merge_move"
+	OpSourceParserDrift = "parser_drift"
+	OpSourceConsolidate = "consolidate"
+	OpSourceDuplicate   = "duplicate_delete"
+	OpSourceSidecar     = "orphan_sidecar"
+	OpSourceUndo        = "undo"
+)
+
+// JournalOp is one filesystem mutation in the operation journal.
+type JournalOp struct {
+	ID         int64      `json:"id"`
+	BatchID    string     `json:"batch_id"`
+	Source     string     `json:"source"`
+	Kind       string     `json:"kind"`
+	BeforePath string     `json:"before_path,omitempty"`
+	AfterPath  string     `json:"after_path,omitempty"`
+	Reversible bool       `json:"reversible"`
+	Snapshot   *OpState   `json:"snapshot,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UndoneAt   *time.Time `json:"undone_at,omitempty"`
+	UndoBatch  string     `json:"undo_batch,omitempty"`
+}
+
+// OpState is the database state an operation replaced, captured so undo
+// can put it back.
+type OpState struct {
+	MediaFile *MediaFile `json:"media_file,omitempty"`
+
+	SeriesID      int64  `json:"series_id,omitempty"`
+	SeriesPath    string `json:"series_path,omitempty"`
+	SeriesLibrary string `json:"series_library,omitempty"`
+
+	MovieID      int64  `json:"movie_id,omitempty"`
+	MoviePath    string `json:"movie_path,omitempty"`
+	MovieLibrary string `json:"movie_library,omitempty"`
+}
+
+// NewJournalBatch returns a fresh batch ID for operations that belong
+// together, e.g. "organize-20260102T030405-9f3a1c".
+func NewJournalBatch(source string) string {
+	var b [3]byte
+	_, _ = rand.Read(b[:])
+	return fmt.Sprintf("%s-%s-%s", source, time.Now().UTC().Format("20060102T150405"), hex.EncodeToString(b[:]))
+}
+
+// RecordOperation journals a filesystem mutation. Call it after the
+// filesystem change and before the database is updated to match: when
+// op.Snapshot is nil, the media_files row at BeforePath and the series or
+// movie it belongs to are captured as the state to restore on undo.
+func (m *MediaDB) RecordOperation(op JournalOp) (int64, error) {
+	if m == nil {
+		return 0, nil
+	}
+	if op.Snapshot == nil && op.BeforePath != "" {
+		op.Snapshot = m.CaptureOpState(op.BeforePath)
+	}
+	var snapshot sql.NullString
+	if op.Snapshot != nil {
+		data, err := json.Marshal(op.Snapshot)
+		if err != nil {
+			return 0, fmt.Errorf("RecordOperation: %w", err)
+		}
+		snapshot = sql.NullString{String: string(data), Valid: true}
+	}
+	if op.CreatedAt.IsZero() {
+		op.CreatedAt = time.Now().UTC()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	res, err := m.db.Exec(`
+		INSERT INTO operation_journal
+			(batch_id, source, kind, before_path, after_path, reversible, snapshot_json, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		op.BatchID, op.Source, op.Kind, nullStr(op.BeforePath), nullStr(op.AfterPath),
+		op.Reversible, snapshot, op.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("RecordOperation: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// CaptureOpState reads the rows an operation on path is about to change.
+// A nil result means there was nothing worth restoring.
+func (m *MediaDB) CaptureOpState(path string) *OpState {
+	file, err := m.GetMediaFile(path)
+	if err != nil || file == nil {
+		return nil
+	}
+	state := &OpState{MediaFile: file}
+	if file.ParentSeriesID != nil {
+		if s, err := m.GetSeriesByID(*file.ParentSeriesID); err == nil && s != nil {
+			state.SeriesID, state.SeriesPath, state.SeriesLibrary = s.ID, s.CanonicalPath, s.LibraryRoot
+		}
+	}
+	if file.ParentMovieID != nil {
+		if mov, err := m.GetMovieByID(*file.ParentMovieID); err == nil && mov != nil {
+			state.MovieID, state.MoviePath, state.MovieLibrary = mov.ID, mov.CanonicalPath, mov.LibraryRoot
+		}
+	}
+	return state
+}
+
+const journalColumns = `id, batch_id, source, kind, before_path, after_path, reversible,
+	snapshot_json, created_at, undone_at, undo_batch_id`
+
+func scanJournalOp(row interface{ Scan(...any) error }) (*JournalOp, error) {
+	var op JournalOp
+	var before, after, snapshot, undoBatch sql.NullString
+	var undoneAt sql.NullTime
+	if err := row.Scan(&op.ID, &op.BatchID, &op.Source, &op.Kind, &before, &after,
+		&op.Reversible, &snapshot, &op.CreatedAt, &undoneAt, &undoBatch); err != nil {
+		return nil, err
+	}
+	op.BeforePath = before.String
+	op.AfterPath = after.String
+	op.UndoBatch = undoBatch.String
+	if undoneAt.Valid {
+		t := undoneAt.Time
+		op.UndoneAt = &t
+	}
+	if snapshot.Valid && snapshot.String != "" {
+		var state OpState
+		if err := json.Unmarshal([]byte(snapshot.String), &state); err != nil {
+			return nil, fmt.Errorf("operation %d snapshot: %w", op.ID, err)
+		}
+		op.Snapshot = &state
+	}
+	return &op, nil
+}
+
+func (m *MediaDB) queryJournal(query string, args ...any) ([]JournalOp, error) {
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []JournalOp
+	for rows.Next() {
+		op, err := scanJournalOp(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *op)
+	}
+	return out, rows.Err()
+}
+
+// GetOperation returns one journal entry, or nil if there is none.
+func (m *MediaDB) GetOperation(id int64) (*JournalOp, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	op, err := scanJournalOp(m.db.QueryRow(`SELECT `+journalColumns+` FROM operation_journal WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetOperation: %w", err)
+	}
+	return op, nil
+}
+
+// ListOperationBatch returns a batch's operations in the order they ran.
+func (m *MediaDB) ListOperationBatch(batchID string) ([]JournalOp, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ops, err := m.queryJournal(`SELECT `+journalColumns+` FROM operation_journal
+		WHERE batch_id = ? ORDER BY id`, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("ListOperationBatch: %w", err)
+	}
+	return ops, nil
+}
+
+// ListOperations returns the newest journal entries first. source filters
+// by workflow when non-empty.
+func (m *MediaDB) ListOperations(source string, limit int) ([]JournalOp, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if limit <= 0 || limit > 10000 {
+		limit = 100
+	}
+	ops, err := m.queryJournal(`SELECT `+journalColumns+` FROM operation_journal
+		WHERE (? = '' OR source = ?) ORDER BY id DESC LIMIT ?`, source, source, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ListOperations: %w", err)
+	}
+	return ops, nil
+}
+
+// MarkOperationUndone records that undoBatch reversed operation id.
+func (m *MediaDB) MarkOperationUndone(id int64, undoBatch string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, err := m.db.Exec(`UPDATE operation_journal SET undone_at = ?, undo_batch_id = ? WHERE id = ?`,
+		time.Now().UTC(), undoBatch, id)
+	return err
+}
+
+// LaterOperationFrom returns the first live operation after id that moved
+// or removed path, or nil. Undo refuses to restore over it.
+func (m *MediaDB) LaterOperationFrom(path string, id int64) (*JournalOp, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	op, err := scanJournalOp(m.db.QueryRow(`SELECT `+journalColumns+` FROM operation_journal
+		WHERE before_path = ? AND id > ? AND kind != ? AND undone_at IS NULL
+		ORDER BY id LIMIT 1`, path, id, OpCopy))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("LaterOperationFrom: %w", err)
+	}
+	return op, nil
+}
+
+// LiveDeleteInto returns the reversible, not yet undone delete that kept
+// its file at path, or nil.
+func (m *MediaDB) LiveDeleteInto(path string) (*JournalOp, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	op, err := scanJournalOp(m.db.QueryRow(`SELECT `+journalColumns+` FROM operation_journal
+		WHERE after_path = ? AND kind = ? AND reversible = 1 AND undone_at IS NULL
+		ORDER BY id DESC LIMIT 1`, path, OpDelete))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("LiveDeleteInto: %w", err)
+	}
+	return op, nil
+}
+
+// ExpireDeletesInto marks deletes that kept their file at path as no
+// longer reversible, once that copy is gone for good.
+func (m *MediaDB) ExpireDeletesInto(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, err := m.db.Exec(`UPDATE operation_journal SET reversible = 0
+		WHERE after_path = ? AND kind = ?`, path, OpDelete)
+	return err
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOperationJournal_RecordListAndUndo(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	movie := &Movie{Title: "Heat", Year: 1995, CanonicalPath: "/movies/Heat (1995)", LibraryRoot: "/movies", Source: "jellywatch"}
+	if _, err := db.UpsertMovie(movie); err != nil {
+		t.Fatalf("UpsertMovie: %v", err)
+	}
+	year := 1995
+	file := &MediaFile{
+		Path:            "/movies/Heat (1995)/Heat.mkv",
+		Size:            1,
+		ModifiedAt:      time.Now(),
+		MediaType:       "movie",
+		ParentMovieID:   &movie.ID,
+		NormalizedTitle: "heat",
+		Year:            &year,
+		Source:          "filesystem",
+		LibraryRoot:     "/movies",
+	}
+	if err := db.UpsertMediaFile(file); err != nil {
+		t.Fatalf("UpsertMediaFile: %v", err)
+	}
+
+	batch := NewJournalBatch(OpSourceConsolidate)
+	moveID, err := db.RecordOperation(JournalOp{
+		BatchID:    batch,
+		Source:     OpSourceConsolidate,
+		Kind:       OpMove,
+		BeforePath: file.Path,
+		AfterPath:  "/movies/Heat (1995)/Heat (1995).mkv",
+		Reversible: true,
+	})
+	if err != nil {
+		t.Fatalf("RecordOperation(move): %v", err)
+	}
+	if _, err := db.RecordOperation(JournalOp{BatchID: batch, Source: OpSourceConsolidate, Kind: OpDelete, BeforePath: "/movies/Heat (1995)/Heat.sample.mkv"}); err != nil {
+		t.Fatalf("RecordOperation(delete): %v", err)
+	}
+	if _, err := db.RecordOperation(JournalOp{BatchID: "other", Source: OpSourceOrganize, Kind: OpMove, BeforePath: "/dl/x.mkv", AfterPath: "/movies/X/x.mkv", Reversible: true}); err != nil {
+		t.Fatalf("RecordOperation(other): %v", err)
+	}
+
+	op, err := db.GetOperation(moveID)
+	if err != nil || op == nil {
+		t.Fatalf("GetOperation = %v, %v", op, err)
+	}
+	if op.Snapshot == nil || op.Snapshot.MediaFile == nil || op.Snapshot.MediaFile.Path != file.Path {
+		t.Fatalf("snapshot = %+v, want the media_files row at the before path", op.Snapshot)
+	}
+	if op.Snapshot.MovieID != movie.ID || op.Snapshot.MoviePath != movie.CanonicalPath {
+		t.Errorf("snapshot movie = %d %q, want %d %q", op.Snapshot.MovieID, op.Snapshot.MoviePath, movie.ID, movie.CanonicalPath)
+	}
+
+	ops, err := db.ListOperationBatch(batch)
+	if err != nil || len(ops) != 2 || ops[0].ID != moveID || ops[1].Kind != OpDelete || ops[1].Reversible {
+		t.Fatalf("ListOperationBatch = %+v, %v", ops, err)
+	}
+	ops, err = db.ListOperations(OpSourceOrganize, 0)
+	if err != nil || len(ops) != 1 || ops[0].BatchID != "other" {
+		t.Fatalf("ListOperations(organize) = %+v, %v", ops, err)
+	}
+	ops, _ = db.ListOperations("", 10)
+	if len(ops) != 3 || ops[0].ID < ops[2].ID {
+		t.Fatalf("ListOperations should return newest first, got %+v", ops)
+	}
+
+	if later, err := db.LaterOperationFrom(file.Path, 0); err != nil || later == nil || later.ID != moveID {
+		t.Fatalf("LaterOperationFrom = %+v, %v", later, err)
+	}
+	if err := db.MarkOperationUndone(moveID, "undo-1"); err != nil {
+		t.Fatalf("MarkOperationUndone: %v", err)
+	}
+	op, _ = db.GetOperation(moveID)
+	if op.UndoneAt == nil || op.UndoBatch != "undo-1" {
+		t.Errorf("undone op = %+v", op)
+	}
+	if later, _ := db.LaterOperationFrom(file.Path, 0); later != nil {
+		t.Errorf("LaterOperationFrom should ignore undone operations, got %d", later.ID)
+	}
+}
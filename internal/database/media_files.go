@@ -26,6 +26,7 @@ type MediaFile struct {
 	Year            *int
 	Season          *int // NULL for movies
 	Episode         *int // NULL for movies
+	EpisodeEnd      *int // last episode of a multi-episode file; NULL otherwise
 
 	// Quality metadata
 	Resolution   string
@@ -80,13 +81,13 @@ func (m *MediaDB) UpsertMediaFile(file *MediaFile) error {
 		INSERT INTO media_files (
 			path, size, modified_at,
 			media_type, parent_movie_id, parent_series_id, parent_episode_id,
-			normalized_title, year, season, episode,
+			normalized_title, year, season, episode, episode_end,
 			resolution, source_type, codec, audio_format, quality_score,
 			is_jellyfin_compliant, compliance_issues,
 			source, source_priority, library_root,
 			confidence, parse_method, needs_review,
 			updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
 		ON CONFLICT(path) DO UPDATE SET
 			size = excluded.size,
 			modified_at = excluded.modified_at,
@@ -98,6 +99,7 @@ func (m *MediaDB) UpsertMediaFile(file *MediaFile) error {
 			year = excluded.year,
 			season = excluded.season,
 			episode = excluded.episode,
+			episode_end = excluded.episode_end,
 			resolution = excluded.resolution,
 			source_type = excluded.source_type,
 			codec = excluded.codec,
@@ -124,7 +126,7 @@ func (m *MediaDB) UpsertMediaFile(file *MediaFile) error {
 		query,
 		file.Path, file.Size, file.ModifiedAt,
 		file.MediaType, file.ParentMovieID, file.ParentSeriesID, file.ParentEpisodeID,
-		file.NormalizedTitle, file.Year, file.Season, file.Episode,
+		file.NormalizedTitle, file.Year, file.Season, file.Episode, file.EpisodeEnd,
 		file.Resolution, file.SourceType, file.Codec, file.AudioFormat, file.QualityScore,
 		file.IsJellyfinCompliant, string(complianceJSON),
 		file.Source, file.SourcePriority, file.LibraryRoot,
@@ -137,6 +139,9 @@ func (m *MediaDB) UpsertMediaFile(file *MediaFile) error {
 	if err := m.db.QueryRow(`SELECT id FROM media_files WHERE path = ?`, file.Path).Scan(&file.ID); err != nil {
 		return fmt.Errorf("failed to read media file id after upsert: %w", err)
 	}
+	if err := m.applyPendingProbeLocked(file.Path); err != nil {
+		return fmt.Errorf("failed to apply pending probe: %w", err)
+	}
 
 	return nil
 }
@@ -145,7 +150,7 @@ func (m *MediaDB) UpsertMediaFile(file *MediaFile) error {
 const mediaFileColumns = `
 	id, path, size, modified_at,
 	media_type, parent_movie_id, parent_series_id, parent_episode_id,
-	normalized_title, year, season, episode,
+	normalized_title, year, season, episode, episode_end,
 	resolution, source_type, codec, audio_format, quality_score,
 	confidence, parse_method, needs_review,
 	is_jellyfin_compliant, compliance_issues,
@@ -162,7 +167,7 @@ func scanMediaFileRow(row *sql.Row) (*MediaFile, error) {
 	err := row.Scan(
 		&file.ID, &file.Path, &file.Size, &file.ModifiedAt,
 		&file.MediaType, &file.ParentMovieID, &file.ParentSeriesID, &file.ParentEpisodeID,
-		&file.NormalizedTitle, &file.Year, &file.Season, &file.Episode,
+		&file.NormalizedTitle, &file.Year, &file.Season, &file.Episode, &file.EpisodeEnd,
 		&file.Resolution, &file.SourceType, &file.Codec, &file.AudioFormat, &file.QualityScore,
 		&file.Confidence, &file.ParseMethod, &needsReviewInt,
 		&file.IsJellyfinCompliant, &complianceJSON,
@@ -229,6 +234,7 @@ func (m *MediaDB) UpdateMediaFile(file *MediaFile) error {
 			year = ?,
 			season = ?,
 			episode = ?,
+			episode_end = ?,
 			resolution = ?,
 			source_type = ?,
 			codec = ?,
@@ -256,7 +262,7 @@ func (m *MediaDB) UpdateMediaFile(file *MediaFile) error {
 		query,
 		file.Path, file.Size, file.ModifiedAt,
 		file.MediaType, file.ParentMovieID, file.ParentSeriesID, file.ParentEpisodeID,
-		file.NormalizedTitle, file.Year, file.Season, file.Episode,
+		file.NormalizedTitle, file.Year, file.Season, file.Episode, file.EpisodeEnd,
 		file.Resolution, file.SourceType, file.Codec, file.AudioFormat, file.QualityScore,
 		file.IsJellyfinCompliant, string(complianceJSON),
 		file.Source, file.SourcePriority, file.LibraryRoot,
@@ -332,7 +338,7 @@ func (m *MediaDB) GetAllMediaFiles() ([]*MediaFile, error) {
 		SELECT
 			id, path, size, modified_at,
 			media_type, parent_movie_id, parent_series_id, parent_episode_id,
-			normalized_title, year, season, episode,
+			normalized_title, year, season, episode, episode_end,
 			resolution, source_type, codec, audio_format, quality_score,
 			confidence, parse_method, needs_review,
 			is_jellyfin_compliant, compliance_issues,
@@ -357,7 +363,7 @@ func (m *MediaDB) GetAllMediaFiles() ([]*MediaFile, error) {
 		err := rows.Scan(
 			&file.ID, &file.Path, &file.Size, &file.ModifiedAt,
 			&file.MediaType, &file.ParentMovieID, &file.ParentSeriesID, &file.ParentEpisodeID,
-			&file.NormalizedTitle, &file.Year, &file.Season, &file.Episode,
+			&file.NormalizedTitle, &file.Year, &file.Season, &file.Episode, &file.EpisodeEnd,
 			&file.Resolution, &file.SourceType, &file.Codec, &file.AudioFormat, &file.QualityScore,
 			&file.Confidence, &file.ParseMethod, &needsReviewInt,
 			&file.IsJellyfinCompliant, &complianceJSON,
@@ -392,7 +398,7 @@ func (m *MediaDB) GetMediaFilesByNormalizedKey(title string, year int, season, e
 		SELECT
 			id, path, size, modified_at,
 			media_type, parent_movie_id, parent_series_id, parent_episode_id,
-			normalized_title, year, season, episode,
+			normalized_title, year, season, episode, episode_end,
 			resolution, source_type, codec, audio_format, quality_score,
 			confidence, parse_method, needs_review,
 			is_jellyfin_compliant, compliance_issues,
@@ -434,7 +440,7 @@ func (m *MediaDB) GetMediaFilesByNormalizedKey(title string, year int, season, e
 		err := rows.Scan(
 			&file.ID, &file.Path, &file.Size, &file.ModifiedAt,
 			&file.MediaType, &file.ParentMovieID, &file.ParentSeriesID, &file.ParentEpisodeID,
-			&file.NormalizedTitle, &file.Year, &file.Season, &file.Episode,
+			&file.NormalizedTitle, &file.Year, &file.Season, &file.Episode, &file.EpisodeEnd,
 			&file.Resolution, &file.SourceType, &file.Codec, &file.AudioFormat, &file.QualityScore,
 			&file.Confidence, &file.ParseMethod, &needsReviewInt,
 			&file.IsJellyfinCompliant, &complianceJSON,
@@ -568,7 +574,7 @@ func (m *MediaDB) getLowConfidenceFiles(threshold float64, limit int, scopePath
 		SELECT
 			id, path, size, modified_at,
 			media_type, parent_movie_id, parent_series_id, parent_episode_id,
-			normalized_title, year, season, episode,
+			normalized_title, year, season, episode, episode_end,
 			resolution, source_type, codec, audio_format, quality_score,
 			confidence, parse_method, needs_review,
 			is_jellyfin_compliant, compliance_issues,
@@ -602,7 +608,7 @@ func (m *MediaDB) getLowConfidenceFiles(threshold float64, limit int, scopePath
 		err := rows.Scan(
 			&file.ID, &file.Path, &file.Size, &file.ModifiedAt,
 			&file.MediaType, &file.ParentMovieID, &file.ParentSeriesID, &file.ParentEpisodeID,
-			&file.NormalizedTitle, &file.Year, &file.Season, &file.Episode,
+			&file.NormalizedTitle, &file.Year, &file.Season, &file.Episode, &file.EpisodeEnd,
 			&file.Resolution, &file.SourceType, &file.Codec, &file.AudioFormat, &file.QualityScore,
 			&file.Confidence, &file.ParseMethod, &file.NeedsReview,
 			&file.IsJellyfinCompliant, &complianceJSON,
@@ -683,7 +689,7 @@ func (m *MediaDB) GetFilesNeverAIParsed(libraryRoot string, limit int) ([]*Media
 SELECT
 id, path, size, modified_at,
 media_type, parent_movie_id, parent_series_id, parent_episode_id,
-normalized_title, year, season, episode,
+normalized_title, year, season, episode, episode_end,
 resolution, source_type, codec, audio_format, quality_score,
 confidence, parse_method, needs_review,
 is_jellyfin_compliant, compliance_issues,
@@ -714,7 +720,7 @@ WHERE (parse_method IS NULL OR parse_method != 'ai')
 		err := rows.Scan(
 			&file.ID, &file.Path, &file.Size, &file.ModifiedAt,
 			&file.MediaType, &file.ParentMovieID, &file.ParentSeriesID, &file.ParentEpisodeID,
-			&file.NormalizedTitle, &file.Year, &file.Season, &file.Episode,
+			&file.NormalizedTitle, &file.Year, &file.Season, &file.Episode, &file.EpisodeEnd,
 			&file.Resolution, &file.SourceType, &file.Codec, &file.AudioFormat, &file.QualityScore,
 			&file.Confidence, &file.ParseMethod, &file.NeedsReview,
 			&file.IsJellyfinCompliant, &complianceJSON,
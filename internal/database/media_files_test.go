@@ -372,6 +372,61 @@ func TestDuplicateDetection(t *testing.T) {
 	}
 }
 
+func TestDuplicateEpisodeDetection_MultiEpisodeSpans(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "jellywatch-test-*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenPath(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	year, season, e01, e02 := 2020, 1, 1, 2
+	files := []*MediaFile{
+		// Two copies of the S01E01-E02 double episode: a real duplicate group.
+		{Path: "/a/Show S01E01-E02.mkv", MediaType: "episode", NormalizedTitle: "show", Year: &year, Season: &season, Episode: &e01, EpisodeEnd: &e02, QualityScore: 300},
+		{Path: "/b/Show.S01E01E02.720p.mkv", MediaType: "episode", NormalizedTitle: "show", Year: &year, Season: &season, Episode: &e01, EpisodeEnd: &e02, QualityScore: 200},
+		// A standalone S01E01 shares the first episode but is not a duplicate.
+		{Path: "/c/Show S01E01.mkv", MediaType: "episode", NormalizedTitle: "show", Year: &year, Season: &season, Episode: &e01, QualityScore: 400},
+	}
+	for _, f := range files {
+		if err := db.UpsertMediaFile(f); err != nil {
+			t.Fatalf("failed to insert media file: %v", err)
+		}
+	}
+
+	got, err := db.GetMediaFile("/a/Show S01E01-E02.mkv")
+	if err != nil || got == nil {
+		t.Fatalf("GetMediaFile: %v", err)
+	}
+	if got.EpisodeEnd == nil || *got.EpisodeEnd != 2 {
+		t.Fatalf("expected episode_end 2 to round-trip, got %v", got.EpisodeEnd)
+	}
+
+	duplicates, err := db.FindDuplicateEpisodes()
+	if err != nil {
+		t.Fatalf("failed to find duplicate episodes: %v", err)
+	}
+	if len(duplicates) != 1 {
+		t.Fatalf("expected 1 duplicate group, got %d", len(duplicates))
+	}
+	group := duplicates[0]
+	if group.EpisodeEnd == nil || *group.EpisodeEnd != 2 {
+		t.Errorf("expected group span to end at E02, got %v", group.EpisodeEnd)
+	}
+	if len(group.Files) != 2 {
+		t.Fatalf("expected 2 files in group, got %d", len(group.Files))
+	}
+	if group.BestFile.Path != "/a/Show S01E01-E02.mkv" {
+		t.Errorf("unexpected best file %s", group.BestFile.Path)
+	}
+}
+
 func TestEpisodeCRUD(t *testing.T) {
 	// Create temporary database
 	tmpFile, err := os.CreateTemp("", "jellywatch-test-*.db")
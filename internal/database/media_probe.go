@@ -0,0 +1,139 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Nomadcxx/jellywatch/internal/probe"
+)
+
+// UpdateMediaFileProbe stores ffprobe measurements on an indexed file.
+// Paths not yet in media_files, such as a file the daemon just organized,
+// keep them in pending_probes until the next scan indexes the file.
+func (m *MediaDB) UpdateMediaFileProbe(path string, r *probe.Result) error {
+	audio, err := json.Marshal(r.AudioTracks)
+	if err != nil {
+		return fmt.Errorf("encode audio tracks: %w", err)
+	}
+	subtitles, err := json.Marshal(r.SubtitleTracks)
+	if err != nil {
+		return fmt.Errorf("encode subtitle tracks: %w", err)
+	}
+	probedAt := r.ProbedAt
+	if probedAt.IsZero() {
+		probedAt = time.Now()
+	}
+	args := []any{r.Duration.Seconds(), r.Width, r.Height, r.VideoCodec, r.HDR,
+		string(audio), string(subtitles), probedAt, path}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res, err := m.db.Exec(`
+		UPDATE media_files SET
+			duration_seconds = ?,
+			video_width = ?,
+			video_height = ?,
+			video_codec = ?,
+			hdr_format = ?,
+			audio_tracks = ?,
+			subtitle_tracks = ?,
+			probed_at = ?
+		WHERE path = ?`, args...)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+	_, err = m.db.Exec(`
+		INSERT INTO pending_probes (duration_seconds, video_width, video_height, video_codec, hdr_format,
+			audio_tracks, subtitle_tracks, probed_at, path)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(path) DO UPDATE SET
+			duration_seconds = excluded.duration_seconds,
+			video_width = excluded.video_width,
+			video_height = excluded.video_height,
+			video_codec = excluded.video_codec,
+			hdr_format = excluded.hdr_format,
+			audio_tracks = excluded.audio_tracks,
+			subtitle_tracks = excluded.subtitle_tracks,
+			probed_at = excluded.probed_at`, args...)
+	return err
+}
+
+// applyPendingProbeLocked moves a probe kept in pending_probes onto the
+// media_files row just written for path. m.mu must be held.
+func (m *MediaDB) applyPendingProbeLocked(path string) error {
+	res, err := m.db.Exec(`
+		UPDATE media_files SET
+			(duration_seconds, video_width, video_height, video_codec, hdr_format,
+			 audio_tracks, subtitle_tracks, probed_at) =
+			(SELECT duration_seconds, video_width, video_height, video_codec, hdr_format,
+			        audio_tracks, subtitle_tracks, probed_at
+			 FROM pending_probes WHERE path = ?)
+		WHERE path = ? AND probed_at IS NULL
+		  AND EXISTS (SELECT 1 FROM pending_probes WHERE path = ?)`, path, path, path)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return err
+	}
+	_, err = m.db.Exec(`DELETE FROM pending_probes WHERE path = ?`, path)
+	return err
+}
+
+// GetMediaFileProbe returns the stored ffprobe measurements for path,
+// pending ones included, or nil if the file was never probed.
+func (m *MediaDB) GetMediaFileProbe(path string) (*probe.Result, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var (
+		duration         sql.NullFloat64
+		width, height    sql.NullInt64
+		codec, hdr       sql.NullString
+		audio, subtitles sql.NullString
+		probedAt         sql.NullTime
+	)
+	const columns = `duration_seconds, video_width, video_height, video_codec, hdr_format,
+		       audio_tracks, subtitle_tracks, probed_at`
+	err := m.db.QueryRow(`SELECT `+columns+` FROM media_files WHERE path = ?`, path).
+		Scan(&duration, &width, &height, &codec, &hdr, &audio, &subtitles, &probedAt)
+	if err == sql.ErrNoRows {
+		err = m.db.QueryRow(`SELECT `+columns+` FROM pending_probes WHERE path = ?`, path).
+			Scan(&duration, &width, &height, &codec, &hdr, &audio, &subtitles, &probedAt)
+	}
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !probedAt.Valid {
+		return nil, nil
+	}
+
+	r := &probe.Result{
+		Duration:   time.Duration(duration.Float64 * float64(time.Second)),
+		Width:      int(width.Int64),
+		Height:     int(height.Int64),
+		VideoCodec: codec.String,
+		HDR:        hdr.String,
+		ProbedAt:   probedAt.Time,
+	}
+	if audio.String != "" {
+		if err := json.Unmarshal([]byte(audio.String), &r.AudioTracks); err != nil {
+			return nil, fmt.Errorf("decode audio tracks: %w", err)
+		}
+	}
+	if subtitles.String != "" {
+		if err := json.Unmarshal([]byte(subtitles.String), &r.SubtitleTracks); err != nil {
+			return nil, fmt.Errorf("decode subtitle tracks: %w", err)
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellywatch/internal/probe"
+)
+
+func TestMediaFileProbeRoundTrip(t *testing.T) {
+	db, err := OpenPath(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	path := "/movies/Heat (1995)/Heat (1995).mkv"
+	result := &probe.Result{
+		Duration:   170 * time.Minute,
+		Width:      1920,
+		Height:     800,
+		VideoCodec: "h264",
+		HDR:        "",
+		AudioTracks: []probe.Track{
+			{Codec: "dts", Profile: "DTS-HD MA", Language: "eng", Channels: 6},
+		},
+		SubtitleTracks: []probe.Track{{Codec: "subrip", Language: "eng", Forced: true}},
+		ProbedAt:       time.Now().Truncate(time.Second),
+	}
+
+	// Not indexed yet: the probe waits for the row.
+	if err := db.UpdateMediaFileProbe(path, result); err != nil {
+		t.Fatalf("UpdateMediaFileProbe() on unknown path error = %v", err)
+	}
+	if got, err := db.GetMediaFileProbe(path); err != nil || got == nil || got.Width != 1920 {
+		t.Fatalf("GetMediaFileProbe() on unknown path = %+v, %v; want the pending probe", got, err)
+	}
+
+	if err := db.UpsertMediaFile(&MediaFile{
+		Path:            path,
+		Size:            1 << 30,
+		ModifiedAt:      time.Now(),
+		MediaType:       "movie",
+		NormalizedTitle: "heat",
+		Source:          "filesystem",
+		LibraryRoot:     "/movies",
+	}); err != nil {
+		t.Fatalf("UpsertMediaFile() error = %v", err)
+	}
+	var pending int
+	if err := db.DB().QueryRow(`SELECT COUNT(*) FROM pending_probes`).Scan(&pending); err != nil || pending != 0 {
+		t.Fatalf("pending_probes rows = %d, %v; want the probe moved onto the row", pending, err)
+	}
+
+	got, err := db.GetMediaFileProbe(path)
+	if err != nil {
+		t.Fatalf("GetMediaFileProbe() error = %v", err)
+	}
+	if got == nil {
+		t.Fatal("GetMediaFileProbe() = nil after probing")
+	}
+	if got.Duration != result.Duration || got.Width != 1920 || got.Height != 800 || got.VideoCodec != "h264" {
+		t.Errorf("video = %s %dx%d %s, want 2h50m 1920x800 h264", got.Duration, got.Width, got.Height, got.VideoCodec)
+	}
+	if len(got.AudioTracks) != 1 || got.AudioTracks[0] != result.AudioTracks[0] {
+		t.Errorf("AudioTracks = %+v, want %+v", got.AudioTracks, result.AudioTracks)
+	}
+	if len(got.SubtitleTracks) != 1 || !got.SubtitleTracks[0].Forced {
+		t.Errorf("SubtitleTracks = %+v, want one forced track", got.SubtitleTracks)
+	}
+	if !got.ProbedAt.Equal(result.ProbedAt) {
+		t.Errorf("ProbedAt = %v, want %v", got.ProbedAt, result.ProbedAt)
+	}
+
+	// Indexed files are updated in place.
+	result.Height = 1080
+	if err := db.UpdateMediaFileProbe(path, result); err != nil {
+		t.Fatalf("UpdateMediaFileProbe() error = %v", err)
+	}
+	if got, err := db.GetMediaFileProbe(path); err != nil || got == nil || got.Height != 1080 {
+		t.Errorf("GetMediaFileProbe() after re-probing = %+v, %v; want height 1080", got, err)
+	}
+}
@@ -166,6 +166,25 @@ func (m *MediaDB) UpsertMovie(mov *Movie) (shouldUpdateExternal bool, err error)
 	return false, nil
 }
 
+// UpdateMovieCanonicalPath records a filesystem move for a movie and marks
+// the row dirty so Radarr can be reconciled.
+func (m *MediaDB) UpdateMovieCanonicalPath(id int64, canonicalPath, libraryRoot string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	_, err := m.db.Exec(`
+		UPDATE movies SET
+			canonical_path = ?,
+			library_root = ?,
+			updated_at = ?,
+			radarr_path_dirty = 1
+		WHERE id = ?`,
+		canonicalPath, libraryRoot, now, id,
+	)
+	return err
+}
+
 // GetAllMoviesInLibrary returns all movies in a specific library root
 func (m *MediaDB) GetAllMoviesInLibrary(libraryRoot string) ([]*Movie, error) {
 	m.mu.RLock()
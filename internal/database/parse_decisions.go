@@ -42,6 +42,9 @@ type ParseDecision struct {
 	LastMetadataCheckAt  *time.Time
 	NextMetadataCheckAt  *time.Time
 	LastMetadataRepairAt *time.Time
+	// ArrDownloadID is the Sonarr or Radarr download whose identity was
+	// used instead of parsing the name.
+	ArrDownloadID string
 }
 
 // ParseUpdate carries updated parse metadata for a decision row.
@@ -53,6 +56,7 @@ type ParseUpdate struct {
 	ParsedEpisode        *int
 	ParserStrippedTokens string
 	MediaTypeGuessed     string
+	ArrDownloadID        string
 }
 
 // OrganizeUpdate carries updated organize metadata for a decision row.
@@ -145,11 +149,12 @@ func (m *MediaDB) UpdateParse(id int64, u ParseUpdate) error {
 			parsed_season = ?,
 			parsed_episode = ?,
 			parser_stripped_tokens = ?,
-			media_type_guessed = ?
+			media_type_guessed = ?,
+			arr_download_id = ?
 		WHERE id = ?`,
 		nullStr(u.ParseMethod), nullStr(u.ParsedTitle),
 		nullIntPtr(u.ParsedYear), nullIntPtr(u.ParsedSeason), nullIntPtr(u.ParsedEpisode),
-		nullStr(u.ParserStrippedTokens), nullStr(u.MediaTypeGuessed),
+		nullStr(u.ParserStrippedTokens), nullStr(u.MediaTypeGuessed), nullStr(u.ArrDownloadID),
 		id,
 	)
 	if err != nil {
@@ -181,6 +186,25 @@ func (m *MediaDB) UpdateOrganize(id int64, u OrganizeUpdate) error {
 	return nil
 }
 
+// RetargetParseDecisions points successful decisions whose file was
+// organized to oldPath at newPath.
+func (m *MediaDB) RetargetParseDecisions(oldPath, newPath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err := m.db.Exec(`
+		UPDATE parse_decisions
+		   SET target_path = ?, target_at = ?
+		 WHERE target_path = ?
+		   AND organize_outcome = 'success'`,
+		newPath, time.Now().UTC(), oldPath,
+	)
+	if err != nil {
+		return fmt.Errorf("RetargetParseDecisions: %w", err)
+	}
+	return nil
+}
+
 // UpdateOutcome updates the Jellyfin resolution columns for the given decision row.
 func (m *MediaDB) UpdateOutcome(id int64, u OutcomeUpdate) error {
 	m.mu.Lock()
@@ -564,7 +588,8 @@ const decisionColumns = `id, source_path, source_filename, event_at,
 	jellyfin_resolved_at, jellyfin_identified, jellyfin_first_seen_at,
 	auto_label, auto_label_at, human_label_override,
 	metadata_state, metadata_error, metadata_check_count, metadata_repair_count,
-	last_metadata_check_at, next_metadata_check_at, last_metadata_repair_at`
+	last_metadata_check_at, next_metadata_check_at, last_metadata_repair_at,
+	arr_download_id`
 
 func scanDecision(s scanner) (*ParseDecision, error) {
 	var d ParseDecision
@@ -598,6 +623,7 @@ func scanDecision(s scanner) (*ParseDecision, error) {
 		lastMetadataCheckAt  sql.NullTime
 		nextMetadataCheckAt  sql.NullTime
 		lastMetadataRepairAt sql.NullTime
+		arrDownloadID        sql.NullString
 	)
 
 	err := s.Scan(
@@ -611,6 +637,7 @@ func scanDecision(s scanner) (*ParseDecision, error) {
 		&autoLabel, &autoLabelAt, &humanLabelOverride,
 		&metadataState, &metadataError, &metadataCheckCount, &metadataRepairCount,
 		&lastMetadataCheckAt, &nextMetadataCheckAt, &lastMetadataRepairAt,
+		&arrDownloadID,
 	)
 	if err != nil {
 		return nil, err
@@ -618,6 +645,7 @@ func scanDecision(s scanner) (*ParseDecision, error) {
 
 	d.MediaTypeGuessed = mediaTypeGuessed.String
 	d.ParseMethod = parseMethod.String
+	d.ArrDownloadID = arrDownloadID.String
 	d.ParsedTitle = parsedTitle.String
 	if parsedYear.Valid {
 		v := int(parsedYear.Int64)
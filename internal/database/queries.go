@@ -11,6 +11,7 @@ type DuplicateGroup struct {
 	Year             *int
 	Season           *int // nil for movies
 	Episode          *int // nil for movies
+	EpisodeEnd       *int // last episode for multi-episode groups; nil otherwise
 	Files            []*MediaFile
 	BestFile         *MediaFile
 	SpaceReclaimable int64
@@ -62,7 +63,7 @@ func (m *MediaDB) FindDuplicateMovies() ([]DuplicateGroup, error) {
 		if year != nil {
 			yearVal = *year
 		}
-		files, err := m.getMediaFilesForGroup(title, yearVal, nil, nil)
+		files, err := m.getMediaFilesForGroup(title, yearVal, nil, nil, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -89,18 +90,20 @@ func (m *MediaDB) FindDuplicateMovies() ([]DuplicateGroup, error) {
 	return groups, rows.Err()
 }
 
-// FindDuplicateEpisodes returns episodes with multiple files
+// FindDuplicateEpisodes returns episodes with multiple files. Files are
+// grouped by their full episode span, so an S01E01-E02 file is only a
+// duplicate of another S01E01-E02 file, never of a standalone S01E01.
 func (m *MediaDB) FindDuplicateEpisodes() ([]DuplicateGroup, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	query := `
-		SELECT normalized_title, year, season, episode
+		SELECT normalized_title, year, season, episode, MAX(episode_end)
 		FROM media_files
 		WHERE media_type = 'episode' AND season IS NOT NULL AND episode IS NOT NULL
-		GROUP BY normalized_title, year, season, episode
+		GROUP BY normalized_title, year, season, episode, COALESCE(episode_end, episode)
 		HAVING COUNT(*) > 1
-		ORDER BY normalized_title, year, season, episode
+		ORDER BY normalized_title, year, season, episode, COALESCE(episode_end, episode)
 	`
 
 	rows, err := m.db.Query(query)
@@ -113,9 +116,9 @@ func (m *MediaDB) FindDuplicateEpisodes() ([]DuplicateGroup, error) {
 
 	for rows.Next() {
 		var title string
-		var year, season, episode *int
+		var year, season, episode, episodeEnd *int
 
-		if err := rows.Scan(&title, &year, &season, &episode); err != nil {
+		if err := rows.Scan(&title, &year, &season, &episode, &episodeEnd); err != nil {
 			return nil, fmt.Errorf("failed to scan duplicate episode: %w", err)
 		}
 
@@ -124,7 +127,7 @@ func (m *MediaDB) FindDuplicateEpisodes() ([]DuplicateGroup, error) {
 		if year != nil {
 			yearVal = *year
 		}
-		files, err := m.getMediaFilesForGroup(title, yearVal, season, episode)
+		files, err := m.getMediaFilesForGroup(title, yearVal, season, episode, episodeEnd)
 		if err != nil {
 			return nil, err
 		}
@@ -134,6 +137,7 @@ func (m *MediaDB) FindDuplicateEpisodes() ([]DuplicateGroup, error) {
 			Year:            year,
 			Season:          season,
 			Episode:         episode,
+			EpisodeEnd:      episodeEnd,
 			Files:           files,
 		}
 
@@ -153,15 +157,16 @@ func (m *MediaDB) FindDuplicateEpisodes() ([]DuplicateGroup, error) {
 	return groups, rows.Err()
 }
 
-// getMediaFilesForGroup retrieves all files for a duplicate group
-func (m *MediaDB) getMediaFilesForGroup(title string, year int, season, episode *int) ([]*MediaFile, error) {
+// getMediaFilesForGroup retrieves all files for a duplicate group. For
+// episodes, episodeEnd selects the span (nil means single-episode files).
+func (m *MediaDB) getMediaFilesForGroup(title string, year int, season, episode, episodeEnd *int) ([]*MediaFile, error) {
 	var files []*MediaFile
 
 	query := `
 		SELECT
 			id, path, size, modified_at,
 			media_type, parent_movie_id, parent_series_id, parent_episode_id,
-			normalized_title, year, season, episode,
+			normalized_title, year, season, episode, episode_end,
 			resolution, source_type, codec, audio_format, quality_score,
 			is_jellyfin_compliant, compliance_issues,
 			source, source_priority, library_root,
@@ -180,8 +185,12 @@ func (m *MediaDB) getMediaFilesForGroup(title string, year int, season, episode
 	}
 
 	if episode != nil {
-		query += " AND episode = ?"
-		args = append(args, *episode)
+		last := *episode
+		if episodeEnd != nil {
+			last = *episodeEnd
+		}
+		query += " AND episode = ? AND COALESCE(episode_end, episode) = ?"
+		args = append(args, *episode, last)
 	} else {
 		query += " AND episode IS NULL"
 	}
@@ -201,7 +210,7 @@ func (m *MediaDB) getMediaFilesForGroup(title string, year int, season, episode
 		err := rows.Scan(
 			&file.ID, &file.Path, &file.Size, &file.ModifiedAt,
 			&file.MediaType, &file.ParentMovieID, &file.ParentSeriesID, &file.ParentEpisodeID,
-			&file.NormalizedTitle, &file.Year, &file.Season, &file.Episode,
+			&file.NormalizedTitle, &file.Year, &file.Season, &file.Episode, &file.EpisodeEnd,
 			&file.Resolution, &file.SourceType, &file.Codec, &file.AudioFormat, &file.QualityScore,
 			&file.IsJellyfinCompliant, &complianceJSON,
 			&file.Source, &file.SourcePriority, &file.LibraryRoot,
@@ -235,7 +244,7 @@ func (m *MediaDB) FindNonCompliantFiles() ([]*MediaFile, error) {
 		SELECT
 			id, path, size, modified_at,
 			media_type, parent_movie_id, parent_series_id, parent_episode_id,
-			normalized_title, year, season, episode,
+			normalized_title, year, season, episode, episode_end,
 			resolution, source_type, codec, audio_format, quality_score,
 			is_jellyfin_compliant, compliance_issues,
 			source, source_priority, library_root,
@@ -258,7 +267,7 @@ func (m *MediaDB) FindNonCompliantFiles() ([]*MediaFile, error) {
 		err := rows.Scan(
 			&file.ID, &file.Path, &file.Size, &file.ModifiedAt,
 			&file.MediaType, &file.ParentMovieID, &file.ParentSeriesID, &file.ParentEpisodeID,
-			&file.NormalizedTitle, &file.Year, &file.Season, &file.Episode,
+			&file.NormalizedTitle, &file.Year, &file.Season, &file.Episode, &file.EpisodeEnd,
 			&file.Resolution, &file.SourceType, &file.Codec, &file.AudioFormat, &file.QualityScore,
 			&file.IsJellyfinCompliant, &complianceJSON,
 			&file.Source, &file.SourcePriority, &file.LibraryRoot,
@@ -408,7 +417,7 @@ func (m *MediaDB) GetMediaFilesByLibrary(libraryRoot string) ([]*MediaFile, erro
 		SELECT
 			id, path, size, modified_at,
 			media_type, parent_movie_id, parent_series_id, parent_episode_id,
-			normalized_title, year, season, episode,
+			normalized_title, year, season, episode, episode_end,
 			resolution, source_type, codec, audio_format, quality_score,
 			is_jellyfin_compliant, compliance_issues,
 			source, source_priority, library_root,
@@ -431,7 +440,7 @@ func (m *MediaDB) GetMediaFilesByLibrary(libraryRoot string) ([]*MediaFile, erro
 		err := rows.Scan(
 			&file.ID, &file.Path, &file.Size, &file.ModifiedAt,
 			&file.MediaType, &file.ParentMovieID, &file.ParentSeriesID, &file.ParentEpisodeID,
-			&file.NormalizedTitle, &file.Year, &file.Season, &file.Episode,
+			&file.NormalizedTitle, &file.Year, &file.Season, &file.Episode, &file.EpisodeEnd,
 			&file.Resolution, &file.SourceType, &file.Codec, &file.AudioFormat, &file.QualityScore,
 			&file.IsJellyfinCompliant, &complianceJSON,
 			&file.Source, &file.SourcePriority, &file.LibraryRoot,
@@ -3,7 +3,7 @@ package database
 import "database/sql"
 
 // Schema version for migrations
-const currentSchemaVersion = 23
+const currentSchemaVersion = 30
 
 // SQL migration scripts
 var migrations = []migration{
@@ -704,6 +704,163 @@ var migrations = []migration{
 			`INSERT INTO schema_version (version) VALUES (23)`,
 		},
 	},
+	{
+		version: 24,
+		// Multi-episode files (S01E01-E02) record the last episode of their
+		// span; NULL for single-episode files and movies.
+		up: []string{
+			`ALTER TABLE media_files ADD COLUMN episode_end INTEGER`,
+			`INSERT INTO schema_version (version) VALUES (24)`,
+		},
+	},
+	{
+		version: 25,
+		// ffprobe measurements. Track lists are JSON arrays; probed_at is
+		// NULL for files that were never probed.
+		up: []string{
+			`ALTER TABLE media_files ADD COLUMN duration_seconds REAL`,
+			`ALTER TABLE media_files ADD COLUMN video_width INTEGER`,
+			`ALTER TABLE media_files ADD COLUMN video_height INTEGER`,
+			`ALTER TABLE media_files ADD COLUMN video_codec TEXT`,
+			`ALTER TABLE media_files ADD COLUMN hdr_format TEXT`,
+			`ALTER TABLE media_files ADD COLUMN audio_tracks TEXT`,
+			`ALTER TABLE media_files ADD COLUMN subtitle_tracks TEXT`,
+			`ALTER TABLE media_files ADD COLUMN probed_at DATETIME`,
+			`INSERT INTO schema_version (version) VALUES (25)`,
+		},
+	},
+	{
+		version: 26,
+		// Operation journal: every filesystem mutation with the paths
+		// before and after, grouped into batches, so it can be undone.
+		// snapshot_json holds the DB rows the operation replaced.
+		up: []string{
+			`CREATE TABLE IF NOT EXISTS operation_journal (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				batch_id TEXT NOT NULL,
+				source TEXT NOT NULL,
+				kind TEXT NOT NULL,
+				before_path TEXT,
+				after_path TEXT,
+				reversible BOOLEAN NOT NULL DEFAULT 1,
+				snapshot_json TEXT,
+				created_at DATETIME NOT NULL,
+				undone_at DATETIME,
+				undo_batch_id TEXT
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_operation_journal_batch ON operation_journal(batch_id)`,
+			`CREATE INDEX IF NOT EXISTS idx_operation_journal_after ON operation_journal(after_path)`,
+			`INSERT INTO schema_version (version) VALUES (26)`,
+		},
+	},
+	{
+		version: 27,
+		// Recycle bin: files deleted into a per-volume trash directory,
+		// with where they came from and why, until the purge job or
+		// "trash empty" removes them for good.
+		up: []string{
+			`CREATE TABLE IF NOT EXISTS trash_items (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				original_path TEXT NOT NULL,
+				trash_path TEXT NOT NULL UNIQUE,
+				trash_dir TEXT NOT NULL,
+				reason TEXT NOT NULL DEFAULT '',
+				size INTEGER NOT NULL DEFAULT 0,
+				deleted_at DATETIME NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_trash_items_deleted ON trash_items(deleted_at)`,
+			`INSERT INTO schema_version (version) VALUES (27)`,
+		},
+	},
+	{
+		version: 28,
+		// Manual title overrides: the aliases table grows from a bare
+		// normalized-title-to-row map into release title, regex and folder
+		// patterns that name the canonical title, year and provider IDs.
+		// Existing rows keep their mapping, with the title, year and IDs
+		// copied from the movie or series they point at.
+		up: []string{
+			`CREATE TABLE aliases_v28 (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				kind TEXT NOT NULL DEFAULT 'title',
+				pattern TEXT NOT NULL DEFAULT '',
+				alias_normalized TEXT NOT NULL,
+				media_type TEXT NOT NULL,
+				media_id INTEGER,
+				title TEXT NOT NULL DEFAULT '',
+				year INTEGER,
+				tmdb_id INTEGER,
+				tvdb_id INTEGER,
+				imdb_id TEXT,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+				UNIQUE(kind, alias_normalized, media_type)
+			)`,
+			`INSERT INTO aliases_v28 (id, kind, pattern, alias_normalized, media_type, media_id, title, year, tmdb_id, tvdb_id, imdb_id)
+				SELECT a.id, 'title', a.alias_normalized, a.alias_normalized, a.media_type, a.media_id,
+					COALESCE(m.title, s.title, ''),
+					NULLIF(COALESCE(m.year, s.year), 0),
+					m.tmdb_id, s.tvdb_id, COALESCE(m.imdb_id, s.imdb_id)
+				FROM aliases a
+				LEFT JOIN movies m ON a.media_type = 'movie' AND m.id = a.media_id
+				LEFT JOIN series s ON a.media_type = 'series' AND s.id = a.media_id`,
+			`DROP INDEX IF EXISTS idx_aliases_lookup`,
+			`DROP TABLE aliases`,
+			`ALTER TABLE aliases_v28 RENAME TO aliases`,
+			`CREATE INDEX idx_aliases_lookup ON aliases(alias_normalized, media_type)`,
+			`INSERT INTO schema_version (version) VALUES (28)`,
+		},
+	},
+	{
+		version: 29,
+		// Download identities reported by Sonarr and Radarr webhooks, and
+		// which one a parse decision used in place of parsing the name.
+		up: []string{
+			`CREATE TABLE IF NOT EXISTS arr_grabs (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				download_id TEXT NOT NULL,
+				source TEXT NOT NULL,
+				event_type TEXT NOT NULL,
+				release_title TEXT NOT NULL DEFAULT '',
+				release_key TEXT NOT NULL DEFAULT '',
+				source_path TEXT NOT NULL DEFAULT '',
+				arr_id INTEGER NOT NULL DEFAULT 0,
+				title TEXT NOT NULL,
+				year INTEGER,
+				tvdb_id INTEGER,
+				tmdb_id INTEGER,
+				imdb_id TEXT NOT NULL DEFAULT '',
+				season INTEGER,
+				episodes TEXT NOT NULL DEFAULT '',
+				updated_at DATETIME NOT NULL,
+
+				UNIQUE(download_id, source_path)
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_arr_grabs_release_key ON arr_grabs(release_key)`,
+			`CREATE INDEX IF NOT EXISTS idx_arr_grabs_source_path ON arr_grabs(source_path)`,
+			`ALTER TABLE parse_decisions ADD COLUMN arr_download_id TEXT`,
+			`INSERT INTO schema_version (version) VALUES (29)`,
+		},
+	},
+	{
+		version: 30,
+		// ffprobe measurements of files organized before a scan has
+		// indexed them; moved onto the media_files row when it appears.
+		up: []string{
+			`CREATE TABLE IF NOT EXISTS pending_probes (
+				path TEXT PRIMARY KEY,
+				duration_seconds REAL,
+				video_width INTEGER,
+				video_height INTEGER,
+				video_codec TEXT,
+				hdr_format TEXT,
+				audio_tracks TEXT,
+				subtitle_tracks TEXT,
+				probed_at DATETIME NOT NULL
+			)`,
+			`INSERT INTO schema_version (version) VALUES (30)`,
+		},
+	},
 }
 
 type migration struct {
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// TrashItem is a file or folder kept in a recycle bin.
+type TrashItem struct {
+	ID           int64     `json:"id"`
+	OriginalPath string    `json:"original_path"`
+	TrashPath    string    `json:"trash_path"`
+	TrashDir     string    `json:"trash_dir"`
+	Reason       string    `json:"reason"`
+	Size         int64     `json:"size"`
+	DeletedAt    time.Time `json:"deleted_at"`
+}
+
+const trashColumns = `id, original_path, trash_path, trash_dir, reason, size, deleted_at`
+
+func scanTrashItem(row interface{ Scan(...any) error }) (*TrashItem, error) {
+	var item TrashItem
+	if err := row.Scan(&item.ID, &item.OriginalPath, &item.TrashPath, &item.TrashDir,
+		&item.Reason, &item.Size, &item.DeletedAt); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// InsertTrashItem records a file moved into the trash and sets item.ID.
+func (m *MediaDB) InsertTrashItem(item *TrashItem) error {
+	if item.DeletedAt.IsZero() {
+		item.DeletedAt = time.Now().UTC()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	res, err := m.db.Exec(`
+		INSERT INTO trash_items (original_path, trash_path, trash_dir, reason, size, deleted_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		item.OriginalPath, item.TrashPath, item.TrashDir, item.Reason, item.Size, item.DeletedAt)
+	if err != nil {
+		return fmt.Errorf("InsertTrashItem: %w", err)
+	}
+	item.ID, err = res.LastInsertId()
+	return err
+}
+
+// GetTrashItem returns one trash entry, or nil if there is none.
+func (m *MediaDB) GetTrashItem(id int64) (*TrashItem, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	item, err := scanTrashItem(m.db.QueryRow(`SELECT `+trashColumns+` FROM trash_items WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetTrashItem: %w", err)
+	}
+	return item, nil
+}
+
+// ListTrashItems returns everything in the trash, oldest first.
+func (m *MediaDB) ListTrashItems() ([]TrashItem, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	rows, err := m.db.Query(`SELECT ` + trashColumns + ` FROM trash_items ORDER BY deleted_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("ListTrashItems: %w", err)
+	}
+	defer rows.Close()
+
+	var out []TrashItem
+	for rows.Next() {
+		item, err := scanTrashItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ListTrashItems: %w", err)
+		}
+		out = append(out, *item)
+	}
+	return out, rows.Err()
+}
+
+// DeleteTrashItem forgets a trash entry.
+func (m *MediaDB) DeleteTrashItem(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, err := m.db.Exec(`DELETE FROM trash_items WHERE id = ?`, id)
+	return err
+}
+
+// DeleteTrashItemByPath forgets the entry for a file at trashPath, e.g.
+// after undo moved it back out of the trash.
+func (m *MediaDB) DeleteTrashItemByPath(trashPath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, err := m.db.Exec(`DELETE FROM trash_items WHERE trash_path = ?`, trashPath)
+	return err
+}
@@ -0,0 +1,131 @@
+package downloads
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/Nomadcxx/jellywatch/internal/qbittorrent"
+	"github.com/Nomadcxx/jellywatch/internal/sabnzbd"
+)
+
+// sabHistoryLimit is how many finished SABnzbd jobs are looked at. Older
+// jobs have long been organized or given up on.
+const sabHistoryLimit = 100
+
+type qbittorrentSource struct {
+	client *qbittorrent.Client
+	opts   Options
+}
+
+// NewQBittorrent is a Source over a qBittorrent client.
+func NewQBittorrent(client *qbittorrent.Client, opts Options) Source {
+	opts.PathMappings = sortedMappings(opts.PathMappings)
+	return &qbittorrentSource{client: client, opts: opts}
+}
+
+func (s *qbittorrentSource) Name() string { return ClientQBittorrent }
+
+func (s *qbittorrentSource) Jobs() ([]Job, error) {
+	torrents, err := s.client.Torrents()
+	if err != nil {
+		return nil, err
+	}
+	var prefs *qbittorrent.Preferences
+	if s.opts.RespectSeedLimits {
+		if prefs, err = s.client.Preferences(); err != nil {
+			return nil, err
+		}
+	}
+
+	jobs := make([]Job, 0, len(torrents))
+	for _, t := range torrents {
+		path := t.ContentPath
+		if path == "" && t.SavePath != "" {
+			// qBittorrent before 4.3.2 has no content_path.
+			path = filepath.Join(t.SavePath, t.Name)
+		}
+		complete := t.Complete()
+		jobs = append(jobs, Job{
+			Client:   ClientQBittorrent,
+			ID:       t.Hash,
+			Name:     t.Name,
+			Category: t.Category,
+			Path:     mapPath(s.opts.PathMappings, path),
+			Complete: complete,
+			Seeding:  s.opts.RespectSeedLimits && complete && !t.SeedLimitReached(prefs),
+		})
+	}
+	return jobs, nil
+}
+
+// Organized pauses or removes the torrent. Removing never deletes data:
+// what the organize left behind is the cleanup's to judge.
+func (s *qbittorrentSource) Organized(job Job) error {
+	switch s.opts.AfterOrganize {
+	case AfterPause:
+		return s.client.Pause(job.ID)
+	case AfterRemove:
+		return s.client.Delete(false, job.ID)
+	}
+	return nil
+}
+
+type sabnzbdSource struct {
+	client *sabnzbd.Client
+	opts   Options
+}
+
+// NewSABnzbd is a Source over a SABnzbd client.
+func NewSABnzbd(client *sabnzbd.Client, opts Options) Source {
+	opts.PathMappings = sortedMappings(opts.PathMappings)
+	return &sabnzbdSource{client: client, opts: opts}
+}
+
+func (s *sabnzbdSource) Name() string { return ClientSABnzbd }
+
+func (s *sabnzbdSource) Jobs() ([]Job, error) {
+	queue, err := s.client.Queue()
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.client.History(sabHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]Job, 0, len(queue)+len(history))
+	for _, q := range queue {
+		jobs = append(jobs, Job{
+			Client:   ClientSABnzbd,
+			ID:       q.NzoID,
+			Name:     q.Filename,
+			Category: q.Category,
+		})
+	}
+	for _, h := range history {
+		if h.Status == sabnzbd.StatusFailed {
+			continue
+		}
+		jobs = append(jobs, Job{
+			Client:   ClientSABnzbd,
+			ID:       h.NzoID,
+			Name:     h.Name,
+			Category: h.Category,
+			Path:     mapPath(s.opts.PathMappings, h.Storage),
+			Complete: h.Status == sabnzbd.StatusCompleted,
+		})
+	}
+	return jobs, nil
+}
+
+// Organized removes the job from the history. SABnzbd has nothing to pause
+// once a job is done.
+func (s *sabnzbdSource) Organized(job Job) error {
+	switch s.opts.AfterOrganize {
+	case AfterRemove:
+		return s.client.DeleteHistory(job.ID)
+	case AfterPause:
+		return fmt.Errorf("SABnzbd jobs can't be paused after they finish")
+	}
+	return nil
+}
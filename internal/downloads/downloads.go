@@ -0,0 +1,264 @@
+// Package downloads asks the configured download clients which torrent or
+// Usenet job a file in a watch folder belongs to: whether it has finished,
+// what category it was filed under, the job's original name, and whether
+// the torrent still has to seed before its files may be moved.
+package downloads
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Download clients.
+const (
+	ClientQBittorrent = "qbittorrent"
+	ClientSABnzbd     = "sabnzbd"
+)
+
+// What happens to a job once one of its files is organized.
+const (
+	AfterKeep   = "keep"
+	AfterPause  = "pause"
+	AfterRemove = "remove"
+)
+
+// refreshInterval is how long a client's job list is reused. A release
+// arrives as a burst of file events; one lookup serves them all.
+const refreshInterval = 10 * time.Second
+
+// ClientTimeout bounds each request to a download client. Lookups sit in
+// the organize path, so a client that has gone away must fail fast.
+const ClientTimeout = 5 * time.Second
+
+// Job is a torrent or Usenet job as its download client sees it.
+type Job struct {
+	Client string
+	// ID is the torrent hash or SABnzbd nzo_id.
+	ID string
+	// Name is the torrent or job name, before any obfuscation of the
+	// files inside it.
+	Name     string
+	Category string
+	// Path is the file or folder the job downloaded to, in the daemon's
+	// view; empty while the client has not said.
+	Path     string
+	Complete bool
+	// Seeding is set while a torrent has not reached its seeding limit and
+	// the client is configured to respect it.
+	Seeding bool
+}
+
+// Source is one download client.
+type Source interface {
+	Name() string
+	Jobs() ([]Job, error)
+	// Organized applies the client's after-organize action to job.
+	Organized(job Job) error
+}
+
+// Options are the settings shared by every client.
+type Options struct {
+	PathMappings  []PathMapping
+	AfterOrganize string
+	// RespectSeedLimits holds torrents below their ratio or seeding time
+	// limit; qBittorrent only.
+	RespectSeedLimits bool
+}
+
+// PathMapping translates the client's view of its download folder to the
+// daemon's, for clients running in a container.
+type PathMapping struct {
+	Client string
+	Daemon string
+}
+
+// mapPath replaces the first matching client prefix of mappings, sorted
+// by sortedMappings, with its daemon counterpart.
+func mapPath(mappings []PathMapping, p string) string {
+	if p == "" {
+		return p
+	}
+	for _, m := range mappings {
+		if hasPathPrefix(p, m.Client) {
+			p = strings.TrimRight(m.Daemon, "/") + p[len(strings.TrimRight(m.Client, "/")):]
+			break
+		}
+	}
+	return filepath.Clean(p)
+}
+
+func hasPathPrefix(p, prefix string) bool {
+	prefix = strings.TrimRight(prefix, "/")
+	if !strings.HasPrefix(p, prefix) {
+		return false
+	}
+	return len(p) == len(prefix) || p[len(prefix)] == '/'
+}
+
+// Tracker finds the job a file came from across all clients.
+type Tracker struct {
+	sources []Source
+
+	mu      sync.Mutex
+	jobs    []Job
+	fetched time.Time
+	errs    error
+	// refreshing is closed when the running refresh ends; nil while none
+	// runs.
+	refreshing chan struct{}
+}
+
+// NewTracker returns a tracker over sources, or nil without any.
+func NewTracker(sources ...Source) *Tracker {
+	if len(sources) == 0 {
+		return nil
+	}
+	return &Tracker{sources: sources}
+}
+
+// Find returns the job the file at path belongs to, or nil. A job whose
+// folder or file holds path wins; otherwise one whose name matches the
+// file's name or one of the two folders above it. Clients that can't be
+// reached are reported in the error; the others are still searched.
+func (t *Tracker) Find(path string) (*Job, error) {
+	if t == nil || path == "" {
+		return nil, nil
+	}
+	jobs, err := t.list()
+	path = filepath.Clean(path)
+
+	var byPath *Job
+	for i := range jobs {
+		j := &jobs[i]
+		if j.Path == "" || !hasPathPrefix(path, j.Path) {
+			continue
+		}
+		if byPath == nil || len(j.Path) > len(byPath.Path) {
+			byPath = j
+		}
+	}
+	if byPath != nil {
+		job := *byPath
+		return &job, err
+	}
+
+	for _, name := range candidateNames(path) {
+		key := nameKey(name)
+		if key == "" {
+			continue
+		}
+		for _, j := range jobs {
+			if nameKey(j.Name) == key {
+				return &j, err
+			}
+		}
+	}
+	return nil, err
+}
+
+// Organized applies the after-organize action of job's client.
+func (t *Tracker) Organized(job *Job) error {
+	if t == nil || job == nil {
+		return nil
+	}
+	for _, s := range t.sources {
+		if s.Name() == job.Client {
+			return s.Organized(*job)
+		}
+	}
+	return fmt.Errorf("no download client %s", job.Client)
+}
+
+// list returns every client's jobs, refreshing them when stale. Only one
+// refresh runs at a time and the clients are asked without holding mu;
+// callers arriving meanwhile get the stale list, or wait for the refresh
+// when there is none yet.
+func (t *Tracker) list() ([]Job, error) {
+	t.mu.Lock()
+	fresh := !t.fetched.IsZero() && time.Since(t.fetched) < refreshInterval
+	if fresh || (t.refreshing != nil && !t.fetched.IsZero()) {
+		defer t.mu.Unlock()
+		return t.jobs, t.errs
+	}
+	if done := t.refreshing; done != nil {
+		t.mu.Unlock()
+		<-done
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		return t.jobs, t.errs
+	}
+	done := make(chan struct{})
+	t.refreshing = done
+	t.mu.Unlock()
+
+	var jobs []Job
+	var errs []error
+	for _, s := range t.sources {
+		js, err := s.Jobs()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.Name(), err))
+			continue
+		}
+		jobs = append(jobs, js...)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.jobs, t.errs, t.fetched = jobs, errors.Join(errs...), time.Now()
+	t.refreshing = nil
+	close(done)
+	return t.jobs, t.errs
+}
+
+// candidateNames are the names a client may have given the job holding
+// path: the file's stem, then its folder and the one above, without the
+// prefixes SABnzbd puts on folders it is still working on.
+func candidateNames(path string) []string {
+	base := filepath.Base(path)
+	names := []string{strings.TrimSuffix(base, filepath.Ext(base)), base}
+	dir := filepath.Dir(path)
+	for i := 0; i < 2; i++ {
+		name := filepath.Base(dir)
+		if name == dir || name == "." || name == string(filepath.Separator) {
+			break
+		}
+		for _, prefix := range []string{"_UNPACK_", "_FAILED_"} {
+			name = strings.TrimPrefix(name, prefix)
+		}
+		names = append(names, name)
+		dir = filepath.Dir(dir)
+	}
+	return names
+}
+
+// nameKey is the form job names and file or folder names are compared in:
+// lowercase letters and digits only, so "Show.S01.1080p" and "Show S01
+// 1080p" match.
+func nameKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// sortedMappings orders mappings longest client prefix first.
+func sortedMappings(mappings []PathMapping) []PathMapping {
+	out := make([]PathMapping, 0, len(mappings))
+	for _, m := range mappings {
+		m.Client, m.Daemon = strings.TrimSpace(m.Client), strings.TrimSpace(m.Daemon)
+		if m.Client != "" && m.Daemon != "" {
+			out = append(out, m)
+		}
+	}
+	sort.SliceStable(out, func(i, k int) bool { return len(out[i].Client) > len(out[k].Client) })
+	return out
+}
@@ -0,0 +1,191 @@
+package downloads
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Nomadcxx/jellywatch/internal/qbittorrent"
+	"github.com/Nomadcxx/jellywatch/internal/sabnzbd"
+)
+
+func newQBittorrentServer(t *testing.T, torrents []qbittorrent.Torrent, deleted *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/auth/login":
+			w.Write([]byte("Ok."))
+		case "/api/v2/torrents/info":
+			json.NewEncoder(w).Encode(torrents)
+		case "/api/v2/app/preferences":
+			json.NewEncoder(w).Encode(qbittorrent.Preferences{MaxRatioEnabled: true, MaxRatio: 1})
+		case "/api/v2/torrents/delete":
+			require.NoError(t, r.ParseForm())
+			*deleted = append(*deleted, r.PostForm.Get("hashes"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func newSABServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("mode") {
+		case "queue":
+			w.Write([]byte(`{"queue": {"slots": [
+				{"nzo_id": "SABnzbd_nzo_1", "filename": "Show.S01E03.1080p.WEB-DL-GROUP", "cat": "tv", "status": "Downloading"}
+			]}}`))
+		case "history":
+			w.Write([]byte(`{"history": {"slots": [
+				{"nzo_id": "SABnzbd_nzo_2", "name": "Show.S01E02.1080p.WEB-DL-GROUP", "category": "tv",
+				 "status": "Completed", "storage": "/complete/tv/Show.S01E02.1080p.WEB-DL-GROUP"},
+				{"nzo_id": "SABnzbd_nzo_3", "name": "Show.S01E04.1080p.WEB-DL-GROUP", "category": "tv",
+				 "status": "Extracting", "storage": ""}
+			]}}`))
+		}
+	}))
+}
+
+func newTestTracker(t *testing.T, deleted *[]string) *Tracker {
+	t.Helper()
+	qb := newQBittorrentServer(t, []qbittorrent.Torrent{
+		{Hash: "seeded", Name: "Heat.1995.1080p.BluRay.x264-GROUP", Category: "radarr",
+			ContentPath: "/data/torrents/Heat.1995.1080p.BluRay.x264-GROUP", Progress: 1, State: "uploading",
+			Ratio: 1.5, RatioLimit: -2, SeedingTimeLimit: -2},
+		{Hash: "seeding", Name: "Alien.1979.1080p.BluRay.x264-GROUP", Category: "radarr",
+			ContentPath: "/data/torrents/Alien.1979.1080p.BluRay.x264-GROUP", Progress: 1, State: "uploading",
+			Ratio: 0.2, RatioLimit: -2, SeedingTimeLimit: -2},
+		{Hash: "partial", Name: "Dune.2021.2160p.WEB-DL-GROUP.mkv", Category: "radarr",
+			ContentPath: "/data/torrents/Dune.2021.2160p.WEB-DL-GROUP.mkv", Progress: 0.4, State: "downloading"},
+	}, deleted)
+	t.Cleanup(qb.Close)
+	sab := newSABServer(t)
+	t.Cleanup(sab.Close)
+
+	return NewTracker(
+		NewQBittorrent(qbittorrent.NewClient(qbittorrent.Config{URL: qb.URL}), Options{
+			PathMappings:      []PathMapping{{Client: "/data", Daemon: "/mnt/storage/"}},
+			AfterOrganize:     AfterRemove,
+			RespectSeedLimits: true,
+		}),
+		NewSABnzbd(sabnzbd.NewClient(sabnzbd.Config{URL: sab.URL}), Options{}),
+	)
+}
+
+func TestTrackerFindsJobs(t *testing.T) {
+	tracker := newTestTracker(t, nil)
+
+	tests := []struct {
+		name     string
+		path     string
+		id       string
+		complete bool
+		seeding  bool
+	}{
+		{"torrent folder", "/mnt/storage/torrents/Heat.1995.1080p.BluRay.x264-GROUP/heat.mkv", "seeded", true, false},
+		{"below ratio", "/mnt/storage/torrents/Alien.1979.1080p.BluRay.x264-GROUP/alien.mkv", "seeding", true, true},
+		{"single file torrent", "/mnt/storage/torrents/Dune.2021.2160p.WEB-DL-GROUP.mkv", "partial", false, false},
+		{"finished job", "/complete/tv/Show.S01E02.1080p.WEB-DL-GROUP/a8f3c9d2e1b4.mkv", "SABnzbd_nzo_2", true, false},
+		{"post-processing job", "/complete/tv/_UNPACK_Show.S01E04.1080p.WEB-DL-GROUP/show.mkv", "SABnzbd_nzo_3", false, false},
+		{"queued job", "/incomplete/Show.S01E03.1080p.WEB-DL-GROUP/show.mkv", "SABnzbd_nzo_1", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, err := tracker.Find(tt.path)
+			require.NoError(t, err)
+			require.NotNil(t, job)
+			assert.Equal(t, tt.id, job.ID)
+			assert.Equal(t, tt.complete, job.Complete)
+			assert.Equal(t, tt.seeding, job.Seeding)
+		})
+	}
+
+	job, err := tracker.Find("/downloads/Other.Movie.2020.mkv")
+	require.NoError(t, err)
+	assert.Nil(t, job)
+}
+
+func TestTrackerOrganizedRemovesTorrent(t *testing.T) {
+	var deleted []string
+	tracker := newTestTracker(t, &deleted)
+
+	job, err := tracker.Find("/mnt/storage/torrents/Heat.1995.1080p.BluRay.x264-GROUP/heat.mkv")
+	require.NoError(t, err)
+	require.NoError(t, tracker.Organized(job))
+	assert.Equal(t, []string{"seeded"}, deleted)
+}
+
+func TestTrackerReportsUnreachableClient(t *testing.T) {
+	sab := newSABServer(t)
+	defer sab.Close()
+	tracker := NewTracker(
+		NewQBittorrent(qbittorrent.NewClient(qbittorrent.Config{URL: "http://127.0.0.1:1"}), Options{}),
+		NewSABnzbd(sabnzbd.NewClient(sabnzbd.Config{URL: sab.URL}), Options{}),
+	)
+
+	job, err := tracker.Find("/complete/tv/Show.S01E02.1080p.WEB-DL-GROUP/show.mkv")
+	assert.ErrorContains(t, err, "qbittorrent")
+	require.NotNil(t, job)
+	assert.Equal(t, "SABnzbd_nzo_2", job.ID)
+}
+
+func TestNilTracker(t *testing.T) {
+	tracker := NewTracker()
+	job, err := tracker.Find("/downloads/x.mkv")
+	assert.NoError(t, err)
+	assert.Nil(t, job)
+}
+
+// blockingSource hands out its job once release is closed.
+type blockingSource struct {
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (b *blockingSource) Name() string { return "blocking" }
+
+func (b *blockingSource) Jobs() ([]Job, error) {
+	b.calls.Add(1)
+	<-b.release
+	return []Job{{Client: "blocking", Name: "Heat.1995.1080p", Path: "/downloads/Heat.1995.1080p"}}, nil
+}
+
+func (b *blockingSource) Organized(Job) error { return nil }
+
+func TestTrackerServesStaleJobsWhileRefreshing(t *testing.T) {
+	src := &blockingSource{release: make(chan struct{})}
+	close(src.release)
+	tracker := NewTracker(src)
+	job, err := tracker.Find("/downloads/Heat.1995.1080p/heat.mkv")
+	require.NoError(t, err)
+	require.NotNil(t, job)
+
+	// Let the list go stale and stall the next refresh.
+	src.release = make(chan struct{})
+	tracker.mu.Lock()
+	tracker.fetched = time.Now().Add(-2 * refreshInterval)
+	tracker.mu.Unlock()
+	refreshed := make(chan struct{})
+	go func() {
+		defer close(refreshed)
+		_, _ = tracker.Find("/downloads/other.mkv")
+	}()
+	require.Eventually(t, func() bool {
+		tracker.mu.Lock()
+		defer tracker.mu.Unlock()
+		return tracker.refreshing != nil
+	}, time.Second, time.Millisecond)
+
+	job, err = tracker.Find("/downloads/Heat.1995.1080p/heat.mkv")
+	require.NoError(t, err)
+	require.NotNil(t, job, "the stale list answers while a refresh is stuck")
+	assert.Equal(t, int32(2), src.calls.Load(), "only one refresh at a time")
+
+	close(src.release)
+	<-refreshed
+}
@@ -0,0 +1,178 @@
+package extract
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Backend unpacks an archive set into a folder.
+type Backend interface {
+	Name() string
+	// Extract unpacks set into dest, which exists and is empty. progress,
+	// when not nil, is called with a percentage whenever the tool reports
+	// one.
+	Extract(ctx context.Context, set *Set, dest string, progress func(percent int)) error
+}
+
+// BackendNames lists the names NewBackend accepts.
+var BackendNames = []string{"auto", "unrar", "7z", "bsdtar"}
+
+// NewBackend returns the named backend. "auto" (or empty) picks, per
+// archive, the first installed tool that reads its format: unrar, then
+// 7z, then bsdtar. Tools are looked up when an archive is extracted, so a
+// missing one is an extraction error rather than a config error.
+func NewBackend(name string) (Backend, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "auto":
+		return autoBackend{}, nil
+	case "unrar":
+		return unrarBackend, nil
+	case "7z":
+		return sevenZipBackend, nil
+	case "bsdtar":
+		return bsdtarBackend, nil
+	default:
+		return nil, fmt.Errorf("unknown extract backend %q (want %s)", name, strings.Join(BackendNames, ", "))
+	}
+}
+
+// commandBackend runs an external extraction tool.
+type commandBackend struct {
+	name string
+	// bins are the executable names to try, in order.
+	bins  []string
+	kinds map[Kind]bool
+	args  func(set *Set, dest string) []string
+}
+
+var (
+	unrarBackend = &commandBackend{
+		name:  "unrar",
+		bins:  []string{"unrar"},
+		kinds: map[Kind]bool{KindRAR: true},
+		args: func(set *Set, dest string) []string {
+			return []string{"x", "-o+", "-y", "-idc", set.First, dest + "/"}
+		},
+	}
+	sevenZipBackend = &commandBackend{
+		name:  "7z",
+		bins:  []string{"7z", "7zz"},
+		kinds: map[Kind]bool{KindRAR: true, Kind7z: true, KindZip: true},
+		args: func(set *Set, dest string) []string {
+			return []string{"x", "-y", "-bsp1", "-bso0", "-o" + dest, set.First}
+		},
+	}
+	bsdtarBackend = &commandBackend{
+		name:  "bsdtar",
+		bins:  []string{"bsdtar"},
+		kinds: map[Kind]bool{KindRAR: true, Kind7z: true, KindZip: true},
+		args: func(set *Set, dest string) []string {
+			return []string{"-x", "-f", set.First, "-C", dest}
+		},
+	}
+)
+
+func (b *commandBackend) Name() string { return b.name }
+
+// lookPath returns the first of the backend's executables on PATH.
+func (b *commandBackend) lookPath() (string, error) {
+	for _, bin := range b.bins {
+		if path, err := exec.LookPath(bin); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%s is not installed", b.name)
+}
+
+func (b *commandBackend) Extract(ctx context.Context, set *Set, dest string, progress func(int)) error {
+	if !b.kinds[set.Kind] {
+		return fmt.Errorf("%s can't extract %s archives", b.name, set.Kind)
+	}
+	bin, err := b.lookPath()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, bin, b.args(set, dest)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("%s: %w", b.name, err)
+	}
+	reportProgress(stdout, progress)
+	if err := cmd.Wait(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %w: %s", b.name, err, lastLine(msg))
+		}
+		return fmt.Errorf("%s: %w", b.name, err)
+	}
+	return nil
+}
+
+var percentPattern = regexp.MustCompile(`(\d{1,3})%`)
+
+// reportProgress reads a tool's output and passes on each new percentage.
+// unrar and 7z redraw the figure with backspaces and carriage returns, so
+// those split lines as well as newlines.
+func reportProgress(r io.Reader, progress func(int)) {
+	sc := bufio.NewScanner(r)
+	sc.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.IndexAny(data, "\r\n\b"); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	})
+	last := -1
+	for sc.Scan() {
+		if progress == nil {
+			continue
+		}
+		m := percentPattern.FindAllSubmatch(sc.Bytes(), -1)
+		if len(m) == 0 {
+			continue
+		}
+		pct, _ := strconv.Atoi(string(m[len(m)-1][1]))
+		if pct != last && pct <= 100 {
+			last = pct
+			progress(pct)
+		}
+	}
+	_, _ = io.Copy(io.Discard, r)
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(s, "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// autoBackend picks a tool per archive.
+type autoBackend struct{}
+
+func (autoBackend) Name() string { return "auto" }
+
+func (autoBackend) Extract(ctx context.Context, set *Set, dest string, progress func(int)) error {
+	for _, b := range []*commandBackend{unrarBackend, sevenZipBackend, bsdtarBackend} {
+		if !b.kinds[set.Kind] {
+			continue
+		}
+		if _, err := b.lookPath(); err != nil {
+			continue
+		}
+		return b.Extract(ctx, set, dest, progress)
+	}
+	return fmt.Errorf("no tool installed to extract %s archives (install unrar, 7z or bsdtar)", set.Kind)
+}
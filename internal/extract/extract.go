@@ -0,0 +1,226 @@
+// Package extract finds complete RAR, 7z and zip releases in download
+// folders and unpacks them with an external tool, so the video inside can
+// be organized like any other download.
+package extract
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kind is an archive format.
+type Kind string
+
+const (
+	KindRAR Kind = "rar"
+	Kind7z  Kind = "7z"
+	KindZip Kind = "zip"
+)
+
+// Volume naming schemes. Each captures the set name and, for split sets,
+// the volume suffix.
+var (
+	// Movie.2020.part01.rar, numbered from 1
+	rarPartPattern = regexp.MustCompile(`(?i)^(.+)\.part(\d+)\.rar$`)
+	// Movie.2020.rar followed by .r00-.r99 and .s00-.s99
+	rarOldPattern = regexp.MustCompile(`(?i)^(.+)\.(rar|[rs]\d{2})$`)
+	// Movie.2020.7z, or Movie.2020.7z.001 onwards
+	sevenZipPattern = regexp.MustCompile(`(?i)^(.+)\.7z(?:\.(\d{3}))?$`)
+	// Movie.2020.zip, preceded by .z01 onwards when split
+	zipPattern = regexp.MustCompile(`(?i)^(.+)\.(zip|z\d{2})$`)
+)
+
+// IsArchive reports whether path looks like a volume of an archive this
+// package can unpack.
+func IsArchive(path string) bool {
+	name := filepath.Base(path)
+	return rarPartPattern.MatchString(name) || rarOldPattern.MatchString(name) ||
+		sevenZipPattern.MatchString(name) || zipPattern.MatchString(name)
+}
+
+// Set is one archive, possibly split over several volumes.
+type Set struct {
+	Kind Kind
+	// Name is the file name without volume suffixes.
+	Name string
+	Dir  string
+	// First is the volume extraction starts from.
+	First string
+	// Volumes lists every volume on disk, First included.
+	Volumes []string
+	// Missing names volumes the set needs but doesn't have yet.
+	Missing []string
+}
+
+// Complete reports whether every volume up to the highest one seen is on
+// disk. A set still downloading its last volumes looks complete until
+// those volumes appear, so callers should only ask once files settle.
+func (s *Set) Complete() bool {
+	return len(s.Missing) == 0
+}
+
+// scheme is one way of naming volumes. Volume indexes run contiguously
+// from seqStart; first is the index of the volume extraction starts from,
+// which for old-style RAR and split zip sits before the sequence.
+type scheme struct {
+	kind     Kind
+	first    int
+	seqStart int
+	index    func(name string) (int, bool)
+	name     func(index int) string
+}
+
+// schemeFor returns the naming scheme of the volume called name, and the
+// set name it carries.
+func schemeFor(name string) (*scheme, string) {
+	if m := rarPartPattern.FindStringSubmatch(name); m != nil {
+		setName, width := m[1], len(m[2])
+		return &scheme{
+			kind: KindRAR, first: 1, seqStart: 1,
+			index: func(n string) (int, bool) {
+				m := rarPartPattern.FindStringSubmatch(n)
+				if m == nil || !strings.EqualFold(m[1], setName) {
+					return 0, false
+				}
+				i, _ := strconv.Atoi(m[2])
+				return i, true
+			},
+			name: func(i int) string { return fmt.Sprintf("%s.part%0*d.rar", setName, width, i) },
+		}, setName
+	}
+	if m := rarOldPattern.FindStringSubmatch(name); m != nil {
+		setName := m[1]
+		return &scheme{
+			kind: KindRAR, first: -1, seqStart: 0,
+			index: func(n string) (int, bool) {
+				m := rarOldPattern.FindStringSubmatch(n)
+				if m == nil || !strings.EqualFold(m[1], setName) || rarPartPattern.MatchString(n) {
+					return 0, false
+				}
+				ext := strings.ToLower(m[2])
+				if ext == "rar" {
+					return -1, true
+				}
+				i, _ := strconv.Atoi(ext[1:])
+				return int(ext[0]-'r')*100 + i, true
+			},
+			name: func(i int) string {
+				if i < 0 {
+					return setName + ".rar"
+				}
+				return fmt.Sprintf("%s.%c%02d", setName, 'r'+i/100, i%100)
+			},
+		}, setName
+	}
+	if m := sevenZipPattern.FindStringSubmatch(name); m != nil {
+		setName := m[1]
+		return &scheme{
+			kind: Kind7z, first: 1, seqStart: 1,
+			index: func(n string) (int, bool) {
+				m := sevenZipPattern.FindStringSubmatch(n)
+				if m == nil || !strings.EqualFold(m[1], setName) {
+					return 0, false
+				}
+				if m[2] == "" {
+					return 0, true
+				}
+				i, _ := strconv.Atoi(m[2])
+				return i, true
+			},
+			name: func(i int) string {
+				if i == 0 {
+					return setName + ".7z"
+				}
+				return fmt.Sprintf("%s.7z.%03d", setName, i)
+			},
+		}, setName
+	}
+	if m := zipPattern.FindStringSubmatch(name); m != nil {
+		setName := m[1]
+		return &scheme{
+			kind: KindZip, first: -1, seqStart: 1,
+			index: func(n string) (int, bool) {
+				m := zipPattern.FindStringSubmatch(n)
+				if m == nil || !strings.EqualFold(m[1], setName) {
+					return 0, false
+				}
+				ext := strings.ToLower(m[2])
+				if ext == "zip" {
+					return -1, true
+				}
+				i, _ := strconv.Atoi(ext[1:])
+				return i, true
+			},
+			name: func(i int) string {
+				if i < 0 {
+					return setName + ".zip"
+				}
+				return fmt.Sprintf("%s.z%02d", setName, i)
+			},
+		}, setName
+	}
+	return nil, ""
+}
+
+// Detect finds the set path belongs to by reading the other files in its
+// folder.
+func Detect(path string) (*Set, error) {
+	dir, name := filepath.Split(path)
+	dir = filepath.Clean(dir)
+	sch, setName := schemeFor(name)
+	if sch == nil {
+		return nil, fmt.Errorf("%s is not an archive", name)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	vols := make(map[int]string)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if i, ok := sch.index(e.Name()); ok {
+			vols[i] = e.Name()
+		}
+	}
+
+	first, last := sch.first, sch.seqStart-1
+	for i := range vols {
+		if i >= sch.seqStart && i > last {
+			last = i
+		}
+	}
+	// A lone Movie.7z is a single volume; Movie.7z.001 starts a split one.
+	if sch.kind == Kind7z && last < sch.seqStart {
+		first = 0
+	}
+
+	set := &Set{Kind: sch.kind, Name: setName, Dir: dir}
+	if n, ok := vols[first]; ok {
+		set.First = filepath.Join(dir, n)
+	} else {
+		set.Missing = append(set.Missing, sch.name(first))
+	}
+	for i := sch.seqStart; i <= last; i++ {
+		if _, ok := vols[i]; !ok && i != first {
+			set.Missing = append(set.Missing, sch.name(i))
+		}
+	}
+
+	indexes := make([]int, 0, len(vols))
+	for i := range vols {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	for _, i := range indexes {
+		set.Volumes = append(set.Volumes, filepath.Join(dir, vols[i]))
+	}
+	return set, nil
+}
@@ -0,0 +1,185 @@
+package extract
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func touch(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	cases := []struct {
+		name    string
+		files   []string
+		path    string
+		kind    Kind
+		first   string
+		missing []string
+	}{
+		{
+			name:  "old-style rar",
+			files: []string{"Film.rar", "Film.r00", "Film.r01", "Film.nfo"},
+			path:  "Film.r01",
+			kind:  KindRAR, first: "Film.rar",
+		},
+		{
+			name:    "old-style rar missing a volume",
+			files:   []string{"Film.rar", "Film.r00", "Film.r02"},
+			path:    "Film.rar",
+			kind:    KindRAR,
+			first:   "Film.rar",
+			missing: []string{"Film.r01"},
+		},
+		{
+			name:  "old-style rar past r99",
+			files: append(numbered("Film.r%02d", 100), "Film.rar", "Film.s00"),
+			path:  "Film.s00",
+			kind:  KindRAR, first: "Film.rar",
+		},
+		{
+			name:    "part rar without its first volume",
+			files:   []string{"Film.part02.rar", "Film.part03.rar"},
+			path:    "Film.part03.rar",
+			kind:    KindRAR,
+			missing: []string{"Film.part01.rar"},
+		},
+		{
+			name:  "part rar beside another set",
+			files: []string{"Film.part1.rar", "Film.part2.rar", "Film.Extras.part1.rar"},
+			path:  "Film.part2.rar",
+			kind:  KindRAR, first: "Film.part1.rar",
+		},
+		{
+			name:  "single 7z",
+			files: []string{"Film.7z"},
+			path:  "Film.7z",
+			kind:  Kind7z, first: "Film.7z",
+		},
+		{
+			name:    "split 7z",
+			files:   []string{"Film.7z.001", "Film.7z.003"},
+			path:    "Film.7z.003",
+			kind:    Kind7z,
+			first:   "Film.7z.001",
+			missing: []string{"Film.7z.002"},
+		},
+		{
+			name:  "split zip",
+			files: []string{"Film.z01", "Film.z02", "Film.zip"},
+			path:  "Film.z01",
+			kind:  KindZip, first: "Film.zip",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			touch(t, dir, tc.files...)
+			set, err := Detect(filepath.Join(dir, tc.path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if set.Kind != tc.kind {
+				t.Errorf("Kind = %s, want %s", set.Kind, tc.kind)
+			}
+			want := ""
+			if tc.first != "" {
+				want = filepath.Join(dir, tc.first)
+			}
+			if set.First != want {
+				t.Errorf("First = %q, want %q", set.First, want)
+			}
+			if !reflect.DeepEqual(set.Missing, tc.missing) {
+				t.Errorf("Missing = %v, want %v", set.Missing, tc.missing)
+			}
+			if set.Complete() != (len(tc.missing) == 0) {
+				t.Errorf("Complete() = %v with Missing %v", set.Complete(), set.Missing)
+			}
+		})
+	}
+}
+
+func numbered(format string, n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = fmt.Sprintf(format, i)
+	}
+	return out
+}
+
+func TestIsArchive(t *testing.T) {
+	for name, want := range map[string]bool{
+		"Film.rar":        true,
+		"Film.part07.rar": true,
+		"Film.r42":        true,
+		"Film.7z.002":     true,
+		"Film.z01":        true,
+		"Film.ZIP":        true,
+		"Film.mkv":        false,
+		"Film.r1":         false,
+		"Film.sfv":        false,
+	} {
+		if got := IsArchive("/dl/" + name); got != want {
+			t.Errorf("IsArchive(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestReportProgress(t *testing.T) {
+	// 7z redraws its percentage with backspaces; unrar with \r.
+	out := "Extracting\n  5% 1 - Film.mkv\b\b\b\b\b 42% 1 - Film.mkv\b\b\b\b\b 42%\r100%\nAll OK\n"
+	var got []int
+	reportProgress(strings.NewReader(out), func(p int) { got = append(got, p) })
+	if want := []int{5, 42, 100}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("progress = %v, want %v", got, want)
+	}
+}
+
+func TestBsdtarExtractsZip(t *testing.T) {
+	if _, err := bsdtarBackend.lookPath(); err != nil {
+		t.Skip(err)
+	}
+	dir := t.TempDir()
+	f, err := os.Create(filepath.Join(dir, "Film.2020.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("Film.2020.mkv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("video"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	set, err := Detect(filepath.Join(dir, "Film.2020.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := t.TempDir()
+	if err := bsdtarBackend.Extract(context.Background(), set, dest, nil); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dest, "Film.2020.mkv")); err != nil || string(data) != "video" {
+		t.Fatalf("extracted file = %q, %v", data, err)
+	}
+
+	if err := unrarBackend.Extract(context.Background(), set, dest, nil); err == nil {
+		t.Fatal("unrar accepted a zip")
+	}
+}
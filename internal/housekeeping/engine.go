@@ -34,9 +34,15 @@ import (
 	"github.com/Nomadcxx/jellywatch/internal/database"
 	"github.com/Nomadcxx/jellywatch/internal/logging"
 	"github.com/Nomadcxx/jellywatch/internal/naming"
+	"github.com/Nomadcxx/jellywatch/internal/notify"
+	"github.com/Nomadcxx/jellywatch/internal/quality"
 	"github.com/Nomadcxx/jellywatch/internal/service"
+	"github.com/Nomadcxx/jellywatch/internal/sidecars"
+	"github.com/Nomadcxx/jellywatch/internal/subtitles"
 	"github.com/Nomadcxx/jellywatch/internal/tmdb"
 	"github.com/Nomadcxx/jellywatch/internal/transfer"
+	"github.com/Nomadcxx/jellywatch/internal/trash"
+	"github.com/Nomadcxx/jellywatch/internal/video"
 )
 
 // normalizeFolderTitle lowercases and collapses whitespace WITHOUT
@@ -63,6 +69,7 @@ type Config struct {
 	TaskPauseBetween   time.Duration // sleep between task executions
 	StuckSyncAfter     time.Duration // mark sync_log running > this as stuck
 	DryRun             bool
+	QualityProfiles    *quality.ProfileSet // ranks duplicate copies; nil uses stored scores
 }
 
 // DefaultConfig returns the conservative defaults discussed in the plan.
@@ -88,6 +95,11 @@ type Engine struct {
 	// IPC op named "hk-task-<id>" and emits FrameProgress events that the
 	// WebUI subscribes to for live progress bars. Nil-safe.
 	registry *ipc.OpRegistry
+	// alerter receives user-facing events derived from repair events
+	// (failed tasks, deleted duplicates). Nil-safe.
+	alerter *notify.Alerter
+	// trash keeps deleted duplicates in the recycle bin. Nil-safe.
+	trash *trash.Bin
 }
 
 // SetVerifier attaches a TMDB verifier so the detector can distinguish
@@ -97,6 +109,17 @@ func (e *Engine) SetVerifier(v *tmdb.Verifier) { e.verifier = v }
 // SetOpRegistry wires the daemon's IPC OpRegistry into the engine so
 // tasks can publish live progress frames consumable via SSE.
 func (e *Engine) SetOpRegistry(r *ipc.OpRegistry) { e.registry = r }
+
+// SetAlerter sends failed tasks and deleted duplicates to the user's
+// notification targets.
+func (e *Engine) SetAlerter(a *notify.Alerter) { e.alerter = a }
+
+// SetTrash sends duplicates the engine deletes to the recycle bin.
+func (e *Engine) SetTrash(bin *trash.Bin) {
+	e.trash = bin
+	e.cleanup.SetTrash(bin)
+}
+
 func (e *Engine) renameWithFallback(src, dst string) error {
 	if err := os.Rename(src, dst); err == nil {
 		return nil
@@ -127,6 +150,33 @@ func (e *Engine) renameWithFallback(src, dst string) error {
 	return nil
 }
 
+// moveSubtitles brings the subtitle sidecars of a video that was renamed
+// to dst along, named after dst. A failed subtitle move is logged; the
+// video move stands.
+func (e *Engine) moveSubtitles(subs []subtitles.Sidecar, dst string) {
+	for _, t := range subtitles.Plan(dst, subs) {
+		if err := e.renameWithFallback(t.Source, t.Target); err != nil {
+			e.logf("warn", "subtitle move %s -> %s failed: %v", t.Source, t.Target, err)
+		}
+	}
+}
+
+// journal records a filesystem change a task made. Call it after the
+// change and before the database is updated so the rows it replaces are
+// captured for undo.
+func (e *Engine) journal(batch, source, kind, before, after string) {
+	if _, err := e.db.RecordOperation(database.JournalOp{
+		BatchID:    batch,
+		Source:     source,
+		Kind:       kind,
+		BeforePath: before,
+		AfterPath:  after,
+		Reversible: kind != database.OpDelete || after != "",
+	}); err != nil {
+		e.logf("warn", "journal %s %s failed: %v", kind, before, err)
+	}
+}
+
 func (e *Engine) moveDirWithFallback(srcDir, dstDir string) error {
 	if e.transferer == nil {
 		return fmt.Errorf("EXDEV and no transferer available for %s -> %s", srcDir, dstDir)
@@ -190,12 +240,14 @@ func NewEngine(cfg Config, db *database.MediaDB, logger *logging.Logger) *Engine
 		cfg.StuckSyncAfter = 24 * time.Hour
 	}
 	tr, _ := transfer.New(transfer.BackendRsync)
+	cleanup := service.NewCleanupService(db)
+	cleanup.SetQualityProfiles(cfg.QualityProfiles)
 	return &Engine{
 		cfg:        cfg,
 		db:         db,
 		logger:     logger,
 		transferer: tr,
-		cleanup:    service.NewCleanupService(db),
+		cleanup:    cleanup,
 	}
 }
 
@@ -208,6 +260,7 @@ type DetectResult struct {
 	VerifiedDistinct   int
 	PollutedNames      int
 	OrphanSources      int
+	OrphanSidecars     int
 	StuckSyncs         int
 	FolderRenames      int
 	ParserDriftRenames int
@@ -253,6 +306,9 @@ func (e *Engine) Detect(ctx context.Context) (*DetectResult, error) {
 	// 5: orphan source dirs in watch directories
 	e.detectOrphanSources(ctx, res, enqueue)
 
+	// 5b: metadata left named after a video that has since been renamed
+	e.detectOrphanSidecars(ctx, res, enqueue)
+
 	// 6: stuck sync_log rows
 	e.detectStuckSync(ctx, res, enqueue)
 
@@ -622,6 +678,82 @@ func (e *Engine) detectOrphanSources(ctx context.Context, res *DetectResult, enq
 	}
 }
 
+// detectOrphanSidecars finds NFO files, thumbnails and trickplay folders
+// in the libraries named after a video that is no longer beside them —
+// left behind when a past move renamed the video but not its metadata.
+// Only orphans in a folder with exactly one video are queued: they are
+// renamed after it. Anything else has no owner to give them to.
+func (e *Engine) detectOrphanSidecars(ctx context.Context, res *DetectResult, enqueue func(string, map[string]any, int)) {
+	libs := append(append([]string{}, e.cfg.MovieLibraries...), e.cfg.TVLibraries...)
+	for _, lib := range libs {
+		_ = filepath.WalkDir(lib, func(path string, d os.DirEntry, err error) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil || !d.IsDir() {
+				return nil
+			}
+			if path != lib && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			if _, extras := naming.ExtraFolderType(d.Name()); extras || sidecars.IsStemSidecar(path, true) {
+				return filepath.SkipDir
+			}
+			for _, o := range orphanSidecars(path) {
+				res.OrphanSidecars++
+				enqueue(database.TaskKindOrphanSidecar, map[string]any{
+					"src_path": o.Source,
+					"dst_path": o.Target,
+				}, 60)
+			}
+			return nil
+		})
+	}
+}
+
+// orphanSidecars returns the renames that would give the orphaned
+// sidecars in dir to the only video there.
+func orphanSidecars(dir string) []sidecars.Transfer {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	stems := make(map[string]bool)
+	var main []string
+	for _, ent := range entries {
+		if ent.IsDir() || !video.IsVideo(ent.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, ent.Name())
+		stems[strings.ToLower(strings.TrimSuffix(ent.Name(), filepath.Ext(ent.Name())))] = true
+		if !naming.IsExtra(path) {
+			main = append(main, path)
+		}
+	}
+	if len(main) != 1 {
+		return nil
+	}
+	owner := strings.TrimSuffix(filepath.Base(main[0]), filepath.Ext(main[0]))
+
+	var out []sidecars.Transfer
+	for _, ent := range entries {
+		path := filepath.Join(dir, ent.Name())
+		if !sidecars.IsStemSidecar(path, ent.IsDir()) {
+			continue
+		}
+		stem := sidecars.StemOf(path, ent.IsDir())
+		if stems[strings.ToLower(stem)] {
+			continue
+		}
+		target := filepath.Join(dir, owner+ent.Name()[len(stem):])
+		if _, err := os.Lstat(target); err == nil {
+			continue
+		}
+		out = append(out, sidecars.Transfer{Source: path, Target: target, Dir: ent.IsDir()})
+	}
+	return out
+}
+
 func isEmptyDir(path string) bool {
 	entries, err := os.ReadDir(path)
 	if err != nil {
@@ -712,8 +844,25 @@ func (e *Engine) detectParserDriftRepairs(ctx context.Context, res *DetectResult
 	}
 }
 
+// aliasFor returns the manual override for a file at path parsed as
+// title, or nil. Drift fixes apply it so they never rename an overridden
+// file back to what the parser thinks.
+func (e *Engine) aliasFor(mediaType, path, title string) *database.Alias {
+	if e.db == nil {
+		return nil
+	}
+	alias, err := e.db.MatchAlias(mediaType, path, title)
+	if err != nil {
+		e.logf("warn", "alias lookup failed path=%s err=%v", path, err)
+		return nil
+	}
+	return alias
+}
+
+// parserDriftMovieRename skips files named from a Sonarr or Radarr
+// download identity: the name was never the parser's to begin with.
 func (e *Engine) parserDriftMovieRename(d *database.ParseDecision) (srcPath, dstPath string, ok bool) {
-	if d == nil || d.SourceFilename == "" || d.TargetPath == "" {
+	if d == nil || d.SourceFilename == "" || d.TargetPath == "" || d.ArrDownloadID != "" {
 		return "", "", false
 	}
 	libRoot := containingLibrary(d.TargetPath, e.cfg.MovieLibraries)
@@ -724,6 +873,7 @@ func (e *Engine) parserDriftMovieRename(d *database.ParseDecision) (srcPath, dst
 	if err != nil || info == nil || info.Title == "" {
 		return "", "", false
 	}
+	e.aliasFor(database.AliasMediaMovie, d.SourcePath, info.Title).ApplyMovie(info)
 	cleanName := naming.NormalizeMediaName(info.Title, info.Year)
 	ext := filepath.Ext(d.TargetPath)
 	if ext == "" {
@@ -771,7 +921,7 @@ func (e *Engine) parserDriftMovieRename(d *database.ParseDecision) (srcPath, dst
 }
 
 func (e *Engine) parserDriftTVRename(d *database.ParseDecision) (srcPath, dstPath string, ok bool) {
-	if d == nil || d.SourceFilename == "" || d.TargetPath == "" {
+	if d == nil || d.SourceFilename == "" || d.TargetPath == "" || d.ArrDownloadID != "" {
 		return "", "", false
 	}
 	libRoot := containingLibrary(d.TargetPath, e.cfg.TVLibraries)
@@ -779,9 +929,10 @@ func (e *Engine) parserDriftTVRename(d *database.ParseDecision) (srcPath, dstPat
 		return "", "", false
 	}
 	info, err := naming.ParseTVShowName(d.SourceFilename)
-	if err != nil || info == nil || info.Title == "" || info.Season <= 0 || info.Episode <= 0 {
+	if err != nil || info == nil || info.Title == "" || info.Season < 0 || info.Episode <= 0 {
 		return "", "", false
 	}
+	e.aliasFor(database.AliasMediaSeries, d.SourcePath, info.Title).ApplyTV(info)
 	if !tvParserDriftWasReleaseYearAfterEpisode(d, libRoot) {
 		return "", "", false
 	}
@@ -798,7 +949,13 @@ func (e *Engine) parserDriftTVRename(d *database.ParseDecision) (srcPath, dstPat
 		return "", "", false
 	}
 	srcPath = filepath.Clean(d.TargetPath)
-	dstPath = filepath.Join(libRoot, showName, naming.FormatSeasonFolder(info.Season), episodeName)
+	seasonFolder := naming.FormatSeasonFolder(info.Season)
+	if current := filepath.Base(filepath.Dir(srcPath)); info.IsSpecial() && naming.IsSpecialsFolder(current) {
+		// Keep the show's existing specials convention ("Specials" vs
+		// "Season 00"); it is not parser drift.
+		seasonFolder = current
+	}
+	dstPath = filepath.Join(libRoot, showName, seasonFolder, episodeName)
 	if srcPath == filepath.Clean(dstPath) {
 		return "", "", false
 	}
@@ -939,6 +1096,9 @@ func (e *Engine) detectFileDuplicates(ctx context.Context, res *DetectResult, en
 			"best_file_id":     group.BestFileID,
 			"confidence":       level,
 		}
+		if group.Edition != "" {
+			payload["edition"] = group.Edition
+		}
 		if group.Year != nil {
 			payload["year"] = *group.Year
 		}
@@ -1052,6 +1212,9 @@ func (e *Engine) Drain(ctx context.Context) error {
 			defer wg.Done()
 			defer func() { <-sem }()
 			runErr := e.executeTask(ctx, t)
+			if runErr != nil {
+				e.recordTaskFailure(t, runErr)
+			}
 			if completeErr := e.db.CompleteHousekeepingTask(t.ID, runErr, e.cfg.TaskRetryMax); completeErr != nil {
 				e.logf("error", "complete task failed id=%d err=%v", t.ID, completeErr)
 			}
@@ -1076,6 +1239,8 @@ func (e *Engine) executeTask(ctx context.Context, t *database.HousekeepingTask)
 	switch t.Kind {
 	case database.TaskKindOrphanSource:
 		return e.execOrphanSource(t)
+	case database.TaskKindOrphanSidecar:
+		return e.execOrphanSidecar(t)
 	case database.TaskKindStuckSync:
 		return e.execStuckSync(t)
 	case database.TaskKindMoveMerge, database.TaskKindNoYearMerge:
@@ -1119,8 +1284,39 @@ func (e *Engine) executeTask(ctx context.Context, t *database.HousekeepingTask)
 	}
 }
 
+// recordTaskFailure records a failed repair event, and with it an alert,
+// for a task of any kind whose execution returned err.
+func (e *Engine) recordTaskFailure(t *database.HousekeepingTask, err error) {
+	safetyClass, confidence := taskSafety(t.Kind)
+	src, _ := t.Payload["src_path"].(string)
+	if src == "" {
+		src, _ = t.Payload["path"].(string)
+	}
+	if src == "" {
+		src, _ = t.Payload["normalized_title"].(string)
+	}
+	dst, _ := t.Payload["dst_path"].(string)
+	e.recordRepairEvent(t.Kind, safetyClass, confidence, src, dst, "failed", err.Error(), t.Payload)
+}
+
+// taskSafety is the safety class and confidence repair events of a task
+// kind are recorded with. Flag kinds only reach the executor once a human
+// approved them.
+func taskSafety(kind string) (string, float64) {
+	switch kind {
+	case database.TaskKindYearMismatch, database.TaskKindCrossVolumeDuplicate,
+		database.TaskKindPollutedName, database.TaskKindSubdirMismatch:
+		return "human_approved", 1.0
+	}
+	return "auto_safe", 0.95
+}
+
 func (e *Engine) recordRepairEvent(action, safetyClass string, confidence float64, src, dst, outcome, errMsg string, evidence map[string]any) {
-	if e == nil || e.db == nil {
+	if e == nil {
+		return
+	}
+	e.alertRepair(action, src, dst, outcome, errMsg)
+	if e.db == nil {
 		return
 	}
 	evidenceJSON := ""
@@ -1143,14 +1339,41 @@ func (e *Engine) recordRepairEvent(action, safetyClass string, confidence float6
 	})
 }
 
-func (e *Engine) execParserDriftRename(t *database.HousekeepingTask) (err error) {
+// alertRepair turns the repair events users care about into alerts.
+func (e *Engine) alertRepair(action, src, dst, outcome, errMsg string) {
+	switch {
+	case outcome == "failed":
+		msg := errMsg
+		if src != "" {
+			msg = src + ": " + errMsg
+		}
+		e.alerter.Alert(notify.Alert{
+			Kind:    notify.AlertFailed,
+			Title:   "Housekeeping " + strings.ReplaceAll(action, "_", " ") + " failed",
+			Message: msg,
+			Path:    src,
+		})
+	case outcome == "success" && isDuplicateAction(action):
+		e.alerter.Alert(notify.Alert{
+			Kind:    notify.AlertDuplicateDeleted,
+			Title:   "Duplicate deleted",
+			Message: src + " (kept " + dst + ")",
+			Path:    src,
+		})
+	}
+}
+
+func isDuplicateAction(action string) bool {
+	switch action {
+	case database.TaskKindConsolidateDuplicate, database.TaskKindCrossVolumeDuplicate, database.TaskKindYearMismatch:
+		return true
+	}
+	return false
+}
+
+func (e *Engine) execParserDriftRename(t *database.HousekeepingTask) error {
 	src, _ := t.Payload["src_path"].(string)
 	dst, _ := t.Payload["dst_path"].(string)
-	defer func() {
-		if err != nil {
-			e.recordRepairEvent(database.TaskKindParserDriftRename, "auto_safe", 0.95, src, dst, "failed", err.Error(), t.Payload)
-		}
-	}()
 	if src == "" || dst == "" {
 		return fmt.Errorf("payload missing src_path/dst_path")
 	}
@@ -1193,9 +1416,12 @@ func (e *Engine) execParserDriftRename(t *database.HousekeepingTask) (err error)
 			return fmt.Errorf("stat dst dir: %w", err)
 		}
 		if dstDirExists {
-			if err := e.renameWithFallback(src, dst); err != nil {
+			subs := subtitles.Find(src)
+			if _, err := sidecars.MoveWith(src, dst, e.renameWithFallback); err != nil {
 				return fmt.Errorf("rename file %s -> %s: %w", src, dst, err)
 			}
+			e.moveSubtitles(subs, dst)
+			e.carryExtras(srcDir, dstDir)
 			if err := os.Remove(srcDir); err != nil && !os.IsNotExist(err) {
 				return fmt.Errorf("remove source dir %s: %w", srcDir, err)
 			}
@@ -1205,11 +1431,14 @@ func (e *Engine) execParserDriftRename(t *database.HousekeepingTask) (err error)
 			}
 			movedPath := filepath.Join(dstDir, filepath.Base(src))
 			if filepath.Base(src) != filepath.Base(dst) {
-				if err := e.renameWithFallback(movedPath, dst); err != nil {
+				subs := subtitles.Find(movedPath)
+				if _, err := sidecars.MoveWith(movedPath, dst, e.renameWithFallback); err != nil {
 					return fmt.Errorf("rename file %s -> %s: %w", movedPath, dst, err)
 				}
+				e.moveSubtitles(subs, dst)
 			}
 		}
+		e.journal(database.NewJournalBatch(database.OpSourceParserDrift), database.OpSourceParserDrift, database.OpMove, src, dst)
 	}
 
 	if file, err := e.db.GetMediaFile(src); err == nil && file != nil {
@@ -1255,14 +1484,9 @@ func (e *Engine) execParserDriftRename(t *database.HousekeepingTask) (err error)
 	return nil
 }
 
-func (e *Engine) execParserDriftTVRename(t *database.HousekeepingTask) (err error) {
+func (e *Engine) execParserDriftTVRename(t *database.HousekeepingTask) error {
 	src, _ := t.Payload["src_path"].(string)
 	dst, _ := t.Payload["dst_path"].(string)
-	defer func() {
-		if err != nil {
-			e.recordRepairEvent(database.TaskKindParserDriftTVRename, "auto_safe", 0.95, src, dst, "failed", err.Error(), t.Payload)
-		}
-	}()
 	if src == "" || dst == "" {
 		return fmt.Errorf("payload missing src_path/dst_path")
 	}
@@ -1308,11 +1532,17 @@ func (e *Engine) execParserDriftTVRename(t *database.HousekeepingTask) (err erro
 		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
 			return fmt.Errorf("mkdir %s: %w", filepath.Dir(dst), err)
 		}
-		if err := e.renameWithFallback(src, dst); err != nil {
+		subs := subtitles.Find(src)
+		if _, err := sidecars.MoveWith(src, dst, e.renameWithFallback); err != nil {
 			return fmt.Errorf("rename file %s -> %s: %w", src, dst, err)
 		}
+		e.moveSubtitles(subs, dst)
+		// Season and show extras follow the last episode out.
+		e.carryExtras(filepath.Dir(src), filepath.Dir(dst))
 		removeDirIfEmpty(filepath.Dir(src))
+		e.carryExtras(filepath.Dir(filepath.Dir(src)), filepath.Dir(filepath.Dir(dst)))
 		removeDirIfEmpty(filepath.Dir(filepath.Dir(src)))
+		e.journal(database.NewJournalBatch(database.OpSourceParserDrift), database.OpSourceParserDrift, database.OpMove, src, dst)
 	}
 
 	if file, err := e.db.GetMediaFile(src); err == nil && file != nil {
@@ -1362,6 +1592,67 @@ func (e *Engine) execParserDriftTVRename(t *database.HousekeepingTask) (err erro
 	return nil
 }
 
+// carryExtras moves the extras folders and extra videos left in srcDir
+// into dstDir once srcDir holds no other videos, so trailers and
+// featurettes follow their movie or show to its new folder. Extras whose
+// name is already taken in dstDir stay behind.
+func (e *Engine) carryExtras(srcDir, dstDir string) {
+	if srcDir == dstDir || hasMainVideos(srcDir) {
+		return
+	}
+	entries, err := os.ReadDir(srcDir)
+	if err != nil {
+		return
+	}
+	batch := database.NewJournalBatch(database.OpSourceParserDrift)
+	for _, ent := range entries {
+		from := filepath.Join(srcDir, ent.Name())
+		if ent.IsDir() {
+			if _, ok := naming.ExtraFolderType(ent.Name()); !ok {
+				continue
+			}
+		} else if !naming.IsExtra(from) {
+			continue
+		}
+		to := filepath.Join(dstDir, ent.Name())
+		if _, err := os.Stat(to); err == nil {
+			continue
+		}
+		if err := os.MkdirAll(dstDir, 0o755); err != nil {
+			e.logf("warn", "carry extras mkdir %s failed: %v", dstDir, err)
+			return
+		}
+		if err := e.renameWithFallback(from, to); err != nil {
+			e.logf("warn", "carry extra %s -> %s failed: %v", from, to, err)
+			continue
+		}
+		e.journal(batch, database.OpSourceParserDrift, database.OpMove, from, to)
+	}
+}
+
+// hasMainVideos reports whether dir holds a video, outside its extras
+// folders, that is not itself an extra.
+func hasMainVideos(dir string) bool {
+	found := false
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if _, ok := naming.ExtraFolderType(d.Name()); ok && path != dir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if video.IsVideo(path) && !naming.IsExtra(path) {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
 func removeDirIfEmpty(path string) {
 	if path == "" || path == "." || path == string(filepath.Separator) {
 		return
@@ -1410,6 +1701,32 @@ func (e *Engine) execOrphanSource(t *database.HousekeepingTask) error {
 	return nil
 }
 
+func (e *Engine) execOrphanSidecar(t *database.HousekeepingTask) error {
+	src, _ := t.Payload["src_path"].(string)
+	dst, _ := t.Payload["dst_path"].(string)
+	if src == "" || dst == "" {
+		return fmt.Errorf("payload missing src_path/dst_path")
+	}
+	// The folder may have changed since detection; only a rename the
+	// folder still calls for goes ahead.
+	still := false
+	for _, o := range orphanSidecars(filepath.Dir(src)) {
+		if o.Source == src && o.Target == dst {
+			still = true
+			break
+		}
+	}
+	if !still {
+		return fmt.Errorf("sidecar no longer orphaned: %s", src)
+	}
+	if err := e.renameWithFallback(src, dst); err != nil {
+		return fmt.Errorf("rename %s -> %s: %w", src, dst, err)
+	}
+	e.journal(database.NewJournalBatch(database.OpSourceSidecar), database.OpSourceSidecar, database.OpMove, src, dst)
+	e.logf("info", "renamed orphan sidecar src=%s dst=%s", src, dst)
+	return nil
+}
+
 func (e *Engine) execStuckSync(t *database.HousekeepingTask) error {
 	idAny, ok := t.Payload["sync_log_id"]
 	if !ok {
@@ -1447,19 +1764,21 @@ func (e *Engine) execStuckSync(t *database.HousekeepingTask) error {
 // longer qualifies as high-confidence, the task is treated as a no-op
 // success — the queue should not be poisoned by stale tasks.
 func (e *Engine) execConsolidateDuplicate(ctx context.Context, t *database.HousekeepingTask) error {
+	safetyClass, confidence := taskSafety(t.Kind)
+	title, _ := t.Payload["normalized_title"].(string)
 	if e.cleanup == nil {
 		return fmt.Errorf("cleanup service not configured")
 	}
 	mediaType, _ := t.Payload["media_type"].(string)
-	title, _ := t.Payload["normalized_title"].(string)
 	if mediaType == "" || title == "" {
 		return fmt.Errorf("payload missing media_type/normalized_title")
 	}
+	edition, _ := t.Payload["edition"].(string)
 	year := payloadIntPtr(t.Payload, "year")
 	season := payloadIntPtr(t.Payload, "season")
 	episode := payloadIntPtr(t.Payload, "episode")
 
-	group, err := e.cleanup.FindDuplicateGroup(mediaType, title, year, season, episode)
+	group, err := e.cleanup.FindDuplicateGroup(mediaType, title, edition, year, season, episode)
 	if err != nil {
 		return fmt.Errorf("lookup duplicate group: %w", err)
 	}
@@ -1497,6 +1816,9 @@ func (e *Engine) execConsolidateDuplicate(ctx context.Context, t *database.House
 		}
 		e.logf("info", "duplicate-deleted task=%d kind=%s group=%s file_id=%d size=%d path=%q kept_id=%d kept_path=%q",
 			t.ID, t.Kind, group.ID, f.ID, f.Size, f.Path, group.BestFileID, keptPath)
+		e.recordRepairEvent(t.Kind, safetyClass, confidence, f.Path, keptPath, "success", "", map[string]any{
+			"task_id": t.ID, "group": group.ID, "size": f.Size,
+		})
 	}
 	e.logf("info", "duplicate-resolved id=%d kind=%s title=%q deleted=%d reclaimed=%d",
 		t.ID, t.Kind, title, deleted, reclaimed)
@@ -1593,6 +1915,7 @@ func (e *Engine) execMergeMove(ctx context.Context, t *database.HousekeepingTask
 	prog := e.startTaskOp(t.ID, src, dst, totalFiles, totalBytes)
 	defer prog.finish(nil)
 
+	batch := database.NewJournalBatch(database.OpSourceMergeMove)
 	moved := 0
 	skipped := 0
 	var doneBytes int64
@@ -1614,15 +1937,30 @@ func (e *Engine) execMergeMove(ctx context.Context, t *database.HousekeepingTask
 
 		if existing, err := os.Stat(target); err == nil && !existing.IsDir() {
 			if existing.Size() == info.Size() {
-				if err := os.Remove(path); err != nil {
+				keptAt, err := e.trash.Remove(path, "identical copy already at "+target)
+				if err != nil {
 					return fmt.Errorf("remove dup src %s: %w", path, err)
 				}
+				e.journal(batch, database.OpSourceMergeMove, database.OpDelete, path, keptAt)
 				e.updateParseDecisionTargetPath(path, target)
 				skipped++
 				doneBytes += info.Size()
 				prog.fileSkipped(rel, info.Size(), doneBytes, totalBytes)
 				return nil
 			}
+			if sidecars.IsMetadata(path) {
+				// Metadata already at the destination describes the
+				// same title; keep it rather than fail the merge.
+				keptAt, err := e.trash.Remove(path, "metadata already at "+target)
+				if err != nil {
+					return fmt.Errorf("remove src metadata %s: %w", path, err)
+				}
+				e.journal(batch, database.OpSourceMergeMove, database.OpDelete, path, keptAt)
+				skipped++
+				doneBytes += info.Size()
+				prog.fileSkipped(rel, info.Size(), doneBytes, totalBytes)
+				return nil
+			}
 			return fmt.Errorf("size mismatch at %s (src=%d dst=%d) — manual review required",
 				target, info.Size(), existing.Size())
 		}
@@ -1649,6 +1987,7 @@ func (e *Engine) execMergeMove(ctx context.Context, t *database.HousekeepingTask
 			prog.fileFailed(rel, res.Error)
 			return fmt.Errorf("move %s -> %s: %w", path, target, res.Error)
 		}
+		e.journal(batch, database.OpSourceMergeMove, database.OpMove, path, target)
 
 		if file, gerr := e.db.GetMediaFile(path); gerr == nil && file != nil {
 			_ = e.db.DeleteMediaFile(path)
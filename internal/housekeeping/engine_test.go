@@ -1,12 +1,16 @@
 package housekeeping
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/notify"
 	"github.com/Nomadcxx/jellywatch/internal/transfer"
 	"github.com/stretchr/testify/require"
 )
@@ -223,6 +227,116 @@ func TestDrainExecutesParserDriftMovieRename(t *testing.T) {
 	require.Equal(t, newPath, events[0].TargetPath)
 }
 
+func TestDrainParserDriftMovieRenameCarriesSubtitles(t *testing.T) {
+	db := openTestDB(t)
+	lib := t.TempDir()
+
+	oldDir := filepath.Join(lib, "Heat DCP (1995)")
+	oldPath := filepath.Join(oldDir, "Heat DCP (1995).mkv")
+	newDir := filepath.Join(lib, "Heat (1995)")
+	newPath := filepath.Join(newDir, "Heat (1995).mkv")
+	require.NoError(t, os.MkdirAll(oldDir, 0o755))
+	require.NoError(t, os.WriteFile(oldPath, []byte("movie"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(oldDir, "Heat DCP (1995).en.srt"), []byte("sub"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(oldDir, "Heat DCP (1995).eng.forced.srt"), []byte("sub"), 0o644))
+
+	_, err := db.EnqueueHousekeepingTask("housekeeping.detect", database.TaskKindParserDriftRename, map[string]any{
+		"src_path": oldPath,
+		"dst_path": newPath,
+	}, 70)
+	require.NoError(t, err)
+
+	engine := NewEngine(Config{
+		MovieLibraries:     []string{lib},
+		MaxConcurrentTasks: 1,
+		MaxTasksPerCycle:   50,
+		TaskRetryMax:       1,
+	}, db, nil)
+	require.NoError(t, engine.Drain(t.Context()))
+
+	require.FileExists(t, newPath)
+	require.FileExists(t, filepath.Join(newDir, "Heat (1995).eng.srt"))
+	require.FileExists(t, filepath.Join(newDir, "Heat (1995).eng.forced.srt"))
+	require.NoDirExists(t, oldDir)
+}
+
+func TestDrainParserDriftMovieRenameCarriesMetadata(t *testing.T) {
+	db := openTestDB(t)
+	lib := t.TempDir()
+
+	oldDir := filepath.Join(lib, "Heat DCP (1995)")
+	oldPath := filepath.Join(oldDir, "Heat DCP (1995).mkv")
+	newDir := filepath.Join(lib, "Heat (1995)")
+	newPath := filepath.Join(newDir, "Heat (1995).mkv")
+	require.NoError(t, os.MkdirAll(filepath.Join(oldDir, "Heat DCP (1995).trickplay", "320 - 10x10"), 0o755))
+	require.NoError(t, os.WriteFile(oldPath, []byte("movie"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(oldDir, "Heat DCP (1995).nfo"), []byte("<movie/>"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(oldDir, "Heat DCP (1995)-thumb.jpg"), []byte("jpg"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(oldDir, "Heat DCP (1995).trickplay", "320 - 10x10", "0.jpg"), []byte("jpg"), 0o644))
+
+	_, err := db.EnqueueHousekeepingTask("housekeeping.detect", database.TaskKindParserDriftRename, map[string]any{
+		"src_path": oldPath,
+		"dst_path": newPath,
+	}, 70)
+	require.NoError(t, err)
+
+	engine := NewEngine(Config{
+		MovieLibraries:     []string{lib},
+		MaxConcurrentTasks: 1,
+		MaxTasksPerCycle:   50,
+		TaskRetryMax:       1,
+	}, db, nil)
+	require.NoError(t, engine.Drain(t.Context()))
+
+	require.FileExists(t, newPath)
+	require.FileExists(t, filepath.Join(newDir, "Heat (1995).nfo"))
+	require.FileExists(t, filepath.Join(newDir, "Heat (1995)-thumb.jpg"))
+	require.FileExists(t, filepath.Join(newDir, "Heat (1995).trickplay", "320 - 10x10", "0.jpg"))
+	require.NoDirExists(t, filepath.Join(newDir, "Heat DCP (1995).trickplay"))
+	require.NoDirExists(t, oldDir)
+}
+
+func TestDetectAndDrainOrphanSidecars(t *testing.T) {
+	db := openTestDB(t)
+	lib := t.TempDir()
+
+	dir := filepath.Join(lib, "Heat (1995)")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "Heat DCP (1995).trickplay"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Heat (1995).mkv"), []byte("movie"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Heat DCP (1995).nfo"), []byte("<movie/>"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Heat DCP (1995).trickplay", "0.jpg"), []byte("jpg"), 0o644))
+	// Folder-level metadata and a scene NFO are nobody's orphans.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "movie.nfo"), []byte("<movie/>"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "GRP.nfo"), []byte("Released by GRP"), 0o644))
+	// Nor is anything in a folder with two videos.
+	shared := filepath.Join(lib, "Shared")
+	require.NoError(t, os.MkdirAll(shared, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(shared, "A (2001).mkv"), []byte("a"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(shared, "B (2002).mkv"), []byte("b"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(shared, "C (2003).nfo"), []byte("<movie/>"), 0o644))
+
+	engine := NewEngine(Config{
+		MovieLibraries:     []string{lib},
+		MaxConcurrentTasks: 1,
+		MaxTasksPerCycle:   50,
+		TaskRetryMax:       1,
+	}, db, nil)
+
+	res, err := engine.Detect(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, 2, res.OrphanSidecars)
+
+	require.NoError(t, engine.Drain(t.Context()))
+
+	require.FileExists(t, filepath.Join(dir, "Heat (1995).nfo"))
+	require.FileExists(t, filepath.Join(dir, "Heat (1995).trickplay", "0.jpg"))
+	require.NoFileExists(t, filepath.Join(dir, "Heat DCP (1995).nfo"))
+	require.NoDirExists(t, filepath.Join(dir, "Heat DCP (1995).trickplay"))
+	require.FileExists(t, filepath.Join(dir, "movie.nfo"))
+	require.FileExists(t, filepath.Join(dir, "GRP.nfo"))
+	require.FileExists(t, filepath.Join(shared, "C (2003).nfo"))
+}
+
 func TestDrainParserDriftMovieRenameFailureWritesRepairEvent(t *testing.T) {
 	db := openTestDB(t)
 	lib := t.TempDir()
@@ -245,6 +359,17 @@ func TestDrainParserDriftMovieRenameFailureWritesRepairEvent(t *testing.T) {
 		TaskPauseBetween:   0,
 	}, db, nil)
 
+	var alerts []notify.Alert
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ Alerts []notify.Alert }
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		alerts = append(alerts, body.Alerts...)
+	}))
+	defer hook.Close()
+	alerter, err := notify.NewAlerter([]notify.Route{{URL: hook.URL, Digest: time.Hour}})
+	require.NoError(t, err)
+	engine.SetAlerter(alerter)
+
 	since := time.Now().UTC().Add(-time.Hour)
 	require.NoError(t, engine.Drain(t.Context()))
 
@@ -256,6 +381,56 @@ func TestDrainParserDriftMovieRenameFailureWritesRepairEvent(t *testing.T) {
 	require.NotEmpty(t, events[0].Error)
 	require.Equal(t, srcPath, events[0].SourcePath)
 	require.Equal(t, dstPath, events[0].TargetPath)
+
+	alerter.Close()
+	require.Len(t, alerts, 1)
+	require.Equal(t, notify.AlertFailed, alerts[0].Kind)
+	require.Equal(t, srcPath, alerts[0].Path)
+}
+
+func TestDrainOrphanSourceFailureWritesRepairEvent(t *testing.T) {
+	db := openTestDB(t)
+	dir := t.TempDir()
+	// The folder gained a file after detection, so removing it fails.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.mkv"), []byte("x"), 0644))
+
+	_, err := db.EnqueueHousekeepingTask("housekeeping.detect", database.TaskKindOrphanSource, map[string]any{
+		"path": dir,
+	}, 50)
+	require.NoError(t, err)
+
+	engine := NewEngine(Config{
+		MaxConcurrentTasks: 1,
+		MaxTasksPerCycle:   50,
+		TaskRetryMax:       1,
+	}, db, nil)
+
+	var alerts []notify.Alert
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ Alerts []notify.Alert }
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		alerts = append(alerts, body.Alerts...)
+	}))
+	defer hook.Close()
+	alerter, err := notify.NewAlerter([]notify.Route{{URL: hook.URL, Digest: time.Hour}})
+	require.NoError(t, err)
+	engine.SetAlerter(alerter)
+
+	since := time.Now().UTC().Add(-time.Hour)
+	require.NoError(t, engine.Drain(t.Context()))
+
+	events, err := db.ListRepairEventsSince(since, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, database.TaskKindOrphanSource, events[0].Action)
+	require.Equal(t, "failed", events[0].Outcome)
+	require.Equal(t, dir, events[0].SourcePath)
+	require.Contains(t, events[0].Error, "no longer empty")
+
+	alerter.Close()
+	require.Len(t, alerts, 1)
+	require.Equal(t, notify.AlertFailed, alerts[0].Kind)
+	require.Equal(t, dir, alerts[0].Path)
 }
 
 func TestDrainParserDriftRenameUsesExistingEmptyCanonicalDir(t *testing.T) {
@@ -268,6 +443,8 @@ func TestDrainParserDriftRenameUsesExistingEmptyCanonicalDir(t *testing.T) {
 	newPath := filepath.Join(newDir, "Is This Thing On (2025).mp4")
 	require.NoError(t, os.MkdirAll(oldDir, 0o755))
 	require.NoError(t, os.WriteFile(oldPath, []byte("movie"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(oldDir, "featurettes"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(oldDir, "featurettes", "Making Of.mp4"), []byte("extra"), 0o644))
 	require.NoError(t, os.MkdirAll(newDir, 0o755))
 
 	now := time.Now().UTC()
@@ -307,6 +484,7 @@ func TestDrainParserDriftRenameUsesExistingEmptyCanonicalDir(t *testing.T) {
 	require.NoFileExists(t, oldPath)
 	require.NoDirExists(t, oldDir)
 	require.FileExists(t, newPath)
+	require.FileExists(t, filepath.Join(newDir, "featurettes", "Making Of.mp4"))
 
 	decision, err := db.GetDecision(id)
 	require.NoError(t, err)
@@ -412,6 +590,51 @@ func TestDetectEnqueuesParserDriftTVRenameForReleaseYearAfterEpisodeMarker(t *te
 	require.Equal(t, filepath.Join(lib, "Upload", "Season 04", "Upload S04E03.mkv"), tasks[0].Payload["dst_path"])
 }
 
+func TestDetectParserDriftTVRenameKeepsSpecialsFolder(t *testing.T) {
+	db := openTestDB(t)
+	lib := t.TempDir()
+
+	oldDir := filepath.Join(lib, "Upload (2025)", "Specials")
+	oldPath := filepath.Join(oldDir, "Upload (2025) S00E03.mkv")
+	require.NoError(t, os.MkdirAll(oldDir, 0o755))
+	require.NoError(t, os.WriteFile(oldPath, []byte("episode"), 0o644))
+
+	now := time.Now().UTC().Add(-25 * 24 * time.Hour)
+	parsedYear := 2025
+	id, err := db.InsertDecision(database.ParseDecision{
+		SourcePath:       "/watch/tv/Upload.S00E03.2025.1080p.Amazon.WEB-DL.AVC.DDP.5.1-DBTV.mkv",
+		SourceFilename:   "Upload.S00E03.2025.1080p.Amazon.WEB-DL.AVC.DDP.5.1-DBTV.mkv",
+		EventAt:          now,
+		MediaTypeGuessed: "tv",
+		ParseMethod:      "regex",
+		ParsedTitle:      "Upload",
+		ParsedYear:       &parsedYear,
+	})
+	require.NoError(t, err)
+	targetAt := now
+	require.NoError(t, db.UpdateOrganize(id, database.OrganizeUpdate{
+		TargetPath:      oldPath,
+		TargetAt:        &targetAt,
+		OrganizeOutcome: "success",
+	}))
+	require.NoError(t, db.UpdateMetadataCheckState(id, "series_unidentified", "repair candidate", nil))
+
+	engine := NewEngine(Config{
+		TVLibraries:        []string{lib},
+		MaxTasksPerCycle:   50,
+		MaxConcurrentTasks: 1,
+	}, db, nil)
+
+	res, err := engine.Detect(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, 1, res.ParserDriftRenames)
+
+	tasks, err := db.ListHousekeepingTasks(database.TaskStatusPending, 10)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	require.Equal(t, filepath.Join(lib, "Upload", "Specials", "Upload S00E03.mkv"), tasks[0].Payload["dst_path"])
+}
+
 func TestDetectSkipsTVParserDriftWhenTargetYearIsNotReleaseYearAfterEpisodeMarker(t *testing.T) {
 	db := openTestDB(t)
 	lib := t.TempDir()
@@ -596,6 +819,64 @@ func TestDrainParserDriftTVRenameMovesMultipleEpisodeFiles(t *testing.T) {
 	require.Equal(t, 2, *decision2.ParsedEpisode)
 }
 
+func TestDrainParserDriftTVRenameCarriesExtras(t *testing.T) {
+	db := openTestDB(t)
+	lib := t.TempDir()
+
+	oldShow := filepath.Join(lib, "Upload (2025)")
+	oldPath := filepath.Join(oldShow, "Season 04", "Upload (2025) S04E01.mkv")
+	newShow := filepath.Join(lib, "Upload")
+	newPath := filepath.Join(newShow, "Season 04", "Upload S04E01.mkv")
+	for path, data := range map[string]string{
+		oldPath: "episode",
+		filepath.Join(oldShow, "Season 04", "extras", "Bloopers.mkv"): "season extra",
+		filepath.Join(oldShow, "trailers", "Trailer.mkv"):             "show extra",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+	}
+
+	sourceFilename := "Upload.S04E01.2025.1080p.Amazon.WEB-DL.AVC.DDP.5.1-DBTV.mkv"
+	now := time.Now().UTC()
+	parsedYear := 2025
+	id, err := db.InsertDecision(database.ParseDecision{
+		SourcePath:       filepath.Join("/watch/tv", sourceFilename),
+		SourceFilename:   sourceFilename,
+		EventAt:          now,
+		MediaTypeGuessed: "tv",
+		ParseMethod:      "regex",
+		ParsedTitle:      "Upload",
+		ParsedYear:       &parsedYear,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.UpdateOrganize(id, database.OrganizeUpdate{
+		TargetPath:      oldPath,
+		TargetAt:        &now,
+		OrganizeOutcome: "success",
+	}))
+	require.NoError(t, db.UpdateMetadataCheckState(id, "series_unidentified", "repair candidate", nil))
+	_, err = db.EnqueueHousekeepingTask("housekeeping.detect", database.TaskKindParserDriftTVRename, map[string]any{
+		"parse_decision_id": id,
+		"src_path":          oldPath,
+		"dst_path":          newPath,
+		"source_filename":   sourceFilename,
+	}, 70)
+	require.NoError(t, err)
+
+	engine := NewEngine(Config{
+		TVLibraries:        []string{lib},
+		MaxConcurrentTasks: 1,
+		MaxTasksPerCycle:   50,
+		TaskRetryMax:       1,
+	}, db, nil)
+	require.NoError(t, engine.Drain(t.Context()))
+
+	require.FileExists(t, newPath)
+	require.FileExists(t, filepath.Join(newShow, "Season 04", "extras", "Bloopers.mkv"))
+	require.FileExists(t, filepath.Join(newShow, "trailers", "Trailer.mkv"))
+	require.NoDirExists(t, oldShow)
+}
+
 func TestDrainMergeMoveUpdatesParseDecisionTargetPaths(t *testing.T) {
 	db := openTestDB(t)
 	srcRoot := filepath.Join(t.TempDir(), "Upload")
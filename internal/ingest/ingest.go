@@ -0,0 +1,321 @@
+// Package ingest decides which files under watch folders count as media,
+// so the watcher, the periodic watch-folder scan and library scans skip
+// the same samples, unpack folders and NAS metadata.
+package ingest
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+	"github.com/Nomadcxx/jellywatch/internal/video"
+)
+
+// Media types a folder can hold.
+const (
+	MediaTypeMovie = "movie"
+	MediaTypeTV    = "tv"
+)
+
+// HiddenPolicy says what happens to files and folders whose name starts
+// with a dot.
+type HiddenPolicy string
+
+const (
+	HiddenSkip    HiddenPolicy = "skip"
+	HiddenInclude HiddenPolicy = "include"
+)
+
+// DefaultIgnoreDirs are folder names skipped everywhere: SABnzbd's
+// extraction staging, Resilio Sync state and Synology thumbnails.
+var DefaultIgnoreDirs = []string{"_UNPACK_*", ".sync", "@eaDir"}
+
+// Filter says which files in a folder are media. Patterns are globs in
+// filepath.Match syntax, matched case-insensitively against the file or
+// folder name, or, when they contain a "/", against the path below the
+// watch folder.
+type Filter struct {
+	// Include, when set, admits only files matching one of the patterns.
+	Include []string
+	// Exclude rejects files matching any pattern.
+	Exclude []string
+	// IgnoreDirs rejects everything inside a folder matching a pattern.
+	IgnoreDirs []string
+	// MinMovieSize and MinEpisodeSize reject smaller files, in bytes;
+	// 0 is no minimum.
+	MinMovieSize   int64
+	MinEpisodeSize int64
+	// Hidden is the dot-file policy; empty inherits, and skip at the top.
+	Hidden HiddenPolicy
+}
+
+// inherit returns f layered over base: pattern lists add up, sizes and
+// the hidden policy replace base's when set.
+func (f Filter) inherit(base Filter) Filter {
+	out := Filter{
+		Include:        append(append([]string(nil), base.Include...), f.Include...),
+		Exclude:        append(append([]string(nil), base.Exclude...), f.Exclude...),
+		IgnoreDirs:     append(append([]string(nil), base.IgnoreDirs...), f.IgnoreDirs...),
+		MinMovieSize:   base.MinMovieSize,
+		MinEpisodeSize: base.MinEpisodeSize,
+		Hidden:         base.Hidden,
+	}
+	if f.MinMovieSize > 0 {
+		out.MinMovieSize = f.MinMovieSize
+	}
+	if f.MinEpisodeSize > 0 {
+		out.MinEpisodeSize = f.MinEpisodeSize
+	}
+	if f.Hidden != "" {
+		out.Hidden = f.Hidden
+	}
+	return out
+}
+
+func (f Filter) validate() error {
+	for _, list := range [][]string{f.Include, f.Exclude, f.IgnoreDirs} {
+		for _, pattern := range list {
+			if _, err := filepath.Match(strings.ToLower(pattern), ""); err != nil {
+				return fmt.Errorf("bad pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	switch f.Hidden {
+	case "", HiddenSkip, HiddenInclude:
+	default:
+		return fmt.Errorf("unknown hidden_files policy %q (want skip or include)", f.Hidden)
+	}
+	if f.MinMovieSize < 0 || f.MinEpisodeSize < 0 {
+		return fmt.Errorf("minimum sizes can't be negative")
+	}
+	return nil
+}
+
+func (f Filter) hiddenSkipped(name string) bool {
+	return f.Hidden != HiddenInclude && strings.HasPrefix(name, ".")
+}
+
+func (f Filter) ignoresDir(name string) bool {
+	if f.hiddenSkipped(name) {
+		return true
+	}
+	return matchAny(f.IgnoreDirs, name, "") != ""
+}
+
+// Folder attaches a filter and media type to a folder and everything
+// below it.
+type Folder struct {
+	Path string
+	// MediaType is MediaTypeMovie or MediaTypeTV; empty when the folder
+	// holds both or is only there to carry a filter.
+	MediaType string
+	Filter    Filter
+}
+
+type folder struct {
+	path      string
+	mediaType string
+	filter    Filter // resolved against the folders above it
+}
+
+// Filters answers filter questions for any path: the filter of the
+// deepest folder holding it applies, layered over those of the folders
+// above it and the default. A nil *Filters uses Default. Replace swaps
+// the rules in place, so everything holding the pointer follows a config
+// reload.
+type Filters struct {
+	set atomic.Pointer[filterSet]
+}
+
+type filterSet struct {
+	def     Filter
+	folders []folder // deepest first
+}
+
+// New validates the patterns and resolves each folder's filter.
+func New(def Filter, folders []Folder) (*Filters, error) {
+	if err := def.validate(); err != nil {
+		return nil, err
+	}
+	if def.Hidden == "" {
+		def.Hidden = HiddenSkip
+	}
+
+	sorted := make([]Folder, 0, len(folders))
+	for _, f := range folders {
+		if strings.TrimSpace(f.Path) == "" {
+			return nil, fmt.Errorf("folder filter without a path")
+		}
+		if err := f.Filter.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Path, err)
+		}
+		f.Path = filepath.Clean(f.Path)
+		sorted = append(sorted, f)
+	}
+	// Outermost first, so each folder can inherit from one already resolved.
+	sort.SliceStable(sorted, func(i, j int) bool { return len(sorted[i].Path) < len(sorted[j].Path) })
+
+	fs := &filterSet{def: def}
+	for _, f := range sorted {
+		base := def
+		if parent := fs.folderFor(f.Path); parent != nil {
+			base = parent.filter
+		}
+		fs.folders = append([]folder{{path: f.Path, mediaType: f.MediaType, filter: f.Filter.inherit(base)}}, fs.folders...)
+	}
+	out := &Filters{}
+	out.set.Store(fs)
+	return out, nil
+}
+
+var defaultFilters, _ = New(Filter{IgnoreDirs: DefaultIgnoreDirs}, nil)
+
+// Default skips DefaultIgnoreDirs and hidden files and nothing else.
+func Default() *Filters {
+	return defaultFilters
+}
+
+// Replace makes f answer with other's rules from the next call on; nil
+// restores Default.
+func (f *Filters) Replace(other *Filters) {
+	if other == nil {
+		other = defaultFilters
+	}
+	f.set.Store(other.set.Load())
+}
+
+// folderFor returns the deepest folder holding path, or nil.
+func (f *filterSet) folderFor(path string) *folder {
+	for i := range f.folders {
+		if within(path, f.folders[i].path) {
+			return &f.folders[i]
+		}
+	}
+	return nil
+}
+
+func (f *filterSet) filterFor(path string) Filter {
+	if folder := f.folderFor(path); folder != nil {
+		return folder.filter
+	}
+	return f.def
+}
+
+// SkipDir reports whether a walk should not descend into dir. A
+// configured folder itself is never skipped.
+func (f *Filters) SkipDir(dir string) bool {
+	s := f.current()
+	dir = filepath.Clean(dir)
+	for _, folder := range s.folders {
+		if folder.path == dir {
+			return false
+		}
+	}
+	return s.filterFor(filepath.Dir(dir)).ignoresDir(filepath.Base(dir))
+}
+
+// Accept reports whether the file at path is media, and if not, why.
+// mediaType is "movie", "tv" or "episode"; empty takes the folder's type
+// or, failing that, guesses from the filename. A negative size skips the
+// size check, for files that are still being written or already gone.
+// Minimum sizes only apply to videos: an archive volume is as small as
+// its packer chose.
+func (f *Filters) Accept(path string, size int64, mediaType string) (bool, string) {
+	s := f.current()
+	path = filepath.Clean(path)
+	name := filepath.Base(path)
+	filter := s.filterFor(path)
+
+	// Folders between the outermost watch folder and the file; without
+	// one, only the file's own name is checked.
+	var rel string
+	for i := len(s.folders) - 1; i >= 0; i-- {
+		if within(path, s.folders[i].path) {
+			rel, _ = filepath.Rel(s.folders[i].path, path)
+			break
+		}
+	}
+	if rel != "" {
+		dirs := strings.Split(filepath.ToSlash(rel), "/")
+		dirs = dirs[:len(dirs)-1]
+		for _, dir := range dirs {
+			if filter.ignoresDir(dir) {
+				return false, fmt.Sprintf("inside ignored folder %s", dir)
+			}
+		}
+	} else {
+		rel = name
+	}
+
+	if filter.hiddenSkipped(name) {
+		return false, "hidden file"
+	}
+	if pattern := matchAny(filter.Exclude, name, rel); pattern != "" {
+		return false, fmt.Sprintf("excluded by %q", pattern)
+	}
+	if len(filter.Include) > 0 && matchAny(filter.Include, name, rel) == "" {
+		return false, "not matched by any include pattern"
+	}
+
+	if size >= 0 && video.IsVideo(path) {
+		min := filter.MinMovieSize
+		if s.mediaTypeFor(path, mediaType) == MediaTypeTV {
+			min = filter.MinEpisodeSize
+		}
+		if size < min {
+			return false, fmt.Sprintf("smaller than %d MB", min>>20)
+		}
+	}
+	return true, ""
+}
+
+// current returns the rules in force, Default's for a nil f.
+func (f *Filters) current() *filterSet {
+	if f == nil {
+		f = defaultFilters
+	}
+	return f.set.Load()
+}
+
+func (f *filterSet) mediaTypeFor(path, mediaType string) string {
+	switch mediaType {
+	case MediaTypeMovie:
+		return MediaTypeMovie
+	case MediaTypeTV, "episode":
+		return MediaTypeTV
+	}
+	for _, folder := range f.folders {
+		if folder.mediaType != "" && within(path, folder.path) {
+			return folder.mediaType
+		}
+	}
+	if naming.IsTVEpisodeFilename(filepath.Base(path)) {
+		return MediaTypeTV
+	}
+	return MediaTypeMovie
+}
+
+// matchAny returns the first pattern matching name, or rel for patterns
+// with a "/".
+func matchAny(patterns []string, name, rel string) string {
+	name = strings.ToLower(name)
+	rel = strings.ToLower(filepath.ToSlash(rel))
+	for _, pattern := range patterns {
+		p := strings.ToLower(pattern)
+		subject := name
+		if strings.Contains(p, "/") {
+			subject = rel
+		}
+		if ok, _ := filepath.Match(p, subject); ok {
+			return pattern
+		}
+	}
+	return ""
+}
+
+func within(path, root string) bool {
+	return path == root || strings.HasPrefix(path, root+string(filepath.Separator))
+}
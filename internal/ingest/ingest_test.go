@@ -0,0 +1,97 @@
+package ingest
+
+import "testing"
+
+func TestFiltersAccept(t *testing.T) {
+	f, err := New(Filter{
+		Exclude:    []string{"*sample*"},
+		IgnoreDirs: DefaultIgnoreDirs,
+		// 100 MB movies, 20 MB episodes
+		MinMovieSize:   100 << 20,
+		MinEpisodeSize: 20 << 20,
+	}, []Folder{
+		{Path: "/dl/tv", MediaType: MediaTypeTV},
+		{Path: "/dl/movies", MediaType: MediaTypeMovie},
+		{Path: "/dl", Filter: Filter{IgnoreDirs: []string{"incomplete"}}},
+		{Path: "/dl/tv/anime", Filter: Filter{Include: []string{"*.mkv"}, MinEpisodeSize: 1 << 20}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const mb = 1 << 20
+	cases := []struct {
+		path      string
+		size      int64
+		mediaType string
+		want      bool
+	}{
+		{"/dl/movies/Film (2020)/Film.2020.mkv", 500 * mb, "", true},
+		{"/dl/movies/Film (2020)/Film.2020.sample.mkv", 500 * mb, "", false},
+		{"/dl/movies/Film.2020.mkv", 50 * mb, "", false},
+		{"/dl/movies/Film.2020.mkv", -1, "", true},
+		{"/dl/movies/Film.2020/film.r07", 15 * mb, "", true},
+		{"/dl/tv/Show.S01E01.mkv", 50 * mb, "", true},
+		{"/dl/tv/Show.S01E01.mkv", 50 * mb, "movie", false},
+		{"/dl/tv/_UNPACK_Show.S01/Show.S01E01.mkv", 50 * mb, "", false},
+		{"/dl/tv/.hidden/Show.S01E01.mkv", 50 * mb, "", false},
+		{"/dl/tv/.Show.S01E01.mkv", 50 * mb, "", false},
+		{"/dl/tv/incomplete/Show.S01E01.mkv", 50 * mb, "", false},
+		{"/dl/tv/anime/Show - 01.mkv", 5 * mb, "", true},
+		{"/dl/tv/anime/Show - 01.mp4", 5 * mb, "", false},
+		{"/lib/@eaDir/x.mkv", 500 * mb, "", true}, // outside every folder: only the name counts
+	}
+	for _, tc := range cases {
+		if got, reason := f.Accept(tc.path, tc.size, tc.mediaType); got != tc.want {
+			t.Errorf("Accept(%q, %d, %q) = %v (%s), want %v", tc.path, tc.size, tc.mediaType, got, reason, tc.want)
+		}
+	}
+
+	for dir, want := range map[string]bool{
+		"/dl/tv":                 false,
+		"/dl/tv/Show":            false,
+		"/dl/tv/@eaDir":          true,
+		"/dl/tv/_unpack_Show":    true,
+		"/dl/movies/incomplete":  true,
+		"/elsewhere/.sync":       true,
+		"/elsewhere/.git":        true,
+		"/elsewhere/Show Season": false,
+	} {
+		if got := f.SkipDir(dir); got != want {
+			t.Errorf("SkipDir(%q) = %v, want %v", dir, got, want)
+		}
+	}
+}
+
+func TestFiltersHiddenInclude(t *testing.T) {
+	f, err := New(Filter{}, []Folder{{Path: "/dl", Filter: Filter{Hidden: HiddenInclude}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, reason := f.Accept("/dl/.staging/Film.2020.mkv", 1, ""); !ok {
+		t.Fatalf("hidden file rejected with hidden_files = include: %s", reason)
+	}
+	if f.SkipDir("/dl/.staging") {
+		t.Fatal("hidden folder skipped with hidden_files = include")
+	}
+	if !f.SkipDir("/other/.staging") {
+		t.Fatal("hidden folder outside /dl not skipped")
+	}
+
+	if _, err := New(Filter{Exclude: []string{"[bad"}}, nil); err == nil {
+		t.Fatal("New accepted a bad pattern")
+	}
+	if _, err := New(Filter{Hidden: "show"}, nil); err == nil {
+		t.Fatal("New accepted an unknown hidden policy")
+	}
+}
+
+func TestNilFiltersUseDefault(t *testing.T) {
+	var f *Filters
+	if !f.SkipDir("/dl/_UNPACK_Film") {
+		t.Fatal("nil Filters doesn't skip _UNPACK_ folders")
+	}
+	if ok, _ := f.Accept("/dl/Film.2020.mkv", 1, ""); !ok {
+		t.Fatal("nil Filters rejected a plain video")
+	}
+}
@@ -188,3 +188,32 @@ func TestGetSystemInfo_InvalidURLAndHTTPError(t *testing.T) {
 		t.Fatalf("expected API status error, got %v", err)
 	}
 }
+
+func TestNotifyMediaUpdated_PostsUpdates(t *testing.T) {
+	var gotPath string
+	var gotBody struct {
+		Updates []MediaUpdate
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	client := NewClient(Config{URL: ts.URL, APIKey: "secret-key"})
+	err := client.NotifyMediaUpdated([]MediaUpdate{
+		{Path: "/movies/Heat (1995)/Heat (1995).mkv", UpdateType: "Deleted"},
+		{Path: "/downloads/Heat.1995.mkv", UpdateType: "Created"},
+	})
+	if err != nil {
+		t.Fatalf("NotifyMediaUpdated() error = %v", err)
+	}
+	if gotPath != "/Library/Media/Updated" {
+		t.Fatalf("path = %s, want /Library/Media/Updated", gotPath)
+	}
+	if len(gotBody.Updates) != 2 || gotBody.Updates[1].UpdateType != "Created" {
+		t.Fatalf("updates = %+v", gotBody.Updates)
+	}
+}
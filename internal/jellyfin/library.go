@@ -62,3 +62,20 @@ func (c *Client) GetPhysicalPaths() ([]string, error) {
 	}
 	return paths, nil
 }
+
+// MediaUpdate is one changed path for NotifyMediaUpdated. UpdateType is
+// "Created", "Modified" or "Deleted".
+type MediaUpdate struct {
+	Path       string `json:"Path"`
+	UpdateType string `json:"UpdateType"`
+}
+
+// NotifyMediaUpdated tells Jellyfin specific paths changed, so it
+// re-indexes them without a full library scan.
+func (c *Client) NotifyMediaUpdated(updates []MediaUpdate) error {
+	payload := map[string]interface{}{"Updates": updates}
+	if err := c.post("/Library/Media/Updated", payload, nil); err != nil {
+		return fmt.Errorf("notifying media updated: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,352 @@
+// Package journal reverses filesystem operations recorded in the
+// database's operation journal.
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/logging"
+	"github.com/Nomadcxx/jellywatch/internal/sidecars"
+	"github.com/Nomadcxx/jellywatch/internal/subtitles"
+	"github.com/Nomadcxx/jellywatch/internal/transfer"
+	"github.com/Nomadcxx/jellywatch/internal/video"
+)
+
+// Syncer tells Sonarr/Radarr and Jellyfin about restored paths.
+// *sync.SyncService implements it.
+type Syncer interface {
+	QueueSync(mediaType string, id int64)
+	NotifyPathChange(oldPath, newPath string) error
+}
+
+// Undoer replays journal operations in reverse.
+type Undoer struct {
+	db         *database.MediaDB
+	syncer     Syncer
+	transferer transfer.Transferer
+	logger     *logging.Logger
+	dryRun     bool
+
+	// planned tracks the paths a dry run has already "moved", true when
+	// the path would exist, so later operations in a batch check against
+	// the state the earlier ones would leave.
+	planned map[string]bool
+}
+
+// NewUndoer returns an Undoer. syncer may be nil, in which case only the
+// dirty flags record that Sonarr/Radarr need the restored paths.
+func NewUndoer(db *database.MediaDB, syncer Syncer, dryRun bool) *Undoer {
+	t, err := transfer.New(transfer.BackendAuto)
+	if err != nil {
+		t = transfer.NewNativeTransferer(32 * 1024 * 1024)
+	}
+	return &Undoer{db: db, syncer: syncer, transferer: t, logger: logging.Nop(), dryRun: dryRun}
+}
+
+// SetLogger sets where warnings about sidecars and notifications go.
+func (u *Undoer) SetLogger(l *logging.Logger) {
+	if l != nil {
+		u.logger = l
+	}
+}
+
+// Skipped is an operation a batch undo left alone.
+type Skipped struct {
+	Op     database.JournalOp
+	Reason string
+}
+
+// Result reports what an undo did.
+type Result struct {
+	// Batch groups the inverse operations the undo journaled.
+	Batch   string
+	Undone  []database.JournalOp
+	Skipped []Skipped
+}
+
+// UndoOperation reverses one operation.
+func (u *Undoer) UndoOperation(id int64) (*Result, error) {
+	op, err := u.db.GetOperation(id)
+	if err != nil {
+		return nil, err
+	}
+	if op == nil {
+		return nil, fmt.Errorf("operation %d not found", id)
+	}
+	if reason := u.refuse(*op, nil); reason != "" {
+		return nil, fmt.Errorf("operation %d: %s", id, reason)
+	}
+	u.planned = make(map[string]bool)
+	res := &Result{Batch: database.NewJournalBatch(database.OpSourceUndo)}
+	if err := u.undo(*op, res.Batch); err != nil {
+		return res, fmt.Errorf("operation %d: %w", id, err)
+	}
+	res.Undone = append(res.Undone, *op)
+	return res, nil
+}
+
+// UndoBatch reverses a batch newest operation first. Operations that were
+// already undone or can't be reversed are skipped; the first failure stops
+// the batch so later operations are never replayed out of order.
+func (u *Undoer) UndoBatch(batchID string) (*Result, error) {
+	ops, err := u.db.ListOperationBatch(batchID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("batch %s not found", batchID)
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].ID > ops[j].ID })
+
+	u.planned = make(map[string]bool)
+	res := &Result{Batch: database.NewJournalBatch(database.OpSourceUndo)}
+	undone := make(map[int64]bool)
+	for _, op := range ops {
+		if reason := u.refuse(op, undone); reason != "" {
+			res.Skipped = append(res.Skipped, Skipped{Op: op, Reason: reason})
+			continue
+		}
+		if err := u.undo(op, res.Batch); err != nil {
+			return res, fmt.Errorf("operation %d: %w", op.ID, err)
+		}
+		res.Undone = append(res.Undone, op)
+		undone[op.ID] = true
+	}
+	return res, nil
+}
+
+// refuse says why op can't be undone, or "" if it can. undone holds the
+// operations this run already reversed, which a dry run never marks.
+func (u *Undoer) refuse(op database.JournalOp, undone map[int64]bool) string {
+	if op.UndoneAt != nil {
+		return fmt.Sprintf("already undone by %s", op.UndoBatch)
+	}
+	if !op.Reversible {
+		return fmt.Sprintf("%s is not reversible", op.Kind)
+	}
+	if op.AfterPath != "" {
+		later, err := u.db.LaterOperationFrom(op.AfterPath, op.ID)
+		if err != nil {
+			return err.Error()
+		}
+		if later != nil && !undone[later.ID] {
+			return fmt.Sprintf("%s was changed again by operation %d; undo that first", op.AfterPath, later.ID)
+		}
+	}
+	return ""
+}
+
+func (u *Undoer) undo(op database.JournalOp, undoBatch string) error {
+	switch op.Kind {
+	case database.OpMove, database.OpDelete:
+		// A reversible delete kept the file at AfterPath, so both come
+		// back the same way.
+		if err := u.moveBack(op, undoBatch); err != nil {
+			return err
+		}
+	case database.OpCopy:
+		if err := u.removeCopy(op, undoBatch); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown operation kind %q", op.Kind)
+	}
+	if u.dryRun {
+		return nil
+	}
+	return u.db.MarkOperationUndone(op.ID, undoBatch)
+}
+
+func (u *Undoer) moveBack(op database.JournalOp, undoBatch string) error {
+	if op.BeforePath == "" || op.AfterPath == "" {
+		return fmt.Errorf("%s has no path to restore", op.Kind)
+	}
+	if !u.exists(op.AfterPath) {
+		return fmt.Errorf("%s is gone", op.AfterPath)
+	}
+	if u.exists(op.BeforePath) {
+		return fmt.Errorf("%s already exists", op.BeforePath)
+	}
+	if u.dryRun {
+		u.planned[op.AfterPath], u.planned[op.BeforePath] = false, true
+		return nil
+	}
+
+	var subs []subtitles.Sidecar
+	if info, err := os.Stat(op.AfterPath); err == nil && !info.IsDir() {
+		subs = subtitles.Find(op.AfterPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(op.BeforePath), 0755); err != nil {
+		return fmt.Errorf("create %s: %w", filepath.Dir(op.BeforePath), err)
+	}
+	if op.Kind == database.OpMove && video.IsVideo(op.AfterPath) {
+		// NFOs, artwork and trickplay go back with the video.
+		if _, err := sidecars.MoveWith(op.AfterPath, op.BeforePath, u.rename); err != nil {
+			return err
+		}
+	} else if err := u.rename(op.AfterPath, op.BeforePath); err != nil {
+		return err
+	}
+	for _, t := range subtitles.Plan(op.BeforePath, subs) {
+		if err := u.rename(t.Source, t.Target); err != nil {
+			u.logger.Warn("journal", "subtitle move failed", logging.F("from", t.Source), logging.F("to", t.Target), logging.F("error", err))
+		}
+	}
+	// Leave no empty title folder behind; a non-empty one stays.
+	_ = os.Remove(filepath.Dir(op.AfterPath))
+
+	if _, err := u.db.RecordOperation(database.JournalOp{
+		BatchID:    undoBatch,
+		Source:     database.OpSourceUndo,
+		Kind:       database.OpMove,
+		BeforePath: op.AfterPath,
+		AfterPath:  op.BeforePath,
+		Reversible: true,
+	}); err != nil {
+		return err
+	}
+	if op.Kind == database.OpDelete {
+		// The file came back out of the recycle bin.
+		if err := u.db.DeleteTrashItemByPath(op.AfterPath); err != nil {
+			return err
+		}
+	}
+	if err := u.restoreRows(op); err != nil {
+		return err
+	}
+	u.notify(op.AfterPath, op.BeforePath)
+	return nil
+}
+
+func (u *Undoer) removeCopy(op database.JournalOp, undoBatch string) error {
+	if !u.exists(op.BeforePath) {
+		return fmt.Errorf("original %s is gone; refusing to delete the only copy", op.BeforePath)
+	}
+	if u.dryRun {
+		u.planned[op.AfterPath] = false
+		return nil
+	}
+	if op.AfterPath != "" {
+		for _, s := range subtitles.Find(op.AfterPath) {
+			_ = os.Remove(s.Path)
+		}
+		if err := os.Remove(op.AfterPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove %s: %w", op.AfterPath, err)
+		}
+		_ = os.Remove(filepath.Dir(op.AfterPath))
+	}
+
+	if _, err := u.db.RecordOperation(database.JournalOp{
+		BatchID:    undoBatch,
+		Source:     database.OpSourceUndo,
+		Kind:       database.OpDelete,
+		BeforePath: op.AfterPath,
+	}); err != nil {
+		return err
+	}
+	if err := u.db.DeleteMediaFile(op.AfterPath); err != nil {
+		return err
+	}
+	if err := u.restoreOwners(op.Snapshot); err != nil {
+		return err
+	}
+	u.notify(op.AfterPath, "")
+	return nil
+}
+
+// restoreRows points the database back at BeforePath: the media_files row,
+// parse decisions and the owning series or movie.
+func (u *Undoer) restoreRows(op database.JournalOp) error {
+	if err := u.db.DeleteMediaFile(op.AfterPath); err != nil {
+		return err
+	}
+	// No snapshot means BeforePath had no row (a download being
+	// organized), so there is nothing to put back.
+	if op.Snapshot != nil && op.Snapshot.MediaFile != nil {
+		f := *op.Snapshot.MediaFile
+		f.ID = 0
+		if err := u.db.UpsertMediaFile(&f); err != nil {
+			return err
+		}
+	}
+	if err := u.db.RetargetParseDecisions(op.AfterPath, op.BeforePath); err != nil {
+		return err
+	}
+	return u.restoreOwners(op.Snapshot)
+}
+
+// restoreOwners puts a series or movie folder back where the snapshot had
+// it and queues the path for Sonarr/Radarr.
+func (u *Undoer) restoreOwners(state *database.OpState) error {
+	if state == nil {
+		return nil
+	}
+	if state.SeriesID > 0 && state.SeriesPath != "" {
+		s, err := u.db.GetSeriesByID(state.SeriesID)
+		if err != nil {
+			return err
+		}
+		if s != nil && s.CanonicalPath != state.SeriesPath {
+			if err := u.db.UpdateSeriesCanonicalPath(s.ID, state.SeriesPath, state.SeriesLibrary); err != nil {
+				return err
+			}
+			if u.syncer != nil {
+				u.syncer.QueueSync("series", s.ID)
+			}
+		}
+	}
+	if state.MovieID > 0 && state.MoviePath != "" {
+		m, err := u.db.GetMovieByID(state.MovieID)
+		if err != nil {
+			return err
+		}
+		if m != nil && m.CanonicalPath != state.MoviePath {
+			if err := u.db.UpdateMovieCanonicalPath(m.ID, state.MoviePath, state.MovieLibrary); err != nil {
+				return err
+			}
+			if u.syncer != nil {
+				u.syncer.QueueSync("movie", m.ID)
+			}
+		}
+	}
+	return nil
+}
+
+func (u *Undoer) notify(oldPath, newPath string) {
+	if u.syncer == nil {
+		return
+	}
+	if err := u.syncer.NotifyPathChange(oldPath, newPath); err != nil {
+		u.logger.Warn("journal", "Jellyfin path update failed", logging.F("path", newPath), logging.F("error", err))
+	}
+}
+
+func (u *Undoer) exists(path string) bool {
+	if e, ok := u.planned[path]; ok {
+		return e
+	}
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// rename moves src to dst, copying across filesystems when it must.
+func (u *Undoer) rename(src, dst string) error {
+	err := os.Rename(src, dst)
+	if err == nil || !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	result, err := u.transferer.Move(src, dst, transfer.TransferOptions{})
+	if err != nil {
+		return fmt.Errorf("cross-device move %s -> %s: %w", src, dst, err)
+	}
+	if !result.Success {
+		return fmt.Errorf("cross-device move failed: %v", result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellywatch/internal/database"
+)
+
+type fakeSyncer struct {
+	queued   []string
+	notified [][2]string
+}
+
+func (f *fakeSyncer) QueueSync(mediaType string, id int64) {
+	f.queued = append(f.queued, mediaType)
+}
+
+func (f *fakeSyncer) NotifyPathChange(oldPath, newPath string) error {
+	f.notified = append(f.notified, [2]string{oldPath, newPath})
+	return nil
+}
+
+func openDB(t *testing.T) *database.MediaDB {
+	t.Helper()
+	db, err := database.OpenPath(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("OpenPath: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func writeFile(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// move does what a journaled workflow does: change the filesystem, record
+// the operation, then move the media_files row.
+func move(t *testing.T, db *database.MediaDB, batch, src, dst string) int64 {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(src, dst); err != nil {
+		t.Fatal(err)
+	}
+	id, err := db.RecordOperation(database.JournalOp{
+		BatchID: batch, Source: database.OpSourceConsolidate, Kind: database.OpMove,
+		BeforePath: src, AfterPath: dst, Reversible: true,
+	})
+	if err != nil {
+		t.Fatalf("RecordOperation: %v", err)
+	}
+	if f, _ := db.GetMediaFile(src); f != nil {
+		_ = db.DeleteMediaFile(src)
+		f.Path = dst
+		if err := db.UpsertMediaFile(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.RetargetParseDecisions(src, dst); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestUndoOperationRestoresFileRowsAndDecision(t *testing.T) {
+	db := openDB(t)
+	lib := t.TempDir()
+	src := filepath.Join(lib, "Heat.1995", "Heat.1995.mkv")
+	dst := filepath.Join(lib, "Heat (1995)", "Heat (1995).mkv")
+	writeFile(t, src)
+	writeFile(t, filepath.Join(lib, "Heat.1995", "Heat.1995.en.srt"))
+
+	movie := &database.Movie{Title: "Heat", Year: 1995, CanonicalPath: filepath.Dir(src), LibraryRoot: lib, Source: "jellywatch"}
+	if _, err := db.UpsertMovie(movie); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.UpsertMediaFile(&database.MediaFile{
+		Path: src, Size: 5, ModifiedAt: time.Now(), MediaType: "movie",
+		ParentMovieID: &movie.ID, NormalizedTitle: "heat", Source: "filesystem", LibraryRoot: lib,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	decisionID, err := db.InsertDecision(database.ParseDecision{
+		SourcePath: "/downloads/Heat.1995.mkv", SourceFilename: "Heat.1995.mkv", EventAt: time.Now(),
+		TargetPath: src, OrganizeOutcome: "success",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := move(t, db, "b1", src, dst)
+	if err := os.Rename(filepath.Join(lib, "Heat.1995", "Heat.1995.en.srt"), filepath.Join(lib, "Heat (1995)", "Heat (1995).en.srt")); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Remove(filepath.Dir(src))
+	if err := db.UpdateMovieCanonicalPath(movie.ID, filepath.Dir(dst), lib); err != nil {
+		t.Fatal(err)
+	}
+
+	syncer := &fakeSyncer{}
+	res, err := NewUndoer(db, syncer, false).UndoOperation(id)
+	if err != nil {
+		t.Fatalf("UndoOperation: %v", err)
+	}
+	if len(res.Undone) != 1 {
+		t.Fatalf("undone = %+v", res.Undone)
+	}
+
+	if !exists(src) || exists(dst) || exists(filepath.Dir(dst)) {
+		t.Errorf("file not moved back: src=%v dst=%v dstDir=%v", exists(src), exists(dst), exists(filepath.Dir(dst)))
+	}
+	if !exists(filepath.Join(lib, "Heat.1995", "Heat.1995.eng.srt")) {
+		t.Error("subtitle did not follow the video back")
+	}
+	if f, _ := db.GetMediaFile(src); f == nil || f.ParentMovieID == nil || *f.ParentMovieID != movie.ID {
+		t.Errorf("media_files row at %s = %+v", src, f)
+	}
+	if f, _ := db.GetMediaFile(dst); f != nil {
+		t.Errorf("stale media_files row at %s", dst)
+	}
+	if d, _ := db.GetDecision(decisionID); d == nil || d.TargetPath != src {
+		t.Errorf("parse decision target = %+v, want %s", d, src)
+	}
+	if m, _ := db.GetMovieByID(movie.ID); m.CanonicalPath != filepath.Dir(src) || !m.RadarrPathDirty {
+		t.Errorf("movie = %q dirty=%v, want %q dirty", m.CanonicalPath, m.RadarrPathDirty, filepath.Dir(src))
+	}
+	if len(syncer.queued) != 1 || syncer.queued[0] != "movie" {
+		t.Errorf("queued = %v, want one movie sync", syncer.queued)
+	}
+	if len(syncer.notified) != 1 || syncer.notified[0] != [2]string{dst, src} {
+		t.Errorf("notified = %v", syncer.notified)
+	}
+
+	op, _ := db.GetOperation(id)
+	if op.UndoneAt == nil || op.UndoBatch != res.Batch {
+		t.Errorf("operation not marked undone: %+v", op)
+	}
+	inverse, _ := db.ListOperationBatch(res.Batch)
+	if len(inverse) != 1 || inverse[0].BeforePath != dst || inverse[0].AfterPath != src {
+		t.Errorf("inverse journal = %+v", inverse)
+	}
+	if _, err := NewUndoer(db, nil, false).UndoOperation(id); err == nil || !strings.Contains(err.Error(), "already undone") {
+		t.Errorf("second undo error = %v, want already undone", err)
+	}
+}
+
+func TestUndoBatchReplaysNewestFirst(t *testing.T) {
+	db := openDB(t)
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a", "show.mkv")
+	b := filepath.Join(dir, "b", "show.mkv")
+	c := filepath.Join(dir, "c", "show.mkv")
+	writeFile(t, a)
+
+	first := move(t, db, "batch", a, b)
+	move(t, db, "batch", b, c)
+	if _, err := db.RecordOperation(database.JournalOp{BatchID: "batch", Source: database.OpSourceConsolidate, Kind: database.OpDelete, BeforePath: filepath.Join(dir, "dup.mkv")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewUndoer(db, nil, false).UndoOperation(first); err == nil || !strings.Contains(err.Error(), "undo that first") {
+		t.Fatalf("undoing the older move alone: err = %v, want refusal", err)
+	}
+
+	dry, err := NewUndoer(db, nil, true).UndoBatch("batch")
+	if err != nil || len(dry.Undone) != 2 || !exists(c) {
+		t.Fatalf("dry run = %+v, %v; file moved=%v", dry, err, !exists(c))
+	}
+
+	res, err := NewUndoer(db, nil, false).UndoBatch("batch")
+	if err != nil {
+		t.Fatalf("UndoBatch: %v", err)
+	}
+	if len(res.Undone) != 2 || len(res.Skipped) != 1 || res.Skipped[0].Op.Kind != database.OpDelete {
+		t.Fatalf("result = %+v", res)
+	}
+	if !exists(a) || exists(b) || exists(c) {
+		t.Errorf("after undo: a=%v b=%v c=%v, want only a", exists(a), exists(b), exists(c))
+	}
+}
+
+func TestUndoCopyRemovesOnlyTheCopy(t *testing.T) {
+	db := openDB(t)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "downloads", "Heat.mkv")
+	dst := filepath.Join(dir, "library", "Heat (1995)", "Heat (1995).mkv")
+	writeFile(t, src)
+	writeFile(t, dst)
+	id, err := db.RecordOperation(database.JournalOp{
+		BatchID: "copy", Source: database.OpSourceOrganize, Kind: database.OpCopy,
+		BeforePath: src, AfterPath: dst, Reversible: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewUndoer(db, nil, false).UndoOperation(id); err != nil {
+		t.Fatalf("UndoOperation: %v", err)
+	}
+	if !exists(src) || exists(dst) {
+		t.Errorf("src=%v dst=%v, want only the original", exists(src), exists(dst))
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"io/fs"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -68,6 +69,7 @@ func (s *Selector) SelectMovieLibrary(movieTitle string, year string, fileSize i
 	if len(s.libraries) == 0 {
 		return nil, fmt.Errorf("no libraries configured")
 	}
+	movieTitle, year = s.canonicalTitle(database.AliasMediaMovie, movieTitle, year)
 
 	// HOLDEN Phase 3: Check database first for existing movie
 	if s.db != nil && year != "" {
@@ -151,6 +153,7 @@ func (s *Selector) SelectTVShowLibrary(showName string, year string, fileSize in
 	if len(s.libraries) == 0 {
 		return nil, fmt.Errorf("no libraries configured")
 	}
+	showName, year = s.canonicalTitle(database.AliasMediaSeries, showName, year)
 
 	if len(s.libraries) == 1 {
 		return s.selectSingleLibrary(s.libraries[0], fileSize)
@@ -180,6 +183,23 @@ func (s *Selector) SelectTVShowLibrary(showName string, year string, fileSize in
 	return s.resolveMultipleLocations(showName, year, fileSize, matches)
 }
 
+// canonicalTitle maps a parsed title through the manual title aliases, so
+// a show parsed as "The Office" is placed next to "The Office (US)".
+// Only title aliases apply; the selector never sees the file path.
+func (s *Selector) canonicalTitle(mediaType, title, year string) (string, string) {
+	if s.db == nil {
+		return title, year
+	}
+	alias, err := s.db.MatchAlias(mediaType, "", title)
+	if err != nil || alias == nil {
+		return title, year
+	}
+	if alias.Year != nil {
+		year = strconv.Itoa(*alias.Year)
+	}
+	return alias.Title, year
+}
+
 // selectSingleLibrary handles the case where only one library is available
 func (s *Selector) selectSingleLibrary(lib string, fileSize int64) (*SelectionResult, error) {
 	available, err := getAvailableSpace(lib)
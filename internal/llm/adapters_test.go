@@ -0,0 +1,191 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var testSchema = json.RawMessage(`{"type":"object","properties":{"title":{"type":"string"}},"required":["title"]}`)
+
+func TestOpenAIAdapterCompleteWithSchema(t *testing.T) {
+	var got map[string]interface{}
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"model":"qwen","choices":[{"message":{"content":"{\"title\":\"Heat\"}"},"finish_reason":"stop"}],"usage":{"total_tokens":42}}`))
+	}))
+	defer srv.Close()
+
+	a := NewOpenAIAdapter("router", "OpenRouter", srv.URL+"/v1/", "sk-test", "qwen")
+	c, err := a.Complete(context.Background(), "Heat.1995.mkv", CompletionOptions{System: "parse", Schema: testSchema, SchemaName: "media"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Text != `{"title":"Heat"}` || c.UsedTokens != 42 || c.FinishReason != "stop" {
+		t.Fatalf("completion = %+v", c)
+	}
+	if auth != "Bearer sk-test" {
+		t.Errorf("Authorization = %q", auth)
+	}
+
+	messages := got["messages"].([]interface{})
+	if len(messages) != 2 || messages[0].(map[string]interface{})["role"] != "system" {
+		t.Errorf("messages = %v, want system then user", messages)
+	}
+	format := got["response_format"].(map[string]interface{})
+	schema := format["json_schema"].(map[string]interface{})
+	if format["type"] != "json_schema" || schema["name"] != "media" || schema["schema"] == nil {
+		t.Errorf("response_format = %v", format)
+	}
+}
+
+func TestOpenAIAdapterListModels(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[{"id":"qwen2.5-7b-instruct","owned_by":"organization_owner"}]}`))
+	}))
+	defer srv.Close()
+
+	a := NewLMStudioAdapter("lms", "LM Studio", srv.URL, "qwen2.5-7b-instruct")
+	if a.Type() != ProviderTypeLMStudio || !a.Capabilities().LocalOnly {
+		t.Errorf("type = %s local = %v", a.Type(), a.Capabilities().LocalOnly)
+	}
+	status, err := a.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Online || len(status.ModelList) != 1 || status.ModelList[0].ID != "qwen2.5-7b-instruct" {
+		t.Fatalf("status = %+v", status)
+	}
+}
+
+func TestAnthropicAdapterForcesSchemaTool(t *testing.T) {
+	var got map[string]interface{}
+	var key, version string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			http.NotFound(w, r)
+			return
+		}
+		key, version = r.Header.Get("x-api-key"), r.Header.Get("anthropic-version")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"model":"claude","content":[{"type":"text","text":"Sure."},{"type":"tool_use","name":"media","input":{"title":"Heat"}}],"stop_reason":"tool_use","usage":{"input_tokens":30,"output_tokens":12}}`))
+	}))
+	defer srv.Close()
+
+	a := NewAnthropicAdapter("claude", "Claude", srv.URL, "sk-ant", "claude")
+	c, err := a.Complete(context.Background(), "Heat.1995.mkv", CompletionOptions{System: "parse", Schema: testSchema, SchemaName: "media"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Text != `{"title":"Heat"}` || c.UsedTokens != 42 {
+		t.Fatalf("completion = %+v", c)
+	}
+	if key != "sk-ant" || version != anthropicVersion {
+		t.Errorf("x-api-key = %q anthropic-version = %q", key, version)
+	}
+	if got["system"] != "parse" || got["max_tokens"].(float64) != anthropicMaxTokens {
+		t.Errorf("request = %v", got)
+	}
+	choice := got["tool_choice"].(map[string]interface{})
+	if choice["type"] != "tool" || choice["name"] != "media" {
+		t.Errorf("tool_choice = %v", choice)
+	}
+}
+
+func TestRegistryCompleteFallsBack(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"model":"m","response":"ok","done":true}`))
+	}))
+	defer up.Close()
+
+	reg := NewProviderRegistry()
+	reg.Register(NewOpenAIAdapter("primary", "Primary", down.URL, "", "m"))
+	reg.Register(NewOllamaAdapter("backup", "Backup", up.URL, "m"))
+	if err := reg.SetFallbacks("backup"); err != nil {
+		t.Fatal(err)
+	}
+
+	c, p, err := reg.Complete(context.Background(), "hi", CompletionOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.ID() != "backup" || c.Text != "ok" {
+		t.Fatalf("provider = %s text = %q, want backup/ok", p.ID(), c.Text)
+	}
+
+	if err := reg.SetFallbacks(); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = reg.Complete(context.Background(), "hi", CompletionOptions{})
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusServiceUnavailable || httpErr.Provider != "primary" {
+		t.Fatalf("err = %v, want primary 503", err)
+	}
+
+	if err := reg.SetFallbacks("missing"); err == nil {
+		t.Fatal("SetFallbacks accepted an unknown provider")
+	}
+}
+
+func TestRegistryCompleteTimesOutEachProvider(t *testing.T) {
+	stuck := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-stuck:
+		}
+	}))
+	defer slow.Close()
+	defer close(stuck)
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"model":"m","response":"ok","done":true}`))
+	}))
+	defer up.Close()
+
+	reg := NewProviderRegistry()
+	reg.Register(NewOllamaAdapter("slow", "Slow", slow.URL, "m"))
+	reg.Register(NewOllamaAdapter("backup", "Backup", up.URL, "m"))
+	if err := reg.SetFallbacks("backup"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The slow provider uses up its own timeout, not the caller's context,
+	// so the fallback still runs.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c, p, err := reg.Complete(ctx, "hi", CompletionOptions{Timeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.ID() != "backup" || c.Text != "ok" {
+		t.Fatalf("provider = %s text = %q, want backup/ok", p.ID(), c.Text)
+	}
+}
+
+func TestLastErrorIsLastProviders(t *testing.T) {
+	first := &HTTPError{Provider: "primary", StatusCode: http.StatusUnauthorized}
+	last := &HTTPError{Provider: "backup", StatusCode: http.StatusServiceUnavailable}
+	err := errors.Join(errors.New("primary: "+first.Error()), last)
+
+	var httpErr *HTTPError
+	if !errors.As(LastError(err), &httpErr) || httpErr.Provider != "backup" {
+		t.Fatalf("LastError = %v, want backup's", LastError(err))
+	}
+	if single := errors.New("x"); LastError(single) != single {
+		t.Fatal("LastError changed an unjoined error")
+	}
+}
@@ -0,0 +1,201 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	anthropicVersion   = "2023-06-01"
+	anthropicMaxTokens = 1024
+)
+
+// AnthropicAdapter talks to the Anthropic messages API. Structured output
+// is requested by forcing a single tool whose input schema is the wanted
+// JSON schema; the tool input becomes the completion text.
+type AnthropicAdapter struct {
+	id       string
+	name     string
+	endpoint string
+	apiKey   string
+	model    string
+	client   *http.Client
+}
+
+func NewAnthropicAdapter(id, name, endpoint, apiKey, model string) *AnthropicAdapter {
+	if endpoint == "" {
+		endpoint = "https://api.anthropic.com"
+	}
+	return &AnthropicAdapter{
+		id:       id,
+		name:     name,
+		endpoint: strings.TrimRight(endpoint, "/"),
+		apiKey:   apiKey,
+		model:    model,
+		client: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+	}
+}
+
+func (a *AnthropicAdapter) ID() string         { return a.id }
+func (a *AnthropicAdapter) Type() ProviderType { return ProviderTypeAnthropic }
+func (a *AnthropicAdapter) Name() string       { return a.name }
+
+func (a *AnthropicAdapter) Info() ProviderInfo {
+	return ProviderInfo{
+		ID:           a.id,
+		Type:         a.Type(),
+		Name:         a.name,
+		Endpoint:     a.endpoint,
+		Enabled:      true,
+		CurrentModel: a.model,
+		Capabilities: a.Capabilities(),
+	}
+}
+
+func (a *AnthropicAdapter) headers() map[string]string {
+	return map[string]string{
+		"x-api-key":         a.apiKey,
+		"anthropic-version": anthropicVersion,
+	}
+}
+
+func (a *AnthropicAdapter) Ping(ctx context.Context) error {
+	_, err := a.ListModels(ctx)
+	return err
+}
+
+func (a *AnthropicAdapter) Status(ctx context.Context) (*ProviderStatus, error) {
+	status := &ProviderStatus{
+		Model: a.model,
+	}
+
+	models, err := a.ListModels(ctx)
+	if err != nil {
+		status.Online = false
+		status.Error = err.Error()
+		return status, nil
+	}
+
+	status.Online = true
+	status.ModelList = models
+
+	return status, nil
+}
+
+func (a *AnthropicAdapter) ListModels(ctx context.Context) ([]Model, error) {
+	var result struct {
+		Data []struct {
+			ID          string `json:"id"`
+			DisplayName string `json:"display_name"`
+		} `json:"data"`
+	}
+	if err := doJSON(ctx, a.client, a.id, http.MethodGet, a.endpoint+"/v1/models", a.headers(), nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to list models: %w", err)
+	}
+
+	models := make([]Model, len(result.Data))
+	for i, m := range result.Data {
+		name := m.DisplayName
+		if name == "" {
+			name = m.ID
+		}
+		models[i] = Model{
+			ID:   m.ID,
+			Name: name,
+		}
+	}
+
+	return models, nil
+}
+
+func (a *AnthropicAdapter) CurrentModel() string {
+	return a.model
+}
+
+func (a *AnthropicAdapter) SetModel(model string) error {
+	a.model = model
+	return nil
+}
+
+func (a *AnthropicAdapter) Complete(ctx context.Context, prompt string, opts CompletionOptions) (*Completion, error) {
+	maxTokens := opts.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = anthropicMaxTokens
+	}
+	reqBody := map[string]interface{}{
+		"model":      a.model,
+		"max_tokens": maxTokens,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}
+	if opts.System != "" {
+		reqBody["system"] = opts.System
+	}
+	if opts.Temperature > 0 {
+		reqBody["temperature"] = opts.Temperature
+	}
+	if opts.TopP > 0 {
+		reqBody["top_p"] = opts.TopP
+	}
+	if len(opts.Schema) > 0 {
+		name := schemaName(opts)
+		reqBody["tools"] = []map[string]interface{}{{
+			"name":         name,
+			"description":  "Record the response in the required structure.",
+			"input_schema": opts.Schema,
+		}}
+		reqBody["tool_choice"] = map[string]string{"type": "tool", "name": name}
+	}
+
+	var result struct {
+		Model   string `json:"model"`
+		Content []struct {
+			Type  string          `json:"type"`
+			Text  string          `json:"text"`
+			Input json.RawMessage `json:"input"`
+		} `json:"content"`
+		StopReason string `json:"stop_reason"`
+		Usage      struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	if err := doJSON(ctx, a.client, a.id, http.MethodPost, a.endpoint+"/v1/messages", a.headers(), reqBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to complete: %w", err)
+	}
+
+	completion := &Completion{
+		Model:        result.Model,
+		UsedTokens:   result.Usage.InputTokens + result.Usage.OutputTokens,
+		FinishReason: result.StopReason,
+	}
+	var text strings.Builder
+	for _, block := range result.Content {
+		if block.Type == "tool_use" && len(opts.Schema) > 0 {
+			completion.Text = string(block.Input)
+			return completion, nil
+		}
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	completion.Text = text.String()
+
+	return completion, nil
+}
+
+func (a *AnthropicAdapter) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{
+		SupportsStreaming:   true,
+		SupportsVision:      true,
+		SupportsModelSwitch: true,
+		LocalOnly:           false,
+	}
+}
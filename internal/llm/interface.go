@@ -2,6 +2,7 @@ package llm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 )
 
@@ -35,6 +36,7 @@ type LLMProvider interface {
 type ProviderRegistry struct {
 	providers       map[string]LLMProvider
 	defaultProvider string
+	fallbacks       []string
 }
 
 // NewProviderRegistry creates a new provider registry
@@ -72,6 +74,78 @@ func (r *ProviderRegistry) SetDefault(id string) error {
 	return nil
 }
 
+// SetFallbacks sets the providers tried, in order, when the default fails
+func (r *ProviderRegistry) SetFallbacks(ids ...string) error {
+	for _, id := range ids {
+		if _, ok := r.providers[id]; !ok {
+			return fmt.Errorf("provider %s not found", id)
+		}
+	}
+	r.fallbacks = append([]string(nil), ids...)
+	return nil
+}
+
+// Chain returns the default provider followed by the fallbacks, each once
+func (r *ProviderRegistry) Chain() []LLMProvider {
+	seen := make(map[string]bool)
+	var chain []LLMProvider
+	for _, id := range append([]string{r.defaultProvider}, r.fallbacks...) {
+		if p, ok := r.providers[id]; ok && !seen[id] {
+			seen[id] = true
+			chain = append(chain, p)
+		}
+	}
+	return chain
+}
+
+// Complete runs the prompt through Chain and returns the first successful
+// completion along with the provider that produced it. If every provider
+// fails, the errors are joined in chain order. With opts.Timeout set, each
+// provider gets its own deadline under ctx; the chain stops early only when
+// ctx itself is done.
+func (r *ProviderRegistry) Complete(ctx context.Context, prompt string, opts CompletionOptions) (*Completion, LLMProvider, error) {
+	chain := r.Chain()
+	if len(chain) == 0 {
+		return nil, nil, errors.New("no LLM provider registered")
+	}
+	var errs []error
+	for _, p := range chain {
+		c, err := completeWithTimeout(ctx, p, prompt, opts)
+		if err == nil {
+			return c, p, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", p.ID(), err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, nil, errors.Join(errs...)
+}
+
+func completeWithTimeout(ctx context.Context, p LLMProvider, prompt string, opts CompletionOptions) (*Completion, error) {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	return p.Complete(ctx, prompt, opts)
+}
+
+// LastError returns the error of the last provider tried when err comes
+// from ProviderRegistry.Complete, and err itself otherwise. That is the
+// error the chain ended on, so it is the one to classify.
+func LastError(err error) error {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return err
+	}
+	errs := joined.Unwrap()
+	if len(errs) == 0 {
+		return err
+	}
+	return errs[len(errs)-1]
+}
+
 // All returns all registered providers
 func (r *ProviderRegistry) All() []LLMProvider {
 	result := make([]LLMProvider, 0, len(r.providers))
@@ -1,7 +1,6 @@
 package llm
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -138,45 +137,43 @@ func (o *OllamaAdapter) Complete(ctx context.Context, prompt string, opts Comple
 		"prompt": prompt,
 		"stream": false,
 	}
+	if opts.System != "" {
+		reqBody["system"] = opts.System
+	}
+	if len(opts.Schema) > 0 {
+		reqBody["format"] = opts.Schema
+	}
 
+	options := map[string]interface{}{}
 	if opts.Temperature > 0 {
-		reqBody["options"] = map[string]interface{}{
-			"temperature": opts.Temperature,
-		}
+		options["temperature"] = opts.Temperature
 	}
-
-	body, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, err
+	if opts.TopP > 0 {
+		options["top_p"] = opts.TopP
 	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", o.endpoint+"/api/generate", bytes.NewReader(body))
-	if err != nil {
-		return nil, err
+	if opts.MaxTokens > 0 {
+		options["num_predict"] = opts.MaxTokens
 	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := o.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to complete: %w", err)
+	if len(options) > 0 {
+		reqBody["options"] = options
 	}
-	defer resp.Body.Close()
 
 	var result struct {
-		Response string `json:"response"`
-		Model    string `json:"model"`
-		Done     bool   `json:"done"`
-		Context  []int  `json:"context"`
+		Response   string `json:"response"`
+		Model      string `json:"model"`
+		Done       bool   `json:"done"`
+		DoneReason string `json:"done_reason"`
+		Context    []int  `json:"context"`
 	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := doJSON(ctx, o.client, "ollama", http.MethodPost, o.endpoint+"/api/generate", nil, reqBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to complete: %w", err)
 	}
 
 	return &Completion{
-		Text:       result.Response,
-		Model:      result.Model,
-		UsedTokens: len(result.Context),
+		Text:         result.Response,
+		Model:        result.Model,
+		UsedTokens:   len(result.Context),
+		FinishReason: result.DoneReason,
 	}, nil
 }
 
@@ -0,0 +1,213 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAIAdapter talks to any OpenAI-compatible chat completions API:
+// OpenAI itself, LM Studio, llama.cpp server, vLLM and OpenRouter. The
+// endpoint is the API base including the version, e.g.
+// "http://localhost:1234/v1" or "https://openrouter.ai/api/v1".
+type OpenAIAdapter struct {
+	id       string
+	name     string
+	typ      ProviderType
+	endpoint string
+	apiKey   string
+	model    string
+	client   *http.Client
+}
+
+func NewOpenAIAdapter(id, name, endpoint, apiKey, model string) *OpenAIAdapter {
+	if endpoint == "" {
+		endpoint = "https://api.openai.com/v1"
+	}
+	return &OpenAIAdapter{
+		id:       id,
+		name:     name,
+		typ:      ProviderTypeOpenAI,
+		endpoint: strings.TrimRight(endpoint, "/"),
+		apiKey:   apiKey,
+		model:    model,
+		client: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+	}
+}
+
+// NewLMStudioAdapter is an OpenAIAdapter for a local LM Studio server.
+func NewLMStudioAdapter(id, name, endpoint, model string) *OpenAIAdapter {
+	if endpoint == "" {
+		endpoint = "http://localhost:1234/v1"
+	}
+	a := NewOpenAIAdapter(id, name, endpoint, "", model)
+	a.typ = ProviderTypeLMStudio
+	return a
+}
+
+func (o *OpenAIAdapter) ID() string         { return o.id }
+func (o *OpenAIAdapter) Type() ProviderType { return o.typ }
+func (o *OpenAIAdapter) Name() string       { return o.name }
+
+func (o *OpenAIAdapter) Info() ProviderInfo {
+	return ProviderInfo{
+		ID:           o.id,
+		Type:         o.typ,
+		Name:         o.name,
+		Endpoint:     o.endpoint,
+		Enabled:      true,
+		CurrentModel: o.model,
+		Capabilities: o.Capabilities(),
+	}
+}
+
+func (o *OpenAIAdapter) headers() map[string]string {
+	if o.apiKey == "" {
+		return nil
+	}
+	return map[string]string{"Authorization": "Bearer " + o.apiKey}
+}
+
+func (o *OpenAIAdapter) Ping(ctx context.Context) error {
+	_, err := o.ListModels(ctx)
+	return err
+}
+
+func (o *OpenAIAdapter) Status(ctx context.Context) (*ProviderStatus, error) {
+	status := &ProviderStatus{
+		Model: o.model,
+	}
+
+	models, err := o.ListModels(ctx)
+	if err != nil {
+		status.Online = false
+		status.Error = err.Error()
+		return status, nil
+	}
+
+	status.Online = true
+	status.ModelList = models
+
+	return status, nil
+}
+
+func (o *OpenAIAdapter) ListModels(ctx context.Context) ([]Model, error) {
+	var result struct {
+		Data []struct {
+			ID      string `json:"id"`
+			OwnedBy string `json:"owned_by"`
+		} `json:"data"`
+	}
+	if err := doJSON(ctx, o.client, o.id, http.MethodGet, o.endpoint+"/models", o.headers(), nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to list models: %w", err)
+	}
+
+	models := make([]Model, len(result.Data))
+	for i, m := range result.Data {
+		models[i] = Model{
+			ID:     m.ID,
+			Name:   m.ID,
+			Family: m.OwnedBy,
+		}
+	}
+
+	return models, nil
+}
+
+func (o *OpenAIAdapter) CurrentModel() string {
+	return o.model
+}
+
+func (o *OpenAIAdapter) SetModel(model string) error {
+	o.model = model
+	return nil
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+func (o *OpenAIAdapter) Complete(ctx context.Context, prompt string, opts CompletionOptions) (*Completion, error) {
+	var messages []openAIMessage
+	if opts.System != "" {
+		messages = append(messages, openAIMessage{Role: "system", Content: opts.System})
+	}
+	messages = append(messages, openAIMessage{Role: "user", Content: prompt})
+
+	reqBody := map[string]interface{}{
+		"model":    o.model,
+		"messages": messages,
+		"stream":   false,
+	}
+	if opts.Temperature > 0 {
+		reqBody["temperature"] = opts.Temperature
+	}
+	if opts.TopP > 0 {
+		reqBody["top_p"] = opts.TopP
+	}
+	if opts.MaxTokens > 0 {
+		reqBody["max_tokens"] = opts.MaxTokens
+	}
+	if len(opts.Schema) > 0 {
+		reqBody["response_format"] = map[string]interface{}{
+			"type": "json_schema",
+			"json_schema": map[string]interface{}{
+				"name":   schemaName(opts),
+				"schema": opts.Schema,
+				"strict": true,
+			},
+		}
+	}
+
+	var result struct {
+		Model   string `json:"model"`
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+				Refusal string `json:"refusal"`
+			} `json:"message"`
+			FinishReason string `json:"finish_reason"`
+		} `json:"choices"`
+		Usage struct {
+			TotalTokens int `json:"total_tokens"`
+		} `json:"usage"`
+	}
+	if err := doJSON(ctx, o.client, o.id, http.MethodPost, o.endpoint+"/chat/completions", o.headers(), reqBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to complete: %w", err)
+	}
+	if len(result.Choices) == 0 {
+		return nil, fmt.Errorf("%s returned no choices", o.id)
+	}
+	choice := result.Choices[0]
+	if choice.Message.Refusal != "" {
+		return nil, fmt.Errorf("%s refused: %s", o.id, choice.Message.Refusal)
+	}
+
+	return &Completion{
+		Text:         choice.Message.Content,
+		Model:        result.Model,
+		UsedTokens:   result.Usage.TotalTokens,
+		FinishReason: choice.FinishReason,
+	}, nil
+}
+
+func (o *OpenAIAdapter) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{
+		SupportsStreaming:   true,
+		SupportsVision:      false,
+		SupportsModelSwitch: true,
+		LocalOnly:           o.typ == ProviderTypeLMStudio,
+	}
+}
+
+func schemaName(opts CompletionOptions) string {
+	if opts.SchemaName != "" {
+		return opts.SchemaName
+	}
+	return "response"
+}
@@ -1,5 +1,11 @@
 package llm
 
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
 // ProviderType represents different LLM provider implementations
 type ProviderType string
 
@@ -34,6 +40,20 @@ type CompletionOptions struct {
 	MaxTokens   int     `json:"maxTokens,omitempty"`
 	TopP        float64 `json:"topP,omitempty"`
 	Stream      bool    `json:"stream,omitempty"`
+
+	// System is sent as the system prompt, separate from the user prompt.
+	System string `json:"system,omitempty"`
+
+	// Schema is a JSON schema the response must match. When set, providers
+	// use their structured-output mode and Completion.Text holds the JSON
+	// document. SchemaName labels it where the API requires a name.
+	Schema     json.RawMessage `json:"schema,omitempty"`
+	SchemaName string          `json:"schemaName,omitempty"`
+
+	// Timeout bounds each provider's attempt when the prompt runs through a
+	// registry's chain, so a slow provider leaves time for its fallbacks.
+	// Zero leaves only the caller's context.
+	Timeout time.Duration `json:"-"`
 }
 
 // Completion represents an LLM response
@@ -82,3 +102,14 @@ type AuditSuggestion struct {
 	Status         string  `json:"status"`
 	CreatedAt      string  `json:"createdAt"`
 }
+
+// HTTPError is a non-2xx response from a provider's API.
+type HTTPError struct {
+	Provider   string
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s returned %d: %s", e.Provider, e.StatusCode, e.Body)
+}
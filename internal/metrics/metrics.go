@@ -0,0 +1,326 @@
+// Package metrics exposes counters, histograms and scrape-time gauges in
+// the Prometheus text exposition format (version 0.0.4), which Prometheus,
+// VictoriaMetrics and Grafana Agent all scrape.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Collector writes metric families when the registry is scraped.
+type Collector interface {
+	Collect(e *Encoder)
+}
+
+// CollectorFunc adapts a function to Collector.
+type CollectorFunc func(e *Encoder)
+
+func (f CollectorFunc) Collect(e *Encoder) { f(e) }
+
+// Registry holds everything one /metrics endpoint serves.
+type Registry struct {
+	mu         sync.Mutex
+	names      map[string]bool
+	collectors []Collector
+}
+
+// Default is the registry instrumented packages record into.
+var Default = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+// MustRegister adds collectors, scraped in registration order.
+func (r *Registry) MustRegister(cs ...Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, cs...)
+}
+
+func (r *Registry) claim(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[name] {
+		panic("metrics: duplicate metric " + name)
+	}
+	r.names[name] = true
+}
+
+// Collect writes every registered collector, so one registry can serve
+// another's metrics alongside its own.
+func (r *Registry) Collect(e *Encoder) {
+	r.mu.Lock()
+	collectors := append([]Collector(nil), r.collectors...)
+	r.mu.Unlock()
+	for _, c := range collectors {
+		c.Collect(e)
+	}
+}
+
+// WriteText writes every registered family in text format.
+func (r *Registry) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	e := &Encoder{w: bw}
+	r.Collect(e)
+	if e.err != nil {
+		return e.err
+	}
+	return bw.Flush()
+}
+
+// ContentType is the media type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Handler serves the registry.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_ = r.WriteText(w)
+	})
+}
+
+// Encoder writes families and samples. Labels are passed as alternating
+// name, value pairs.
+type Encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+// Family starts a metric family: its HELP and TYPE lines.
+func (e *Encoder) Family(name, help, typ string) {
+	e.printf("# HELP %s %s\n# TYPE %s %s\n", name, escapeHelp(help), name, typ)
+}
+
+// Sample writes one sample of the current family.
+func (e *Encoder) Sample(name string, value float64, labels ...string) {
+	e.printf("%s%s %s\n", name, formatLabels(labels), formatValue(value))
+}
+
+// Gauge writes a single-sample gauge family.
+func (e *Encoder) Gauge(name, help string, value float64, labels ...string) {
+	e.Family(name, help, "gauge")
+	e.Sample(name, value, labels...)
+}
+
+func (e *Encoder) printf(format string, args ...any) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = fmt.Fprintf(e.w, format, args...)
+}
+
+func formatLabels(pairs []string) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i])
+		b.WriteString(`="`)
+		b.WriteString(escapeLabel(pairs[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// vec keeps one series per combination of label values.
+type vec[T any] struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	series map[string]*T
+	values map[string][]string
+}
+
+func newVec[T any](name, help string, labels []string) vec[T] {
+	return vec[T]{
+		name:   name,
+		help:   help,
+		labels: labels,
+		series: make(map[string]*T),
+		values: make(map[string][]string),
+	}
+}
+
+// get returns the series for values, creating it with create. Callers
+// hold v.mu.
+func (v *vec[T]) get(values []string, create func() *T) *T {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s wants %d label values, got %d", v.name, len(v.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	s, ok := v.series[key]
+	if !ok {
+		s = create()
+		v.series[key] = s
+		v.values[key] = append([]string(nil), values...)
+	}
+	return s
+}
+
+// sortedKeys returns series keys in label-value order. Callers hold v.mu.
+func (v *vec[T]) sortedKeys() []string {
+	keys := make([]string, 0, len(v.series))
+	for k := range v.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (v *vec[T]) pairs(key string, extra ...string) []string {
+	values := v.values[key]
+	out := make([]string, 0, 2*len(values)+len(extra))
+	for i, name := range v.labels {
+		out = append(out, name, values[i])
+	}
+	return append(out, extra...)
+}
+
+// CounterVec is a monotonically increasing value per label combination.
+type CounterVec struct {
+	vec[float64]
+}
+
+// NewCounterVec creates a counter family and registers it.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	r.claim(name)
+	c := &CounterVec{newVec[float64](name, help, labels)}
+	r.MustRegister(c)
+	return c
+}
+
+// Add increases the series for labelValues by delta, which must not be
+// negative.
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	*c.get(labelValues, func() *float64 { return new(float64) }) += delta
+}
+
+// Inc adds one.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Value returns the current value of a series.
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s, ok := c.series[strings.Join(labelValues, "\xff")]; ok {
+		return *s
+	}
+	return 0
+}
+
+func (c *CounterVec) Collect(e *Encoder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e.Family(c.name, c.help, "counter")
+	for _, key := range c.sortedKeys() {
+		e.Sample(c.name, *c.series[key], c.pairs(key)...)
+	}
+}
+
+// HistogramVec counts observations into cumulative buckets per label
+// combination.
+type HistogramVec struct {
+	vec[histogram]
+	buckets []float64
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewHistogramVec creates a histogram family with the given upper bounds
+// and registers it.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	r.claim(name)
+	b := append([]float64(nil), buckets...)
+	sort.Float64s(b)
+	h := &HistogramVec{vec: newVec[histogram](name, help, labels), buckets: b}
+	r.MustRegister(h)
+	return h
+}
+
+// Observe records v in the series for labelValues.
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := h.get(labelValues, func() *histogram { return &histogram{counts: make([]uint64, len(h.buckets))} })
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += v
+}
+
+func (h *HistogramVec) Collect(e *Encoder) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	e.Family(h.name, h.help, "histogram")
+	for _, key := range h.sortedKeys() {
+		s := h.series[key]
+		var cumulative uint64
+		for i, le := range h.buckets {
+			cumulative += s.counts[i]
+			e.Sample(h.name+"_bucket", float64(cumulative), h.pairs(key, "le", formatValue(le))...)
+		}
+		e.Sample(h.name+"_bucket", float64(s.count), h.pairs(key, "le", "+Inf")...)
+		e.Sample(h.name+"_sum", s.sum, h.pairs(key)...)
+		e.Sample(h.name+"_count", float64(s.count), h.pairs(key)...)
+	}
+}
+
+// ExponentialBuckets returns count upper bounds starting at start, each
+// factor times the previous.
+func ExponentialBuckets(start, factor float64, count int) []float64 {
+	out := make([]float64, count)
+	for i := range out {
+		out[i] = start
+		start *= factor
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistryWritesTextFormat(t *testing.T) {
+	r := NewRegistry()
+	organized := r.NewCounterVec("test_organized_total", "Files organized.", "media_type", "outcome")
+	durations := r.NewHistogramVec("test_duration_seconds", "Transfer time.", []float64{10, 1}, "backend")
+	r.MustRegister(CollectorFunc(func(e *Encoder) {
+		e.Gauge("test_locks", "Active locks.", 3)
+	}))
+
+	organized.Inc("tv", "success")
+	organized.Add(2, "movie", "failed")
+	organized.Add(-1, "movie", "failed")
+	durations.Observe(0.5, "rsync")
+	durations.Observe(5, "rsync")
+	durations.Observe(50, `we"ird`)
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	want := `# HELP test_organized_total Files organized.
+# TYPE test_organized_total counter
+test_organized_total{media_type="movie",outcome="failed"} 2
+test_organized_total{media_type="tv",outcome="success"} 1
+# HELP test_duration_seconds Transfer time.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{backend="rsync",le="1"} 1
+test_duration_seconds_bucket{backend="rsync",le="10"} 2
+test_duration_seconds_bucket{backend="rsync",le="+Inf"} 2
+test_duration_seconds_sum{backend="rsync"} 5.5
+test_duration_seconds_count{backend="rsync"} 2
+test_duration_seconds_bucket{backend="we\"ird",le="1"} 0
+test_duration_seconds_bucket{backend="we\"ird",le="10"} 0
+test_duration_seconds_bucket{backend="we\"ird",le="+Inf"} 1
+test_duration_seconds_sum{backend="we\"ird"} 50
+test_duration_seconds_count{backend="we\"ird"} 1
+# HELP test_locks Active locks.
+# TYPE test_locks gauge
+test_locks 3
+`
+	if got := rec.Body.String(); got != want {
+		t.Fatalf("output:\n%s\nwant:\n%s", got, want)
+	}
+	if v := organized.Value("movie", "failed"); v != 2 {
+		t.Errorf("Value() = %v, want 2", v)
+	}
+}
+
+func TestRegistryRejectsDuplicateNames(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounterVec("dup_total", "x")
+	defer func() {
+		if p := recover(); p == nil || !strings.Contains(p.(string), "dup_total") {
+			t.Fatalf("recover() = %v, want duplicate panic", p)
+		}
+	}()
+	r.NewCounterVec("dup_total", "x")
+}
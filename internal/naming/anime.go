@@ -0,0 +1,168 @@
+package naming
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// AnimeInfo is the result of parsing a fansub-style anime release such as
+// "[Group] Show Title - 123v2 [1080p][ABCD1234].mkv". The embedded
+// TVShowInfo carries the episode numbering: for absolute releases
+// AbsoluteEpisode is set and Season/Episode hold the season 1 placeholder
+// until the absolute number is mapped (see organizer's absolute resolver).
+type AnimeInfo struct {
+	TVShowInfo
+	Group   string // fansub group from the leading [Group] tag
+	CRC32   string // upper-cased CRC32 checksum tag, if present
+	Version int    // release revision from a v2/v3 suffix, 0 when absent
+}
+
+var (
+	animeGroupRegex = regexp.MustCompile(`^\s*\[([^\]]+)\]\s*`)
+	animeCRCRegex   = regexp.MustCompile(`[\[(]([0-9A-Fa-f]{8})[\])]`)
+	// " - 123", " - 12v2", " - 01-02": the dash-separated episode number
+	// fansub groups use, followed by a bracket, paren, space or the end.
+	animeEpisodeRegex = regexp.MustCompile(`\s-\s(\d{1,4})(?:-(\d{1,4}))?(?:[vV](\d))?(?:\s|[\[(]|$)`)
+	// Season markers fansubs put in the title ("Show S2 - 05", "Show 2nd
+	// Season - 05"), which make the number season-relative.
+	animeSeasonSuffixRegex = regexp.MustCompile(`(?i)\s(?:S(\d{1,2})|Season\s?(\d{1,2})|(\d{1,2})(?:st|nd|rd|th)\sSeason)$`)
+	animeTrailingTagRegex  = regexp.MustCompile(`[\[(][^\])]*[\])]`)
+)
+
+// IsAnimeFilename reports whether filename looks like a fansub anime release:
+// a leading [Group] tag followed by a dash-separated episode number.
+func IsAnimeFilename(filename string) bool {
+	baseName := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	if !animeGroupRegex.MatchString(baseName) {
+		return false
+	}
+	_, err := parseAnimeFromBaseName(baseName, filename)
+	return err == nil
+}
+
+// ParseAnimeName parses a fansub-style anime filename. Unlike
+// IsAnimeFilename it does not require the leading group tag, so it can be
+// used for files in libraries flagged as anime ("Show - 123.mkv"). Names
+// carrying an SxxEyy marker are parsed by ParseTVShowName and wrapped.
+func ParseAnimeName(filename string) (*AnimeInfo, error) {
+	baseName := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	info, err := parseAnimeFromBaseName(baseName, filename)
+	if err == nil {
+		return info, nil
+	}
+	tv, tvErr := ParseTVShowName(filename)
+	if tvErr != nil {
+		return nil, err
+	}
+	return &AnimeInfo{
+		TVShowInfo: *tv,
+		Group:      animeGroup(baseName),
+		CRC32:      animeCRC(baseName),
+	}, nil
+}
+
+func parseAnimeFromBaseName(baseName, filename string) (*AnimeInfo, error) {
+	s := strings.ReplaceAll(baseName, "_", " ")
+	info := &AnimeInfo{
+		Group: animeGroup(s),
+		CRC32: animeCRC(s),
+	}
+	s = animeGroupRegex.ReplaceAllString(s, "")
+
+	m := animeEpisodeRegex.FindStringSubmatchIndex(s)
+	if m == nil {
+		// "Show EP123" and friends are absolute numbers too.
+		if match := findEpisodeMatch(s); match.found && match.kind == "absolute" {
+			title := cleanAnimeTitle(s[:match.loc[0]])
+			if title == "" {
+				return nil, fmt.Errorf("%w: could not extract anime title from: %s", ErrParseFailed, filename)
+			}
+			info.TVShowInfo = TVShowInfo{
+				Title:           title,
+				Season:          1,
+				Episode:         match.episode,
+				AbsoluteEpisode: match.episode,
+			}
+			return info, nil
+		}
+		return nil, fmt.Errorf("%w: no anime episode number found in: %s", ErrParseFailed, filename)
+	}
+
+	number, _ := strconv.Atoi(s[m[2]:m[3]])
+	if number <= 0 || isYearNumber(s[m[2]:m[3]]) {
+		return nil, fmt.Errorf("%w: no anime episode number found in: %s", ErrParseFailed, filename)
+	}
+	end := 0
+	if m[4] >= 0 {
+		last, _ := strconv.Atoi(s[m[4]:m[5]])
+		end = validEpisodeEnd(number, last)
+	}
+	if m[6] >= 0 {
+		info.Version, _ = strconv.Atoi(s[m[6]:m[7]])
+	}
+
+	titlePart := strings.TrimSpace(s[:m[0]])
+	year := extractYear(titlePart)
+	if year != "" {
+		titlePart = strings.TrimSpace(removeYear(" "+titlePart+" ", year))
+	}
+
+	season := 0
+	if sm := animeSeasonSuffixRegex.FindStringSubmatch(titlePart); sm != nil {
+		for _, g := range sm[1:] {
+			if g != "" {
+				season, _ = strconv.Atoi(g)
+				break
+			}
+		}
+		titlePart = titlePart[:len(titlePart)-len(sm[0])]
+	}
+
+	title := cleanAnimeTitle(titlePart)
+	if title == "" {
+		return nil, fmt.Errorf("%w: could not extract anime title from: %s", ErrParseFailed, filename)
+	}
+
+	info.TVShowInfo = TVShowInfo{
+		Title:      title,
+		Year:       year,
+		Season:     season,
+		Episode:    number,
+		EpisodeEnd: end,
+	}
+	if season == 0 {
+		// Absolute numbering: park it in season 1 until it is mapped.
+		info.Season = 1
+		info.AbsoluteEpisode = number
+	}
+	return info, nil
+}
+
+func cleanAnimeTitle(s string) string {
+	s = animeTrailingTagRegex.ReplaceAllString(s, " ")
+	s = strings.TrimRight(strings.TrimSpace(s), "-")
+	return strings.TrimSpace(normalizeSpaces(s))
+}
+
+func animeGroup(s string) string {
+	if m := animeGroupRegex.FindStringSubmatch(s); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+	return ""
+}
+
+func animeCRC(s string) string {
+	matches := animeCRCRegex.FindAllStringSubmatch(s, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+	// The checksum is conventionally the last bracketed tag.
+	return strings.ToUpper(matches[len(matches)-1][1])
+}
+
+func isYearNumber(s string) bool {
+	return len(s) == 4 && (strings.HasPrefix(s, "19") || strings.HasPrefix(s, "20"))
+}
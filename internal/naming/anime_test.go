@@ -0,0 +1,121 @@
+package naming
+
+import "testing"
+
+func TestParseAnimeName(t *testing.T) {
+	tests := []struct {
+		input       string
+		wantTitle   string
+		wantSeason  int
+		wantEpisode int
+		wantEnd     int
+		wantAbs     int
+		wantGroup   string
+		wantCRC     string
+		wantVersion int
+	}{
+		{"[SubsPlease] Frieren - 123 [1080p].mkv", "Frieren", 1, 123, 0, 123, "SubsPlease", "", 0},
+		{"[Erai-raws] One Piece - 1071v2 [1080p][ABCD1234].mkv", "One Piece", 1, 1071, 0, 1071, "Erai-raws", "ABCD1234", 2},
+		{"[Group] Show Title - 05 (1920x1080 HEVC) [deadbeef].mkv", "Show Title", 1, 5, 0, 5, "Group", "DEADBEEF", 0},
+		{"[Group]_Show_Title_-_12_[720p].mkv", "Show Title", 1, 12, 0, 12, "Group", "", 0},
+		{"[Group] Show - 01-02 [1080p].mkv", "Show", 1, 1, 2, 1, "Group", "", 0},
+		// Season marker in the title makes the number season-relative.
+		{"[SubsPlease] Oshi no Ko S2 - 05 (1080p) [0A1B2C3D].mkv", "Oshi no Ko", 2, 5, 0, 0, "SubsPlease", "0A1B2C3D", 0},
+		{"[Group] Show 2nd Season - 03 [1080p].mkv", "Show", 2, 3, 0, 0, "Group", "", 0},
+		// No group tag: accepted by ParseAnimeName for anime libraries.
+		{"Show - 045.mkv", "Show", 1, 45, 0, 45, "", "", 0},
+		{"Show EP123 1080p.mkv", "Show", 1, 123, 0, 123, "", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseAnimeName(tt.input)
+			if err != nil {
+				t.Fatalf("ParseAnimeName() error = %v", err)
+			}
+			if got.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", got.Title, tt.wantTitle)
+			}
+			if got.Season != tt.wantSeason || got.Episode != tt.wantEpisode || got.EpisodeEnd != tt.wantEnd {
+				t.Errorf("S%d E%d-E%d, want S%d E%d-E%d", got.Season, got.Episode, got.EpisodeEnd, tt.wantSeason, tt.wantEpisode, tt.wantEnd)
+			}
+			if got.AbsoluteEpisode != tt.wantAbs {
+				t.Errorf("AbsoluteEpisode = %d, want %d", got.AbsoluteEpisode, tt.wantAbs)
+			}
+			if got.Group != tt.wantGroup || got.CRC32 != tt.wantCRC || got.Version != tt.wantVersion {
+				t.Errorf("group/crc/version = %q/%q/%d, want %q/%q/%d", got.Group, got.CRC32, got.Version, tt.wantGroup, tt.wantCRC, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestParseAnimeName_Rejects(t *testing.T) {
+	for _, input := range []string{
+		"[Group] Some Movie - 2019 [1080p].mkv",
+		"[Group] Show Title [1080p].mkv",
+	} {
+		if _, err := ParseAnimeName(input); err == nil {
+			t.Errorf("ParseAnimeName(%q) expected error", input)
+		}
+	}
+}
+
+func TestParseTVShowName_FansubFallsBackToAnime(t *testing.T) {
+	got, err := ParseTVShowName("[SubsPlease] Frieren - 28 (1080p) [1920x1080].mkv")
+	if err != nil {
+		t.Fatalf("ParseTVShowName() error = %v", err)
+	}
+	if got.Title != "Frieren" || got.AbsoluteEpisode != 28 {
+		t.Errorf("got %q abs=%d, want Frieren abs=28", got.Title, got.AbsoluteEpisode)
+	}
+	if !IsTVEpisodeFilename("[SubsPlease] Frieren - 28 (1080p).mkv") {
+		t.Error("fansub release should be detected as a TV episode")
+	}
+
+	// An explicit SxxEyy marker still wins over the anime path.
+	got, err = ParseTVShowName("[Group] Show S02E05 [1080p].mkv")
+	if err != nil {
+		t.Fatalf("ParseTVShowName() error = %v", err)
+	}
+	if got.Season != 2 || got.Episode != 5 || got.AbsoluteEpisode != 0 {
+		t.Errorf("got S%dE%d abs=%d, want S2E5 abs=0", got.Season, got.Episode, got.AbsoluteEpisode)
+	}
+}
+
+func TestIsTVEpisodeFilename_AnimeWithoutGroup(t *testing.T) {
+	for _, input := range []string{
+		"Show - 123.mkv",
+		"Frieren - 28 (1080p).mkv",
+		"One Piece - 1071v2 [ABCD1234].mkv",
+	} {
+		if !IsTVEpisodeFilename(input) {
+			t.Errorf("IsTVEpisodeFilename(%q) = false, want true", input)
+		}
+		if !IsTVEpisodeFromPath("/downloads/"+input, SourceUnknown) {
+			t.Errorf("IsTVEpisodeFromPath(%q) = false, want true", input)
+		}
+		// Classification and parsing must agree.
+		if _, err := ParseTVShowName(input); err != nil {
+			t.Errorf("ParseTVShowName(%q) error = %v", input, err)
+		}
+	}
+
+	got, err := ParseTVShowName("Show - 123.mkv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "Show" || got.AbsoluteEpisode != 123 {
+		t.Errorf("got %q abs=%d, want Show abs=123", got.Title, got.AbsoluteEpisode)
+	}
+
+	// A year after the dash is not an episode number.
+	for _, input := range []string{
+		"Some Movie - 2019.mkv",
+		"Blade Runner - 2049 (2017).mkv",
+		"Movie (2001) - 2160p.mkv",
+	} {
+		if IsTVEpisodeFilename(input) {
+			t.Errorf("IsTVEpisodeFilename(%q) = true, want false", input)
+		}
+	}
+}
@@ -257,6 +257,9 @@ func ParseMovieFromPathVerbose(path string) (*MovieInfo, []string, error) {
 		if !IsTVEpisodeFilename(folderName) {
 			info, tokens, err := ParseMovieNameVerbose(folderName)
 			if err == nil && isUsableMovieFolderCandidate(folderName, info) {
+				if part := ParseStackPart(filename); part > 0 {
+					info.Part = part
+				}
 				return info, tokens, nil
 			}
 		}
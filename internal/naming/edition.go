@@ -0,0 +1,126 @@
+package naming
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// movieEditions lists the editions kept as separate versions of a movie,
+// in the order their labels are joined when a release carries several.
+// Labels are the Jellyfin multi-version suffixes: "Movie (2001) - Label".
+var movieEditions = []struct {
+	label string
+	re    *regexp.Regexp
+}{
+	{"Director's Cut", regexp.MustCompile(`(?i)\bdirector'?s[ ._-]+cut\b`)},
+	{"Extended", regexp.MustCompile(`(?i)\bextended(?:[ ._-]+(?:edition|cut|version))?\b`)},
+	{"Unrated", regexp.MustCompile(`(?i)\bunrated(?:[ ._-]+(?:edition|cut|version))?\b`)},
+	{"IMAX", regexp.MustCompile(`(?i)\bimax(?:[ ._-]+(?:enhanced|edition))?\b`)},
+	{"Criterion", regexp.MustCompile(`(?i)\bcriterion(?:[ ._-]+(?:collection|edition))?\b`)},
+	{"Remastered", regexp.MustCompile(`(?i)\bremaster(?:ed)?\b`)},
+}
+
+// ParseMovieEdition returns the edition label of a movie release
+// ("Director's Cut", "Extended IMAX"), or "" for the standard cut.
+// Markers are only looked for after the title so films named "Unrated" or
+// "Remastered" are not mistaken for editions.
+func ParseMovieEdition(filename string) string {
+	baseName := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	tail := editionSearchTail(baseName)
+
+	var labels []string
+	for _, ed := range movieEditions {
+		if ed.re.MatchString(tail) {
+			labels = append(labels, ed.label)
+		}
+	}
+	return strings.Join(labels, " ")
+}
+
+// editionSearchTail returns the part of baseName that may carry edition
+// markers: everything after the release year, or after the first word when
+// there is no year.
+func editionSearchTail(baseName string) string {
+	if loc := yearRegex.FindStringIndex(baseName); loc != nil && loc[0] > 0 {
+		return baseName[loc[1]:]
+	}
+	if i := strings.IndexAny(baseName, " ._-"); i > 0 {
+		return baseName[i:]
+	}
+	return ""
+}
+
+// stripMovieEdition removes edition markers following the title so they
+// don't leak into it ("Movie (2001) - Director's Cut" → "Movie (2001)").
+func stripMovieEdition(baseName string) string {
+	tail := editionSearchTail(baseName)
+	head := baseName[:len(baseName)-len(tail)]
+	for _, ed := range movieEditions {
+		tail = ed.re.ReplaceAllString(tail, " ")
+	}
+	out := strings.TrimSpace(head + tail)
+	return strings.TrimSpace(strings.TrimRight(out, " -"))
+}
+
+// resolutionVersionRegex matches resolution labels Jellyfin users put on
+// multi-version files ("Movie (2001) - 2160p.mkv").
+var resolutionVersionRegex = regexp.MustCompile(`(?i)^(?:\d{3,4}[pi]|4K|8K|UHD)$`)
+
+// MovieVersionLabel returns the " - Label" multi-version suffix of an
+// organized movie file named after title/year, or "" if there is none.
+// A stacking label ("- part1") is not a version and is left out.
+func MovieVersionLabel(filename, title, year string) string {
+	baseName := StripStackPart(filename)
+	prefix := NormalizeMediaName(title, year) + " - "
+	if !strings.HasPrefix(baseName, prefix) {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(baseName, prefix))
+}
+
+// IsResolutionVersionLabel reports whether a multi-version label names a
+// resolution ("2160p", "4K") rather than an edition.
+func IsResolutionVersionLabel(label string) bool {
+	return resolutionVersionRegex.MatchString(strings.TrimSpace(label))
+}
+
+// ParseResolutionVersion returns the resolution label of a multi-version
+// movie file ("Movie (2001) - 2160p.mkv" → "2160p"), or "" if it has none.
+func ParseResolutionVersion(filename string) string {
+	baseName := StripStackPart(filename)
+	i := strings.LastIndex(baseName, " - ")
+	if i < 0 {
+		return ""
+	}
+	label := strings.TrimSpace(baseName[i+3:])
+	if !IsResolutionVersionLabel(label) {
+		return ""
+	}
+	return label
+}
+
+// ParseMovieVersion returns the label a movie file is kept under as a
+// separate version: its edition, else its resolution label, else "" for
+// the standard cut.
+func ParseMovieVersion(filename string) string {
+	if edition := ParseMovieEdition(filename); edition != "" {
+		return edition
+	}
+	return ParseResolutionVersion(filename)
+}
+
+// FormatMovieVersionFilename formats a movie file name with Jellyfin's
+// multi-version suffix: "Movie (2001) - Director's Cut.mkv". An empty
+// version yields the plain FormatMovieFilename form.
+func FormatMovieVersionFilename(title, year, version, ext string) string {
+	version = strings.TrimSpace(version)
+	if version == "" {
+		return FormatMovieFilename(title, year, ext)
+	}
+	if year != "" {
+		return fmt.Sprintf("%s (%s) - %s.%s", title, year, version, ext)
+	}
+	return fmt.Sprintf("%s - %s.%s", title, version, ext)
+}
@@ -0,0 +1,65 @@
+package naming
+
+import "testing"
+
+func TestParseMovieEdition(t *testing.T) {
+	tests := []struct {
+		input       string
+		wantTitle   string
+		wantYear    string
+		wantEdition string
+	}{
+		{"Blade.Runner.1982.Directors.Cut.1080p.BluRay.x264.mkv", "Blade Runner", "1982", "Director's Cut"},
+		{"Blade Runner (1982) - Director's Cut.mkv", "Blade Runner", "1982", "Director's Cut"},
+		{"Aliens.1986.Extended.Edition.2160p.UHD.BluRay.mkv", "Aliens", "1986", "Extended"},
+		{"The.Dark.Knight.2008.IMAX.1080p.WEB-DL.mkv", "The Dark Knight", "2008", "IMAX"},
+		{"Seven.Samurai.1954.Criterion.Collection.1080p.BluRay.mkv", "Seven Samurai", "1954", "Criterion"},
+		{"Movie.2001.UNRATED.720p.BluRay.mkv", "Movie", "2001", "Unrated"},
+		{"Jaws.1975.REMASTERED.1080p.BluRay.mkv", "Jaws", "1975", "Remastered"},
+		{"Avatar.2009.Extended.IMAX.2160p.mkv", "Avatar", "2009", "Extended IMAX"},
+		{"Movie (2001).mkv", "Movie", "2001", ""},
+		{"Movie (2001) - 2160p.mkv", "Movie", "2001", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseMovieName(tt.input)
+			if err != nil {
+				t.Fatalf("ParseMovieName() error = %v", err)
+			}
+			if got.Title != tt.wantTitle || got.Year != tt.wantYear {
+				t.Errorf("Title/Year = %q/%q, want %q/%q", got.Title, got.Year, tt.wantTitle, tt.wantYear)
+			}
+			if got.Edition != tt.wantEdition {
+				t.Errorf("Edition = %q, want %q", got.Edition, tt.wantEdition)
+			}
+		})
+	}
+}
+
+func TestFormatMovieVersionFilename(t *testing.T) {
+	if got, want := FormatMovieVersionFilename("Blade Runner", "1982", "Director's Cut", "mkv"), "Blade Runner (1982) - Director's Cut.mkv"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := FormatMovieVersionFilename("Blade Runner", "1982", "", "mkv"), "Blade Runner (1982).mkv"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := MovieVersionLabel("Movie (2001) - 2160p.mkv", "Movie", "2001"); !IsResolutionVersionLabel(got) {
+		t.Errorf("MovieVersionLabel = %q, want a resolution label", got)
+	}
+}
+
+func TestParseMovieVersion(t *testing.T) {
+	tests := map[string]string{
+		"Movie (2001) - 2160p.mkv":               "2160p",
+		"Movie (2001) - 4K - part1.mkv":          "4K",
+		"Movie (2001) - Director's Cut.mkv":      "Director's Cut",
+		"Movie (2001).mkv":                       "",
+		"Movie.2001.2160p.BluRay.x265-GROUP.mkv": "",
+	}
+	for input, want := range tests {
+		if got := ParseMovieVersion(input); got != want {
+			t.Errorf("ParseMovieVersion(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,394 @@
+package naming
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ExtraType is the kind of a bonus video. Its value is the subfolder
+// Jellyfin looks for next to a movie, show or season.
+type ExtraType string
+
+const (
+	ExtraBehindTheScenes ExtraType = "behind the scenes"
+	ExtraDeletedScene    ExtraType = "deleted scenes"
+	ExtraFeaturette      ExtraType = "featurettes"
+	ExtraInterview       ExtraType = "interviews"
+	ExtraTrailer         ExtraType = "trailers"
+	ExtraShort           ExtraType = "shorts"
+	ExtraOther           ExtraType = "extras"
+)
+
+// Folder returns the Jellyfin extras folder for t ("trailers").
+func (t ExtraType) Folder() string {
+	return string(t)
+}
+
+// Suffix returns the file-name suffix Jellyfin accepts in place of the
+// folder ("Movie (2001)-trailer.mkv").
+func (t ExtraType) Suffix() string {
+	switch t {
+	case ExtraBehindTheScenes:
+		return "-behindthescenes"
+	case ExtraDeletedScene:
+		return "-deleted"
+	case ExtraFeaturette:
+		return "-featurette"
+	case ExtraInterview:
+		return "-interview"
+	case ExtraTrailer:
+		return "-trailer"
+	case ExtraShort:
+		return "-short"
+	default:
+		return "-extra"
+	}
+}
+
+// Label names an extra that has no title of its own.
+func (t ExtraType) Label() string {
+	switch t {
+	case ExtraBehindTheScenes:
+		return "Behind the Scenes"
+	case ExtraDeletedScene:
+		return "Deleted Scene"
+	case ExtraFeaturette:
+		return "Featurette"
+	case ExtraInterview:
+		return "Interview"
+	case ExtraTrailer:
+		return "Trailer"
+	case ExtraShort:
+		return "Short"
+	default:
+		return "Extra"
+	}
+}
+
+// ExtrasLayout is how movie extras are placed in the library.
+type ExtrasLayout string
+
+const (
+	// ExtrasFolders files extras into per-type subfolders of the movie
+	// folder ("Movie (2001)/trailers/Trailer.mkv").
+	ExtrasFolders ExtrasLayout = "folders"
+	// ExtrasSuffix names extras after the movie with a type suffix
+	// ("Movie (2001)/Movie (2001)-trailer.mkv").
+	ExtrasSuffix ExtrasLayout = "suffix"
+	// ExtrasSkip leaves extras where they were downloaded.
+	ExtrasSkip ExtrasLayout = "skip"
+)
+
+// ParseExtrasLayout validates an extras layout setting; empty means
+// ExtrasFolders.
+func ParseExtrasLayout(s string) (ExtrasLayout, error) {
+	switch layout := ExtrasLayout(strings.ToLower(strings.TrimSpace(s))); layout {
+	case "":
+		return ExtrasFolders, nil
+	case ExtrasFolders, ExtrasSuffix, ExtrasSkip:
+		return layout, nil
+	default:
+		return "", fmt.Errorf("unknown extras layout %q (want folders, suffix or skip)", s)
+	}
+}
+
+// ExtraInfo describes a bonus video found by ParseExtra.
+type ExtraInfo struct {
+	Type ExtraType
+	// Name is the extra's own title ("Official Trailer", "Making of"),
+	// or the type's label when the file has none.
+	Name string
+	// MainPath stands in for the release's main video: the extra's path
+	// with its extra markers removed, or the release folder's name for
+	// extras in an extras folder. It is parsed in place of the extra to
+	// find the movie or show it belongs to.
+	MainPath string
+	// Season is the season a TV extra belongs to, -1 when it belongs to
+	// the whole show.
+	Season int
+}
+
+// extraFolders maps normalized folder names to the extras they hold.
+// Jellyfin's own folder names come first; the rest are common in
+// releases.
+var extraFolders = map[string]ExtraType{
+	"behind the scenes": ExtraBehindTheScenes,
+	"deleted scenes":    ExtraDeletedScene,
+	"featurettes":       ExtraFeaturette,
+	"interviews":        ExtraInterview,
+	"trailers":          ExtraTrailer,
+	"shorts":            ExtraShort,
+	"extras":            ExtraOther,
+	"other":             ExtraOther,
+	"scenes":            ExtraOther,
+	"clips":             ExtraOther,
+	"making of":         ExtraBehindTheScenes,
+	"deleted scene":     ExtraDeletedScene,
+	"featurette":        ExtraFeaturette,
+	"interview":         ExtraInterview,
+	"trailer":           ExtraTrailer,
+	"extra":             ExtraOther,
+	"bonus":             ExtraOther,
+	"bonus features":    ExtraOther,
+	"special features":  ExtraOther,
+}
+
+// extraSuffixes are the Jellyfin file-name suffixes, longest first so
+// "-deletedscene" isn't read as "-scene".
+var extraSuffixes = []struct {
+	suffix string
+	t      ExtraType
+}{
+	{"behindthescenes", ExtraBehindTheScenes},
+	{"deletedscene", ExtraDeletedScene},
+	{"featurette", ExtraFeaturette},
+	{"interview", ExtraInterview},
+	{"deleted", ExtraDeletedScene},
+	{"trailer", ExtraTrailer},
+	{"short", ExtraShort},
+	{"scene", ExtraOther},
+	{"extra", ExtraOther},
+	{"other", ExtraOther},
+	{"clip", ExtraOther},
+}
+
+// extraTokens are the markers that make a release an extra when they
+// follow its title and year ("Movie.2001.Making.Of.1080p").
+var extraTokens = []struct {
+	t  ExtraType
+	re *regexp.Regexp
+}{
+	{ExtraBehindTheScenes, regexp.MustCompile(`(?i)(?:^|[ ._-])(?:behind[ ._-]*the[ ._-]*scenes|making[ ._-]*of)(?:$|[ ._-])`)},
+	{ExtraDeletedScene, regexp.MustCompile(`(?i)(?:^|[ ._-])deleted[ ._-]*scenes?(?:$|[ ._-])`)},
+	{ExtraFeaturette, regexp.MustCompile(`(?i)(?:^|[ ._-])featurettes?(?:$|[ ._-])`)},
+	{ExtraInterview, regexp.MustCompile(`(?i)(?:^|[ ._-])interviews?(?:$|[ ._-])`)},
+	{ExtraTrailer, regexp.MustCompile(`(?i)(?:^|[ ._-])(?:trailers?|teasers?)(?:$|[ ._-])`)},
+	{ExtraOther, regexp.MustCompile(`(?i)(?:^|[ ._-])(?:extras?|bonus)(?:$|[ ._-])`)},
+}
+
+// IsExtra reports whether path is a bonus video rather than a movie or
+// episode.
+func IsExtra(path string) bool {
+	_, ok := ParseExtra(path)
+	return ok
+}
+
+// ExtraFolderType returns the kind of extras a folder named name holds.
+func ExtraFolderType(name string) (ExtraType, bool) {
+	t, ok := extraFolders[normalizeExtraName(name)]
+	return t, ok
+}
+
+// ParseExtra classifies a bonus video the way Jellyfin does, by its folder
+// ("Featurettes/Making Of.mkv") or a suffix ("Movie (2001)-trailer.mkv"),
+// and also by a marker after a release's title and year or season
+// ("Movie.2001.Trailer.1080p.mkv", "Show.S01.Extras.mkv"). Markers are
+// ignored in episode names and before the year, so "The Interview (2014)"
+// and "Show S02E03 The Interview" stay what they are.
+func ParseExtra(path string) (ExtraInfo, bool) {
+	path = filepath.Clean(path)
+	ext := filepath.Ext(path)
+	baseName := strings.TrimSuffix(filepath.Base(path), ext)
+	dir := filepath.Dir(path)
+
+	// A video in an extras folder, or in a subfolder of a generic one
+	// ("Extras/Cast Interviews/"). Only generic folders count one level
+	// up, so a library of short films isn't taken for extras.
+	for i, d := 0, dir; i < 2 && d != "." && d != string(filepath.Separator); i, d = i+1, filepath.Dir(d) {
+		folder := filepath.Base(d)
+		t, ok := ExtraFolderType(folder)
+		if !ok || (i > 0 && t != ExtraOther) {
+			continue
+		}
+		// A show called "Extras" keeps its episodes.
+		if strings.HasPrefix(normalizeExtraName(baseName), normalizeExtraName(folder)+" ") {
+			break
+		}
+		releaseDir := filepath.Dir(d)
+		main := releaseDir + ext
+		return ExtraInfo{
+			Type:     t,
+			Name:     extraName(baseName, t),
+			MainPath: main,
+			Season:   extraSeason(filepath.Base(releaseDir)),
+		}, true
+	}
+
+	// A Jellyfin suffix, or a file named just "trailer". Suffixes only
+	// count on library-style names and in Jellyfin's lowercase or title
+	// case; otherwise ("x264-SCENE", "-SHORT") the last dash starts the
+	// release group.
+	lower := strings.ToLower(baseName)
+	for _, s := range extraSuffixes {
+		if lower == s.suffix {
+			return ExtraInfo{Type: s.t, Name: s.t.Label(), MainPath: dir + ext, Season: extraSeason(filepath.Base(dir))}, true
+		}
+		if strings.HasSuffix(lower, "-"+s.suffix) {
+			head := strings.TrimSpace(baseName[:len(baseName)-len(s.suffix)-1])
+			if head == "" || !isLibraryStyleName(head) || baseName[len(head)+1:] == strings.ToUpper(s.suffix) {
+				continue
+			}
+			return ExtraInfo{Type: s.t, Name: s.t.Label(), MainPath: filepath.Join(dir, head+ext), Season: extraSeason(head)}, true
+		}
+	}
+
+	// A marker after the title and year or season, followed by nothing
+	// but release markers, so "Movie.2019.Bonus.Round" stays a title. The
+	// release group closing the name is never a marker.
+	tail := extraSearchTail(baseName)
+	if tail == "" {
+		return ExtraInfo{}, false
+	}
+	group := len(tail)
+	if loc := releaseGroupSuffix.FindStringIndex(tail); loc != nil {
+		group = loc[0]
+	}
+	first, firstType := -1, ExtraType("")
+	for _, tok := range extraTokens {
+		loc := tok.re.FindStringIndex(tail)
+		if loc == nil || loc[0] >= group || !onlyReleaseMarkers(tail[loc[1]:]) {
+			continue
+		}
+		if first < 0 || loc[0] < first {
+			first, firstType = loc[0], tok.t
+		}
+	}
+	if first < 0 {
+		return ExtraInfo{}, false
+	}
+	head := strings.TrimRight(baseName[:len(baseName)-len(tail)], " ._-")
+	return ExtraInfo{
+		Type:     firstType,
+		Name:     extraName(tail, firstType),
+		MainPath: filepath.Join(dir, head+ext),
+		Season:   extraSeason(head),
+	}, true
+}
+
+// extraSearchTail returns the part of baseName that may carry an extra
+// marker: what follows a season marker or the year. Names with an episode
+// marker have none; their tail is the episode title.
+func extraSearchTail(baseName string) string {
+	if findEpisodeMatch(baseName).found {
+		return ""
+	}
+	if loc := seasonPackRegex.FindStringSubmatchIndex(baseName); loc != nil && loc[2] > 0 {
+		return baseName[loc[5]:]
+	}
+	if loc := yearRegex.FindStringIndex(baseName); loc != nil && loc[0] > 0 {
+		return baseName[loc[1]:]
+	}
+	return ""
+}
+
+// isLibraryStyleName reports whether name reads like a file named for the
+// library ("Movie (2001)") rather than a release: no quality or source
+// markers.
+func isLibraryStyleName(name string) bool {
+	return !qualityMarkerDetect.MatchString(name)
+}
+
+// onlyReleaseMarkers reports whether s holds nothing but release markers,
+// a release group and perhaps a part number ("2.1080p.WEB-DL.x264-GRP").
+func onlyReleaseMarkers(s string) bool {
+	s = releaseGroupSuffix.ReplaceAllString(s, "")
+	s = strings.TrimSpace(normalizeSpaces(stripReleaseMarkers(s)))
+	return s == "" || strings.Trim(s, "0123456789") == ""
+}
+
+// extraSeason returns the season named by a release or folder name
+// ("Show.S02.Extras", "Season 02"), or -1 for none.
+func extraSeason(name string) int {
+	if m := findEpisodeMatch(name); m.found && (m.kind == "season_episode" || m.kind == "x") {
+		return m.season
+	}
+	if m := seasonPackRegex.FindStringSubmatch(name); m != nil {
+		var season int
+		fmt.Sscanf(m[2], "%d", &season)
+		return season
+	}
+	if season, ok := SeasonFolderNumber(name); ok {
+		return season
+	}
+	return -1
+}
+
+// extraName tidies an extra's title from its file name, falling back to
+// the type's label when nothing is left once release markers are gone.
+func extraName(s string, t ExtraType) string {
+	s = stripReleaseMarkers(s)
+	s = strings.Trim(normalizeSpaces(s), " -")
+	if s == "" {
+		return t.Label()
+	}
+	return s
+}
+
+// normalizeExtraName lower-cases a folder or file name and turns its
+// separators into single spaces.
+func normalizeExtraName(s string) string {
+	s = strings.NewReplacer(".", " ", "_", " ", "-", " ").Replace(strings.ToLower(s))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// FormatExtraFilename returns the library file name of an extra. In the
+// folder layout it is the extra's own name; in the suffix layout it is
+// the owner's name ("Movie (2001)") with the type suffix, keeping the
+// extra's name in between when it has one of its own. Copies after the
+// first (n > 1) are numbered so extras sharing a name don't collide.
+func FormatExtraFilename(extra ExtraInfo, owner string, layout ExtrasLayout, n int, ext string) string {
+	name := sanitizeExtraName(extra.Name)
+	if name == "" {
+		name = extra.Type.Label()
+	}
+	unnamed := name == extra.Type.Label()
+	if n > 1 {
+		name = fmt.Sprintf("%s %d", name, n)
+	}
+	if layout != ExtrasSuffix {
+		return name + ext
+	}
+	if unnamed && n <= 1 {
+		return owner + extra.Type.Suffix() + ext
+	}
+	return fmt.Sprintf("%s - %s%s%s", owner, name, extra.Type.Suffix(), ext)
+}
+
+// Show returns the show a TV extra belongs to, parsed from its MainPath:
+// an episode or season-pack name, or a plain "Show (Year)" folder. Season
+// is the extra's season, -1 for a show-level extra.
+func (e ExtraInfo) Show() (*TVShowInfo, error) {
+	ext := filepath.Ext(e.MainPath)
+	main := strings.TrimSuffix(e.MainPath, ext)
+	if _, ok := SeasonFolderNumber(filepath.Base(main)); ok {
+		// "Show (2019)/Season 01/extras": the show folder names it.
+		main = filepath.Dir(main)
+	}
+	name := filepath.Base(main)
+
+	info := &TVShowInfo{Season: e.Season}
+	if tv, err := ParseTVShowName(name + ext); err == nil {
+		info.Title, info.Year = tv.Title, tv.Year
+	} else if pack, err := ParseTVSeasonPackName(name); err == nil {
+		info.Title, info.Year = pack.Title, pack.Year
+	} else if movie, err := ParseMovieName(name + ext); err == nil {
+		info.Title, info.Year = movie.Title, movie.Year
+	} else {
+		return nil, fmt.Errorf("%w: no show name in extra %s", ErrParseFailed, e.MainPath)
+	}
+	return info, nil
+}
+
+// sanitizeExtraName drops characters that can't appear in a file name.
+func sanitizeExtraName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return -1
+		}
+		return r
+	}, s)
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,137 @@
+package naming
+
+import "testing"
+
+func TestParseExtra(t *testing.T) {
+	tests := []struct {
+		path       string
+		wantType   ExtraType
+		wantName   string
+		wantMain   string
+		wantSeason int
+	}{
+		// Jellyfin folders.
+		{"/dl/Movie.2020.1080p.BluRay/Featurettes/Making Of.mkv", ExtraFeaturette, "Making Of", "/dl/Movie.2020.1080p.BluRay.mkv", -1},
+		{"/dl/Movie.2020.1080p.BluRay/Behind.The.Scenes/Part.1.mkv", ExtraBehindTheScenes, "Part 1", "/dl/Movie.2020.1080p.BluRay.mkv", -1},
+		{"/dl/Movie.2020.1080p.BluRay/Extras/Cast/Interview.mkv", ExtraOther, "Interview", "/dl/Movie.2020.1080p.BluRay.mkv", -1},
+		{"/dl/Show.S02.1080p.WEB/Extras/Bloopers.mkv", ExtraOther, "Bloopers", "/dl/Show.S02.1080p.WEB.mkv", 2},
+		{"/tv/Show (2019)/Season 01/trailers/Trailer.mkv", ExtraTrailer, "Trailer", "/tv/Show (2019)/Season 01.mkv", 1},
+		// Jellyfin suffixes and bare names.
+		{"/movies/Movie (2020)/Movie (2020)-trailer.mkv", ExtraTrailer, "Trailer", "/movies/Movie (2020)/Movie (2020).mkv", -1},
+		{"/movies/Movie (2020)/Movie (2020)-deletedscene.mkv", ExtraDeletedScene, "Deleted Scene", "/movies/Movie (2020)/Movie (2020).mkv", -1},
+		{"/movies/Movie (2020)/trailer.mp4", ExtraTrailer, "Trailer", "/movies/Movie (2020).mp4", -1},
+		// Markers after the year or season.
+		{"/dl/Movie.2020.Official.Trailer.1080p.WEB-DL.x264-GRP.mkv", ExtraTrailer, "Official Trailer", "/dl/Movie.2020.mkv", -1},
+		{"/dl/Movie.2020.behind-the-scenes.mkv", ExtraBehindTheScenes, "behind the scenes", "/dl/Movie.2020.mkv", -1},
+		{"/dl/Movie.2020.Deleted.Scenes.720p.mkv", ExtraDeletedScene, "Deleted Scenes", "/dl/Movie.2020.mkv", -1},
+		{"/dl/Show.S03.Extras.1080p.mkv", ExtraOther, "Extras", "/dl/Show.S03.mkv", 3},
+		{"/dl/Movie.2020.Trailer.2.1080p.WEB-DL.x264-GRP.mkv", ExtraTrailer, "Trailer 2", "/dl/Movie.2020.mkv", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := ParseExtra(tt.path)
+			if !ok {
+				t.Fatal("not recognised as an extra")
+			}
+			if got.Type != tt.wantType || got.Name != tt.wantName {
+				t.Errorf("Type/Name = %q/%q, want %q/%q", got.Type, got.Name, tt.wantType, tt.wantName)
+			}
+			if got.MainPath != tt.wantMain {
+				t.Errorf("MainPath = %q, want %q", got.MainPath, tt.wantMain)
+			}
+			if got.Season != tt.wantSeason {
+				t.Errorf("Season = %d, want %d", got.Season, tt.wantSeason)
+			}
+		})
+	}
+}
+
+func TestParseExtra_TitlesAreNotExtras(t *testing.T) {
+	for _, path := range []string{
+		"/dl/Extra.Ordinary.2019.1080p.BluRay.mkv",
+		"/dl/The.Interview.2014.1080p.BluRay.mkv",
+		"/dl/Trailer.Park.Boys.S01E01.720p.mkv",
+		"/dl/Show.S02E03.The.Interview.1080p.WEB.mkv",
+		"/dl/Movie.2020-sample.mkv",
+		"/tv/Extras/Season 01/Extras S01E01.mkv",
+		"/tv/Extras/Extras S01E02.mkv",
+		"/movies/Shorts/Film (2010)/Film (2010).mkv",
+		"/movies/Movie (2020)/Movie (2020).mkv",
+		// Release groups that happen to be extra words.
+		"/dl/Movie.2019.1080p.BluRay.x264-SCENE.mkv",
+		"/dl/Movie.2019.1080p.WEB-DL.x264-OTHER.mkv",
+		"/dl/Movie.2019.1080p.WEB-DL.x264-EXTRA.mkv",
+		"/dl/Movie.2019.1080p.BluRay.x264-SHORT.mkv",
+		"/dl/Movie.2019-EXTRA.mkv",
+		"/movies/Movie (2019)/Movie (2019)-SHORT.mkv",
+		// A marker word that is part of a title.
+		"/dl/Movie.2019.Bonus.Round.1080p.mkv",
+	} {
+		if info, ok := ParseExtra(path); ok {
+			t.Errorf("ParseExtra(%q) = %+v, want not an extra", path, info)
+		}
+	}
+}
+
+func TestFormatExtraFilename(t *testing.T) {
+	named := ExtraInfo{Type: ExtraTrailer, Name: "Teaser: Part 2"}
+	unnamed := ExtraInfo{Type: ExtraFeaturette, Name: ExtraFeaturette.Label()}
+
+	tests := []struct {
+		extra  ExtraInfo
+		layout ExtrasLayout
+		n      int
+		want   string
+	}{
+		{named, ExtrasFolders, 1, "Teaser Part 2.mkv"},
+		{named, ExtrasSuffix, 1, "Movie (2020) - Teaser Part 2-trailer.mkv"},
+		{unnamed, ExtrasFolders, 1, "Featurette.mkv"},
+		{unnamed, ExtrasFolders, 2, "Featurette 2.mkv"},
+		{unnamed, ExtrasSuffix, 1, "Movie (2020)-featurette.mkv"},
+		{unnamed, ExtrasSuffix, 3, "Movie (2020) - Featurette 3-featurette.mkv"},
+	}
+	for _, tt := range tests {
+		if got := FormatExtraFilename(tt.extra, "Movie (2020)", tt.layout, tt.n, ".mkv"); got != tt.want {
+			t.Errorf("FormatExtraFilename(%q, %s, %d) = %q, want %q", tt.extra.Name, tt.layout, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestExtraInfoShow(t *testing.T) {
+	tests := []struct {
+		path       string
+		wantTitle  string
+		wantYear   string
+		wantSeason int
+	}{
+		{"/dl/Show.S02.1080p.WEB/Extras/Bloopers.mkv", "Show", "", 2},
+		{"/dl/Show.2019.Trailer.1080p.mkv", "Show", "2019", -1},
+		{"/tv/Show (2019)/Season 01/trailers/Trailer.mkv", "Show", "2019", 1},
+		{"/tv/Show (2019)/extras/Bloopers.mkv", "Show", "2019", -1},
+	}
+	for _, tt := range tests {
+		extra, ok := ParseExtra(tt.path)
+		if !ok {
+			t.Fatalf("ParseExtra(%q) not an extra", tt.path)
+		}
+		got, err := extra.Show()
+		if err != nil {
+			t.Fatalf("Show(%q) error = %v", tt.path, err)
+		}
+		if got.Title != tt.wantTitle || got.Year != tt.wantYear || got.Season != tt.wantSeason {
+			t.Errorf("Show(%q) = %q/%q/%d, want %q/%q/%d", tt.path, got.Title, got.Year, got.Season, tt.wantTitle, tt.wantYear, tt.wantSeason)
+		}
+	}
+}
+
+func TestParseExtrasLayout(t *testing.T) {
+	for in, want := range map[string]ExtrasLayout{"": ExtrasFolders, "Suffix": ExtrasSuffix, "skip": ExtrasSkip} {
+		if got, err := ParseExtrasLayout(in); err != nil || got != want {
+			t.Errorf("ParseExtrasLayout(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseExtrasLayout("nested"); err == nil {
+		t.Error("ParseExtrasLayout accepted an unknown layout")
+	}
+}
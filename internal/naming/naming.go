@@ -18,6 +18,12 @@ var ErrParseFailed = errors.New("parse failed")
 type MovieInfo struct {
 	Title string
 	Year  string
+	// Edition is the release's edition label ("Director's Cut", "Extended"),
+	// empty for the standard cut. Different editions are distinct versions.
+	Edition string
+	// Part is the part number of a stacked (multi-part) release, 0 when the
+	// movie is a single file.
+	Part int
 }
 
 type TVShowInfo struct {
@@ -25,15 +31,60 @@ type TVShowInfo struct {
 	Year        string
 	Season      int
 	Episode     int
+	EpisodeEnd  int // last episode of a multi-episode file (S01E01-E03 → 3), 0 otherwise
 	EpisodeDate string
+	// AbsoluteEpisode is the absolute (anime) episode number when the
+	// release is numbered that way, 0 for season-relative numbering. Until
+	// it is mapped, Season/Episode hold season 1 and the absolute number.
+	AbsoluteEpisode int
+	// SpecialTitle labels a season 0 release found by a special marker
+	// ("Christmas Special 2023", "OVA") rather than an SxxEyy tag. It names
+	// the file when the special has no episode number.
+	SpecialTitle string
+}
+
+// IsMultiEpisode reports whether the file spans more than one episode.
+func (t TVShowInfo) IsMultiEpisode() bool {
+	return t.EpisodeEnd > t.Episode
+}
+
+// LastEpisode returns the final episode covered by the file, which is
+// Episode itself for single-episode files.
+func (t TVShowInfo) LastEpisode() int {
+	if t.IsMultiEpisode() {
+		return t.EpisodeEnd
+	}
+	return t.Episode
+}
+
+// SetEpisodes sets Episode/EpisodeEnd from an episode list such as the one
+// returned by the AI matcher. Only a contiguous ascending run is recorded as
+// a span; anything else keeps just the first episode.
+func (t *TVShowInfo) SetEpisodes(episodes []int) {
+	if len(episodes) == 0 {
+		return
+	}
+	t.Episode = episodes[0]
+	t.EpisodeEnd = 0
+	for i := 1; i < len(episodes); i++ {
+		if episodes[i] != episodes[i-1]+1 {
+			return
+		}
+	}
+	if len(episodes) > 1 {
+		t.EpisodeEnd = episodes[len(episodes)-1]
+	}
 }
 
 var (
 	yearRegex      = regexp.MustCompile(`\b(19|20)\d{2}\b`)
 	yearParenRegex = regexp.MustCompile(`\((\d{4})\)`)
-	episodeSERegex = regexp.MustCompile(`[Ss](\d{1,2})[Ee](\d{1,2})`)
-	episodeXRegex  = regexp.MustCompile(`(\d{1,2})x(\d{1,2})`)
-	episodeEPRegex = regexp.MustCompile(`(?i)\bEP[.\-_ ]?(\d{2,5})\b`)
+	// Multi-episode suffixes: S01E01E02, S01E01-E03, S01E01-03.
+	episodeSERegex = regexp.MustCompile(`[Ss](\d{1,2})[Ee](\d{1,2})((?:-?[Ee]\d{1,2})+|-\d{1,2}\b)?`)
+	// Multi-episode suffixes: 1x01-1x02, 1x01-02.
+	episodeXRegex   = regexp.MustCompile(`(\d{1,2})x(\d{1,2})(?:-(?:(\d{1,2})x)?(\d{1,2})\b)?`)
+	episodeNumRegex = regexp.MustCompile(`\d+`)
+	episodeEPRegex  = regexp.MustCompile(`(?i)\bEP[.\-_ ]?(\d{2,5})\b`)
 	// Date-based episode pattern: YYYY.MM.DD, YYYY-MM-DD, YYYY_MM_DD
 	episodeDateRegex = regexp.MustCompile(`\b((19|20)\d{2})[.\-_](0[1-9]|1[0-2])[.\-_](0[1-9]|[12]\d|3[01])\b`)
 	releasePatterns  []*regexp.Regexp
@@ -90,10 +141,16 @@ func IsMovieFilename(filename string) bool {
 	return !IsTVEpisodeFilename(filename)
 }
 
+// IsTVEpisodeFilename reports whether filename names an episode: it has an
+// episode marker, or the dash-separated number of an anime release, with or
+// without the leading [Group] tag ("Show - 123.mkv").
 func IsTVEpisodeFilename(filename string) bool {
 	baseName := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
-
-	return findEpisodeMatch(baseName).found
+	if findEpisodeMatch(baseName).found {
+		return true
+	}
+	_, err := parseAnimeFromBaseName(baseName, filename)
+	return err == nil
 }
 
 func HasYearInParentheses(filename string) bool {
@@ -110,7 +167,11 @@ func ParseMovieName(filename string) (*MovieInfo, error) {
 func ParseMovieNameVerbose(filename string) (*MovieInfo, []string, error) {
 	baseName := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
 	tokens := collectStrippedTokens(baseName)
-	info, err := parseMovieFromBaseName(baseName)
+	info, err := parseMovieFromBaseName(stripMovieEdition(stripStackPart(baseName)))
+	if info != nil {
+		info.Edition = ParseMovieEdition(filename)
+		info.Part = ParseStackPart(filename)
+	}
 	return info, tokens, err
 }
 
@@ -207,7 +268,7 @@ func ParseTVSeasonPackNameVerbose(name string) (*TVSeasonPackInfo, []string, err
 
 	seasonText := baseName[match[4]:match[5]]
 	season := 0
-	if _, err := fmt.Sscanf(seasonText, "%d", &season); err != nil || season <= 0 {
+	if _, err := fmt.Sscanf(seasonText, "%d", &season); err != nil || season < 0 {
 		return nil, tokens, fmt.Errorf("%w: invalid season-pack marker in: %s", ErrParseFailed, name)
 	}
 
@@ -233,6 +294,18 @@ func ParseTVSeasonPackNameVerbose(name string) (*TVSeasonPackInfo, []string, err
 
 func parseTVShowFromBaseName(baseName, filename string) (*TVShowInfo, error) {
 	episodeMatch := findEpisodeMatch(baseName)
+
+	// Fansub releases ("[Group] Show - 123 [1080p]") carry no SxxEyy marker
+	// and their resolution tags can look like NxMM, so they take the anime
+	// path unless an explicit SxxEyy is present. Without the group tag the
+	// anime path is the fallback for names with no other episode marker, as
+	// in IsTVEpisodeFilename.
+	if episodeMatch.kind != "season_episode" && (animeGroupRegex.MatchString(baseName) || !episodeMatch.found) {
+		if anime, err := parseAnimeFromBaseName(baseName, filename); err == nil {
+			return &anime.TVShowInfo, nil
+		}
+	}
+
 	if !episodeMatch.found {
 		return nil, fmt.Errorf("%w: no episode information found in: %s", ErrParseFailed, filename)
 	}
@@ -251,18 +324,28 @@ func parseTVShowFromBaseName(baseName, filename string) (*TVShowInfo, error) {
 
 	titlePart = normalizeSpaces(titlePart)
 	titlePart = strings.TrimSpace(titlePart)
+	if episodeMatch.kind == "special" {
+		// "Show (2005) - Christmas Special": drop the separator dash.
+		titlePart = strings.TrimSpace(strings.TrimRight(titlePart, " -"))
+	}
 
 	if titlePart == "" {
 		return nil, fmt.Errorf("%w: could not extract TV show title from: %s", ErrParseFailed, filename)
 	}
 
-	return &TVShowInfo{
-		Title:       titlePart,
-		Year:        year,
-		Season:      episodeMatch.season,
-		Episode:     episodeMatch.episode,
-		EpisodeDate: episodeMatch.date,
-	}, nil
+	info := &TVShowInfo{
+		Title:        titlePart,
+		Year:         year,
+		Season:       episodeMatch.season,
+		Episode:      episodeMatch.episode,
+		EpisodeEnd:   episodeMatch.episodeEnd,
+		EpisodeDate:  episodeMatch.date,
+		SpecialTitle: episodeMatch.special,
+	}
+	if episodeMatch.kind == "absolute" {
+		info.AbsoluteEpisode = episodeMatch.episode
+	}
+	return info, nil
 }
 
 func NormalizeMediaName(title, year string) string {
@@ -285,10 +368,26 @@ func FormatSeasonFolder(season int) string {
 }
 
 func FormatTVEpisodeFilename(title, year string, season, episode int, ext string) string {
+	return FormatTVEpisodeRangeFilename(title, year, season, episode, 0, ext)
+}
+
+// FormatTVEpisodeRangeFilename formats a (possibly multi-episode) filename
+// using Jellyfin's S01E01-E02 span notation. episodeEnd <= episode yields
+// the plain single-episode form.
+func FormatTVEpisodeRangeFilename(title, year string, season, episode, episodeEnd int, ext string) string {
+	tag := FormatEpisodeTag(season, episode, episodeEnd)
 	if year != "" {
-		return fmt.Sprintf("%s (%s) S%02dE%02d.%s", title, year, season, episode, ext)
+		return fmt.Sprintf("%s (%s) %s.%s", title, year, tag, ext)
+	}
+	return fmt.Sprintf("%s %s.%s", title, tag, ext)
+}
+
+// FormatEpisodeTag returns "S01E02", or "S01E02-E04" for a span.
+func FormatEpisodeTag(season, episode, episodeEnd int) string {
+	if episodeEnd > episode {
+		return fmt.Sprintf("S%02dE%02d-E%02d", season, episode, episodeEnd)
 	}
-	return fmt.Sprintf("%s S%02dE%02d.%s", title, season, episode, ext)
+	return fmt.Sprintf("S%02dE%02d", season, episode)
 }
 
 func FormatTVEpisodeFilenameFromInfo(info *TVShowInfo, ext string) string {
@@ -299,7 +398,11 @@ func FormatTVEpisodeFilenameFromInfo(info *TVShowInfo, ext string) string {
 		title := NormalizeMediaName(info.Title, info.Year)
 		return fmt.Sprintf("%s %s.%s", title, info.EpisodeDate, ext)
 	}
-	return FormatTVEpisodeFilename(info.Title, info.Year, info.Season, info.Episode, ext)
+	if info.IsSpecial() && info.Episode <= 0 && info.SpecialTitle != "" {
+		title := NormalizeMediaName(info.Title, info.Year)
+		return fmt.Sprintf("%s - %s.%s", title, info.SpecialTitle, ext)
+	}
+	return FormatTVEpisodeRangeFilename(info.Title, info.Year, info.Season, info.Episode, info.EpisodeEnd, ext)
 }
 
 // knownReleaseGroups is an exhaustive list of common release groups
@@ -469,10 +572,13 @@ func removeYear(s, year string) string {
 type episodeMatch struct {
 	season  int
 	episode int
-	loc     []int
-	kind    string
-	date    string
-	found   bool
+	// episodeEnd is the last episode of a multi-episode match, 0 otherwise.
+	episodeEnd int
+	loc        []int
+	kind       string
+	date       string
+	special    string
+	found      bool
 }
 
 func findEpisodeMatch(s string) episodeMatch {
@@ -480,12 +586,18 @@ func findEpisodeMatch(s string) episodeMatch {
 	if len(match) > 2 {
 		season, _ := strconv.Atoi(match[1])
 		episode, _ := strconv.Atoi(match[2])
+		episodeEnd := 0
+		if nums := episodeNumRegex.FindAllString(match[3], -1); len(nums) > 0 {
+			last, _ := strconv.Atoi(nums[len(nums)-1])
+			episodeEnd = validEpisodeEnd(episode, last)
+		}
 		return episodeMatch{
-			season:  season,
-			episode: episode,
-			loc:     episodeSERegex.FindStringIndex(s),
-			kind:    "season_episode",
-			found:   true,
+			season:     season,
+			episode:    episode,
+			episodeEnd: episodeEnd,
+			loc:        episodeSERegex.FindStringIndex(s),
+			kind:       "season_episode",
+			found:      true,
 		}
 	}
 
@@ -493,12 +605,24 @@ func findEpisodeMatch(s string) episodeMatch {
 	if len(match) > 2 {
 		season, _ := strconv.Atoi(match[1])
 		episode, _ := strconv.Atoi(match[2])
+		episodeEnd := 0
+		if match[4] != "" {
+			endSeason := season
+			if match[3] != "" {
+				endSeason, _ = strconv.Atoi(match[3])
+			}
+			if endSeason == season {
+				last, _ := strconv.Atoi(match[4])
+				episodeEnd = validEpisodeEnd(episode, last)
+			}
+		}
 		return episodeMatch{
-			season:  season,
-			episode: episode,
-			loc:     episodeXRegex.FindStringIndex(s),
-			kind:    "x",
-			found:   true,
+			season:     season,
+			episode:    episode,
+			episodeEnd: episodeEnd,
+			loc:        episodeXRegex.FindStringIndex(s),
+			kind:       "x",
+			found:      true,
 		}
 	}
 
@@ -529,7 +653,37 @@ func findEpisodeMatch(s string) episodeMatch {
 		}
 	}
 
-	return episodeMatch{}
+	return findSpecialMatch(s)
+}
+
+// maxEpisodeSpan bounds how many episodes a single file may claim. Larger
+// "ranges" are almost always something else (E01-24 on a season pack
+// listing, a resolution fragment) and are treated as a single episode.
+const maxEpisodeSpan = 10
+
+// validEpisodeEnd returns last when it forms a plausible span with first,
+// or 0 when the file should be treated as a single episode.
+func validEpisodeEnd(first, last int) int {
+	if last <= first || last-first >= maxEpisodeSpan {
+		return 0
+	}
+	return last
+}
+
+// ParseEpisodeSpan extracts the season and episode span from an SxxEyy or
+// NxMM marker anywhere in s, including the multi-episode forms accepted by
+// ParseTVShowName. Unlike ParseTVShowName it needs no title, so it works on
+// bare names such as "S01E01E02.mkv". first == last for single episodes.
+func ParseEpisodeSpan(s string) (season, first, last int, ok bool) {
+	match := findEpisodeMatch(s)
+	if !match.found || (match.kind != "season_episode" && match.kind != "x") {
+		return 0, 0, 0, false
+	}
+	last = match.episode
+	if match.episodeEnd > match.episode {
+		last = match.episodeEnd
+	}
+	return match.season, match.episode, last, true
 }
 
 func extractEpisodeInfo(s string) (season, episode int, found bool) {
@@ -323,6 +323,75 @@ func TestFormatTVEpisodeFilenameFromInfo_DateBased(t *testing.T) {
 	}
 }
 
+func TestParseTVShowName_MultiEpisode(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantTitle  string
+		wantSeason int
+		wantEp     int
+		wantEnd    int
+	}{
+		{"Show.S01E01E02.1080p.WEB-DL.mkv", "Show", 1, 1, 2},
+		{"Show.S01E01-E03.720p.HDTV.mkv", "Show", 1, 1, 3},
+		{"show.s02e05e06e07.mkv", "show", 2, 5, 7},
+		{"Show.S01E04-05.1080p.mkv", "Show", 1, 4, 5},
+		{"Show 1x01-1x02.mkv", "Show", 1, 1, 2},
+		{"Show 3x09-10.mkv", "Show", 3, 9, 10},
+		// Not spans: single episode, mismatched season, backwards, implausible.
+		{"Show.S01E01.1080p.mkv", "Show", 1, 1, 0},
+		{"Show 1x01-2x02.mkv", "Show", 1, 1, 0},
+		{"Show.S01E03-E02.mkv", "Show", 1, 3, 0},
+		{"Show.S01E01-E24.mkv", "Show", 1, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseTVShowName(tt.input)
+			if err != nil {
+				t.Fatalf("ParseTVShowName() error = %v", err)
+			}
+			if got.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", got.Title, tt.wantTitle)
+			}
+			if got.Season != tt.wantSeason || got.Episode != tt.wantEp || got.EpisodeEnd != tt.wantEnd {
+				t.Errorf("S%d E%d-E%d, want S%d E%d-E%d", got.Season, got.Episode, got.EpisodeEnd, tt.wantSeason, tt.wantEp, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestFormatTVEpisodeFilenameFromInfo_MultiEpisode(t *testing.T) {
+	info := &TVShowInfo{Title: "Show", Year: "2020", Season: 1, Episode: 1, EpisodeEnd: 2}
+
+	got := FormatTVEpisodeFilenameFromInfo(info, "mkv")
+	want := "Show (2020) S01E01-E02.mkv"
+	if got != want {
+		t.Errorf("FormatTVEpisodeFilenameFromInfo() = %q, want %q", got, want)
+	}
+
+	// The formatted name must parse back to the same span.
+	parsed, err := ParseTVShowName(got)
+	if err != nil {
+		t.Fatalf("ParseTVShowName(%q) error = %v", got, err)
+	}
+	if parsed.Episode != 1 || parsed.EpisodeEnd != 2 {
+		t.Errorf("round trip = E%d-E%d, want E1-E2", parsed.Episode, parsed.EpisodeEnd)
+	}
+}
+
+func TestTVShowInfo_SetEpisodes(t *testing.T) {
+	var info TVShowInfo
+	info.SetEpisodes([]int{3, 4, 5})
+	if info.Episode != 3 || info.EpisodeEnd != 5 || info.LastEpisode() != 5 {
+		t.Errorf("contiguous: got E%d-E%d", info.Episode, info.EpisodeEnd)
+	}
+
+	info.SetEpisodes([]int{3, 7})
+	if info.Episode != 3 || info.EpisodeEnd != 0 || info.IsMultiEpisode() {
+		t.Errorf("non-contiguous: got E%d-E%d", info.Episode, info.EpisodeEnd)
+	}
+}
+
 func TestIsTVEpisodeFilename_DatePatterns(t *testing.T) {
 	tests := []struct {
 		filename string
@@ -0,0 +1,100 @@
+package naming
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SpecialsFolder is the season 0 folder name Jellyfin accepts alongside
+// "Season 00". Existing libraries use either; new shows get "Season 00".
+const SpecialsFolder = "Specials"
+
+var (
+	// "Christmas Special", "Holiday.Special.2023". A trailing year is kept
+	// in the label so yearly specials don't collide on one target name.
+	specialOccasionRegex = regexp.MustCompile(`(?i)\b((?:christmas|xmas|holiday|halloween|easter|thanksgiving|new[ ._-]?years?|valentines?)[ ._-]+special)s?\b(?:[ ._-]+((?:19|20)\d{2})\b)?`)
+	// "Show.S02.Special": a special attached to a season, without an episode.
+	specialSeasonRegex = regexp.MustCompile(`(?i)\bS(\d{1,2})[ ._-]+specials?\b`)
+	// Anime OVA/OAD/ONA releases, optionally numbered ("OVA 2", "OVA02").
+	specialAnimeRegex = regexp.MustCompile(`\b(OVA|OAD|ONA)(?:[ ._-]*(\d{1,3}))?\b`)
+	// "Season 1", "season 01", "Season.2": a numbered season folder.
+	seasonFolderRegex = regexp.MustCompile(`(?i)^season[ ._-]*(\d{1,4})$`)
+)
+
+// IsSpecial reports whether the episode belongs to season 0 (specials).
+func (t TVShowInfo) IsSpecial() bool {
+	return t.Season == 0
+}
+
+// findSpecialMatch recognises specials that carry no SxxEyy marker. The
+// match is filed under season 0; label names the special for files with no
+// episode number.
+func findSpecialMatch(s string) episodeMatch {
+	if m := specialOccasionRegex.FindStringSubmatchIndex(s); m != nil {
+		label := titleWords(s[m[2]:m[3]])
+		if m[4] >= 0 {
+			label += " " + s[m[4]:m[5]]
+		}
+		return episodeMatch{loc: m[:2], kind: "special", special: label, found: true}
+	}
+	if m := specialSeasonRegex.FindStringSubmatchIndex(s); m != nil {
+		season, _ := strconv.Atoi(s[m[2]:m[3]])
+		return episodeMatch{loc: m[:2], kind: "special", special: fmt.Sprintf("S%02d Special", season), found: true}
+	}
+	if m := specialAnimeRegex.FindStringSubmatchIndex(s); m != nil && m[0] > 0 {
+		match := episodeMatch{loc: m[:2], kind: "special", special: s[m[2]:m[3]], found: true}
+		if m[4] >= 0 {
+			match.episode, _ = strconv.Atoi(s[m[4]:m[5]])
+		}
+		return match
+	}
+	return episodeMatch{}
+}
+
+// titleWords turns a separator-joined phrase ("christmas.special") into
+// space-separated title case ("Christmas Special").
+func titleWords(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == '.' || r == '_' || r == '-'
+	})
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + strings.ToLower(w[1:])
+	}
+	return strings.Join(words, " ")
+}
+
+// SeasonFolderNumber returns the season a library folder holds: "Season 01"
+// and "Season 1" give 1, "Season 00" and "Specials" give 0.
+func SeasonFolderNumber(name string) (int, bool) {
+	name = strings.TrimSpace(name)
+	if strings.EqualFold(name, SpecialsFolder) {
+		return 0, true
+	}
+	m := seasonFolderRegex.FindStringSubmatch(name)
+	if m == nil {
+		return 0, false
+	}
+	season, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, false
+	}
+	return season, true
+}
+
+// IsSpecialsFolder reports whether name is a season 0 folder in either of
+// Jellyfin's conventions ("Season 00" or "Specials").
+func IsSpecialsFolder(name string) bool {
+	season, ok := SeasonFolderNumber(name)
+	return ok && season == 0
+}
+
+// SeasonFolderMatches reports whether folder is an acceptable canonical
+// folder for season: FormatSeasonFolder(season), or "Specials" for season 0.
+func SeasonFolderMatches(folder string, season int) bool {
+	if strings.EqualFold(folder, FormatSeasonFolder(season)) {
+		return true
+	}
+	return season == 0 && strings.EqualFold(folder, SpecialsFolder)
+}
@@ -0,0 +1,97 @@
+package naming
+
+import "testing"
+
+func TestParseTVShowName_Specials(t *testing.T) {
+	tests := []struct {
+		input       string
+		wantTitle   string
+		wantYear    string
+		wantEpisode int
+		wantSpecial string
+	}{
+		{"Doctor.Who.2005.S00E05.1080p.WEB-DL.mkv", "Doctor Who", "2005", 5, ""},
+		{"Doctor.Who.2005.Christmas.Special.2023.1080p.WEB.mkv", "Doctor Who", "2005", 0, "Christmas Special 2023"},
+		{"The.Office.US.Holiday.Special.720p.HDTV.mkv", "The Office US", "", 0, "Holiday Special"},
+		{"Taskmaster.S02.Special.1080p.mkv", "Taskmaster", "", 0, "S02 Special"},
+		{"[Group] Made in Abyss - OVA [1080p].mkv", "Made in Abyss", "", 0, "OVA"},
+		{"Attack.on.Titan.OVA.2.1080p.mkv", "Attack on Titan", "", 2, "OVA"},
+		// Canonical names produced by FormatTVEpisodeFilenameFromInfo.
+		{"Doctor Who (2005) - Christmas Special 2023.mkv", "Doctor Who", "2005", 0, "Christmas Special 2023"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseTVShowName(tt.input)
+			if err != nil {
+				t.Fatalf("ParseTVShowName() error = %v", err)
+			}
+			if !got.IsSpecial() {
+				t.Errorf("Season = %d, want 0", got.Season)
+			}
+			if got.Title != tt.wantTitle || got.Year != tt.wantYear {
+				t.Errorf("Title/Year = %q/%q, want %q/%q", got.Title, got.Year, tt.wantTitle, tt.wantYear)
+			}
+			if got.Episode != tt.wantEpisode || got.SpecialTitle != tt.wantSpecial {
+				t.Errorf("Episode/SpecialTitle = %d/%q, want %d/%q", got.Episode, got.SpecialTitle, tt.wantEpisode, tt.wantSpecial)
+			}
+		})
+	}
+}
+
+func TestParseTVShowName_SpecialWordsAreNotSpecials(t *testing.T) {
+	for _, input := range []string{
+		"Special.Ops.Lioness.S01E03.1080p.mkv",
+		"The.Specials.S01E01.720p.mkv",
+	} {
+		got, err := ParseTVShowName(input)
+		if err != nil {
+			t.Fatalf("ParseTVShowName(%q) error = %v", input, err)
+		}
+		if got.IsSpecial() || got.SpecialTitle != "" {
+			t.Errorf("%q parsed as special: %+v", input, got)
+		}
+	}
+	if IsTVEpisodeFilename("Special.Forces.2011.1080p.BluRay.mkv") {
+		t.Error("a movie titled Special... is not a TV special")
+	}
+}
+
+func TestFormatTVEpisodeFilenameFromInfo_UnnumberedSpecial(t *testing.T) {
+	info := &TVShowInfo{Title: "Doctor Who", Year: "2005", Season: 0, SpecialTitle: "Christmas Special 2023"}
+	if got, want := FormatTVEpisodeFilenameFromInfo(info, "mkv"), "Doctor Who (2005) - Christmas Special 2023.mkv"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	info = &TVShowInfo{Title: "Doctor Who", Year: "2005", Season: 0, Episode: 5, SpecialTitle: "OVA"}
+	if got, want := FormatTVEpisodeFilenameFromInfo(info, "mkv"), "Doctor Who (2005) S00E05.mkv"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSeasonFolderNumber(t *testing.T) {
+	tests := []struct {
+		name   string
+		season int
+		ok     bool
+	}{
+		{"Season 01", 1, true},
+		{"season 1", 1, true},
+		{"Season 00", 0, true},
+		{"Specials", 0, true},
+		{"specials", 0, true},
+		{"Extras", 0, false},
+		{"S01", 0, false},
+	}
+	for _, tt := range tests {
+		season, ok := SeasonFolderNumber(tt.name)
+		if season != tt.season || ok != tt.ok {
+			t.Errorf("SeasonFolderNumber(%q) = %d, %v; want %d, %v", tt.name, season, ok, tt.season, tt.ok)
+		}
+	}
+	if !SeasonFolderMatches("Specials", 0) || !SeasonFolderMatches("Season 00", 0) {
+		t.Error("both specials conventions should match season 0")
+	}
+	if SeasonFolderMatches("Specials", 1) {
+		t.Error("Specials is not a folder for season 1")
+	}
+}
@@ -0,0 +1,88 @@
+package naming
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// stackPartRegex matches the part markers of a multi-part (stacked) movie
+// release: "cd1", "CD 2", "part1", "pt.2", "disc-1", "dvdb". Groups: 1 the
+// marker word, 2 the part number or letter.
+var stackPartRegex = regexp.MustCompile(`(?i)[ ._-]+(cd|dvd|part|pt|disc|disk)[ ._-]*(\d{1,2}|[a-d])\b`)
+
+// findStackPart returns the location of the last part marker in the part
+// of baseName that may carry one, or nil. Like edition markers, part
+// markers are only looked for after the title so "Harry Potter and the
+// Deathly Hallows Part 1 (2010)" is not mistaken for a stack.
+func findStackPart(baseName string) (loc []int, part int) {
+	tail := editionSearchTail(baseName)
+	offset := len(baseName) - len(tail)
+	matches := stackPartRegex.FindAllStringSubmatchIndex(tail, -1)
+	for i := len(matches) - 1; i >= 0; i-- {
+		m := matches[i]
+		marker := strings.ToLower(tail[m[2]:m[3]])
+		value := strings.ToLower(tail[m[4]:m[5]])
+		// DVD5/DVD9 name the disc capacity of a full-disc rip, not a part.
+		if marker == "dvd" && (value == "5" || value == "9") {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			n = int(value[0]-'a') + 1
+		}
+		if n < 1 {
+			continue
+		}
+		for j := range m {
+			m[j] += offset
+		}
+		return m, n
+	}
+	return nil, 0
+}
+
+// ParseStackPart returns the part number of a stacked movie file
+// ("movie.cd2.avi" → 2, "Movie (2001) - part1.mkv" → 1), or 0 when the
+// file is not part of a stack.
+func ParseStackPart(filename string) int {
+	baseName := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	_, part := findStackPart(baseName)
+	return part
+}
+
+// StripStackPart returns the base name of filename, without extension and
+// part marker. The parts of one stack share it.
+func StripStackPart(filename string) string {
+	baseName := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	return stripStackPart(baseName)
+}
+
+func stripStackPart(baseName string) string {
+	loc, _ := findStackPart(baseName)
+	if loc == nil {
+		return baseName
+	}
+	return strings.TrimSpace(baseName[:loc[0]] + baseName[loc[1]:])
+}
+
+// FormatStackPart returns Jellyfin's stacking label for part ("part1").
+func FormatStackPart(part int) string {
+	return fmt.Sprintf("part%d", part)
+}
+
+// StackedVersion appends the stacking label of part to a multi-version
+// label: ("Director's Cut", 2) → "Director's Cut - part2", ("", 1) →
+// "part1". A zero part returns version unchanged.
+func StackedVersion(version string, part int) string {
+	version = strings.TrimSpace(version)
+	if part <= 0 {
+		return version
+	}
+	if version == "" {
+		return FormatStackPart(part)
+	}
+	return version + " - " + FormatStackPart(part)
+}
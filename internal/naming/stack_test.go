@@ -0,0 +1,67 @@
+package naming
+
+import "testing"
+
+func TestParseStackPart(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantTitle string
+		wantYear  string
+		wantPart  int
+		wantBase  string
+	}{
+		{"The.Matrix.1999.DVDRip.x264.CD1.avi", "The Matrix", "1999", 1, "The.Matrix.1999.DVDRip.x264"},
+		{"The.Matrix.1999.DVDRip.x264.cd2-GROUP.avi", "The Matrix", "1999", 2, "The.Matrix.1999.DVDRip.x264-GROUP"},
+		{"Heat (1995) - part1.mkv", "Heat", "1995", 1, "Heat (1995)"},
+		{"Heat 1995 Part 2 1080p.mkv", "Heat", "1995", 2, "Heat 1995 1080p"},
+		{"Heat.1995.pt.3.mkv", "Heat", "1995", 3, "Heat.1995"},
+		{"Heat.1995.Disc-B.mkv", "Heat", "1995", 2, "Heat.1995"},
+		{"Blade Runner (1982) - Director's Cut - part2.mkv", "Blade Runner", "1982", 2, "Blade Runner (1982) - Director's Cut"},
+		{"Harry.Potter.and.the.Deathly.Hallows.Part.1.2010.1080p.mkv", "Harry Potter and the Deathly Hallows Part 1", "2010", 0, "Harry.Potter.and.the.Deathly.Hallows.Part.1.2010.1080p"},
+		{"Movie.2001.DVD9.mkv", "Movie", "2001", 0, "Movie.2001.DVD9"},
+		{"Movie.2001.DVDRip.mkv", "Movie", "2001", 0, "Movie.2001.DVDRip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := ParseStackPart(tt.input); got != tt.wantPart {
+				t.Errorf("ParseStackPart() = %d, want %d", got, tt.wantPart)
+			}
+			if got := StripStackPart(tt.input); got != tt.wantBase {
+				t.Errorf("StripStackPart() = %q, want %q", got, tt.wantBase)
+			}
+			movie, err := ParseMovieName(tt.input)
+			if err != nil {
+				t.Fatalf("ParseMovieName() error = %v", err)
+			}
+			if movie.Title != tt.wantTitle || movie.Year != tt.wantYear || movie.Part != tt.wantPart {
+				t.Errorf("ParseMovieName() = %q/%q part %d, want %q/%q part %d",
+					movie.Title, movie.Year, movie.Part, tt.wantTitle, tt.wantYear, tt.wantPart)
+			}
+		})
+	}
+}
+
+func TestStackedVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		part    int
+		want    string
+	}{
+		{"", 0, ""},
+		{"", 1, "part1"},
+		{"Director's Cut", 2, "Director's Cut - part2"},
+		{"Extended", 0, "Extended"},
+	}
+	for _, tt := range tests {
+		if got := StackedVersion(tt.version, tt.part); got != tt.want {
+			t.Errorf("StackedVersion(%q, %d) = %q, want %q", tt.version, tt.part, got, tt.want)
+		}
+	}
+	if got, want := FormatMovieVersionFilename("Heat", "1995", StackedVersion("", 1), "avi"), "Heat (1995) - part1.avi"; got != want {
+		t.Errorf("stacked filename = %q, want %q", got, want)
+	}
+	if got := MovieVersionLabel("Heat (1995) - part1.avi", "Heat", "1995"); got != "" {
+		t.Errorf("MovieVersionLabel() = %q, want no version for a plain stack part", got)
+	}
+}
@@ -0,0 +1,403 @@
+package naming
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Nomadcxx/jellywatch/internal/quality"
+)
+
+// Default templates reproduce the built-in Jellyfin layout, so a library
+// that only overrides some templates keeps the standard form for the rest.
+const (
+	DefaultMovieFolderTemplate  = "{Title} ({Year})"
+	DefaultMovieFileTemplate    = "{Title} ({Year}) - {Edition}"
+	DefaultSeriesFolderTemplate = "{Title} ({Year})"
+	DefaultSeasonFolderTemplate = "Season {Season}"
+	DefaultEpisodeFileTemplate  = "{Title} ({Year}) S{Season}E{Episode}"
+)
+
+// Templates holds a library's naming templates. Empty fields use the
+// defaults above. Names are built from literal text and {Token}
+// placeholders; a (...) or [...] group whose tokens all render empty is
+// dropped along with its leading space, so "{Title} ({Year}) [imdbid-{ImdbID}]"
+// renders "Movie (2001)" when neither year nor ID is known.
+//
+// Tokens: {Title} {Year} {Season} {Episode} {EpisodeTitle} {ImdbID}
+// {TmdbID} {TvdbID} {Resolution} {Source} {Edition} {ReleaseGroup}.
+type Templates struct {
+	MovieFolder  string
+	MovieFile    string
+	SeriesFolder string
+	SeasonFolder string
+	EpisodeFile  string
+}
+
+// TemplateValues are the values substituted into a template. Season and
+// Episode are zero-padded; EpisodeEnd > Episode renders {Episode} as a
+// span ("01-E03") so "S{Season}E{Episode}" gives "S01E01-E03".
+type TemplateValues struct {
+	Title        string
+	Year         string
+	Season       int
+	Episode      int
+	EpisodeEnd   int
+	EpisodeTitle string
+	ImdbID       string
+	TmdbID       string
+	TvdbID       string
+	Resolution   string
+	Source       string
+	Edition      string
+	ReleaseGroup string
+}
+
+var templateTokens = map[string]func(TemplateValues) string{
+	"title":        func(v TemplateValues) string { return v.Title },
+	"year":         func(v TemplateValues) string { return v.Year },
+	"season":       func(v TemplateValues) string { return fmt.Sprintf("%02d", v.Season) },
+	"episode":      formatEpisodeToken,
+	"episodetitle": func(v TemplateValues) string { return v.EpisodeTitle },
+	"imdbid":       func(v TemplateValues) string { return v.ImdbID },
+	"tmdbid":       func(v TemplateValues) string { return v.TmdbID },
+	"tvdbid":       func(v TemplateValues) string { return v.TvdbID },
+	"resolution":   func(v TemplateValues) string { return v.Resolution },
+	"source":       func(v TemplateValues) string { return v.Source },
+	"edition":      func(v TemplateValues) string { return v.Edition },
+	"releasegroup": func(v TemplateValues) string { return v.ReleaseGroup },
+}
+
+var (
+	templateTokenRegex = regexp.MustCompile(`\{([A-Za-z]+)\}`)
+	// An optional group: brackets around text holding at least one token.
+	templateGroupRegex = regexp.MustCompile(`\s*[(\[][^()\[\]{}]*\{[A-Za-z]+\}[^()\[\]]*[)\]]`)
+	// Separators left dangling by empty tokens ("Show S01E01 - - [1080p]").
+	templateDashRunRegex   = regexp.MustCompile(`\s+-(?:\s+-)+\s+`)
+	templateDashGroupRegex = regexp.MustCompile(`\s+-\s+([(\[])`)
+	templateSpaceRegex     = regexp.MustCompile(`\s{2,}`)
+	// Jellyfin provider ID tags: "[imdbid-tt0133093]", "{tmdb-603}".
+	providerIDRegex = regexp.MustCompile(`(?i)\s*[\[{](imdb|tmdb|tvdb)(?:id)?-([a-z0-9]+)[\]}]`)
+	// A trailing "-GROUP" release group.
+	releaseGroupRegex = regexp.MustCompile(`-([A-Za-z0-9]{2,15})$`)
+)
+
+// releaseGroupFalsePositives are hyphenated release markers whose tail
+// looks like a group ("WEB-DL", "Blu-Ray").
+var releaseGroupFalsePositives = map[string]bool{
+	"dl": true, "rip": true, "ray": true, "hd": true, "sd": true,
+}
+
+func formatEpisodeToken(v TemplateValues) string {
+	if v.EpisodeEnd > v.Episode {
+		return fmt.Sprintf("%02d-E%02d", v.Episode, v.EpisodeEnd)
+	}
+	return fmt.Sprintf("%02d", v.Episode)
+}
+
+// TemplateTokenNames returns the supported token names.
+func TemplateTokenNames() []string {
+	return []string{
+		"Title", "Year", "Season", "Episode", "EpisodeTitle", "ImdbID",
+		"TmdbID", "TvdbID", "Resolution", "Source", "Edition", "ReleaseGroup",
+	}
+}
+
+// ValidateTemplate checks tmpl for unknown tokens and stray braces.
+func ValidateTemplate(tmpl string) error {
+	for _, m := range templateTokenRegex.FindAllStringSubmatch(tmpl, -1) {
+		if _, ok := templateTokens[strings.ToLower(m[1])]; !ok {
+			return fmt.Errorf("unknown token {%s} (supported: %s)", m[1], strings.Join(TemplateTokenNames(), ", "))
+		}
+	}
+	rest := templateTokenRegex.ReplaceAllString(tmpl, "")
+	if strings.ContainsAny(rest, "{}") {
+		return fmt.Errorf("unbalanced brace in template %q", tmpl)
+	}
+	if strings.ContainsAny(rest, `/\`) {
+		return fmt.Errorf("template %q must not contain path separators", tmpl)
+	}
+	return nil
+}
+
+// TemplateUses reports whether tmpl contains any of the given tokens
+// (names without braces, case-insensitive).
+func TemplateUses(tmpl string, tokens ...string) bool {
+	for _, m := range templateTokenRegex.FindAllStringSubmatch(tmpl, -1) {
+		for _, token := range tokens {
+			if strings.EqualFold(m[1], token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RenderTemplate substitutes v into tmpl, dropping empty optional groups
+// and the separators they leave behind.
+func RenderTemplate(tmpl string, v TemplateValues) (string, error) {
+	if err := ValidateTemplate(tmpl); err != nil {
+		return "", err
+	}
+
+	out := templateGroupRegex.ReplaceAllStringFunc(tmpl, func(group string) string {
+		empty := true
+		rendered := templateTokenRegex.ReplaceAllStringFunc(group, func(tok string) string {
+			val := renderToken(tok, v)
+			if val != "" {
+				empty = false
+			}
+			return val
+		})
+		if empty {
+			return ""
+		}
+		return rendered
+	})
+	out = templateTokenRegex.ReplaceAllStringFunc(out, func(tok string) string {
+		return renderToken(tok, v)
+	})
+
+	out = templateDashRunRegex.ReplaceAllString(out, " - ")
+	out = templateDashGroupRegex.ReplaceAllString(out, " $1")
+	out = templateSpaceRegex.ReplaceAllString(out, " ")
+	out = strings.Trim(out, " -")
+	if out == "" {
+		return "", fmt.Errorf("template %q rendered an empty name", tmpl)
+	}
+	return out, nil
+}
+
+func renderToken(tok string, v TemplateValues) string {
+	name := strings.ToLower(strings.Trim(tok, "{}"))
+	val := strings.TrimSpace(templateTokens[name](v))
+	// Values become path components; a separator would nest directories.
+	return strings.NewReplacer("/", "-", `\`, "-").Replace(val)
+}
+
+// IsZero reports whether no template is set.
+func (t Templates) IsZero() bool {
+	return t == Templates{}
+}
+
+// WithDefaults returns t with empty fields set to the default templates.
+func (t Templates) WithDefaults() Templates {
+	if t.MovieFolder == "" {
+		t.MovieFolder = DefaultMovieFolderTemplate
+	}
+	if t.MovieFile == "" {
+		t.MovieFile = DefaultMovieFileTemplate
+	}
+	if t.SeriesFolder == "" {
+		t.SeriesFolder = DefaultSeriesFolderTemplate
+	}
+	if t.SeasonFolder == "" {
+		t.SeasonFolder = DefaultSeasonFolderTemplate
+	}
+	if t.EpisodeFile == "" {
+		t.EpisodeFile = DefaultEpisodeFileTemplate
+	}
+	return t
+}
+
+// Validate checks every set template and that each can still identify
+// what it names: titles for folders and movies, season and episode for
+// episode files.
+func (t Templates) Validate() error {
+	checks := []struct {
+		name     string
+		tmpl     string
+		required []string
+	}{
+		{"movie_folder_template", t.MovieFolder, []string{"Title"}},
+		{"movie_file_template", t.MovieFile, []string{"Title"}},
+		{"series_folder_template", t.SeriesFolder, []string{"Title"}},
+		{"season_folder_template", t.SeasonFolder, []string{"Season"}},
+		{"episode_file_template", t.EpisodeFile, []string{"Season", "Episode"}},
+	}
+	for _, c := range checks {
+		if c.tmpl == "" {
+			continue
+		}
+		if err := ValidateTemplate(c.tmpl); err != nil {
+			return fmt.Errorf("%s: %w", c.name, err)
+		}
+		for _, token := range c.required {
+			if !TemplateUses(c.tmpl, token) {
+				return fmt.Errorf("%s: must contain {%s}", c.name, token)
+			}
+		}
+	}
+	return nil
+}
+
+// render renders tmpl, falling back to def if tmpl fails. Configured
+// templates are validated at load, so the fallback only guards bad input
+// from callers that skipped Validate.
+func render(tmpl, def string, v TemplateValues) string {
+	if out, err := RenderTemplate(tmpl, v); err == nil {
+		return out
+	}
+	out, _ := RenderTemplate(def, v)
+	return out
+}
+
+// MovieFolderName renders the movie folder for movie. extra supplies the
+// values that don't come from the parsed name (IDs, quality).
+func (t Templates) MovieFolderName(movie *MovieInfo, extra TemplateValues) string {
+	t = t.WithDefaults()
+	return render(t.MovieFolder, DefaultMovieFolderTemplate, movieValues(movie, extra))
+}
+
+// MovieFileName renders the movie file name, with extension. Parts of a
+// stacked movie get Jellyfin's " - partN" label after the template.
+func (t Templates) MovieFileName(movie *MovieInfo, extra TemplateValues, ext string) string {
+	t = t.WithDefaults()
+	name := render(t.MovieFile, DefaultMovieFileTemplate, movieValues(movie, extra))
+	if movie.Part > 0 {
+		name += " - " + FormatStackPart(movie.Part)
+	}
+	return name + "." + strings.TrimPrefix(ext, ".")
+}
+
+// SeriesFolderName renders the show folder for tv.
+func (t Templates) SeriesFolderName(tv *TVShowInfo, extra TemplateValues) string {
+	t = t.WithDefaults()
+	v := tvValues(tv, extra)
+	v.Title = titleCaseWithOrdinals(v.Title)
+	return render(t.SeriesFolder, DefaultSeriesFolderTemplate, v)
+}
+
+// SeasonFolderName renders the folder for season.
+func (t Templates) SeasonFolderName(season int) string {
+	t = t.WithDefaults()
+	return render(t.SeasonFolder, DefaultSeasonFolderTemplate, TemplateValues{Season: season})
+}
+
+// EpisodeFileName renders the episode file name, with extension. Dated
+// episodes and unnumbered specials have no season/episode to template and
+// keep the FormatTVEpisodeFilenameFromInfo form.
+func (t Templates) EpisodeFileName(tv *TVShowInfo, extra TemplateValues, ext string) string {
+	ext = strings.TrimPrefix(ext, ".")
+	if tv.EpisodeDate != "" || (tv.IsSpecial() && tv.Episode <= 0) {
+		return FormatTVEpisodeFilenameFromInfo(tv, ext)
+	}
+	t = t.WithDefaults()
+	return render(t.EpisodeFile, DefaultEpisodeFileTemplate, tvValues(tv, extra)) + "." + ext
+}
+
+// MatchesEpisodeFileName reports whether filename is what EpisodeFileName
+// renders for tv. When extra has no episode title, any title (or none) is
+// accepted in the {EpisodeTitle} slot, since it can't be derived from the
+// file name alone.
+func (t Templates) MatchesEpisodeFileName(filename string, tv *TVShowInfo, extra TemplateValues) bool {
+	ext := filepath.Ext(filename)
+	if filename == t.EpisodeFileName(tv, extra, ext) {
+		return true
+	}
+	if extra.EpisodeTitle != "" || !TemplateUses(t.WithDefaults().EpisodeFile, "EpisodeTitle") {
+		return false
+	}
+	// Render with a marker in the title slot and match around it.
+	const marker = "\x00"
+	extra.EpisodeTitle = marker
+	rendered := t.EpisodeFileName(tv, extra, ext)
+	i := strings.Index(rendered, marker)
+	if i < 0 {
+		return false
+	}
+	prefix, suffix := rendered[:i], rendered[i+len(marker):]
+	return len(filename) > len(prefix)+len(suffix) &&
+		strings.HasPrefix(filename, prefix) && strings.HasSuffix(filename, suffix)
+}
+
+func movieValues(movie *MovieInfo, extra TemplateValues) TemplateValues {
+	v := extra
+	v.Title = titleCaseWithOrdinals(movie.Title)
+	v.Year = movie.Year
+	v.Edition = movie.Edition
+	return v
+}
+
+func tvValues(tv *TVShowInfo, extra TemplateValues) TemplateValues {
+	v := extra
+	v.Title = tv.Title
+	v.Year = tv.Year
+	v.Season = tv.Season
+	v.Episode = tv.Episode
+	v.EpisodeEnd = tv.EpisodeEnd
+	return v
+}
+
+// FillRelease sets Resolution, Source and ReleaseGroup from a release
+// file name, leaving fields it can't determine empty.
+func (v *TemplateValues) FillRelease(filename string) {
+	q := quality.Parse(filepath.Base(filename))
+	if r := quality.ResolutionToString(q.Resolution); r != "unknown" {
+		v.Resolution = r
+	}
+	if s := quality.SourceToString(q.Source); s != "unknown" {
+		v.Source = s
+	}
+	v.ReleaseGroup = ReleaseGroup(filename)
+}
+
+// FillProviderIDs copies provider ID tags found in name (a file name or
+// path) into fields that are still empty.
+func (v *TemplateValues) FillProviderIDs(name string) {
+	for _, m := range providerIDRegex.FindAllStringSubmatch(name, -1) {
+		id := m[2]
+		switch strings.ToLower(m[1]) {
+		case "imdb":
+			if v.ImdbID == "" {
+				v.ImdbID = id
+			}
+		case "tmdb":
+			if v.TmdbID == "" {
+				v.TmdbID = id
+			}
+		case "tvdb":
+			if v.TvdbID == "" {
+				v.TvdbID = id
+			}
+		}
+	}
+}
+
+// TemplateValuesFromPath reads release details and provider IDs back from
+// an organized file's name and folders: the values a parsed name doesn't
+// carry, so templated names can be checked for layout rather than values.
+func TemplateValuesFromPath(path string) TemplateValues {
+	var v TemplateValues
+	v.FillRelease(path)
+	v.FillProviderIDs(path)
+	return v
+}
+
+// StripProviderIDs removes Jellyfin provider ID tags from a folder or file
+// name: "Show (2020) [tvdbid-12345]" → "Show (2020)".
+func StripProviderIDs(name string) string {
+	return strings.TrimSpace(providerIDRegex.ReplaceAllString(name, ""))
+}
+
+// ReleaseGroup returns the release group of a scene or fansub release
+// ("Movie.2001.1080p.BluRay.x264-SPARKS" → "SPARKS"), or "" if none.
+func ReleaseGroup(filename string) string {
+	baseName := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	if IsAnimeFilename(baseName) {
+		if group := animeGroup(baseName); group != "" {
+			return group
+		}
+	}
+	m := releaseGroupRegex.FindStringSubmatch(baseName)
+	if m == nil || releaseGroupFalsePositives[strings.ToLower(m[1])] {
+		return ""
+	}
+	// Hyphenated titles ("Spider-Man") end in a lower-case word; groups
+	// are upper-case or known.
+	if m[1] != strings.ToUpper(m[1]) && !IsKnownReleaseGroup(strings.ToLower(m[1])) {
+		return ""
+	}
+	return m[1]
+}
@@ -0,0 +1,139 @@
+package naming
+
+import "testing"
+
+func TestRenderTemplate(t *testing.T) {
+	full := TemplateValues{
+		Title: "The Matrix", Year: "1999", ImdbID: "tt0133093",
+		Resolution: "1080p", Source: "BluRay", ReleaseGroup: "SPARKS",
+	}
+	tests := []struct {
+		name string
+		tmpl string
+		v    TemplateValues
+		want string
+	}{
+		{"default movie", DefaultMovieFolderTemplate, full, "The Matrix (1999)"},
+		{"provider id", "{Title} ({Year}) [imdbid-{ImdbID}]", full, "The Matrix (1999) [imdbid-tt0133093]"},
+		{"missing id drops group", "{Title} ({Year}) [imdbid-{ImdbID}]", TemplateValues{Title: "The Matrix", Year: "1999"}, "The Matrix (1999)"},
+		{"missing year drops group", "{Title} ({Year})", TemplateValues{Title: "The Matrix"}, "The Matrix"},
+		{"quality suffix", "{Title} ({Year}) [{Resolution} {Source}]-{ReleaseGroup}", full, "The Matrix (1999) [1080p BluRay]-SPARKS"},
+		{"empty edition separator", DefaultMovieFileTemplate, full, "The Matrix (1999)"},
+		{"edition", DefaultMovieFileTemplate, TemplateValues{Title: "Blade Runner", Year: "1982", Edition: "Director's Cut"}, "Blade Runner (1982) - Director's Cut"},
+		{"episode span", "{Title} S{Season}E{Episode}", TemplateValues{Title: "Show", Season: 1, Episode: 1, EpisodeEnd: 3}, "Show S01E01-E03"},
+		{"empty episode title", "{Title} S{Season}E{Episode} - {EpisodeTitle} [{Resolution}]", TemplateValues{Title: "Show", Season: 2, Episode: 5, Resolution: "720p"}, "Show S02E05 [720p]"},
+		{"episode title", "{Title} S{Season}E{Episode} - {EpisodeTitle}", TemplateValues{Title: "Show", Season: 2, Episode: 5, EpisodeTitle: "Pilot"}, "Show S02E05 - Pilot"},
+		{"case-insensitive tokens", "{title} ({YEAR})", full, "The Matrix (1999)"},
+		{"separator in value", "{Title}", TemplateValues{Title: "AC/DC"}, "AC-DC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderTemplate(tt.tmpl, tt.v)
+			if err != nil {
+				t.Fatalf("RenderTemplate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderTemplate(%q) = %q, want %q", tt.tmpl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplatesValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       Templates
+		wantErr bool
+	}{
+		{"empty", Templates{}, false},
+		{"valid", Templates{MovieFolder: "{Title} ({Year}) [imdbid-{ImdbID}]", EpisodeFile: "{Title} S{Season}E{Episode} [{Resolution}]"}, false},
+		{"unknown token", Templates{MovieFile: "{Title} {Quality}"}, true},
+		{"stray brace", Templates{MovieFile: "{Title} {Year"}, true},
+		{"path separator", Templates{MovieFolder: "{Title}/{Year}"}, true},
+		{"episode without episode", Templates{EpisodeFile: "{Title} S{Season}"}, true},
+		{"season folder without season", Templates{SeasonFolder: "Season"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.t.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTemplatesDefaultsMatchFixedLayout(t *testing.T) {
+	var tmpl Templates
+	movie := &MovieInfo{Title: "The Matrix", Year: "1999"}
+	if got, want := tmpl.MovieFolderName(movie, TemplateValues{}), NormalizeMediaName("The Matrix", "1999"); got != want {
+		t.Errorf("MovieFolderName() = %q, want %q", got, want)
+	}
+	if got, want := tmpl.MovieFileName(movie, TemplateValues{}, "mkv"), FormatMovieFilename("The Matrix", "1999", "mkv"); got != want {
+		t.Errorf("MovieFileName() = %q, want %q", got, want)
+	}
+	tv := &TVShowInfo{Title: "Breaking Bad", Year: "2008", Season: 1, Episode: 2, EpisodeEnd: 3}
+	if got, want := tmpl.EpisodeFileName(tv, TemplateValues{}, ".mkv"), FormatTVEpisodeFilenameFromInfo(tv, "mkv"); got != want {
+		t.Errorf("EpisodeFileName() = %q, want %q", got, want)
+	}
+	if got, want := tmpl.SeasonFolderName(0), FormatSeasonFolder(0); got != want {
+		t.Errorf("SeasonFolderName(0) = %q, want %q", got, want)
+	}
+
+	special := &TVShowInfo{Title: "Doctor Who", Year: "2005", Season: 0, SpecialTitle: "Christmas Special 2023"}
+	custom := Templates{EpisodeFile: "{Title} S{Season}E{Episode} [{Resolution}]"}
+	if got, want := custom.EpisodeFileName(special, TemplateValues{}, "mkv"), "Doctor Who (2005) - Christmas Special 2023.mkv"; got != want {
+		t.Errorf("unnumbered special = %q, want %q", got, want)
+	}
+}
+
+func TestTemplatesMatchesEpisodeFileName(t *testing.T) {
+	tmpl := Templates{EpisodeFile: "{Title} ({Year}) S{Season}E{Episode} - {EpisodeTitle} [{Resolution}]"}
+	tv := &TVShowInfo{Title: "Show", Year: "2020", Season: 1, Episode: 2}
+	extra := TemplateValues{Resolution: "1080p"}
+
+	for name, want := range map[string]bool{
+		"Show (2020) S01E02 - Pilot [1080p].mkv":  true,
+		"Show (2020) S01E02 [1080p].mkv":          true,
+		"Show (2020) S01E02 - Pilot [720p].mkv":   false,
+		"Show (2020) S01E03 - Pilot [1080p].mkv":  false,
+		"Show (2020) S01E02 - Pilot.1080p.mkv":    false,
+		"Other (2020) S01E02 - Pilot [1080p].mkv": false,
+	} {
+		if got := tmpl.MatchesEpisodeFileName(name, tv, extra); got != want {
+			t.Errorf("MatchesEpisodeFileName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestProviderIDs(t *testing.T) {
+	var v TemplateValues
+	v.FillProviderIDs("/tv/Show (2020) [tvdbid-12345]/Season 01/Show (2020) {imdb-tt7654321} S01E01.mkv")
+	if v.TvdbID != "12345" || v.ImdbID != "tt7654321" || v.TmdbID != "" {
+		t.Errorf("FillProviderIDs() = %+v", v)
+	}
+	if got := StripProviderIDs("Show (2020) [tvdbid-12345]"); got != "Show (2020)" {
+		t.Errorf("StripProviderIDs() = %q", got)
+	}
+}
+
+func TestTemplateValuesFromPath(t *testing.T) {
+	v := TemplateValuesFromPath("/movies/The Matrix (1999) [tmdbid-603]/The Matrix (1999) - 2160p.mkv")
+	if v.Resolution != "2160p" || v.TmdbID != "603" {
+		t.Errorf("TemplateValuesFromPath() = %+v", v)
+	}
+}
+
+func TestReleaseGroup(t *testing.T) {
+	tests := map[string]string{
+		"The.Matrix.1999.1080p.BluRay.x264-SPARKS.mkv": "SPARKS",
+		"Show.S01E01.1080p.WEB-DL.mkv":                 "",
+		"Spider-Man.2002.mkv":                          "",
+		"[SubsPlease] Frieren - 12 (1080p).mkv":        "SubsPlease",
+		"The Matrix (1999).mkv":                        "",
+	}
+	for input, want := range tests {
+		if got := ReleaseGroup(input); got != want {
+			t.Errorf("ReleaseGroup(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
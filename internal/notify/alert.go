@@ -0,0 +1,279 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AlertKind names a user-facing event that alert targets can subscribe to.
+type AlertKind string
+
+const (
+	AlertOrganized        AlertKind = "organized"
+	AlertFailed           AlertKind = "failed"
+	AlertNeedsReview      AlertKind = "needs-review"
+	AlertDuplicateDeleted AlertKind = "duplicate-deleted"
+	AlertDiskUnhealthy    AlertKind = "disk-unhealthy"
+)
+
+// AlertKinds lists every kind in the order digests report them.
+var AlertKinds = []AlertKind{AlertOrganized, AlertFailed, AlertNeedsReview, AlertDuplicateDeleted, AlertDiskUnhealthy}
+
+// ParseAlertKind accepts a kind name with "-" or "_" separators.
+func ParseAlertKind(s string) (AlertKind, error) {
+	k := AlertKind(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), "_", "-"))
+	for _, known := range AlertKinds {
+		if k == known {
+			return k, nil
+		}
+	}
+	return "", fmt.Errorf("unknown event %q", s)
+}
+
+// Alert is one user-facing event: a file organized, an organize that
+// failed, a decision waiting for a human, a duplicate deleted, a library
+// disk gone unhealthy.
+type Alert struct {
+	Kind    AlertKind `json:"kind"`
+	Title   string    `json:"title"`
+	Message string    `json:"message"`
+	Path    string    `json:"path,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// Sender delivers alerts to one service. A batch holds a single alert
+// unless the target collects a digest or is being rate limited.
+type Sender interface {
+	Name() string
+	Send(ctx context.Context, alerts []Alert) error
+}
+
+// Route configures one alert target.
+type Route struct {
+	URL        string        // service URL, see NewSender
+	Events     []AlertKind   // kinds to deliver; empty means all
+	Digest     time.Duration // collect alerts for this long and send them together
+	MaxPerHour int           // sends allowed per rolling hour; 0 is unlimited
+	Template   string        // text/template body for webhook targets
+}
+
+// sendTimeout bounds one delivery so a hung service can't hold a digest.
+const sendTimeout = 30 * time.Second
+
+// Alerter fans alerts out to every route that subscribes to them. Alert
+// never blocks: delivery happens on timers, and alerts that arrive while
+// a route is over its rate limit are held and sent as one digest once it
+// has budget again.
+type Alerter struct {
+	routes []*alertRoute
+}
+
+// NewAlerter builds an alerter for routes, failing on URLs or event names
+// it can't use.
+func NewAlerter(routes []Route) (*Alerter, error) {
+	a := &Alerter{}
+	for i, r := range routes {
+		sender, err := NewSender(r.URL, r.Template)
+		if err != nil {
+			return nil, fmt.Errorf("notification %d: %w", i+1, err)
+		}
+		a.routes = append(a.routes, newAlertRoute(sender, r))
+	}
+	return a, nil
+}
+
+// RouteCount returns the number of configured targets.
+func (a *Alerter) RouteCount() int {
+	if a == nil {
+		return 0
+	}
+	return len(a.routes)
+}
+
+// Alert queues al for every route subscribed to its kind. A nil Alerter
+// drops it.
+func (a *Alerter) Alert(al Alert) {
+	if a == nil {
+		return
+	}
+	if al.Time.IsZero() {
+		al.Time = time.Now()
+	}
+	for _, r := range a.routes {
+		r.add(al)
+	}
+}
+
+// Close sends whatever is still queued, ignoring digest windows and rate
+// limits, and stops accepting alerts.
+func (a *Alerter) Close() {
+	if a == nil {
+		return
+	}
+	var wg sync.WaitGroup
+	for _, r := range a.routes {
+		wg.Add(1)
+		go func(r *alertRoute) {
+			defer wg.Done()
+			r.close()
+		}(r)
+	}
+	wg.Wait()
+}
+
+type alertRoute struct {
+	sender     Sender
+	kinds      map[AlertKind]bool
+	digest     time.Duration
+	maxPerHour int
+
+	mu      sync.Mutex
+	pending []Alert
+	timer   *time.Timer
+	sent    []time.Time // sends within the last hour
+	closed  bool
+}
+
+func newAlertRoute(sender Sender, r Route) *alertRoute {
+	route := &alertRoute{
+		sender:     sender,
+		digest:     r.Digest,
+		maxPerHour: r.MaxPerHour,
+	}
+	if len(r.Events) > 0 {
+		route.kinds = make(map[AlertKind]bool, len(r.Events))
+		for _, k := range r.Events {
+			route.kinds[k] = true
+		}
+	}
+	return route
+}
+
+func (r *alertRoute) add(al Alert) {
+	if r.kinds != nil && !r.kinds[al.Kind] {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	r.pending = append(r.pending, al)
+	if r.timer == nil {
+		delay := r.digest
+		if wait := r.limitWait(time.Now()); wait > delay {
+			delay = wait
+		}
+		r.timer = time.AfterFunc(delay, r.flush)
+	}
+}
+
+func (r *alertRoute) flush() {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	if wait := r.limitWait(now); wait > 0 {
+		r.timer = time.AfterFunc(wait, r.flush)
+		r.mu.Unlock()
+		return
+	}
+	batch := r.pending
+	r.pending = nil
+	r.timer = nil
+	r.sent = append(r.sent, now)
+	r.mu.Unlock()
+
+	r.deliver(batch)
+}
+
+func (r *alertRoute) close() {
+	r.mu.Lock()
+	r.closed = true
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+	batch := r.pending
+	r.pending = nil
+	r.mu.Unlock()
+
+	r.deliver(batch)
+}
+
+// limitWait returns how long the route must wait before its next send.
+// Callers hold r.mu.
+func (r *alertRoute) limitWait(now time.Time) time.Duration {
+	cutoff := now.Add(-time.Hour)
+	for len(r.sent) > 0 && !r.sent[0].After(cutoff) {
+		r.sent = r.sent[1:]
+	}
+	if r.maxPerHour <= 0 || len(r.sent) < r.maxPerHour {
+		return 0
+	}
+	return r.sent[0].Sub(cutoff)
+}
+
+func (r *alertRoute) deliver(batch []Alert) {
+	if len(batch) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+	if err := r.sender.Send(ctx, batch); err != nil {
+		log.Printf("[%s] Alert delivery failed (%d events): %v", r.sender.Name(), len(batch), err)
+	}
+}
+
+// maxDigestLines caps how many alerts a digest message lists.
+const maxDigestLines = 25
+
+// Summarize renders a batch as a title and a plain-text message: the alert
+// itself for a batch of one, a count by kind and one line per alert for a
+// digest.
+func Summarize(alerts []Alert) (title, message string) {
+	if len(alerts) == 1 {
+		return "jellywatch: " + alerts[0].Title, alerts[0].Message
+	}
+
+	counts := make(map[AlertKind]int)
+	for _, a := range alerts {
+		counts[a.Kind]++
+	}
+	kinds := make([]AlertKind, 0, len(counts))
+	for k := range counts {
+		kinds = append(kinds, k)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kindOrder(kinds[i]) < kindOrder(kinds[j]) })
+	parts := make([]string, len(kinds))
+	for i, k := range kinds {
+		parts[i] = fmt.Sprintf("%d %s", counts[k], k)
+	}
+	title = fmt.Sprintf("jellywatch: %d events (%s)", len(alerts), strings.Join(parts, ", "))
+
+	var b strings.Builder
+	for i, a := range alerts {
+		if i == maxDigestLines {
+			fmt.Fprintf(&b, "… and %d more\n", len(alerts)-i)
+			break
+		}
+		fmt.Fprintf(&b, "[%s] %s: %s\n", a.Kind, a.Title, a.Message)
+	}
+	return title, strings.TrimRight(b.String(), "\n")
+}
+
+func kindOrder(k AlertKind) int {
+	for i, known := range AlertKinds {
+		if k == known {
+			return i
+		}
+	}
+	return len(AlertKinds)
+}
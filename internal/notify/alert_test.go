@@ -0,0 +1,245 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingSender struct {
+	mu      sync.Mutex
+	batches [][]Alert
+}
+
+func (s *recordingSender) Name() string { return "recording" }
+
+func (s *recordingSender) Send(_ context.Context, alerts []Alert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, alerts)
+	return nil
+}
+
+func (s *recordingSender) snapshot() [][]Alert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]Alert(nil), s.batches...)
+}
+
+func waitForBatches(t *testing.T, s *recordingSender, n int) [][]Alert {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(s.snapshot()) < n && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return s.snapshot()
+}
+
+func testAlerter(sender Sender, r Route) *Alerter {
+	return &Alerter{routes: []*alertRoute{newAlertRoute(sender, r)}}
+}
+
+func TestAlerterFiltersByKind(t *testing.T) {
+	s := &recordingSender{}
+	a := testAlerter(s, Route{Events: []AlertKind{AlertFailed}})
+
+	a.Alert(Alert{Kind: AlertOrganized, Title: "ok"})
+	a.Alert(Alert{Kind: AlertFailed, Title: "broken"})
+
+	got := waitForBatches(t, s, 1)
+	a.Close()
+	if len(got) != 1 || len(got[0]) != 1 || got[0][0].Title != "broken" {
+		t.Fatalf("batches = %+v, want only the failed alert", got)
+	}
+}
+
+func TestAlerterDigestBatchesAlerts(t *testing.T) {
+	s := &recordingSender{}
+	a := testAlerter(s, Route{Digest: 100 * time.Millisecond})
+	defer a.Close()
+
+	for _, title := range []string{"one", "two", "three"} {
+		a.Alert(Alert{Kind: AlertOrganized, Title: title})
+	}
+	got := waitForBatches(t, s, 1)
+	if len(got) != 1 || len(got[0]) != 3 {
+		t.Fatalf("batches = %+v, want one digest of three", got)
+	}
+}
+
+func TestAlerterRateLimitHoldsAlertsUntilClose(t *testing.T) {
+	s := &recordingSender{}
+	a := testAlerter(s, Route{MaxPerHour: 1})
+
+	a.Alert(Alert{Kind: AlertFailed, Title: "first"})
+	waitForBatches(t, s, 1)
+	a.Alert(Alert{Kind: AlertFailed, Title: "second"})
+	a.Alert(Alert{Kind: AlertFailed, Title: "third"})
+	time.Sleep(50 * time.Millisecond)
+	if got := s.snapshot(); len(got) != 1 {
+		t.Fatalf("batches before close = %d, want 1 (rest held by the limit)", len(got))
+	}
+
+	a.Close()
+	got := s.snapshot()
+	if len(got) != 2 || len(got[1]) != 2 {
+		t.Fatalf("batches after close = %+v, want held alerts sent together", got)
+	}
+	a.Alert(Alert{Kind: AlertFailed, Title: "late"})
+	if len(s.snapshot()) != 2 {
+		t.Fatal("alert accepted after Close")
+	}
+}
+
+func TestManagerNotifyFeedsAlerter(t *testing.T) {
+	s := &recordingSender{}
+	mgr := NewManager(false)
+	mgr.SetAlerter(testAlerter(s, Route{}))
+
+	mgr.Notify(OrganizationEvent{MediaType: MediaTypeMovie, Title: "Heat", Year: "1995", TargetPath: "/m/Heat (1995)/Heat (1995).mkv"})
+	mgr.Alert(Alert{Kind: AlertDiskUnhealthy, Title: "Library unhealthy"})
+	mgr.Close()
+
+	kinds := map[AlertKind]string{}
+	for _, batch := range s.snapshot() {
+		for _, al := range batch {
+			kinds[al.Kind] = al.Title
+		}
+	}
+	if kinds[AlertOrganized] != "Organized Movie: Heat (1995)" || kinds[AlertDiskUnhealthy] == "" {
+		t.Fatalf("alerts = %+v, want organized and disk-unhealthy", kinds)
+	}
+}
+
+func TestSummarizeDigest(t *testing.T) {
+	title, message := Summarize([]Alert{
+		{Kind: AlertFailed, Title: "a", Message: "x"},
+		{Kind: AlertOrganized, Title: "b", Message: "y"},
+		{Kind: AlertOrganized, Title: "c", Message: "z"},
+	})
+	if title != "jellywatch: 3 events (2 organized, 1 failed)" {
+		t.Errorf("title = %q", title)
+	}
+	if message != "[failed] a: x\n[organized] b: y\n[organized] c: z" {
+		t.Errorf("message = %q", message)
+	}
+}
+
+func TestNewSenderEndpoints(t *testing.T) {
+	for _, tc := range []struct {
+		url  string
+		want string
+	}{
+		{"discord://123/abc", "https://discord.com/api/webhooks/123/abc"},
+		{"ntfy://jellywatch", "https://ntfy.sh/jellywatch"},
+		{"ntfys://ntfy.example.com/media", "https://ntfy.example.com/media"},
+		{"ntfy://10.0.0.2:8080/media", "http://10.0.0.2:8080/media"},
+		{"gotifys://push.example.com/sub/TOKEN", "https://push.example.com/sub/message"},
+		{"jsons://example.com/hook?x=1", "https://example.com/hook?x=1"},
+	} {
+		s, err := NewSender(tc.url, "")
+		if err != nil {
+			t.Errorf("NewSender(%q): %v", tc.url, err)
+			continue
+		}
+		req, err := s.(*httpSender).build(context.Background(), []Alert{{Kind: AlertFailed, Title: "t"}})
+		if err != nil {
+			t.Errorf("build(%q): %v", tc.url, err)
+			continue
+		}
+		if got := req.URL.String(); got != tc.want {
+			t.Errorf("NewSender(%q) endpoint = %s, want %s", tc.url, got, tc.want)
+		}
+	}
+
+	for _, bad := range []string{"discord://123", "gotify://host", "mailto://smtp.example.com", "irc://x", "no-scheme"} {
+		if _, err := NewSender(bad, ""); err == nil {
+			t.Errorf("NewSender(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestWebhookSenderTemplate(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer srv.Close()
+
+	s, err := NewSender(srv.URL, `{"text": {{json .Title}}, "n": {{len .Alerts}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send(context.Background(), []Alert{{Kind: AlertFailed, Title: `Say "hi"`}}); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Text string `json:"text"`
+		N    int    `json:"n"`
+	}
+	if err := json.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatalf("body %q is not JSON: %v", body, err)
+	}
+	if got.Text != `jellywatch: Say "hi"` || got.N != 1 {
+		t.Fatalf("body = %+v", got)
+	}
+}
+
+func TestNtfySenderHeaders(t *testing.T) {
+	var title, tags, auth, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		title, tags, auth = r.Header.Get("Title"), r.Header.Get("Tags"), r.Header.Get("Authorization")
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer srv.Close()
+
+	s, err := NewSender("ntfy://tk_secret@"+strings.TrimPrefix(srv.URL, "http://")+"/media", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Send(context.Background(), []Alert{{Kind: AlertNeedsReview, Title: "Review", Message: "Heat.mkv"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title != "jellywatch: Review" || tags != "needs-review" || auth != "Bearer tk_secret" || body != "Heat.mkv" {
+		t.Fatalf("title=%q tags=%q auth=%q body=%q", title, tags, auth, body)
+	}
+}
+
+func TestHTTPSenderReportsStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad token", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	s, err := NewSender(srv.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send(context.Background(), []Alert{{Kind: AlertFailed}}); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("Send() error = %v, want 401", err)
+	}
+}
+
+func TestMailSenderMessage(t *testing.T) {
+	s, err := NewSender("mailtos://alerts@example.com:pw@smtp.example.com/?to=a@example.com,b@example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := s.(*mailSender)
+	if m.addr != "smtp.example.com:465" || !m.implicit || m.from != "alerts@example.com" || len(m.to) != 2 {
+		t.Fatalf("mail sender = %+v", m)
+	}
+	msg := string(m.message("jellywatch: Organized", "line one\nline two", time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)))
+	if !strings.Contains(msg, "Subject: jellywatch: Organized\r\n") || !strings.HasSuffix(msg, "line one\r\nline two\r\n") {
+		t.Fatalf("message = %q", msg)
+	}
+}
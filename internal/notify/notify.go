@@ -38,6 +38,7 @@ type OrganizationEvent struct {
 	Year        string
 	Season      int
 	Episode     int
+	EpisodeEnd  int // last episode of a multi-episode file, 0 otherwise
 	BytesCopied int64
 	Duration    time.Duration
 }
@@ -73,6 +74,7 @@ type Manager struct {
 	mu        sync.RWMutex
 	async     bool
 	results   chan *NotifyResult
+	alerter   *Alerter // user-facing alert targets; nil disables them
 }
 
 // NewManager creates a new notification manager
@@ -97,13 +99,40 @@ func (m *Manager) Register(n Notifier) {
 	}
 }
 
+// SetAlerter attaches user-facing alert targets. Organized files reach
+// them through Notify; other events through Alert.
+func (m *Manager) SetAlerter(a *Alerter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.alerter = a
+}
+
+// Alert passes a to the alert targets, if any are attached.
+func (m *Manager) Alert(a Alert) {
+	if m == nil {
+		return
+	}
+	m.mu.RLock()
+	alerter := m.alerter
+	m.mu.RUnlock()
+	alerter.Alert(a)
+}
+
 // Notify sends notifications to all registered providers
 func (m *Manager) Notify(event OrganizationEvent) []*NotifyResult {
 	m.mu.RLock()
 	notifiers := make([]Notifier, len(m.notifiers))
 	copy(notifiers, m.notifiers)
+	alerter := m.alerter
 	m.mu.RUnlock()
 
+	alerter.Alert(Alert{
+		Kind:    AlertOrganized,
+		Title:   "Organized " + FormatEventSummary(event),
+		Message: event.TargetPath,
+		Path:    event.TargetPath,
+	})
+
 	if len(notifiers) == 0 {
 		return nil
 	}
@@ -190,8 +219,12 @@ func (m *Manager) NotifierCount() int {
 	return len(m.notifiers)
 }
 
-// Close cleans up the manager
+// Close cleans up the manager, sending any alerts still queued.
 func (m *Manager) Close() {
+	m.mu.RLock()
+	alerter := m.alerter
+	m.mu.RUnlock()
+	alerter.Close()
 	if m.results != nil {
 		close(m.results)
 	}
@@ -203,6 +236,9 @@ func FormatEventSummary(event OrganizationEvent) string {
 	case MediaTypeMovie:
 		return fmt.Sprintf("Movie: %s (%s)", event.Title, event.Year)
 	case MediaTypeTVEpisode:
+		if event.EpisodeEnd > event.Episode {
+			return fmt.Sprintf("TV: %s S%02dE%02d-E%02d", event.Title, event.Season, event.Episode, event.EpisodeEnd)
+		}
 		return fmt.Sprintf("TV: %s S%02dE%02d", event.Title, event.Season, event.Episode)
 	default:
 		return fmt.Sprintf("Unknown: %s", event.Title)
@@ -123,6 +123,10 @@ func TestFormatEventSummary(t *testing.T) {
 			OrganizationEvent{MediaType: MediaTypeTVEpisode, Title: "Breaking Bad", Season: 1, Episode: 5},
 			"TV: Breaking Bad S01E05",
 		},
+		{
+			OrganizationEvent{MediaType: MediaTypeTVEpisode, Title: "Breaking Bad", Season: 1, Episode: 5, EpisodeEnd: 6},
+			"TV: Breaking Bad S01E05-E06",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,83 @@
+package organizer
+
+import (
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+)
+
+// AbsoluteResolver maps an anime absolute episode number to the season and
+// episode the series is filed under. sonarr.AbsoluteResolver implements it.
+type AbsoluteResolver interface {
+	ResolveAbsolute(title string, absolute int) (season, episode int, err error)
+}
+
+// WithAbsoluteResolver translates absolute episode numbers (fansub
+// "Show - 123" releases, "EP123") into season/episode before organizing.
+// Without a resolver such files keep the season 1 placeholder.
+func WithAbsoluteResolver(r AbsoluteResolver) func(*Organizer) {
+	return func(o *Organizer) {
+		o.absResolver = r
+	}
+}
+
+// WithAnimeLibrary marks libraryPath as an anime library. Files organized
+// into it are parsed with the anime parser first, which also accepts
+// absolute numbering without a leading [Group] tag.
+func WithAnimeLibrary(libraryPath string) func(*Organizer) {
+	return func(o *Organizer) {
+		if strings.TrimSpace(libraryPath) == "" {
+			return
+		}
+		if o.animeLibs == nil {
+			o.animeLibs = make(map[string]bool)
+		}
+		o.animeLibs[filepath.Clean(libraryPath)] = true
+	}
+}
+
+func (o *Organizer) isAnimeLibrary(libraryPath string) bool {
+	return o.animeLibs[filepath.Clean(libraryPath)]
+}
+
+// parseTVForLibrary parses sourcePath for organizing into libraryPath,
+// preferring the anime parser for anime libraries.
+func (o *Organizer) parseTVForLibrary(sourcePath, libraryPath string) (*naming.TVShowInfo, error) {
+	if o.isAnimeLibrary(libraryPath) {
+		if anime, err := naming.ParseAnimeName(filepath.Base(sourcePath)); err == nil {
+			return &anime.TVShowInfo, nil
+		}
+	}
+	return naming.ParseTVShowFromPath(sourcePath)
+}
+
+// resolveAbsolute rewrites tv's season/episode from its absolute number.
+// Failures are logged and leave the placeholder numbering in place.
+func (o *Organizer) resolveAbsolute(tv *naming.TVShowInfo) {
+	if tv.AbsoluteEpisode <= 0 || o.absResolver == nil {
+		return
+	}
+	span := tv.LastEpisode() - tv.Episode
+
+	season, episode, err := o.absResolver.ResolveAbsolute(tv.Title, tv.AbsoluteEpisode)
+	if err != nil {
+		log.Printf("[organizer] absolute episode %d of %q not mapped, keeping S%02dE%02d: %v",
+			tv.AbsoluteEpisode, tv.Title, tv.Season, tv.Episode, err)
+		return
+	}
+	tv.Season, tv.Episode, tv.EpisodeEnd = season, episode, 0
+
+	if span > 0 {
+		endSeason, endEpisode, err := o.absResolver.ResolveAbsolute(tv.Title, tv.AbsoluteEpisode+span)
+		if err == nil && endSeason == season && endEpisode > episode {
+			tv.EpisodeEnd = endEpisode
+		} else {
+			log.Printf("[organizer] absolute span %d-%d of %q does not map to one season; filing first episode only",
+				tv.AbsoluteEpisode, tv.AbsoluteEpisode+span, tv.Title)
+		}
+	}
+	log.Printf("[organizer] absolute episode %d of %q mapped to %s",
+		tv.AbsoluteEpisode, tv.Title, naming.FormatEpisodeTag(tv.Season, tv.Episode, tv.EpisodeEnd))
+}
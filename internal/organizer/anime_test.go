@@ -0,0 +1,75 @@
+package organizer
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+	"github.com/Nomadcxx/jellywatch/internal/transfer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAbsoluteResolver maps absolute numbers through a fixed table.
+type fakeAbsoluteResolver map[int][2]int
+
+func (f fakeAbsoluteResolver) ResolveAbsolute(title string, absolute int) (int, int, error) {
+	se, ok := f[absolute]
+	if !ok {
+		return 0, 0, fmt.Errorf("absolute %d unknown", absolute)
+	}
+	return se[0], se[1], nil
+}
+
+func TestResolveAbsolute(t *testing.T) {
+	org, err := NewOrganizer([]string{t.TempDir()}, WithAbsoluteResolver(fakeAbsoluteResolver{
+		29: {2, 1},
+		30: {2, 2},
+		28: {1, 28},
+	}))
+	require.NoError(t, err)
+
+	tv := naming.TVShowInfo{Title: "Frieren", Season: 1, Episode: 29, AbsoluteEpisode: 29}
+	org.resolveAbsolute(&tv)
+	assert.Equal(t, "S02E01", naming.FormatEpisodeTag(tv.Season, tv.Episode, tv.EpisodeEnd))
+
+	tv = naming.TVShowInfo{Title: "Frieren", Season: 1, Episode: 29, EpisodeEnd: 30, AbsoluteEpisode: 29}
+	org.resolveAbsolute(&tv)
+	assert.Equal(t, "S02E01-E02", naming.FormatEpisodeTag(tv.Season, tv.Episode, tv.EpisodeEnd))
+
+	// A span crossing a season boundary can't be one file name.
+	tv = naming.TVShowInfo{Title: "Frieren", Season: 1, Episode: 28, EpisodeEnd: 29, AbsoluteEpisode: 28}
+	org.resolveAbsolute(&tv)
+	assert.Equal(t, "S01E28", naming.FormatEpisodeTag(tv.Season, tv.Episode, tv.EpisodeEnd))
+
+	// Unknown numbers keep the season 1 placeholder.
+	tv = naming.TVShowInfo{Title: "Frieren", Season: 1, Episode: 500, AbsoluteEpisode: 500}
+	org.resolveAbsolute(&tv)
+	assert.Equal(t, 1, tv.Season)
+	assert.Equal(t, 500, tv.Episode)
+}
+
+func TestParseTVForLibrary_AnimeLibrary(t *testing.T) {
+	animeLib := t.TempDir()
+	plainLib := t.TempDir()
+	org, err := NewOrganizer([]string{animeLib, plainLib},
+		WithBackend(transfer.BackendNative),
+		WithAnimeLibrary(animeLib+"/"),
+	)
+	require.NoError(t, err)
+
+	src := filepath.Join(t.TempDir(), "Show - 045.mkv")
+
+	tv, err := org.parseTVForLibrary(src, animeLib)
+	require.NoError(t, err)
+	assert.Equal(t, "Show", tv.Title)
+	assert.Equal(t, 45, tv.AbsoluteEpisode)
+
+	// Group-less anime names are episodes everywhere, so a plain library
+	// parses them the same way.
+	tv, err = org.parseTVForLibrary(src, plainLib)
+	require.NoError(t, err)
+	assert.Equal(t, "Show", tv.Title)
+	assert.Equal(t, 45, tv.AbsoluteEpisode)
+}
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strings"
 	"syscall"
+
+	"github.com/Nomadcxx/jellywatch/internal/trash"
 )
 
 // allowedExtensions lists extensions that PurgeNonAllowed will not remove.
@@ -21,9 +23,10 @@ var allowedExtensions = map[string]bool{
 }
 
 // PurgeNonAllowed removes every file whose extension is not in the video/subtitle
-// allowlist from dir and its descendants. Directories are removed only when empty.
-// Unreadable entries are skipped rather than aborting the walk.
-func PurgeNonAllowed(dir string) error {
+// allowlist from dir and its descendants, through bin when it is enabled.
+// Directories are removed only when empty. Unreadable entries are skipped
+// rather than aborting the walk.
+func PurgeNonAllowed(dir string, bin *trash.Bin) error {
 	type entry struct {
 		path  string
 		isDir bool
@@ -57,7 +60,7 @@ func PurgeNonAllowed(dir string) error {
 		}
 		ext := strings.ToLower(filepath.Ext(e.path))
 		if !allowedExtensions[ext] {
-			if err := os.Remove(e.path); err != nil && !ignoreCleanupRemoveError(err) {
+			if _, err := bin.Remove(e.path, "not a video or subtitle"); err != nil && !ignoreCleanupRemoveError(err) {
 				errs = append(errs, err)
 			}
 		}
@@ -14,7 +14,7 @@ func TestPurgeNonAllowed_VideoSurvives(t *testing.T) {
 	for _, name := range []string{"movie.mkv", "episode.mp4", "clip.avi"} {
 		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("data"), 0644))
 	}
-	require.NoError(t, PurgeNonAllowed(dir))
+	require.NoError(t, PurgeNonAllowed(dir, nil))
 	for _, name := range []string{"movie.mkv", "episode.mp4", "clip.avi"} {
 		_, err := os.Stat(filepath.Join(dir, name))
 		assert.NoError(t, err, "%s should survive purge", name)
@@ -26,7 +26,7 @@ func TestPurgeNonAllowed_SubtitleSurvives(t *testing.T) {
 	for _, name := range []string{"movie.srt", "movie.en.srt", "movie.ass", "movie.vtt"} {
 		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("sub"), 0644))
 	}
-	require.NoError(t, PurgeNonAllowed(dir))
+	require.NoError(t, PurgeNonAllowed(dir, nil))
 	for _, name := range []string{"movie.srt", "movie.en.srt", "movie.ass", "movie.vtt"} {
 		_, err := os.Stat(filepath.Join(dir, name))
 		assert.NoError(t, err, "%s should survive purge", name)
@@ -39,7 +39,7 @@ func TestPurgeNonAllowed_JunkRemoved(t *testing.T) {
 	for _, name := range junkFiles {
 		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("junk"), 0644))
 	}
-	require.NoError(t, PurgeNonAllowed(dir))
+	require.NoError(t, PurgeNonAllowed(dir, nil))
 	for _, name := range junkFiles {
 		_, err := os.Stat(filepath.Join(dir, name))
 		assert.True(t, os.IsNotExist(err), "%s should be removed", name)
@@ -52,7 +52,7 @@ func TestPurgeNonAllowed_NestedEmptyDirsRemoved(t *testing.T) {
 	require.NoError(t, os.MkdirAll(sub, 0755))
 	require.NoError(t, os.WriteFile(filepath.Join(sub, "info.nfo"), []byte("x"), 0644))
 
-	require.NoError(t, PurgeNonAllowed(dir))
+	require.NoError(t, PurgeNonAllowed(dir, nil))
 
 	_, err := os.Stat(sub)
 	assert.True(t, os.IsNotExist(err), "empty nested dir should be removed after junk deleted")
@@ -64,13 +64,13 @@ func TestPurgeNonAllowed_NestedDirWithAllowedFilePreserved(t *testing.T) {
 	require.NoError(t, os.MkdirAll(sub, 0755))
 	require.NoError(t, os.WriteFile(filepath.Join(sub, "movie.srt"), []byte("sub"), 0644))
 
-	require.NoError(t, PurgeNonAllowed(dir))
+	require.NoError(t, PurgeNonAllowed(dir, nil))
 
 	_, err := os.Stat(filepath.Join(sub, "movie.srt"))
 	assert.NoError(t, err, "subtitle in nested dir should survive")
 }
 
 func TestPurgeNonAllowed_ReportsWalkErrors(t *testing.T) {
-	err := PurgeNonAllowed(filepath.Join(t.TempDir(), "missing"))
+	err := PurgeNonAllowed(filepath.Join(t.TempDir(), "missing"), nil)
 	assert.Error(t, err)
 }
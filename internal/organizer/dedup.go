@@ -3,51 +3,73 @@ package organizer
 import (
 	"os"
 	"path/filepath"
-	"regexp"
-	"strconv"
 
 	"github.com/Nomadcxx/jellywatch/internal/naming"
 	"github.com/Nomadcxx/jellywatch/internal/video"
 )
 
-var (
-	reSxxExx = regexp.MustCompile(`(?i)[Ss](\d{1,2})[Ee](\d{1,2})`)
-	reNxNN   = regexp.MustCompile(`(\d{1,2})x(\d{2,3})`)
-)
-
 // ExtractEpisodeKey returns the season and episode number encoded in filename.
-// It first delegates to naming.ParseTVShowName (which handles SxxExx, NxNN, and
-// date-based formats).  If that returns a zero season/episode it falls back to
-// a simple SxxExx then NxNN regex so that bare filenames without a title are
-// still handled.
+// For multi-episode files the first episode of the span is returned; use
+// ExtractEpisodeRange to get the whole span.
 func ExtractEpisodeKey(filename string) (season, episode int, ok bool) {
+	season, episode, _, ok = ExtractEpisodeRange(filename)
+	return season, episode, ok
+}
+
+// ExtractEpisodeRange returns the season and the first/last episode encoded
+// in filename; first == last for single-episode files.  It first delegates to
+// naming.ParseTVShowName (which handles SxxExx, NxNN, multi-episode spans and
+// date-based formats).  If that returns a zero season/episode it falls back
+// to naming.ParseEpisodeSpan on the base name so that bare filenames without
+// a title are still handled with the same span rules.
+func ExtractEpisodeRange(filename string) (season, first, last int, ok bool) {
 	info, err := naming.ParseTVShowName(filename)
 	if err == nil && (info.Season != 0 || info.Episode != 0) {
-		return info.Season, info.Episode, true
+		return info.Season, info.Episode, info.LastEpisode(), true
 	}
+	return naming.ParseEpisodeSpan(filepath.Base(filename))
+}
 
-	base := filepath.Base(filename)
-	if m := reSxxExx.FindStringSubmatch(base); m != nil {
-		s, _ := strconv.Atoi(m[1])
-		e, _ := strconv.Atoi(m[2])
-		return s, e, true
-	}
-	if m := reNxNN.FindStringSubmatch(base); m != nil {
-		s, _ := strconv.Atoi(m[1])
-		e, _ := strconv.Atoi(m[2])
-		return s, e, true
-	}
-	return 0, 0, false
+// EpisodeFile is a video file in a season directory together with the
+// episode span parsed from its name.
+type EpisodeFile struct {
+	Path   string
+	Season int
+	First  int
+	Last   int
+}
+
+// CoveredBy reports whether every episode in the file lies within
+// [first, last], i.e. a file spanning [first, last] fully supersedes it.
+func (f EpisodeFile) CoveredBy(first, last int) bool {
+	return f.First >= first && f.Last <= last
 }
 
 // FindEpisodeFile scans seasonDir for a video file whose episode key matches
-// the given season and episode numbers.  Directories and non-video files
-// (including subtitles) are ignored.
+// the given season and episode numbers.  A multi-episode file matches any
+// episode inside its span.  Directories and non-video files (including
+// subtitles) are ignored.
 func FindEpisodeFile(seasonDir string, season, episode int) (string, bool) {
+	files := FindEpisodeFiles(seasonDir, season, episode, episode)
+	if len(files) == 0 {
+		return "", false
+	}
+	return files[0].Path, true
+}
+
+// FindEpisodeFiles returns every video file in seasonDir whose episode span
+// overlaps [first, last] for the given season.  A new S01E01-E02 file thus
+// finds both an existing S01E01 and S01E02, and a new S01E02 finds an
+// existing S01E01-E03.
+func FindEpisodeFiles(seasonDir string, season, first, last int) []EpisodeFile {
+	if last < first {
+		last = first
+	}
 	entries, err := os.ReadDir(seasonDir)
 	if err != nil {
-		return "", false
+		return nil
 	}
+	var files []EpisodeFile
 	for _, entry := range entries {
 		if entry.IsDir() {
 			continue
@@ -55,10 +77,16 @@ func FindEpisodeFile(seasonDir string, season, episode int) (string, bool) {
 		if !video.IsVideo(entry.Name()) {
 			continue
 		}
-		s, e, ok := ExtractEpisodeKey(entry.Name())
-		if ok && s == season && e == episode {
-			return filepath.Join(seasonDir, entry.Name()), true
+		s, f, l, ok := ExtractEpisodeRange(entry.Name())
+		if !ok || s != season || l < first || f > last {
+			continue
 		}
+		files = append(files, EpisodeFile{
+			Path:   filepath.Join(seasonDir, entry.Name()),
+			Season: s,
+			First:  f,
+			Last:   l,
+		})
 	}
-	return "", false
+	return files
 }
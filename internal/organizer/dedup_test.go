@@ -19,6 +19,8 @@ func TestExtractEpisodeKey(t *testing.T) {
 		{"Show.Name.S02E19.1080p.mkv", 2, 19, true},
 		{"show.name.s01e05.720p.mp4", 1, 5, true},
 		{"Show.Name.2x07.BluRay.mkv", 2, 7, true},
+		// multi-episode: the key is the first episode of the span
+		{"Show.Name.S01E03E04.mkv", 1, 3, true},
 		// date-based: naming.ParseTVShowName maps year→season, month*100+day→episode
 		{"Late Night Show.2021.03.15.mkv", 2021, 315, true},
 		// no episode marker → false
@@ -76,3 +78,61 @@ func TestFindEpisodeFile(t *testing.T) {
 		assert.Empty(t, path)
 	})
 }
+
+func TestExtractEpisodeRange(t *testing.T) {
+	tests := []struct {
+		filename  string
+		wantFirst int
+		wantLast  int
+	}{
+		{"Show.Name.S01E05.mkv", 5, 5},
+		{"Show.Name.S01E01E02.mkv", 1, 2},
+		{"Show (2020) S01E01-E03.mkv", 1, 3},
+		{"Show.1x04-1x05.mkv", 4, 5},
+		// bare filenames without a title use naming.ParseEpisodeSpan and
+		// must follow the same span rules as the full parser
+		{"S02E07E08.mkv", 7, 8},
+		{"S01E04-05.mkv", 4, 5},
+		{"1x01-1x02.mkv", 1, 2},
+		{"S01E01-E24.mkv", 1, 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.filename, func(t *testing.T) {
+			_, first, last, ok := ExtractEpisodeRange(tc.filename)
+			require.True(t, ok)
+			assert.Equal(t, tc.wantFirst, first, "first mismatch")
+			assert.Equal(t, tc.wantLast, last, "last mismatch")
+		})
+	}
+}
+
+func TestFindEpisodeFiles_Spans(t *testing.T) {
+	dir := t.TempDir()
+	multi := filepath.Join(dir, "Show S01E01-E02.mkv")
+	single := filepath.Join(dir, "Show S01E03.mkv")
+	require.NoError(t, os.WriteFile(multi, []byte("v"), 0644))
+	require.NoError(t, os.WriteFile(single, []byte("v"), 0644))
+
+	t.Run("episode inside an existing span matches it", func(t *testing.T) {
+		path, found := FindEpisodeFile(dir, 1, 2)
+		assert.True(t, found)
+		assert.Equal(t, multi, path)
+	})
+
+	t.Run("new span finds every overlapping file", func(t *testing.T) {
+		files := FindEpisodeFiles(dir, 1, 2, 3)
+		require.Len(t, files, 2)
+		for _, f := range files {
+			if f.Path == multi {
+				assert.False(t, f.CoveredBy(2, 3), "E01-E02 is not covered by E02-E03")
+			} else {
+				assert.True(t, f.CoveredBy(2, 3), "E03 is covered by E02-E03")
+			}
+		}
+	})
+
+	t.Run("non-overlapping span finds nothing", func(t *testing.T) {
+		assert.Empty(t, FindEpisodeFiles(dir, 1, 4, 5))
+	})
+}
@@ -0,0 +1,223 @@
+package organizer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/Nomadcxx/jellywatch/internal/analyzer"
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+	"github.com/Nomadcxx/jellywatch/internal/transfer"
+)
+
+// WithExtrasLayout sets how movie extras are named: in per-type folders
+// (the default) or with a Jellyfin suffix beside the movie. TV extras
+// always go in folders, the only form Jellyfin reads for shows and seasons.
+func WithExtrasLayout(layout naming.ExtrasLayout) func(*Organizer) {
+	return func(o *Organizer) {
+		o.extrasLayout = layout
+	}
+}
+
+// OrganizeMovieExtra places a trailer, featurette or other bonus video in
+// the folder of the movie it belongs to.
+func (o *Organizer) OrganizeMovieExtra(sourcePath, libraryPath string, movie naming.MovieInfo, extra naming.ExtraInfo) (*OrganizationResult, error) {
+	movie.Edition, movie.Part = "", 0
+	cleanName := naming.NormalizeMediaName(movie.Title, movie.Year)
+	movieDir := filepath.Join(libraryPath, cleanName)
+	if tmpl, ok := o.templatesFor(libraryPath); ok {
+		values := o.movieTemplateValues(extra.MainPath, &movie)
+		folder := tmpl.MovieFolderName(&movie, values)
+		if movieDir = findTemplatedDir(libraryPath, folder); movieDir == "" {
+			movieDir = filepath.Join(libraryPath, folder)
+		}
+	}
+
+	layout := o.extrasLayout
+	if layout != naming.ExtrasSuffix {
+		layout = naming.ExtrasFolders
+	}
+	return o.placeExtra(sourcePath, libraryPath, movieDir, filepath.Base(movieDir), extra, layout)
+}
+
+// OrganizeTVExtra places a bonus video in the extras folders of its
+// season, or of the show when tv.Season is negative.
+func (o *Organizer) OrganizeTVExtra(sourcePath, libraryPath string, tv naming.TVShowInfo, extra naming.ExtraInfo) (*OrganizationResult, error) {
+	tmpl, templated := o.templatesFor(libraryPath)
+	var values naming.TemplateValues
+	if templated {
+		values = o.tvTemplateValues(extra.MainPath, &tv, tmpl)
+	}
+	showDir, seasonDir := showDirs(libraryPath, &tv, tmpl, templated, values)
+	dir := seasonDir
+	if tv.Season < 0 {
+		dir = showDir
+	}
+	return o.placeExtra(sourcePath, libraryPath, dir, filepath.Base(showDir), extra, naming.ExtrasFolders)
+}
+
+// OrganizeTVExtraAuto routes a TV extra through the library selector, so
+// it lands beside the show wherever the show already lives.
+func (o *Organizer) OrganizeTVExtraAuto(sourcePath string, tv naming.TVShowInfo, extra naming.ExtraInfo, getFileSize func(string) (int64, error)) (*OrganizationResult, error) {
+	size, err := getFileSize(sourcePath)
+	if err != nil {
+		return &OrganizationResult{
+			Success:    false,
+			SourcePath: sourcePath,
+			Error:      fmt.Errorf("unable to get file size: %w", err),
+		}, nil
+	}
+	selection, err := o.selector.SelectTVShowLibrary(tv.Title, tv.Year, size)
+	if err != nil {
+		return &OrganizationResult{
+			Success:    false,
+			SourcePath: sourcePath,
+			Error:      fmt.Errorf("unable to select library: %w", err),
+		}, nil
+	}
+	return o.OrganizeTVExtra(sourcePath, selection.Library, tv, extra)
+}
+
+// placeExtra transfers an extra into dir: into its type's subfolder in
+// the folder layout, beside the main video named after owner in the
+// suffix layout. An extra that is already there is skipped; a different
+// one with the same name gets a number.
+func (o *Organizer) placeExtra(sourcePath, libraryPath, dir, owner string, extra naming.ExtraInfo, layout naming.ExtrasLayout) (*OrganizationResult, error) {
+	targetDir := dir
+	if layout == naming.ExtrasFolders {
+		if targetDir = findTemplatedDir(dir, extra.Type.Folder()); targetDir == "" {
+			targetDir = filepath.Join(dir, extra.Type.Folder())
+		}
+	}
+
+	sourceInfo, err := os.Stat(sourcePath)
+	if err != nil {
+		return &OrganizationResult{
+			Success:    false,
+			SourcePath: sourcePath,
+			Error:      fmt.Errorf("unable to stat extra: %w", err),
+		}, nil
+	}
+	ext := filepath.Ext(sourcePath)
+	var targetPath string
+	for n := 1; ; n++ {
+		targetPath = filepath.Join(targetDir, naming.FormatExtraFilename(extra, owner, layout, n, ext))
+		existing, err := os.Stat(targetPath)
+		if err != nil {
+			break
+		}
+		if existing.Size() == sourceInfo.Size() {
+			return &OrganizationResult{
+				Success:    false,
+				SourcePath: sourcePath,
+				TargetPath: targetPath,
+				Skipped:    true,
+				SkipReason: "extra already in library",
+			}, nil
+		}
+	}
+
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return &OrganizationResult{
+			Success:    false,
+			SourcePath: sourcePath,
+			TargetPath: targetPath,
+			Error:      fmt.Errorf("unable to create directory: %w", err),
+		}, nil
+	}
+	for _, d := range []string{dir, targetDir} {
+		if err := o.applyDirOwnership(d); err != nil {
+			return &OrganizationResult{
+				Success:    false,
+				SourcePath: sourcePath,
+				TargetPath: targetPath,
+				Error:      fmt.Errorf("unable to set directory permissions: %w", err),
+			}, nil
+		}
+	}
+
+	if o.dryRun {
+		return &OrganizationResult{
+			Success:    true,
+			SourcePath: sourcePath,
+			TargetPath: targetPath,
+		}, nil
+	}
+
+	opts := o.buildTransferOptions()
+	transferer := o.transfererFor(libraryPath)
+
+	var result *transfer.TransferResult
+	if o.keepSource {
+		result, err = transferer.Copy(sourcePath, targetPath, opts)
+	} else {
+		result, err = transferer.Move(sourcePath, targetPath, opts)
+	}
+	if err != nil {
+		return &OrganizationResult{
+			Success:     false,
+			SourcePath:  sourcePath,
+			TargetPath:  targetPath,
+			BytesCopied: result.BytesCopied,
+			Duration:    result.Duration,
+			Attempts:    result.Attempts,
+			Error:       fmt.Errorf("transfer failed: %w", err),
+		}, nil
+	}
+	o.journalTransfer(sourcePath, targetPath, o.keepSource || result.SourcePreserved, nil, nil)
+
+	return &OrganizationResult{
+		Success:         true,
+		SourcePath:      sourcePath,
+		TargetPath:      targetPath,
+		BytesCopied:     result.BytesCopied,
+		Duration:        result.Duration,
+		Attempts:        result.Attempts,
+		SourcePreserved: result.SourcePreserved,
+		LinkType:        result.LinkType,
+	}, nil
+}
+
+// placeFolderTVExtras places the extras of an organized TV release beside
+// its show when keepExtras is set, and lists them as skipped otherwise.
+func (o *Organizer) placeFolderTVExtras(result *FolderOrganizationResult, analysis *analyzer.FolderAnalysis, libraryPath string, keepExtras bool) {
+	if !keepExtras {
+		if !o.dryRun {
+			result.ExtrasSkipped = o.getFilePaths(analysis.ExtraFiles)
+		}
+		return
+	}
+	show, err := naming.ParseTVShowFromPath(analysis.MainMediaFile.Path)
+	if err != nil {
+		return
+	}
+	result.ExtrasPlaced = o.placeFolderExtras(analysis.ExtraFiles, func(path string, extra naming.ExtraInfo) (*OrganizationResult, error) {
+		tv := naming.TVShowInfo{Title: show.Title, Year: show.Year, Season: extra.Season}
+		return o.OrganizeTVExtra(path, libraryPath, tv, extra)
+	})
+}
+
+// placeFolderExtras runs place on each extra of a release folder and
+// returns where the placed ones went.
+func (o *Organizer) placeFolderExtras(files []analyzer.FileInfo, place func(string, naming.ExtraInfo) (*OrganizationResult, error)) []string {
+	var placed []string
+	for _, f := range files {
+		extra, ok := naming.ParseExtra(f.Path)
+		if !ok {
+			continue
+		}
+		res, err := place(f.Path, extra)
+		if err == nil && res.Error != nil {
+			err = res.Error
+		}
+		if err != nil {
+			log.Printf("[organizer] extra not placed: src=%s err=%v", f.Path, err)
+			continue
+		}
+		if res.Success {
+			placed = append(placed, res.TargetPath)
+		}
+	}
+	return placed
+}
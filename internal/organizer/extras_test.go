@@ -0,0 +1,125 @@
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+	"github.com/Nomadcxx/jellywatch/internal/transfer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrganizeFolder_PlacesMovieExtras(t *testing.T) {
+	root := t.TempDir()
+	release := filepath.Join(root, "downloads", "Heat.1995.1080p.BluRay-GRP")
+	lib := filepath.Join(root, "movies")
+	require.NoError(t, os.MkdirAll(filepath.Join(release, "Featurettes"), 0755))
+	require.NoError(t, os.MkdirAll(lib, 0755))
+
+	createTestFile(t, filepath.Join(release, "Heat.1995.1080p.BluRay-GRP.mkv"), 4096)
+	createTestFile(t, filepath.Join(release, "Featurettes", "Making Of.mkv"), 1024)
+	createTestFile(t, filepath.Join(release, "Heat.1995.Trailer.1080p.BluRay-GRP.mkv"), 1024)
+
+	org, err := NewOrganizer([]string{lib}, WithBackend(transfer.BackendNative))
+	require.NoError(t, err)
+
+	result, err := org.OrganizeFolder(release, lib, true)
+	require.NoError(t, err)
+	require.NotNil(t, result.MediaResult)
+	require.True(t, result.MediaResult.Success, "organize failed: %v", result.MediaResult.Error)
+
+	movieDir := filepath.Join(lib, "Heat (1995)")
+	assert.FileExists(t, filepath.Join(movieDir, "Heat (1995).mkv"))
+	assert.FileExists(t, filepath.Join(movieDir, "featurettes", "Making Of.mkv"))
+	assert.FileExists(t, filepath.Join(movieDir, "trailers", "Trailer.mkv"))
+	assert.Len(t, result.ExtrasPlaced, 2)
+	assert.Empty(t, result.ExtrasSkipped)
+}
+
+func TestOrganizeMovieExtra_SuffixLayout(t *testing.T) {
+	root := t.TempDir()
+	lib := filepath.Join(root, "movies")
+	movieDir := filepath.Join(lib, "Heat (1995)")
+	require.NoError(t, os.MkdirAll(movieDir, 0755))
+
+	org, err := NewOrganizer([]string{lib},
+		WithBackend(transfer.BackendNative),
+		WithExtrasLayout(naming.ExtrasSuffix),
+		WithKeepSource(true),
+	)
+	require.NoError(t, err)
+
+	organize := func(name string, size int64) *OrganizationResult {
+		t.Helper()
+		src := filepath.Join(root, "downloads", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(src), 0755))
+		createTestFile(t, src, size)
+		extra, ok := naming.ParseExtra(src)
+		require.True(t, ok)
+		movie, err := naming.ParseMovieFromPath(extra.MainPath)
+		require.NoError(t, err)
+		res, err := org.OrganizeMovieExtra(src, lib, *movie, extra)
+		require.NoError(t, err)
+		return res
+	}
+
+	res := organize("Heat.1995.Trailer.mkv", 1024)
+	require.True(t, res.Success, "organize failed: %v", res.Error)
+	assert.Equal(t, filepath.Join(movieDir, "Heat (1995)-trailer.mkv"), res.TargetPath)
+
+	// A different trailer gets a name of its own; the same one again is
+	// already there.
+	res = organize("Heat.1995.Teaser.mkv", 2048)
+	require.True(t, res.Success, "organize failed: %v", res.Error)
+	assert.Equal(t, filepath.Join(movieDir, "Heat (1995) - Teaser-trailer.mkv"), res.TargetPath)
+	res = organize("Heat.1995.Trailer.720p.mkv", 1024)
+	assert.True(t, res.Skipped)
+
+	// Suffix extras beside the movie are not versions of it.
+	main := filepath.Join(root, "downloads", "Heat.1995.720p.BluRay.mkv")
+	createTestFile(t, main, 4096)
+	movieRes, err := org.OrganizeMovie(main, lib)
+	require.NoError(t, err)
+	require.True(t, movieRes.Success, "organize failed: %v", movieRes.Error)
+	assert.FileExists(t, filepath.Join(movieDir, "Heat (1995)-trailer.mkv"))
+}
+
+func TestOrganizeTVExtra_SeasonAndShowLevel(t *testing.T) {
+	root := t.TempDir()
+	lib := filepath.Join(root, "tv")
+	showDir := filepath.Join(lib, "Severance (2022)")
+	require.NoError(t, os.MkdirAll(filepath.Join(showDir, "Season 1"), 0755))
+
+	org, err := NewOrganizer([]string{lib}, WithBackend(transfer.BackendNative))
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		src  string
+		want string
+	}{
+		{
+			src:  filepath.Join(root, "downloads", "Severance.S01.1080p.WEB", "Extras", "Bloopers.mkv"),
+			want: filepath.Join(showDir, "Season 1", "extras", "Bloopers.mkv"),
+		},
+		{
+			src:  filepath.Join(root, "downloads", "Severance.2022.Official.Trailer.1080p.mkv"),
+			want: filepath.Join(showDir, "trailers", "Official Trailer.mkv"),
+		},
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(tt.src), 0755))
+		createTestFile(t, tt.src, 1024)
+		extra, ok := naming.ParseExtra(tt.src)
+		require.True(t, ok, tt.src)
+		show, err := extra.Show()
+		require.NoError(t, err)
+
+		res, err := org.OrganizeTVExtra(tt.src, lib, *show, extra)
+		require.NoError(t, err)
+		require.True(t, res.Success, "organize failed: %v", res.Error)
+		assert.Equal(t, tt.want, res.TargetPath)
+		assert.FileExists(t, tt.want)
+		assert.NoFileExists(t, tt.src)
+	}
+}
@@ -0,0 +1,123 @@
+package organizer
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Nomadcxx/jellywatch/internal/database"
+)
+
+// journalDB is the database organizes are journaled in, or nil.
+func (o *Organizer) journalDB() *database.MediaDB {
+	if o.journal != nil {
+		return o.journal
+	}
+	return o.db
+}
+
+// removal is an existing file an organize replaced, and where the recycle
+// bin kept it ("" when it was deleted for good).
+type removal struct {
+	path   string
+	keptAt string
+}
+
+// removeReplaced deletes the existing files a transfer to targetPath
+// superseded, through the recycle bin when one is configured.
+func (o *Organizer) removeReplaced(paths []string, targetPath string) []removal {
+	var removed []removal
+	for _, path := range paths {
+		if path == "" || path == targetPath {
+			continue
+		}
+		keptAt, err := o.trash.Remove(path, "replaced by "+targetPath)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				log.Printf("[organizer] warning: failed to remove existing file %s: %v", path, err)
+			}
+			continue
+		}
+		removed = append(removed, removal{path: path, keptAt: keptAt})
+	}
+	return removed
+}
+
+// journalTransfer records an organize in the operation journal: the
+// transfer, then every existing file it replaced. Call it after the
+// transfer and before the database is updated, with owner holding the
+// series or movie row as it was before this file arrived.
+func (o *Organizer) journalTransfer(sourcePath, targetPath string, preserved bool, replaced []removal, owner *database.OpState) {
+	db := o.journalDB()
+	if db == nil {
+		return
+	}
+	state := db.CaptureOpState(sourcePath)
+	if state == nil {
+		state = &database.OpState{}
+	}
+	if owner != nil {
+		state.SeriesID, state.SeriesPath, state.SeriesLibrary = owner.SeriesID, owner.SeriesPath, owner.SeriesLibrary
+		state.MovieID, state.MoviePath, state.MovieLibrary = owner.MovieID, owner.MoviePath, owner.MovieLibrary
+	}
+
+	batch := database.NewJournalBatch(database.OpSourceOrganize)
+	kind := database.OpMove
+	if preserved {
+		kind = database.OpCopy
+	}
+	ops := []database.JournalOp{{
+		BatchID:    batch,
+		Source:     database.OpSourceOrganize,
+		Kind:       kind,
+		BeforePath: sourcePath,
+		AfterPath:  targetPath,
+		Reversible: true,
+		Snapshot:   state,
+	}}
+	for _, r := range replaced {
+		ops = append(ops, database.JournalOp{
+			BatchID:    batch,
+			Source:     database.OpSourceOrganize,
+			Kind:       database.OpDelete,
+			BeforePath: r.path,
+			AfterPath:  r.keptAt,
+			Reversible: r.keptAt != "",
+		})
+	}
+	for _, op := range ops {
+		if _, err := db.RecordOperation(op); err != nil {
+			log.Printf("[organizer] warning: failed to journal %s of %s: %v", op.Kind, op.BeforePath, err)
+		}
+	}
+}
+
+// movieOwner returns the movie row an organize is about to update.
+func (o *Organizer) movieOwner(title, year string) *database.OpState {
+	db := o.journalDB()
+	if db == nil {
+		return nil
+	}
+	yearInt := 0
+	fmt.Sscanf(year, "%d", &yearInt)
+	m, err := db.GetMovieByTitle(title, yearInt)
+	if err != nil || m == nil {
+		return nil
+	}
+	return &database.OpState{MovieID: m.ID, MoviePath: m.CanonicalPath, MovieLibrary: m.LibraryRoot}
+}
+
+// seriesOwner returns the series row an organize is about to update.
+func (o *Organizer) seriesOwner(title, year string) *database.OpState {
+	db := o.journalDB()
+	if db == nil {
+		return nil
+	}
+	yearInt := 0
+	fmt.Sscanf(year, "%d", &yearInt)
+	s, err := db.GetSeriesByTitle(title, yearInt)
+	if err != nil || s == nil {
+		return nil
+	}
+	return &database.OpState{SeriesID: s.ID, SeriesPath: s.CanonicalPath, SeriesLibrary: s.LibraryRoot}
+}
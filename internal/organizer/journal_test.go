@@ -0,0 +1,54 @@
+package organizer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+	"github.com/Nomadcxx/jellywatch/internal/transfer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithJournalRecordsOrganizes(t *testing.T) {
+	sourceDir, libraryDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	db, err := database.OpenPath(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	// Resolution in the name makes the replacement land beside the file
+	// it supersedes rather than over it.
+	org, err := NewOrganizer([]string{libraryDir},
+		WithBackend(transfer.BackendNative),
+		WithLibraryTemplates(libraryDir, naming.Templates{MovieFile: "{Title} ({Year}) - {Resolution}"}),
+		WithJournal(db),
+	)
+	require.NoError(t, err)
+
+	first := filepath.Join(sourceDir, "Heat.1995.720p.WEB-DL.mkv")
+	createTestFile(t, first, 1024)
+	result, err := org.OrganizeMovie(first, libraryDir)
+	require.NoError(t, err)
+	require.True(t, result.Success, "organize failed: %v", result.Error)
+
+	better := filepath.Join(sourceDir, "Heat.1995.1080p.BluRay.mkv")
+	createTestFile(t, better, 1024)
+	replaced, err := org.OrganizeMovie(better, libraryDir)
+	require.NoError(t, err)
+	require.True(t, replaced.Success, "organize failed: %v", replaced.Error)
+
+	ops, err := db.ListOperations(database.OpSourceOrganize, 10)
+	require.NoError(t, err)
+	require.Len(t, ops, 3)
+	// Newest first: the replacement's delete, its move, the first move.
+	assert.Equal(t, database.OpDelete, ops[0].Kind)
+	assert.Equal(t, result.TargetPath, ops[0].BeforePath)
+	assert.Equal(t, database.OpMove, ops[1].Kind)
+	assert.Equal(t, better, ops[1].BeforePath)
+	assert.Equal(t, ops[0].BatchID, ops[1].BatchID)
+	assert.Equal(t, first, ops[2].BeforePath)
+	assert.Equal(t, result.TargetPath, ops[2].AfterPath)
+}
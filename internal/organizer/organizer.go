@@ -16,9 +16,12 @@ import (
 	"github.com/Nomadcxx/jellywatch/internal/library"
 	"github.com/Nomadcxx/jellywatch/internal/naming"
 	"github.com/Nomadcxx/jellywatch/internal/quality"
+	"github.com/Nomadcxx/jellywatch/internal/sidecars"
 	"github.com/Nomadcxx/jellywatch/internal/sonarr"
+	"github.com/Nomadcxx/jellywatch/internal/subtitles"
 	syncsvc "github.com/Nomadcxx/jellywatch/internal/sync"
 	"github.com/Nomadcxx/jellywatch/internal/transfer"
+	"github.com/Nomadcxx/jellywatch/internal/trash"
 	"github.com/Nomadcxx/jellywatch/internal/video"
 )
 
@@ -34,6 +37,18 @@ type OrganizationResult struct {
 	SkipReason      string
 	SourceQuality   *quality.QualityInfo
 	ExistingQuality *quality.QualityInfo
+	// SourcePreserved is set when the transfer backend linked the target
+	// and deliberately left the source in place (seeding). Callers must not
+	// delete the source or its release directory.
+	SourcePreserved bool
+	// LinkType is "hardlink" or "reflink" when the target shares data with
+	// the source, empty for a byte copy.
+	LinkType string
+	// Subtitles names the subtitle sidecars placed next to TargetPath.
+	Subtitles []string
+	// Metadata names the NFO files, artwork and trickplay folders placed
+	// next to TargetPath.
+	Metadata []string
 }
 
 type SeasonPackResult struct {
@@ -53,6 +68,12 @@ type Organizer struct {
 	forceOverwrite bool
 	selector       *library.Selector
 	transferer     transfer.Transferer
+	libTransferers map[string]transfer.Transferer
+	animeLibs      map[string]bool
+	absResolver    AbsoluteResolver
+	libTemplates   map[string]naming.Templates
+	episodeTitles  EpisodeTitleResolver
+	idDB           *database.MediaDB
 	timeout        time.Duration
 	checksumVerify bool
 	targetUID      int
@@ -70,6 +91,13 @@ type Organizer struct {
 	pauseMu        sync.RWMutex
 	playbackLocks  *jellyfin.PlaybackLockManager
 	deferredQueue  *jellyfin.DeferredQueue
+	journal        *database.MediaDB
+	trash          *trash.Bin
+	extrasLayout   naming.ExtrasLayout
+
+	// resolutionVersions keeps a movie at each resolution as a separate
+	// Jellyfin version instead of replacing the lower one.
+	resolutionVersions bool
 }
 
 func NewOrganizer(libraries []string, options ...func(*Organizer)) (*Organizer, error) {
@@ -155,12 +183,36 @@ func WithTransferer(t transfer.Transferer) func(*Organizer) {
 	}
 }
 
+// WithLibraryTransferer injects a pre-built transferer used only for
+// libraryPath. Other libraries keep the default transferer.
+func WithLibraryTransferer(libraryPath string, t transfer.Transferer) func(*Organizer) {
+	return func(o *Organizer) {
+		if t == nil || strings.TrimSpace(libraryPath) == "" {
+			return
+		}
+		if o.libTransferers == nil {
+			o.libTransferers = make(map[string]transfer.Transferer)
+		}
+		o.libTransferers[filepath.Clean(libraryPath)] = t
+	}
+}
+
 func WithForceOverwrite(force bool) func(*Organizer) {
 	return func(o *Organizer) {
 		o.forceOverwrite = force
 	}
 }
 
+// WithResolutionVersions keeps a movie that arrives at a different
+// resolution from the copy already in the library beside it, labelled
+// "Movie (2001) - 2160p.mkv", rather than having the better copy replace
+// the other. Copies of the same resolution still compete.
+func WithResolutionVersions(enabled bool) func(*Organizer) {
+	return func(o *Organizer) {
+		o.resolutionVersions = enabled
+	}
+}
+
 func WithPermissions(uid, gid int, fileMode, dirMode os.FileMode) func(*Organizer) {
 	return func(o *Organizer) {
 		o.targetUID = uid
@@ -199,6 +251,15 @@ func WithDatabase(db *database.MediaDB) func(*Organizer) {
 	}
 }
 
+// WithJournal records organizes in db's operation journal without the
+// library bookkeeping WithDatabase enables. Organizers built with
+// WithDatabase already journal there.
+func WithJournal(db *database.MediaDB) func(*Organizer) {
+	return func(o *Organizer) {
+		o.journal = db
+	}
+}
+
 func WithSyncService(svc *syncsvc.SyncService) func(*Organizer) {
 	return func(o *Organizer) {
 		o.syncService = svc
@@ -220,6 +281,44 @@ func WithDeferredQueue(queue *jellyfin.DeferredQueue) func(*Organizer) {
 	}
 }
 
+// WithTrash sends replaced library files and download junk to the recycle
+// bin instead of deleting them.
+func WithTrash(bin *trash.Bin) func(*Organizer) {
+	return func(o *Organizer) {
+		o.trash = bin
+	}
+}
+
+// transfererFor returns the transferer configured for libraryPath, falling
+// back to the organizer-wide default.
+func (o *Organizer) transfererFor(libraryPath string) transfer.Transferer {
+	if t, ok := o.libTransferers[filepath.Clean(libraryPath)]; ok {
+		return t
+	}
+	return o.transferer
+}
+
+// LeavesSource reports whether organizing into libraryPath leaves the
+// download where it is, because keep_source is set or the library's
+// backend links or copies. An empty libraryPath asks about every library.
+func (o *Organizer) LeavesSource(libraryPath string) bool {
+	if o.keepSource {
+		return true
+	}
+	if libraryPath != "" {
+		return transfer.PreservesSource(o.transfererFor(libraryPath))
+	}
+	if !transfer.PreservesSource(o.transferer) {
+		return false
+	}
+	for _, t := range o.libTransferers {
+		if !transfer.PreservesSource(t) {
+			return false
+		}
+	}
+	return true
+}
+
 func (o *Organizer) buildTransferOptions() transfer.TransferOptions {
 	return transfer.TransferOptions{
 		Timeout:       o.timeout,
@@ -379,6 +478,21 @@ func (o *Organizer) OrganizeMovieWithParsed(sourcePath, libraryPath string, movi
 	movieDir := filepath.Join(libraryPath, cleanName)
 	ext := filepath.Ext(sourcePath)
 	targetPath := filepath.Join(movieDir, cleanName+ext)
+	if version := naming.StackedVersion(movie.Edition, movie.Part); version != "" {
+		// Jellyfin multi-version naming keeps editions side by side
+		// ("Movie (2001) - Director's Cut.mkv") and stacking names the
+		// parts of a multi-part movie ("Movie (2001) - part1.mkv").
+		targetPath = filepath.Join(movieDir, fmt.Sprintf("%s - %s%s", cleanName, version, ext))
+	}
+	tmpl, templated := o.templatesFor(libraryPath)
+	if templated {
+		values := o.movieTemplateValues(sourcePath, &movie)
+		folder := tmpl.MovieFolderName(&movie, values)
+		if movieDir = findTemplatedDir(libraryPath, folder); movieDir == "" {
+			movieDir = filepath.Join(libraryPath, folder)
+		}
+		targetPath = filepath.Join(movieDir, tmpl.MovieFileName(&movie, values, ext))
+	}
 
 	if err := o.checkPlaybackSafetyWithOp(sourcePath, "organize_movie", targetPath); err != nil {
 		return &OrganizationResult{
@@ -391,7 +505,12 @@ func (o *Organizer) OrganizeMovieWithParsed(sourcePath, libraryPath string, movi
 		}, nil
 	}
 
-	existingFile, existingQuality := o.findExistingMediaFile(movieDir)
+	existingFile, existingQuality := o.findExistingMediaFile(movieDir, movie.Edition, movie.Part)
+	if movie.Edition == "" && movie.Part == 0 && o.resolutionVersions && !templated {
+		if target, existing, existingQ, ok := o.resolutionVersionTarget(movieDir, cleanName, ext, sourceQuality); ok {
+			targetPath, existingFile, existingQuality = target, existing, existingQ
+		}
+	}
 	if existingFile != "" && !o.forceOverwrite {
 		if !sourceQuality.IsBetterThan(existingQuality) {
 			return &OrganizationResult{
@@ -436,14 +555,19 @@ func (o *Organizer) OrganizeMovieWithParsed(sourcePath, libraryPath string, movi
 		}, nil
 	}
 
+	// Find the sidecars before the video leaves its folder.
+	subs := subtitles.Find(sourcePath)
+	cars := sidecars.Find(sourcePath)
+
 	opts := o.buildTransferOptions()
+	transferer := o.transfererFor(libraryPath)
 
 	var err error
 	var result *transfer.TransferResult
 	if o.keepSource {
-		result, err = o.transferer.Copy(sourcePath, targetPath, opts)
+		result, err = transferer.Copy(sourcePath, targetPath, opts)
 	} else {
-		result, err = o.transferer.Move(sourcePath, targetPath, opts)
+		result, err = transferer.Move(sourcePath, targetPath, opts)
 	}
 
 	if err != nil {
@@ -459,11 +583,15 @@ func (o *Organizer) OrganizeMovieWithParsed(sourcePath, libraryPath string, movi
 		}, nil
 	}
 
-	if existingFile != "" && existingFile != targetPath {
-		if err := os.Remove(existingFile); err != nil && !os.IsNotExist(err) {
-			log.Printf("[organizer] warning: failed to remove existing file %s: %v", existingFile, err)
-		}
+	// A single file replaces a whole existing stack; a part replaces
+	// only the same part (or the single file the stack supersedes).
+	replaceFiles := []string{existingFile}
+	if existingFile != "" && movie.Part == 0 {
+		replaceFiles = stackParts(existingFile)
 	}
+	owner := o.movieOwner(movie.Title, movie.Year)
+	replaced := o.removeReplaced(replaceFiles, targetPath)
+	o.journalTransfer(sourcePath, targetPath, o.keepSource || result.SourcePreserved, replaced, owner)
 
 	if o.db != nil {
 		if mediaFile, err := o.db.GetMediaFile(sourcePath); err == nil && mediaFile != nil {
@@ -506,6 +634,10 @@ func (o *Organizer) OrganizeMovieWithParsed(sourcePath, libraryPath string, movi
 		Attempts:        result.Attempts,
 		SourceQuality:   sourceQuality,
 		ExistingQuality: existingQuality,
+		SourcePreserved: result.SourcePreserved,
+		LinkType:        result.LinkType,
+		Subtitles:       o.carrySubtitles(subs, targetPath, libraryPath),
+		Metadata:        o.carrySidecars(cars, targetPath, libraryPath),
 	}, nil
 }
 
@@ -513,7 +645,7 @@ func (o *Organizer) OrganizeTVEpisode(sourcePath, libraryPath string) (*Organiza
 	filename := filepath.Base(sourcePath)
 	sourceQuality := quality.Parse(filename)
 
-	tv, err := naming.ParseTVShowFromPath(sourcePath)
+	tv, err := o.parseTVForLibrary(sourcePath, libraryPath)
 	if err != nil {
 		return &OrganizationResult{
 			Success:       false,
@@ -523,6 +655,7 @@ func (o *Organizer) OrganizeTVEpisode(sourcePath, libraryPath string) (*Organiza
 		}, nil
 	}
 
+	o.resolveAbsolute(tv)
 	return o.OrganizeTVWithParsed(sourcePath, libraryPath, *tv)
 }
 
@@ -551,25 +684,41 @@ func (o *Organizer) OrganizeTVWithParsedAuto(sourcePath string, tv naming.TVShow
 
 	log.Printf("[organizer] tv library selected (parsed): title=%q lib=%s reason=%s", tv.Title, selection.Library, selection.Reason)
 
+	o.resolveAbsolute(&tv)
 	return o.OrganizeTVWithParsed(sourcePath, selection.Library, tv)
 }
 
+// OrganizeTVWithParsedIn organizes an episode the caller parsed, and may
+// have re-titled, into libraryPath. Like OrganizeTVEpisode, an anime
+// library takes its numbering from the anime parser and absolute episode
+// numbers are mapped to a season first.
+func (o *Organizer) OrganizeTVWithParsedIn(sourcePath, libraryPath string, tv naming.TVShowInfo) (*OrganizationResult, error) {
+	if tv.AbsoluteEpisode == 0 && o.isAnimeLibrary(libraryPath) {
+		if anime, err := naming.ParseAnimeName(filepath.Base(sourcePath)); err == nil && anime.AbsoluteEpisode > 0 {
+			tv.Season, tv.Episode, tv.EpisodeEnd = anime.Season, anime.Episode, anime.EpisodeEnd
+			tv.AbsoluteEpisode = anime.AbsoluteEpisode
+		}
+	}
+	o.resolveAbsolute(&tv)
+	return o.OrganizeTVWithParsed(sourcePath, libraryPath, tv)
+}
+
 func (o *Organizer) OrganizeTVWithParsed(sourcePath, libraryPath string, tv naming.TVShowInfo) (*OrganizationResult, error) {
 	filename := filepath.Base(sourcePath)
 	sourceQuality := quality.Parse(filename)
 
-	showDir := findExistingShowDir(libraryPath, tv.Title)
-	if showDir == "" {
-		showName := naming.NormalizeMediaName(tv.Title, tv.Year)
-		showDir = filepath.Join(libraryPath, showName)
+	tmpl, templated := o.templatesFor(libraryPath)
+	var values naming.TemplateValues
+	if templated {
+		values = o.tvTemplateValues(sourcePath, &tv, tmpl)
 	}
 
-	seasonDir := findExistingSeasonDir(showDir, tv.Season)
-	if seasonDir == "" {
-		seasonDir = filepath.Join(showDir, naming.FormatSeasonFolder(tv.Season))
-	}
+	showDir, seasonDir := showDirs(libraryPath, &tv, tmpl, templated, values)
 	ext := filepath.Ext(sourcePath)
 	episodeName := naming.FormatTVEpisodeFilenameFromInfo(&tv, ext[1:])
+	if templated {
+		episodeName = tmpl.EpisodeFileName(&tv, values, ext)
+	}
 	targetPath := filepath.Join(seasonDir, episodeName)
 
 	if err := o.checkPlaybackSafetyWithOp(sourcePath, "organize_tv", targetPath); err != nil {
@@ -583,28 +732,53 @@ func (o *Organizer) OrganizeTVWithParsed(sourcePath, libraryPath string, tv nami
 		}, nil
 	}
 
-	existingFile, existingFound := FindEpisodeFile(seasonDir, tv.Season, tv.Episode)
-	var existingQuality *quality.QualityInfo
-	if existingFound {
-		existingQuality = quality.Parse(filepath.Base(existingFile))
+	// Existing files overlapping this episode span. Multi-episode files are
+	// only replaced when the incoming span covers every episode they hold;
+	// otherwise replacing them would drop episodes from the library.
+	lastEpisode := tv.LastEpisode()
+	existingFiles := FindEpisodeFiles(seasonDir, tv.Season, tv.Episode, lastEpisode)
+	if tv.Episode <= 0 {
+		// Unnumbered specials have no episode key; only the same
+		// "Show - Christmas Special" name is the same release.
+		existingFiles = nil
+		if _, err := os.Stat(targetPath); err == nil {
+			existingFiles = []EpisodeFile{{Path: targetPath}}
+		}
 	}
-
-	if existingFound && !o.forceOverwrite {
-		if !sourceQuality.IsBetterThan(existingQuality) {
+	var existingQuality *quality.QualityInfo
+	var replaceFiles []string
+	for _, existing := range existingFiles {
+		q := quality.Parse(filepath.Base(existing.Path))
+		if existingQuality == nil {
+			existingQuality = q
+		}
+		if !existing.CoveredBy(tv.Episode, lastEpisode) {
+			if o.forceOverwrite {
+				continue
+			}
 			return &OrganizationResult{
 				Success:         false,
 				SourcePath:      sourcePath,
-				TargetPath:      existingFile,
+				TargetPath:      existing.Path,
 				Skipped:         true,
-				SkipReason:      fmt.Sprintf("existing file has equal or better quality (%s vs %s)", existingQuality.String(), sourceQuality.String()),
+				SkipReason:      fmt.Sprintf("episode already present in multi-episode file %s", filepath.Base(existing.Path)),
 				SourceQuality:   sourceQuality,
-				ExistingQuality: existingQuality,
+				ExistingQuality: q,
 			}, nil
 		}
-		// same episode, source is better quality — fall through to overwrite
-	}
-	if !existingFound {
-		existingFile = ""
+		if !o.forceOverwrite && !sourceQuality.IsBetterThan(q) {
+			return &OrganizationResult{
+				Success:         false,
+				SourcePath:      sourcePath,
+				TargetPath:      existing.Path,
+				Skipped:         true,
+				SkipReason:      fmt.Sprintf("existing file has equal or better quality (%s vs %s)", q.String(), sourceQuality.String()),
+				SourceQuality:   sourceQuality,
+				ExistingQuality: q,
+			}, nil
+		}
+		// same episode(s), source is better quality — fall through to overwrite
+		replaceFiles = append(replaceFiles, existing.Path)
 	}
 
 	if err := os.MkdirAll(seasonDir, 0755); err != nil {
@@ -646,14 +820,20 @@ func (o *Organizer) OrganizeTVWithParsed(sourcePath, libraryPath string, tv nami
 		}, nil
 	}
 
+	// Find the sidecars before the video leaves its folder. Folder-level
+	// artwork belongs to a movie, not to an episode's season folder.
+	subs := subtitles.Find(sourcePath)
+	cars := stemSidecars(sidecars.Find(sourcePath))
+
 	opts := o.buildTransferOptions()
+	transferer := o.transfererFor(libraryPath)
 
 	var err error
 	var result *transfer.TransferResult
 	if o.keepSource {
-		result, err = o.transferer.Copy(sourcePath, targetPath, opts)
+		result, err = transferer.Copy(sourcePath, targetPath, opts)
 	} else {
-		result, err = o.transferer.Move(sourcePath, targetPath, opts)
+		result, err = transferer.Move(sourcePath, targetPath, opts)
 	}
 
 	if err != nil {
@@ -669,11 +849,9 @@ func (o *Organizer) OrganizeTVWithParsed(sourcePath, libraryPath string, tv nami
 		}, nil
 	}
 
-	if existingFile != "" && existingFile != targetPath {
-		if err := os.Remove(existingFile); err != nil && !os.IsNotExist(err) {
-			log.Printf("[organizer] warning: failed to remove existing file %s: %v", existingFile, err)
-		}
-	}
+	owner := o.seriesOwner(tv.Title, tv.Year)
+	replaced := o.removeReplaced(replaceFiles, targetPath)
+	o.journalTransfer(sourcePath, targetPath, o.keepSource || result.SourcePreserved, replaced, owner)
 
 	if o.db != nil {
 		if mediaFile, err := o.db.GetMediaFile(sourcePath); err == nil && mediaFile != nil {
@@ -717,9 +895,36 @@ func (o *Organizer) OrganizeTVWithParsed(sourcePath, libraryPath string, tv nami
 		Attempts:        result.Attempts,
 		SourceQuality:   sourceQuality,
 		ExistingQuality: existingQuality,
+		SourcePreserved: result.SourcePreserved,
+		LinkType:        result.LinkType,
+		Subtitles:       o.carrySubtitles(subs, targetPath, libraryPath),
+		Metadata:        o.carrySidecars(cars, targetPath, libraryPath),
 	}, nil
 }
 
+// showDirs returns the show and season folders tv belongs in, reusing
+// existing ones so a show keeps its folder whatever the library's naming.
+func showDirs(libraryPath string, tv *naming.TVShowInfo, tmpl naming.Templates, templated bool, values naming.TemplateValues) (showDir, seasonDir string) {
+	showDir = findExistingShowDir(libraryPath, tv.Title)
+	if showDir == "" {
+		showName := naming.NormalizeMediaName(tv.Title, tv.Year)
+		if templated {
+			showName = tmpl.SeriesFolderName(tv, values)
+		}
+		showDir = filepath.Join(libraryPath, showName)
+	}
+
+	seasonDir = findExistingSeasonDir(showDir, tv.Season)
+	if seasonDir == "" {
+		seasonFolder := naming.FormatSeasonFolder(tv.Season)
+		if templated {
+			seasonFolder = tmpl.SeasonFolderName(tv.Season)
+		}
+		seasonDir = filepath.Join(showDir, seasonFolder)
+	}
+	return showDir, seasonDir
+}
+
 func (o *Organizer) OrganizeTVEpisodeAuto(sourcePath string, getFileSize func(string) (int64, error)) (*OrganizationResult, error) {
 	info, err := getFileSize(sourcePath)
 	if err != nil {
@@ -770,7 +975,7 @@ func (o *Organizer) OrganizeTVSeasonPackAuto(releaseDir string, getFileSize func
 	result := &SeasonPackResult{SourceDir: releaseDir}
 	for _, path := range videoFiles {
 		tv, err := naming.ParseTVShowFromPath(path)
-		if err != nil || tv.Season <= 0 || tv.Episode <= 0 {
+		if err != nil || tv.Season < 0 || (tv.Episode <= 0 && tv.SpecialTitle == "") {
 			result.Unresolved = append(result.Unresolved, path)
 			continue
 		}
@@ -818,7 +1023,7 @@ func collectVideoFiles(root string) ([]string, error) {
 		if d.IsDir() {
 			return nil
 		}
-		if video.IsVideo(path) {
+		if video.IsVideo(path) && !naming.IsExtra(path) {
 			files = append(files, path)
 		}
 		return nil
@@ -826,10 +1031,14 @@ func collectVideoFiles(root string) ([]string, error) {
 	return files, err
 }
 
-// findExistingMediaFile scans a directory for existing video files and returns
-// the path and quality info of the best existing file.
+// findExistingMediaFile scans a directory for existing video files of the
+// given edition ("" for the standard cut) and returns the path and quality
+// info of the best one. Other editions are separate versions, so a
+// Director's Cut never replaces the theatrical cut. A stack part (part >
+// 0) only competes with the same part and with single files; a single
+// file competes with every part, since a stack is compared as a unit.
 // Returns ("", nil) if no video files found.
-func (o *Organizer) findExistingMediaFile(dir string) (string, *quality.QualityInfo) {
+func (o *Organizer) findExistingMediaFile(dir, edition string, part int) (string, *quality.QualityInfo) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return "", nil
@@ -843,7 +1052,15 @@ func (o *Organizer) findExistingMediaFile(dir string) (string, *quality.QualityI
 			continue
 		}
 
-		if !video.IsVideo(entry.Name()) {
+		// Extras named with a Jellyfin suffix sit beside the movie but
+		// aren't a version of it.
+		if !video.IsVideo(entry.Name()) || naming.IsExtra(filepath.Join(dir, entry.Name())) {
+			continue
+		}
+		if naming.ParseMovieEdition(entry.Name()) != edition {
+			continue
+		}
+		if existingPart := naming.ParseStackPart(entry.Name()); part > 0 && existingPart > 0 && existingPart != part {
 			continue
 		}
 
@@ -859,6 +1076,93 @@ func (o *Organizer) findExistingMediaFile(dir string) (string, *quality.QualityI
 	return bestPath, bestQuality
 }
 
+// resolutionVersionTarget places a standard-cut movie among the
+// resolution versions in movieDir. When the folder holds the movie at
+// another resolution, the incoming copy goes beside it as "Movie (2001) -
+// 2160p.mkv" and competes only with an existing copy of its own
+// resolution, which it returns. ok is false when resolution versions do
+// not apply: nothing is there at another resolution, or a copy's
+// resolution is unknown.
+func (o *Organizer) resolutionVersionTarget(movieDir, cleanName, ext string, source *quality.QualityInfo) (target, existing string, existingQuality *quality.QualityInfo, ok bool) {
+	if source.Resolution == quality.ResolutionUnknown {
+		return "", "", nil, false
+	}
+	entries, err := os.ReadDir(movieDir)
+	if err != nil {
+		return "", "", nil, false
+	}
+
+	other := false
+	for _, entry := range entries {
+		path := filepath.Join(movieDir, entry.Name())
+		if entry.IsDir() || !video.IsVideo(entry.Name()) || naming.IsExtra(path) {
+			continue
+		}
+		if naming.ParseMovieEdition(entry.Name()) != "" || naming.ParseStackPart(entry.Name()) != 0 {
+			continue
+		}
+		q := quality.Parse(entry.Name())
+		if q.Resolution == quality.ResolutionUnknown {
+			q.Resolution = o.storedResolution(path)
+			q.Score = q.ComputeScore()
+		}
+		switch {
+		case q.Resolution == quality.ResolutionUnknown:
+			return "", "", nil, false
+		case q.Resolution != source.Resolution:
+			other = true
+		case existingQuality == nil || q.IsBetterThan(existingQuality):
+			existing, existingQuality = path, q
+		}
+	}
+	if !other {
+		return "", "", nil, false
+	}
+	if existing != "" {
+		// Keep the name the copy of this resolution already has.
+		return strings.TrimSuffix(existing, filepath.Ext(existing)) + ext, existing, existingQuality, true
+	}
+	label := quality.ResolutionToString(source.Resolution)
+	return filepath.Join(movieDir, fmt.Sprintf("%s - %s%s", cleanName, label, ext)), "", nil, true
+}
+
+// storedResolution is the resolution the database recorded for the
+// library file at path, for organized names that no longer say.
+func (o *Organizer) storedResolution(path string) quality.Resolution {
+	if o.db == nil {
+		return quality.ResolutionUnknown
+	}
+	f, err := o.db.GetMediaFile(path)
+	if err != nil || f == nil {
+		return quality.ResolutionUnknown
+	}
+	return quality.Parse(f.Resolution).Resolution
+}
+
+// stackParts returns every part of the stack path belongs to, or just
+// path when it is not a stack part.
+func stackParts(path string) []string {
+	if naming.ParseStackPart(path) == 0 {
+		return []string{path}
+	}
+	dir := filepath.Dir(path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return []string{path}
+	}
+	base := naming.StripStackPart(path)
+	var parts []string
+	for _, entry := range entries {
+		if entry.IsDir() || !video.IsVideo(entry.Name()) || naming.ParseStackPart(entry.Name()) == 0 || naming.IsExtra(filepath.Join(dir, entry.Name())) {
+			continue
+		}
+		if strings.EqualFold(naming.StripStackPart(entry.Name()), base) {
+			parts = append(parts, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return parts
+}
+
 func findExistingShowDir(libraryPath, showTitle string) string {
 	entries, err := os.ReadDir(libraryPath)
 	if err != nil {
@@ -880,8 +1184,9 @@ func findExistingShowDir(libraryPath, showTitle string) string {
 			return filepath.Join(libraryPath, dirName)
 		}
 
-		// Strip year from directory name, then normalize and compare
-		baseName := yearPattern.ReplaceAllString(dirName, "")
+		// Strip provider ID tags and year from directory name, then
+		// normalize and compare
+		baseName := yearPattern.ReplaceAllString(naming.StripProviderIDs(dirName), "")
 		if database.NormalizeForMatch(baseName) == normalizedTitle {
 			return filepath.Join(libraryPath, dirName)
 		}
@@ -897,10 +1202,16 @@ type FolderOrganizationResult struct {
 	JunkRemoved     []string
 	SamplesRemoved  []string
 	ExtrasSkipped   []string
-	Error           error
+	// ExtrasPlaced lists the library paths of the extras placed beside
+	// the media when extras are kept.
+	ExtrasPlaced []string
+	Error        error
 }
 
 func (o *Organizer) OrganizeFolder(folderPath, libraryPath string, keepExtras bool) (*FolderOrganizationResult, error) {
+	// The skip layout overrides keepExtras.
+	keepExtras = keepExtras && o.extrasLayout != naming.ExtrasSkip
+
 	analysis, err := analyzer.AnalyzeFolder(folderPath)
 	if err != nil {
 		return &FolderOrganizationResult{Error: err}, err
@@ -922,15 +1233,39 @@ func (o *Organizer) OrganizeFolder(folderPath, libraryPath string, keepExtras bo
 		if err != nil || !mediaResult.Success {
 			return result, err
 		}
+		result.SubtitlesCopied = mediaResult.Subtitles
+		// The remaining parts of a stacked movie move with the first.
+		if len(analysis.StackedFiles) > 1 {
+			for _, part := range analysis.StackedFiles[1:] {
+				partResult, err := o.OrganizeMovie(part.Path, libraryPath)
+				if err != nil || !partResult.Success {
+					result.MediaResult = partResult
+					return result, err
+				}
+				mediaResult.SourcePreserved = mediaResult.SourcePreserved || partResult.SourcePreserved
+				result.SubtitlesCopied = append(result.SubtitlesCopied, partResult.Subtitles...)
+			}
+		}
 
 		if !o.dryRun {
-			result.SubtitlesCopied = o.copySubtitles(analysis, filepath.Dir(mediaResult.TargetPath))
-			result.JunkRemoved = o.removeFiles(analysis.JunkFiles)
-			result.SamplesRemoved = o.removeFiles(analysis.SampleFiles)
+			// A preserved (seeding) source must stay byte-identical, so
+			// junk and samples are only cleaned when the release was moved.
+			if !mediaResult.SourcePreserved {
+				result.JunkRemoved = o.removeFiles(analysis.JunkFiles)
+				result.SamplesRemoved = o.removeFiles(analysis.SampleFiles)
+			}
 			if !keepExtras {
 				result.ExtrasSkipped = o.getFilePaths(analysis.ExtraFiles)
 			}
 		}
+		if keepExtras {
+			movie, err := naming.ParseMovieFromPath(analysis.MainMediaFile.Path)
+			if err == nil {
+				result.ExtrasPlaced = o.placeFolderExtras(analysis.ExtraFiles, func(path string, extra naming.ExtraInfo) (*OrganizationResult, error) {
+					return o.OrganizeMovieExtra(path, libraryPath, *movie, extra)
+				})
+			}
+		}
 
 	case analyzer.MediaTypeTVEpisode:
 		mediaResult, err := o.OrganizeTVEpisode(analysis.MainMediaFile.Path, libraryPath)
@@ -939,13 +1274,18 @@ func (o *Organizer) OrganizeFolder(folderPath, libraryPath string, keepExtras bo
 			return result, err
 		}
 
+		result.SubtitlesCopied = mediaResult.Subtitles
+
 		if !o.dryRun {
-			result.SubtitlesCopied = o.copySubtitles(analysis, filepath.Dir(mediaResult.TargetPath))
-			result.JunkRemoved = o.removeFiles(analysis.JunkFiles)
-			result.SamplesRemoved = o.removeFiles(analysis.SampleFiles)
+			if !mediaResult.SourcePreserved {
+				result.JunkRemoved = o.removeFiles(analysis.JunkFiles)
+				result.SamplesRemoved = o.removeFiles(analysis.SampleFiles)
+			}
 		}
+		o.placeFolderTVExtras(result, analysis, libraryPath, keepExtras)
 
 	case analyzer.MediaTypeTVSeason:
+		preserved := false
 		for _, mediaFile := range analysis.MediaFiles {
 			mediaResult, err := o.OrganizeTVEpisode(mediaFile.Path, libraryPath)
 			if err != nil {
@@ -953,12 +1293,15 @@ func (o *Organizer) OrganizeFolder(folderPath, libraryPath string, keepExtras bo
 				return result, err
 			}
 			result.MediaResult = mediaResult
+			result.SubtitlesCopied = append(result.SubtitlesCopied, mediaResult.Subtitles...)
+			preserved = preserved || mediaResult.SourcePreserved
 		}
 
-		if !o.dryRun {
+		if !o.dryRun && !preserved {
 			result.JunkRemoved = o.removeFiles(analysis.JunkFiles)
 			result.SamplesRemoved = o.removeFiles(analysis.SampleFiles)
 		}
+		o.placeFolderTVExtras(result, analysis, libraryPath, keepExtras)
 
 	default:
 		result.Error = fmt.Errorf("unknown media type")
@@ -968,73 +1311,95 @@ func (o *Organizer) OrganizeFolder(folderPath, libraryPath string, keepExtras bo
 	return result, nil
 }
 
-// languageSuffixes lists subtitle language/flag suffixes to strip when matching
-// subtitle stems against the video stem.
-var languageSuffixes = []string{
-	".forced", ".sdh", ".hi",
-	".eng", ".en", ".fra", ".fre", ".ger", ".deu", ".spa", ".por", ".ita",
-	".rus", ".pol", ".chi", ".jpn", ".kor", ".ara", ".ces", ".cze", ".nld",
-	".dut", ".swe", ".nor", ".dan", ".fin", ".tur", ".hun", ".heb", ".ron",
-}
-
-// subtitleMatchesVideo reports whether a subtitle file (identified by its base
-// name without extension) corresponds to the given video stem. One language or
-// flag suffix is stripped before the case-insensitive comparison.
-func subtitleMatchesVideo(subName, videoStem string) bool {
-	subExt := strings.ToLower(filepath.Ext(subName))
-	_ = subExt
-	subStem := subName[:len(subName)-len(filepath.Ext(subName))]
-	lower := strings.ToLower(subStem)
-	videoLower := strings.ToLower(videoStem)
-
-	if lower == videoLower {
-		return true
+// carrySubtitles brings a video's subtitle sidecars into the library,
+// named after targetPath so Jellyfin associates them. They are copied or
+// moved the same way as the video. Returns the names placed.
+func (o *Organizer) carrySubtitles(subs []subtitles.Sidecar, targetPath, libraryPath string) []string {
+	if len(subs) == 0 {
+		return nil
 	}
-	for _, suf := range languageSuffixes {
-		if strings.HasSuffix(lower, suf) {
-			trimmed := lower[:len(lower)-len(suf)]
-			if trimmed == videoLower {
-				return true
-			}
+	opts := transfer.TransferOptions{
+		Timeout:       30 * time.Second,
+		RetryAttempts: 2,
+		TargetUID:     o.targetUID,
+		TargetGID:     o.targetGID,
+		FileMode:      o.fileMode,
+	}
+	transferer := o.transfererFor(libraryPath)
+
+	var placed []string
+	for _, t := range subtitles.Plan(targetPath, subs) {
+		var err error
+		if o.keepSource {
+			_, err = transferer.Copy(t.Source, t.Target, opts)
+		} else {
+			_, err = transferer.Move(t.Source, t.Target, opts)
 		}
+		if err != nil {
+			log.Printf("[organizer] subtitle transfer failed: src=%s dst=%s err=%v", t.Source, t.Target, err)
+			continue
+		}
+		placed = append(placed, filepath.Base(t.Target))
 	}
-	return false
+	return placed
 }
 
-func (o *Organizer) copySubtitles(analysis *analyzer.FolderAnalysis, targetDir string) []string {
-	if analysis.MainMediaFile == nil {
+// carrySidecars brings a video's NFO, artwork and trickplay folder into
+// the library beside targetPath, renamed after it, the same way as the
+// video. Metadata already in the library is left as it is. Returns the
+// names placed.
+func (o *Organizer) carrySidecars(cars []sidecars.Sidecar, targetPath, libraryPath string) []string {
+	if len(cars) == 0 {
 		return nil
 	}
-	videoName := analysis.MainMediaFile.Name
-	videoStem := videoName[:len(videoName)-len(filepath.Ext(videoName))]
+	opts := transfer.TransferOptions{
+		Timeout:       30 * time.Second,
+		RetryAttempts: 2,
+		TargetUID:     o.targetUID,
+		TargetGID:     o.targetGID,
+		FileMode:      o.fileMode,
+	}
+	transferer := o.transfererFor(libraryPath)
+	carry := func(src, dst string) error {
+		var err error
+		if o.keepSource {
+			_, err = transferer.Copy(src, dst, opts)
+		} else {
+			_, err = transferer.Move(src, dst, opts)
+		}
+		return err
+	}
 
-	var copied []string
-	for _, sub := range analysis.SubtitleFiles {
-		if !subtitleMatchesVideo(sub.Name, videoStem) {
+	var placed []string
+	for _, t := range sidecars.Plan(targetPath, cars) {
+		if _, err := os.Stat(t.Target); err == nil {
 			continue
 		}
-		targetPath := filepath.Join(targetDir, sub.Name)
-		opts := transfer.TransferOptions{
-			Timeout:       30 * time.Second,
-			RetryAttempts: 2,
-			TargetUID:     o.targetUID,
-			TargetGID:     o.targetGID,
-			FileMode:      o.fileMode,
-		}
-		_, err := o.transferer.Copy(sub.Path, targetPath, opts)
-		if err == nil {
-			copied = append(copied, sub.Name)
-		} else {
-			log.Printf("[organizer] subtitle copy failed: src=%s dst=%s err=%v", sub.Path, targetPath, err)
+		err := sidecars.Carry(t, carry)
+		if err != nil {
+			log.Printf("[organizer] metadata transfer failed: src=%s dst=%s err=%v", t.Source, t.Target, err)
+			continue
+		}
+		placed = append(placed, filepath.Base(t.Target))
+	}
+	return placed
+}
+
+// stemSidecars keeps the sidecars named after their video.
+func stemSidecars(cars []sidecars.Sidecar) []sidecars.Sidecar {
+	var out []sidecars.Sidecar
+	for _, c := range cars {
+		if c.Suffix != "" {
+			out = append(out, c)
 		}
 	}
-	return copied
+	return out
 }
 
 func (o *Organizer) removeFiles(files []analyzer.FileInfo) []string {
 	var removed []string
 	for _, f := range files {
-		if err := os.Remove(f.Path); err == nil {
+		if _, err := o.trash.Remove(f.Path, "junk in download folder"); err == nil {
 			removed = append(removed, f.Name)
 		}
 	}
@@ -1051,6 +1416,8 @@ func (o *Organizer) getFilePaths(files []analyzer.FileInfo) []string {
 
 // findExistingSeasonDir looks for an existing season directory in the show folder,
 // matching both padded ("Season 01") and non-padded ("Season 1") formats.
+// Season 0 also matches a "Specials" folder, so specials follow whichever
+// convention the show already uses.
 // Returns the full path to the existing directory, or empty string if none found.
 func findExistingSeasonDir(showDir string, season int) string {
 	entries, err := os.ReadDir(showDir)
@@ -1058,23 +1425,13 @@ func findExistingSeasonDir(showDir string, season int) string {
 		return ""
 	}
 
-	seasonPrefix := "season "
-
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
 		}
 
 		dirName := entry.Name()
-		dirLower := strings.ToLower(dirName)
-
-		if !strings.HasPrefix(dirLower, seasonPrefix) {
-			continue
-		}
-
-		numStr := strings.TrimSpace(dirLower[len(seasonPrefix):])
-		var num int
-		if _, err := fmt.Sscanf(numStr, "%d", &num); err == nil && num == season {
+		if num, ok := naming.SeasonFolderNumber(dirName); ok && num == season {
 			return filepath.Join(showDir, dirName)
 		}
 	}
@@ -9,10 +9,11 @@ import (
 	"testing"
 	"time"
 
-	"github.com/Nomadcxx/jellywatch/internal/analyzer"
 	"github.com/Nomadcxx/jellywatch/internal/database"
 	"github.com/Nomadcxx/jellywatch/internal/jellyfin"
 	"github.com/Nomadcxx/jellywatch/internal/naming"
+	"github.com/Nomadcxx/jellywatch/internal/sidecars"
+	"github.com/Nomadcxx/jellywatch/internal/subtitles"
 	"github.com/Nomadcxx/jellywatch/internal/transfer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -571,6 +572,7 @@ func TestFindExistingSeasonDir(t *testing.T) {
 	os.MkdirAll(filepath.Join(tmpDir, "Season 1"), 0755)  // non-padded
 	os.MkdirAll(filepath.Join(tmpDir, "Season 02"), 0755) // correctly padded
 	os.MkdirAll(filepath.Join(tmpDir, "Season 10"), 0755) // two digits
+	os.MkdirAll(filepath.Join(tmpDir, "Specials"), 0755)  // season 0
 
 	tests := []struct {
 		name      string
@@ -596,6 +598,12 @@ func TestFindExistingSeasonDir(t *testing.T) {
 			season:    10,
 			expectDir: filepath.Join(tmpDir, "Season 10"),
 		},
+		{
+			name:      "finds Specials when looking for season 0",
+			showDir:   tmpDir,
+			season:    0,
+			expectDir: filepath.Join(tmpDir, "Specials"),
+		},
 		{
 			name:      "returns empty for non-existent season",
 			showDir:   tmpDir,
@@ -775,6 +783,132 @@ func TestOrganizeTVEpisode_DateBased(t *testing.T) {
 	assert.FileExists(t, incoming, "skipped incoming source must not be moved or deleted")
 }
 
+func TestOrganizeMovie_EditionKeptAlongsideTheatricalCut(t *testing.T) {
+	sourceDir, libraryDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	movieDir := filepath.Join(libraryDir, "Blade Runner (1982)")
+	require.NoError(t, os.MkdirAll(movieDir, 0755))
+	theatrical := filepath.Join(movieDir, "Blade Runner (1982).mkv")
+	createTestFile(t, theatrical, 1024)
+
+	org, err := NewOrganizer([]string{libraryDir}, WithBackend(transfer.BackendNative))
+	require.NoError(t, err)
+
+	incoming := filepath.Join(sourceDir, "Blade.Runner.1982.Directors.Cut.2160p.BluRay.mkv")
+	createTestFile(t, incoming, 1024)
+	result, err := org.OrganizeMovie(incoming, libraryDir)
+	require.NoError(t, err)
+	require.True(t, result.Success, "organize failed: %v", result.Error)
+	assert.Equal(t, filepath.Join(movieDir, "Blade Runner (1982) - Director's Cut.mkv"), result.TargetPath)
+	assert.Nil(t, result.ExistingQuality, "the theatrical cut is not a duplicate of the Director's Cut")
+	assert.FileExists(t, theatrical)
+
+	// A second Director's Cut competes only with the first one.
+	again := filepath.Join(sourceDir, "Blade.Runner.1982.Directors.Cut.1080p.WEB-DL.mkv")
+	createTestFile(t, again, 1024)
+	result, err = org.OrganizeMovie(again, libraryDir)
+	require.NoError(t, err)
+	require.True(t, result.Success, "organize failed: %v", result.Error)
+	require.NotNil(t, result.ExistingQuality, "the earlier Director's Cut is the duplicate")
+	entries, err := os.ReadDir(movieDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.FileExists(t, theatrical)
+}
+
+func TestOrganizeMovie_ResolutionVersionKeptBeside(t *testing.T) {
+	sourceDir, libraryDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+	db, dbCleanup := setupTestDB(t)
+	defer dbCleanup()
+
+	movieDir := filepath.Join(libraryDir, "The Matrix (1999)")
+	require.NoError(t, os.MkdirAll(movieDir, 0755))
+	hd := filepath.Join(movieDir, "The Matrix (1999).mkv")
+	createTestFile(t, hd, 1024)
+	// The organized name no longer says; the database remembers.
+	year := 1999
+	require.NoError(t, db.UpsertMediaFile(&database.MediaFile{
+		Path: hd, NormalizedTitle: "thematrix", Year: &year, MediaType: "movie",
+		Size: 1024, Resolution: "1080p", SourceType: "BluRay",
+	}))
+
+	org, err := NewOrganizer([]string{libraryDir},
+		WithDatabase(db),
+		WithBackend(transfer.BackendNative),
+		WithResolutionVersions(true),
+	)
+	require.NoError(t, err)
+
+	uhd := filepath.Join(sourceDir, "The.Matrix.1999.2160p.REMUX.mkv")
+	createTestFile(t, uhd, 2048)
+	result, err := org.OrganizeMovie(uhd, libraryDir)
+	require.NoError(t, err)
+	require.True(t, result.Success, "organize failed: %v", result.Error)
+	assert.Equal(t, filepath.Join(movieDir, "The Matrix (1999) - 2160p.mkv"), result.TargetPath)
+	assert.Nil(t, result.ExistingQuality, "the 1080p copy is another version, not a duplicate")
+	assert.FileExists(t, hd)
+
+	// A better 2160p copy replaces the 2160p version only.
+	again := filepath.Join(sourceDir, "The.Matrix.1999.2160p.UHD.BluRay.REMUX.HDR.TrueHD.Atmos.mkv")
+	createTestFile(t, again, 4096)
+	result, err = org.OrganizeMovie(again, libraryDir)
+	require.NoError(t, err)
+	require.True(t, result.Success, "organize failed: %v", result.Error)
+	assert.Equal(t, filepath.Join(movieDir, "The Matrix (1999) - 2160p.mkv"), result.TargetPath)
+	require.NotNil(t, result.ExistingQuality, "the earlier 2160p copy is the duplicate")
+	entries, err := os.ReadDir(movieDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.FileExists(t, hd)
+}
+
+func TestOrganizeTVEpisode_SpecialsFollowExistingConvention(t *testing.T) {
+	sourceDir, libraryDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	specialsDir := filepath.Join(libraryDir, "Doctor Who (2005)", "Specials")
+	require.NoError(t, os.MkdirAll(specialsDir, 0755))
+
+	org, err := NewOrganizer([]string{libraryDir}, WithBackend(transfer.BackendNative))
+	require.NoError(t, err)
+
+	numbered := filepath.Join(sourceDir, "Doctor.Who.2005.S00E05.1080p.WEB-DL.mkv")
+	createTestFile(t, numbered, 1024)
+	result, err := org.OrganizeTVEpisode(numbered, libraryDir)
+	require.NoError(t, err)
+	require.True(t, result.Success, "organize failed: %v", result.Error)
+	assert.Equal(t, filepath.Join(specialsDir, "Doctor Who (2005) S00E05.mkv"), result.TargetPath)
+
+	unnumbered := filepath.Join(sourceDir, "Doctor.Who.2005.Christmas.Special.2023.1080p.WEB.mkv")
+	createTestFile(t, unnumbered, 1024)
+	result, err = org.OrganizeTVEpisode(unnumbered, libraryDir)
+	require.NoError(t, err)
+	require.True(t, result.Success, "organize failed: %v", result.Error)
+	assert.Equal(t, filepath.Join(specialsDir, "Doctor Who (2005) - Christmas Special 2023.mkv"), result.TargetPath)
+
+	// The same special again resolves to the same file, never a new entry.
+	again := filepath.Join(sourceDir, "Doctor.Who.2005.Christmas.Special.2023.2160p.WEB.mkv")
+	createTestFile(t, again, 1024)
+	result, err = org.OrganizeTVEpisode(again, libraryDir)
+	require.NoError(t, err)
+	require.True(t, result.Success, "organize failed: %v", result.Error)
+	require.NotNil(t, result.ExistingQuality, "existing special should be found as a duplicate")
+	entries, err := os.ReadDir(specialsDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	// Without an existing folder, specials land in "Season 00".
+	freshLib := t.TempDir()
+	fresh := filepath.Join(sourceDir, "Taskmaster.S00E01.1080p.mkv")
+	createTestFile(t, fresh, 1024)
+	result, err = org.OrganizeTVEpisode(fresh, freshLib)
+	require.NoError(t, err)
+	require.True(t, result.Success, "organize failed: %v", result.Error)
+	assert.Equal(t, filepath.Join(freshLib, "Taskmaster", "Season 00", "Taskmaster S00E01.mkv"), result.TargetPath)
+}
+
 func TestOrganizeTVSeasonPackAuto_ImportsEpisodeFiles(t *testing.T) {
 	sourceDir, libraryDir, cleanup := setupTestEnv(t)
 	defer cleanup()
@@ -835,69 +969,220 @@ func TestOrganizeTVSeasonPackAuto_SkipsUnresolvedSeasonOnlyFile(t *testing.T) {
 }
 
 // ---------------------------------------------------------------------------
-// Task 3.3: stem-matching subtitle copy tests
+// Task 3.3: subtitle sidecar tests
 // ---------------------------------------------------------------------------
 
-// TestCopySubtitles_OnlyStemMatching verifies that copySubtitles copies only
-// subtitle files whose stem matches the video file stem (after stripping one
-// language/flag suffix such as .eng, .en, .forced, .sdh).
-func TestCopySubtitles_OnlyStemMatching(t *testing.T) {
+// TestCarrySubtitles_RenamesToTargetStem verifies that carrySubtitles
+// takes only the subtitles belonging to the video and renames them after
+// the organized file with normalised language and flag tags.
+func TestCarrySubtitles_RenamesToTargetStem(t *testing.T) {
 	sourceDir, targetDir, cleanup := setupTestEnv(t)
 	defer cleanup()
 
-	// Create subtitle files in the source directory.
-	subtitles := []struct {
-		name   string
-		copied bool
-	}{
-		{"Show.S01E01.srt", true},        // exact stem match
-		{"Show.S01E01.eng.srt", true},    // stem match after stripping language suffix
-		{"Show.S01E01.en.srt", true},     // stem match after stripping short lang suffix
-		{"Show.S01E01.forced.srt", true}, // stem match after stripping flag suffix
-		{"OtherShow.S01E01.srt", false},  // different show — must not be copied
-		{"random.srt", false},            // no relation to video — must not be copied
+	video := filepath.Join(sourceDir, "Show.S01E01.720p.WEB.mkv")
+	createTestFile(t, video, 1024)
+	cases := map[string]string{
+		"Show.S01E01.720p.WEB.srt":           "Show (2020) S01E01.srt",
+		"Show.S01E01.720p.WEB.English.srt":   "Show (2020) S01E01.eng.srt",
+		"Show.S01E01.720p.WEB.fr.srt":        "Show (2020) S01E01.fre.srt",
+		"Show.S01E01.720p.WEB.en.forced.srt": "Show (2020) S01E01.eng.forced.srt",
+		"Show.S01E01.720p.WEB.eng.SDH.srt":   "Show (2020) S01E01.eng.sdh.srt",
+		"OtherShow.S01E01.srt":               "",
+		"random.srt":                         "",
 	}
-
-	var subFiles []analyzer.FileInfo
-	for _, s := range subtitles {
-		p := filepath.Join(sourceDir, s.name)
-		require.NoError(t, os.WriteFile(p, []byte("sub"), 0644))
-		subFiles = append(subFiles, analyzer.FileInfo{Path: p, Name: s.name})
-	}
-
-	// Build a minimal FolderAnalysis with the matching video file.
-	analysis := &analyzer.FolderAnalysis{
-		MainMediaFile: &analyzer.FileInfo{
-			Path: filepath.Join(sourceDir, "Show.S01E01.mkv"),
-			Name: "Show.S01E01.mkv",
-		},
-		SubtitleFiles: subFiles,
+	for name := range cases {
+		require.NoError(t, os.WriteFile(filepath.Join(sourceDir, name), []byte("sub"), 0644))
 	}
 
 	org, err := NewOrganizer([]string{targetDir}, WithBackend(transfer.BackendNative))
 	require.NoError(t, err)
 
-	copied := org.copySubtitles(analysis, targetDir)
+	target := filepath.Join(targetDir, "Show (2020) S01E01.mkv")
+	placed := org.carrySubtitles(subtitles.Find(video), target, targetDir)
 
-	for _, s := range subtitles {
-		_, err := os.Stat(filepath.Join(targetDir, s.name))
-		if s.copied {
-			assert.NoError(t, err, "%s should have been copied", s.name)
-		} else {
-			assert.True(t, os.IsNotExist(err), "%s should NOT have been copied", s.name)
+	var want []string
+	for src, dst := range cases {
+		if dst == "" {
+			assert.FileExists(t, filepath.Join(sourceDir, src), "%s must stay behind", src)
+			continue
 		}
+		want = append(want, dst)
+		assert.FileExists(t, filepath.Join(targetDir, dst))
+		assert.NoFileExists(t, filepath.Join(sourceDir, src), "%s should have moved", src)
 	}
+	assert.ElementsMatch(t, want, placed)
+}
 
-	// Verify return value lists only the copied names.
-	copiedSet := make(map[string]bool, len(copied))
-	for _, n := range copied {
-		copiedSet[n] = true
+// TestCarrySidecars_RenamesToTargetStem verifies that a movie's NFO,
+// artwork and trickplay folder follow it, renamed after the organized
+// file, while a scene NFO and metadata already in the library stay put.
+func TestCarrySidecars_RenamesToTargetStem(t *testing.T) {
+	sourceDir, targetDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	video := filepath.Join(sourceDir, "Heat.1995.1080p.BluRay.mkv")
+	createTestFile(t, video, 1024)
+	files := map[string]string{
+		"Heat.1995.1080p.BluRay.nfo":                 "<movie></movie>",
+		"Heat.1995.1080p.BluRay-thumb.jpg":           "jpg",
+		"Heat.1995.1080p.BluRay.trickplay/320/0.jpg": "jpg",
+		"poster.jpg": "new poster",
+		"GRP.nfo":    "Released by GRP",
 	}
-	for _, s := range subtitles {
-		if s.copied {
-			assert.True(t, copiedSet[s.name], "%s should be in copied list", s.name)
-		} else {
-			assert.False(t, copiedSet[s.name], "%s should not be in copied list", s.name)
-		}
+	for name, body := range files {
+		path := filepath.Join(sourceDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(body), 0644))
 	}
+	require.NoError(t, os.WriteFile(filepath.Join(targetDir, "poster.jpg"), []byte("old poster"), 0644))
+
+	org, err := NewOrganizer([]string{targetDir}, WithBackend(transfer.BackendNative))
+	require.NoError(t, err)
+
+	target := filepath.Join(targetDir, "Heat (1995).mkv")
+	placed := org.carrySidecars(sidecars.Find(video), target, targetDir)
+
+	assert.ElementsMatch(t, []string{"Heat (1995).nfo", "Heat (1995)-thumb.jpg", "Heat (1995).trickplay"}, placed)
+	assert.FileExists(t, filepath.Join(targetDir, "Heat (1995).trickplay", "320", "0.jpg"))
+	assert.NoDirExists(t, filepath.Join(sourceDir, "Heat.1995.1080p.BluRay.trickplay"))
+	assert.FileExists(t, filepath.Join(sourceDir, "GRP.nfo"))
+	assert.FileExists(t, filepath.Join(sourceDir, "poster.jpg"))
+	poster, err := os.ReadFile(filepath.Join(targetDir, "poster.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, "old poster", string(poster))
+}
+
+func TestOrganizeMovie_LibraryHardlinkPreservesSource(t *testing.T) {
+	root := t.TempDir()
+	sourceDir := filepath.Join(root, "downloads")
+	linkLib := filepath.Join(root, "movies-linked")
+	otherLib := filepath.Join(root, "movies-other")
+	require.NoError(t, os.MkdirAll(sourceDir, 0755))
+
+	src := filepath.Join(sourceDir, "The.Matrix.1999.1080p.BluRay.mkv")
+	createTestFile(t, src, 1024)
+
+	org, err := NewOrganizer([]string{linkLib, otherLib},
+		WithTransferer(transfer.NewNativeTransferer(1024)),
+		WithLibraryTransferer(linkLib, transfer.NewHardlinkTransferer(transfer.NewNativeTransferer(1024))),
+	)
+	require.NoError(t, err)
+
+	result, err := org.OrganizeMovie(src, linkLib)
+	require.NoError(t, err)
+	require.True(t, result.Success, "organize failed: %v", result.Error)
+	assert.True(t, result.SourcePreserved)
+	assert.Equal(t, "hardlink", result.LinkType)
+
+	srcInfo, err := os.Stat(src)
+	require.NoError(t, err, "source must remain for seeding")
+	dstInfo, err := os.Stat(result.TargetPath)
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(srcInfo, dstInfo))
+}
+
+func TestOrganizeTV_MultiEpisode(t *testing.T) {
+	root := t.TempDir()
+	sourceDir := filepath.Join(root, "downloads")
+	lib := filepath.Join(root, "tv")
+	seasonDir := filepath.Join(lib, "Show", "Season 01")
+	require.NoError(t, os.MkdirAll(sourceDir, 0755))
+	require.NoError(t, os.MkdirAll(seasonDir, 0755))
+
+	org, err := NewOrganizer([]string{lib}, WithTransferer(transfer.NewNativeTransferer(1024)))
+	require.NoError(t, err)
+
+	t.Run("better span replaces covered single episodes", func(t *testing.T) {
+		e01 := filepath.Join(seasonDir, "Show S01E01.720p.mkv")
+		e02 := filepath.Join(seasonDir, "Show S01E02.720p.mkv")
+		createTestFile(t, e01, 1024)
+		createTestFile(t, e02, 1024)
+
+		src := filepath.Join(sourceDir, "Show.S01E01E02.1080p.BluRay.mkv")
+		createTestFile(t, src, 1024)
+
+		result, err := org.OrganizeTVEpisode(src, lib)
+		require.NoError(t, err)
+		require.True(t, result.Success, "organize failed: %v (%s)", result.Error, result.SkipReason)
+		assert.Equal(t, filepath.Join(seasonDir, "Show S01E01-E02.mkv"), result.TargetPath)
+		assert.NoFileExists(t, e01)
+		assert.NoFileExists(t, e02)
+	})
+
+	t.Run("single episode inside an existing span is skipped", func(t *testing.T) {
+		src := filepath.Join(sourceDir, "Show.S01E02.2160p.BluRay.mkv")
+		createTestFile(t, src, 1024)
+
+		result, err := org.OrganizeTVEpisode(src, lib)
+		require.NoError(t, err)
+		assert.True(t, result.Skipped)
+		assert.Contains(t, result.SkipReason, "multi-episode")
+		assert.FileExists(t, filepath.Join(seasonDir, "Show S01E01-E02.mkv"))
+		assert.FileExists(t, src)
+	})
+}
+
+func TestOrganizeFolder_KeepSourceStillRemovesJunk(t *testing.T) {
+	root := t.TempDir()
+	release := filepath.Join(root, "downloads", "Heat.1995.1080p.BluRay-GRP")
+	lib := filepath.Join(root, "movies")
+	require.NoError(t, os.MkdirAll(release, 0755))
+	require.NoError(t, os.MkdirAll(lib, 0755))
+
+	src := filepath.Join(release, "Heat.1995.1080p.BluRay-GRP.mkv")
+	junk := filepath.Join(release, "Heat.1995.1080p.BluRay-GRP.nfo")
+	createTestFile(t, src, 1024)
+	require.NoError(t, os.WriteFile(junk, []byte("nfo"), 0644))
+
+	// keepSource copies the video but, unlike a link backend, does not mark
+	// the release as seeding, so junk cleanup still runs.
+	org, err := NewOrganizer([]string{lib},
+		WithKeepSource(true),
+		WithTransferer(transfer.NewNativeTransferer(1024)),
+	)
+	require.NoError(t, err)
+
+	result, err := org.OrganizeFolder(release, lib, false)
+	require.NoError(t, err)
+	require.NotNil(t, result.MediaResult)
+	require.True(t, result.MediaResult.Success, "organize failed: %v", result.MediaResult.Error)
+	assert.False(t, result.MediaResult.SourcePreserved)
+	assert.FileExists(t, src)
+	assert.NoFileExists(t, junk)
+}
+
+func TestOrganizeFolder_StackedMovieMovesAllParts(t *testing.T) {
+	root := t.TempDir()
+	release := filepath.Join(root, "downloads", "Heat.1995.1080p.BluRay-GRP")
+	lib := filepath.Join(root, "movies")
+	movieDir := filepath.Join(lib, "Heat (1995)")
+	require.NoError(t, os.MkdirAll(release, 0755))
+	require.NoError(t, os.MkdirAll(movieDir, 0755))
+
+	older := filepath.Join(movieDir, "Heat (1995) 720p.mkv")
+	createTestFile(t, older, 1024)
+	createTestFile(t, filepath.Join(release, "Heat.1995.1080p.BluRay-GRP.cd1.mkv"), 1024)
+	createTestFile(t, filepath.Join(release, "Heat.1995.1080p.BluRay-GRP.cd2.mkv"), 1024)
+
+	org, err := NewOrganizer([]string{lib}, WithBackend(transfer.BackendNative))
+	require.NoError(t, err)
+
+	result, err := org.OrganizeFolder(release, lib, false)
+	require.NoError(t, err)
+	require.NotNil(t, result.MediaResult)
+	require.True(t, result.MediaResult.Success, "organize failed: %v", result.MediaResult.Error)
+	assert.FileExists(t, filepath.Join(movieDir, "Heat (1995) - part1.mkv"))
+	assert.FileExists(t, filepath.Join(movieDir, "Heat (1995) - part2.mkv"))
+	assert.NoFileExists(t, older, "the stack replaces the worse single file")
+
+	// A better single file replaces the stack as a whole.
+	single := filepath.Join(root, "downloads", "Heat.1995.2160p.UHD.BluRay.mkv")
+	createTestFile(t, single, 1024)
+	movieResult, err := org.OrganizeMovie(single, lib)
+	require.NoError(t, err)
+	require.True(t, movieResult.Success, "organize failed: %v", movieResult.Error)
+	entries, err := os.ReadDir(movieDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Heat (1995).mkv", entries[0].Name())
 }
@@ -0,0 +1,152 @@
+package organizer
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+)
+
+// EpisodeTitleResolver looks up episode titles for the {EpisodeTitle}
+// template token. sonarr.AbsoluteResolver implements it.
+type EpisodeTitleResolver interface {
+	EpisodeTitle(title string, season, episode int) (string, error)
+}
+
+// WithLibraryTemplates names files organized into libraryPath with tmpl
+// instead of the built-in Jellyfin layout. A zero Templates is ignored.
+func WithLibraryTemplates(libraryPath string, tmpl naming.Templates) func(*Organizer) {
+	return func(o *Organizer) {
+		if strings.TrimSpace(libraryPath) == "" || tmpl.IsZero() {
+			return
+		}
+		if o.libTemplates == nil {
+			o.libTemplates = make(map[string]naming.Templates)
+		}
+		o.libTemplates[filepath.Clean(libraryPath)] = tmpl
+	}
+}
+
+// WithProviderIDDatabase reads {ImdbID}/{TmdbID}/{TvdbID} from db without
+// the library bookkeeping WithDatabase enables. Organizers built with
+// WithDatabase already use that database for IDs.
+func WithProviderIDDatabase(db *database.MediaDB) func(*Organizer) {
+	return func(o *Organizer) {
+		o.idDB = db
+	}
+}
+
+// WithEpisodeTitleResolver fills {EpisodeTitle} in episode templates.
+// Without one the token renders empty.
+func WithEpisodeTitleResolver(r EpisodeTitleResolver) func(*Organizer) {
+	return func(o *Organizer) {
+		o.episodeTitles = r
+	}
+}
+
+func (o *Organizer) templatesFor(libraryPath string) (naming.Templates, bool) {
+	tmpl, ok := o.libTemplates[filepath.Clean(libraryPath)]
+	return tmpl, ok
+}
+
+func (o *Organizer) providerIDDB() *database.MediaDB {
+	if o.idDB != nil {
+		return o.idDB
+	}
+	return o.db
+}
+
+// movieTemplateValues collects the template values a parsed movie name
+// doesn't carry: release details from the source file and provider IDs
+// from the database.
+func (o *Organizer) movieTemplateValues(sourcePath string, movie *naming.MovieInfo) naming.TemplateValues {
+	var v naming.TemplateValues
+	v.FillRelease(sourcePath)
+	db := o.providerIDDB()
+	if db == nil {
+		return v
+	}
+	year, _ := strconv.Atoi(movie.Year)
+	if rec, err := db.GetMovieByTitle(movie.Title, year); err == nil && rec != nil {
+		if rec.ImdbID != nil {
+			v.ImdbID = *rec.ImdbID
+		}
+		if rec.TmdbID != nil {
+			v.TmdbID = strconv.Itoa(*rec.TmdbID)
+		}
+	}
+	if v.ImdbID == "" && v.TmdbID == "" {
+		// A manual alias may know the IDs before the library does.
+		if alias, err := db.AliasIDs(database.AliasMediaMovie, movie.Title, year); err == nil && alias != nil {
+			if alias.ImdbID != nil {
+				v.ImdbID = *alias.ImdbID
+			}
+			if alias.TmdbID != nil {
+				v.TmdbID = strconv.Itoa(*alias.TmdbID)
+			}
+		}
+	}
+	return v
+}
+
+// tvTemplateValues is movieTemplateValues for episodes. The episode title
+// is only looked up when the template uses it.
+func (o *Organizer) tvTemplateValues(sourcePath string, tv *naming.TVShowInfo, tmpl naming.Templates) naming.TemplateValues {
+	var v naming.TemplateValues
+	v.FillRelease(sourcePath)
+	if db := o.providerIDDB(); db != nil {
+		year, _ := strconv.Atoi(tv.Year)
+		if rec, err := db.GetSeriesByTitle(tv.Title, year); err == nil && rec != nil {
+			if rec.ImdbID != nil {
+				v.ImdbID = *rec.ImdbID
+			}
+			if rec.TvdbID != nil {
+				v.TvdbID = strconv.Itoa(*rec.TvdbID)
+			}
+		}
+		if v.ImdbID == "" && v.TvdbID == "" {
+			if alias, err := db.AliasIDs(database.AliasMediaSeries, tv.Title, year); err == nil && alias != nil {
+				if alias.ImdbID != nil {
+					v.ImdbID = *alias.ImdbID
+				}
+				if alias.TvdbID != nil {
+					v.TvdbID = strconv.Itoa(*alias.TvdbID)
+				}
+			}
+		}
+	}
+	if o.episodeTitles != nil && tv.Episode > 0 &&
+		naming.TemplateUses(tmpl.WithDefaults().EpisodeFile, "EpisodeTitle") {
+		title, err := o.episodeTitles.EpisodeTitle(tv.Title, tv.Season, tv.Episode)
+		if err != nil {
+			log.Printf("[organizer] episode title lookup failed for %s S%02dE%02d: %v", tv.Title, tv.Season, tv.Episode, err)
+		}
+		v.EpisodeTitle = title
+	}
+	return v
+}
+
+// findTemplatedDir returns the existing folder in parent that names the
+// same item as want once provider ID tags are ignored, so a folder created
+// before the ID was known is reused instead of duplicated.
+func findTemplatedDir(parent, want string) string {
+	target := filepath.Join(parent, want)
+	if _, err := os.Stat(target); err == nil {
+		return target
+	}
+	entries, err := os.ReadDir(parent)
+	if err != nil {
+		return ""
+	}
+	bare := naming.StripProviderIDs(want)
+	for _, entry := range entries {
+		if entry.IsDir() && strings.EqualFold(naming.StripProviderIDs(entry.Name()), bare) {
+			return filepath.Join(parent, entry.Name())
+		}
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+	"github.com/Nomadcxx/jellywatch/internal/transfer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEpisodeTitles map[string]string
+
+func (f fakeEpisodeTitles) EpisodeTitle(title string, season, episode int) (string, error) {
+	return f[naming.FormatEpisodeTag(season, episode, 0)], nil
+}
+
+func TestOrganizeTVEpisode_LibraryTemplates(t *testing.T) {
+	sourceDir, libraryDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	tmpl := naming.Templates{
+		SeasonFolder: "S{Season}",
+		EpisodeFile:  "{Title} S{Season}E{Episode} - {EpisodeTitle} [{Resolution} {Source}]",
+	}
+	org, err := NewOrganizer([]string{libraryDir},
+		WithBackend(transfer.BackendNative),
+		WithLibraryTemplates(libraryDir, tmpl),
+		WithEpisodeTitleResolver(fakeEpisodeTitles{"S01E01": "Pilot"}),
+	)
+	require.NoError(t, err)
+
+	src := filepath.Join(sourceDir, "Severance.S01E01.720p.WEB-DL.mkv")
+	createTestFile(t, src, 1024)
+	result, err := org.OrganizeTVEpisode(src, libraryDir)
+	require.NoError(t, err)
+	require.True(t, result.Success, "organize failed: %v", result.Error)
+	seasonDir := filepath.Join(libraryDir, "Severance", "S01")
+	assert.Equal(t, filepath.Join(seasonDir, "Severance S01E01 - Pilot [720p WEB-DL].mkv"), result.TargetPath)
+
+	// Quality survives in templated names, so a better release replaces
+	// the old file and a worse one is skipped.
+	better := filepath.Join(sourceDir, "Severance.S01E01.1080p.BluRay.mkv")
+	createTestFile(t, better, 1024)
+	result, err = org.OrganizeTVEpisode(better, libraryDir)
+	require.NoError(t, err)
+	require.True(t, result.Success, "organize failed: %v", result.Error)
+	assert.Equal(t, filepath.Join(seasonDir, "Severance S01E01 - Pilot [1080p BluRay].mkv"), result.TargetPath)
+	entries, err := os.ReadDir(seasonDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	worse := filepath.Join(sourceDir, "Severance.S01E01.480p.HDTV.mkv")
+	createTestFile(t, worse, 1024)
+	result, err = org.OrganizeTVEpisode(worse, libraryDir)
+	require.NoError(t, err)
+	assert.True(t, result.Skipped)
+
+	// Unknown episode titles drop the separator.
+	second := filepath.Join(sourceDir, "Severance.S01E02.1080p.WEB-DL.mkv")
+	createTestFile(t, second, 1024)
+	result, err = org.OrganizeTVEpisode(second, libraryDir)
+	require.NoError(t, err)
+	require.True(t, result.Success, "organize failed: %v", result.Error)
+	assert.Equal(t, filepath.Join(seasonDir, "Severance S01E02 [1080p WEB-DL].mkv"), result.TargetPath)
+}
+
+func TestOrganizeMovie_LibraryTemplatesReuseTaggedFolder(t *testing.T) {
+	sourceDir, libraryDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	tagged := filepath.Join(libraryDir, "The Matrix (1999) [imdbid-tt0133093]")
+	require.NoError(t, os.MkdirAll(tagged, 0755))
+
+	org, err := NewOrganizer([]string{libraryDir},
+		WithBackend(transfer.BackendNative),
+		WithLibraryTemplates(libraryDir, naming.Templates{
+			MovieFolder: "{Title} ({Year}) [imdbid-{ImdbID}]",
+			MovieFile:   "{Title} ({Year}) - {Resolution}",
+		}),
+	)
+	require.NoError(t, err)
+
+	src := filepath.Join(sourceDir, "The.Matrix.1999.2160p.UHD.BluRay.x265-GROUP.mkv")
+	createTestFile(t, src, 1024)
+	result, err := org.OrganizeMovie(src, libraryDir)
+	require.NoError(t, err)
+	require.True(t, result.Success, "organize failed: %v", result.Error)
+	assert.Equal(t, filepath.Join(tagged, "The Matrix (1999) - 2160p.mkv"), result.TargetPath,
+		"the folder tagged with the ID is reused even though the ID is unknown here")
+}
+
+func TestFindExistingShowDirIgnoresProviderIDs(t *testing.T) {
+	libraryDir := t.TempDir()
+	showDir := filepath.Join(libraryDir, "Severance (2022) [tvdbid-371980]")
+	require.NoError(t, os.MkdirAll(showDir, 0755))
+	assert.Equal(t, showDir, findExistingShowDir(libraryDir, "Severance"))
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/Nomadcxx/jellywatch/internal/paths"
 	"github.com/Nomadcxx/jellywatch/internal/permissions"
 	"github.com/Nomadcxx/jellywatch/internal/transfer"
+	"github.com/Nomadcxx/jellywatch/internal/trash"
 )
 
 // FileInfo represents a media file in a plan
@@ -34,6 +35,7 @@ type DuplicateGroup struct {
 	MediaType string   `json:"media_type"`
 	Season    *int     `json:"season,omitempty"`
 	Episode   *int     `json:"episode,omitempty"`
+	Profile   string   `json:"quality_profile,omitempty"`
 	Keep      FileInfo `json:"keep"`
 	Delete    FileInfo `json:"delete"`
 }
@@ -276,6 +278,12 @@ func executeDelete(db *database.MediaDB, item AuditItem, action AuditAction, dry
 		return nil
 	}
 
+	var bin *trash.Bin
+	if cfg != nil {
+		bin = trash.New(db, cfg.Trash)
+	}
+	reason := "audit: " + action.Reasoning
+
 	// Check if we can delete the file (using uid/gid = -1 to not change ownership)
 	canDelete, err := permissions.CanDelete(file.Path)
 	if err != nil {
@@ -289,14 +297,14 @@ func executeDelete(db *database.MediaDB, item AuditItem, action AuditAction, dry
 			gid, _ = cfg.Permissions.ResolveGID()
 		}
 		if err := permissions.FixPermissions(file.Path, uid, gid); err != nil {
-			if removeErr := os.Remove(file.Path); removeErr != nil {
+			if _, removeErr := bin.Remove(file.Path, reason); removeErr != nil {
 				_ = db.DeleteMediaFileByID(file.ID)
 				return permissions.NewPermissionError(file.Path, "delete", removeErr, uid, gid)
 			}
 		}
 	}
 
-	if err := os.Remove(file.Path); err != nil {
+	if _, err := bin.Remove(file.Path, reason); err != nil {
 		_ = db.DeleteMediaFileByID(file.ID)
 		if os.IsNotExist(err) {
 			return nil
@@ -0,0 +1,26 @@
+package probe
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteFakeBinary writes an executable stand-in for ffprobe into dir and
+// returns its path. It prints output on stdout and exits with exitCode, so
+// tests can feed Prober canned ffprobe JSON (or a failure) without ffprobe
+// installed.
+func WriteFakeBinary(dir, output string, exitCode int) (string, error) {
+	dataPath := filepath.Join(dir, "ffprobe-output.json")
+	if err := os.WriteFile(dataPath, []byte(output), 0644); err != nil {
+		return "", err
+	}
+	script := fmt.Sprintf("#!/bin/sh\ncat '%s'\nif [ %d -ne 0 ]; then echo 'Invalid data found when processing input' >&2; fi\nexit %d\n",
+		strings.ReplaceAll(dataPath, "'", `'\''`), exitCode, exitCode)
+	path := filepath.Join(dir, "ffprobe")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,40 @@
+package probe
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// minStreamCoverage is how much of the container duration every video
+	// and audio stream must span. Streams legitimately end a little early
+	// (trailing silence, encoder padding); one stopping well short means
+	// the tail of the file is missing.
+	minStreamCoverage = 0.9
+	// minByteCoverage is the share of the muxer's recorded stream bytes
+	// the file must hold.
+	minByteCoverage = 0.9
+)
+
+// CheckIntegrity reports whether a freshly probed file of fileSize bytes
+// looks complete. It returns an error wrapping ErrCorrupt describing the
+// first problem found, or nil. Results loaded from the database carry no
+// integrity evidence beyond the duration and video codec.
+func (r *Result) CheckIntegrity(fileSize int64) error {
+	if r.VideoCodec == "" {
+		return fmt.Errorf("%w: no video stream", ErrCorrupt)
+	}
+	if r.Duration <= 0 {
+		return fmt.Errorf("%w: no duration", ErrCorrupt)
+	}
+	for _, d := range r.streamDurations {
+		if float64(d) < float64(r.Duration)*minStreamCoverage {
+			return fmt.Errorf("%w: stream ends at %s of %s (truncated)", ErrCorrupt,
+				d.Round(time.Second), r.Duration.Round(time.Second))
+		}
+	}
+	if r.expectedBytes > 0 && fileSize > 0 && float64(fileSize) < float64(r.expectedBytes)*minByteCoverage {
+		return fmt.Errorf("%w: file holds %d of %d stream bytes (truncated)", ErrCorrupt, fileSize, r.expectedBytes)
+	}
+	return nil
+}
@@ -0,0 +1,329 @@
+// Package probe inspects media files with ffprobe. It measures what the
+// file actually holds (duration, resolution, codecs, HDR format, audio and
+// subtitle tracks) so quality scoring doesn't have to trust the release
+// name, and it flags truncated or corrupt downloads before they are
+// organized.
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nomadcxx/jellywatch/internal/quality"
+)
+
+// DefaultTimeout bounds a single ffprobe run.
+const DefaultTimeout = 30 * time.Second
+
+var (
+	// ErrUnavailable is returned when ffprobe can't be run at all (missing
+	// binary, timeout). Callers treat it as "no data", not as a bad file.
+	ErrUnavailable = errors.New("ffprobe unavailable")
+	// ErrCorrupt is returned when ffprobe can't read the file or its
+	// streams show it is truncated or damaged.
+	ErrCorrupt = errors.New("corrupt media file")
+)
+
+// HDR formats as recorded in Result.HDR. SDR is "".
+const (
+	HDRDolbyVision = "DV"
+	HDR10Plus      = "HDR10+"
+	HDR10          = "HDR10"
+	HDRHLG         = "HLG"
+)
+
+// Track describes one audio or subtitle stream.
+type Track struct {
+	Codec    string `json:"codec"`
+	Profile  string `json:"profile,omitempty"`
+	Language string `json:"language,omitempty"`
+	Channels int    `json:"channels,omitempty"`
+	Forced   bool   `json:"forced,omitempty"`
+}
+
+// Result is what ffprobe measured for a file.
+type Result struct {
+	Duration       time.Duration
+	Width          int
+	Height         int
+	VideoCodec     string // ffprobe codec name: "hevc", "h264", "av1"
+	HDR            string // HDRDolbyVision, HDR10Plus, HDR10, HDRHLG or "" for SDR
+	AudioTracks    []Track
+	SubtitleTracks []Track
+	ProbedAt       time.Time
+
+	// Integrity evidence, only present on fresh probes.
+	streamDurations []time.Duration // video and audio streams that report one
+	expectedBytes   int64           // muxer byte statistics (mkvmerge NUMBER_OF_BYTES)
+}
+
+// Prober runs ffprobe.
+type Prober struct {
+	binary  string
+	timeout time.Duration
+}
+
+// New returns a Prober running the ffprobe binary at path ("" looks it up
+// on PATH). A zero timeout uses DefaultTimeout.
+func New(path string, timeout time.Duration) *Prober {
+	if strings.TrimSpace(path) == "" {
+		path = "ffprobe"
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Prober{binary: path, timeout: timeout}
+}
+
+// Available reports whether the ffprobe binary can be found.
+func (p *Prober) Available() bool {
+	_, err := exec.LookPath(p.binary)
+	return err == nil
+}
+
+// Probe runs ffprobe on path. A file ffprobe rejects returns an error
+// wrapping ErrCorrupt; failing to run ffprobe returns one wrapping
+// ErrUnavailable.
+func (p *Prober) Probe(ctx context.Context, path string) (*Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.binary,
+		"-v", "error",
+		"-print_format", "json",
+		"-show_format",
+		"-show_streams",
+		path)
+	out, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrUnavailable, path, ctx.Err())
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("%w: ffprobe could not read %s: %s", ErrCorrupt, path, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	result, err := Parse(out)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrCorrupt, path, err)
+	}
+	result.ProbedAt = time.Now()
+	return result, nil
+}
+
+type ffprobeOutput struct {
+	Streams []ffprobeStream `json:"streams"`
+	Format  struct {
+		Duration string `json:"duration"`
+	} `json:"format"`
+}
+
+type ffprobeStream struct {
+	CodecType     string            `json:"codec_type"`
+	CodecName     string            `json:"codec_name"`
+	Profile       string            `json:"profile"`
+	Width         int               `json:"width"`
+	Height        int               `json:"height"`
+	Channels      int               `json:"channels"`
+	ColorTransfer string            `json:"color_transfer"`
+	Duration      string            `json:"duration"`
+	Tags          map[string]string `json:"tags"`
+	Disposition   map[string]int    `json:"disposition"`
+	SideDataList  []map[string]any  `json:"side_data_list"`
+}
+
+// Parse reads ffprobe's JSON output (-show_format -show_streams).
+func Parse(data []byte) (*Result, error) {
+	var out ffprobeOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("parse ffprobe output: %w", err)
+	}
+
+	r := &Result{Duration: parseSeconds(out.Format.Duration)}
+	for _, s := range out.Streams {
+		switch s.CodecType {
+		case "video":
+			// Cover art is a video stream too; it has no duration to check
+			// and must not decide the resolution.
+			if s.Disposition["attached_pic"] == 1 {
+				continue
+			}
+			if r.VideoCodec == "" {
+				r.VideoCodec = s.CodecName
+				r.Width, r.Height = s.Width, s.Height
+				r.HDR = hdrFormat(s)
+			}
+		case "audio":
+			r.AudioTracks = append(r.AudioTracks, Track{
+				Codec:    s.CodecName,
+				Profile:  s.Profile,
+				Language: tag(s.Tags, "language"),
+				Channels: s.Channels,
+			})
+		case "subtitle":
+			r.SubtitleTracks = append(r.SubtitleTracks, Track{
+				Codec:    s.CodecName,
+				Language: tag(s.Tags, "language"),
+				Forced:   s.Disposition["forced"] == 1,
+			})
+		default:
+			continue
+		}
+
+		if s.CodecType == "video" || s.CodecType == "audio" {
+			d := parseSeconds(s.Duration)
+			if d == 0 {
+				d = parseClock(tag(s.Tags, "DURATION"))
+			}
+			if d > 0 {
+				r.streamDurations = append(r.streamDurations, d)
+			}
+		}
+		if n, err := strconv.ParseInt(tag(s.Tags, "NUMBER_OF_BYTES"), 10, 64); err == nil {
+			r.expectedBytes += n
+		}
+	}
+	return r, nil
+}
+
+// hdrFormat classifies a video stream's HDR signalling.
+func hdrFormat(s ffprobeStream) string {
+	for _, sd := range s.SideDataList {
+		kind, _ := sd["side_data_type"].(string)
+		switch {
+		case strings.Contains(kind, "DOVI"):
+			return HDRDolbyVision
+		case strings.Contains(kind, "2094-40"):
+			return HDR10Plus
+		}
+	}
+	switch s.ColorTransfer {
+	case "smpte2084":
+		return HDR10
+	case "arib-std-b67":
+		return HDRHLG
+	}
+	return ""
+}
+
+// tag looks up a stream tag; muxers disagree on key case.
+func tag(tags map[string]string, key string) string {
+	if v, ok := tags[key]; ok {
+		return v
+	}
+	for k, v := range tags {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
+
+func parseSeconds(s string) time.Duration {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || f <= 0 {
+		return 0
+	}
+	return time.Duration(f * float64(time.Second))
+}
+
+// parseClock parses Matroska "HH:MM:SS.nnnnnnnnn" duration tags.
+func parseClock(s string) time.Duration {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) != 3 {
+		return 0
+	}
+	h, err1 := strconv.Atoi(parts[0])
+	m, err2 := strconv.Atoi(parts[1])
+	sec, err3 := strconv.ParseFloat(parts[2], 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return 0
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sec*float64(time.Second))
+}
+
+// Quality converts the probe to the measured values quality scoring
+// prefers over filename markers. A nil Result yields nil.
+func (r *Result) Quality() *quality.Probed {
+	if r == nil {
+		return nil
+	}
+	p := &quality.Probed{
+		Resolution: quality.ResolutionFromDimensions(r.Width, r.Height),
+		Codec:      codecName(r.VideoCodec),
+	}
+	switch r.HDR {
+	case HDRDolbyVision:
+		p.HDR = quality.DolbyVision
+	case HDR10Plus:
+		p.HDR = quality.HDR10Plus
+	case HDR10:
+		p.HDR = quality.HDR10
+	case HDRHLG:
+		p.HDR = quality.HLG
+	}
+	for _, t := range r.AudioTracks {
+		if a := audioCodec(t); a > p.Audio {
+			p.Audio = a
+		}
+	}
+	return p
+}
+
+// codecName maps ffprobe video codec names to the names CodecToString
+// uses for filename markers.
+func codecName(codec string) string {
+	switch codec {
+	case "hevc":
+		return "x265"
+	case "h264":
+		return "x264"
+	case "av1":
+		return "AV1"
+	case "vp9":
+		return "VP9"
+	case "mpeg4":
+		return "XviD"
+	}
+	return ""
+}
+
+func audioCodec(t Track) quality.AudioCodec {
+	profile := strings.ToLower(t.Profile)
+	switch t.Codec {
+	case "truehd":
+		if strings.Contains(profile, "atmos") {
+			return quality.AudioAtmos
+		}
+		return quality.AudioTrueHD
+	case "eac3":
+		if strings.Contains(profile, "atmos") {
+			return quality.AudioAtmos
+		}
+		return quality.AudioEAC3
+	case "dts":
+		switch {
+		case strings.Contains(profile, "dts:x"):
+			return quality.AudioDTSX
+		case strings.Contains(profile, "ma"):
+			return quality.AudioDTSHDMA
+		case strings.Contains(profile, "hd"):
+			return quality.AudioDTSHD
+		}
+		return quality.AudioDTS
+	case "ac3":
+		return quality.AudioAC3
+	case "aac":
+		return quality.AudioAAC
+	}
+	return quality.AudioUnknown
+}
@@ -0,0 +1,160 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellywatch/internal/quality"
+)
+
+const healthyJSON = `{
+  "streams": [
+    {"codec_type": "video", "codec_name": "hevc", "width": 3840, "height": 1608,
+     "color_transfer": "smpte2084",
+     "side_data_list": [{"side_data_type": "DOVI configuration record"}],
+     "tags": {"DURATION": "01:58:00.000000000", "NUMBER_OF_BYTES": "40000000"}},
+    {"codec_type": "video", "codec_name": "mjpeg", "width": 600, "height": 900,
+     "disposition": {"attached_pic": 1}},
+    {"codec_type": "audio", "codec_name": "truehd", "profile": "Dolby TrueHD + Dolby Atmos", "channels": 8,
+     "tags": {"language": "eng", "DURATION": "01:57:59.900000000", "NUMBER_OF_BYTES": "8000000"}},
+    {"codec_type": "audio", "codec_name": "ac3", "channels": 6, "tags": {"LANGUAGE": "fre"}},
+    {"codec_type": "subtitle", "codec_name": "subrip", "tags": {"language": "eng"}},
+    {"codec_type": "subtitle", "codec_name": "hdmv_pgs_subtitle", "disposition": {"forced": 1}, "tags": {"language": "eng"}}
+  ],
+  "format": {"duration": "7080.000000"}
+}`
+
+const truncatedJSON = `{
+  "streams": [
+    {"codec_type": "video", "codec_name": "h264", "width": 1920, "height": 1080, "duration": "1800.0"},
+    {"codec_type": "audio", "codec_name": "aac", "channels": 2, "duration": "1800.0"}
+  ],
+  "format": {"duration": "5400.0"}
+}`
+
+func TestParse(t *testing.T) {
+	r, err := Parse([]byte(healthyJSON))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if r.Duration != 7080*time.Second {
+		t.Errorf("Duration = %s, want 1h58m", r.Duration)
+	}
+	if r.VideoCodec != "hevc" || r.Width != 3840 || r.Height != 1608 {
+		t.Errorf("video = %s %dx%d, want hevc 3840x1608 (cover art ignored)", r.VideoCodec, r.Width, r.Height)
+	}
+	if r.HDR != HDRDolbyVision {
+		t.Errorf("HDR = %q, want %q", r.HDR, HDRDolbyVision)
+	}
+	if len(r.AudioTracks) != 2 || r.AudioTracks[0].Language != "eng" || r.AudioTracks[1].Language != "fre" {
+		t.Errorf("AudioTracks = %+v, want eng truehd and fre ac3", r.AudioTracks)
+	}
+	if len(r.SubtitleTracks) != 2 || r.SubtitleTracks[0].Forced || !r.SubtitleTracks[1].Forced {
+		t.Errorf("SubtitleTracks = %+v, want one regular and one forced", r.SubtitleTracks)
+	}
+
+	q := r.Quality()
+	want := &quality.Probed{
+		Resolution: quality.Resolution2160p,
+		HDR:        quality.DolbyVision,
+		Audio:      quality.AudioAtmos,
+		Codec:      "x265",
+	}
+	if *q != *want {
+		t.Errorf("Quality() = %+v, want %+v", q, want)
+	}
+	if (*Result)(nil).Quality() != nil {
+		t.Error("nil Result should yield nil Quality()")
+	}
+}
+
+func TestParse_HDRFormats(t *testing.T) {
+	tests := []struct {
+		stream string
+		want   string
+	}{
+		{`{"codec_type": "video", "codec_name": "hevc", "color_transfer": "smpte2084"}`, HDR10},
+		{`{"codec_type": "video", "codec_name": "hevc", "color_transfer": "smpte2084",
+		   "side_data_list": [{"side_data_type": "HDR Dynamic Metadata SMPTE2094-40 (HDR10+)"}]}`, HDR10Plus},
+		{`{"codec_type": "video", "codec_name": "hevc", "color_transfer": "arib-std-b67"}`, HDRHLG},
+		{`{"codec_type": "video", "codec_name": "h264", "color_transfer": "bt709"}`, ""},
+	}
+	for _, tt := range tests {
+		r, err := Parse([]byte(`{"streams": [` + tt.stream + `], "format": {"duration": "10"}}`))
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if r.HDR != tt.want {
+			t.Errorf("HDR = %q, want %q for %s", r.HDR, tt.want, tt.stream)
+		}
+	}
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		fileSize int64
+		wantErr  bool
+	}{
+		{"healthy", healthyJSON, 48_000_000, false},
+		{"stream stops early", truncatedJSON, 1 << 30, true},
+		{"file smaller than muxed bytes", healthyJSON, 20_000_000, true},
+		{"no video", `{"streams": [{"codec_type": "audio", "codec_name": "aac"}], "format": {"duration": "60"}}`, 1 << 20, true},
+		{"no duration", `{"streams": [{"codec_type": "video", "codec_name": "h264"}], "format": {}}`, 1 << 20, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse([]byte(tt.json))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			err = r.CheckIntegrity(tt.fileSize)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckIntegrity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrCorrupt) {
+				t.Errorf("CheckIntegrity() error = %v, want ErrCorrupt", err)
+			}
+		})
+	}
+}
+
+func TestProbe_FakeBinary(t *testing.T) {
+	ctx := context.Background()
+
+	bin, err := WriteFakeBinary(t.TempDir(), healthyJSON, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := New(bin, time.Second)
+	if !p.Available() {
+		t.Fatal("fake ffprobe should be available")
+	}
+	r, err := p.Probe(ctx, "/media/movie.mkv")
+	if err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+	if r.VideoCodec != "hevc" || r.ProbedAt.IsZero() {
+		t.Errorf("Probe() = %+v, want parsed result with ProbedAt", r)
+	}
+
+	bin, err = WriteFakeBinary(t.TempDir(), "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(bin, time.Second).Probe(ctx, "/media/broken.mkv"); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Probe() of unreadable file error = %v, want ErrCorrupt", err)
+	}
+
+	missing := New(filepath.Join(t.TempDir(), "no-ffprobe"), time.Second)
+	if missing.Available() {
+		t.Error("missing binary reported available")
+	}
+	if _, err := missing.Probe(ctx, "/media/movie.mkv"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Probe() without ffprobe error = %v, want ErrUnavailable", err)
+	}
+}
@@ -0,0 +1,263 @@
+// Package qbittorrent is a small client for the qBittorrent Web API: what
+// each torrent is, whether it has finished and seeded enough, and pausing
+// or removing it once its files are organized.
+package qbittorrent
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Config struct {
+	URL      string
+	Username string
+	Password string
+	Timeout  time.Duration
+}
+
+// Client talks to one qBittorrent instance. It logs in on first use and
+// again whenever the session cookie expires.
+type Client struct {
+	baseURL    string
+	username   string
+	password   string
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	loggedIn bool
+}
+
+func NewClient(cfg Config) *Client {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	jar, _ := cookiejar.New(nil)
+
+	return &Client{
+		baseURL:  strings.TrimRight(cfg.URL, "/"),
+		username: cfg.Username,
+		password: cfg.Password,
+		httpClient: &http.Client{
+			Timeout: timeout,
+			Jar:     jar,
+		},
+	}
+}
+
+// Torrent is one entry of /api/v2/torrents/info.
+type Torrent struct {
+	Hash        string  `json:"hash"`
+	Name        string  `json:"name"`
+	Category    string  `json:"category"`
+	SavePath    string  `json:"save_path"`
+	ContentPath string  `json:"content_path"`
+	Progress    float64 `json:"progress"`
+	AmountLeft  int64   `json:"amount_left"`
+	State       string  `json:"state"`
+	Ratio       float64 `json:"ratio"`
+	// RatioLimit and SeedingTimeLimit (minutes) are -2 to use the global
+	// limit and -1 for none.
+	RatioLimit       float64 `json:"ratio_limit"`
+	SeedingTime      int64   `json:"seeding_time"`
+	SeedingTimeLimit int64   `json:"seeding_time_limit"`
+}
+
+// Preferences holds the global seeding limits from /api/v2/app/preferences.
+type Preferences struct {
+	MaxRatioEnabled       bool    `json:"max_ratio_enabled"`
+	MaxRatio              float64 `json:"max_ratio"`
+	MaxSeedingTimeEnabled bool    `json:"max_seeding_time_enabled"`
+	MaxSeedingTime        int64   `json:"max_seeding_time"`
+}
+
+// Complete reports whether every wanted piece is on disk and qBittorrent
+// is not still checking or moving the files.
+func (t Torrent) Complete() bool {
+	if t.Progress < 1 || t.AmountLeft > 0 {
+		return false
+	}
+	switch t.State {
+	case "checkingUP", "checkingDL", "checkingResumeData", "moving", "allocating", "missingFiles", "error":
+		return false
+	}
+	return true
+}
+
+// SeedLimitReached reports whether the torrent has met its ratio or
+// seeding time limit, its own or else the global one in prefs. A torrent
+// without any limit has nothing to wait for.
+func (t Torrent) SeedLimitReached(prefs *Preferences) bool {
+	switch t.State {
+	case "pausedUP", "stoppedUP":
+		// qBittorrent stops torrents that reach their limit.
+		return true
+	}
+	ratioLimit, timeLimit := t.RatioLimit, t.SeedingTimeLimit
+	if ratioLimit == -2 {
+		ratioLimit = -1
+		if prefs != nil && prefs.MaxRatioEnabled {
+			ratioLimit = prefs.MaxRatio
+		}
+	}
+	if timeLimit == -2 {
+		timeLimit = -1
+		if prefs != nil && prefs.MaxSeedingTimeEnabled {
+			timeLimit = prefs.MaxSeedingTime
+		}
+	}
+	if ratioLimit < 0 && timeLimit < 0 {
+		return true
+	}
+	return (ratioLimit >= 0 && t.Ratio >= ratioLimit) ||
+		(timeLimit >= 0 && t.SeedingTime >= timeLimit*60)
+}
+
+// Torrents lists every torrent.
+func (c *Client) Torrents() ([]Torrent, error) {
+	var torrents []Torrent
+	if err := c.get("/api/v2/torrents/info", &torrents); err != nil {
+		return nil, err
+	}
+	return torrents, nil
+}
+
+// Preferences returns the global seeding limits.
+func (c *Client) Preferences() (*Preferences, error) {
+	var prefs Preferences
+	if err := c.get("/api/v2/app/preferences", &prefs); err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// Pause stops the torrents from seeding. qBittorrent 5 renamed pause to
+// stop; older versions only know pause.
+func (c *Client) Pause(hashes ...string) error {
+	form := url.Values{"hashes": {strings.Join(hashes, "|")}}
+	err := c.post("/api/v2/torrents/stop", form)
+	if apiErr, ok := err.(*APIError); ok && apiErr.StatusCode == http.StatusNotFound {
+		err = c.post("/api/v2/torrents/pause", form)
+	}
+	return err
+}
+
+// Delete removes the torrents, and their data when deleteFiles is set.
+func (c *Client) Delete(deleteFiles bool, hashes ...string) error {
+	return c.post("/api/v2/torrents/delete", url.Values{
+		"hashes":      {strings.Join(hashes, "|")},
+		"deleteFiles": {fmt.Sprintf("%t", deleteFiles)},
+	})
+}
+
+// APIError is a non-2xx response from qBittorrent.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("qBittorrent API error (status %d): %s", e.StatusCode, e.Body)
+}
+
+func (c *Client) login() error {
+	form := url.Values{"username": {c.username}, "password": {c.password}}
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+"/api/v2/auth/login", strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// qBittorrent refuses logins whose Referer doesn't match its host.
+	req.Header.Set("Referer", c.baseURL)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("logging in: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+	if strings.TrimSpace(string(body)) != "Ok." {
+		return fmt.Errorf("logging in: qBittorrent rejected the username or password")
+	}
+	return nil
+}
+
+// do sends a request, logging in first and once more if the session has
+// expired (403).
+func (c *Client) do(method, endpoint string, form url.Values) (*http.Response, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		c.mu.Lock()
+		if !c.loggedIn {
+			if err := c.login(); err != nil {
+				c.mu.Unlock()
+				return nil, err
+			}
+			c.loggedIn = true
+		}
+		c.mu.Unlock()
+
+		var body io.Reader
+		if form != nil {
+			body = strings.NewReader(form.Encode())
+		}
+		req, err := http.NewRequest(method, c.baseURL+endpoint, body)
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+		if form != nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		req.Header.Set("Referer", c.baseURL)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("executing request: %w", err)
+		}
+		if resp.StatusCode == http.StatusForbidden && attempt == 0 {
+			resp.Body.Close()
+			c.mu.Lock()
+			c.loggedIn = false
+			c.mu.Unlock()
+			continue
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			defer resp.Body.Close()
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			return nil, &APIError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(bodyBytes))}
+		}
+		return resp, nil
+	}
+	return nil, &APIError{StatusCode: http.StatusForbidden, Body: "session rejected after login"}
+}
+
+func (c *Client) get(endpoint string, result interface{}) error {
+	resp, err := c.do(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) post(endpoint string, form url.Values) error {
+	resp, err := c.do(http.MethodPost, endpoint, form)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,125 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeQBittorrent is a qBittorrent stand-in that expires the session once,
+// speaks only the pre-5.0 pause endpoint, and records the calls it gets.
+type fakeQBittorrent struct {
+	logins   int
+	expired  bool
+	paused   []string
+	deleted  []string
+	torrents []Torrent
+}
+
+func (f *fakeQBittorrent) server(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/auth/login" {
+			require.NoError(t, r.ParseForm())
+			if r.PostForm.Get("username") != "admin" || r.PostForm.Get("password") != "secret" {
+				w.Write([]byte("Fails."))
+				return
+			}
+			f.logins++
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: "session", Path: "/"})
+			w.Write([]byte("Ok."))
+			return
+		}
+		if c, err := r.Cookie("SID"); err != nil || c.Value != "session" || f.expired {
+			f.expired = false
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			json.NewEncoder(w).Encode(f.torrents)
+		case "/api/v2/app/preferences":
+			json.NewEncoder(w).Encode(Preferences{MaxRatioEnabled: true, MaxRatio: 2})
+		case "/api/v2/torrents/pause":
+			require.NoError(t, r.ParseForm())
+			f.paused = append(f.paused, r.PostForm.Get("hashes"))
+		case "/api/v2/torrents/delete":
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "false", r.PostForm.Get("deleteFiles"))
+			f.deleted = append(f.deleted, r.PostForm.Get("hashes"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestClientLogsInAndRenewsSession(t *testing.T) {
+	fake := &fakeQBittorrent{torrents: []Torrent{{Hash: "abc", Name: "Heat.1995.1080p", Category: "radarr", Progress: 1, State: "uploading"}}}
+	server := fake.server(t)
+	defer server.Close()
+	client := NewClient(Config{URL: server.URL + "/", Username: "admin", Password: "secret"})
+
+	torrents, err := client.Torrents()
+	require.NoError(t, err)
+	require.Len(t, torrents, 1)
+	assert.Equal(t, "radarr", torrents[0].Category)
+
+	fake.expired = true
+	_, err = client.Torrents()
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.logins)
+}
+
+func TestClientRejectedLogin(t *testing.T) {
+	server := (&fakeQBittorrent{}).server(t)
+	defer server.Close()
+	client := NewClient(Config{URL: server.URL, Username: "admin", Password: "wrong"})
+
+	_, err := client.Torrents()
+	assert.ErrorContains(t, err, "username or password")
+}
+
+func TestPauseFallsBackAndDeleteKeepsFiles(t *testing.T) {
+	fake := &fakeQBittorrent{}
+	server := fake.server(t)
+	defer server.Close()
+	client := NewClient(Config{URL: server.URL, Username: "admin", Password: "secret"})
+
+	require.NoError(t, client.Pause("abc", "def"))
+	require.NoError(t, client.Delete(false, "abc"))
+	assert.Equal(t, []string{"abc|def"}, fake.paused)
+	assert.Equal(t, []string{"abc"}, fake.deleted)
+}
+
+func TestTorrentComplete(t *testing.T) {
+	assert.True(t, Torrent{Progress: 1, State: "stalledUP"}.Complete())
+	assert.False(t, Torrent{Progress: 0.5, State: "downloading"}.Complete())
+	assert.False(t, Torrent{Progress: 1, State: "checkingUP"}.Complete())
+	assert.False(t, Torrent{Progress: 1, State: "moving"}.Complete())
+}
+
+func TestTorrentSeedLimitReached(t *testing.T) {
+	global := &Preferences{MaxRatioEnabled: true, MaxRatio: 2}
+	tests := []struct {
+		name    string
+		torrent Torrent
+		prefs   *Preferences
+		want    bool
+	}{
+		{"below global ratio", Torrent{Ratio: 1, RatioLimit: -2, SeedingTimeLimit: -2, State: "uploading"}, global, false},
+		{"at global ratio", Torrent{Ratio: 2, RatioLimit: -2, SeedingTimeLimit: -2, State: "uploading"}, global, true},
+		{"own ratio", Torrent{Ratio: 1.1, RatioLimit: 1, SeedingTimeLimit: -2, State: "uploading"}, global, true},
+		{"seeding time", Torrent{Ratio: 0.1, RatioLimit: -1, SeedingTime: 3600, SeedingTimeLimit: 60, State: "uploading"}, nil, true},
+		{"no limits", Torrent{Ratio: 0.1, RatioLimit: -2, SeedingTimeLimit: -2, State: "uploading"}, &Preferences{}, true},
+		{"stopped at limit", Torrent{Ratio: 0.5, RatioLimit: -2, SeedingTimeLimit: -2, State: "stoppedUP"}, global, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.torrent.SeedLimitReached(tt.prefs))
+		})
+	}
+}
@@ -25,6 +25,39 @@ type QualityMetadata struct {
 //
 // Returns: QualityMetadata ready for database insertion
 func ExtractMetadata(path string, fileSize int64, isEpisode bool) QualityMetadata {
+	return ExtractMetadataProbed(path, fileSize, isEpisode, nil)
+}
+
+// ExtractMetadataProbed is ExtractMetadata with measured stream data.
+// Whatever the probe determined (resolution, HDR, audio, codec) takes
+// precedence over filename markers; a nil probe behaves like
+// ExtractMetadata.
+func ExtractMetadataProbed(path string, fileSize int64, isEpisode bool, probed *Probed) QualityMetadata {
+	return extractMetadata(defaultProfile, path, fileSize, isEpisode, probed)
+}
+
+func extractMetadata(profile *Profile, path string, fileSize int64, isEpisode bool, probed *Probed) QualityMetadata {
+	info := parsePath(path, probed)
+	score := profile.Score(info, fileSize, isEpisode)
+
+	codec := info.Codec
+	if codec == "" {
+		codec = "unknown"
+	}
+
+	// Convert to database-friendly strings
+	return QualityMetadata{
+		Resolution:   ResolutionToString(info.Resolution),
+		SourceType:   SourceToString(info.Source),
+		Codec:        codec,
+		AudioFormat:  AudioToString(info.Audio),
+		QualityScore: score,
+	}
+}
+
+// parsePath reads quality markers from a file path, filling gaps from
+// the release folder names, and applies measured stream data on top.
+func parsePath(path string, probed *Probed) *QualityInfo {
 	// Parse quality from filename
 	info := Parse(filepath.Base(path))
 
@@ -55,17 +88,12 @@ func ExtractMetadata(path string, fileSize int64, isEpisode bool) QualityMetadat
 		info.Score = info.ComputeScore()
 	}
 
-	// Compute CONDOR quality score (resolution + source + size)
-	score := ScoreFile(info, fileSize, isEpisode)
-
-	// Convert to database-friendly strings
-	return QualityMetadata{
-		Resolution:   ResolutionToString(info.Resolution),
-		SourceType:   SourceToString(info.Source),
-		Codec:        CodecToString(path),
-		AudioFormat:  AudioToString(info.Audio),
-		QualityScore: score,
+	// Codec markers sometimes sit only on the release folder
+	if info.Codec == "" {
+		info.Codec = codecName(path)
 	}
+	info.ApplyProbed(probed)
+	return info
 }
 
 // ExtractMovieMetadata is a convenience wrapper for ExtractMetadata with isEpisode=false
@@ -96,7 +124,8 @@ func CompareWithSize(path1 string, size1 int64, path2 string, size2 int64, isEpi
 	return 0 // equal
 }
 
-// FindBestFile finds the best quality file from a list
+// FindBestFile finds the best quality file from a list using the default
+// (CONDOR) profile; see Profile.FindBestFile.
 //
 // Parameters:
 //   - files: Map of path -> size
@@ -104,20 +133,5 @@ func CompareWithSize(path1 string, size1 int64, path2 string, size2 int64, isEpi
 //
 // Returns: path of the best quality file, or empty string if no files
 func FindBestFile(files map[string]int64, isEpisode bool) string {
-	if len(files) == 0 {
-		return ""
-	}
-
-	var bestPath string
-	var bestScore int = EmptyFilePenalty - 1 // Start lower than worst possible score
-
-	for path, size := range files {
-		meta := ExtractMetadata(path, size, isEpisode)
-		if meta.QualityScore > bestScore {
-			bestScore = meta.QualityScore
-			bestPath = path
-		}
-	}
-
-	return bestPath
+	return defaultProfile.FindBestFile(files, isEpisode)
 }
@@ -0,0 +1,54 @@
+package quality
+
+// Probed holds quality attributes measured from the file itself (ffprobe)
+// rather than guessed from release-name markers. Zero values mean "not
+// measured" and leave the filename-derived value alone.
+type Probed struct {
+	Resolution Resolution
+	HDR        HDRFormat
+	Audio      AudioCodec
+	Codec      string // "x265", "x264", "AV1", "VP9", "XviD" or ""
+}
+
+// ResolutionFromDimensions buckets a video frame size into a Resolution.
+// Width decides for scope and letterboxed encodes (1920x800 is 1080p);
+// height catches narrow anamorphic frames.
+func ResolutionFromDimensions(width, height int) Resolution {
+	switch {
+	case width >= 7600 || height >= 4300:
+		return Resolution4320p
+	case width >= 3800 || height >= 2000:
+		return Resolution2160p
+	case width >= 1900 || height >= 1000:
+		return Resolution1080p
+	case width >= 1260 || height >= 700:
+		return Resolution720p
+	case height >= 560:
+		return Resolution576p
+	case width > 0 || height > 0:
+		return Resolution480p
+	default:
+		return ResolutionUnknown
+	}
+}
+
+// ApplyProbed overwrites filename-derived attributes with measured ones.
+// Source, PROPER and edition flags only exist in the name and are kept.
+func (q *QualityInfo) ApplyProbed(p *Probed) {
+	if p == nil {
+		return
+	}
+	if p.Resolution != ResolutionUnknown {
+		q.Resolution = p.Resolution
+	}
+	if p.HDR != HDRNone {
+		q.HDR = p.HDR
+	}
+	if p.Audio != AudioUnknown {
+		q.Audio = p.Audio
+	}
+	if p.Codec != "" {
+		q.Codec = p.Codec
+	}
+	q.Score = q.ComputeScore()
+}
@@ -0,0 +1,272 @@
+package quality
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Profile weighs quality attributes into a score for picking the copy to
+// keep among duplicates. DefaultProfile reproduces the CONDOR constants;
+// user profiles start from it and override individual weights, e.g. to
+// rank 1080p REMUX with Atmos above a 2160p WEB-DL.
+type Profile struct {
+	Name       string
+	Resolution map[Resolution]int
+	Source     map[Source]int
+	HDR        map[HDRFormat]int
+	Audio      map[AudioCodec]int
+	Codec      map[string]int // keyed by CodecToString names ("x265", "x264", ...)
+	// Proper is added for PROPER/REPACK releases.
+	Proper int
+	// SizePerGB rewards bigger encodes, up to MaxSizeGB (movies) or
+	// MaxSizeGBTV (episodes) gigabytes.
+	SizePerGB   int
+	MaxSizeGB   int
+	MaxSizeGBTV int
+	// UnknownResolutionPerGB replaces the resolution weight for files
+	// without a resolution marker, so size stands in for it.
+	UnknownResolutionPerGB int
+}
+
+// DefaultProfileName is the name of the built-in CONDOR profile.
+const DefaultProfileName = "default"
+
+// defaultProfile backs ScoreFile and the package-level helpers.
+var defaultProfile = DefaultProfile()
+
+// DefaultProfile returns the built-in CONDOR weights: resolution, then
+// source, then size. HDR, audio, codec and PROPER carry no weight.
+func DefaultProfile() *Profile {
+	return &Profile{
+		Name: DefaultProfileName,
+		Resolution: map[Resolution]int{
+			Resolution4320p: ScoreResolution4320p,
+			Resolution2160p: ScoreResolution2160p,
+			Resolution1080p: ScoreResolution1080p,
+			Resolution720p:  ScoreResolution720p,
+			Resolution576p:  ScoreResolution576p,
+			Resolution480p:  ScoreResolution480p,
+		},
+		Source: map[Source]int{
+			SourceREMUX:  ScoreSourceREMUX,
+			SourceBluRay: ScoreSourceBluRay,
+			SourceWEBDL:  ScoreSourceWEBDL,
+			SourceWEBRip: ScoreSourceWEBRip,
+			SourceHDTV:   ScoreSourceHDTV,
+			SourceDVDRip: ScoreSourceDVDRip,
+		},
+		HDR:                    map[HDRFormat]int{},
+		Audio:                  map[AudioCodec]int{},
+		Codec:                  map[string]int{},
+		SizePerGB:              SizeBonusPerGB,
+		MaxSizeGB:              MaxSizeBonusGB,
+		MaxSizeGBTV:            MaxSizeBonusGBTV,
+		UnknownResolutionPerGB: UnknownResolutionMultiplier,
+	}
+}
+
+// SetWeight sets the points for one named value of a factor: "resolution"
+// ("2160p"), "source" ("REMUX", "WEB-DL"), "hdr" ("DV", "HDR10+"), "audio"
+// ("Atmos", "DTS-HD MA") or "codec" ("x265"). Names match the strings
+// stored in media_files, case-insensitively.
+func (p *Profile) SetWeight(factor, name string, points int) error {
+	key := strings.ToLower(strings.TrimSpace(name))
+	switch strings.ToLower(factor) {
+	case "resolution":
+		for _, r := range []Resolution{Resolution4320p, Resolution2160p, Resolution1080p, Resolution720p, Resolution576p, Resolution480p} {
+			if strings.ToLower(ResolutionToString(r)) == key {
+				p.Resolution[r] = points
+				return nil
+			}
+		}
+	case "source":
+		for s := SourceCAM; s <= SourceREMUX; s++ {
+			if strings.ToLower(SourceToString(s)) == key {
+				p.Source[s] = points
+				return nil
+			}
+		}
+	case "hdr":
+		for h := HDR10; h <= HLG; h++ {
+			if strings.ToLower(HDRToString(h)) == key {
+				p.HDR[h] = points
+				return nil
+			}
+		}
+	case "audio":
+		for a := AudioAAC; a <= AudioAtmos; a++ {
+			if strings.ToLower(AudioToString(a)) == key {
+				p.Audio[a] = points
+				return nil
+			}
+		}
+	case "codec":
+		for _, c := range codecNames {
+			if strings.ToLower(c) == key {
+				p.Codec[c] = points
+				return nil
+			}
+		}
+	default:
+		return fmt.Errorf("unknown quality factor %q", factor)
+	}
+	return fmt.Errorf("unknown %s %q", factor, name)
+}
+
+// codecNames lists the values CodecToString can return.
+var codecNames = []string{"AV1", "x265", "x264", "VP9", "XviD"}
+
+// ScoreItem is one factor's contribution to a score.
+type ScoreItem struct {
+	Factor string // "resolution", "source", "hdr", "audio", "codec", "proper", "size"
+	Value  string // what the file has, e.g. "1080p" or "12 GB"
+	Points int
+}
+
+// Breakdown is a score with the contributions that make it up.
+type Breakdown struct {
+	Profile string
+	Total   int
+	Items   []ScoreItem
+}
+
+// Score returns the profile's score for a file.
+func (p *Profile) Score(info *QualityInfo, fileSize int64, isEpisode bool) int {
+	return p.Explain(info, fileSize, isEpisode).Total
+}
+
+// Explain scores a file and itemises every factor, including the ones
+// that contributed nothing, so two breakdowns can be compared line by line.
+func (p *Profile) Explain(info *QualityInfo, fileSize int64, isEpisode bool) Breakdown {
+	b := Breakdown{Profile: p.Name}
+	if fileSize == 0 {
+		b.Total = EmptyFilePenalty
+		b.Items = []ScoreItem{{Factor: "size", Value: "empty", Points: EmptyFilePenalty}}
+		return b
+	}
+
+	sizeGB := int(fileSize / (1024 * 1024 * 1024))
+	add := func(factor, value string, points int) {
+		b.Items = append(b.Items, ScoreItem{Factor: factor, Value: value, Points: points})
+		b.Total += points
+	}
+
+	if info.Resolution == ResolutionUnknown {
+		add("resolution", "unknown", sizeGB*p.UnknownResolutionPerGB)
+	} else {
+		add("resolution", ResolutionToString(info.Resolution), p.Resolution[info.Resolution])
+	}
+	add("source", SourceToString(info.Source), p.Source[info.Source])
+	add("hdr", HDRToString(info.HDR), p.HDR[info.HDR])
+	add("audio", AudioToString(info.Audio), p.Audio[info.Audio])
+	codec := info.Codec
+	if codec == "" {
+		codec = "unknown"
+	}
+	add("codec", codec, p.Codec[info.Codec])
+	if info.IsProper {
+		add("proper", "PROPER/REPACK", p.Proper)
+	} else {
+		add("proper", "no", 0)
+	}
+
+	maxGB := p.MaxSizeGB
+	if isEpisode {
+		maxGB = p.MaxSizeGBTV
+	}
+	add("size", fmt.Sprintf("%d GB", sizeGB), min(sizeGB, maxGB)*p.SizePerGB)
+	return b
+}
+
+// ExtractMetadata is the package-level ExtractMetadataProbed scored with
+// this profile.
+func (p *Profile) ExtractMetadata(path string, fileSize int64, isEpisode bool, probed *Probed) QualityMetadata {
+	return extractMetadata(p, path, fileSize, isEpisode, probed)
+}
+
+// ExplainFile is Explain for a file on disk, reading quality the same way
+// ExtractMetadata does.
+func (p *Profile) ExplainFile(path string, fileSize int64, isEpisode bool, probed *Probed) Breakdown {
+	return p.Explain(parsePath(path, probed), fileSize, isEpisode)
+}
+
+// FindBestFile returns the path of the highest-scoring file in files (path
+// -> size), or "" for an empty map. Ties go to the lexically first path
+// so the result doesn't depend on map order.
+func (p *Profile) FindBestFile(files map[string]int64, isEpisode bool) string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var bestPath string
+	bestScore := EmptyFilePenalty - 1 // Start lower than worst possible score
+	for _, path := range paths {
+		meta := p.ExtractMetadata(path, files[path], isEpisode, nil)
+		if meta.QualityScore > bestScore {
+			bestScore = meta.QualityScore
+			bestPath = path
+		}
+	}
+	return bestPath
+}
+
+// ProfileSet assigns scoring profiles to library roots. A nil ProfileSet
+// scores everything with DefaultProfile.
+type ProfileSet struct {
+	fallback  *Profile
+	libraries map[string]*Profile // cleaned library root -> profile
+}
+
+// NewProfileSet returns a set scoring files under each library root in
+// byLibrary with its profile and everything else with fallback (nil means
+// DefaultProfile).
+func NewProfileSet(fallback *Profile, byLibrary map[string]*Profile) *ProfileSet {
+	if fallback == nil {
+		fallback = DefaultProfile()
+	}
+	set := &ProfileSet{fallback: fallback, libraries: make(map[string]*Profile, len(byLibrary))}
+	for root, p := range byLibrary {
+		set.libraries[filepath.Clean(root)] = p
+	}
+	return set
+}
+
+// For returns the profile of the library holding path, a file anywhere
+// below a library root.
+func (s *ProfileSet) For(path string) *Profile {
+	if s == nil {
+		return defaultProfile
+	}
+	path = filepath.Clean(path)
+	best := ""
+	for root := range s.libraries {
+		if (path == root || strings.HasPrefix(path, root+string(filepath.Separator))) && len(root) > len(best) {
+			best = root
+		}
+	}
+	if best == "" {
+		return s.fallback
+	}
+	return s.libraries[best]
+}
+
+// ForFiles returns the one profile that can rank paths against each
+// other: the shared profile when every path resolves to the same one,
+// otherwise the fallback, since scores from different profiles aren't
+// comparable.
+func (s *ProfileSet) ForFiles(paths []string) *Profile {
+	if s == nil || len(paths) == 0 {
+		return s.For("")
+	}
+	p := s.For(paths[0])
+	for _, path := range paths[1:] {
+		if s.For(path) != p {
+			return s.fallback
+		}
+	}
+	return p
+}
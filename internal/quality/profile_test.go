@@ -0,0 +1,110 @@
+package quality
+
+import "testing"
+
+const gb = 1024 * 1024 * 1024
+
+func remuxFirstProfile(t *testing.T) *Profile {
+	t.Helper()
+	p := DefaultProfile()
+	p.Name = "remux-first"
+	for _, w := range []struct {
+		factor, name string
+		points       int
+	}{
+		{"resolution", "2160p", 300},
+		{"source", "remux", 200},
+		{"audio", "Atmos", 80},
+		{"hdr", "dv", 20},
+		{"codec", "X265", 15},
+	} {
+		if err := p.SetWeight(w.factor, w.name, w.points); err != nil {
+			t.Fatalf("SetWeight(%s, %s) error = %v", w.factor, w.name, err)
+		}
+	}
+	p.Proper = 5
+	return p
+}
+
+func TestProfile_DefaultMatchesCondor(t *testing.T) {
+	info := Parse("Movie.2020.1080p.BluRay.x264.mkv")
+	if got, want := DefaultProfile().Score(info, 12*gb, false), ScoreResolution1080p+ScoreSourceBluRay+12; got != want {
+		t.Errorf("default profile score = %d, want %d", got, want)
+	}
+}
+
+func TestProfile_RemuxAtmosBeats4KWebDL(t *testing.T) {
+	remux := "Movie.2020.1080p.BluRay.REMUX.TrueHD.Atmos.mkv"
+	web := "Movie.2020.2160p.WEB-DL.DDP5.1.x265.mkv"
+	files := map[string]int64{remux: 30 * gb, web: 15 * gb}
+
+	if got := FindBestFile(files, false); got != web {
+		t.Fatalf("default profile picked %s, want the 2160p WEB-DL", got)
+	}
+	if got := remuxFirstProfile(t).FindBestFile(files, false); got != remux {
+		t.Errorf("remux-first profile picked %s, want the REMUX", got)
+	}
+}
+
+func TestProfile_Explain(t *testing.T) {
+	p := remuxFirstProfile(t)
+	info := Parse("Movie.2020.2160p.WEB-DL.DV.x265.PROPER.mkv")
+	b := p.Explain(info, 20*gb, false)
+
+	want := map[string]int{
+		"resolution": 300,
+		"source":     ScoreSourceWEBDL,
+		"hdr":        20,
+		"audio":      0,
+		"codec":      15,
+		"proper":     5,
+		"size":       20,
+	}
+	sum := 0
+	for _, item := range b.Items {
+		if item.Points != want[item.Factor] {
+			t.Errorf("%s (%s) = %d points, want %d", item.Factor, item.Value, item.Points, want[item.Factor])
+		}
+		sum += item.Points
+	}
+	if len(b.Items) != len(want) || b.Total != sum || b.Profile != "remux-first" {
+		t.Errorf("Explain() = %+v, want one item per factor summing to Total", b)
+	}
+	if b.Total != p.Score(info, 20*gb, false) {
+		t.Errorf("Explain total %d != Score %d", b.Total, p.Score(info, 20*gb, false))
+	}
+	if got := p.Explain(info, 0, false).Total; got != EmptyFilePenalty {
+		t.Errorf("empty file total = %d, want %d", got, EmptyFilePenalty)
+	}
+}
+
+func TestProfile_SetWeightRejectsUnknownNames(t *testing.T) {
+	p := DefaultProfile()
+	if err := p.SetWeight("resolution", "1440p", 10); err == nil {
+		t.Error("expected error for unknown resolution")
+	}
+	if err := p.SetWeight("bitrate", "high", 10); err == nil {
+		t.Error("expected error for unknown factor")
+	}
+}
+
+func TestProfileSet(t *testing.T) {
+	remux := remuxFirstProfile(t)
+	set := NewProfileSet(nil, map[string]*Profile{"/mnt/Movies/": remux})
+
+	if got := set.For("/mnt/Movies/Heat (1995)/Heat (1995).mkv"); got != remux {
+		t.Errorf("For(library file) = %s, want remux-first", got.Name)
+	}
+	if got := set.For("/mnt/Movies4K/Heat (1995)/Heat (1995).mkv"); got.Name != DefaultProfileName {
+		t.Errorf("For(other library) = %s, want default", got.Name)
+	}
+	if got := set.ForFiles([]string{"/mnt/Movies/a.mkv", "/mnt/Movies/b/c.mkv"}); got != remux {
+		t.Errorf("ForFiles(same library) = %s, want remux-first", got.Name)
+	}
+	if got := set.ForFiles([]string{"/mnt/Movies/a.mkv", "/mnt/Other/a.mkv"}); got.Name != DefaultProfileName {
+		t.Errorf("ForFiles(mixed libraries) = %s, want default", got.Name)
+	}
+	if got := (*ProfileSet)(nil).For("/anything"); got.Name != DefaultProfileName {
+		t.Errorf("nil set For() = %s, want default", got.Name)
+	}
+}
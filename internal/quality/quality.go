@@ -71,6 +71,7 @@ type QualityInfo struct {
 	Resolution Resolution
 	HDR        HDRFormat
 	Audio      AudioCodec
+	Codec      string // CodecToString name, "" when unknown
 	Is3D       bool
 	IsProper   bool   // PROPER/REPACK release
 	IsExtended bool   // Extended/Director's cut
@@ -91,6 +92,7 @@ func Parse(filename string) *QualityInfo {
 	info.Source = parseSource(upper)
 	info.HDR = parseHDR(upper)
 	info.Audio = parseAudio(upper)
+	info.Codec = codecName(upper)
 	info.Is3D = parse3D(upper)
 	info.IsProper = parseProper(upper)
 	info.IsExtended = parseExtended(upper)
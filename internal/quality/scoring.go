@@ -42,7 +42,8 @@ const (
 )
 
 // ScoreFile calculates a quality score for a media file using CONDOR algorithm.
-// This combines resolution, source type, and file size.
+// This combines resolution, source type, and file size. It scores with
+// DefaultProfile; Profile.Score applies user-defined weights instead.
 //
 // Algorithm:
 //  1. Empty files always score -1000 (worst)
@@ -52,70 +53,14 @@ const (
 //  5. Unknown resolution gets size-weighted bonus (50x size multiplier)
 //
 // Parameters:
-//   - info: Quality metadata from Parse()
+//   - info: Quality metadata from Parse(), with ApplyProbed already applied
+//     when the file was probed so measured resolution wins over the name
 //   - fileSize: File size in bytes
 //   - isEpisode: true for TV episodes (affects size cap), false for movies
 //
 // Returns: Quality score (higher is better)
 func ScoreFile(info *QualityInfo, fileSize int64, isEpisode bool) int {
-	// Empty files are always worst
-	if fileSize == 0 {
-		return EmptyFilePenalty
-	}
-
-	score := 0
-	sizeGB := fileSize / (1024 * 1024 * 1024)
-
-	// Resolution scoring (highest weight)
-	switch info.Resolution {
-	case Resolution4320p:
-		score += ScoreResolution4320p
-	case Resolution2160p:
-		score += ScoreResolution2160p
-	case Resolution1080p:
-		score += ScoreResolution1080p
-	case Resolution720p:
-		score += ScoreResolution720p
-	case Resolution576p:
-		score += ScoreResolution576p
-	case Resolution480p:
-		score += ScoreResolution480p
-	case ResolutionUnknown:
-		// For unknown resolution, heavily weight file size
-		// This handles cases where filename lacks resolution marker
-		// Example: 5GB file gets 5 * 50 = 250 points
-		score += int(sizeGB) * UnknownResolutionMultiplier
-	}
-
-	// Source type scoring (second highest weight)
-	switch info.Source {
-	case SourceREMUX:
-		score += ScoreSourceREMUX
-	case SourceBluRay:
-		score += ScoreSourceBluRay
-	case SourceWEBDL:
-		score += ScoreSourceWEBDL
-	case SourceWEBRip:
-		score += ScoreSourceWEBRip
-	case SourceHDTV:
-		score += ScoreSourceHDTV
-	case SourceDVDRip:
-		score += ScoreSourceDVDRip
-	}
-
-	// Size bonus (capped based on media type)
-	maxBonus := MaxSizeBonusGB
-	if isEpisode {
-		maxBonus = MaxSizeBonusGBTV
-	}
-
-	if sizeGB > int64(maxBonus) {
-		sizeGB = int64(maxBonus)
-	}
-
-	score += int(sizeGB) * SizeBonusPerGB
-
-	return score
+	return defaultProfile.Score(info, fileSize, isEpisode)
 }
 
 // ScoreMovie is a convenience wrapper for ScoreFile with isEpisode=false
@@ -212,6 +157,30 @@ func AudioToString(a AudioCodec) string {
 	}
 }
 
+// HDRToString converts HDRFormat enum to database-friendly string
+func HDRToString(h HDRFormat) string {
+	switch h {
+	case HDR10:
+		return "HDR10"
+	case HDR10Plus:
+		return "HDR10+"
+	case DolbyVision:
+		return "DV"
+	case HLG:
+		return "HLG"
+	default:
+		return "SDR"
+	}
+}
+
+// codecName is CodecToString with "" for unknown codecs.
+func codecName(filename string) string {
+	if codec := CodecToString(filename); codec != "unknown" {
+		return codec
+	}
+	return ""
+}
+
 // CodecToString extracts codec information from filename
 func CodecToString(filename string) string {
 	upper := strings.ToUpper(filename)
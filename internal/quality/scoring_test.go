@@ -334,3 +334,50 @@ func TestShouldInclude(t *testing.T) {
 		t.Error("40MB episode should not be included (sample)")
 	}
 }
+
+func TestExtractMetadataProbed_PrefersMeasuredData(t *testing.T) {
+	// Mislabelled release: the name claims 720p x264, the stream is 4K HEVC.
+	path := "/movies/Movie (2020)/Movie.2020.720p.WEB-DL.x264.mkv"
+	size := int64(8 * 1024 * 1024 * 1024)
+
+	named := ExtractMetadata(path, size, false)
+	probed := ExtractMetadataProbed(path, size, false, &Probed{
+		Resolution: ResolutionFromDimensions(3840, 1608),
+		Audio:      AudioAtmos,
+		Codec:      "x265",
+	})
+
+	if probed.Resolution != "2160p" || probed.Codec != "x265" || probed.AudioFormat != AudioToString(AudioAtmos) {
+		t.Errorf("probed metadata = %+v, want measured 2160p x265 Atmos", probed)
+	}
+	if probed.SourceType != named.SourceType {
+		t.Errorf("source = %q, want filename source %q kept", probed.SourceType, named.SourceType)
+	}
+	if probed.QualityScore <= named.QualityScore {
+		t.Errorf("probed score %d should beat filename score %d", probed.QualityScore, named.QualityScore)
+	}
+	if got := ExtractMetadataProbed(path, size, false, nil); got != named {
+		t.Errorf("nil probe = %+v, want %+v", got, named)
+	}
+}
+
+func TestResolutionFromDimensions(t *testing.T) {
+	tests := []struct {
+		width, height int
+		want          Resolution
+	}{
+		{3840, 2160, Resolution2160p},
+		{3840, 1600, Resolution2160p},
+		{1920, 800, Resolution1080p},
+		{1440, 1080, Resolution1080p},
+		{1280, 536, Resolution720p},
+		{720, 576, Resolution576p},
+		{720, 480, Resolution480p},
+		{0, 0, ResolutionUnknown},
+	}
+	for _, tt := range tests {
+		if got := ResolutionFromDimensions(tt.width, tt.height); got != tt.want {
+			t.Errorf("ResolutionFromDimensions(%d, %d) = %d, want %d", tt.width, tt.height, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package radarr
+
+// WebhookEvent is the body of a Radarr webhook connection notification.
+// Only the fields JellyWatch uses to identify a download are decoded.
+type WebhookEvent struct {
+	EventType      string            `json:"eventType"`
+	Movie          WebhookMovie      `json:"movie"`
+	Release        WebhookRelease    `json:"release"`
+	MovieFile      *WebhookMovieFile `json:"movieFile,omitempty"`
+	DownloadClient string            `json:"downloadClient,omitempty"`
+	DownloadID     string            `json:"downloadId,omitempty"`
+}
+
+// WebhookMovie is the movie a webhook event is about.
+type WebhookMovie struct {
+	ID         int    `json:"id"`
+	Title      string `json:"title"`
+	Year       int    `json:"year"`
+	FolderPath string `json:"folderPath"`
+	TmdbID     int    `json:"tmdbId"`
+	ImdbID     string `json:"imdbId"`
+}
+
+// WebhookRelease is the release a Grab event sent to the download client.
+type WebhookRelease struct {
+	ReleaseTitle string `json:"releaseTitle"`
+	Indexer      string `json:"indexer"`
+	Size         int64  `json:"size"`
+}
+
+// WebhookMovieFile is the file a Download event imported. SourcePath is
+// where it was in the download client's folder.
+type WebhookMovieFile struct {
+	ID           int    `json:"id"`
+	RelativePath string `json:"relativePath"`
+	Path         string `json:"path"`
+	SceneName    string `json:"sceneName"`
+	SourcePath   string `json:"sourcePath"`
+}
+
+// Webhook event types. Download is sent "On Import" and "On Upgrade".
+const (
+	EventGrab     = "Grab"
+	EventDownload = "Download"
+	EventTest     = "Test"
+)
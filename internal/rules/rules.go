@@ -0,0 +1,260 @@
+// Package rules matches downloads against user-written rules that route,
+// ignore or re-title them before the filename parser sees them.
+package rules
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+)
+
+// Media types a rule can force.
+const (
+	MediaTypeMovie = "movie"
+	MediaTypeTV    = "tv"
+)
+
+// Rule is one [[rules]] entry. Every matcher that is set must match; the
+// actions of the first matching rule apply.
+type Rule struct {
+	Name string
+
+	// Path is a glob over the full path, matched case-insensitively. "*"
+	// matches any run of characters including "/", "?" one character.
+	Path string
+	// PathRegex is a regular expression searched for in the full path.
+	PathRegex string
+	// ReleaseGroups and Resolutions match the release group and resolution
+	// in the filename, case-insensitively; either one listed is enough.
+	ReleaseGroups []string
+	Resolutions   []string
+	// MinSize and MaxSize bound the file size in bytes; 0 is no bound.
+	MinSize int64
+	MaxSize int64
+	// Categories match the download client category of the torrent or job
+	// the file came from, case-insensitively.
+	Categories []string
+
+	// Library sends the file to this library root instead of the one the
+	// selector would pick.
+	Library string
+	// MediaType forces "movie" or "tv" regardless of the watch folder.
+	MediaType string
+	// Title and Year replace what the parser finds.
+	Title string
+	Year  string
+	// Ignore leaves the file where it is.
+	Ignore bool
+	// KeepSource, when set, overrides whether the download is kept after
+	// it is organized.
+	KeepSource *bool
+	// TransferBackend overrides how the file lands in the library.
+	TransferBackend string
+
+	glob  *regexp.Regexp
+	regex *regexp.Regexp
+}
+
+// Organizes reports whether the rule changes how a file is organized, as
+// opposed to only ignoring it.
+func (r *Rule) Organizes() bool {
+	return r.Library != "" || r.MediaType != "" || r.Title != "" || r.Year != "" ||
+		r.KeepSource != nil || r.TransferBackend != ""
+}
+
+// Actions describes what the rule does, for logs and "jellywatch rules test".
+func (r *Rule) Actions() string {
+	if r.Ignore {
+		return "ignore"
+	}
+	var parts []string
+	add := func(key, value string) {
+		if value != "" {
+			parts = append(parts, fmt.Sprintf("%s=%q", key, value))
+		}
+	}
+	add("library", r.Library)
+	add("media_type", r.MediaType)
+	add("title", r.Title)
+	add("year", r.Year)
+	if r.KeepSource != nil {
+		parts = append(parts, fmt.Sprintf("keep_source=%t", *r.KeepSource))
+	}
+	add("transfer_backend", r.TransferBackend)
+	return strings.Join(parts, " ")
+}
+
+// Engine holds compiled rules in the order they were written.
+type Engine struct {
+	rules []*Rule
+}
+
+// New compiles rules. It fails on bad patterns and on rules that match
+// everything or do nothing.
+func New(rules []Rule) (*Engine, error) {
+	e := &Engine{}
+	for i := range rules {
+		r := rules[i]
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rules[%d]", i)
+		}
+		if err := r.compile(); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+		e.rules = append(e.rules, &r)
+	}
+	return e, nil
+}
+
+func (r *Rule) compile() error {
+	if r.Path == "" && r.PathRegex == "" && len(r.ReleaseGroups) == 0 &&
+		len(r.Resolutions) == 0 && r.MinSize <= 0 && r.MaxSize <= 0 && len(r.Categories) == 0 {
+		return fmt.Errorf("needs at least one of path, path_regex, release_group, resolution, min_size_mb, max_size_mb or category")
+	}
+	if !r.Ignore && !r.Organizes() {
+		return fmt.Errorf("needs an action: library, media_type, title, year, ignore, keep_source or transfer_backend")
+	}
+	switch r.MediaType {
+	case "", MediaTypeMovie, MediaTypeTV:
+	default:
+		return fmt.Errorf("unknown media_type %q (want movie or tv)", r.MediaType)
+	}
+	if r.MaxSize > 0 && r.MinSize > r.MaxSize {
+		return fmt.Errorf("min_size_mb is larger than max_size_mb")
+	}
+	if r.Path != "" {
+		r.glob = globRegexp(r.Path)
+	}
+	if r.PathRegex != "" {
+		re, err := regexp.Compile(r.PathRegex)
+		if err != nil {
+			return fmt.Errorf("path_regex: %w", err)
+		}
+		r.regex = re
+	}
+	return nil
+}
+
+// globRegexp turns a glob into an anchored, case-insensitive expression.
+func globRegexp(glob string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?i)^")
+	for _, c := range filepath.ToSlash(glob) {
+		switch c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// Rules returns the compiled rules in evaluation order.
+func (e *Engine) Rules() []*Rule {
+	if e == nil {
+		return nil
+	}
+	return e.rules
+}
+
+// Match returns the first rule matching a file of size bytes at path that
+// a download client filed under category, or nil. A negative size is
+// unknown and fails any size bound; an empty category fails any category
+// matcher.
+func (e *Engine) Match(path string, size int64, category string) *Rule {
+	for _, r := range e.Rules() {
+		if r.mismatch(path, size, category) == "" {
+			return r
+		}
+	}
+	return nil
+}
+
+// Verdict is how one rule judged a path.
+type Verdict struct {
+	Rule    *Rule
+	Matched bool
+	// Reason names the first matcher that failed.
+	Reason string
+}
+
+// Explain judges path against every rule in order. Only the first rule
+// with Matched set applies.
+func (e *Engine) Explain(path string, size int64, category string) []Verdict {
+	var out []Verdict
+	for _, r := range e.Rules() {
+		reason := r.mismatch(path, size, category)
+		out = append(out, Verdict{Rule: r, Matched: reason == "", Reason: reason})
+	}
+	return out
+}
+
+// mismatch returns why r doesn't match, or "" when it does.
+func (r *Rule) mismatch(path string, size int64, category string) string {
+	slashed := filepath.ToSlash(path)
+	if r.glob != nil && !r.glob.MatchString(slashed) {
+		return fmt.Sprintf("path does not match %q", r.Path)
+	}
+	if r.regex != nil && !r.regex.MatchString(slashed) {
+		return fmt.Sprintf("path does not match path_regex %q", r.PathRegex)
+	}
+	if len(r.ReleaseGroups) > 0 {
+		group := naming.ReleaseGroup(path)
+		if !containsFold(r.ReleaseGroups, group) {
+			return fmt.Sprintf("release group %q is not one of %v", group, r.ReleaseGroups)
+		}
+	}
+	if len(r.Resolutions) > 0 {
+		res := resolution(path)
+		if !containsFold(r.Resolutions, res) {
+			return fmt.Sprintf("resolution %q is not one of %v", res, r.Resolutions)
+		}
+	}
+	if r.MinSize > 0 || r.MaxSize > 0 {
+		switch {
+		case size < 0:
+			return "size is unknown"
+		case r.MinSize > 0 && size < r.MinSize:
+			return fmt.Sprintf("size %d MB is under min_size_mb", size>>20)
+		case r.MaxSize > 0 && size > r.MaxSize:
+			return fmt.Sprintf("size %d MB is over max_size_mb", size>>20)
+		}
+	}
+	if len(r.Categories) > 0 {
+		switch {
+		case category == "":
+			return "download client category is unknown"
+		case !containsFold(r.Categories, category):
+			return fmt.Sprintf("category %q is not one of %v", category, r.Categories)
+		}
+	}
+	return ""
+}
+
+// resolution reads the resolution from the filename, then from its folder,
+// since episode files inside a season pack often leave it out.
+func resolution(path string) string {
+	if res := naming.ExtractResolution(filepath.Base(path)); res != "unknown" {
+		return res
+	}
+	return naming.ExtractResolution(filepath.Base(filepath.Dir(path)))
+}
+
+func containsFold(list []string, s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, v := range list {
+		if strings.EqualFold(strings.TrimSpace(v), s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+func mustNew(t *testing.T, rs ...Rule) *Engine {
+	t.Helper()
+	e, err := New(rs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestMatchFirstRuleWins(t *testing.T) {
+	e := mustNew(t,
+		Rule{Name: "samples", Path: "*.sample.*", Ignore: true},
+		Rule{Name: "kids", Path: "*/Kids/*", Library: "/media/Kids Movies"},
+		Rule{Name: "anime", ReleaseGroups: []string{"subsplease"}, MediaType: MediaTypeTV},
+		Rule{Name: "uhd", Resolutions: []string{"2160p"}, MinSize: 10 << 30, TransferBackend: "hardlink"},
+	)
+
+	tests := []struct {
+		path string
+		size int64
+		want string
+	}{
+		{"/downloads/movies/kids/Frozen.2013.1080p.BluRay.x264-SPARKS/frozen.sample.mkv", 1 << 20, "samples"},
+		{"/downloads/movies/kids/Frozen.2013.1080p.BluRay.x264-SPARKS/Frozen.2013.1080p.BluRay.x264-SPARKS.mkv", 8 << 30, "kids"},
+		{"/downloads/tv/[SubsPlease] Frieren - 01 (1080p) [ABCD1234].mkv", 1 << 30, "anime"},
+		{"/downloads/movies/Dune.2021.2160p.UHD.BluRay.x265-TERMiNAL.mkv", 40 << 30, "uhd"},
+		{"/downloads/movies/Dune.2021.2160p.UHD.BluRay.x265-TERMiNAL.mkv", 4 << 30, ""},
+		{"/downloads/movies/Dune.2021.2160p.UHD.BluRay.x265-TERMiNAL.mkv", -1, ""},
+		{"/downloads/movies/Heat.1995.1080p.BluRay.x264-AMIABLE.mkv", 8 << 30, ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if r := e.Match(tt.path, tt.size, ""); r != nil {
+			got = r.Name
+		}
+		if got != tt.want {
+			t.Errorf("Match(%s, %d) = %q, want %q", tt.path, tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestResolutionFromFolder(t *testing.T) {
+	e := mustNew(t, Rule{Resolutions: []string{"2160p"}, Library: "/media/4K TV"})
+	if e.Match("/downloads/tv/Show.S01.2160p.WEB-DL-GROUP/Show.S01E01.mkv", 1, "") == nil {
+		t.Error("resolution in the release folder should match")
+	}
+}
+
+func TestPathRegex(t *testing.T) {
+	e := mustNew(t, Rule{PathRegex: `(?i)/tracker-a/`, MediaType: MediaTypeTV})
+	if e.Match("/downloads/Tracker-A/Some.Show.mkv", 1, "") == nil {
+		t.Error("path_regex should match")
+	}
+	if e.Match("/downloads/tracker-b/Some.Show.mkv", 1, "") != nil {
+		t.Error("path_regex should not match another tracker")
+	}
+}
+
+func TestCategory(t *testing.T) {
+	e := mustNew(t, Rule{Categories: []string{"tv-sonarr", "anime"}, Library: "/media/TV"})
+	if e.Match("/downloads/complete/Show.S01E01.mkv", 1, "TV-Sonarr") == nil {
+		t.Error("category should match case-insensitively")
+	}
+	if e.Match("/downloads/complete/Show.S01E01.mkv", 1, "radarr") != nil {
+		t.Error("another category should not match")
+	}
+	if v := e.Explain("/downloads/complete/Show.S01E01.mkv", 1, ""); v[0].Matched || !strings.Contains(v[0].Reason, "unknown") {
+		t.Errorf("verdict without a category = %+v", v[0])
+	}
+}
+
+func TestExplain(t *testing.T) {
+	e := mustNew(t,
+		Rule{Name: "kids", Path: "*/Kids/*", Library: "/media/Kids Movies"},
+		Rule{Name: "big", MinSize: 100 << 20, Title: "Heat", Year: "1995"},
+	)
+	verdicts := e.Explain("/downloads/movies/Heat.1995.mkv", 200<<20, "")
+	if len(verdicts) != 2 {
+		t.Fatalf("got %d verdicts", len(verdicts))
+	}
+	if verdicts[0].Matched || !strings.Contains(verdicts[0].Reason, "*/Kids/*") {
+		t.Errorf("kids verdict = %+v", verdicts[0])
+	}
+	if !verdicts[1].Matched {
+		t.Errorf("big verdict = %+v", verdicts[1])
+	}
+	if got := verdicts[1].Rule.Actions(); got != `title="Heat" year="1995"` {
+		t.Errorf("Actions = %s", got)
+	}
+}
+
+func TestNewRejectsUselessRules(t *testing.T) {
+	for _, r := range []Rule{
+		{Ignore: true},
+		{Path: "*"},
+		{Path: "*", MediaType: "music"},
+		{MinSize: 10, MaxSize: 5, Ignore: true},
+	} {
+		if _, err := New([]Rule{r}); err == nil {
+			t.Errorf("New accepted %+v", r)
+		}
+	}
+}
+
+func TestNilEngineMatchesNothing(t *testing.T) {
+	var e *Engine
+	if e.Match("/x.mkv", 1, "") != nil || e.Explain("/x.mkv", 1, "") != nil {
+		t.Error("nil engine matched")
+	}
+}
@@ -0,0 +1,132 @@
+// Package sabnzbd is a small client for the SABnzbd API: the jobs still
+// downloading or post-processing, the ones that finished and where they
+// were stored, under their original (non-obfuscated) job names.
+package sabnzbd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	URL     string
+	APIKey  string
+	Timeout time.Duration
+}
+
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+func NewClient(cfg Config) *Client {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &Client{
+		baseURL: strings.TrimRight(cfg.URL, "/"),
+		apiKey:  cfg.APIKey,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// History statuses. Anything else is still being verified, repaired,
+// extracted or moved.
+const (
+	StatusCompleted = "Completed"
+	StatusFailed    = "Failed"
+)
+
+// QueueSlot is a job still downloading.
+type QueueSlot struct {
+	NzoID    string `json:"nzo_id"`
+	Filename string `json:"filename"`
+	Category string `json:"cat"`
+	Status   string `json:"status"`
+}
+
+// HistorySlot is a job that finished downloading. Storage is where the
+// completed job ended up, once post-processing has moved it there.
+type HistorySlot struct {
+	NzoID    string `json:"nzo_id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Status   string `json:"status"`
+	Storage  string `json:"storage"`
+}
+
+// Queue lists the jobs still downloading.
+func (c *Client) Queue() ([]QueueSlot, error) {
+	var resp struct {
+		Queue struct {
+			Slots []QueueSlot `json:"slots"`
+		} `json:"queue"`
+	}
+	if err := c.get(url.Values{"mode": {"queue"}}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Queue.Slots, nil
+}
+
+// History lists the newest limit finished jobs, post-processing included.
+func (c *Client) History(limit int) ([]HistorySlot, error) {
+	var resp struct {
+		History struct {
+			Slots []HistorySlot `json:"slots"`
+		} `json:"history"`
+	}
+	if err := c.get(url.Values{"mode": {"history"}, "limit": {strconv.Itoa(limit)}}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.History.Slots, nil
+}
+
+// DeleteHistory removes a finished job from the history, leaving its
+// files alone.
+func (c *Client) DeleteHistory(nzoID string) error {
+	var resp struct{}
+	return c.get(url.Values{"mode": {"history"}, "name": {"delete"}, "value": {nzoID}}, &resp)
+}
+
+// get calls the API with params. SABnzbd reports errors such as a wrong
+// API key as {"status": false, "error": ...} with a 200.
+func (c *Client) get(params url.Values, result interface{}) error {
+	params.Set("output", "json")
+	params.Set("apikey", c.apiKey)
+	resp, err := c.httpClient.Get(c.baseURL + "/api?" + params.Encode())
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("SABnzbd API error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var failure struct {
+		Status *bool  `json:"status"`
+		Error  string `json:"error"`
+	}
+	if json.Unmarshal(body, &failure) == nil && failure.Status != nil && !*failure.Status {
+		return fmt.Errorf("SABnzbd API error: %s", failure.Error)
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
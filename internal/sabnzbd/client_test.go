@@ -0,0 +1,77 @@
+package sabnzbd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockSABServer(t *testing.T, deleted *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "/api", r.URL.Path)
+		assert.Equal(t, "json", q.Get("output"))
+		if q.Get("apikey") != "key" {
+			w.Write([]byte(`{"status": false, "error": "API Key Incorrect"}`))
+			return
+		}
+
+		switch {
+		case q.Get("mode") == "queue":
+			w.Write([]byte(`{"queue": {"slots": [
+				{"nzo_id": "SABnzbd_nzo_1", "filename": "Show.S01E02.1080p.WEB-DL-GROUP", "cat": "tv", "status": "Downloading"}
+			]}}`))
+		case q.Get("mode") == "history" && q.Get("name") == "delete":
+			*deleted = append(*deleted, q.Get("value"))
+			w.Write([]byte(`{"status": true}`))
+		case q.Get("mode") == "history":
+			assert.Equal(t, "50", q.Get("limit"))
+			w.Write([]byte(`{"history": {"slots": [
+				{"nzo_id": "SABnzbd_nzo_2", "name": "Heat.1995.1080p.BluRay.x264-GROUP", "category": "movies",
+				 "status": "Completed", "storage": "/downloads/complete/movies/Heat.1995.1080p.BluRay.x264-GROUP"}
+			]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestQueueAndHistory(t *testing.T) {
+	server := newMockSABServer(t, nil)
+	defer server.Close()
+	client := NewClient(Config{URL: server.URL, APIKey: "key"})
+
+	queue, err := client.Queue()
+	require.NoError(t, err)
+	require.Len(t, queue, 1)
+	assert.Equal(t, "Show.S01E02.1080p.WEB-DL-GROUP", queue[0].Filename)
+	assert.Equal(t, "tv", queue[0].Category)
+
+	history, err := client.History(50)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, StatusCompleted, history[0].Status)
+	assert.Equal(t, "/downloads/complete/movies/Heat.1995.1080p.BluRay.x264-GROUP", history[0].Storage)
+}
+
+func TestDeleteHistory(t *testing.T) {
+	var deleted []string
+	server := newMockSABServer(t, &deleted)
+	defer server.Close()
+	client := NewClient(Config{URL: server.URL, APIKey: "key"})
+
+	require.NoError(t, client.DeleteHistory("SABnzbd_nzo_2"))
+	assert.Equal(t, []string{"SABnzbd_nzo_2"}, deleted)
+}
+
+func TestWrongAPIKey(t *testing.T) {
+	server := newMockSABServer(t, nil)
+	defer server.Close()
+	client := NewClient(Config{URL: server.URL, APIKey: "wrong"})
+
+	_, err := client.Queue()
+	assert.ErrorContains(t, err, "API Key Incorrect")
+}
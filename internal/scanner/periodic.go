@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Nomadcxx/jellywatch/internal/ingest"
 	"github.com/Nomadcxx/jellywatch/internal/logging"
 	"github.com/Nomadcxx/jellywatch/internal/radarr"
 	"github.com/Nomadcxx/jellywatch/internal/service"
@@ -25,6 +26,7 @@ type PeriodicScanner struct {
 	logger      *logging.Logger
 	activityDir string
 	orphanCheck OrphanChecker
+	filters     *ingest.Filters
 
 	// Arr clients for health checks
 	sonarrClient *sonarr.Client
@@ -107,6 +109,7 @@ func NewPeriodicScanner(cfg ScannerConfig) *PeriodicScanner {
 		logger:       cfg.Logger,
 		activityDir:  cfg.ActivityDir,
 		orphanCheck:  orphanCheck,
+		filters:      cfg.Filters,
 		sonarrClient: cfg.SonarrClient,
 		radarrClient: cfg.RadarrClient,
 		healthy:      true,
@@ -294,6 +297,9 @@ func (s *PeriodicScanner) scanWatchDirectories() (processed int, errors int) {
 			}
 
 			if info.IsDir() {
+				if path != watchPath && s.filters.SkipDir(path) {
+					return filepath.SkipDir
+				}
 				return nil
 			}
 
@@ -301,6 +307,13 @@ func (s *PeriodicScanner) scanWatchDirectories() (processed int, errors int) {
 				return nil
 			}
 
+			if ok, reason := s.filters.Accept(path, info.Size(), ""); !ok {
+				s.logger.Debug("scanner", "Skipping filtered file",
+					logging.F("path", path),
+					logging.F("reason", reason))
+				return nil
+			}
+
 			event := watcher.FileEvent{
 				Type: watcher.EventCreate,
 				Path: path,
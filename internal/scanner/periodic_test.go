@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/Nomadcxx/jellywatch/internal/activity"
+	"github.com/Nomadcxx/jellywatch/internal/ingest"
 	"github.com/Nomadcxx/jellywatch/internal/jellyfin"
 	"github.com/Nomadcxx/jellywatch/internal/logging"
 	"github.com/Nomadcxx/jellywatch/internal/watcher"
@@ -127,6 +128,47 @@ func TestPeriodicScanner_ScanWatchDirectoriesForwardsFullPath(t *testing.T) {
 	}
 }
 
+func TestPeriodicScanner_ScanWatchDirectoriesAppliesFilters(t *testing.T) {
+	tempDir := t.TempDir()
+	keep := filepath.Join(tempDir, "Show", "Show.S01E01.mkv")
+	for _, path := range []string{
+		keep,
+		filepath.Join(tempDir, "Show", "Show.S01E01.sample.mkv"),
+		filepath.Join(tempDir, "_UNPACK_Show", "Show.S01E02.mkv"),
+		filepath.Join(tempDir, "@eaDir", "Show.S01E03.mkv"),
+		filepath.Join(tempDir, ".hidden.mkv"),
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	filters, err := ingest.New(ingest.Filter{
+		Exclude:    []string{"*.sample.*"},
+		IgnoreDirs: ingest.DefaultIgnoreDirs,
+	}, []ingest.Folder{{Path: tempDir, MediaType: ingest.MediaTypeTV}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := &recordingHandler{}
+	s := &PeriodicScanner{
+		watchPaths: []string{tempDir},
+		handler:    handler,
+		logger:     logging.Nop(),
+		filters:    filters,
+	}
+
+	if processed, _ := s.scanWatchDirectories(); processed != 1 {
+		t.Fatalf("processed = %d, want 1 (%v)", processed, handler.events)
+	}
+	if handler.events[0].Path != keep {
+		t.Fatalf("forwarded %q, want %q", handler.events[0].Path, keep)
+	}
+}
+
 func TestPeriodicScanner_RunScanIgnoresTypedNilOrphanChecker(t *testing.T) {
 	var checker *jellyfin.Client
 	s := NewPeriodicScanner(ScannerConfig{
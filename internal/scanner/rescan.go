@@ -34,11 +34,17 @@ func (s *FileScanner) FullRescan(ctx context.Context, roots []string, dryRun boo
 				return nil
 			}
 			if info.IsDir() {
+				if s.skipDir(root, p) {
+					return filepath.SkipDir
+				}
 				return nil
 			}
 			if !isVideoFile(p) {
 				return nil
 			}
+			if ok, _ := s.filters.Accept(p, info.Size(), ""); !ok {
+				return nil
+			}
 			files = append(files, fileEntry{path: p, root: root})
 			return nil
 		})
@@ -11,8 +11,11 @@ import (
 	"time"
 
 	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/ingest"
 	"github.com/Nomadcxx/jellywatch/internal/naming"
+	"github.com/Nomadcxx/jellywatch/internal/probe"
 	"github.com/Nomadcxx/jellywatch/internal/quality"
+	"github.com/Nomadcxx/jellywatch/internal/trash"
 )
 
 // FileScanner scans libraries and populates the media_files database
@@ -22,6 +25,10 @@ type FileScanner struct {
 	minMovieSize   int64
 	minEpisodeSize int64
 	skipPatterns   []string
+	templates      map[string]naming.Templates // naming templates by library root
+	prober         *probe.Prober               // Optional ffprobe inspection
+	profiles       *quality.ProfileSet         // quality scoring profiles by library
+	filters        *ingest.Filters             // watch-folder filters; nil uses ingest.Default
 }
 
 // ScanResult contains statistics from a scan operation
@@ -70,10 +77,7 @@ func NewFileScanner(db *database.MediaDB) *FileScanner {
 		minMovieSize:   quality.MinMovieSize,   // 500MB
 		minEpisodeSize: quality.MinEpisodeSize, // 50MB
 		skipPatterns: []string{
-			"sample", "trailer", "extras", "extra",
-			"featurette", "behind the scenes", "deleted scene",
-			"interview", "bonus", "cover", "artwork",
-			"proof", "rarbg",
+			"sample", "cover", "artwork", "proof", "rarbg",
 		},
 	}
 }
@@ -85,6 +89,32 @@ func NewFileScannerWithAI(db *database.MediaDB, aiHelper *AIHelper) *FileScanner
 	return scanner
 }
 
+// SetLibraryTemplates makes compliance checks follow each library's naming
+// templates, keyed by library root as passed to ScanLibraries.
+func (s *FileScanner) SetLibraryTemplates(templates map[string]naming.Templates) {
+	s.templates = templates
+}
+
+// SetProber enables ffprobe inspection: measured resolution, codecs and
+// tracks are stored per file and preferred over filename markers when
+// scoring. Files whose stored probe is newer than their mtime are not
+// probed again.
+func (s *FileScanner) SetProber(p *probe.Prober) {
+	s.prober = p
+}
+
+// SetQualityProfiles scores files with their library's quality profile;
+// without it the built-in weights are used.
+func (s *FileScanner) SetQualityProfiles(profiles *quality.ProfileSet) {
+	s.profiles = profiles
+}
+
+// SetFilters applies the watch-folder filters to scans, so folders and
+// files the watcher ignores aren't indexed either.
+func (s *FileScanner) SetFilters(filters *ingest.Filters) {
+	s.filters = filters
+}
+
 // ScanLibraries scans multiple libraries (TV and Movie)
 func (s *FileScanner) ScanLibraries(ctx context.Context, tvLibs, movieLibs []string) (*ScanResult, error) {
 	start := time.Now()
@@ -207,8 +237,11 @@ func (s *FileScanner) scanPathWithLibraryRoot(ctx context.Context, path string,
 			return nil // Continue walking
 		}
 
-		// Skip directories
+		// Skip directories, and recycle bins and ignored folders entirely
 		if info.IsDir() {
+			if s.skipDir(path, filePath) {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
@@ -251,6 +284,9 @@ func (s *FileScanner) scanPathWithProgress(ctx context.Context, path string, med
 		}
 
 		if info.IsDir() {
+			if s.skipDir(path, filePath) {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
@@ -297,17 +333,18 @@ func (s *FileScanner) processFile(filePath string, info os.FileInfo, libraryRoot
 
 	isEpisode := mediaType == "episode"
 
-	// Extract quality metadata
-	qualityMeta := quality.ExtractMetadata(filePath, info.Size(), isEpisode)
+	// Extract quality metadata, preferring measured stream data
+	probed, freshProbe := s.probeFile(filePath, info)
+	qualityMeta := s.profiles.For(filePath).ExtractMetadata(filePath, info.Size(), isEpisode, probed.Quality())
 
 	// Check compliance
-	isCompliant, issues := database.CheckCompliance(filePath, libraryRoot)
+	isCompliant, issues := database.CheckComplianceWithTemplates(filePath, libraryRoot, s.templates[libraryRoot])
 
 	// === STEP 1: REGEX PARSE ===
 	var rawTitle string        // For confidence calculation
 	var normalizedTitle string // For database storage
 	var year *int
-	var season, episode *int
+	var season, episode, episodeEnd *int
 	parseMethod := "regex"
 
 	if isEpisode {
@@ -339,6 +376,9 @@ func (s *FileScanner) processFile(filePath string, info os.FileInfo, libraryRoot
 		}
 		if episodeKnown && tv.Episode > 0 {
 			episode = &tv.Episode
+			if tv.IsMultiEpisode() {
+				episodeEnd = &tv.EpisodeEnd
+			}
 		}
 	} else {
 		movie, err := naming.ParseMovieName(filename)
@@ -372,6 +412,10 @@ func (s *FileScanner) processFile(filePath string, info os.FileInfo, libraryRoot
 					}
 					season = &tvInfo.Season
 					episode = &tvInfo.Episode
+					episodeEnd = nil
+					if tvInfo.IsMultiEpisode() {
+						episodeEnd = &tvInfo.EpisodeEnd
+					}
 					bestConfidence = folderConfidence
 					parseMethod = "folder"
 				}
@@ -395,8 +439,27 @@ func (s *FileScanner) processFile(filePath string, info os.FileInfo, libraryRoot
 		}
 	}
 
-	// === STEP 3: AI TRIGGER (if low confidence + AI enabled) ===
-	if s.aiHelper != nil && s.aiHelper.IsEnabled() {
+	// === STEP 3: MANUAL OVERRIDE (alias table) ===
+	aliasType := database.AliasMediaMovie
+	if isEpisode {
+		aliasType = database.AliasMediaSeries
+	}
+	alias, err := s.db.MatchAlias(aliasType, filePath, rawTitle)
+	if err != nil {
+		log.Printf("[scanner] alias lookup failed for %s: %v", filename, err)
+	}
+	if alias != nil {
+		rawTitle = alias.Title
+		normalizedTitle = database.NormalizeTitle(alias.Title)
+		if alias.Year != nil {
+			year = alias.Year
+		}
+		bestConfidence = 1.0
+		parseMethod = "alias"
+	}
+
+	// === STEP 4: AI TRIGGER (if low confidence + AI enabled) ===
+	if alias == nil && s.aiHelper != nil && s.aiHelper.IsEnabled() {
 		if shouldAttemptAIScan(filePath, filename, isEpisode, rawTitle, bestConfidence, s.aiHelper.GetAutoTriggerThreshold(), season, episode, year) {
 			ctx := context.Background()
 			aiResult, fromCache, err := s.aiHelper.TryParseWithContext(ctx, filename, mediaType, filepath.Dir(filePath), rawTitle, bestConfidence)
@@ -421,8 +484,7 @@ func (s *FileScanner) processFile(filePath string, info os.FileInfo, libraryRoot
 					if isEpisode && aiResult.Season != nil {
 						season = aiResult.Season.Int()
 						if len(aiResult.Episodes) > 0 {
-							ep := aiResult.Episodes[0]
-							episode = &ep
+							episode, episodeEnd = episodeSpan(aiResult.Episodes)
 						}
 					}
 					bestConfidence = aiResult.Confidence
@@ -438,8 +500,7 @@ func (s *FileScanner) processFile(filePath string, info os.FileInfo, libraryRoot
 					if isEpisode && aiResult.Season != nil {
 						season = aiResult.Season.Int()
 						if len(aiResult.Episodes) > 0 {
-							ep := aiResult.Episodes[0]
-							episode = &ep
+							episode, episodeEnd = episodeSpan(aiResult.Episodes)
 						}
 					}
 					bestConfidence = aiResult.Confidence
@@ -452,7 +513,7 @@ func (s *FileScanner) processFile(filePath string, info os.FileInfo, libraryRoot
 		}
 	}
 
-	// === STEP 4: SAVE TO DATABASE ===
+	// === STEP 5: SAVE TO DATABASE ===
 	needsReview := bestConfidence < 0.8
 	if needsReview {
 		result.NeedsReview++
@@ -460,10 +521,18 @@ func (s *FileScanner) processFile(filePath string, info os.FileInfo, libraryRoot
 
 	var parentSeriesID *int64
 	var parentEpisodeID *int64
+	var episodeIDs []int64
 	var parentMovieID *int64
 	if isEpisode && normalizedTitle != "" {
 		seriesPath := deriveSeriesPath(filePath, libraryRoot)
 		seriesTitle, yearValue := seriesIdentityFromPath(seriesPath, rawTitle, year)
+		if alias != nil {
+			// The override names the show even when its folder doesn't.
+			seriesTitle = alias.Title
+			if alias.Year != nil {
+				yearValue = *alias.Year
+			}
+		}
 		series := &database.Series{
 			Title:          seriesTitle,
 			Year:           yearValue,
@@ -477,21 +546,36 @@ func (s *FileScanner) processFile(filePath string, info os.FileInfo, libraryRoot
 				return &t
 			}(),
 		}
+		if alias != nil {
+			series.TvdbID = alias.TvdbID
+			series.ImdbID = alias.ImdbID
+		}
 		if _, err := s.db.UpsertSeries(series); err != nil {
 			return fmt.Errorf("upsert series: %w", err)
 		}
 		parentSeriesID = &series.ID
 
 		if season != nil && episode != nil {
-			ep := &database.Episode{
-				SeriesID: series.ID,
-				Season:   *season,
-				Episode:  *episode,
+			// A multi-episode file backs every episode in its span; the
+			// first one is recorded as the file's parent episode.
+			last := *episode
+			if episodeEnd != nil && *episodeEnd > last {
+				last = *episodeEnd
 			}
-			if err := s.db.UpsertEpisode(ep); err != nil {
-				return fmt.Errorf("upsert episode: %w", err)
+			for n := *episode; n <= last; n++ {
+				ep := &database.Episode{
+					SeriesID: series.ID,
+					Season:   *season,
+					Episode:  n,
+				}
+				if err := s.db.UpsertEpisode(ep); err != nil {
+					return fmt.Errorf("upsert episode: %w", err)
+				}
+				if parentEpisodeID == nil {
+					parentEpisodeID = &ep.ID
+				}
+				episodeIDs = append(episodeIDs, ep.ID)
 			}
-			parentEpisodeID = &ep.ID
 		}
 	}
 	if !isEpisode {
@@ -507,6 +591,10 @@ func (s *FileScanner) processFile(filePath string, info os.FileInfo, libraryRoot
 			Source:         "filesystem",
 			SourcePriority: 50,
 		}
+		if alias != nil {
+			movie.TmdbID = alias.TmdbID
+			movie.ImdbID = alias.ImdbID
+		}
 		if _, err := s.db.UpsertMovie(movie); err != nil {
 			return fmt.Errorf("upsert movie: %w", err)
 		}
@@ -526,6 +614,7 @@ func (s *FileScanner) processFile(filePath string, info os.FileInfo, libraryRoot
 		Year:                year,
 		Season:              season,
 		Episode:             episode,
+		EpisodeEnd:          episodeEnd,
 		Resolution:          qualityMeta.Resolution,
 		SourceType:          qualityMeta.SourceType,
 		Codec:               qualityMeta.Codec,
@@ -545,9 +634,14 @@ func (s *FileScanner) processFile(filePath string, info os.FileInfo, libraryRoot
 	if err := s.db.UpsertMediaFile(file); err != nil {
 		return err
 	}
+	if freshProbe {
+		if err := s.db.UpdateMediaFileProbe(filePath, probed); err != nil {
+			return fmt.Errorf("store probe: %w", err)
+		}
+	}
 
-	if parentEpisodeID != nil {
-		if err := s.db.UpdateEpisodeBestFile(*parentEpisodeID, &file.ID); err != nil {
+	for _, episodeID := range episodeIDs {
+		if err := s.db.UpdateEpisodeBestFile(episodeID, &file.ID); err != nil {
 			return fmt.Errorf("update episode best file: %w", err)
 		}
 	}
@@ -560,6 +654,25 @@ func (s *FileScanner) processFile(filePath string, info os.FileInfo, libraryRoot
 	return nil
 }
 
+// probeFile returns ffprobe data for filePath, reusing the stored probe
+// while the file is unchanged. fresh reports whether it was just measured
+// and still needs storing. Probe failures only cost the measured data; the
+// file is still indexed from its name.
+func (s *FileScanner) probeFile(filePath string, info os.FileInfo) (result *probe.Result, fresh bool) {
+	if s.prober == nil {
+		return nil, false
+	}
+	if stored, err := s.db.GetMediaFileProbe(filePath); err == nil && stored != nil && !stored.ProbedAt.Before(info.ModTime()) {
+		return stored, false
+	}
+	result, err := s.prober.Probe(context.Background(), filePath)
+	if err != nil {
+		log.Printf("[probe] %s: %v", filePath, err)
+		return nil, false
+	}
+	return result, true
+}
+
 func shouldAttemptAIScan(filePath, filename string, isEpisode bool, rawTitle string, confidence, threshold float64, season, episode, year *int) bool {
 	if confidence >= threshold {
 		return false
@@ -656,6 +769,18 @@ func parseTVShowFromLibraryFolders(path, libraryRoot string) (*naming.TVShowInfo
 	}, nil
 }
 
+// episodeSpan converts an AI episode list into the first episode and, for a
+// contiguous multi-episode run, the last episode of the span.
+func episodeSpan(episodes []int) (episode, episodeEnd *int) {
+	var info naming.TVShowInfo
+	info.SetEpisodes(episodes)
+	episode = &info.Episode
+	if info.IsMultiEpisode() {
+		episodeEnd = &info.EpisodeEnd
+	}
+	return episode, episodeEnd
+}
+
 func parseSeasonFolder(name string) (int, bool) {
 	lower := strings.TrimSpace(strings.ToLower(name))
 	if !strings.HasPrefix(lower, "season") {
@@ -693,6 +818,12 @@ func seriesIdentityFromPath(seriesPath, fallbackTitle string, fallbackYear *int)
 	return title, year
 }
 
+// skipDir reports whether a walk from root should not enter dir: recycle
+// bins and folders the ingest filters ignore.
+func (s *FileScanner) skipDir(root, dir string) bool {
+	return trash.IsTrashDir(dir) || (dir != root && s.filters.SkipDir(dir))
+}
+
 // shouldIncludeFile determines if a file should be indexed
 func (s *FileScanner) shouldIncludeFile(path string, size int64, mediaType string) bool {
 	// Check if it's extra content (sample, trailer, etc.)
@@ -700,6 +831,11 @@ func (s *FileScanner) shouldIncludeFile(path string, size int64, mediaType strin
 		return false
 	}
 
+	// Apply the watch-folder filters
+	if ok, _ := s.filters.Accept(path, size, mediaType); !ok {
+		return false
+	}
+
 	// Apply size thresholds
 	if mediaType == "movie" && size < s.minMovieSize {
 		return false
@@ -713,6 +849,9 @@ func (s *FileScanner) shouldIncludeFile(path string, size int64, mediaType strin
 
 // isExtraContent checks if file is a sample, trailer, or other extra
 func (s *FileScanner) isExtraContent(path string) bool {
+	if naming.IsExtra(path) {
+		return true
+	}
 	lowerPath := strings.ToLower(path)
 
 	// Check filename and parent folder
@@ -11,6 +11,7 @@ import (
 	"github.com/Nomadcxx/jellywatch/internal/ai"
 	"github.com/Nomadcxx/jellywatch/internal/config"
 	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/probe"
 )
 
 func TestNewFileScanner(t *testing.T) {
@@ -78,6 +79,11 @@ func TestIsExtraContent(t *testing.T) {
 		{"/media/Movies/Movie (2020)/cover.jpg", true},
 		{"/media/TV/Show/Season 01/Show S01E01.mkv", false},
 		{"/media/TV/Show/Season 01/RARBG.txt", true},
+		{"/media/Movies/Movie (2020)/Movie (2020)-trailer.mkv", true},
+		{"/media/Movies/Movie (2020)/Behind The Scenes/Part 1.mkv", true},
+		{"/media/TV/Show/Season 01/extras/Bloopers.mkv", true},
+		{"/media/Movies/The Interview (2014)/The Interview (2014).mkv", false},
+		{"/media/TV/Trailer Park Boys/Season 01/Trailer Park Boys S01E01.mkv", false},
 	}
 
 	for _, tt := range tests {
@@ -214,6 +220,103 @@ func TestProcessFile_Movie(t *testing.T) {
 	}
 }
 
+func TestProcessFile_AliasOverridesParse(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	scanner := NewFileScanner(db)
+
+	year, tvdb := 2005, 73244
+	if err := db.UpsertAlias(&database.Alias{
+		Kind: database.AliasKindFolder, Pattern: "The Office", MediaType: database.AliasMediaSeries,
+		Title: "The Office (US)", Year: &year, TvdbID: &tvdb,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tempDir := t.TempDir()
+	seasonDir := filepath.Join(tempDir, "The Office", "Season 02")
+	if err := os.MkdirAll(seasonDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(seasonDir, "The Office S02E01.mkv")
+	if err := os.WriteFile(path, []byte("fake video content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := scanner.processFile(path, info, tempDir, "episode", &ScanResult{}); err != nil {
+		t.Fatalf("processFile failed: %v", err)
+	}
+
+	file, err := db.GetMediaFile(path)
+	if err != nil || file == nil {
+		t.Fatalf("GetMediaFile = %v, %v", file, err)
+	}
+	if file.ParseMethod != "alias" || file.NormalizedTitle != "theofficeus" {
+		t.Errorf("parse = %s %q, want alias theofficeus", file.ParseMethod, file.NormalizedTitle)
+	}
+	series, err := db.GetSeriesByTitle("The Office (US)", 2005)
+	if err != nil || series == nil {
+		t.Fatalf("GetSeriesByTitle = %v, %v", series, err)
+	}
+	if series.TvdbID == nil || *series.TvdbID != tvdb {
+		t.Errorf("series tvdb id = %v, want %d", series.TvdbID, tvdb)
+	}
+}
+
+func TestProcessFile_ProbedQualityOverridesFilename(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	scanner := NewFileScanner(db)
+
+	bin, err := probe.WriteFakeBinary(t.TempDir(), `{
+		"streams": [
+			{"codec_type": "video", "codec_name": "hevc", "width": 3840, "height": 2160, "color_transfer": "smpte2084"},
+			{"codec_type": "audio", "codec_name": "eac3", "channels": 6, "tags": {"language": "eng"}}
+		],
+		"format": {"duration": "6000.0"}
+	}`, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner.SetProber(probe.New(bin, 0))
+
+	tempDir := t.TempDir()
+	moviePath := filepath.Join(tempDir, "Interstellar (2014)", "Interstellar (2014) 720p.mkv")
+	if err := os.MkdirAll(filepath.Dir(moviePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(moviePath, []byte("fake video content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(moviePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := scanner.processFile(moviePath, info, tempDir, "movie", &ScanResult{}); err != nil {
+		t.Fatalf("processFile failed: %v", err)
+	}
+
+	file, err := db.GetMediaFile(moviePath)
+	if err != nil || file == nil {
+		t.Fatalf("GetMediaFile() = %v, %v", file, err)
+	}
+	if file.Resolution != "2160p" || file.Codec != "x265" {
+		t.Errorf("quality = %s %s, want probed 2160p x265", file.Resolution, file.Codec)
+	}
+	stored, err := db.GetMediaFileProbe(moviePath)
+	if err != nil || stored == nil {
+		t.Fatalf("GetMediaFileProbe() = %v, %v; want stored probe", stored, err)
+	}
+	if stored.HDR != probe.HDR10 || len(stored.AudioTracks) != 1 || stored.AudioTracks[0].Language != "eng" {
+		t.Errorf("stored probe = %+v, want HDR10 with one eng audio track", stored)
+	}
+}
+
 func TestProcessFile_Episode(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
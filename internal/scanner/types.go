@@ -3,6 +3,7 @@ package scanner
 import (
 	"time"
 
+	"github.com/Nomadcxx/jellywatch/internal/ingest"
 	"github.com/Nomadcxx/jellywatch/internal/jellyfin"
 	"github.com/Nomadcxx/jellywatch/internal/logging"
 	"github.com/Nomadcxx/jellywatch/internal/radarr"
@@ -22,6 +23,9 @@ type ScannerConfig struct {
 	Logger      *logging.Logger
 	ActivityDir string
 	OrphanCheck OrphanChecker
+	// Filters decides which files in the watch folders are media; nil
+	// uses ingest.Default.
+	Filters *ingest.Filters
 
 	// Arr health check (optional)
 	SonarrClient *sonarr.Client
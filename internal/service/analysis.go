@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/naming"
 	"github.com/Nomadcxx/jellywatch/internal/video"
 )
 
@@ -19,8 +21,10 @@ type DuplicateGroup struct {
 	Title            string
 	Year             *int
 	MediaType        string // "movie" or "series"
+	Edition          string // movie version ("Director's Cut", "2160p"), "" for the standard cut
 	Season           *int   // For TV
 	Episode          *int   // For TV
+	QualityProfile   string // scoring profile that ranked Files; "" for stored scores
 	Files            []MediaFile
 	BestFileID       int64
 	ReclaimableBytes int64
@@ -34,6 +38,10 @@ type MediaFile struct {
 	Resolution   string
 	SourceType   string
 	QualityScore int
+	// Parts lists the other parts of a stacked (multi-part) movie this
+	// file leads. Size covers the whole stack, and the parts are kept or
+	// removed together with the file.
+	Parts []string
 }
 
 // ScatteredItem represents media scattered across multiple locations
@@ -87,41 +95,58 @@ func (s *CleanupService) AnalyzeDuplicates() (*DuplicateAnalysis, error) {
 	}
 
 	for _, mg := range movieGroups {
-		files, err := s.pruneMissingMediaFiles(mg.Files)
+		pruned, err := s.pruneMissingMediaFiles(mg.Files)
 		if err != nil {
 			return nil, err
 		}
-		if len(files) < 2 {
-			continue
-		}
 
-		group := DuplicateGroup{
-			ID:        generateGroupID(mg.NormalizedTitle, mg.Year, nil, nil),
-			Title:     mg.NormalizedTitle,
-			Year:      mg.Year,
-			MediaType: "movie",
-			Files:     make([]MediaFile, len(files)),
-		}
+		// Different editions of a movie, and copies labelled with their
+		// resolution, are distinct versions, not duplicates: only copies
+		// of the same version compete.
+		for _, files := range splitByVersion(pruned) {
+			profile := s.rankFiles(files, false)
+			// The parts of a stacked movie are one copy, compared and
+			// kept as a unit.
+			units := collapseStacks(files)
+			if len(units) < 2 {
+				continue
+			}
+			edition := naming.ParseMovieVersion(files[0].Path)
+
+			group := DuplicateGroup{
+				ID:             movieGroupID(mg.NormalizedTitle, mg.Year, edition),
+				Title:          mg.NormalizedTitle,
+				Year:           mg.Year,
+				MediaType:      "movie",
+				Edition:        edition,
+				QualityProfile: profile,
+				Files:          make([]MediaFile, 0, len(files)),
+			}
 
-		for i, f := range files {
-			group.Files[i] = MediaFile{
-				ID:           f.ID,
-				Path:         f.Path,
-				Size:         f.Size,
-				Resolution:   f.Resolution,
-				SourceType:   f.SourceType,
-				QualityScore: f.QualityScore,
+			// The best unit is listed once, led by its first part, so
+			// callers deleting everything but BestFileID never touch
+			// its other parts. Inferior stacks are listed part by part.
+			best := stackMediaFile(units[0])
+			group.Files = append(group.Files, best)
+			group.BestFileID = best.ID
+			for _, unit := range units[1:] {
+				for _, f := range unit {
+					group.Files = append(group.Files, MediaFile{
+						ID:           f.ID,
+						Path:         f.Path,
+						Size:         f.Size,
+						Resolution:   f.Resolution,
+						SourceType:   f.SourceType,
+						QualityScore: f.QualityScore,
+					})
+					group.ReclaimableBytes += f.Size
+				}
 			}
-		}
 
-		group.BestFileID = files[0].ID
-		for _, f := range files[1:] {
-			group.ReclaimableBytes += f.Size
+			analysis.Groups = append(analysis.Groups, group)
+			analysis.TotalFiles += len(files)
+			analysis.ReclaimableBytes += group.ReclaimableBytes
 		}
-
-		analysis.Groups = append(analysis.Groups, group)
-		analysis.TotalFiles += len(files)
-		analysis.ReclaimableBytes += group.ReclaimableBytes
 	}
 
 	// Get episode duplicates
@@ -138,15 +163,17 @@ func (s *CleanupService) AnalyzeDuplicates() (*DuplicateAnalysis, error) {
 		if len(files) < 2 {
 			continue
 		}
+		profile := s.rankFiles(files, true)
 
 		group := DuplicateGroup{
-			ID:        generateGroupID(eg.NormalizedTitle, eg.Year, eg.Season, eg.Episode),
-			Title:     eg.NormalizedTitle,
-			Year:      eg.Year,
-			MediaType: "series",
-			Season:    eg.Season,
-			Episode:   eg.Episode,
-			Files:     make([]MediaFile, len(files)),
+			ID:             generateGroupID(eg.NormalizedTitle, eg.Year, eg.Season, eg.Episode),
+			Title:          eg.NormalizedTitle,
+			Year:           eg.Year,
+			MediaType:      "series",
+			Season:         eg.Season,
+			Episode:        eg.Episode,
+			QualityProfile: profile,
+			Files:          make([]MediaFile, len(files)),
 		}
 
 		for i, f := range files {
@@ -341,6 +368,111 @@ func isScatteredMediaFile(ext string) bool {
 	return video.IsVideoExt(ext)
 }
 
+// splitByVersion partitions movie files by edition or resolution version,
+// keeping the incoming (best-first) order within each partition.
+func splitByVersion(files []*database.MediaFile) [][]*database.MediaFile {
+	var order []string
+	byEdition := make(map[string][]*database.MediaFile)
+	for _, f := range files {
+		edition := naming.ParseMovieVersion(f.Path)
+		if _, ok := byEdition[edition]; !ok {
+			order = append(order, edition)
+		}
+		byEdition[edition] = append(byEdition[edition], f)
+	}
+	out := make([][]*database.MediaFile, 0, len(order))
+	for _, edition := range order {
+		out = append(out, byEdition[edition])
+	}
+	return out
+}
+
+// rankFiles re-scores duplicate copies with their libraries' quality
+// profile and sorts them best first (score, then size, then ID, like the
+// duplicate queries). Measured ffprobe data is used where a scan stored
+// it. Returns the profile name, or "" when no profiles are configured and
+// the stored order is kept.
+func (s *CleanupService) rankFiles(files []*database.MediaFile, isEpisode bool) string {
+	if s.profiles == nil {
+		return ""
+	}
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Path
+	}
+	profile := s.profiles.ForFiles(paths)
+	for _, f := range files {
+		probed, _ := s.db.GetMediaFileProbe(f.Path)
+		f.QualityScore = profile.ExtractMetadata(f.Path, f.Size, isEpisode, probed.Quality()).QualityScore
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		a, b := files[i], files[j]
+		if a.QualityScore != b.QualityScore {
+			return a.QualityScore > b.QualityScore
+		}
+		if a.Size != b.Size {
+			return a.Size > b.Size
+		}
+		return a.ID < b.ID
+	})
+	return profile.Name
+}
+
+// collapseStacks groups the parts of each stacked movie into one unit,
+// sorted by part; other files are units of their own. Units keep the
+// incoming (best-first) order of their first file.
+func collapseStacks(files []*database.MediaFile) [][]*database.MediaFile {
+	var units [][]*database.MediaFile
+	stackIndex := make(map[string]int)
+	for _, f := range files {
+		if naming.ParseStackPart(f.Path) == 0 {
+			units = append(units, []*database.MediaFile{f})
+			continue
+		}
+		key := filepath.Join(filepath.Dir(f.Path), strings.ToLower(naming.StripStackPart(f.Path)))
+		if i, ok := stackIndex[key]; ok {
+			units[i] = append(units[i], f)
+			continue
+		}
+		stackIndex[key] = len(units)
+		units = append(units, []*database.MediaFile{f})
+	}
+	for _, unit := range units {
+		sort.SliceStable(unit, func(i, j int) bool {
+			return naming.ParseStackPart(unit[i].Path) < naming.ParseStackPart(unit[j].Path)
+		})
+	}
+	return units
+}
+
+// stackMediaFile describes a unit from collapseStacks as its first file,
+// with the size of the whole unit and the paths of the remaining parts.
+func stackMediaFile(unit []*database.MediaFile) MediaFile {
+	first := unit[0]
+	mf := MediaFile{
+		ID:           first.ID,
+		Path:         first.Path,
+		Size:         first.Size,
+		Resolution:   first.Resolution,
+		SourceType:   first.SourceType,
+		QualityScore: first.QualityScore,
+	}
+	for _, f := range unit[1:] {
+		mf.Size += f.Size
+		mf.Parts = append(mf.Parts, f.Path)
+	}
+	return mf
+}
+
+// movieGroupID is generateGroupID for one edition of a movie. The standard
+// cut keeps the plain title/year ID.
+func movieGroupID(title string, year *int, edition string) string {
+	if edition == "" {
+		return generateGroupID(title, year, nil, nil)
+	}
+	return generateGroupID(title+" - "+strings.ToLower(edition), year, nil, nil)
+}
+
 // generateGroupID creates a unique ID for a duplicate group
 func generateGroupID(title string, year *int, season, episode *int) string {
 	parts := []string{strings.ToLower(title)}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/quality"
 )
 
 func setupTestDB(t *testing.T) *database.MediaDB {
@@ -146,6 +147,133 @@ func TestAnalyzeDuplicates_FindsMovieDuplicates(t *testing.T) {
 	}
 }
 
+func TestAnalyzeDuplicates_KeepsEditionsApart(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	year := 1982
+	root := t.TempDir()
+	paths := []string{
+		filepath.Join(root, "storage1", "Blade Runner (1982)", "Blade Runner (1982).mkv"),
+		filepath.Join(root, "storage2", "Blade Runner (1982)", "Blade Runner (1982).mkv"),
+		filepath.Join(root, "storage1", "Blade Runner (1982)", "Blade Runner (1982) - Director's Cut.mkv"),
+		filepath.Join(root, "storage2", "Blade Runner (1982)", "Blade.Runner.1982.Directors.Cut.1080p.BluRay.mkv"),
+		// A resolution version is not a copy of the standard cut either.
+		filepath.Join(root, "storage1", "Blade Runner (1982)", "Blade Runner (1982) - 2160p.mkv"),
+	}
+	for i, path := range paths {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("video"), 0644); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+		if err := db.UpsertMediaFile(&database.MediaFile{
+			Path:            path,
+			NormalizedTitle: "bladerunner",
+			Year:            &year,
+			MediaType:       "movie",
+			Size:            int64(1000 + i),
+			QualityScore:    100 - i,
+		}); err != nil {
+			t.Fatalf("failed to insert media file: %v", err)
+		}
+	}
+
+	svc := NewCleanupService(db)
+	analysis, err := svc.AnalyzeDuplicates()
+	if err != nil {
+		t.Fatalf("AnalyzeDuplicates failed: %v", err)
+	}
+	if analysis.TotalGroups != 2 {
+		t.Fatalf("Expected standard cut and Director's Cut groups, got %d", analysis.TotalGroups)
+	}
+	editions := map[string]int{}
+	ids := map[string]bool{}
+	for _, g := range analysis.Groups {
+		editions[g.Edition] = len(g.Files)
+		ids[g.ID] = true
+	}
+	if editions[""] != 2 || editions["Director's Cut"] != 2 {
+		t.Errorf("unexpected edition groups: %v", editions)
+	}
+	if len(ids) != 2 {
+		t.Errorf("edition groups must have distinct IDs")
+	}
+
+	group, err := svc.FindDuplicateGroup("movie", "bladerunner", "Director's Cut", &year, nil, nil)
+	if err != nil {
+		t.Fatalf("FindDuplicateGroup failed: %v", err)
+	}
+	if group == nil || group.Edition != "Director's Cut" {
+		t.Fatalf("expected Director's Cut group, got %+v", group)
+	}
+}
+
+func TestAnalyzeDuplicates_ComparesStacksAsUnits(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	year := 1995
+	root := t.TempDir()
+	stackDir := filepath.Join(root, "storage1", "Heat (1995)")
+	singleDir := filepath.Join(root, "storage2", "Heat (1995)")
+	files := []*database.MediaFile{
+		{Path: filepath.Join(stackDir, "Heat (1995) - part2.mkv"), Size: 2000, QualityScore: 300},
+		{Path: filepath.Join(stackDir, "Heat (1995) - part1.mkv"), Size: 2100, QualityScore: 300},
+		{Path: filepath.Join(singleDir, "Heat (1995).mkv"), Size: 3000, QualityScore: 200},
+	}
+	for _, f := range files {
+		f.NormalizedTitle = "heat"
+		f.Year = &year
+		f.MediaType = "movie"
+		if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(f.Path, []byte("video"), 0644); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+		if err := db.UpsertMediaFile(f); err != nil {
+			t.Fatalf("failed to insert media file: %v", err)
+		}
+	}
+
+	svc := NewCleanupService(db)
+	analysis, err := svc.AnalyzeDuplicates()
+	if err != nil {
+		t.Fatalf("AnalyzeDuplicates failed: %v", err)
+	}
+	if analysis.TotalGroups != 1 {
+		t.Fatalf("TotalGroups = %d, want 1", analysis.TotalGroups)
+	}
+	group := analysis.Groups[0]
+	if len(group.Files) != 2 {
+		t.Fatalf("Files = %+v, want the stack and the single file", group.Files)
+	}
+	best := group.Files[0]
+	if group.BestFileID != files[1].ID || best.ID != files[1].ID {
+		t.Errorf("BestFileID = %d, want first part %d", group.BestFileID, files[1].ID)
+	}
+	if best.Size != 4100 || len(best.Parts) != 1 || best.Parts[0] != files[0].Path {
+		t.Errorf("best = %+v, want the whole stack", best)
+	}
+	if group.ReclaimableBytes != 3000 {
+		t.Errorf("ReclaimableBytes = %d, want 3000", group.ReclaimableBytes)
+	}
+
+	// The parts of a single stack are not duplicates of each other.
+	if err := os.Remove(files[2].Path); err != nil {
+		t.Fatal(err)
+	}
+	analysis, err = svc.AnalyzeDuplicates()
+	if err != nil {
+		t.Fatalf("AnalyzeDuplicates failed: %v", err)
+	}
+	if analysis.TotalGroups != 0 {
+		t.Errorf("TotalGroups = %d, want 0 for a lone stack", analysis.TotalGroups)
+	}
+}
+
 func TestAnalyzeDuplicates_TieBreaksBestFileBySize(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -515,3 +643,79 @@ func TestAnalyzeScattered_FiltersMissingConflictLocations(t *testing.T) {
 		t.Fatalf("FilesToMove = %d, want 1", item.FilesToMove)
 	}
 }
+
+func TestAnalyzeDuplicates_RanksWithLibraryProfile(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	year := 2020
+	root := t.TempDir()
+	library := filepath.Join(root, "Movies")
+	remux := filepath.Join(library, "Movie (2020)", "Movie.2020.1080p.BluRay.REMUX.TrueHD.Atmos.mkv")
+	web := filepath.Join(library, "Movie (2020) [4K]", "Movie.2020.2160p.WEB-DL.DDP5.1.x265.mkv")
+	for _, f := range []struct {
+		path  string
+		size  int64
+		score int
+	}{
+		// Stored scores come from the default profile: 4K WEB-DL wins.
+		{remux, 30 << 30, 430},
+		{web, 15 << 30, 475},
+	} {
+		if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(f.path, []byte("video"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.UpsertMediaFile(&database.MediaFile{
+			Path: f.path, NormalizedTitle: "movie", Year: &year, MediaType: "movie",
+			Size: f.size, QualityScore: f.score, LibraryRoot: library,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bestPath := func(svc *CleanupService) (string, string) {
+		t.Helper()
+		analysis, err := svc.AnalyzeDuplicates()
+		if err != nil {
+			t.Fatalf("AnalyzeDuplicates failed: %v", err)
+		}
+		if len(analysis.Groups) != 1 {
+			t.Fatalf("expected 1 group, got %d", len(analysis.Groups))
+		}
+		g := analysis.Groups[0]
+		for _, f := range g.Files {
+			if f.ID == g.BestFileID {
+				return f.Path, g.QualityProfile
+			}
+		}
+		t.Fatal("best file missing from group")
+		return "", ""
+	}
+
+	if got, _ := bestPath(NewCleanupService(db)); got != web {
+		t.Fatalf("stored scores kept %s, want the 4K WEB-DL", got)
+	}
+
+	profile := quality.DefaultProfile()
+	profile.Name = "remux-first"
+	for factor, weights := range map[string]map[string]int{
+		"resolution": {"2160p": 300},
+		"source":     {"REMUX": 200},
+		"audio":      {"Atmos": 80},
+	} {
+		for name, points := range weights {
+			if err := profile.SetWeight(factor, name, points); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	svc := NewCleanupService(db)
+	svc.SetQualityProfiles(quality.NewProfileSet(nil, map[string]*quality.Profile{library: profile}))
+	got, name := bestPath(svc)
+	if got != remux || name != "remux-first" {
+		t.Errorf("profile kept %s (profile %q), want the REMUX under remux-first", got, name)
+	}
+}
@@ -110,7 +110,8 @@ func sizeDivergesMaterially(a, b int64) bool {
 //
 // Used by the housekeeping executor to re-fetch a group at execute time
 // using only the lightweight payload it persists in housekeeping_tasks.
-func (s *CleanupService) FindDuplicateGroup(mediaType, normalizedTitle string,
+// edition selects one movie edition; "" is the standard cut.
+func (s *CleanupService) FindDuplicateGroup(mediaType, normalizedTitle, edition string,
 	year, season, episode *int) (*DuplicateGroup, error) {
 
 	analysis, err := s.AnalyzeDuplicates()
@@ -119,7 +120,7 @@ func (s *CleanupService) FindDuplicateGroup(mediaType, normalizedTitle string,
 	}
 	for i := range analysis.Groups {
 		g := &analysis.Groups[i]
-		if g.MediaType != mediaType || g.Title != normalizedTitle {
+		if g.MediaType != mediaType || g.Title != normalizedTitle || g.Edition != edition {
 			continue
 		}
 		if !intPtrEq(g.Year, year) {
@@ -147,7 +148,8 @@ func intPtrEq(a, b *int) bool {
 }
 
 // ResolveDuplicateGroup deletes the inferior copies in a group, keeping
-// BestFileID. Thin wrapper around DeleteDuplicateFiles so the housekeeping
+// BestFileID, which AnalyzeDuplicates picked with the group's quality
+// profile. Thin wrapper around DeleteDuplicateFiles so the housekeeping
 // engine has a stable per-group entry point.
 func (s *CleanupService) ResolveDuplicateGroup(group *DuplicateGroup) (deleted int, reclaimed int64, err error) {
 	if group == nil {
@@ -5,11 +5,15 @@ import (
 	"os"
 
 	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/quality"
+	"github.com/Nomadcxx/jellywatch/internal/trash"
 )
 
 // CleanupService provides operations for cleaning up media libraries
 type CleanupService struct {
-	db *database.MediaDB
+	db       *database.MediaDB
+	profiles *quality.ProfileSet // nil ranks duplicates by stored quality_score
+	trash    *trash.Bin          // nil deletes for good
 }
 
 // NewCleanupService creates a new cleanup service
@@ -17,8 +21,25 @@ func NewCleanupService(db *database.MediaDB) *CleanupService {
 	return &CleanupService{db: db}
 }
 
+// SetQualityProfiles makes duplicate analysis re-score every copy with
+// the scoring profile of its library instead of trusting the quality_score
+// stored at scan time, so profile changes apply without a rescan.
+func (s *CleanupService) SetQualityProfiles(profiles *quality.ProfileSet) {
+	s.profiles = profiles
+}
+
+// SetTrash sends deleted duplicates to the recycle bin.
+func (s *CleanupService) SetTrash(bin *trash.Bin) {
+	s.trash = bin
+}
+
 // DeleteFileByID deletes a media file from both the database and filesystem
 func (s *CleanupService) DeleteFileByID(fileID int64) error {
+	return s.deleteFile(fileID, database.NewJournalBatch(database.OpSourceDuplicate))
+}
+
+// deleteFile deletes a media file and journals the deletion in batch.
+func (s *CleanupService) deleteFile(fileID int64, batch string) error {
 	// Get the file first to get its path
 	file, err := s.db.GetMediaFileByID(fileID)
 	if err != nil {
@@ -29,9 +50,20 @@ func (s *CleanupService) DeleteFileByID(fileID int64) error {
 	}
 
 	// Delete from filesystem first — if this fails, DB stays consistent
-	if err := os.Remove(file.Path); err != nil && !os.IsNotExist(err) {
+	keptAt, err := s.trash.Remove(file.Path, "duplicate")
+	if err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to delete file from filesystem: %w", err)
 	}
+	// Best effort: the file is gone either way, so a journal failure must
+	// not leave its row behind.
+	_, _ = s.db.RecordOperation(database.JournalOp{
+		BatchID:    batch,
+		Source:     database.OpSourceDuplicate,
+		Kind:       database.OpDelete,
+		BeforePath: file.Path,
+		AfterPath:  keptAt,
+		Reversible: keptAt != "",
+	})
 
 	// Now remove from database
 	if err := s.db.DeleteMediaFileByID(fileID); err != nil {
@@ -105,6 +137,7 @@ func (s *CleanupService) DeleteDuplicateFiles(groupID string, keepFileID int64)
 	}
 
 	// Delete all files except the one to keep
+	batch := database.NewJournalBatch(database.OpSourceDuplicate)
 	var deletedCount int
 	var reclaimedBytes int64
 
@@ -113,7 +146,7 @@ func (s *CleanupService) DeleteDuplicateFiles(groupID string, keepFileID int64)
 			continue // Keep this file
 		}
 
-		if err := s.DeleteFileByID(file.ID); err != nil {
+		if err := s.deleteFile(file.ID, batch); err != nil {
 			// Continue with other files even if one fails
 			continue
 		}
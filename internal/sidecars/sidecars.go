@@ -0,0 +1,304 @@
+// Package sidecars finds the metadata that belongs to a video — NFO
+// files, artwork and trickplay folders — and moves it along when the
+// video moves, renamed after the video's new name. Subtitles are handled
+// by package subtitles.
+package sidecars
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+	"github.com/Nomadcxx/jellywatch/internal/video"
+)
+
+// imageExts are the artwork extensions Jellyfin reads.
+var imageExts = map[string]bool{
+	".jpg": true, ".jpeg": true, ".png": true, ".webp": true, ".tbn": true,
+}
+
+// artKinds are the image types Jellyfin reads as "<stem>-<kind>.<ext>"
+// beside a video, or as "<kind>.<ext>" in a movie's folder.
+var artKinds = map[string]bool{
+	"thumb": true, "poster": true, "folder": true, "cover": true,
+	"fanart": true, "backdrop": true, "background": true, "banner": true,
+	"logo": true, "clearlogo": true, "clearart": true, "landscape": true,
+	"disc": true, "discart": true,
+}
+
+// trickplaySuffix names the folder of seek previews Jellyfin generates
+// beside a video.
+const trickplaySuffix = ".trickplay"
+
+// Sidecar is a metadata file or folder belonging to a video.
+type Sidecar struct {
+	Path string
+	// Suffix is what follows the video's stem in the name: ".nfo",
+	// "-thumb.jpg", ".trickplay". It is empty for folder-level artwork
+	// ("poster.jpg", "movie.nfo"), which keeps its name.
+	Suffix string
+	Dir    bool
+}
+
+// Find returns the sidecars of videoPath: files and trickplay folders
+// beside it named after the video ("Movie (2020).nfo",
+// "Movie (2020)-thumb.jpg"), and, when it is the only video in its
+// folder apart from extras, the folder's artwork and movie.nfo. NFO files
+// count only when they hold XML metadata, so a release's scene NFO is
+// never mistaken for one. Results are sorted by path.
+func Find(videoPath string) []Sidecar {
+	dir := filepath.Dir(videoPath)
+	stem := stemOf(filepath.Base(videoPath))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var out, folderArt []Sidecar
+	videos := 0
+	for _, e := range entries {
+		name := e.Name()
+		path := filepath.Join(dir, name)
+		if !e.IsDir() && video.IsVideo(name) {
+			if !naming.IsExtra(path) {
+				videos++
+			}
+			continue
+		}
+		if suffix, ok := stemSuffix(name, stem, e.IsDir()); ok {
+			if suffix == ".nfo" && !isMetadataNFO(path) {
+				continue
+			}
+			out = append(out, Sidecar{Path: path, Suffix: suffix, Dir: e.IsDir()})
+			continue
+		}
+		if !e.IsDir() && isFolderArt(name) {
+			if strings.EqualFold(name, "movie.nfo") && !isMetadataNFO(path) {
+				continue
+			}
+			folderArt = append(folderArt, Sidecar{Path: path})
+		}
+	}
+	if videos == 1 {
+		out = append(out, folderArt...)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
+
+// stemSuffix reports what follows stem in a sidecar named name, when
+// name is one.
+func stemSuffix(name, stem string, dir bool) (string, bool) {
+	if len(name) <= len(stem) || !strings.EqualFold(name[:len(stem)], stem) {
+		return "", false
+	}
+	rest := name[len(stem):]
+	if dir {
+		return rest, strings.EqualFold(rest, trickplaySuffix)
+	}
+	ext := strings.ToLower(filepath.Ext(rest))
+	switch {
+	case rest == ext:
+		return rest, ext == ".nfo" || imageExts[ext]
+	case strings.HasPrefix(rest, "-") && imageExts[ext]:
+		return rest, artKinds[strings.ToLower(strings.TrimSuffix(rest[1:], filepath.Ext(rest)))]
+	}
+	return "", false
+}
+
+// isFolderArt reports whether name is folder-level movie metadata.
+func isFolderArt(name string) bool {
+	if strings.EqualFold(name, "movie.nfo") {
+		return true
+	}
+	ext := strings.ToLower(filepath.Ext(name))
+	return imageExts[ext] && artKinds[strings.ToLower(stemOf(name))]
+}
+
+// isMetadataNFO reports whether the NFO at path is XML, as Jellyfin,
+// Kodi and the *arr apps write them, rather than a release's text notes.
+func isMetadataNFO(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	head := make([]byte, 512)
+	n, _ := f.Read(head)
+	head = bytes.TrimPrefix(head[:n], []byte("\xef\xbb\xbf"))
+	return bytes.HasPrefix(bytes.TrimSpace(head), []byte("<"))
+}
+
+// IsStemSidecar reports whether path looks like a sidecar named after a
+// video — an XML NFO, a "-thumb" image or a trickplay folder — that
+// could be orphaned when the video is renamed. Folder-level names such as
+// movie.nfo, tvshow.nfo and poster.jpg are not.
+func IsStemSidecar(path string, dir bool) bool {
+	name := filepath.Base(path)
+	lower := strings.ToLower(name)
+	if dir {
+		return strings.HasSuffix(lower, trickplaySuffix) && len(lower) > len(trickplaySuffix)
+	}
+	switch lower {
+	case "movie.nfo", "tvshow.nfo", "season.nfo":
+		return false
+	}
+	if strings.HasSuffix(lower, ".nfo") {
+		return isMetadataNFO(path)
+	}
+	stem := stemOf(lower)
+	return imageExts[filepath.Ext(lower)] && strings.HasSuffix(stem, "-thumb") && len(stem) > len("-thumb")
+}
+
+// StemOf returns the video stem a sidecar named by IsStemSidecar
+// belongs to.
+func StemOf(path string, dir bool) string {
+	name := filepath.Base(path)
+	if dir {
+		return name[:len(name)-len(trickplaySuffix)]
+	}
+	stem := stemOf(name)
+	if strings.EqualFold(filepath.Ext(name), ".nfo") {
+		return stem
+	}
+	return stem[:len(stem)-len("-thumb")]
+}
+
+// IsMetadata reports whether path is a metadata file: an NFO, artwork,
+// or part of a trickplay folder.
+func IsMetadata(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".nfo" || imageExts[ext] {
+		return true
+	}
+	for _, part := range strings.Split(filepath.Dir(path), string(filepath.Separator)) {
+		if strings.HasSuffix(strings.ToLower(part), trickplaySuffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Transfer pairs a sidecar with its destination path.
+type Transfer struct {
+	Source string
+	Target string
+	Dir    bool
+}
+
+// Plan names every sidecar for targetVideo: stem sidecars after its stem,
+// folder artwork under its own name, all in targetVideo's folder.
+func Plan(targetVideo string, cars []Sidecar) []Transfer {
+	dir := filepath.Dir(targetVideo)
+	stem := stemOf(filepath.Base(targetVideo))
+	out := make([]Transfer, 0, len(cars))
+	for _, c := range cars {
+		name := filepath.Base(c.Path)
+		if c.Suffix != "" {
+			name = stem + c.Suffix
+		}
+		out = append(out, Transfer{Source: c.Path, Target: filepath.Join(dir, name), Dir: c.Dir})
+	}
+	return out
+}
+
+// MoveWith moves the video at src to dst with move and brings its
+// sidecars along, renamed after dst. move is only ever given files;
+// trickplay folders are moved file by file. Sidecars whose new name is
+// already taken stay where they are. If a sidecar cannot follow, what was
+// moved goes back and the error is returned, so a video never ends up
+// apart from its metadata. It returns the sidecar moves made.
+func MoveWith(src, dst string, move func(from, to string) error) ([]Transfer, error) {
+	var plan []Transfer
+	for _, t := range Plan(dst, Find(src)) {
+		if t.Source == t.Target {
+			continue
+		}
+		if _, err := os.Stat(t.Target); err == nil {
+			continue
+		}
+		plan = append(plan, t)
+	}
+
+	if err := move(src, dst); err != nil {
+		return nil, err
+	}
+	var done []Transfer
+	for _, t := range plan {
+		if err := Carry(t, move); err != nil {
+			if t.Dir {
+				// Part of the folder may have made it across.
+				_ = Carry(t.reverse(), move)
+			}
+			for i := len(done) - 1; i >= 0; i-- {
+				_ = Carry(done[i].reverse(), move)
+			}
+			if rerr := move(dst, src); rerr != nil {
+				return nil, fmt.Errorf("move sidecar %s: %w (and moving %s back failed: %v)", t.Source, err, dst, rerr)
+			}
+			return nil, fmt.Errorf("move sidecar %s: %w", t.Source, err)
+		}
+		done = append(done, t)
+	}
+	return done, nil
+}
+
+func (t Transfer) reverse() Transfer {
+	return Transfer{Source: t.Target, Target: t.Source, Dir: t.Dir}
+}
+
+// Carry applies move, or a copy, to one planned sidecar. A trickplay
+// folder is carried file by file and its source folders are removed once
+// they are empty.
+func Carry(t Transfer, move func(from, to string) error) error {
+	if !t.Dir {
+		return move(t.Source, t.Target)
+	}
+	err := filepath.WalkDir(t.Source, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(t.Source, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(t.Target, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		return move(path, target)
+	})
+	if err != nil {
+		return err
+	}
+	return removeEmptyTree(t.Source)
+}
+
+// removeEmptyTree removes root and those of its subfolders that hold no
+// files; a copied folder stays as it is.
+func removeEmptyTree(root string) error {
+	var dirs []string
+	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if entries, err := os.ReadDir(dirs[i]); err != nil || len(entries) > 0 {
+			continue
+		}
+		if err := os.Remove(dirs[i]); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func stemOf(name string) string {
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
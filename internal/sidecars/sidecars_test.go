@@ -0,0 +1,171 @@
+package sidecars
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const xmlNFO = "<?xml version=\"1.0\"?>\n<movie><title>Heat</title></movie>\n"
+
+func write(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func names(cars []Sidecar) []string {
+	var out []string
+	for _, c := range cars {
+		out = append(out, filepath.Base(c.Path))
+	}
+	return out
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	video := filepath.Join(dir, "Heat (1995).mkv")
+	write(t, video, "video")
+	write(t, filepath.Join(dir, "Heat (1995).nfo"), xmlNFO)
+	write(t, filepath.Join(dir, "Heat (1995)-thumb.jpg"), "img")
+	write(t, filepath.Join(dir, "Heat (1995).trickplay", "320 - 10x10", "0.jpg"), "img")
+	write(t, filepath.Join(dir, "poster.jpg"), "img")
+	write(t, filepath.Join(dir, "movie.nfo"), xmlNFO)
+	write(t, filepath.Join(dir, "Heat (1995)-trailer.mkv"), "extra")
+	write(t, filepath.Join(dir, "Heat (1995).eng.srt"), "subs")
+	write(t, filepath.Join(dir, "Heat (1995)-notes.jpg"), "img")
+
+	want := []string{"Heat (1995)-thumb.jpg", "Heat (1995).nfo", "Heat (1995).trickplay", "movie.nfo", "poster.jpg"}
+	if got := names(Find(video)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Find() = %v, want %v", got, want)
+	}
+
+	// With a second video the folder's artwork belongs to neither.
+	other := filepath.Join(dir, "Heat (1995) - 4K.mkv")
+	write(t, other, "video")
+	want = []string{"Heat (1995)-thumb.jpg", "Heat (1995).nfo", "Heat (1995).trickplay"}
+	if got := names(Find(video)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Find() with two videos = %v, want %v", got, want)
+	}
+}
+
+func TestFindSkipsSceneNFO(t *testing.T) {
+	dir := t.TempDir()
+	video := filepath.Join(dir, "Heat.1995.1080p.BluRay-GRP.mkv")
+	write(t, video, "video")
+	write(t, filepath.Join(dir, "Heat.1995.1080p.BluRay-GRP.nfo"), "  ___ GRP PRESENTS ___\n")
+	if got := Find(video); len(got) != 0 {
+		t.Errorf("Find() = %v, want no sidecars", names(got))
+	}
+}
+
+func TestMoveWith(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "Heat", "Heat.mkv")
+	dst := filepath.Join(root, "Heat (1995)", "Heat (1995).mkv")
+	write(t, src, "video")
+	write(t, filepath.Join(root, "Heat", "Heat.nfo"), xmlNFO)
+	write(t, filepath.Join(root, "Heat", "Heat.trickplay", "320 - 10x10", "0.jpg"), "img")
+	write(t, filepath.Join(root, "Heat", "fanart.jpg"), "img")
+	write(t, filepath.Join(root, "Heat (1995)", "fanart.jpg"), "kept")
+
+	rename := func(from, to string) error {
+		if err := os.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+			return err
+		}
+		return os.Rename(from, to)
+	}
+	moved, err := MoveWith(src, dst, rename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(moved) != 2 {
+		t.Errorf("moved %d sidecars, want 2: %+v", len(moved), moved)
+	}
+	for _, p := range []string{
+		dst,
+		filepath.Join(root, "Heat (1995)", "Heat (1995).nfo"),
+		filepath.Join(root, "Heat (1995)", "Heat (1995).trickplay", "320 - 10x10", "0.jpg"),
+		filepath.Join(root, "Heat", "fanart.jpg"),
+	} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("missing %s", p)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "Heat", "Heat.trickplay")); !os.IsNotExist(err) {
+		t.Error("source trickplay folder left behind")
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "Heat (1995)", "fanart.jpg")); string(data) != "kept" {
+		t.Error("existing artwork was overwritten")
+	}
+}
+
+func TestMoveWithRollsBack(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "Heat", "Heat.mkv")
+	dst := filepath.Join(root, "Heat (1995)", "Heat (1995).mkv")
+	write(t, src, "video")
+	write(t, filepath.Join(root, "Heat", "Heat-thumb.jpg"), "img")
+	write(t, filepath.Join(root, "Heat", "Heat.nfo"), xmlNFO)
+
+	rename := func(from, to string) error {
+		if strings.HasSuffix(to, ".nfo") {
+			return errors.New("disk full")
+		}
+		if err := os.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+			return err
+		}
+		return os.Rename(from, to)
+	}
+	if _, err := MoveWith(src, dst, rename); err == nil {
+		t.Fatal("MoveWith succeeded with a failing sidecar")
+	}
+	for _, p := range []string{src, filepath.Join(root, "Heat", "Heat-thumb.jpg"), filepath.Join(root, "Heat", "Heat.nfo")} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("%s not restored", p)
+		}
+	}
+	if _, err := os.Stat(dst); !os.IsNotExist(err) {
+		t.Error("video left at its destination")
+	}
+}
+
+func TestIsStemSidecar(t *testing.T) {
+	dir := t.TempDir()
+	write(t, filepath.Join(dir, "Old Name (2020).nfo"), xmlNFO)
+	write(t, filepath.Join(dir, "tvshow.nfo"), xmlNFO)
+	write(t, filepath.Join(dir, "release.nfo"), "scene notes")
+
+	for _, tc := range []struct {
+		name string
+		dir  bool
+		want bool
+		stem string
+	}{
+		{"Old Name (2020).nfo", false, true, "Old Name (2020)"},
+		{"Old Name (2020)-thumb.jpg", false, true, "Old Name (2020)"},
+		{"Old Name (2020).trickplay", true, true, "Old Name (2020)"},
+		{"tvshow.nfo", false, false, ""},
+		{"release.nfo", false, false, ""},
+		{"poster.jpg", false, false, ""},
+		{"-thumb.jpg", false, false, ""},
+	} {
+		path := filepath.Join(dir, tc.name)
+		if got := IsStemSidecar(path, tc.dir); got != tc.want {
+			t.Errorf("IsStemSidecar(%q) = %v, want %v", tc.name, got, tc.want)
+			continue
+		}
+		if tc.want {
+			if got := StemOf(path, tc.dir); got != tc.stem {
+				t.Errorf("StemOf(%q) = %q, want %q", tc.name, got, tc.stem)
+			}
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package sonarr
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// absoluteCacheTTL bounds how long series and episode lists are reused.
+// Anime seasons gain episodes weekly, so a short TTL is enough to pick up
+// new absolute numbers without hitting Sonarr for every file.
+const absoluteCacheTTL = 10 * time.Minute
+
+// AbsoluteResolver maps anime absolute episode numbers to the season and
+// episode Sonarr files them under, using the absoluteEpisodeNumber exposed
+// by GetEpisodes.
+type AbsoluteResolver struct {
+	client *Client
+	ttl    time.Duration
+
+	mu       sync.Mutex
+	series   []Series
+	seriesAt time.Time
+	episodes map[int]cachedEpisodes
+}
+
+type cachedEpisodes struct {
+	episodes []Episode
+	at       time.Time
+}
+
+// NewAbsoluteResolver returns a resolver backed by client.
+func NewAbsoluteResolver(client *Client) *AbsoluteResolver {
+	return &AbsoluteResolver{
+		client:   client,
+		ttl:      absoluteCacheTTL,
+		episodes: make(map[int]cachedEpisodes),
+	}
+}
+
+// ResolveAbsolute returns the season/episode for absolute episode number
+// absolute of the series called title.
+func (r *AbsoluteResolver) ResolveAbsolute(title string, absolute int) (season, episode int, err error) {
+	if absolute <= 0 {
+		return 0, 0, fmt.Errorf("invalid absolute episode %d", absolute)
+	}
+	series, err := r.findSeries(title)
+	if err != nil {
+		return 0, 0, err
+	}
+	episodes, err := r.seriesEpisodes(series.ID)
+	if err != nil {
+		return 0, 0, err
+	}
+	ep, ok := EpisodeForAbsolute(episodes, absolute)
+	if !ok {
+		return 0, 0, fmt.Errorf("absolute episode %d not found for series %q", absolute, series.Title)
+	}
+	return ep.SeasonNumber, ep.EpisodeNumber, nil
+}
+
+// EpisodeTitle returns Sonarr's title for season/episode of the series
+// called title, reusing the resolver's series and episode caches.
+func (r *AbsoluteResolver) EpisodeTitle(title string, season, episode int) (string, error) {
+	series, err := r.findSeries(title)
+	if err != nil {
+		return "", err
+	}
+	episodes, err := r.seriesEpisodes(series.ID)
+	if err != nil {
+		return "", err
+	}
+	for _, ep := range episodes {
+		if ep.SeasonNumber == season && ep.EpisodeNumber == episode {
+			return ep.Title, nil
+		}
+	}
+	return "", fmt.Errorf("S%02dE%02d not found for series %q", season, episode, series.Title)
+}
+
+// EpisodeForAbsolute returns the regular (non-special) episode whose
+// absoluteEpisodeNumber equals absolute.
+func EpisodeForAbsolute(episodes []Episode, absolute int) (*Episode, bool) {
+	for i := range episodes {
+		ep := &episodes[i]
+		if ep.SeasonNumber > 0 && ep.AbsoluteEpisodeNumber != nil && *ep.AbsoluteEpisodeNumber == absolute {
+			return ep, true
+		}
+	}
+	return nil, false
+}
+
+// findSeries matches title against Sonarr's series by normalized title,
+// sort title or slug, preferring series typed as anime on ties.
+func (r *AbsoluteResolver) findSeries(title string) (*Series, error) {
+	all, err := r.allSeries()
+	if err != nil {
+		return nil, err
+	}
+	want := normalizeSeriesTitle(title)
+	if want == "" {
+		return nil, fmt.Errorf("empty series title")
+	}
+
+	var best *Series
+	for i := range all {
+		s := &all[i]
+		if normalizeSeriesTitle(s.Title) != want &&
+			normalizeSeriesTitle(s.SortTitle) != want &&
+			normalizeSeriesTitle(s.TitleSlug) != want {
+			continue
+		}
+		if best == nil || (s.SeriesType == "anime" && best.SeriesType != "anime") {
+			best = s
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("no Sonarr series matches %q", title)
+	}
+	return best, nil
+}
+
+func (r *AbsoluteResolver) allSeries() ([]Series, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.series != nil && time.Since(r.seriesAt) < r.ttl {
+		return r.series, nil
+	}
+	series, err := r.client.GetAllSeries()
+	if err != nil {
+		return nil, err
+	}
+	r.series, r.seriesAt = series, time.Now()
+	return series, nil
+}
+
+func (r *AbsoluteResolver) seriesEpisodes(seriesID int) ([]Episode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c, ok := r.episodes[seriesID]; ok && time.Since(c.at) < r.ttl {
+		return c.episodes, nil
+	}
+	episodes, err := r.client.GetEpisodes(seriesID)
+	if err != nil {
+		return nil, err
+	}
+	r.episodes[seriesID] = cachedEpisodes{episodes: episodes, at: time.Now()}
+	return episodes, nil
+}
+
+// normalizeSeriesTitle lower-cases s and drops everything but letters and
+// digits, so "Oshi no Ko", "oshi-no-ko" and "Oshi.no.Ko" compare equal.
+func normalizeSeriesTitle(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
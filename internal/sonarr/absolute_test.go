@@ -0,0 +1,73 @@
+package sonarr
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(n int) *int { return &n }
+
+func TestAbsoluteResolver_ResolveAbsolute(t *testing.T) {
+	episodeCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/series":
+			json.NewEncoder(w).Encode([]Series{
+				{ID: 1, Title: "Frieren: Beyond Journey's End", SortTitle: "frieren beyond journeys end", TitleSlug: "frieren", SeriesType: "anime"},
+				{ID: 2, Title: "One Piece", TitleSlug: "one-piece", SeriesType: "standard"},
+				{ID: 3, Title: "One Piece", TitleSlug: "one-piece-anime", SeriesType: "anime"},
+			})
+		case "/api/v3/episode":
+			episodeCalls++
+			switch r.URL.Query().Get("seriesId") {
+			case "1":
+				json.NewEncoder(w).Encode([]Episode{
+					{SeasonNumber: 0, EpisodeNumber: 1, AbsoluteEpisodeNumber: intPtr(29)},
+					{SeasonNumber: 1, EpisodeNumber: 28, AbsoluteEpisodeNumber: intPtr(28)},
+					{SeasonNumber: 2, EpisodeNumber: 1, AbsoluteEpisodeNumber: intPtr(29), Title: "The Journey Resumes"},
+				})
+			case "3":
+				json.NewEncoder(w).Encode([]Episode{
+					{SeasonNumber: 21, EpisodeNumber: 179, AbsoluteEpisodeNumber: intPtr(1071)},
+				})
+			default:
+				json.NewEncoder(w).Encode([]Episode{})
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	r := NewAbsoluteResolver(NewClient(Config{URL: server.URL, APIKey: "test"}))
+
+	season, episode, err := r.ResolveAbsolute("frieren", 29)
+	require.NoError(t, err)
+	assert.Equal(t, 2, season, "specials sharing the absolute number are ignored")
+	assert.Equal(t, 1, episode)
+
+	season, episode, err = r.ResolveAbsolute("One Piece", 1071)
+	require.NoError(t, err)
+	assert.Equal(t, 21, season, "anime-typed series wins a title tie")
+	assert.Equal(t, 179, episode)
+
+	_, _, err = r.ResolveAbsolute("frieren", 28)
+	require.NoError(t, err)
+	assert.Equal(t, 2, episodeCalls, "episode lists are cached per series")
+
+	title, err := r.EpisodeTitle("frieren", 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "The Journey Resumes", title)
+	assert.Equal(t, 2, episodeCalls, "episode titles share the episode cache")
+
+	_, _, err = r.ResolveAbsolute("frieren", 500)
+	assert.Error(t, err)
+	_, _, err = r.ResolveAbsolute("Unknown Show", 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,56 @@
+package sonarr
+
+// WebhookEvent is the body of a Sonarr webhook connection notification.
+// Only the fields JellyWatch uses to identify a download are decoded.
+type WebhookEvent struct {
+	EventType      string              `json:"eventType"`
+	Series         WebhookSeries       `json:"series"`
+	Episodes       []WebhookEpisode    `json:"episodes"`
+	Release        WebhookRelease      `json:"release"`
+	EpisodeFile    *WebhookEpisodeFile `json:"episodeFile,omitempty"`
+	DownloadClient string              `json:"downloadClient,omitempty"`
+	DownloadID     string              `json:"downloadId,omitempty"`
+}
+
+// WebhookSeries is the series a webhook event is about.
+type WebhookSeries struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	Year   int    `json:"year"`
+	Path   string `json:"path"`
+	TvdbID int    `json:"tvdbId"`
+	TmdbID int    `json:"tmdbId"`
+	ImdbID string `json:"imdbId"`
+}
+
+// WebhookEpisode is one episode a grab or import covers.
+type WebhookEpisode struct {
+	ID            int    `json:"id"`
+	SeasonNumber  int    `json:"seasonNumber"`
+	EpisodeNumber int    `json:"episodeNumber"`
+	Title         string `json:"title"`
+}
+
+// WebhookRelease is the release a Grab event sent to the download client.
+type WebhookRelease struct {
+	ReleaseTitle string `json:"releaseTitle"`
+	Indexer      string `json:"indexer"`
+	Size         int64  `json:"size"`
+}
+
+// WebhookEpisodeFile is the file a Download event imported. SourcePath is
+// where it was in the download client's folder.
+type WebhookEpisodeFile struct {
+	ID           int    `json:"id"`
+	RelativePath string `json:"relativePath"`
+	Path         string `json:"path"`
+	SceneName    string `json:"sceneName"`
+	SourcePath   string `json:"sourcePath"`
+}
+
+// Webhook event types. Download is sent "On Import" and "On Upgrade".
+const (
+	EventGrab     = "Grab"
+	EventDownload = "Download"
+	EventTest     = "Test"
+)
@@ -0,0 +1,55 @@
+package subtitles
+
+// languages maps English language names, ISO 639-1 codes and both ISO
+// 639-2 forms to the ISO 639-2/B code. "hi" is left out: in subtitle names
+// it means hearing impaired far more often than Hindi.
+var languages = map[string]string{}
+
+func init() {
+	for code, aliases := range map[string][]string{
+		"ara": {"ar", "arabic"},
+		"bul": {"bg", "bulgarian"},
+		"chi": {"zh", "zho", "chs", "cht", "chinese", "mandarin", "cantonese"},
+		"cze": {"cs", "ces", "czech"},
+		"dan": {"da", "danish"},
+		"dut": {"nl", "nld", "dutch", "flemish"},
+		"eng": {"en", "english"},
+		"est": {"et", "estonian"},
+		"fin": {"fi", "finnish"},
+		"fre": {"fr", "fra", "french"},
+		"ger": {"de", "deu", "german"},
+		"gre": {"el", "ell", "greek"},
+		"heb": {"he", "hebrew"},
+		"hin": {"hindi"},
+		"hrv": {"hr", "croatian"},
+		"hun": {"hu", "hungarian"},
+		"ice": {"is", "isl", "icelandic"},
+		"ind": {"indonesian"},
+		"ita": {"it", "italian"},
+		"jpn": {"ja", "japanese"},
+		"kor": {"ko", "korean"},
+		"lav": {"lv", "latvian"},
+		"lit": {"lt", "lithuanian"},
+		"may": {"ms", "msa", "malay"},
+		"nor": {"no", "nb", "nob", "norwegian", "bokmal"},
+		"per": {"fa", "fas", "persian", "farsi"},
+		"pol": {"pl", "polish"},
+		"por": {"pt", "portuguese", "brazilian"},
+		"rum": {"ro", "ron", "romanian"},
+		"rus": {"ru", "russian"},
+		"slo": {"sk", "slk", "slovak"},
+		"slv": {"sl", "slovenian", "slovene"},
+		"spa": {"es", "esp", "spanish", "castilian", "latino"},
+		"srp": {"sr", "serbian"},
+		"swe": {"sv", "swedish"},
+		"tha": {"th", "thai"},
+		"tur": {"tr", "turkish"},
+		"ukr": {"uk", "ukrainian"},
+		"vie": {"vi", "vietnamese"},
+	} {
+		languages[code] = code
+		for _, alias := range aliases {
+			languages[alias] = code
+		}
+	}
+}
@@ -0,0 +1,218 @@
+// Package subtitles finds the subtitle sidecars that belong to a video and
+// names them so Jellyfin associates them with it:
+// <video stem>.<language>[.forced][.sdh].<ext>.
+package subtitles
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Nomadcxx/jellywatch/internal/video"
+)
+
+// Extensions lists the subtitle file extensions jellywatch carries along
+// with videos.
+var Extensions = map[string]bool{
+	".srt": true, ".sub": true, ".idx": true, ".ass": true,
+	".ssa": true, ".vtt": true, ".smi": true,
+}
+
+// IsSubtitle reports whether path has a subtitle extension.
+func IsSubtitle(path string) bool {
+	return Extensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// subsDirs are the folder names releases keep subtitles in.
+var subsDirs = map[string]bool{"subs": true, "sub": true, "subtitles": true}
+
+// Sidecar is a subtitle file belonging to a video.
+type Sidecar struct {
+	Path     string
+	Language string // ISO 639-2/B code ("eng"), "" when the name has none
+	Forced   bool
+	SDH      bool
+}
+
+// Find returns the subtitles for videoPath: files beside it named after the
+// video ("Movie.2001.eng.srt" for "Movie.2001.mkv"), files in a
+// Subs/<video stem>/ folder (episode packs), and files directly in Subs/
+// when the video is the only one in its folder (single releases with
+// "2_English.srt"-style names). Results are sorted by path.
+func Find(videoPath string) []Sidecar {
+	dir := filepath.Dir(videoPath)
+	stem := stemOf(filepath.Base(videoPath))
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var out []Sidecar
+	videos := 0
+	var subsDir string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() {
+			if subsDirs[strings.ToLower(name)] {
+				subsDir = filepath.Join(dir, name)
+			}
+			continue
+		}
+		if video.IsVideo(name) {
+			videos++
+			continue
+		}
+		if !IsSubtitle(name) {
+			continue
+		}
+		if tags, ok := tagsAfterStem(stemOf(name), stem); ok {
+			out = append(out, newSidecar(filepath.Join(dir, name), tags))
+		}
+	}
+
+	if subsDir != "" {
+		out = append(out, findInSubsDir(subsDir, stem, videos == 1)...)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
+
+func findInSubsDir(subsDir, stem string, soleVideo bool) []Sidecar {
+	entries, err := os.ReadDir(subsDir)
+	if err != nil {
+		return nil
+	}
+	var out []Sidecar
+	for _, e := range entries {
+		name := e.Name()
+		switch {
+		case e.IsDir() && strings.EqualFold(name, stem):
+			inner, err := os.ReadDir(filepath.Join(subsDir, name))
+			if err != nil {
+				continue
+			}
+			for _, f := range inner {
+				if !f.IsDir() && IsSubtitle(f.Name()) {
+					out = append(out, newSidecar(filepath.Join(subsDir, name, f.Name()), stemOf(f.Name())))
+				}
+			}
+		case e.IsDir() || !IsSubtitle(name):
+		case soleVideo:
+			out = append(out, newSidecar(filepath.Join(subsDir, name), stemOf(name)))
+		default:
+			// Several videos share the folder: only take files named
+			// after this one.
+			if tags, ok := tagsAfterStem(stemOf(name), stem); ok {
+				out = append(out, newSidecar(filepath.Join(subsDir, name), tags))
+			}
+		}
+	}
+	return out
+}
+
+// tagsAfterStem reports whether subStem is videoStem optionally followed by
+// "."- or "_"-separated tags, and returns the tags.
+func tagsAfterStem(subStem, videoStem string) (string, bool) {
+	if len(subStem) < len(videoStem) || !strings.EqualFold(subStem[:len(videoStem)], videoStem) {
+		return "", false
+	}
+	rest := subStem[len(videoStem):]
+	if rest == "" {
+		return "", true
+	}
+	if rest[0] != '.' && rest[0] != '_' {
+		return "", false
+	}
+	return rest[1:], true
+}
+
+func newSidecar(path, tags string) Sidecar {
+	s := Sidecar{Path: path}
+	for _, token := range tokenize(tags) {
+		switch token {
+		case "forced", "foreign":
+			s.Forced = true
+		case "sdh", "hi", "cc", "hearing", "impaired":
+			s.SDH = true
+		default:
+			if s.Language == "" {
+				s.Language = Language(token)
+			}
+		}
+	}
+	return s
+}
+
+// tokenize splits a name into lower-case words and numbers.
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+}
+
+// Language normalises a language name or ISO 639-1/639-2 code to its ISO
+// 639-2/B code, the form Jellyfin reads from sidecar names. It returns ""
+// for anything it doesn't recognise.
+func Language(token string) string {
+	return languages[strings.ToLower(strings.TrimSpace(token))]
+}
+
+// Name returns the Jellyfin sidecar name for s beside targetVideo.
+func Name(targetVideo string, s Sidecar) string {
+	return buildName(targetVideo, s, 0)
+}
+
+func buildName(targetVideo string, s Sidecar, n int) string {
+	parts := []string{stemOf(filepath.Base(targetVideo))}
+	if s.Language != "" {
+		parts = append(parts, s.Language)
+	}
+	if n > 1 {
+		parts = append(parts, strconv.Itoa(n))
+	}
+	if s.Forced {
+		parts = append(parts, "forced")
+	}
+	if s.SDH {
+		parts = append(parts, "sdh")
+	}
+	return strings.Join(parts, ".") + strings.ToLower(filepath.Ext(s.Path))
+}
+
+// Transfer pairs a sidecar with its destination path.
+type Transfer struct {
+	Source string
+	Target string
+}
+
+// Plan names every sidecar for targetVideo. Sidecars that would share a
+// name (two English tracks) are numbered: "Movie.eng.srt",
+// "Movie.eng.2.srt". An .idx and its .sub keep the same number.
+func Plan(targetVideo string, subs []Sidecar) []Transfer {
+	dir := filepath.Dir(targetVideo)
+	used := make(map[string]bool)
+	numbers := make(map[string]int) // source path without extension -> number
+	out := make([]Transfer, 0, len(subs))
+	for _, s := range subs {
+		key := strings.TrimSuffix(s.Path, filepath.Ext(s.Path))
+		n, paired := numbers[key]
+		name := buildName(targetVideo, s, n)
+		for !paired && used[strings.ToLower(name)] {
+			if n == 0 {
+				n = 1
+			}
+			n++
+			name = buildName(targetVideo, s, n)
+		}
+		numbers[key] = n
+		used[strings.ToLower(name)] = true
+		out = append(out, Transfer{Source: s.Path, Target: filepath.Join(dir, name)})
+	}
+	return out
+}
+
+func stemOf(name string) string {
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
@@ -0,0 +1,114 @@
+package subtitles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func touch(t *testing.T, paths ...string) {
+	t.Helper()
+	for _, p := range paths {
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	for in, want := range map[string]string{
+		"English": "eng", "en": "eng", "eng": "eng",
+		"fra": "fre", "fr": "fre", "deu": "ger", "pt": "por",
+		"hi": "", "Klingon": "", "": "",
+	} {
+		if got := Language(in); got != want {
+			t.Errorf("Language(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFindBesideVideo(t *testing.T) {
+	dir := t.TempDir()
+	video := filepath.Join(dir, "Movie.2001.1080p.mkv")
+	touch(t, video,
+		filepath.Join(dir, "Movie.2001.1080p.eng.srt"),
+		filepath.Join(dir, "Movie.2001.1080p_Spanish.Forced.srt"),
+		filepath.Join(dir, "Movie.2001.1080p.English.SDH.srt"),
+		filepath.Join(dir, "Movie.2001.1080pExtra.srt"),
+		filepath.Join(dir, "Other.Movie.srt"),
+		filepath.Join(dir, "Movie.2001.1080p.nfo"),
+	)
+
+	got := Find(video)
+	want := []Sidecar{
+		{Path: filepath.Join(dir, "Movie.2001.1080p.English.SDH.srt"), Language: "eng", SDH: true},
+		{Path: filepath.Join(dir, "Movie.2001.1080p.eng.srt"), Language: "eng"},
+		{Path: filepath.Join(dir, "Movie.2001.1080p_Spanish.Forced.srt"), Language: "spa", Forced: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Find() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Find()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestFindSubsFolder(t *testing.T) {
+	t.Run("single release", func(t *testing.T) {
+		dir := t.TempDir()
+		video := filepath.Join(dir, "Movie.2001.1080p.BluRay.x264-GRP.mkv")
+		touch(t, video,
+			filepath.Join(dir, "Subs", "2_English.srt"),
+			filepath.Join(dir, "Subs", "3_French.srt"),
+			filepath.Join(dir, "Subs", "readme.txt"),
+		)
+		got := Find(video)
+		if len(got) != 2 || got[0].Language != "eng" || got[1].Language != "fre" {
+			t.Fatalf("Find() = %+v, want English and French from Subs/", got)
+		}
+	})
+
+	t.Run("episode pack", func(t *testing.T) {
+		dir := t.TempDir()
+		ep1 := filepath.Join(dir, "Show.S01E01.720p.WEB.mkv")
+		ep2 := filepath.Join(dir, "Show.S01E02.720p.WEB.mkv")
+		touch(t, ep1, ep2,
+			filepath.Join(dir, "Subs", "Show.S01E01.720p.WEB", "2_English.srt"),
+			filepath.Join(dir, "Subs", "Show.S01E02.720p.WEB", "2_English.srt"),
+			filepath.Join(dir, "Subs", "stray.srt"),
+		)
+		got := Find(ep1)
+		if len(got) != 1 || got[0].Path != filepath.Join(dir, "Subs", "Show.S01E01.720p.WEB", "2_English.srt") {
+			t.Fatalf("Find(ep1) = %+v, want only its own Subs/ folder", got)
+		}
+	})
+}
+
+func TestPlan(t *testing.T) {
+	target := "/lib/Movie (2001)/Movie (2001).mkv"
+	subs := []Sidecar{
+		{Path: "/dl/Subs/2_English.srt", Language: "eng"},
+		{Path: "/dl/Subs/3_English.srt", Language: "eng"},
+		{Path: "/dl/Movie.idx", Language: "eng"},
+		{Path: "/dl/Movie.sub", Language: "eng"},
+		{Path: "/dl/Movie.forced.SRT", Forced: true},
+	}
+	want := []string{
+		"/lib/Movie (2001)/Movie (2001).eng.srt",
+		"/lib/Movie (2001)/Movie (2001).eng.2.srt",
+		"/lib/Movie (2001)/Movie (2001).eng.idx",
+		"/lib/Movie (2001)/Movie (2001).eng.sub",
+		"/lib/Movie (2001)/Movie (2001).forced.srt",
+	}
+	got := Plan(target, subs)
+	for i, tr := range got {
+		if tr.Source != subs[i].Path || tr.Target != want[i] {
+			t.Errorf("Plan()[%d] = %+v, want target %s", i, tr, want[i])
+		}
+	}
+}
@@ -43,6 +43,10 @@ func (s *SyncService) SyncFromFilesystem(ctx context.Context) (result *scanner.S
 	} else {
 		fileScanner = scanner.NewFileScanner(s.db)
 	}
+	fileScanner.SetLibraryTemplates(s.templates)
+	fileScanner.SetProber(s.prober)
+	fileScanner.SetQualityProfiles(s.profiles)
+	fileScanner.SetFilters(s.filters)
 
 	// Scan files into media_files table
 	s.logger.Info("scanning files into media_files table")
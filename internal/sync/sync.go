@@ -9,6 +9,11 @@ import (
 	"time"
 
 	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/ingest"
+	"github.com/Nomadcxx/jellywatch/internal/jellyfin"
+	"github.com/Nomadcxx/jellywatch/internal/naming"
+	"github.com/Nomadcxx/jellywatch/internal/probe"
+	"github.com/Nomadcxx/jellywatch/internal/quality"
 	"github.com/Nomadcxx/jellywatch/internal/radarr"
 	"github.com/Nomadcxx/jellywatch/internal/scanner"
 	"github.com/Nomadcxx/jellywatch/internal/sonarr"
@@ -19,10 +24,16 @@ type SyncService struct {
 	db             *database.MediaDB
 	sonarr         *sonarr.Client
 	radarr         *radarr.Client
+	jellyfin       *jellyfin.Client
+	jellyfinPaths  *jellyfin.PathTranslator
 	tvLibraries    []string
 	movieLibraries []string
 	logger         *slog.Logger
 	aiHelper       *scanner.AIHelper
+	templates      map[string]naming.Templates
+	prober         *probe.Prober
+	profiles       *quality.ProfileSet
+	filters        *ingest.Filters
 
 	syncHour int
 	stopCh   chan struct{}
@@ -43,11 +54,20 @@ type SyncConfig struct {
 	DB             *database.MediaDB
 	Sonarr         *sonarr.Client    // nil if not configured
 	Radarr         *radarr.Client    // nil if not configured
+	Jellyfin       *jellyfin.Client  // nil if not configured
+	// JellyfinPaths maps daemon paths to Jellyfin's view for path updates.
+	JellyfinPaths *jellyfin.PathTranslator
 	AIHelper       *scanner.AIHelper // Optional AI helper for auto-trigger
 	TVLibraries    []string
 	MovieLibraries []string
-	SyncHour       int // Hour for daily sync, default 3
-	Logger         *slog.Logger
+	// LibraryTemplates holds naming templates by library root; compliance
+	// checks in those libraries follow the templates.
+	LibraryTemplates map[string]naming.Templates
+	Prober           *probe.Prober       // Optional ffprobe inspection during scans
+	QualityProfiles  *quality.ProfileSet // Optional per-library scoring profiles
+	Filters          *ingest.Filters     // Optional watch-folder filters; nil uses ingest.Default
+	SyncHour         int                 // Hour for daily sync, default 3
+	Logger           *slog.Logger
 }
 
 // NewSyncService creates a new sync service
@@ -64,9 +84,15 @@ func NewSyncService(cfg SyncConfig) *SyncService {
 		db:             cfg.DB,
 		sonarr:         cfg.Sonarr,
 		radarr:         cfg.Radarr,
+		jellyfin:       cfg.Jellyfin,
+		jellyfinPaths:  cfg.JellyfinPaths,
 		aiHelper:       cfg.AIHelper,
 		tvLibraries:    cfg.TVLibraries,
 		movieLibraries: cfg.MovieLibraries,
+		templates:      cfg.LibraryTemplates,
+		prober:         cfg.Prober,
+		profiles:       cfg.QualityProfiles,
+		filters:        cfg.Filters,
 		syncHour:       cfg.SyncHour,
 		logger:         cfg.Logger,
 		stopCh:         make(chan struct{}),
@@ -106,6 +132,32 @@ func (s *SyncService) QueueSync(mediaType string, id int64) {
 	}
 }
 
+// NotifyPathChange tells Jellyfin a file moved from oldPath to newPath
+// outside the organize flow, so it drops the old item and indexes the new
+// one. Either path may be empty. A no-op without a Jellyfin client.
+func (s *SyncService) NotifyPathChange(oldPath, newPath string) error {
+	if s.jellyfin == nil {
+		return nil
+	}
+	var updates []jellyfin.MediaUpdate
+	if oldPath != "" {
+		updates = append(updates, jellyfin.MediaUpdate{Path: s.jellyfinPaths.DaemonToJellyfin(oldPath), UpdateType: "Deleted"})
+	}
+	if newPath != "" {
+		updates = append(updates, jellyfin.MediaUpdate{Path: s.jellyfinPaths.DaemonToJellyfin(newPath), UpdateType: "Created"})
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	return s.jellyfin.NotifyMediaUpdated(updates)
+}
+
+// SyncDirty pushes every dirty series and movie path to Sonarr/Radarr now
+// instead of waiting for the retry loop. Used by one-shot CLI commands.
+func (s *SyncService) SyncDirty(ctx context.Context) error {
+	return s.syncDirtyRecords(ctx)
+}
+
 // runScheduler runs the daily sync at the configured hour
 func (s *SyncService) runScheduler() {
 	for {
@@ -66,17 +66,31 @@ func (f *FallbackTransferer) tryAll(src, dst string, opts TransferOptions, isMov
 	var lastErr error
 	var errors []string
 
+	// Once a source-preserving backend (hardlink/reflink) is in the chain,
+	// the caller asked for the source to stay put. Later backends must copy
+	// rather than move, or a failed link would still rip the file out from
+	// under the download client.
+	preserve := false
+
 	for _, backend := range f.backends {
 		var result *TransferResult
 		var err error
 
-		if isMove {
+		if PreservesSource(backend) {
+			preserve = true
+		}
+
+		if isMove && !preserve {
 			result, err = backend.Move(src, dst, opts)
 		} else {
 			result, err = backend.Copy(src, dst, opts)
 		}
 
 		if err == nil && result.Success {
+			if isMove && preserve {
+				result.SourcePreserved = true
+				result.SourceRemoved = false
+			}
 			return result, nil
 		}
 
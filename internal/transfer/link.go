@@ -0,0 +1,211 @@
+package transfer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// LinkMode selects how a LinkTransferer materialises the destination.
+type LinkMode int
+
+const (
+	// LinkHardlink creates a second directory entry for the source inode.
+	// Only possible when source and destination share a filesystem.
+	LinkHardlink LinkMode = iota
+
+	// LinkReflink clones the source extents into a new inode (FICLONE).
+	// Only possible on copy-on-write filesystems such as btrfs and xfs.
+	LinkReflink
+)
+
+func (m LinkMode) String() string {
+	switch m {
+	case LinkHardlink:
+		return "hardlink"
+	case LinkReflink:
+		return "reflink"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrLinkUnsupported is returned by the low-level link helpers when the
+// filesystem pair cannot satisfy the requested link mode (cross-device,
+// no CoW support, link count exhausted, ...). LinkTransferer treats it as
+// the signal to fall back to a byte copy.
+var ErrLinkUnsupported = errors.New("link not supported for this source/destination")
+
+// LinkTransferer keeps the source file in place and produces the
+// destination as a hardlink or reflink, so torrents can keep seeding from
+// the download directory while Jellyfin reads the organized copy. When the
+// link cannot be made it copies with the fallback transferer instead.
+//
+// Move and Copy behave identically: the source is never removed. Results
+// always carry SourcePreserved=true so callers skip source cleanup.
+type LinkTransferer struct {
+	mode     LinkMode
+	fallback Transferer
+}
+
+// NewHardlinkTransferer returns a transferer that hardlinks when source and
+// destination share a device and copies with fallback otherwise.
+func NewHardlinkTransferer(fallback Transferer) *LinkTransferer {
+	return &LinkTransferer{mode: LinkHardlink, fallback: fallback}
+}
+
+// NewReflinkTransferer returns a transferer that reflinks on CoW
+// filesystems and copies with fallback otherwise.
+func NewReflinkTransferer(fallback Transferer) *LinkTransferer {
+	return &LinkTransferer{mode: LinkReflink, fallback: fallback}
+}
+
+func (l *LinkTransferer) Name() string {
+	if l.fallback == nil {
+		return l.mode.String()
+	}
+	return l.mode.String() + "+" + l.fallback.Name()
+}
+
+func (l *LinkTransferer) CanResume() bool {
+	return false
+}
+
+// PreservesSource reports that this backend never removes the source. The
+// FallbackTransferer uses it to downgrade later Move attempts to Copy.
+func (l *LinkTransferer) PreservesSource() bool {
+	return true
+}
+
+func (l *LinkTransferer) Move(src, dst string, opts TransferOptions) (*TransferResult, error) {
+	return l.transfer(src, dst, opts)
+}
+
+func (l *LinkTransferer) Copy(src, dst string, opts TransferOptions) (*TransferResult, error) {
+	return l.transfer(src, dst, opts)
+}
+
+func (l *LinkTransferer) transfer(src, dst string, opts TransferOptions) (*TransferResult, error) {
+	result := &TransferResult{Attempts: 1, SourcePreserved: true}
+	startTime := time.Now()
+
+	srcInfo, err := StatWithTimeout(src, 10*time.Second)
+	if err != nil {
+		result.Error = fmt.Errorf("%w: %v", ErrSourceNotFound, err)
+		return result, result.Error
+	}
+	result.BytesTotal = srcInfo.Size()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		result.Error = fmt.Errorf("%w: %v", ErrDestinationNotWritable, err)
+		return result, result.Error
+	}
+
+	linkErr := l.link(src, dst)
+	if linkErr == nil {
+		// A hardlink shares the source inode, so chmod/chown would also
+		// rewrite the download client's file. Only reflinks (new inode)
+		// get the configured permissions applied.
+		if l.mode == LinkReflink {
+			if err := ApplyPermissions(dst, opts); err != nil {
+				result.Error = fmt.Errorf("transfer succeeded but permission application failed: %w", err)
+			}
+		}
+		result.Success = true
+		result.LinkType = l.mode.String()
+		result.Duration = time.Since(startTime)
+		return result, nil
+	}
+	if !errors.Is(linkErr, ErrLinkUnsupported) {
+		result.Error = fmt.Errorf("%s failed: %w", l.mode, linkErr)
+		result.Duration = time.Since(startTime)
+		return result, result.Error
+	}
+	if l.fallback == nil {
+		result.Error = fmt.Errorf("%s failed and no copy fallback configured: %w", l.mode, linkErr)
+		return result, result.Error
+	}
+
+	copyResult, err := l.fallback.Copy(src, dst, opts)
+	if copyResult == nil {
+		copyResult = &TransferResult{}
+	}
+	copyResult.SourcePreserved = true
+	copyResult.SourceRemoved = false
+	if err != nil {
+		return copyResult, err
+	}
+	if copyResult.Success {
+		if permErr := ApplyPermissions(dst, opts); permErr != nil {
+			copyResult.Error = fmt.Errorf("transfer succeeded but permission application failed: %w", permErr)
+		}
+	}
+	return copyResult, nil
+}
+
+// link creates dst from src using the configured mode. The link is built
+// under a temporary name and renamed into place so an existing destination
+// is replaced atomically, matching the native backend's commit semantics.
+func (l *LinkTransferer) link(src, dst string) error {
+	// rename(2) between two links to the same inode is a no-op that leaves
+	// the temporary name behind, so an existing hardlink is already done.
+	if srcInfo, err := os.Stat(src); err == nil {
+		if dstInfo, err := os.Stat(dst); err == nil && os.SameFile(srcInfo, dstInfo) {
+			return nil
+		}
+	}
+
+	tmp := filepath.Join(filepath.Dir(dst), fmt.Sprintf(".%s.link-%d", filepath.Base(dst), time.Now().UnixNano()))
+
+	var err error
+	switch l.mode {
+	case LinkHardlink:
+		err = hardlink(src, tmp)
+	case LinkReflink:
+		err = reflink(src, tmp)
+	default:
+		return fmt.Errorf("unknown link mode %d", l.mode)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("commit link: %w", err)
+	}
+	return nil
+}
+
+func hardlink(src, dst string) error {
+	if err := os.Link(src, dst); err != nil {
+		if isLinkUnsupported(err) {
+			return fmt.Errorf("%w: %v", ErrLinkUnsupported, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// isLinkUnsupported reports whether err means "this filesystem pair can't
+// do that" rather than a real I/O failure.
+func isLinkUnsupported(err error) bool {
+	return errors.Is(err, syscall.EXDEV) ||
+		errors.Is(err, syscall.EPERM) ||
+		errors.Is(err, syscall.EMLINK) ||
+		errors.Is(err, syscall.ENOTSUP) ||
+		errors.Is(err, syscall.EOPNOTSUPP) ||
+		errors.Is(err, syscall.EINVAL) ||
+		errors.Is(err, syscall.ENOTTY) ||
+		errors.Is(err, syscall.ENOSYS)
+}
+
+// PreservesSource reports whether t is a backend that never removes the
+// source on Move.
+func PreservesSource(t Transferer) bool {
+	p, ok := t.(interface{ PreservesSource() bool })
+	return ok && p.PreservesSource()
+}
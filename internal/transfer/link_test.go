@@ -0,0 +1,203 @@
+package transfer
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestHardlinkTransferer_MoveKeepsSource(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "downloads", "Movie.2001.1080p.mkv")
+	dst := filepath.Join(dir, "library", "Movie (2001)", "Movie (2001).mkv")
+	if err := os.MkdirAll(filepath.Dir(src), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(src, []byte("seeding payload"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lt := NewHardlinkTransferer(NewNativeTransferer(1024))
+	result, err := lt.Move(src, dst, TransferOptions{TargetUID: -1, TargetGID: -1})
+	if err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	if !result.Success || !result.SourcePreserved || result.SourceRemoved {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.LinkType != "hardlink" {
+		t.Fatalf("LinkType = %q, want hardlink", result.LinkType)
+	}
+
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		t.Fatalf("source removed: %v", err)
+	}
+	dstInfo, err := os.Stat(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !os.SameFile(srcInfo, dstInfo) {
+		t.Fatal("destination is not a hardlink of the source")
+	}
+}
+
+func TestHardlinkTransferer_ReplacesExistingDestination(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "new.mkv")
+	dst := filepath.Join(dir, "lib", "old.mkv")
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(src, []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dst, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewHardlinkTransferer(nil).Move(src, dst, TransferOptions{}); err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	got, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "new" {
+		t.Fatalf("destination = %q, want new", got)
+	}
+}
+
+func TestHardlinkTransferer_RelinkSameInodeLeavesNoTemp(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.mkv")
+	libDir := filepath.Join(dir, "lib")
+	dst := filepath.Join(libDir, "dst.mkv")
+	if err := os.MkdirAll(libDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(src, []byte("payload"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(src, dst); err != nil {
+		t.Skipf("hardlinks unsupported: %v", err)
+	}
+
+	if _, err := NewHardlinkTransferer(nil).Move(src, dst, TransferOptions{}); err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	entries, err := os.ReadDir(libDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "dst.mkv" {
+		names := make([]string, 0, len(entries))
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Fatalf("library dir = %v, want only dst.mkv", names)
+	}
+}
+
+func TestReflinkTransferer_FallsBackToCopy(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.mkv")
+	dst := filepath.Join(dir, "lib", "dst.mkv")
+	if err := os.WriteFile(src, []byte("payload"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Test temp dirs are rarely btrfs/xfs, so this normally exercises the
+	// copy fallback; on a CoW filesystem it exercises FICLONE instead.
+	result, err := NewReflinkTransferer(NewNativeTransferer(1024)).Move(src, dst, TransferOptions{TargetUID: -1, TargetGID: -1, SkipHealthCheck: true})
+	if err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	if !result.Success || !result.SourcePreserved {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Fatalf("source removed: %v", err)
+	}
+	got, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "payload" {
+		t.Fatalf("destination = %q, want payload", got)
+	}
+}
+
+func TestIsLinkUnsupported(t *testing.T) {
+	if !isLinkUnsupported(&os.LinkError{Op: "link", Err: syscall.EXDEV}) {
+		t.Error("EXDEV should be treated as unsupported")
+	}
+	if isLinkUnsupported(&os.LinkError{Op: "link", Err: syscall.ENOSPC}) {
+		t.Error("ENOSPC is a real failure, not unsupported")
+	}
+}
+
+// preservingMock is a source-preserving backend that always fails, forcing
+// the fallback chain onto the next backend.
+type preservingMock struct{ mockTransferer }
+
+func (p *preservingMock) PreservesSource() bool { return true }
+
+type recordingTransferer struct {
+	moves, copies int
+}
+
+func (r *recordingTransferer) Name() string    { return "recording" }
+func (r *recordingTransferer) CanResume() bool { return false }
+func (r *recordingTransferer) Move(src, dst string, opts TransferOptions) (*TransferResult, error) {
+	r.moves++
+	return &TransferResult{Success: true, SourceRemoved: true}, nil
+}
+func (r *recordingTransferer) Copy(src, dst string, opts TransferOptions) (*TransferResult, error) {
+	r.copies++
+	return &TransferResult{Success: true}, nil
+}
+
+func TestFallbackTransferer_PreservingBackendDowngradesMoveToCopy(t *testing.T) {
+	link := &preservingMock{mockTransferer{name: "hardlink", shouldFail: true}}
+	next := &recordingTransferer{}
+
+	ft := NewFallbackTransferer(link, next)
+	opts := DefaultOptions()
+	opts.SkipHealthCheck = true
+	result, err := ft.Move("/src", "/dst", opts)
+	if err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	if next.moves != 0 || next.copies != 1 {
+		t.Fatalf("expected copy after failed link, got moves=%d copies=%d", next.moves, next.copies)
+	}
+	if !result.SourcePreserved {
+		t.Fatal("expected SourcePreserved after link chain")
+	}
+}
+
+func TestParseBackend_LinkBackends(t *testing.T) {
+	if ParseBackend("hardlink") != BackendHardlink || BackendHardlink.String() != "hardlink" {
+		t.Error("hardlink backend did not round-trip")
+	}
+	if ParseBackend("reflink") != BackendReflink || BackendReflink.String() != "reflink" {
+		t.Error("reflink backend did not round-trip")
+	}
+	if _, err := New(BackendHardlink); err != nil {
+		t.Fatalf("New(BackendHardlink): %v", err)
+	}
+}
+
+func TestLookupBackend_RejectsUnknown(t *testing.T) {
+	if b, err := LookupBackend("hardlink"); err != nil || b != BackendHardlink {
+		t.Fatalf("LookupBackend(hardlink) = %v, %v", b, err)
+	}
+	if b, err := LookupBackend(""); err != nil || b != BackendAuto {
+		t.Fatalf("LookupBackend(\"\") = %v, %v", b, err)
+	}
+	if _, err := LookupBackend("hardlnk"); err == nil {
+		t.Fatal("expected error for misspelled backend")
+	}
+}
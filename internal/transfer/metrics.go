@@ -0,0 +1,28 @@
+package transfer
+
+import (
+	"time"
+
+	"github.com/Nomadcxx/jellywatch/internal/metrics"
+)
+
+var (
+	transferBytes = metrics.Default.NewCounterVec("jellywatch_transfer_bytes_total",
+		"Bytes copied into libraries, by backend and destination volume.", "backend", "volume")
+	transferDuration = metrics.Default.NewHistogramVec("jellywatch_transfer_duration_seconds",
+		"Time spent per transfer once a volume slot was acquired.",
+		metrics.ExponentialBuckets(1, 4, 8), "backend", "volume", "outcome")
+)
+
+// observeTransfer records a finished transfer made through a
+// VolumeLimitedTransferer, which every daemon transfer goes through.
+func observeTransfer(backend, volume string, result *TransferResult, err error, elapsed time.Duration) {
+	outcome := "success"
+	if err != nil || result == nil || !result.Success {
+		outcome = "failed"
+	}
+	if result != nil {
+		transferBytes.Add(float64(result.BytesCopied), backend, volume)
+	}
+	transferDuration.Observe(elapsed.Seconds(), backend, volume, outcome)
+}
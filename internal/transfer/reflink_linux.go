@@ -0,0 +1,37 @@
+//go:build linux
+
+package transfer
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// reflink clones src into a new file at dst with the FICLONE ioctl. The
+// clone shares extents with the source until either side is written, so it
+// costs no additional space on btrfs/xfs.
+func reflink(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if err := unix.IoctlFileClone(int(out.Fd()), int(in.Fd())); err != nil {
+		_ = out.Close()
+		if isLinkUnsupported(err) {
+			return fmt.Errorf("%w: %v", ErrLinkUnsupported, err)
+		}
+		return err
+	}
+	if info, err := in.Stat(); err == nil {
+		_ = out.Chmod(info.Mode().Perm())
+	}
+	return out.Close()
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package transfer
+
+import "fmt"
+
+// reflink is only implemented on Linux (FICLONE); elsewhere the link
+// transferer always falls back to a copy.
+func reflink(src, dst string) error {
+	return fmt.Errorf("%w: reflink requires linux", ErrLinkUnsupported)
+}
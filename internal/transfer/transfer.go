@@ -126,6 +126,16 @@ type TransferResult struct {
 	// SourceRemoved indicates whether the source file was deleted (for Move operations)
 	SourceRemoved bool
 
+	// SourcePreserved indicates the source was intentionally left in place
+	// even though a Move was requested (hardlink/reflink backends keep the
+	// original so the download client can keep seeding). Callers must not
+	// clean up the source or its release directory when this is set.
+	SourcePreserved bool
+
+	// LinkType is "hardlink" or "reflink" when the destination shares data
+	// with the source, and empty when the bytes were copied.
+	LinkType string
+
 	// Attempts is the number of attempts made (including retries)
 	Attempts int
 
@@ -162,6 +172,8 @@ const (
 	BackendPV
 	BackendRsync
 	BackendNative
+	BackendHardlink
+	BackendReflink
 )
 
 func (b Backend) String() string {
@@ -174,6 +186,10 @@ func (b Backend) String() string {
 		return "rsync"
 	case BackendNative:
 		return "native"
+	case BackendHardlink:
+		return "hardlink"
+	case BackendReflink:
+		return "reflink"
 	default:
 		return "unknown"
 	}
@@ -198,6 +214,18 @@ func New(backend Backend) (Transferer, error) {
 	case BackendNative:
 		return NewNativeTransferer(32 * 1024 * 1024), nil
 
+	case BackendHardlink, BackendReflink:
+		// Links only work within one filesystem (hardlink) or on CoW
+		// filesystems (reflink); everything else copies with the auto chain.
+		copier, err := New(BackendAuto)
+		if err != nil {
+			return nil, err
+		}
+		if backend == BackendHardlink {
+			return NewHardlinkTransferer(copier), nil
+		}
+		return NewReflinkTransferer(copier), nil
+
 	case BackendAuto:
 		fallthrough
 	default:
@@ -234,14 +262,31 @@ func MustNew(backend Backend) Transferer {
 }
 
 func ParseBackend(s string) Backend {
+	b, err := LookupBackend(s)
+	if err != nil {
+		return BackendAuto
+	}
+	return b
+}
+
+// LookupBackend is the strict form of ParseBackend: it rejects unknown
+// names instead of falling back to auto. Use it for configuration, where a
+// typo like "hardlnk" must not silently turn into a destructive move.
+func LookupBackend(s string) (Backend, error) {
 	switch s {
+	case "", "auto":
+		return BackendAuto, nil
 	case "pv":
-		return BackendPV
+		return BackendPV, nil
 	case "rsync":
-		return BackendRsync
+		return BackendRsync, nil
 	case "native":
-		return BackendNative
+		return BackendNative, nil
+	case "hardlink":
+		return BackendHardlink, nil
+	case "reflink":
+		return BackendReflink, nil
 	default:
-		return BackendAuto
+		return BackendAuto, fmt.Errorf("unknown transfer backend %q (want auto, pv, rsync, native, hardlink or reflink)", s)
 	}
 }
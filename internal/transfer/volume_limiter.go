@@ -2,8 +2,10 @@ package transfer
 
 import (
 	"path/filepath"
+	"sort"
 	"sync"
 	"syscall"
+	"time"
 )
 
 // VolumeLimiter caps the number of concurrent transfers per destination
@@ -18,18 +20,20 @@ import (
 type VolumeLimiter struct {
 	cap int
 
-	mu    sync.Mutex
-	sems  map[string]chan struct{}
-	roots map[string]string // path -> resolved mount root (cache)
+	mu      sync.Mutex
+	sems    map[string]chan struct{}
+	roots   map[string]string // path -> resolved mount root (cache)
+	waiting map[string]int    // mount root -> callers blocked in Acquire
 }
 
 // NewVolumeLimiter returns a limiter that allows up to `concurrent` parallel
 // transfers per destination mount point. A value <= 0 disables limiting.
 func NewVolumeLimiter(concurrent int) *VolumeLimiter {
 	return &VolumeLimiter{
-		cap:   concurrent,
-		sems:  make(map[string]chan struct{}),
-		roots: make(map[string]string),
+		cap:     concurrent,
+		sems:    make(map[string]chan struct{}),
+		roots:   make(map[string]string),
+		waiting: make(map[string]int),
 	}
 }
 
@@ -43,10 +47,47 @@ func (v *VolumeLimiter) Acquire(dst string) func() {
 	}
 	root := v.mountRoot(dst)
 	sem := v.semFor(root)
+	v.mu.Lock()
+	v.waiting[root]++
+	v.mu.Unlock()
 	sem <- struct{}{}
+	v.mu.Lock()
+	v.waiting[root]--
+	v.mu.Unlock()
 	return func() { <-sem }
 }
 
+// VolumeUsage is one destination volume's transfer slots.
+type VolumeUsage struct {
+	Root    string
+	Active  int // transfers holding a slot
+	Waiting int // transfers queued for one
+}
+
+// Usage reports every volume that has seen a transfer, sorted by mount
+// root.
+func (v *VolumeLimiter) Usage() []VolumeUsage {
+	if v == nil {
+		return nil
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	out := make([]VolumeUsage, 0, len(v.sems))
+	for root, sem := range v.sems {
+		out = append(out, VolumeUsage{Root: root, Active: len(sem), Waiting: v.waiting[root]})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Root < out[j].Root })
+	return out
+}
+
+// volume returns the mount root used to label dst in metrics.
+func (v *VolumeLimiter) volume(dst string) string {
+	if v == nil {
+		return ""
+	}
+	return v.mountRoot(dst)
+}
+
 func (v *VolumeLimiter) semFor(root string) chan struct{} {
 	v.mu.Lock()
 	defer v.mu.Unlock()
@@ -58,10 +99,7 @@ func (v *VolumeLimiter) semFor(root string) chan struct{} {
 	return s
 }
 
-// mountRoot walks up from `path` until it finds the directory whose parent
-// resides on a different filesystem (different syscall.Stat_t.Dev). On any
-// stat error or when reaching "/", it returns the deepest path inspected.
-// Results are cached per input path.
+// mountRoot returns MountRoot(path), cached per input path.
 func (v *VolumeLimiter) mountRoot(path string) string {
 	abs, err := filepath.Abs(path)
 	if err != nil {
@@ -74,14 +112,25 @@ func (v *VolumeLimiter) mountRoot(path string) string {
 	}
 	v.mu.Unlock()
 
-	cur := abs
+	root := MountRoot(abs)
+	v.cacheRoot(abs, root)
+	return root
+}
+
+// MountRoot walks up from `path` until it finds the directory whose parent
+// resides on a different filesystem (different syscall.Stat_t.Dev). On any
+// stat error or when reaching "/", it returns the deepest path inspected.
+func MountRoot(path string) string {
+	cur, err := filepath.Abs(path)
+	if err != nil {
+		cur = path
+	}
 	curDev, ok := devOf(cur)
 	if !ok {
 		// stat failed: try parent until we find an existing dir.
 		cur = filepath.Dir(cur)
 		curDev, ok = devOf(cur)
 		if !ok {
-			v.cacheRoot(abs, cur)
 			return cur
 		}
 	}
@@ -96,10 +145,20 @@ func (v *VolumeLimiter) mountRoot(path string) string {
 		}
 		cur = parent
 	}
-	v.cacheRoot(abs, cur)
 	return cur
 }
 
+// SameVolume reports whether a and b, which must exist, are on the same
+// filesystem, i.e. whether a rename between them can succeed.
+func SameVolume(a, b string) bool {
+	da, ok := devOf(a)
+	if !ok {
+		return false
+	}
+	db, ok := devOf(b)
+	return ok && da == db
+}
+
 func (v *VolumeLimiter) cacheRoot(key, root string) {
 	v.mu.Lock()
 	v.roots[key] = root
@@ -136,14 +195,26 @@ func (t *VolumeLimitedTransferer) CanResume() bool {
 	return t.inner.CanResume()
 }
 
+// PreservesSource reports whether the wrapped backend never removes the
+// source.
+func (t *VolumeLimitedTransferer) PreservesSource() bool {
+	return PreservesSource(t.inner)
+}
+
 func (t *VolumeLimitedTransferer) Move(src, dst string, opts TransferOptions) (*TransferResult, error) {
 	release := t.limiter.Acquire(dst)
 	defer release()
-	return t.inner.Move(src, dst, opts)
+	start := time.Now()
+	result, err := t.inner.Move(src, dst, opts)
+	observeTransfer(t.inner.Name(), t.limiter.volume(dst), result, err, time.Since(start))
+	return result, err
 }
 
 func (t *VolumeLimitedTransferer) Copy(src, dst string, opts TransferOptions) (*TransferResult, error) {
 	release := t.limiter.Acquire(dst)
 	defer release()
-	return t.inner.Copy(src, dst, opts)
+	start := time.Now()
+	result, err := t.inner.Copy(src, dst, opts)
+	observeTransfer(t.inner.Name(), t.limiter.volume(dst), result, err, time.Since(start))
+	return result, err
 }
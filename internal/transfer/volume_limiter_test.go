@@ -34,7 +34,7 @@ func (c *countingTransferer) Move(_, _ string, _ TransferOptions) (*TransferResu
 }
 func (c *countingTransferer) Copy(_, _ string, _ TransferOptions) (*TransferResult, error) {
 	c.trace()
-	return &TransferResult{Success: true}, nil
+	return &TransferResult{Success: true, BytesCopied: 10}, nil
 }
 
 func TestVolumeLimiter_CapsConcurrencyPerVolume(t *testing.T) {
@@ -88,3 +88,33 @@ func TestVolumeLimiter_MountRootCachesResolution(t *testing.T) {
 		t.Fatalf("expected stable mount root, got %q vs %q", root1, root2)
 	}
 }
+
+func TestVolumeLimiter_UsageAndTransferMetrics(t *testing.T) {
+	limiter := NewVolumeLimiter(1)
+	inner := &countingTransferer{delay: 100 * time.Millisecond}
+	wrapped := NewVolumeLimitedTransferer(inner, limiter)
+	root := limiter.mountRoot("/tmp/dst")
+	before := transferBytes.Value("counting", root)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = wrapped.Copy("/tmp/src", "/tmp/dst", TransferOptions{SkipHealthCheck: true})
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	usage := limiter.Usage()
+	if len(usage) != 1 || usage[0] != (VolumeUsage{Root: root, Active: 1, Waiting: 2}) {
+		t.Fatalf("Usage() = %+v, want one active and two waiting on %s", usage, root)
+	}
+	wg.Wait()
+
+	if got := limiter.Usage(); got[0].Active != 0 || got[0].Waiting != 0 {
+		t.Fatalf("Usage() after transfers = %+v, want idle", got)
+	}
+	if after := transferBytes.Value("counting", root); after-before != 30 {
+		t.Fatalf("bytes counter moved by %v, want 30", after-before)
+	}
+}
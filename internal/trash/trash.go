@@ -0,0 +1,323 @@
+// Package trash keeps deleted files in a recycle bin on their own volume,
+// so a delete is a rename that can be restored until the bin is purged.
+package trash
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Nomadcxx/jellywatch/internal/config"
+	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/journal"
+	"github.com/Nomadcxx/jellywatch/internal/transfer"
+)
+
+// DefaultDirName is the trash directory created at the mount root of a
+// volume that has none configured.
+const DefaultDirName = ".jellywatch-trash"
+
+// markerName marks a configured trash directory so library walks that
+// reach it can skip it.
+const markerName = ".jellywatch-trash-dir"
+
+// IsTrashDir reports whether dir is a recycle bin that library scans
+// must not index.
+func IsTrashDir(dir string) bool {
+	if filepath.Base(dir) == DefaultDirName {
+		return true
+	}
+	_, err := os.Lstat(filepath.Join(dir, markerName))
+	return err == nil
+}
+
+// Bin moves deleted files into a per-volume trash directory and records
+// them in the database. A nil or disabled Bin deletes for good, so callers
+// can route every delete through it unconditionally.
+type Bin struct {
+	db      *database.MediaDB
+	enabled bool
+	dirs    []string
+	maxAge  time.Duration
+	maxSize int64
+	syncer  journal.Syncer
+	now     func() time.Time
+}
+
+// New returns a Bin configured by cfg.
+func New(db *database.MediaDB, cfg config.TrashConfig) *Bin {
+	b := &Bin{
+		db:      db,
+		enabled: cfg.Enabled && db != nil,
+		dirs:    cfg.Dirs,
+		maxSize: int64(cfg.MaxSizeGB * (1 << 30)),
+		now:     time.Now,
+	}
+	if cfg.MaxAgeDays > 0 {
+		b.maxAge = time.Duration(cfg.MaxAgeDays) * 24 * time.Hour
+	}
+	return b
+}
+
+// Enabled reports whether Remove keeps what it deletes.
+func (b *Bin) Enabled() bool {
+	return b != nil && b.enabled
+}
+
+// SetSyncer sets who hears about files Restore puts back through the
+// operation journal.
+func (b *Bin) SetSyncer(s journal.Syncer) {
+	if b != nil {
+		b.syncer = s
+	}
+}
+
+// Remove deletes path like os.Remove, with reason recorded for
+// "jellywatch trash list". It returns where the trash kept the file, or ""
+// when it was deleted for good because the bin is disabled. Directories are
+// never kept: an empty one is removed and a non-empty one is an error, as
+// with os.Remove, so a missing path is an error satisfying os.IsNotExist.
+func (b *Bin) Remove(path, reason string) (string, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() || !b.Enabled() {
+		return "", os.Remove(path)
+	}
+	return b.keep(path, reason, info.Size())
+}
+
+// RemoveAll deletes path and anything it contains like os.RemoveAll,
+// keeping the whole tree in the trash when the bin is enabled.
+func (b *Bin) RemoveAll(path, reason string) (string, error) {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil || !b.Enabled() {
+		return "", os.RemoveAll(path)
+	}
+	size := info.Size()
+	if info.IsDir() {
+		size = treeSize(path)
+	}
+	return b.keep(path, reason, size)
+}
+
+// keep moves path into the trash on its volume and records it.
+func (b *Bin) keep(path, reason string, size int64) (string, error) {
+	dir, err := b.dirFor(path)
+	if err != nil {
+		return "", err
+	}
+	if within(path, dir) {
+		// Already in the trash: this is the purge.
+		return "", os.RemoveAll(path)
+	}
+
+	bucket := filepath.Join(dir, b.now().UTC().Format("20060102-150405")+"-"+token())
+	if err := os.MkdirAll(bucket, 0755); err != nil {
+		return "", fmt.Errorf("trash: %w", err)
+	}
+	dest := filepath.Join(bucket, filepath.Base(path))
+	if err := os.Rename(path, dest); err != nil {
+		_ = os.Remove(bucket)
+		return "", fmt.Errorf("trash %s: %w", path, err)
+	}
+
+	item := &database.TrashItem{
+		OriginalPath: path,
+		TrashPath:    dest,
+		TrashDir:     dir,
+		Reason:       reason,
+		Size:         size,
+		DeletedAt:    b.now().UTC(),
+	}
+	if err := b.db.InsertTrashItem(item); err != nil {
+		// An unrecorded file would never be purged; put it back.
+		if rerr := os.Rename(dest, path); rerr == nil {
+			_ = os.Remove(bucket)
+		}
+		return "", err
+	}
+	return dest, nil
+}
+
+// dirFor picks the trash directory on path's volume: the first configured
+// directory on the same filesystem, else DefaultDirName at its mount root.
+func (b *Bin) dirFor(path string) (string, error) {
+	parent := filepath.Dir(path)
+	for _, dir := range b.dirs {
+		if dir == "" || os.MkdirAll(dir, 0755) != nil {
+			continue
+		}
+		if transfer.SameVolume(dir, parent) {
+			if f, err := os.OpenFile(filepath.Join(dir, markerName), os.O_CREATE|os.O_WRONLY, 0644); err == nil {
+				f.Close()
+			}
+			return dir, nil
+		}
+	}
+	dir := filepath.Join(transfer.MountRoot(parent), DefaultDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("trash: no trash directory on the volume of %s; add one to [trash] dirs: %w", path, err)
+	}
+	if !transfer.SameVolume(dir, parent) {
+		return "", fmt.Errorf("trash: %s is not on the volume of %s", dir, path)
+	}
+	return dir, nil
+}
+
+// List returns everything in the trash, oldest first.
+func (b *Bin) List() ([]database.TrashItem, error) {
+	if b == nil || b.db == nil {
+		return nil, nil
+	}
+	return b.db.ListTrashItems()
+}
+
+// Restore moves item id back to where it was deleted from. When the delete
+// was journaled, the journal undoes it so the library rows come back too.
+func (b *Bin) Restore(id int64) (*database.TrashItem, error) {
+	if b == nil || b.db == nil {
+		return nil, fmt.Errorf("trash item %d not found", id)
+	}
+	item, err := b.db.GetTrashItem(id)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, fmt.Errorf("trash item %d not found", id)
+	}
+
+	op, err := b.db.LiveDeleteInto(item.TrashPath)
+	if err != nil {
+		return nil, err
+	}
+	if op != nil {
+		if _, err := journal.NewUndoer(b.db, b.syncer, false).UndoOperation(op.ID); err != nil {
+			return nil, err
+		}
+		return item, nil
+	}
+
+	if _, err := os.Lstat(item.TrashPath); err != nil {
+		return nil, fmt.Errorf("%s is gone: %w", item.TrashPath, err)
+	}
+	if _, err := os.Lstat(item.OriginalPath); err == nil {
+		return nil, fmt.Errorf("%s already exists", item.OriginalPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(item.OriginalPath), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(item.TrashPath, item.OriginalPath); err != nil {
+		return nil, fmt.Errorf("restore %s: %w", item.OriginalPath, err)
+	}
+	_ = os.Remove(filepath.Dir(item.TrashPath))
+	return item, b.db.DeleteTrashItem(item.ID)
+}
+
+// PurgeResult counts what a purge deleted for good.
+type PurgeResult struct {
+	Items int
+	Bytes int64
+}
+
+// Purge deletes items older than the configured age, then the oldest items
+// of any trash directory over the size quota.
+func (b *Bin) Purge() (PurgeResult, error) {
+	var res PurgeResult
+	items, err := b.List()
+	if err != nil || len(items) == 0 {
+		return res, err
+	}
+
+	cutoff := b.now().Add(-b.maxAge)
+	used := make(map[string]int64)
+	var kept []database.TrashItem
+	for _, item := range items {
+		if b.maxAge > 0 && item.DeletedAt.Before(cutoff) {
+			if err := b.drop(item, &res); err != nil {
+				return res, err
+			}
+			continue
+		}
+		kept = append(kept, item)
+		used[item.TrashDir] += item.Size
+	}
+
+	if b.maxSize > 0 {
+		// kept is oldest first, so the oldest go until each directory fits.
+		for _, item := range kept {
+			if used[item.TrashDir] <= b.maxSize {
+				continue
+			}
+			if err := b.drop(item, &res); err != nil {
+				return res, err
+			}
+			used[item.TrashDir] -= item.Size
+		}
+	}
+	return res, nil
+}
+
+// Empty deletes everything in the trash for good.
+func (b *Bin) Empty() (PurgeResult, error) {
+	var res PurgeResult
+	items, err := b.List()
+	if err != nil {
+		return res, err
+	}
+	for _, item := range items {
+		if err := b.drop(item, &res); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+func (b *Bin) drop(item database.TrashItem, res *PurgeResult) error {
+	if err := os.RemoveAll(item.TrashPath); err != nil {
+		return fmt.Errorf("purge %s: %w", item.TrashPath, err)
+	}
+	_ = os.Remove(filepath.Dir(item.TrashPath))
+	if err := b.db.DeleteTrashItem(item.ID); err != nil {
+		return err
+	}
+	if err := b.db.ExpireDeletesInto(item.TrashPath); err != nil {
+		return err
+	}
+	res.Items++
+	res.Bytes += item.Size
+	return nil
+}
+
+func treeSize(root string) int64 {
+	var total int64
+	_ = filepath.WalkDir(root, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total
+}
+
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func token() string {
+	var b [3]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,194 @@
+package trash
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellywatch/internal/config"
+	"github.com/Nomadcxx/jellywatch/internal/database"
+)
+
+func openDB(t *testing.T) *database.MediaDB {
+	t.Helper()
+	db, err := database.OpenPath(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("OpenPath: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func writeFile(t *testing.T, path string, size int) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+func TestRemoveAndRestore(t *testing.T) {
+	db := openDB(t)
+	root := t.TempDir()
+	trashDir := filepath.Join(root, ".trash")
+	bin := New(db, config.TrashConfig{Enabled: true, Dirs: []string{trashDir}})
+
+	path := filepath.Join(root, "Movies", "Heat (1995)", "Heat (1995).mkv")
+	writeFile(t, path, 10)
+
+	kept, err := bin.Remove(path, "replaced by a better copy")
+	if err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if exists(path) || !exists(kept) {
+		t.Fatalf("after Remove: original exists=%v, trash copy %s exists=%v", exists(path), kept, exists(kept))
+	}
+	if !strings.HasPrefix(kept, trashDir+string(filepath.Separator)) {
+		t.Errorf("kept at %s, want inside %s", kept, trashDir)
+	}
+
+	items, err := bin.List()
+	if err != nil || len(items) != 1 {
+		t.Fatalf("List = %v, %v; want one item", items, err)
+	}
+	item := items[0]
+	if item.OriginalPath != path || item.Reason != "replaced by a better copy" || item.Size != 10 || item.TrashDir != trashDir {
+		t.Errorf("item = %+v", item)
+	}
+
+	writeFile(t, path, 1)
+	if _, err := bin.Restore(item.ID); err == nil {
+		t.Fatal("Restore over an existing file succeeded")
+	}
+	os.Remove(path)
+
+	if _, err := bin.Restore(item.ID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if !exists(path) || exists(kept) || exists(filepath.Dir(kept)) {
+		t.Errorf("after Restore: original exists=%v, trash copy exists=%v, bucket exists=%v",
+			exists(path), exists(kept), exists(filepath.Dir(kept)))
+	}
+	if items, _ := bin.List(); len(items) != 0 {
+		t.Errorf("List after Restore = %v, want empty", items)
+	}
+}
+
+func TestRestoreUndoesJournaledDelete(t *testing.T) {
+	db := openDB(t)
+	root := t.TempDir()
+	bin := New(db, config.TrashConfig{Enabled: true, Dirs: []string{filepath.Join(root, ".trash")}})
+
+	path := filepath.Join(root, "Movies", "Heat (1995)", "Heat (1995).mkv")
+	writeFile(t, path, 10)
+	if err := db.UpsertMediaFile(&database.MediaFile{Path: path, Size: 10, ModifiedAt: time.Now(), MediaType: "movie", NormalizedTitle: "heat", Source: "filesystem", LibraryRoot: filepath.Join(root, "Movies")}); err != nil {
+		t.Fatal(err)
+	}
+
+	kept, err := bin.Remove(path, "duplicate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.RecordOperation(database.JournalOp{
+		BatchID:    database.NewJournalBatch(database.OpSourceDuplicate),
+		Source:     database.OpSourceDuplicate,
+		Kind:       database.OpDelete,
+		BeforePath: path,
+		AfterPath:  kept,
+		Reversible: true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DeleteMediaFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	items, _ := bin.List()
+	if _, err := bin.Restore(items[0].ID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if !exists(path) {
+		t.Fatal("file not restored")
+	}
+	if f, err := db.GetMediaFile(path); err != nil || f == nil {
+		t.Errorf("media_files row not restored: %v, %v", f, err)
+	}
+	if items, _ := bin.List(); len(items) != 0 {
+		t.Errorf("trash item left behind: %v", items)
+	}
+}
+
+func TestPurgeByAgeAndQuota(t *testing.T) {
+	db := openDB(t)
+	root := t.TempDir()
+	bin := New(db, config.TrashConfig{Enabled: true, Dirs: []string{filepath.Join(root, ".trash")}, MaxAgeDays: 30, MaxSizeGB: 25.0 / (1 << 30)})
+
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	var kept []string
+	for i, age := range []time.Duration{40 * 24 * time.Hour, 3 * time.Hour, 2 * time.Hour, time.Hour} {
+		bin.now = func() time.Time { return now.Add(-age) }
+		path := filepath.Join(root, "dl", string(rune('a'+i))+".nfo")
+		writeFile(t, path, 10)
+		k, err := bin.Remove(path, "junk")
+		if err != nil {
+			t.Fatal(err)
+		}
+		kept = append(kept, k)
+	}
+	old, _ := db.ListTrashItems()
+	if _, err := db.RecordOperation(database.JournalOp{BatchID: "b", Source: database.OpSourceDuplicate, Kind: database.OpDelete, BeforePath: old[0].OriginalPath, AfterPath: kept[0], Reversible: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	bin.now = func() time.Time { return now }
+	res, err := bin.Purge()
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	// The 40-day-old item is past the age limit; of the remaining 30
+	// bytes against a 25-byte quota, the oldest goes.
+	if res.Items != 2 || res.Bytes != 20 {
+		t.Errorf("Purge = %+v, want 2 items, 20 bytes", res)
+	}
+	if exists(kept[0]) || exists(kept[1]) || !exists(kept[2]) || !exists(kept[3]) {
+		t.Errorf("purged the wrong items: %v %v %v %v", exists(kept[0]), exists(kept[1]), exists(kept[2]), exists(kept[3]))
+	}
+	if op, _ := db.LiveDeleteInto(kept[0]); op != nil {
+		t.Errorf("journaled delete still reversible after purge: %+v", op)
+	}
+
+	res, err = bin.Empty()
+	if err != nil || res.Items != 2 {
+		t.Fatalf("Empty = %+v, %v", res, err)
+	}
+	if items, _ := bin.List(); len(items) != 0 {
+		t.Errorf("List after Empty = %v", items)
+	}
+}
+
+func TestDisabledBinDeletes(t *testing.T) {
+	var nilBin *Bin
+	for _, bin := range []*Bin{nilBin, New(openDB(t), config.TrashConfig{})} {
+		dir := filepath.Join(t.TempDir(), "Sample")
+		writeFile(t, filepath.Join(dir, "sample.mkv"), 1)
+		if _, err := bin.Remove(dir, "junk"); err == nil || !exists(dir) {
+			t.Errorf("Remove(non-empty dir) = %v; exists=%v", err, exists(dir))
+		}
+		kept, err := bin.RemoveAll(dir, "junk")
+		if err != nil || kept != "" || exists(dir) {
+			t.Errorf("RemoveAll = %q, %v; exists=%v", kept, err, exists(dir))
+		}
+		if _, err := bin.Remove(dir, "junk"); !os.IsNotExist(err) {
+			t.Errorf("Remove(missing) = %v, want not-exist", err)
+		}
+	}
+}
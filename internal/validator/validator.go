@@ -29,6 +29,7 @@ type ValidationResult struct {
 // Validator validates media files against Jellyfin naming conventions
 type Validator struct {
 	allowMissingYear bool
+	templates        *naming.Templates
 }
 
 // NewValidator creates a new Validator instance
@@ -51,6 +52,16 @@ func WithAllowMissingYear(allow bool) func(*Validator) {
 	}
 }
 
+// WithTemplates validates names against a library's naming templates
+// instead of the built-in layout. A zero Templates keeps the built-in rules.
+func WithTemplates(t naming.Templates) func(*Validator) {
+	return func(v *Validator) {
+		if !t.IsZero() {
+			v.templates = &t
+		}
+	}
+}
+
 // ValidateFile validates a single media file
 func (v *Validator) ValidateFile(path string) (*ValidationResult, error) {
 	filename := filepath.Base(path)
@@ -102,6 +113,11 @@ func (v *Validator) validateMovieFile(path string, result *ValidationResult) {
 		return
 	}
 
+	if v.templates != nil {
+		v.validateTemplatedMovie(path, movie, result)
+		return
+	}
+
 	// Check if year is in parentheses
 	if !v.allowMissingYear && !naming.HasYearInParentheses(filename) {
 		result.Issues = append(result.Issues, "Year not in parentheses format (YYYY)")
@@ -114,7 +130,7 @@ func (v *Validator) validateMovieFile(path string, result *ValidationResult) {
 
 	// Calculate expected name
 	ext := filepath.Ext(filename)
-	expectedName := naming.FormatMovieFilename(movie.Title, movie.Year, ext[1:])
+	expectedName := naming.FormatMovieVersionFilename(movie.Title, movie.Year, naming.StackedVersion(movie.Edition, movie.Part), ext[1:])
 
 	if filename != expectedName {
 		result.Issues = append(result.Issues, fmt.Sprintf("Filename doesn't match expected format"))
@@ -134,9 +150,14 @@ func (v *Validator) validateTVFile(path string, result *ValidationResult) {
 		return
 	}
 
+	if v.templates != nil {
+		v.validateTemplatedTVFile(path, tv, result)
+		return
+	}
+
 	// Check if in proper season folder
 	expectedSeason := naming.FormatSeasonFolder(tv.Season)
-	if !strings.EqualFold(dirName, expectedSeason) {
+	if !naming.SeasonFolderMatches(dirName, tv.Season) {
 		result.Issues = append(result.Issues,
 			fmt.Sprintf("Not in proper season folder (expected: %s, found: %s)", expectedSeason, dirName))
 	}
@@ -156,6 +177,47 @@ func (v *Validator) validateTVFile(path string, result *ValidationResult) {
 	}
 }
 
+// validateTemplatedMovie validates a movie file name against the
+// configured movie file template.
+func (v *Validator) validateTemplatedMovie(path string, movie *naming.MovieInfo, result *ValidationResult) {
+	filename := filepath.Base(path)
+	tmpl := v.templates.WithDefaults()
+
+	if !naming.TemplateUses(tmpl.MovieFile, "Resolution", "Source", "ReleaseGroup") && v.hasReleaseMarkers(filename) {
+		result.Issues = append(result.Issues, "Contains release group markers")
+	}
+
+	expectedName := tmpl.MovieFileName(movie, naming.TemplateValuesFromPath(path), filepath.Ext(filename))
+	if filename != expectedName {
+		result.Issues = append(result.Issues, "Filename doesn't match naming template")
+		result.ExpectedName = expectedName
+	}
+}
+
+// validateTemplatedTVFile validates an episode's season folder and file
+// name against the configured templates.
+func (v *Validator) validateTemplatedTVFile(path string, tv *naming.TVShowInfo, result *ValidationResult) {
+	filename := filepath.Base(path)
+	dirName := filepath.Base(filepath.Dir(path))
+	tmpl := v.templates.WithDefaults()
+	extra := naming.TemplateValuesFromPath(path)
+
+	expectedSeason := tmpl.SeasonFolderName(tv.Season)
+	if dirName != expectedSeason && !(v.templates.SeasonFolder == "" && naming.SeasonFolderMatches(dirName, tv.Season)) {
+		result.Issues = append(result.Issues,
+			fmt.Sprintf("Not in proper season folder (expected: %s, found: %s)", expectedSeason, dirName))
+	}
+
+	if !naming.TemplateUses(tmpl.EpisodeFile, "Resolution", "Source", "ReleaseGroup") && v.hasReleaseMarkers(filename) {
+		result.Issues = append(result.Issues, "Contains release group markers")
+	}
+
+	if !tmpl.MatchesEpisodeFileName(filename, tv, extra) {
+		result.Issues = append(result.Issues, "Filename doesn't match naming template")
+		result.ExpectedName = tmpl.EpisodeFileName(tv, extra, filepath.Ext(filename))
+	}
+}
+
 // hasReleaseMarkers checks if filename contains release markers
 func (v *Validator) hasReleaseMarkers(filename string) bool {
 	upperName := strings.ToUpper(filename)
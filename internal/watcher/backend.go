@@ -0,0 +1,85 @@
+package watcher
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BackendKind names a way of noticing new files in a watch folder.
+type BackendKind string
+
+const (
+	// BackendAuto polls network and FUSE mounts and uses fsnotify
+	// everywhere else.
+	BackendAuto BackendKind = "auto"
+	// BackendFSNotify uses kernel notifications (inotify on Linux).
+	BackendFSNotify BackendKind = "fsnotify"
+	// BackendPoll re-reads the folder tree every poll interval. Use it
+	// where changes are made by another host or a FUSE daemon and never
+	// reach inotify: NFS, SMB, rclone mounts.
+	BackendPoll BackendKind = "poll"
+)
+
+// DefaultPollInterval is how often polled folders are re-read unless
+// WithPollInterval says otherwise.
+const DefaultPollInterval = 30 * time.Second
+
+// ParseBackendKind parses a backend name from the config. Empty is auto.
+func ParseBackendKind(name string) (BackendKind, error) {
+	switch kind := BackendKind(strings.ToLower(strings.TrimSpace(name))); kind {
+	case "", BackendAuto:
+		return BackendAuto, nil
+	case BackendFSNotify, "inotify":
+		return BackendFSNotify, nil
+	case BackendPoll, "polling":
+		return BackendPoll, nil
+	default:
+		return "", fmt.Errorf("unknown watch backend %q (want auto, fsnotify or poll)", name)
+	}
+}
+
+// Backend reports filesystem activity under the folders it is given. The
+// Watcher filters what it reports down to videos, settles them and hands
+// them to its Handler.
+type Backend interface {
+	// Watch starts reporting activity under root.
+	Watch(root string) error
+	// Reset stops watching every folder.
+	Reset()
+	// Events delivers activity until the backend is closed.
+	Events() <-chan FileEvent
+	// Errors delivers problems that don't stop the backend.
+	Errors() <-chan error
+	Close() error
+}
+
+// eventCloseWrite is sent by a backend when a writer closes a file. The
+// watcher feeds it to the settler; handlers never see it.
+const eventCloseWrite EventType = "close_write"
+
+// resolveBackend turns auto into a concrete backend for root. fsType names
+// the network filesystem that made it pick polling.
+func resolveBackend(kind BackendKind, root string) (resolved BackendKind, fsType string) {
+	if kind != BackendAuto {
+		return kind, ""
+	}
+	if fsType, ok := networkFS(root); ok {
+		return BackendPoll, fsType
+	}
+	return BackendFSNotify, ""
+}
+
+// backendKindFor returns the configured backend for root: the per-path
+// entry for root or the closest folder above it, else the default.
+func (w *Watcher) backendKindFor(root string) BackendKind {
+	root = filepath.Clean(root)
+	best, bestLen := w.defaultBackend, -1
+	for path, kind := range w.pathBackends {
+		if (root == path || strings.HasPrefix(root, path+string(filepath.Separator))) && len(path) > bestLen {
+			best, bestLen = kind, len(path)
+		}
+	}
+	return best
+}
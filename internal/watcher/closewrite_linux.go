@@ -0,0 +1,113 @@
+//go:build linux
+
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// closeWriteNotifier reports IN_CLOSE_WRITE events, which fsnotify doesn't
+// expose: a writer closing a file is a strong hint that a download or
+// unpack has finished.
+type closeWriteNotifier struct {
+	fd     int // used directly: File.Fd would switch the fd to blocking
+	file   *os.File
+	events chan string
+	done   chan struct{}
+
+	mu   sync.Mutex
+	dirs map[string]int // watched directory -> watch descriptor
+	wds  map[int]string
+}
+
+func newCloseWriteNotifier() (*closeWriteNotifier, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	n := &closeWriteNotifier{
+		// A non-blocking fd goes through the runtime poller, so Close
+		// unblocks the pending Read.
+		fd:     fd,
+		file:   os.NewFile(uintptr(fd), "inotify"),
+		events: make(chan string, 64),
+		done:   make(chan struct{}),
+		dirs:   make(map[string]int),
+		wds:    make(map[int]string),
+	}
+	go n.readEvents()
+	return n, nil
+}
+
+// Add watches dir (not recursively) for closed writes.
+func (n *closeWriteNotifier) Add(dir string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	wd, err := unix.InotifyAddWatch(n.fd, dir, unix.IN_CLOSE_WRITE)
+	if err != nil {
+		return err
+	}
+	n.dirs[dir] = wd
+	n.wds[wd] = dir
+	return nil
+}
+
+// RemoveAll drops every watch.
+func (n *closeWriteNotifier) RemoveAll() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for dir, wd := range n.dirs {
+		_, _ = unix.InotifyRmWatch(n.fd, uint32(wd))
+		delete(n.dirs, dir)
+		delete(n.wds, wd)
+	}
+}
+
+// Events delivers the paths of files closed after writing.
+func (n *closeWriteNotifier) Events() <-chan string {
+	return n.events
+}
+
+func (n *closeWriteNotifier) Close() error {
+	close(n.done)
+	return n.file.Close()
+}
+
+func (n *closeWriteNotifier) readEvents() {
+	defer close(n.events)
+	var buf [unix.SizeofInotifyEvent * 256]byte
+	for {
+		count, err := n.file.Read(buf[:])
+		if err != nil {
+			return
+		}
+		for offset := 0; offset+unix.SizeofInotifyEvent <= count; {
+			raw := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameEnd := offset + unix.SizeofInotifyEvent + int(raw.Len)
+			name := strings.TrimRight(string(buf[offset+unix.SizeofInotifyEvent:nameEnd]), "\x00")
+			offset = nameEnd
+
+			n.mu.Lock()
+			dir, ok := n.wds[int(raw.Wd)]
+			if raw.Mask&unix.IN_IGNORED != 0 {
+				delete(n.wds, int(raw.Wd))
+				delete(n.dirs, dir)
+			}
+			n.mu.Unlock()
+
+			if ok && name != "" && raw.Mask&unix.IN_CLOSE_WRITE != 0 {
+				select {
+				case n.events <- filepath.Join(dir, name):
+				case <-n.done:
+					return
+				}
+			}
+		}
+	}
+}
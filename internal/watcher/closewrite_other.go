@@ -0,0 +1,18 @@
+//go:build !linux
+
+package watcher
+
+import "errors"
+
+// closeWriteNotifier is only implemented on Linux (IN_CLOSE_WRITE);
+// elsewhere downloads settle on size and mtime alone.
+type closeWriteNotifier struct{}
+
+func newCloseWriteNotifier() (*closeWriteNotifier, error) {
+	return nil, errors.New("close-write notification requires linux")
+}
+
+func (n *closeWriteNotifier) Add(string) error      { return nil }
+func (n *closeWriteNotifier) RemoveAll()            {}
+func (n *closeWriteNotifier) Events() <-chan string { return nil }
+func (n *closeWriteNotifier) Close() error          { return nil }
@@ -0,0 +1,197 @@
+package watcher
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// fsnotifyBackend reports kernel notifications for watched folders, adding
+// folders created under them as they appear.
+type fsnotifyBackend struct {
+	watcher     *fsnotify.Watcher
+	closeWrites *closeWriteNotifier // nil where IN_CLOSE_WRITE is unavailable
+	recursive   bool
+	skipDir     func(string) bool
+
+	events chan FileEvent
+	errors chan error
+	done   chan struct{}
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+}
+
+// newFSNotifyBackend starts an fsnotify backend. With closeWrites set it
+// also reports writers closing files, best effort. Folders below a root
+// for which skipDir is true are not watched.
+func newFSNotifyBackend(recursive, closeWrites bool, skipDir func(string) bool) (*fsnotifyBackend, error) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create watcher: %w", err)
+	}
+	b := &fsnotifyBackend{
+		watcher:   fsWatcher,
+		recursive: recursive,
+		skipDir:   skipDir,
+		events:    make(chan FileEvent),
+		errors:    make(chan error, 8),
+		done:      make(chan struct{}),
+	}
+	if closeWrites {
+		if cw, err := newCloseWriteNotifier(); err == nil {
+			b.closeWrites = cw
+		} else {
+			log.Printf("watcher: close-write events unavailable, settling on size alone: %v", err)
+		}
+	}
+	b.wg.Add(1)
+	go b.run()
+	return b, nil
+}
+
+func (b *fsnotifyBackend) Events() <-chan FileEvent { return b.events }
+func (b *fsnotifyBackend) Errors() <-chan error     { return b.errors }
+
+func (b *fsnotifyBackend) Watch(root string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.recursive {
+		if err := b.add(root); err != nil {
+			return fmt.Errorf("unable to watch %s: %w", root, err)
+		}
+		log.Printf("Watching: %s", root)
+		return nil
+	}
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != root && b.skipDir(path) {
+			return filepath.SkipDir
+		}
+		if err := b.add(path); err != nil {
+			return fmt.Errorf("unable to watch %s: %w", path, err)
+		}
+		log.Printf("Watching: %s", path)
+		return nil
+	})
+}
+
+func (b *fsnotifyBackend) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, path := range b.watcher.WatchList() {
+		if err := b.watcher.Remove(path); err != nil {
+			// Non-fatal: the watch may have been auto-removed by the kernel
+			// when the directory was deleted (e.g., post-extraction cleanup
+			// of a SABnzbd _UNPACK_ folder). fsnotify returns EINVAL for
+			// such stale watches; we don't want a stale watch to block the
+			// entire reload pipeline.
+			log.Printf("watcher: ignoring remove error for %s: %v", path, err)
+		}
+	}
+	if b.closeWrites != nil {
+		b.closeWrites.RemoveAll()
+	}
+}
+
+// add watches one directory. Close-write notification is best effort.
+func (b *fsnotifyBackend) add(dir string) error {
+	if err := b.watcher.Add(dir); err != nil {
+		return err
+	}
+	if b.closeWrites != nil {
+		if err := b.closeWrites.Add(dir); err != nil {
+			log.Printf("watcher: no close-write events for %s: %v", dir, err)
+		}
+	}
+	return nil
+}
+
+func (b *fsnotifyBackend) run() {
+	defer b.wg.Done()
+	defer close(b.events)
+
+	var closeWrites <-chan string
+	if b.closeWrites != nil {
+		closeWrites = b.closeWrites.Events()
+	}
+
+	for {
+		select {
+		case event, ok := <-b.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&fsnotify.Create == fsnotify.Create {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if b.recursive && !b.skipDir(event.Name) {
+						b.mu.Lock()
+						b.add(event.Name)
+						b.mu.Unlock()
+						log.Printf("Now watching new directory: %s", event.Name)
+					}
+					continue
+				}
+			}
+			b.send(FileEvent{Type: fsnotifyEventType(event.Op), Path: event.Name})
+
+		case err, ok := <-b.watcher.Errors:
+			if !ok {
+				return
+			}
+			select {
+			case b.errors <- err:
+			default:
+			}
+
+		case path, ok := <-closeWrites:
+			if !ok {
+				closeWrites = nil
+				continue
+			}
+			b.send(FileEvent{Type: eventCloseWrite, Path: path})
+
+		case <-b.done:
+			return
+		}
+	}
+}
+
+func (b *fsnotifyBackend) send(event FileEvent) {
+	select {
+	case b.events <- event:
+	case <-b.done:
+	}
+}
+
+func (b *fsnotifyBackend) Close() error {
+	close(b.done)
+	if b.closeWrites != nil {
+		b.closeWrites.Close()
+	}
+	err := b.watcher.Close()
+	b.wg.Wait()
+	return err
+}
+
+// fsnotifyEventType maps an fsnotify op to the event handlers see.
+func fsnotifyEventType(op fsnotify.Op) EventType {
+	switch {
+	case op&fsnotify.Write == fsnotify.Write:
+		return EventWrite
+	case op&fsnotify.Rename == fsnotify.Rename:
+		return EventMove
+	case op&fsnotify.Remove == fsnotify.Remove:
+		return EventDelete
+	default:
+		return EventCreate
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package watcher
+
+import "os"
+
+// inode is unknown off Unix; the poller then reports a rename as a
+// delete and a create.
+func inode(os.FileInfo) uint64 {
+	return 0
+}
@@ -0,0 +1,34 @@
+//go:build linux
+
+package watcher
+
+import "golang.org/x/sys/unix"
+
+// networkFSMagic maps statfs f_type values of filesystems whose changes
+// are made by another host or a FUSE daemon, so inotify never hears of
+// them.
+var networkFSMagic = map[uint32]string{
+	0x6969:     "nfs",
+	0x517b:     "smb",
+	0xff534d42: "cifs",
+	0xfe534d42: "smb2",
+	0x65735546: "fuse",
+	0x01021997: "9p",
+	0x00c36400: "ceph",
+	0x5346414f: "afs",
+	0x73757245: "coda",
+	0x0000564c: "ncp",
+	0x47504653: "gpfs",
+	0x0bd00bd0: "lustre",
+}
+
+// networkFS reports whether path is on a network or FUSE filesystem, and
+// which.
+func networkFS(path string) (string, bool) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return "", false
+	}
+	name, ok := networkFSMagic[uint32(st.Type)]
+	return name, ok
+}
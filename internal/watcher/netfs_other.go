@@ -0,0 +1,9 @@
+//go:build !linux
+
+package watcher
+
+// networkFS only recognises network mounts on Linux; elsewhere auto always
+// picks fsnotify and polling has to be asked for.
+func networkFS(string) (string, bool) {
+	return "", false
+}
@@ -0,0 +1,255 @@
+package watcher
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fileState is what the poller remembers about a video between polls.
+type fileState struct {
+	size    int64
+	modTime time.Time
+	inode   uint64
+}
+
+// pollBackend notices changes by re-reading the watched trees every
+// interval and diffing each video's size, mtime and inode against the
+// last poll. Only media files (videos, and archives when the watcher
+// passes them) are remembered, so a poll costs one directory walk and a
+// stat per video.
+type pollBackend struct {
+	interval  time.Duration
+	recursive bool
+	skipDir   func(string) bool
+	isMedia   func(string) bool
+
+	events chan FileEvent
+	errors chan error
+	done   chan struct{}
+	wg     sync.WaitGroup
+
+	mu    sync.Mutex
+	roots map[string]struct{}
+	snap  map[string]fileState
+	gen   int // bumped by Watch and Reset so an overlapping poll is dropped
+}
+
+func newPollBackend(interval time.Duration, recursive bool, skipDir, isMedia func(string) bool) *pollBackend {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	p := &pollBackend{
+		interval:  interval,
+		recursive: recursive,
+		skipDir:   skipDir,
+		isMedia:   isMedia,
+		events:    make(chan FileEvent),
+		errors:    make(chan error, 8),
+		done:      make(chan struct{}),
+		roots:     make(map[string]struct{}),
+		snap:      make(map[string]fileState),
+	}
+	p.wg.Add(1)
+	go p.run()
+	return p
+}
+
+func (p *pollBackend) Events() <-chan FileEvent { return p.events }
+func (p *pollBackend) Errors() <-chan error     { return p.errors }
+
+// Watch records what root holds now; only changes after this are
+// reported, as with fsnotify.
+func (p *pollBackend) Watch(root string) error {
+	root = filepath.Clean(root)
+	info, err := os.Stat(root)
+	if err != nil {
+		return fmt.Errorf("unable to watch %s: %w", root, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("unable to watch %s: not a directory", root)
+	}
+	snap := make(map[string]fileState)
+	if err := p.scan(root, snap); err != nil {
+		return fmt.Errorf("unable to watch %s: %w", root, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.roots[root] = struct{}{}
+	for path, st := range snap {
+		p.snap[path] = st
+	}
+	p.gen++
+	return nil
+}
+
+func (p *pollBackend) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.roots = make(map[string]struct{})
+	p.snap = make(map[string]fileState)
+	p.gen++
+}
+
+func (p *pollBackend) run() {
+	defer p.wg.Done()
+	defer close(p.events)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for _, event := range p.poll() {
+				select {
+				case p.events <- event:
+				case <-p.done:
+					return
+				}
+			}
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// poll re-reads every root and returns what changed since the last poll.
+// A root that can't be read keeps its old state, so a mount that drops
+// for a moment doesn't look like every file was deleted and re-created.
+func (p *pollBackend) poll() []FileEvent {
+	p.mu.Lock()
+	gen := p.gen
+	roots := make([]string, 0, len(p.roots))
+	for root := range p.roots {
+		roots = append(roots, root)
+	}
+	p.mu.Unlock()
+
+	next := make(map[string]fileState)
+	var failed []string
+	for _, root := range roots {
+		if err := p.scan(root, next); err != nil {
+			failed = append(failed, root)
+			select {
+			case p.errors <- fmt.Errorf("poll %s: %w", root, err):
+			default:
+			}
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.gen != gen {
+		return nil
+	}
+	for _, root := range failed {
+		for path, st := range p.snap {
+			if underRoot(path, root) {
+				next[path] = st
+			}
+		}
+	}
+	events := diffSnapshots(p.snap, next)
+	p.snap = next
+	return events
+}
+
+// scan adds the videos under root to snap. Folders are skipped as the
+// fsnotify backend skips them; without recursion only root itself is
+// read. Only a root that can't be read is an error.
+func (p *pollBackend) scan(root string, snap map[string]fileState) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && (!p.recursive || p.skipDir(path)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !p.isMedia(path) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		snap[path] = fileState{size: info.Size(), modTime: info.ModTime(), inode: inode(info)}
+		return nil
+	})
+}
+
+func (p *pollBackend) Close() error {
+	close(p.done)
+	p.wg.Wait()
+	return nil
+}
+
+// diffSnapshots turns two polls into the events fsnotify would have sent:
+// a rename is a move of the old path and a create of the new one, found
+// by inode; a replaced file is a create; growth is a write.
+func diffSnapshots(prev, next map[string]fileState) []FileEvent {
+	gone := make(map[uint64]string)
+	var deleted []string
+	for path, st := range prev {
+		if _, ok := next[path]; ok {
+			continue
+		}
+		if st.inode != 0 {
+			gone[st.inode] = path
+		} else {
+			deleted = append(deleted, path)
+		}
+	}
+
+	var moved, created, written []string
+	for path, st := range next {
+		old, ok := prev[path]
+		switch {
+		case !ok:
+			if from, ok := gone[st.inode]; ok && st.inode != 0 {
+				delete(gone, st.inode)
+				moved = append(moved, from)
+			}
+			created = append(created, path)
+		case old.inode != st.inode:
+			created = append(created, path)
+		case old.size != st.size || !old.modTime.Equal(st.modTime):
+			written = append(written, path)
+		}
+	}
+	for _, path := range gone {
+		deleted = append(deleted, path)
+	}
+
+	var events []FileEvent
+	for _, group := range []struct {
+		eventType EventType
+		paths     []string
+	}{
+		{EventMove, moved},
+		{EventDelete, deleted},
+		{EventCreate, created},
+		{EventWrite, written},
+	} {
+		sort.Strings(group.paths)
+		for _, path := range group.paths {
+			events = append(events, FileEvent{Type: group.eventType, Path: path})
+		}
+	}
+	return events
+}
+
+func underRoot(path, root string) bool {
+	return strings.HasPrefix(path, root+string(filepath.Separator))
+}
@@ -0,0 +1,104 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	t0 := time.Unix(1700000000, 0)
+	prev := map[string]fileState{
+		"/w/renamed.mkv":  {size: 10, modTime: t0, inode: 1},
+		"/w/deleted.mkv":  {size: 10, modTime: t0, inode: 2},
+		"/w/growing.mkv":  {size: 10, modTime: t0, inode: 3},
+		"/w/replaced.mkv": {size: 10, modTime: t0, inode: 4},
+		"/w/same.mkv":     {size: 10, modTime: t0, inode: 5},
+	}
+	next := map[string]fileState{
+		"/w/sub/renamed.mkv": {size: 10, modTime: t0, inode: 1},
+		"/w/growing.mkv":     {size: 20, modTime: t0.Add(time.Second), inode: 3},
+		"/w/replaced.mkv":    {size: 10, modTime: t0, inode: 9},
+		"/w/same.mkv":        {size: 10, modTime: t0, inode: 5},
+		"/w/new.mkv":         {size: 1, modTime: t0, inode: 6},
+	}
+
+	want := []FileEvent{
+		{Type: EventMove, Path: "/w/renamed.mkv"},
+		{Type: EventDelete, Path: "/w/deleted.mkv"},
+		{Type: EventCreate, Path: "/w/new.mkv"},
+		{Type: EventCreate, Path: "/w/replaced.mkv"},
+		{Type: EventCreate, Path: "/w/sub/renamed.mkv"},
+		{Type: EventWrite, Path: "/w/growing.mkv"},
+	}
+	if got := diffSnapshots(prev, next); !reflect.DeepEqual(got, want) {
+		t.Fatalf("diffSnapshots =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestPollBackendReportsOnlyNewVideos(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "Existing.2019.mkv"), "old")
+
+	h := &recordingHandler{}
+	w, err := NewWatcher(h, false, WithBackend("poll", nil), WithPollInterval(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if err := w.Watch([]string{dir}); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Backends()[dir]; got != BackendPoll {
+		t.Fatalf("backend for %s = %q, want poll", dir, got)
+	}
+	go w.Start()
+
+	if err := os.MkdirAll(filepath.Join(dir, "Show"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "Show", "Show.S01E01.mkv")
+	writeFile(t, path, "episode")
+	writeFile(t, filepath.Join(dir, "Show", "notes.txt"), "ignored")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(h.snapshot()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	got := h.snapshot()
+	if len(got) != 1 || got[0] != (FileEvent{Type: EventCreate, Path: path}) {
+		t.Fatalf("events = %v, want one create for %s", got, path)
+	}
+}
+
+func TestBackendKindForUsesClosestPath(t *testing.T) {
+	w, err := NewWatcher(noopHandler{}, false, WithBackend("fsnotify", map[string]string{
+		"/mnt/nas":       "polling",
+		"/mnt/nas/local": "inotify",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	cases := map[string]BackendKind{
+		"/downloads":            BackendFSNotify,
+		"/mnt/nas":              BackendPoll,
+		"/mnt/nas/tv/":          BackendPoll,
+		"/mnt/nas/local/movies": BackendFSNotify,
+		"/mnt/nasty":            BackendFSNotify,
+	}
+	for root, want := range cases {
+		if got := w.backendKindFor(root); got != want {
+			t.Errorf("backendKindFor(%q) = %q, want %q", root, got, want)
+		}
+	}
+
+	if _, err := NewWatcher(noopHandler{}, false, WithBackend("auto", map[string]string{"/x": "kqueue"})); err == nil {
+		t.Fatal("NewWatcher accepted an unknown backend")
+	}
+}
@@ -0,0 +1,114 @@
+package watcher
+
+import (
+	"os"
+	"time"
+)
+
+// partialSuffixes are the in-progress markers download clients keep next
+// to (or instead of) the file they are writing: browsers and Transmission
+// use .part, qBittorrent .!qB, aria2 a .aria2 control file.
+var partialSuffixes = []string{".part", ".!qB", ".aria2"}
+
+// settlingFile is a download seen by the watcher but not yet handed on.
+type settlingFile struct {
+	eventType EventType
+	size      int64
+	modTime   time.Time
+	changed   time.Time // last time size, mtime or a sidecar changed
+	closed    bool      // the writer closed the file since the last change
+}
+
+// settler holds Create/Write events until the file stops changing, so the
+// handler gets one event per finished download instead of one per write.
+// It is driven from the watcher's event loop and is not safe for
+// concurrent use.
+type settler struct {
+	window time.Duration
+	files  map[string]*settlingFile
+}
+
+func newSettler(window time.Duration) *settler {
+	return &settler{window: window, files: make(map[string]*settlingFile)}
+}
+
+// pollInterval is how often pending files are re-checked.
+func (s *settler) pollInterval() time.Duration {
+	return min(max(s.window/4, 100*time.Millisecond), time.Second)
+}
+
+// track records activity on path. A Create is reported as such even when
+// writes follow it.
+func (s *settler) track(path string, eventType EventType, now time.Time) {
+	f, ok := s.files[path]
+	if !ok {
+		f = &settlingFile{eventType: eventType, size: -1}
+		s.files[path] = f
+	}
+	f.changed = now
+	f.closed = false
+}
+
+// closeWrite records that a writer closed path. A closed file only has to
+// stay quiet for a fifth of the window. Close events for paths the watcher
+// never saw created or written are ignored.
+func (s *settler) closeWrite(path string, now time.Time) {
+	f, ok := s.files[path]
+	if !ok {
+		return
+	}
+	if info, err := os.Stat(path); err == nil {
+		f.size, f.modTime = info.Size(), info.ModTime()
+	}
+	f.changed = now
+	f.closed = true
+}
+
+// forget drops path, e.g. after it was deleted or renamed away.
+func (s *settler) forget(path string) {
+	delete(s.files, path)
+}
+
+// ready re-checks every pending file and returns the ones that have
+// settled, removing them from the settler. Files that disappeared are
+// dropped; empty files and files with a partial-download sidecar wait.
+func (s *settler) ready(now time.Time) []FileEvent {
+	var out []FileEvent
+	for path, f := range s.files {
+		info, err := os.Stat(path)
+		if err != nil {
+			delete(s.files, path)
+			continue
+		}
+		if info.Size() != f.size || !info.ModTime().Equal(f.modTime) {
+			f.size, f.modTime = info.Size(), info.ModTime()
+			f.changed = now
+			f.closed = false
+			continue
+		}
+		if f.size == 0 || hasPartialSidecar(path) {
+			f.changed = now
+			continue
+		}
+
+		quiet := s.window
+		if f.closed {
+			quiet = s.window / 5
+		}
+		if now.Sub(f.changed) < quiet {
+			continue
+		}
+		out = append(out, FileEvent{Type: f.eventType, Path: path})
+		delete(s.files, path)
+	}
+	return out
+}
+
+func hasPartialSidecar(path string) bool {
+	for _, suffix := range partialSuffixes {
+		if _, err := os.Stat(path + suffix); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSettlerWaitsUntilFileStopsGrowing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Movie.2020.1080p.mkv")
+	writeFile(t, path, "part one")
+
+	s := newSettler(10 * time.Second)
+	t0 := time.Now()
+	s.track(path, EventCreate, t0)
+	s.track(path, EventWrite, t0)
+	if got := s.ready(t0); len(got) != 0 {
+		t.Fatalf("ready on first poll = %v, want nothing", got)
+	}
+
+	writeFile(t, path, " part two")
+	if got := s.ready(t0.Add(9 * time.Second)); len(got) != 0 {
+		t.Fatalf("ready after growth = %v, want nothing", got)
+	}
+	if got := s.ready(t0.Add(15 * time.Second)); len(got) != 0 {
+		t.Fatalf("ready 6s after growth = %v, want nothing", got)
+	}
+
+	got := s.ready(t0.Add(20 * time.Second))
+	if len(got) != 1 || got[0] != (FileEvent{Type: EventCreate, Path: path}) {
+		t.Fatalf("ready after quiet window = %v, want one create event", got)
+	}
+	if got := s.ready(t0.Add(time.Minute)); len(got) != 0 {
+		t.Fatalf("settled file reported twice: %v", got)
+	}
+}
+
+func TestSettlerHoldsFilesWithPartialSidecar(t *testing.T) {
+	for _, suffix := range partialSuffixes {
+		t.Run(suffix, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "Show.S01E01.mkv")
+			writeFile(t, path, "video")
+			writeFile(t, path+suffix, "in progress")
+
+			s := newSettler(time.Second)
+			t0 := time.Now()
+			s.track(path, EventCreate, t0)
+			s.ready(t0)
+			if got := s.ready(t0.Add(time.Hour)); len(got) != 0 {
+				t.Fatalf("ready with %s sidecar = %v, want nothing", suffix, got)
+			}
+
+			if err := os.Remove(path + suffix); err != nil {
+				t.Fatal(err)
+			}
+			if got := s.ready(t0.Add(time.Hour + 500*time.Millisecond)); len(got) != 0 {
+				t.Fatalf("ready right after sidecar removal = %v, want nothing", got)
+			}
+			if got := s.ready(t0.Add(time.Hour + 2*time.Second)); len(got) != 1 {
+				t.Fatalf("ready after sidecar removal = %v, want one event", got)
+			}
+		})
+	}
+}
+
+func TestSettlerCloseWriteShortensWait(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Movie.2020.mkv")
+	writeFile(t, path, "video")
+
+	s := newSettler(10 * time.Second)
+	t0 := time.Now()
+	s.track(path, EventWrite, t0)
+	s.closeWrite(path, t0)
+	s.closeWrite(filepath.Join(filepath.Dir(path), "untracked.mkv"), t0)
+
+	got := s.ready(t0.Add(2 * time.Second))
+	if len(got) != 1 || got[0].Type != EventWrite {
+		t.Fatalf("ready after close-write = %v, want one write event", got)
+	}
+}
+
+func TestSettlerDropsVanishedFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Movie.2020.mkv")
+	writeFile(t, path, "video")
+
+	s := newSettler(time.Second)
+	t0 := time.Now()
+	s.track(path, EventCreate, t0)
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.ready(t0.Add(time.Minute)); len(got) != 0 || len(s.files) != 0 {
+		t.Fatalf("vanished file: ready = %v, pending = %d", got, len(s.files))
+	}
+}
+
+type recordingHandler struct {
+	mu     sync.Mutex
+	events []FileEvent
+}
+
+func (h *recordingHandler) HandleFileEvent(e FileEvent) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, e)
+	return nil
+}
+
+func (h *recordingHandler) IsMediaFile(string) bool { return true }
+
+func (h *recordingHandler) snapshot() []FileEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]FileEvent(nil), h.events...)
+}
+
+func TestWatcherSettledDeliversOneEventPerDownload(t *testing.T) {
+	dir := t.TempDir()
+	h := &recordingHandler{}
+	w, err := NewWatcher(h, false, WithRecursive(false), WithSettleWindow(300*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if err := w.Watch([]string{dir}); err != nil {
+		t.Fatal(err)
+	}
+	go w.Start()
+
+	path := filepath.Join(dir, "Movie.2020.1080p.mkv")
+	for i := 0; i < 5; i++ {
+		writeFile(t, path, "chunk")
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(h.snapshot()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	got := h.snapshot()
+	if len(got) != 1 || got[0] != (FileEvent{Type: EventCreate, Path: path}) {
+		t.Fatalf("events = %v, want one create for %s", got, path)
+	}
+}
@@ -5,10 +5,11 @@ import (
 	"log"
 	"os"
 	"path/filepath"
-	"strings"
 	"sync"
+	"time"
 
-	"github.com/fsnotify/fsnotify"
+	"github.com/Nomadcxx/jellywatch/internal/extract"
+	"github.com/Nomadcxx/jellywatch/internal/ingest"
 	"github.com/Nomadcxx/jellywatch/internal/video"
 )
 
@@ -32,11 +33,28 @@ type Handler interface {
 }
 
 type Watcher struct {
-	fsWatcher *fsnotify.Watcher
-	handler   Handler
-	dryRun    bool
-	recursive bool
-	mu        sync.Mutex
+	handler      Handler
+	dryRun       bool
+	recursive    bool
+	settleWindow time.Duration
+	settle       *settler // nil: events go straight to the handler
+	filters      *ingest.Filters
+	archives     bool
+
+	defaultBackend BackendKind
+	pathBackends   map[string]BackendKind
+	pollInterval   time.Duration
+	optErr         error
+
+	backends map[BackendKind]Backend // created on first use
+	roots    map[string]BackendKind  // watched root -> backend watching it
+	fs       *fsnotifyBackend
+	events   chan FileEvent
+	errors   chan error
+	done     chan struct{}
+	closing  sync.Once
+	wg       sync.WaitGroup
+	mu       sync.Mutex
 }
 
 type Option func(*Watcher)
@@ -47,22 +65,89 @@ func WithRecursive(recursive bool) Option {
 	}
 }
 
-func NewWatcher(handler Handler, dryRun bool, opts ...Option) (*Watcher, error) {
-	fsWatcher, err := fsnotify.NewWatcher()
-	if err != nil {
-		return nil, fmt.Errorf("unable to create watcher: %w", err)
+// WithSettleWindow holds Create/Write events for a video until its size
+// and mtime have been unchanged for window (and no .part/.!qB/.aria2
+// sidecar remains), then sends the handler a single event. Zero disables
+// settling.
+func WithSettleWindow(window time.Duration) Option {
+	return func(w *Watcher) {
+		w.settleWindow = window
+	}
+}
+
+// WithFilters drops videos the watch-folder filters reject and leaves
+// ignored folders unwatched. Without it, ingest.Default applies.
+func WithFilters(filters *ingest.Filters) Option {
+	return func(w *Watcher) {
+		w.filters = filters
+	}
+}
+
+// WithArchives passes RAR, 7z and zip volumes to the handler along with
+// videos, for handlers that extract them.
+func WithArchives(enabled bool) Option {
+	return func(w *Watcher) {
+		w.archives = enabled
+	}
+}
+
+// WithBackend picks how watch folders are monitored: kind ("auto",
+// "fsnotify" or "poll") for every folder, except folders under a path in
+// perPath, which use that path's kind. Auto polls network and FUSE mounts.
+func WithBackend(kind string, perPath map[string]string) Option {
+	return func(w *Watcher) {
+		k, err := ParseBackendKind(kind)
+		if err != nil {
+			w.optErr = err
+			return
+		}
+		w.defaultBackend = k
+		for path, name := range perPath {
+			k, err := ParseBackendKind(name)
+			if err != nil {
+				w.optErr = fmt.Errorf("%s: %w", path, err)
+				return
+			}
+			w.pathBackends[filepath.Clean(path)] = k
+		}
+	}
+}
+
+// WithPollInterval sets how often polled folders are re-read. Zero keeps
+// DefaultPollInterval.
+func WithPollInterval(interval time.Duration) Option {
+	return func(w *Watcher) {
+		if interval > 0 {
+			w.pollInterval = interval
+		}
 	}
+}
 
+func NewWatcher(handler Handler, dryRun bool, opts ...Option) (*Watcher, error) {
 	w := &Watcher{
-		fsWatcher: fsWatcher,
-		handler:   handler,
-		dryRun:    dryRun,
-		recursive: true,
+		handler:        handler,
+		dryRun:         dryRun,
+		recursive:      true,
+		defaultBackend: BackendAuto,
+		pathBackends:   make(map[string]BackendKind),
+		pollInterval:   DefaultPollInterval,
+		backends:       make(map[BackendKind]Backend),
+		roots:          make(map[string]BackendKind),
+		events:         make(chan FileEvent),
+		errors:         make(chan error, 8),
+		done:           make(chan struct{}),
 	}
 
 	for _, opt := range opts {
 		opt(w)
 	}
+	if w.optErr != nil {
+		return nil, w.optErr
+	}
+
+	if w.settleWindow > 0 {
+		w.settle = newSettler(w.settleWindow)
+	}
 
 	return w, nil
 }
@@ -77,115 +162,197 @@ func (w *Watcher) ReplaceWatchPaths(paths []string) error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	for _, path := range w.fsWatcher.WatchList() {
-		if err := w.fsWatcher.Remove(path); err != nil {
-			// Non-fatal: the watch may have been auto-removed by the kernel
-			// when the directory was deleted (e.g., post-extraction cleanup
-			// of a SABnzbd _UNPACK_ folder). fsnotify returns EINVAL for
-			// such stale watches; we don't want a stale watch to block the
-			// entire reload pipeline.
-			log.Printf("watcher: ignoring remove error for %s: %v", path, err)
-		}
+	for _, b := range w.backends {
+		b.Reset()
 	}
+	w.roots = make(map[string]BackendKind)
 	return w.watchLocked(paths)
 }
 
+// Backends reports which backend watches each root.
+func (w *Watcher) Backends() map[string]BackendKind {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	out := make(map[string]BackendKind, len(w.roots))
+	for root, kind := range w.roots {
+		out[root] = kind
+	}
+	return out
+}
+
 func (w *Watcher) watchLocked(paths []string) error {
 	for _, path := range paths {
-		if w.recursive {
-			if err := w.addRecursive(path); err != nil {
-				return err
-			}
-		} else {
-			if err := w.fsWatcher.Add(path); err != nil {
-				return fmt.Errorf("unable to watch %s: %w", path, err)
+		kind, fsType := resolveBackend(w.backendKindFor(path), path)
+		b, err := w.backendLocked(kind)
+		if err != nil {
+			return err
+		}
+		if err := b.Watch(path); err != nil {
+			return err
+		}
+		w.roots[filepath.Clean(path)] = kind
+		if kind == BackendPoll {
+			if fsType != "" {
+				log.Printf("Polling: %s every %s (%s mount)", path, w.pollInterval, fsType)
+			} else {
+				log.Printf("Polling: %s every %s", path, w.pollInterval)
 			}
-			log.Printf("Watching: %s", path)
 		}
 	}
 	return nil
 }
 
-func (w *Watcher) addRecursive(root string) error {
-	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+// backendLocked returns the backend of kind, starting it on first use.
+func (w *Watcher) backendLocked(kind BackendKind) (Backend, error) {
+	if b, ok := w.backends[kind]; ok {
+		return b, nil
+	}
+	var b Backend
+	switch kind {
+	case BackendPoll:
+		b = newPollBackend(w.pollInterval, w.recursive, w.filters.SkipDir, w.isMediaFile)
+	case BackendFSNotify:
+		fsb, err := newFSNotifyBackend(w.recursive, w.settle != nil, w.filters.SkipDir)
 		if err != nil {
-			return nil
-		}
-		if !info.IsDir() {
-			return nil
+			return nil, err
 		}
-		if strings.HasPrefix(filepath.Base(path), ".") {
-			return filepath.SkipDir
-		}
-		if err := w.fsWatcher.Add(path); err != nil {
-			return fmt.Errorf("unable to watch %s: %w", path, err)
+		w.fs = fsb
+		b = fsb
+	default:
+		return nil, fmt.Errorf("unknown watch backend %q", kind)
+	}
+	w.backends[kind] = b
+	w.wg.Add(1)
+	go w.forward(b)
+	return b, nil
+}
+
+// forward copies a backend's events and errors into the watcher's loop.
+func (w *Watcher) forward(b Backend) {
+	defer w.wg.Done()
+	events, errs := b.Events(), b.Errors()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			select {
+			case w.events <- event:
+			case <-w.done:
+				return
+			}
+		case err := <-errs:
+			select {
+			case w.errors <- err:
+			default:
+			}
+		case <-w.done:
+			return
 		}
-		log.Printf("Watching: %s", path)
-		return nil
-	})
+	}
 }
 
 func (w *Watcher) Start() error {
 	log.Println("Jellywatch started. Press Ctrl+C to stop.")
 
+	var settleTick <-chan time.Time
+	if w.settle != nil {
+		ticker := time.NewTicker(w.settle.pollInterval())
+		defer ticker.Stop()
+		settleTick = ticker.C
+	}
+
 	for {
 		select {
-		case event, ok := <-w.fsWatcher.Events:
-			if !ok {
-				return fmt.Errorf("watcher events channel closed")
+		case event := <-w.events:
+			if err := w.handleEvent(event); err != nil {
+				log.Printf("Error handling event: %v", err)
 			}
 
-			if event.Op&fsnotify.Create == fsnotify.Create {
-				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
-					if w.recursive && !strings.HasPrefix(filepath.Base(event.Name), ".") {
-						w.fsWatcher.Add(event.Name)
-						log.Printf("Now watching new directory: %s", event.Name)
-					}
+		case err := <-w.errors:
+			log.Printf("Watcher error: %v", err)
+
+		case now := <-settleTick:
+			for _, fileEvent := range w.settle.ready(now) {
+				if !w.accepts(fileEvent, true) {
 					continue
 				}
+				log.Printf("Event: %s - %s (settled)", fileEvent.Type, filepath.Base(fileEvent.Path))
+				if err := w.handler.HandleFileEvent(fileEvent); err != nil {
+					log.Printf("Error handling event: %v", err)
+				}
 			}
 
-			if err := w.handleEvent(event); err != nil {
-				log.Printf("Error handling event: %v", err)
-			}
-
-		case err, ok := <-w.fsWatcher.Errors:
-			if !ok {
-				return fmt.Errorf("watcher errors channel closed")
-			}
-			log.Printf("Watcher error: %v", err)
+		case <-w.done:
+			return fmt.Errorf("watcher events channel closed")
 		}
 	}
 }
 
 func (w *Watcher) Close() error {
-	return w.fsWatcher.Close()
+	var err error
+	w.closing.Do(func() {
+		close(w.done)
+		w.mu.Lock()
+		for _, b := range w.backends {
+			if cerr := b.Close(); cerr != nil && err == nil {
+				err = cerr
+			}
+		}
+		w.mu.Unlock()
+		w.wg.Wait()
+	})
+	return err
 }
 
-func (w *Watcher) handleEvent(event fsnotify.Event) error {
-	if !w.isVideoFile(event.Name) {
+func (w *Watcher) handleEvent(event FileEvent) error {
+	if event.Type == eventCloseWrite {
+		if w.settle != nil {
+			w.settle.closeWrite(event.Path, time.Now())
+		}
 		return nil
 	}
-
-	eventType := EventCreate
-	if event.Op&fsnotify.Write == fsnotify.Write {
-		eventType = EventWrite
-	} else if event.Op&fsnotify.Rename == fsnotify.Rename {
-		eventType = EventMove
-	} else if event.Op&fsnotify.Remove == fsnotify.Remove {
-		eventType = EventDelete
+	if !w.isMediaFile(event.Path) {
+		return nil
 	}
 
-	fileEvent := FileEvent{
-		Type: eventType,
-		Path: event.Name,
+	if w.settle != nil {
+		if event.Type == EventCreate || event.Type == EventWrite {
+			// Sizes are checked once the file has settled.
+			if w.accepts(event, false) {
+				w.settle.track(event.Path, event.Type, time.Now())
+			}
+			return nil
+		}
+		w.settle.forget(event.Path)
+	}
+	if !w.accepts(event, w.settle == nil && (event.Type == EventCreate || event.Type == EventWrite)) {
+		return nil
 	}
 
-	log.Printf("Event: %s - %s", eventType, filepath.Base(event.Name))
+	log.Printf("Event: %s - %s", event.Type, filepath.Base(event.Path))
 
-	return w.handler.HandleFileEvent(fileEvent)
+	return w.handler.HandleFileEvent(event)
+}
+
+// accepts applies the watch-folder filters, including the minimum size
+// when checkSize is set and the file is still there. Rejections are
+// logged once per file: when it settles, or on its create event.
+func (w *Watcher) accepts(event FileEvent, checkSize bool) bool {
+	size := int64(-1)
+	if checkSize {
+		if info, err := os.Stat(event.Path); err == nil {
+			size = info.Size()
+		}
+	}
+	ok, reason := w.filters.Accept(event.Path, size, "")
+	if !ok && checkSize && (w.settle != nil || event.Type == EventCreate) {
+		log.Printf("Skipping: %s (%s)", filepath.Base(event.Path), reason)
+	}
+	return ok
 }
 
-func (w *Watcher) isVideoFile(path string) bool {
-	return video.IsVideo(path)
+func (w *Watcher) isMediaFile(path string) bool {
+	return video.IsVideo(path) || (w.archives && extract.IsArchive(path))
 }
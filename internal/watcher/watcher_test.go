@@ -4,6 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellywatch/internal/ingest"
 )
 
 type noopHandler struct{}
@@ -37,7 +40,7 @@ func TestReplaceWatchPathsRemovesOldWatchesAndAddsNewOnes(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	watches := w.fsWatcher.WatchList()
+	watches := w.fs.watcher.WatchList()
 	if len(watches) != 1 {
 		t.Fatalf("watch count = %d, want 1 (%v)", len(watches), watches)
 	}
@@ -45,3 +48,42 @@ func TestReplaceWatchPathsRemovesOldWatchesAndAddsNewOnes(t *testing.T) {
 		t.Fatalf("watched path = %q, want %q", watches[0], newPath)
 	}
 }
+
+func TestWatcherAppliesFilters(t *testing.T) {
+	dir := t.TempDir()
+	filters, err := ingest.New(ingest.Filter{IgnoreDirs: ingest.DefaultIgnoreDirs, MinMovieSize: 10},
+		[]ingest.Folder{{Path: dir, MediaType: ingest.MediaTypeMovie}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := &recordingHandler{}
+	w, err := NewWatcher(h, false, WithBackend("poll", nil), WithPollInterval(50*time.Millisecond), WithFilters(filters))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if err := w.Watch([]string{dir}); err != nil {
+		t.Fatal(err)
+	}
+	go w.Start()
+
+	keep := filepath.Join(dir, "Film.2020.mkv")
+	writeFile(t, keep, "big enough")
+	writeFile(t, filepath.Join(dir, "Tiny.2020.mkv"), "tiny")
+	if err := os.MkdirAll(filepath.Join(dir, "_UNPACK_Film"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "_UNPACK_Film", "Film.2020.mkv"), "big enough")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(h.snapshot()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	got := h.snapshot()
+	if len(got) != 1 || got[0] != (FileEvent{Type: EventCreate, Path: keep}) {
+		t.Fatalf("events = %v, want one create for %s", got, keep)
+	}
+}
@@ -2,7 +2,6 @@ package wizard
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/Nomadcxx/jellywatch/internal/service"
 )
@@ -41,6 +40,9 @@ func (w *Wizard) handleDuplicates(analysis *service.DuplicateAnalysis) error {
 			}
 			fmt.Printf("  %s: %s %s (%s) - %s\n",
 				marker, f.Resolution, f.SourceType, formatBytes(f.Size), f.Path)
+			for _, part := range f.Parts {
+				fmt.Printf("        + %s\n", part)
+			}
 		}
 
 		fmt.Printf("\n  Space saved: %s\n\n", formatBytes(group.ReclaimableBytes))
@@ -59,7 +61,7 @@ func (w *Wizard) handleDuplicates(analysis *service.DuplicateAnalysis) error {
 					if w.dryRun {
 						fmt.Printf("  Would delete: %s\n", f.Path)
 					} else {
-						if err := os.Remove(f.Path); err != nil {
+						if _, err := w.trash.Remove(f.Path, "duplicate"); err != nil {
 							fmt.Printf("  ❌ Failed to delete %s: %v\n", f.Path, err)
 							continue
 						}
@@ -81,7 +83,7 @@ func (w *Wizard) handleDuplicates(analysis *service.DuplicateAnalysis) error {
 					if w.dryRun {
 						fmt.Printf("  Would delete: %s\n", f.Path)
 					} else {
-						if err := os.Remove(f.Path); err != nil {
+						if _, err := w.trash.Remove(f.Path, "duplicate"); err != nil {
 							fmt.Printf("  ❌ Failed to delete %s: %v\n", f.Path, err)
 							continue
 						}
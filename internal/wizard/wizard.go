@@ -7,7 +7,9 @@ import (
 	"strings"
 
 	"github.com/Nomadcxx/jellywatch/internal/database"
+	"github.com/Nomadcxx/jellywatch/internal/quality"
 	"github.com/Nomadcxx/jellywatch/internal/service"
+	"github.com/Nomadcxx/jellywatch/internal/trash"
 )
 
 // Wizard provides an interactive cleanup experience
@@ -17,6 +19,7 @@ type Wizard struct {
 	reader  *bufio.Reader
 	dryRun  bool
 	autoYes bool
+	trash   *trash.Bin
 }
 
 // Options configures wizard behavior
@@ -25,16 +28,22 @@ type Options struct {
 	AutoYes         bool
 	DuplicatesOnly  bool
 	ConsolidateOnly bool
+	QualityProfiles *quality.ProfileSet // ranks duplicate copies; nil uses stored scores
+	Trash           *trash.Bin          // keeps deleted duplicates; nil deletes for good
 }
 
 // New creates a new wizard
 func New(db *database.MediaDB, opts Options) *Wizard {
+	svc := service.NewCleanupService(db)
+	svc.SetQualityProfiles(opts.QualityProfiles)
+	svc.SetTrash(opts.Trash)
 	return &Wizard{
 		db:      db,
-		service: service.NewCleanupService(db),
+		service: svc,
 		reader:  bufio.NewReader(os.Stdin),
 		dryRun:  opts.DryRun,
 		autoYes: opts.AutoYes,
+		trash:   opts.Trash,
 	}
 }
 
@@ -0,0 +1,185 @@
+'use client';
+
+import { useState } from 'react';
+import { AppShell } from '@/components/layout/AppShell';
+import { Button } from '@/components/ui/button';
+import { Input } from '@/components/ui/input';
+import {
+  AliasInput,
+  AliasKind,
+  AliasMediaType,
+  useAliases,
+  useDeleteAlias,
+  useSaveAlias,
+} from '@/hooks/useAliases';
+import { AlertTriangle, Tags, Trash2 } from 'lucide-react';
+
+const emptyForm = {
+  kind: 'title' as AliasKind,
+  media_type: 'series' as AliasMediaType,
+  pattern: '',
+  title: '',
+  year: '',
+  tmdb_id: '',
+  tvdb_id: '',
+  imdb_id: '',
+};
+
+const kindHelp: Record<AliasKind, string> = {
+  title: 'Release title as the parser reads it, e.g. The Office',
+  regex: 'Regular expression searched in the file path, e.g. (?i)office\\.uk\\.',
+  folder: 'Name of a folder the file sits in, e.g. The Office (2005)',
+};
+
+function toNumber(value: string): number | undefined {
+  const n = parseInt(value, 10);
+  return Number.isFinite(n) && n > 0 ? n : undefined;
+}
+
+export default function AliasesPage() {
+  const { data, isLoading, error } = useAliases();
+  const save = useSaveAlias();
+  const remove = useDeleteAlias();
+  const [form, setForm] = useState(emptyForm);
+
+  const set = (key: keyof typeof emptyForm) => (e: { target: { value: string } }) =>
+    setForm({ ...form, [key]: e.target.value });
+
+  const submit = () => {
+    const alias: AliasInput = {
+      kind: form.kind,
+      media_type: form.media_type,
+      pattern: form.pattern.trim(),
+      title: form.title.trim(),
+      year: toNumber(form.year),
+      tmdb_id: toNumber(form.tmdb_id),
+      tvdb_id: toNumber(form.tvdb_id),
+      imdb_id: form.imdb_id.trim() || undefined,
+    };
+    save.mutate(alias, { onSuccess: () => setForm(emptyForm) });
+  };
+
+  if (isLoading) {
+    return (
+      <AppShell>
+        <div className="space-y-4">
+          <h1 className="text-3xl font-bold">Aliases</h1>
+          <div className="h-32 bg-zinc-900 rounded-lg animate-pulse" />
+        </div>
+      </AppShell>
+    );
+  }
+
+  if (error) {
+    return (
+      <AppShell>
+        <div className="p-4 bg-red-500/10 border border-red-500/30 rounded-lg">
+          <p className="text-red-400">Failed to load aliases</p>
+        </div>
+      </AppShell>
+    );
+  }
+
+  const aliases = data || [];
+  const mutationError = (save.error || remove.error) as Error | null;
+
+  return (
+    <AppShell>
+      <div className="space-y-5">
+        <div>
+          <h1 className="text-3xl font-bold flex items-center gap-2">
+            <Tags className="h-8 w-8" />
+            Aliases
+          </h1>
+          <p className="text-zinc-400 mt-1">
+            Map a release title, path pattern or folder to the title, year and IDs it should be organized as.
+          </p>
+        </div>
+
+        <div className="space-y-3 rounded-lg border border-zinc-800 bg-zinc-900 p-4">
+          <div className="flex flex-wrap gap-2">
+            <select
+              value={form.media_type}
+              onChange={set('media_type')}
+              className="bg-zinc-900 border border-zinc-700 rounded px-2 py-1 text-sm"
+            >
+              <option value="series">Series</option>
+              <option value="movie">Movie</option>
+            </select>
+            <select
+              value={form.kind}
+              onChange={set('kind')}
+              className="bg-zinc-900 border border-zinc-700 rounded px-2 py-1 text-sm"
+            >
+              <option value="title">Title</option>
+              <option value="regex">Regex</option>
+              <option value="folder">Folder</option>
+            </select>
+            <Input
+              className="flex-1 min-w-64"
+              placeholder={kindHelp[form.kind]}
+              value={form.pattern}
+              onChange={set('pattern')}
+            />
+          </div>
+          <div className="flex flex-wrap gap-2">
+            <Input className="flex-1 min-w-48" placeholder="Canonical title" value={form.title} onChange={set('title')} />
+            <Input className="w-24" placeholder="Year" value={form.year} onChange={set('year')} />
+            <Input className="w-28" placeholder="TMDB ID" value={form.tmdb_id} onChange={set('tmdb_id')} />
+            <Input className="w-28" placeholder="TVDB ID" value={form.tvdb_id} onChange={set('tvdb_id')} />
+            <Input className="w-32" placeholder="IMDb ID" value={form.imdb_id} onChange={set('imdb_id')} />
+            <Button disabled={!form.pattern.trim() || !form.title.trim() || save.isPending} onClick={submit}>
+              Save alias
+            </Button>
+          </div>
+        </div>
+
+        {mutationError && (
+          <div className="p-4 bg-red-500/10 border border-red-500/30 rounded-lg text-sm text-red-400">
+            {mutationError.message}
+          </div>
+        )}
+
+        {aliases.length === 0 ? (
+          <div className="p-8 bg-zinc-900 rounded-lg border border-zinc-800 text-center">
+            <AlertTriangle className="h-12 w-12 text-zinc-600 mx-auto mb-4" />
+            <p className="text-zinc-400">No aliases yet</p>
+          </div>
+        ) : (
+          <div className="space-y-2">
+            {aliases.map((alias) => (
+              <div
+                key={alias.id}
+                className="flex items-start justify-between gap-4 rounded-lg border border-zinc-800 bg-zinc-900 p-4"
+              >
+                <div className="min-w-0 space-y-1">
+                  <p className="truncate font-medium" title={alias.pattern}>
+                    <span className="font-mono">{alias.pattern}</span>
+                    <span className="text-zinc-500"> → </span>
+                    {alias.title}
+                    {alias.year && <> ({alias.year})</>}
+                  </p>
+                  <p className="text-xs text-zinc-500">
+                    {alias.media_type} · {alias.kind}
+                    {alias.tmdb_id && <> · tmdb {alias.tmdb_id}</>}
+                    {alias.tvdb_id && <> · tvdb {alias.tvdb_id}</>}
+                    {alias.imdb_id && <> · {alias.imdb_id}</>}
+                  </p>
+                </div>
+                <Button
+                  variant="outline"
+                  size="sm"
+                  disabled={remove.isPending}
+                  onClick={() => remove.mutate(alias.id)}
+                >
+                  <Trash2 className="mr-2 h-4 w-4" />
+                  Remove
+                </Button>
+              </div>
+            ))}
+          </div>
+        )}
+      </div>
+    </AppShell>
+  );
+}
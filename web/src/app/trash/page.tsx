@@ -0,0 +1,122 @@
+'use client';
+
+import { useState } from 'react';
+import { AppShell } from '@/components/layout/AppShell';
+import { AlertDialog } from '@/components/ui/alert-dialog';
+import { Button } from '@/components/ui/button';
+import { useEmptyTrash, useRestoreTrashItem, useTrash } from '@/hooks/useTrash';
+import { formatBytes } from '@/lib/utils';
+import { AlertTriangle, RotateCcw, Trash2 } from 'lucide-react';
+
+export default function TrashPage() {
+  const { data, isLoading, error } = useTrash();
+  const restore = useRestoreTrashItem();
+  const empty = useEmptyTrash();
+  const [confirmOpen, setConfirmOpen] = useState(false);
+
+  if (isLoading) {
+    return (
+      <AppShell>
+        <div className="space-y-4">
+          <h1 className="text-3xl font-bold">Trash</h1>
+          <div className="h-32 bg-zinc-900 rounded-lg animate-pulse" />
+        </div>
+      </AppShell>
+    );
+  }
+
+  if (error) {
+    return (
+      <AppShell>
+        <div className="p-4 bg-red-500/10 border border-red-500/30 rounded-lg">
+          <p className="text-red-400">Failed to load the trash</p>
+        </div>
+      </AppShell>
+    );
+  }
+
+  const items = data?.items || [];
+  const mutationError = (restore.error || empty.error) as Error | null;
+
+  return (
+    <AppShell>
+      <div className="space-y-5">
+        <div className="flex items-end justify-between gap-4">
+          <div>
+            <h1 className="text-3xl font-bold flex items-center gap-2">
+              <Trash2 className="h-8 w-8" />
+              Trash
+            </h1>
+            <p className="text-zinc-400 mt-1">
+              {items.length} items, {formatBytes(data?.bytes || 0)}
+            </p>
+          </div>
+          <Button
+            variant="destructive"
+            disabled={items.length === 0 || empty.isPending}
+            onClick={() => setConfirmOpen(true)}
+          >
+            Empty trash
+          </Button>
+        </div>
+
+        {data && !data.enabled && (
+          <div className="p-4 bg-yellow-500/10 border border-yellow-500/30 rounded-lg text-sm text-yellow-300">
+            The recycle bin is disabled, so deletions are permanent. Enable it under [trash] in the config.
+          </div>
+        )}
+
+        {mutationError && (
+          <div className="p-4 bg-red-500/10 border border-red-500/30 rounded-lg text-sm text-red-400">
+            {mutationError.message}
+          </div>
+        )}
+
+        {items.length === 0 ? (
+          <div className="p-8 bg-zinc-900 rounded-lg border border-zinc-800 text-center">
+            <AlertTriangle className="h-12 w-12 text-zinc-600 mx-auto mb-4" />
+            <p className="text-zinc-400">The trash is empty</p>
+          </div>
+        ) : (
+          <div className="space-y-2">
+            {items.map((item) => (
+              <div
+                key={item.id}
+                className="flex items-start justify-between gap-4 rounded-lg border border-zinc-800 bg-zinc-900 p-4"
+              >
+                <div className="min-w-0 space-y-1">
+                  <p className="truncate font-medium" title={item.original_path}>
+                    {item.original_path}
+                  </p>
+                  <p className="text-xs text-zinc-500">
+                    {new Date(item.deleted_at).toLocaleString()} · {formatBytes(item.size)}
+                    {item.reason && <> · {item.reason}</>}
+                  </p>
+                </div>
+                <Button
+                  variant="outline"
+                  size="sm"
+                  disabled={restore.isPending}
+                  onClick={() => restore.mutate(item.id)}
+                >
+                  <RotateCcw className="mr-2 h-4 w-4" />
+                  Restore
+                </Button>
+              </div>
+            ))}
+          </div>
+        )}
+      </div>
+
+      <AlertDialog
+        open={confirmOpen}
+        onOpenChange={setConfirmOpen}
+        title="Empty the trash?"
+        description={`${items.length} items will be deleted for good.`}
+        confirmLabel="Empty"
+        destructive
+        onConfirm={() => empty.mutate()}
+      />
+    </AppShell>
+  );
+}
@@ -3,7 +3,7 @@
 import Link from 'next/link';
 import Image from 'next/image';
 import { usePathname } from 'next/navigation';
-import { LayoutDashboard, Copy, Download, Activity, FolderSync, Settings, Calendar } from 'lucide-react';
+import { LayoutDashboard, Copy, Download, Activity, FolderSync, Settings, Calendar, Trash2, Tags } from 'lucide-react';
 
 const navigation = [
   { name: 'Dashboard', href: '/', icon: LayoutDashboard },
@@ -12,6 +12,8 @@ const navigation = [
   { name: 'Activity', href: '/activity', icon: Activity },
   { name: 'Consolidation', href: '/consolidation', icon: FolderSync },
   { name: 'Scheduler', href: '/scheduler', icon: Calendar },
+  { name: 'Aliases', href: '/aliases', icon: Tags },
+  { name: 'Trash', href: '/trash', icon: Trash2 },
   { name: 'Settings', href: '/settings', icon: Settings },
 ];
 
@@ -0,0 +1,53 @@
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import { api } from '@/lib/api/client';
+
+export type AliasKind = 'title' | 'regex' | 'folder';
+export type AliasMediaType = 'movie' | 'series';
+
+export type Alias = {
+  id: number;
+  kind: AliasKind;
+  pattern: string;
+  media_type: AliasMediaType;
+  title: string;
+  year?: number;
+  tmdb_id?: number;
+  tvdb_id?: number;
+  imdb_id?: string;
+  created_at: string;
+};
+
+export type AliasInput = Omit<Alias, 'id' | 'created_at'>;
+
+export const aliasKeys = {
+  all: ['aliases'] as const,
+};
+
+export function useAliases() {
+  return useQuery<Alias[]>({
+    queryKey: aliasKeys.all,
+    queryFn: () => api.get('/aliases'),
+  });
+}
+
+export function useSaveAlias() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: (alias: AliasInput) => api.post<Alias>('/aliases', alias),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: aliasKeys.all });
+    },
+  });
+}
+
+export function useDeleteAlias() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: (id: number) => api.delete<void>(`/aliases/${id}`),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: aliasKeys.all });
+    },
+  });
+}
@@ -0,0 +1,51 @@
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import { api } from '@/lib/api/client';
+
+export type TrashItem = {
+  id: number;
+  original_path: string;
+  trash_path: string;
+  trash_dir: string;
+  reason: string;
+  size: number;
+  deleted_at: string;
+};
+
+type TrashListing = {
+  enabled: boolean;
+  items: TrashItem[];
+  bytes: number;
+};
+
+export const trashKeys = {
+  all: ['trash'] as const,
+};
+
+export function useTrash() {
+  return useQuery<TrashListing>({
+    queryKey: trashKeys.all,
+    queryFn: () => api.get('/trash'),
+  });
+}
+
+export function useRestoreTrashItem() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: (id: number) => api.post<TrashItem>(`/trash/${id}/restore`),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: trashKeys.all });
+    },
+  });
+}
+
+export function useEmptyTrash() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: () => api.delete<{ items: number; bytes: number }>('/trash'),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: trashKeys.all });
+    },
+  });
+}